@@ -27,18 +27,24 @@ import (
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/externalLink"
+	"github.com/devtron-labs/devtron/api/gitopsDrift"
+	"github.com/devtron-labs/devtron/api/gitopsRepo"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSignature"
 	"github.com/devtron-labs/devtron/api/module"
 	"github.com/devtron-labs/devtron/api/restHandler"
 	pipeline2 "github.com/devtron-labs/devtron/api/restHandler/app"
 	"github.com/devtron-labs/devtron/api/router"
 	"github.com/devtron-labs/devtron/api/router/pubsub"
+	"github.com/devtron-labs/devtron/api/scim"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sse"
 	"github.com/devtron-labs/devtron/api/sso"
@@ -129,6 +135,12 @@ func InitializeApp() (*App, error) {
 		server.ServerWireSet,
 		module.ModuleWireSet,
 		apiToken.ApiTokenWireSet,
+		scim.ScimWireSet,
+		auditLog.AuditLogWireSet,
+		deploymentWindow.DeploymentWindowWireSet,
+		gitopsDrift.GitOpsDriftWireSet,
+		gitopsRepo.GitOpsRepoWireSet,
+		imageSignature.ImageSignatureWireSet,
 		webhookHelm.WebhookHelmWireSet,
 		// -------wireset end ----------
 		gitSensor.GetGitSensorConfig,
@@ -353,6 +365,22 @@ func InitializeApp() (*App, error) {
 		repository.NewSlackNotificationRepositoryImpl,
 		wire.Bind(new(repository.SlackNotificationRepository), new(*repository.SlackNotificationRepositoryImpl)),
 
+		notifier.NewTeamsNotificationServiceImpl,
+		repository.NewTeamsNotificationRepositoryImpl,
+		wire.Bind(new(repository.TeamsNotificationRepository), new(*repository.TeamsNotificationRepositoryImpl)),
+		notifier.NewWebhookNotificationServiceImpl,
+		repository.NewWebhookNotificationRepositoryImpl,
+		wire.Bind(new(repository.WebhookNotificationRepository), new(*repository.WebhookNotificationRepositoryImpl)),
+		notifier.NewPagerDutyNotificationServiceImpl,
+		repository.NewPagerDutyNotificationRepositoryImpl,
+		wire.Bind(new(repository.PagerDutyNotificationRepository), new(*repository.PagerDutyNotificationRepositoryImpl)),
+		notifier.NewNotificationChannelRegistryImpl,
+		wire.Bind(new(notifier.NotificationChannelRegistry), new(*notifier.NotificationChannelRegistryImpl)),
+		notifier.NewNotificationTemplateServiceImpl,
+		wire.Bind(new(notifier.NotificationTemplateService), new(*notifier.NotificationTemplateServiceImpl)),
+		repository.NewNotificationTemplatesRepositoryImpl,
+		wire.Bind(new(repository.NotificationTemplatesRepository), new(*repository.NotificationTemplatesRepositoryImpl)),
+
 		notifier.NewNotificationConfigServiceImpl,
 		wire.Bind(new(notifier.NotificationConfigService), new(*notifier.NotificationConfigServiceImpl)),
 		app.NewAppListingViewBuilderImpl,
@@ -480,6 +508,10 @@ func InitializeApp() (*App, error) {
 
 		app.NewReleaseDataServiceImpl,
 		wire.Bind(new(app.ReleaseDataService), new(*app.ReleaseDataServiceImpl)),
+		app.NewDoraMetricsServiceImpl,
+		wire.Bind(new(app.DoraMetricsService), new(*app.DoraMetricsServiceImpl)),
+		app.NewPipelineStatusTimelineServiceImpl,
+		wire.Bind(new(app.PipelineStatusTimelineService), new(*app.PipelineStatusTimelineServiceImpl)),
 		restHandler.NewReleaseMetricsRestHandlerImpl,
 		wire.Bind(new(restHandler.ReleaseMetricsRestHandler), new(*restHandler.ReleaseMetricsRestHandlerImpl)),
 		router.NewReleaseMetricsRouterImpl,
@@ -572,6 +604,15 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(security2.CveStoreRepository), new(*security2.CveStoreRepositoryImpl)),
 		security2.NewImageScanDeployInfoRepositoryImpl,
 		wire.Bind(new(security2.ImageScanDeployInfoRepository), new(*security2.ImageScanDeployInfoRepositoryImpl)),
+		security2.NewImageScanNewCveRepositoryImpl,
+		wire.Bind(new(security2.ImageScanNewCveRepository), new(*security2.ImageScanNewCveRepositoryImpl)),
+		security.NewImageRescanServiceImpl,
+		wire.Bind(new(security.ImageRescanService), new(*security.ImageRescanServiceImpl)),
+		security.GetSbomConfig,
+		security.NewSbomServiceImpl,
+		wire.Bind(new(security.SbomService), new(*security.SbomServiceImpl)),
+		security2.NewCiArtifactSbomRepositoryImpl,
+		wire.Bind(new(security2.CiArtifactSbomRepository), new(*security2.CiArtifactSbomRepositoryImpl)),
 		router.NewPolicyRouterImpl,
 		wire.Bind(new(router.PolicyRouter), new(*router.PolicyRouterImpl)),
 		restHandler.NewPolicyRestHandlerImpl,
@@ -734,6 +775,68 @@ func InitializeApp() (*App, error) {
 
 		pipelineConfig.NewPipelineStatusTimelineRepositoryImpl,
 		wire.Bind(new(pipelineConfig.PipelineStatusTimelineRepository), new(*pipelineConfig.PipelineStatusTimelineRepositoryImpl)),
+		pipelineConfig.NewPipelineStatusTimelineResourcesRepositoryImpl,
+		wire.Bind(new(pipelineConfig.PipelineStatusTimelineResourcesRepository), new(*pipelineConfig.PipelineStatusTimelineResourcesRepositoryImpl)),
+
+		pipelineConfig.NewCanaryAnalysisConfigRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CanaryAnalysisConfigRepository), new(*pipelineConfig.CanaryAnalysisConfigRepositoryImpl)),
+		pipelineConfig.NewCanaryAnalysisRunRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CanaryAnalysisRunRepository), new(*pipelineConfig.CanaryAnalysisRunRepositoryImpl)),
+		pipeline.NewCanaryAnalysisServiceImpl,
+		wire.Bind(new(pipeline.CanaryAnalysisService), new(*pipeline.CanaryAnalysisServiceImpl)),
+		restHandler.NewCanaryAnalysisRestHandlerImpl,
+		wire.Bind(new(restHandler.CanaryAnalysisRestHandler), new(*restHandler.CanaryAnalysisRestHandlerImpl)),
+		router.NewCanaryAnalysisRouterImpl,
+		wire.Bind(new(router.CanaryAnalysisRouter), new(*router.CanaryAnalysisRouterImpl)),
+		cron.NewCanaryAnalysisHandlerImpl,
+		wire.Bind(new(cron.CanaryAnalysisHandler), new(*cron.CanaryAnalysisHandlerImpl)),
+		cron.NewCveExceptionExpiryHandlerImpl,
+		wire.Bind(new(cron.CveExceptionExpiryHandler), new(*cron.CveExceptionExpiryHandlerImpl)),
+		cron.NewDeployedImageRescanHandlerImpl,
+		wire.Bind(new(cron.DeployedImageRescanHandler), new(*cron.DeployedImageRescanHandlerImpl)),
+		cron.NewRoleGrantExpiryHandlerImpl,
+		wire.Bind(new(cron.RoleGrantExpiryHandler), new(*cron.RoleGrantExpiryHandlerImpl)),
+		cron.NewAuditEventRetentionHandlerImpl,
+		wire.Bind(new(cron.AuditEventRetentionHandler), new(*cron.AuditEventRetentionHandlerImpl)),
+		cron.NewSiemExportHandlerImpl,
+		wire.Bind(new(cron.SiemExportHandler), new(*cron.SiemExportHandlerImpl)),
+		app.NewGitOpsPullRequestServiceImpl,
+		wire.Bind(new(app.GitOpsPullRequestService), new(*app.GitOpsPullRequestServiceImpl)),
+		cron.NewGitOpsPullRequestHandlerImpl,
+		wire.Bind(new(cron.GitOpsPullRequestHandler), new(*cron.GitOpsPullRequestHandlerImpl)),
+		cron.NewGitOpsDriftHandlerImpl,
+		wire.Bind(new(cron.GitOpsDriftHandler), new(*cron.GitOpsDriftHandlerImpl)),
+		cron.NewOCIChartSyncHandlerImpl,
+		wire.Bind(new(cron.OCIChartSyncHandler), new(*cron.OCIChartSyncHandlerImpl)),
+		cron.NewCdPromotionHandlerImpl,
+		wire.Bind(new(cron.CdPromotionHandler), new(*cron.CdPromotionHandlerImpl)),
+
+		pipelineConfig.NewCdApprovalRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CdApprovalRepository), new(*pipelineConfig.CdApprovalRepositoryImpl)),
+		pipeline.NewCdApprovalServiceImpl,
+		wire.Bind(new(pipeline.CdApprovalService), new(*pipeline.CdApprovalServiceImpl)),
+		restHandler.NewCdApprovalRestHandlerImpl,
+		wire.Bind(new(restHandler.CdApprovalRestHandler), new(*restHandler.CdApprovalRestHandlerImpl)),
+		router.NewCdApprovalRouterImpl,
+		wire.Bind(new(router.CdApprovalRouter), new(*router.CdApprovalRouterImpl)),
+
+		pipelineConfig.NewCiPipelineScheduleRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CiPipelineScheduleRepository), new(*pipelineConfig.CiPipelineScheduleRepositoryImpl)),
+		pipeline.NewCiPipelineScheduleServiceImpl,
+		wire.Bind(new(pipeline.CiPipelineScheduleService), new(*pipeline.CiPipelineScheduleServiceImpl)),
+		restHandler.NewCiPipelineScheduleRestHandlerImpl,
+		wire.Bind(new(restHandler.CiPipelineScheduleRestHandler), new(*restHandler.CiPipelineScheduleRestHandlerImpl)),
+		router.NewCiPipelineScheduleRouterImpl,
+		wire.Bind(new(router.CiPipelineScheduleRouter), new(*router.CiPipelineScheduleRouterImpl)),
+
+		pipelineConfig.NewCdPromotionPolicyRepositoryImpl,
+		wire.Bind(new(pipelineConfig.CdPromotionPolicyRepository), new(*pipelineConfig.CdPromotionPolicyRepositoryImpl)),
+		pipeline.NewCdPromotionPolicyServiceImpl,
+		wire.Bind(new(pipeline.CdPromotionPolicyService), new(*pipeline.CdPromotionPolicyServiceImpl)),
+		restHandler.NewCdPromotionPolicyRestHandlerImpl,
+		wire.Bind(new(restHandler.CdPromotionPolicyRestHandler), new(*restHandler.CdPromotionPolicyRestHandlerImpl)),
+		router.NewCdPromotionPolicyRouterImpl,
+		wire.Bind(new(router.CdPromotionPolicyRouter), new(*router.CdPromotionPolicyRouterImpl)),
 	)
 	return &App{}, nil
 }
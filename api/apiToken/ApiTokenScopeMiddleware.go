@@ -0,0 +1,38 @@
+package apiToken
+
+import (
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ApiTokenScopeMiddleware interface {
+	// Handle rejects the requests made by api-tokens which are out of their scope, it runs on matched routes
+	// before the handlers and so before casbin enforcement
+	Handle(next http.Handler) http.Handler
+}
+
+type ApiTokenScopeMiddlewareImpl struct {
+	logger          *zap.SugaredLogger
+	apiTokenService apiToken.ApiTokenService
+}
+
+func NewApiTokenScopeMiddlewareImpl(logger *zap.SugaredLogger, apiTokenService apiToken.ApiTokenService) *ApiTokenScopeMiddlewareImpl {
+	return &ApiTokenScopeMiddlewareImpl{
+		logger:          logger,
+		apiTokenService: apiTokenService,
+	}
+}
+
+func (impl ApiTokenScopeMiddlewareImpl) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := impl.apiTokenService.VerifyApiTokenScope(r)
+		if err != nil {
+			impl.logger.Errorw("api-token scope verification failed", "err", err, "method", r.Method, "path", r.URL.Path)
+			common.WriteJsonResp(w, err, nil, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -12,6 +12,8 @@ var ApiTokenWireSet = wire.NewSet(
 	wire.Bind(new(apiToken.ApiTokenService), new(*apiToken.ApiTokenServiceImpl)),
 	NewApiTokenRestHandlerImpl,
 	wire.Bind(new(ApiTokenRestHandler), new(*ApiTokenRestHandlerImpl)),
+	NewApiTokenScopeMiddlewareImpl,
+	wire.Bind(new(ApiTokenScopeMiddleware), new(*ApiTokenScopeMiddlewareImpl)),
 	NewApiTokenRouterImpl,
 	wire.Bind(new(ApiTokenRouter), new(*ApiTokenRouterImpl)),
 )
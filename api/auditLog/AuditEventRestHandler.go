@@ -0,0 +1,141 @@
+package auditLog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"go.uber.org/zap"
+)
+
+type AuditEventRestHandler interface {
+	GetAuditEvents(w http.ResponseWriter, r *http.Request)
+	ExportAuditEvents(w http.ResponseWriter, r *http.Request)
+}
+
+type AuditEventRestHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	auditEventService auditLog.AuditEventService
+	userService       user.UserService
+}
+
+func NewAuditEventRestHandlerImpl(logger *zap.SugaredLogger, auditEventService auditLog.AuditEventService,
+	userService user.UserService) *AuditEventRestHandlerImpl {
+	return &AuditEventRestHandlerImpl{
+		logger:            logger,
+		auditEventService: auditEventService,
+		userService:       userService,
+	}
+}
+
+func (impl AuditEventRestHandlerImpl) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	if ok := impl.authorize(w, r); !ok {
+		return
+	}
+	filter, err := getAuditEventFilter(r)
+	if err != nil {
+		impl.logger.Errorw("request err, GetAuditEvents", "err", err, "query", r.URL.RawQuery)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.auditEventService.GetAuditEvents(filter)
+	if err != nil {
+		impl.logger.Errorw("service err, GetAuditEvents", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl AuditEventRestHandlerImpl) ExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	if ok := impl.authorize(w, r); !ok {
+		return
+	}
+	filter, err := getAuditEventFilter(r)
+	if err != nil {
+		impl.logger.Errorw("request err, ExportAuditEvents", "err", err, "query", r.URL.RawQuery)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=audit-events-%s.jsonl", time.Now().Format("20060102150405")))
+	w.WriteHeader(http.StatusOK)
+	// status is already written, a failure is only logged and truncates the export
+	err = impl.auditEventService.ExportAuditEvents(filter, w)
+	if err != nil {
+		impl.logger.Errorw("service err, ExportAuditEvents", "err", err, "filter", filter)
+	}
+}
+
+// authorize allows super-admins only, audit events have the changes made across all the apps and clusters
+func (impl AuditEventRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request) bool {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	isSuperAdmin, err := impl.userService.IsSuperAdmin(int(userId))
+	if err != nil {
+		impl.logger.Errorw("service err, IsSuperAdmin", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func getAuditEventFilter(r *http.Request) (*auditLog.AuditEventFilter, error) {
+	query := r.URL.Query()
+	filter := &auditLog.AuditEventFilter{
+		ActorEmail:   query.Get("actorEmail"),
+		ApiTokenName: query.Get("apiTokenName"),
+		ResourceType: query.Get("resourceType"),
+		Action:       query.Get("action"),
+		RequestId:    query.Get("requestId"),
+	}
+	ints := map[string]*int{
+		"resourceId": &filter.ResourceId,
+		"appId":      &filter.AppId,
+		"envId":      &filter.EnvId,
+		"offset":     &filter.Offset,
+		"size":       &filter.Size,
+	}
+	for key, value := range ints {
+		if param := query.Get(key); len(param) > 0 {
+			parsed, err := strconv.Atoi(param)
+			if err != nil || parsed < 0 {
+				return nil, fmt.Errorf("invalid %s '%s'", key, param)
+			}
+			*value = parsed
+		}
+	}
+	if param := query.Get("actorId"); len(param) > 0 {
+		actorId, err := strconv.Atoi(param)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actorId '%s'", param)
+		}
+		filter.ActorId = int32(actorId)
+	}
+	times := map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+	for key, value := range times {
+		if param := query.Get(key); len(param) > 0 {
+			parsed, err := time.Parse(time.RFC3339, param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s '%s', expected RFC3339 time", key, param)
+			}
+			*value = parsed
+		}
+	}
+	return filter, nil
+}
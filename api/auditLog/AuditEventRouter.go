@@ -0,0 +1,22 @@
+package auditLog
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type AuditEventRouter interface {
+	InitAuditEventRouter(auditEventRouter *mux.Router)
+}
+
+type AuditEventRouterImpl struct {
+	auditEventRestHandler AuditEventRestHandler
+}
+
+func NewAuditEventRouterImpl(auditEventRestHandler AuditEventRestHandler) *AuditEventRouterImpl {
+	return &AuditEventRouterImpl{auditEventRestHandler: auditEventRestHandler}
+}
+
+func (impl AuditEventRouterImpl) InitAuditEventRouter(auditEventRouter *mux.Router) {
+	auditEventRouter.Path("/events").HandlerFunc(impl.auditEventRestHandler.GetAuditEvents).Methods("GET")
+	auditEventRouter.Path("/events/export").HandlerFunc(impl.auditEventRestHandler.ExportAuditEvents).Methods("GET")
+}
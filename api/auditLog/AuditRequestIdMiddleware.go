@@ -0,0 +1,32 @@
+package auditLog
+
+import (
+	"net/http"
+
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	uuid "github.com/satori/go.uuid"
+)
+
+type AuditRequestIdMiddleware interface {
+	// Handle assigns a request id to the requests which do not carry one, so that the audit events of a request can be
+	// correlated, the id is returned in response header
+	Handle(next http.Handler) http.Handler
+}
+
+type AuditRequestIdMiddlewareImpl struct{}
+
+func NewAuditRequestIdMiddlewareImpl() *AuditRequestIdMiddlewareImpl {
+	return &AuditRequestIdMiddlewareImpl{}
+}
+
+func (impl AuditRequestIdMiddlewareImpl) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(auditLog.REQUEST_ID_HEADER)
+		if len(requestId) == 0 {
+			requestId = uuid.NewV4().String()
+			r.Header.Set(auditLog.REQUEST_ID_HEADER, requestId)
+		}
+		w.Header().Set(auditLog.REQUEST_ID_HEADER, requestId)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,22 @@
+package auditLog
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/devtron-labs/devtron/pkg/auditLog/siem"
+	"github.com/google/wire"
+)
+
+var AuditLogWireSet = wire.NewSet(
+	siem.NewSiemExportServiceImpl,
+	wire.Bind(new(siem.SiemExportService), new(*siem.SiemExportServiceImpl)),
+	auditLog.NewAuditEventRepositoryImpl,
+	wire.Bind(new(auditLog.AuditEventRepository), new(*auditLog.AuditEventRepositoryImpl)),
+	auditLog.NewAuditEventServiceImpl,
+	wire.Bind(new(auditLog.AuditEventService), new(*auditLog.AuditEventServiceImpl)),
+	NewAuditEventRestHandlerImpl,
+	wire.Bind(new(AuditEventRestHandler), new(*AuditEventRestHandlerImpl)),
+	NewAuditEventRouterImpl,
+	wire.Bind(new(AuditEventRouter), new(*AuditEventRouterImpl)),
+	NewAuditRequestIdMiddlewareImpl,
+	wire.Bind(new(AuditRequestIdMiddleware), new(*AuditRequestIdMiddlewareImpl)),
+)
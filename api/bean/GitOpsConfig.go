@@ -1,5 +1,7 @@
 package bean
 
+import "time"
+
 type GitOpsConfigDto struct {
 	Id                   int    `json:"id,omitempty"`
 	Provider             string `json:"provider"`
@@ -12,5 +14,66 @@ type GitOpsConfigDto struct {
 	AzureProjectName     string `json:"azureProjectName"`
 	BitBucketWorkspaceId string `json:"bitBucketWorkspaceId"`
 	BitBucketProjectKey  string `json:"bitBucketProjectKey"`
+	GiteaOrgId           string `json:"giteaOrgId"`
+	SshPrivateKey        string `json:"sshPrivateKey,omitempty"`
+	SshKnownHosts        string `json:"sshKnownHosts,omitempty"`
 	UserId               int32  `json:"-"`
 }
+
+// GitOpsDriftReport is the drift of a cd pipeline deployed through gitops from the state deployed by devtron, values
+// in gitops repo are compared with the values committed by latest deployment and live state is compared by argocd
+// with gitops repo
+type GitOpsDriftReport struct {
+	PipelineId                  int                    `json:"pipelineId"`
+	AppId                       int                    `json:"appId"`
+	AppName                     string                 `json:"appName"`
+	EnvId                       int                    `json:"envId"`
+	EnvName                     string                 `json:"envName"`
+	TeamId                      int                    `json:"-"`
+	DeploymentTemplateHistoryId int                    `json:"deploymentTemplateHistoryId"`
+	PipelineOverrideId          int                    `json:"pipelineOverrideId"`
+	Drifted                     bool                   `json:"drifted"`
+	GitDrift                    bool                   `json:"gitDrift"`
+	GitDriftPaths               []string               `json:"gitDriftPaths,omitempty"`
+	ArgoSyncStatus              string                 `json:"argoSyncStatus"`
+	LiveDrift                   bool                   `json:"liveDrift"`
+	DriftedResources            []*GitOpsDriftResource `json:"driftedResources,omitempty"`
+	Error                       string                 `json:"error,omitempty"`
+	DriftDetectedOn             *time.Time             `json:"driftDetectedOn,omitempty"`
+	CheckedOn                   time.Time              `json:"checkedOn"`
+	// PullRequestUrl is the pull request re-applying devtron state in environments which deploy through pull requests
+	PullRequestUrl string `json:"pullRequestUrl,omitempty"`
+}
+
+// GitOpsDriftResource is a resource of argocd app whose live state differs from gitops repo
+type GitOpsDriftResource struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+type GitOpsDriftReapplyRequest struct {
+	PipelineId int   `json:"pipelineId" validate:"required,number"`
+	UserId     int32 `json:"-"`
+}
+
+const (
+	GitOpsRepoMigrationStatusMigrated = "Migrated"
+	GitOpsRepoMigrationStatusSkipped  = "Skipped"
+	GitOpsRepoMigrationStatusFailed   = "Failed"
+)
+
+type GitOpsRepoMigrationRequest struct {
+	AppIds []int `json:"appIds"` //all apps deployed through gitops when empty
+	UserId int32 `json:"-"`
+}
+
+type GitOpsRepoMigrationResponse struct {
+	AppId      int    `json:"appId"`
+	AppName    string `json:"appName"`
+	GitRepoUrl string `json:"gitRepoUrl,omitempty"`
+	AppDir     string `json:"appDir,omitempty"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+}
@@ -17,6 +17,8 @@
 
 package bean
 
+import "time"
+
 // CreateVulnerabilityPolicyRequest defines model for CreateVulnerabilityPolicyRequest.
 type CreateVulnerabilityPolicyRequest struct {
 	// actions which can be taken on vulnerabilities
@@ -26,6 +28,16 @@ type CreateVulnerabilityPolicyRequest struct {
 	CveId     string               `json:"cveId,omitempty"`
 	EnvId     int                  `json:"envId,omitempty"`
 	Severity  string               `json:"severity,omitempty"`
+	// ExpiresOn makes the policy of a cve an exception, justification is mandatory for exceptions and they are in
+	// effect only after approval by another user
+	ExpiresOn     *time.Time `json:"expiresOn,omitempty"`
+	Justification string     `json:"justification,omitempty"`
+}
+
+// ReviewCveExceptionRequest approves or rejects a pending exception.
+type ReviewCveExceptionRequest struct {
+	Id      int  `json:"id" validate:"required"`
+	Approve bool `json:"approve"`
 }
 
 // CreateVulnerabilityPolicyResponse defines model for CreateVulnerabilityPolicyResponse.
@@ -43,6 +55,45 @@ type CvePolicy struct {
 
 	// In case of CVE policy this is same as cve name else it is blank
 	Name string `json:"name,omitempty"`
+
+	// Set in case of exceptions
+	ExpiresOn      *time.Time `json:"expiresOn,omitempty"`
+	Justification  string     `json:"justification,omitempty"`
+	ApprovalStatus string     `json:"approvalStatus,omitempty"`
+	ApprovedBy     int32      `json:"approvedBy,omitempty"`
+}
+
+// CveExceptionDetail defines model for an exception in expiring exceptions report.
+type CveExceptionDetail struct {
+	Id             int       `json:"id"`
+	CveName        string    `json:"cveName"`
+	Severity       string    `json:"severity"`
+	PolicyOrigin   string    `json:"policyOrigin"`
+	ClusterId      int       `json:"clusterId,omitempty"`
+	ClusterName    string    `json:"clusterName,omitempty"`
+	EnvId          int       `json:"envId,omitempty"`
+	EnvName        string    `json:"envName,omitempty"`
+	AppId          int       `json:"appId,omitempty"`
+	AppName        string    `json:"appName,omitempty"`
+	TeamId         int       `json:"-"`
+	Justification  string    `json:"justification"`
+	ApprovalStatus string    `json:"approvalStatus"`
+	ApprovedBy     string    `json:"approvedBy"`
+	CreatedBy      string    `json:"createdBy"`
+	ExpiresOn      time.Time `json:"expiresOn"`
+	ExpiryNotified bool      `json:"expiryNotified"`
+}
+
+// DeployedImageCveFinding defines model for cves found blocked by rescanning an image running in an environment.
+type DeployedImageCveFinding struct {
+	ImageScanDeployInfoId int      `json:"imageScanDeployInfoId"`
+	AppId                 int      `json:"appId"`
+	AppName               string   `json:"appName"`
+	EnvId                 int      `json:"envId"`
+	EnvName               string   `json:"envName"`
+	TeamId                int      `json:"-"`
+	Image                 string   `json:"image"`
+	BlockedCves           []string `json:"blockedCves"`
 }
 
 // DeleteVulnerabilityPolicyResponse defines model for DeleteVulnerabilityPolicyResponse.
@@ -51,6 +51,8 @@ type RoleGroup struct {
 	RoleFilters []RoleFilter `json:"roleFilters"`
 	Status      string       `json:"status,omitempty"`
 	UserId      int32        `json:"-"` // created or modified user id
+	// ScimProvisioned is set for groups created by the identity provider through scim, only they are managed by it
+	ScimProvisioned bool `json:"-"`
 }
 
 type RoleFilter struct {
@@ -60,6 +62,8 @@ type RoleFilter struct {
 	Environment string `json:"environment"`
 	Action      string `json:"action"`
 	AccessType  string `json:"accessType"`
+	// ExpiresOn makes the role grant time-bound, the role is revoked once it expires
+	ExpiresOn *time.Time `json:"expiresOn,omitempty"`
 }
 
 type Role struct {
@@ -103,3 +107,64 @@ const SUPERADMIN = "role:super-admin___"
 const APP_ACCESS_TYPE_HELM = "helm-app"
 
 const USER_TYPE_API_TOKEN = "apiToken"
+
+// SYSTEM_USER_ID is the user "system" created by migrations, recorded as the actor of actions taken by devtron itself
+const SYSTEM_USER_ID int32 = 1
+
+const (
+	RBAC_PERMITTED_ACTION_TRIGGER = "trigger"
+	RBAC_PERMITTED_ACTION_EDIT    = "edit"
+)
+
+type RbacExplainRequest struct {
+	EmailId   string `json:"emailId"`
+	GroupName string `json:"groupName"`
+	Resource  string `json:"resource" validate:"required"`
+	Action    string `json:"action" validate:"required"`
+	Object    string `json:"object" validate:"required"`
+}
+
+type RbacExplainResponse struct {
+	Subject  string                   `json:"subject"`
+	Allowed  bool                     `json:"allowed"`
+	Policies []*RbacPolicyExplanation `json:"policies"`
+}
+
+// RbacPolicyExplanation is a policy line matching the request, granted to the subject through roles and role groups
+type RbacPolicyExplanation struct {
+	Subject    string    `json:"subject"`
+	Resource   string    `json:"resource"`
+	Action     string    `json:"action"`
+	Object     string    `json:"object"`
+	Effect     string    `json:"effect"`
+	GrantedVia []string  `json:"grantedVia"`
+	RoleGroups []string  `json:"roleGroups,omitempty"`
+	Role       *RoleData `json:"role,omitempty"`
+}
+
+type RbacPermittedUsersRequest struct {
+	AppId  int    `json:"appId" validate:"required"`
+	EnvId  int    `json:"envId" validate:"required"`
+	Action string `json:"action" validate:"oneof=trigger edit"`
+}
+
+type RbacPermittedUser struct {
+	UserId     int32    `json:"userId"`
+	EmailId    string   `json:"emailId"`
+	IsApiToken bool     `json:"isApiToken"`
+	GrantedVia []string `json:"grantedVia"`
+	RoleGroups []string `json:"roleGroups,omitempty"`
+}
+
+// RoleGrantExpiry is a time-bound role grant of a user or a role group which is expiring
+type RoleGrantExpiry struct {
+	UserRoleId             int       `json:"-"`
+	RoleGroupRoleMappingId int       `json:"-"`
+	UserId                 int32     `json:"userId,omitempty"`
+	RoleGroupId            int32     `json:"roleGroupId,omitempty"`
+	RoleGroupName          string    `json:"roleGroupName,omitempty"`
+	Role                   *RoleData `json:"role"`
+	ExpiresOn              time.Time `json:"expiresOn"`
+	// EmailIds are the users losing the role, members of role group for a role group grant
+	EmailIds []string `json:"emailIds"`
+}
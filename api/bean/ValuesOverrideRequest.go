@@ -43,6 +43,28 @@ type ValuesOverrideRequest struct {
 	CdWorkflowId       int                   `json:"cdWorkflowId"`
 	UserId             int32                 `json:"-"`
 	DeploymentType     models.DeploymentType `json:"-"`
+	SkipCanaryAnalysis bool                  `json:"-"`
+	// SkipDeploymentGates is set by system rollbacks which redeploy a previously healthy artifact, approval, promotion,
+	// deployment window, image signature and vulnerability checks are not applied to them
+	SkipDeploymentGates bool `json:"-"`
+	// OverrideDeploymentWindow allows super admin to deploy through an active deployment freeze window
+	OverrideDeploymentWindow bool   `json:"overrideDeploymentWindow"`
+	OverrideReason           string `json:"overrideReason"`
+}
+
+// DeploymentWindowOverride is a deployment made by super admin through active deployment freeze windows, it is
+// notified to the subscribers of the pipeline
+type DeploymentWindowOverride struct {
+	PipelineId   int      `json:"pipelineId"`
+	AppId        int      `json:"appId"`
+	AppName      string   `json:"appName"`
+	EnvId        int      `json:"envId"`
+	EnvName      string   `json:"envName"`
+	TeamId       int      `json:"-"`
+	CiArtifactId int      `json:"ciArtifactId"`
+	WindowNames  []string `json:"windowNames"`
+	OverriddenBy string   `json:"overriddenBy"`
+	Reason       string   `json:"reason"`
 }
 
 type ReleaseStatusUpdateRequest struct {
@@ -15,6 +15,8 @@ var ChartRepositoryWireSet = wire.NewSet(
 	wire.Bind(new(chartRepoRepository.ChartRepository), new(*chartRepoRepository.ChartRepositoryImpl)),
 	chartRepo.NewChartRepositoryServiceImpl,
 	wire.Bind(new(chartRepo.ChartRepositoryService), new(*chartRepo.ChartRepositoryServiceImpl)),
+	chartRepo.NewOCIChartSyncServiceImpl,
+	wire.Bind(new(chartRepo.OCIChartSyncService), new(*chartRepo.OCIChartSyncServiceImpl)),
 	NewChartRepositoryRestHandlerImpl,
 	wire.Bind(new(ChartRepositoryRestHandler), new(*ChartRepositoryRestHandlerImpl)),
 	NewChartRepositoryRouterImpl,
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	util2 "github.com/devtron-labs/devtron/util"
@@ -54,13 +55,14 @@ type ClusterRestHandler interface {
 }
 
 type ClusterRestHandlerImpl struct {
-	clusterService  cluster.ClusterService
-	logger          *zap.SugaredLogger
-	userService     user.UserService
-	validator       *validator.Validate
-	enforcer        casbin.Enforcer
-	deleteService   delete2.DeleteService
-	argoUserService argo.ArgoUserService
+	clusterService    cluster.ClusterService
+	logger            *zap.SugaredLogger
+	userService       user.UserService
+	validator         *validator.Validate
+	enforcer          casbin.Enforcer
+	deleteService     delete2.DeleteService
+	argoUserService   argo.ArgoUserService
+	auditEventService auditLog.AuditEventService
 }
 
 func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
@@ -69,15 +71,17 @@ func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
 	validator *validator.Validate,
 	enforcer casbin.Enforcer,
 	deleteService delete2.DeleteService,
-	argoUserService argo.ArgoUserService) *ClusterRestHandlerImpl {
+	argoUserService argo.ArgoUserService,
+	auditEventService auditLog.AuditEventService) *ClusterRestHandlerImpl {
 	return &ClusterRestHandlerImpl{
-		clusterService:  clusterService,
-		logger:          logger,
-		userService:     userService,
-		validator:       validator,
-		enforcer:        enforcer,
-		deleteService:   deleteService,
-		argoUserService: argoUserService,
+		clusterService:    clusterService,
+		logger:            logger,
+		userService:       userService,
+		validator:         validator,
+		enforcer:          enforcer,
+		deleteService:     deleteService,
+		argoUserService:   argoUserService,
+		auditEventService: auditEventService,
 	}
 }
 
@@ -137,6 +141,13 @@ func (impl ClusterRestHandlerImpl) Save(w http.ResponseWriter, r *http.Request)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	impl.auditEventService.Record(r, &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_CLUSTER,
+		ResourceId:   bean.Id,
+		ResourceName: bean.ClusterName,
+		Action:       auditLog.ACTION_CREATE,
+		After:        bean,
+	})
 
 	/*	isTriggered, err := impl.installedAppService.DeployDefaultChartOnCluster(bean, userId)
 		if err != nil {
@@ -269,12 +280,26 @@ func (impl ClusterRestHandlerImpl) Update(w http.ResponseWriter, r *http.Request
 		}
 		ctx = context.WithValue(ctx, "token", acdToken)
 	}
+	existingCluster, err := impl.clusterService.FindById(bean.Id)
+	if err != nil {
+		impl.logger.Errorw("service err, Update", "error", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	_, err = impl.clusterService.Update(ctx, &bean, userId)
 	if err != nil {
 		impl.logger.Errorw("service err, Update", "error", err, "payload", bean)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	impl.auditEventService.Record(r, &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_CLUSTER,
+		ResourceId:   bean.Id,
+		ResourceName: bean.ClusterName,
+		Action:       auditLog.ACTION_UPDATE,
+		Before:       existingCluster,
+		After:        bean,
+	})
 
 	common.WriteJsonResp(w, err, bean, http.StatusOK)
 }
@@ -352,11 +377,24 @@ func (impl ClusterRestHandlerImpl) DeleteCluster(w http.ResponseWriter, r *http.
 		return
 	}
 	//RBAC enforcer Ends
+	existingCluster, err := impl.clusterService.FindById(bean.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster", "err", err, "id", bean.Id, "name", bean.ClusterName)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	err = impl.deleteService.DeleteCluster(&bean, userId)
 	if err != nil {
 		impl.logger.Errorw("error in deleting cluster", "err", err, "id", bean.Id, "name", bean.ClusterName)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	impl.auditEventService.Record(r, &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_CLUSTER,
+		ResourceId:   existingCluster.Id,
+		ResourceName: existingCluster.ClusterName,
+		Action:       auditLog.ACTION_DELETE,
+		Before:       existingCluster,
+	})
 	common.WriteJsonResp(w, err, CLUSTER_DELETE_SUCCESS_RESP, http.StatusOK)
 }
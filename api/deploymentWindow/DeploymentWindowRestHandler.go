@@ -0,0 +1,204 @@
+package deploymentWindow
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/gorilla/mux"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type DeploymentWindowRestHandler interface {
+	CreateDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	UpdateDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	DeleteDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindows(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindowById(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindowOverrides(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentWindowRestHandlerImpl struct {
+	logger                  *zap.SugaredLogger
+	deploymentWindowService deploymentWindow.DeploymentWindowService
+	userService             user.UserService
+	enforcer                casbin.Enforcer
+	validator               *validator.Validate
+}
+
+func NewDeploymentWindowRestHandlerImpl(logger *zap.SugaredLogger,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate,
+) *DeploymentWindowRestHandlerImpl {
+	return &DeploymentWindowRestHandlerImpl{
+		logger:                  logger,
+		deploymentWindowService: deploymentWindowService,
+		userService:             userService,
+		enforcer:                enforcer,
+		validator:               validator,
+	}
+}
+
+func (impl DeploymentWindowRestHandlerImpl) CreateDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentWindow.DeploymentWindowDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, CreateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	bean.UserId = userId
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, CreateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.deploymentWindowService.Create(&bean)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) UpdateDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean deploymentWindow.DeploymentWindowDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, UpdateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	bean.UserId = userId
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, UpdateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.deploymentWindowService.Update(&bean)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateDeploymentWindow", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) DeleteDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = impl.deploymentWindowService.Delete(id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteDeploymentWindow", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, id, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) GetDeploymentWindows(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// auth free api as freeze windows are shown on trigger pages of all users
+	res, err := impl.deploymentWindowService.GetAll()
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentWindows", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) GetDeploymentWindowById(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.deploymentWindowService.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentWindowById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl DeploymentWindowRestHandlerImpl) GetDeploymentWindowOverrides(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.deploymentWindowService.GetOverrides(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDeploymentWindowOverrides", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
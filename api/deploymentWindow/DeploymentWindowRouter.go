@@ -0,0 +1,25 @@
+package deploymentWindow
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type DeploymentWindowRouter interface {
+	InitDeploymentWindowRouter(configRouter *mux.Router)
+}
+type DeploymentWindowRouterImpl struct {
+	deploymentWindowRestHandler DeploymentWindowRestHandler
+}
+
+func NewDeploymentWindowRouterImpl(deploymentWindowRestHandler DeploymentWindowRestHandler) *DeploymentWindowRouterImpl {
+	return &DeploymentWindowRouterImpl{deploymentWindowRestHandler: deploymentWindowRestHandler}
+}
+
+func (impl DeploymentWindowRouterImpl) InitDeploymentWindowRouter(configRouter *mux.Router) {
+	configRouter.Path("").HandlerFunc(impl.deploymentWindowRestHandler.CreateDeploymentWindow).Methods("POST")
+	configRouter.Path("").HandlerFunc(impl.deploymentWindowRestHandler.UpdateDeploymentWindow).Methods("PUT")
+	configRouter.Path("").HandlerFunc(impl.deploymentWindowRestHandler.GetDeploymentWindows).Methods("GET")
+	configRouter.Path("/{id}").HandlerFunc(impl.deploymentWindowRestHandler.GetDeploymentWindowById).Methods("GET")
+	configRouter.Path("/{id}").HandlerFunc(impl.deploymentWindowRestHandler.DeleteDeploymentWindow).Methods("DELETE")
+	configRouter.Path("/{id}/overrides").HandlerFunc(impl.deploymentWindowRestHandler.GetDeploymentWindowOverrides).Methods("GET")
+}
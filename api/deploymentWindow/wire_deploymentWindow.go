@@ -0,0 +1,17 @@
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	"github.com/google/wire"
+)
+
+var DeploymentWindowWireSet = wire.NewSet(
+	deploymentWindow.NewDeploymentWindowRepositoryImpl,
+	wire.Bind(new(deploymentWindow.DeploymentWindowRepository), new(*deploymentWindow.DeploymentWindowRepositoryImpl)),
+	deploymentWindow.NewDeploymentWindowServiceImpl,
+	wire.Bind(new(deploymentWindow.DeploymentWindowService), new(*deploymentWindow.DeploymentWindowServiceImpl)),
+	NewDeploymentWindowRestHandlerImpl,
+	wire.Bind(new(DeploymentWindowRestHandler), new(*DeploymentWindowRestHandlerImpl)),
+	NewDeploymentWindowRouterImpl,
+	wire.Bind(new(DeploymentWindowRouter), new(*DeploymentWindowRouterImpl)),
+)
@@ -0,0 +1,195 @@
+package gitopsDrift
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/gitops"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type GitOpsDriftRestHandler interface {
+	GetDriftReports(w http.ResponseWriter, r *http.Request)
+	CheckDrift(w http.ResponseWriter, r *http.Request)
+	ReapplyDevtronState(w http.ResponseWriter, r *http.Request)
+}
+
+type GitOpsDriftRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	gitOpsDriftService gitops.GitOpsDriftService
+	pipelineRepository pipelineConfig.PipelineRepository
+	userService        user.UserService
+	enforcer           casbin.Enforcer
+	enforcerUtil       rbac.EnforcerUtil
+	validator          *validator.Validate
+}
+
+func NewGitOpsDriftRestHandlerImpl(logger *zap.SugaredLogger,
+	gitOpsDriftService gitops.GitOpsDriftService,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate,
+) *GitOpsDriftRestHandlerImpl {
+	return &GitOpsDriftRestHandlerImpl{
+		logger:             logger,
+		gitOpsDriftService: gitOpsDriftService,
+		pipelineRepository: pipelineRepository,
+		userService:        userService,
+		enforcer:           enforcer,
+		enforcerUtil:       enforcerUtil,
+		validator:          validator,
+	}
+}
+
+func (impl GitOpsDriftRestHandlerImpl) GetDriftReports(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var appId, envId int
+	if appIdParam := r.URL.Query().Get("appId"); len(appIdParam) > 0 {
+		appId, err = strconv.Atoi(appIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if envIdParam := r.URL.Query().Get("envId"); len(envIdParam) > 0 {
+		envId, err = strconv.Atoi(envIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	driftedOnly := r.URL.Query().Get("driftedOnly") == "true"
+	reports, err := impl.gitOpsDriftService.GetDriftReports(appId, envId, driftedOnly)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDriftReports", "err", err, "appId", appId, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	//RBAC, reports of apps which user can view
+	token := r.Header.Get("token")
+	appObjects := impl.enforcerUtil.GetRbacObjectsForAllApps()
+	authorizedReports := make([]*bean.GitOpsDriftReport, 0, len(reports))
+	for _, report := range reports {
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, appObjects[report.AppId]); ok {
+			authorizedReports = append(authorizedReports, report)
+		}
+	}
+	//RBAC
+	common.WriteJsonResp(w, nil, authorizedReports, http.StatusOK)
+}
+
+func (impl GitOpsDriftRestHandlerImpl) CheckDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, err := impl.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline, CheckDrift", "err", err, "pipelineId", pipelineId)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	//RBAC
+	token := r.Header.Get("token")
+	object := impl.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC
+	res, err := impl.gitOpsDriftService.CheckDrift(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("service err, CheckDrift", "err", err, "pipelineId", pipelineId)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, errors.New("pipeline is not deployed yet"), nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl GitOpsDriftRestHandlerImpl) ReapplyDevtronState(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request bean.GitOpsDriftReapplyRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, ReapplyDevtronState", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, ReapplyDevtronState", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	cdPipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline, ReapplyDevtronState", "err", err, "pipelineId", request.PipelineId)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	//RBAC, re-applying devtron state deploys like a trigger
+	token := r.Header.Get("token")
+	object := impl.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	envObject := impl.enforcerUtil.GetEnvRBACNameByAppId(cdPipeline.AppId, cdPipeline.EnvironmentId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, envObject); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC
+	res, err := impl.gitOpsDriftService.ReapplyDevtronState(&request)
+	if err != nil {
+		impl.logger.Errorw("service err, ReapplyDevtronState", "err", err, "payload", request)
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, errors.New("pipeline is not deployed yet"), nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
@@ -0,0 +1,22 @@
+package gitopsDrift
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type GitOpsDriftRouter interface {
+	InitGitOpsDriftRouter(driftRouter *mux.Router)
+}
+type GitOpsDriftRouterImpl struct {
+	gitOpsDriftRestHandler GitOpsDriftRestHandler
+}
+
+func NewGitOpsDriftRouterImpl(gitOpsDriftRestHandler GitOpsDriftRestHandler) *GitOpsDriftRouterImpl {
+	return &GitOpsDriftRouterImpl{gitOpsDriftRestHandler: gitOpsDriftRestHandler}
+}
+
+func (impl GitOpsDriftRouterImpl) InitGitOpsDriftRouter(driftRouter *mux.Router) {
+	driftRouter.Path("").HandlerFunc(impl.gitOpsDriftRestHandler.GetDriftReports).Methods("GET")
+	driftRouter.Path("/check/{pipelineId}").HandlerFunc(impl.gitOpsDriftRestHandler.CheckDrift).Methods("POST")
+	driftRouter.Path("/reapply").HandlerFunc(impl.gitOpsDriftRestHandler.ReapplyDevtronState).Methods("POST")
+}
@@ -0,0 +1,17 @@
+package gitopsDrift
+
+import (
+	"github.com/devtron-labs/devtron/pkg/gitops"
+	"github.com/google/wire"
+)
+
+var GitOpsDriftWireSet = wire.NewSet(
+	gitops.NewGitOpsDriftRepositoryImpl,
+	wire.Bind(new(gitops.GitOpsDriftRepository), new(*gitops.GitOpsDriftRepositoryImpl)),
+	gitops.NewGitOpsDriftServiceImpl,
+	wire.Bind(new(gitops.GitOpsDriftService), new(*gitops.GitOpsDriftServiceImpl)),
+	NewGitOpsDriftRestHandlerImpl,
+	wire.Bind(new(GitOpsDriftRestHandler), new(*GitOpsDriftRestHandlerImpl)),
+	NewGitOpsDriftRouterImpl,
+	wire.Bind(new(GitOpsDriftRouter), new(*GitOpsDriftRouterImpl)),
+)
@@ -0,0 +1,68 @@
+package gitopsRepo
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/gitops"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+)
+
+type GitOpsRepoRestHandler interface {
+	MigrateApps(w http.ResponseWriter, r *http.Request)
+}
+
+type GitOpsRepoRestHandlerImpl struct {
+	logger                     *zap.SugaredLogger
+	gitOpsRepoMigrationService gitops.GitOpsRepoMigrationService
+	userService                user.UserService
+	enforcer                   casbin.Enforcer
+}
+
+func NewGitOpsRepoRestHandlerImpl(logger *zap.SugaredLogger,
+	gitOpsRepoMigrationService gitops.GitOpsRepoMigrationService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+) *GitOpsRepoRestHandlerImpl {
+	return &GitOpsRepoRestHandlerImpl{
+		logger:                     logger,
+		gitOpsRepoMigrationService: gitOpsRepoMigrationService,
+		userService:                userService,
+		enforcer:                   enforcer,
+	}
+}
+
+func (impl GitOpsRepoRestHandlerImpl) MigrateApps(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.GitOpsRepoMigrationRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, MigrateApps", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	//RBAC, moving repos of apps is a change of global gitops config
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	//RBAC
+	res, err := impl.gitOpsRepoMigrationService.MigrateApps(&request)
+	if err != nil {
+		impl.logger.Errorw("service err, MigrateApps", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
@@ -0,0 +1,20 @@
+package gitopsRepo
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type GitOpsRepoRouter interface {
+	InitGitOpsRepoRouter(repoRouter *mux.Router)
+}
+type GitOpsRepoRouterImpl struct {
+	gitOpsRepoRestHandler GitOpsRepoRestHandler
+}
+
+func NewGitOpsRepoRouterImpl(gitOpsRepoRestHandler GitOpsRepoRestHandler) *GitOpsRepoRouterImpl {
+	return &GitOpsRepoRouterImpl{gitOpsRepoRestHandler: gitOpsRepoRestHandler}
+}
+
+func (impl GitOpsRepoRouterImpl) InitGitOpsRepoRouter(repoRouter *mux.Router) {
+	repoRouter.Path("/migrate").HandlerFunc(impl.gitOpsRepoRestHandler.MigrateApps).Methods("POST")
+}
@@ -0,0 +1,15 @@
+package gitopsRepo
+
+import (
+	"github.com/devtron-labs/devtron/pkg/gitops"
+	"github.com/google/wire"
+)
+
+var GitOpsRepoWireSet = wire.NewSet(
+	gitops.NewGitOpsRepoMigrationServiceImpl,
+	wire.Bind(new(gitops.GitOpsRepoMigrationService), new(*gitops.GitOpsRepoMigrationServiceImpl)),
+	NewGitOpsRepoRestHandlerImpl,
+	wire.Bind(new(GitOpsRepoRestHandler), new(*GitOpsRepoRestHandlerImpl)),
+	NewGitOpsRepoRouterImpl,
+	wire.Bind(new(GitOpsRepoRouter), new(*GitOpsRepoRouterImpl)),
+)
@@ -8,10 +8,13 @@ import (
 	openapi "github.com/devtron-labs/devtron/api/helm-app/openapiClient"
 	openapi2 "github.com/devtron-labs/devtron/api/openapi/openapiClient"
 	"github.com/devtron-labs/devtron/client/k8s/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
 	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/ociRegistry"
 	serverBean "github.com/devtron-labs/devtron/pkg/server/bean"
 	serverEnvConfig "github.com/devtron-labs/devtron/pkg/server/config"
 	serverDataStore "github.com/devtron-labs/devtron/pkg/server/store"
@@ -66,6 +69,8 @@ type HelmAppServiceImpl struct {
 	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
 	environmentService                   cluster.EnvironmentService
 	pipelineRepository                   pipelineConfig.PipelineRepository
+	chartRepoRepository                  chartRepoRepository.ChartRepoRepository
+	dockerArtifactStoreRepository        repository.DockerArtifactStoreRepository
 }
 
 func NewHelmAppServiceImpl(Logger *zap.SugaredLogger,
@@ -73,7 +78,8 @@ func NewHelmAppServiceImpl(Logger *zap.SugaredLogger,
 	helmAppClient HelmAppClient,
 	pump connector.Pump, enforcerUtil rbac.EnforcerUtilHelm, serverDataStore *serverDataStore.ServerDataStore,
 	serverEnvConfig *serverEnvConfig.ServerEnvConfig, appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
-	environmentService cluster.EnvironmentService, pipelineRepository pipelineConfig.PipelineRepository) *HelmAppServiceImpl {
+	environmentService cluster.EnvironmentService, pipelineRepository pipelineConfig.PipelineRepository,
+	chartRepoRepository chartRepoRepository.ChartRepoRepository, dockerArtifactStoreRepository repository.DockerArtifactStoreRepository) *HelmAppServiceImpl {
 	return &HelmAppServiceImpl{
 		logger:                               Logger,
 		clusterService:                       clusterService,
@@ -85,6 +91,8 @@ func NewHelmAppServiceImpl(Logger *zap.SugaredLogger,
 		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
 		environmentService:                   environmentService,
 		pipelineRepository:                   pipelineRepository,
+		chartRepoRepository:                  chartRepoRepository,
+		dockerArtifactStoreRepository:        dockerArtifactStoreRepository,
 	}
 }
 
@@ -409,6 +417,10 @@ func (impl *HelmAppServiceImpl) InstallRelease(ctx context.Context, clusterId in
 
 	installReleaseRequest.ReleaseIdentifier.ClusterConfig = config
 	impl.logger.Debugw("helm install final request", "request", installReleaseRequest)
+	err = impl.setOCIChartRepository(installReleaseRequest)
+	if err != nil {
+		return nil, err
+	}
 	installReleaseResponse, err := impl.helmAppClient.InstallRelease(ctx, installReleaseRequest)
 	if err != nil {
 		impl.logger.Errorw("error in installing release", "err", err)
@@ -426,6 +438,10 @@ func (impl *HelmAppServiceImpl) UpdateApplicationWithChartInfo(ctx context.Conte
 	}
 
 	updateReleaseRequest.ReleaseIdentifier.ClusterConfig = config
+	err = impl.setOCIChartRepository(updateReleaseRequest)
+	if err != nil {
+		return nil, err
+	}
 
 	updateReleaseResponse, err := impl.helmAppClient.UpdateApplicationWithChartInfo(ctx, updateReleaseRequest)
 	if err != nil {
@@ -440,6 +456,37 @@ func (impl *HelmAppServiceImpl) UpdateApplicationWithChartInfo(ctx context.Conte
 	return response, nil
 }
 
+// setOCIChartRepository points chart repository of an oci registry to repository of the chart with credentials of its
+// container registry, credentials are read on every install as tokens of ecr expire
+func (impl *HelmAppServiceImpl) setOCIChartRepository(request *InstallReleaseRequest) error {
+	chartRepository := request.ChartRepository
+	if chartRepository == nil || !strings.HasPrefix(chartRepository.Url, ociRegistry.OCIScheme) {
+		return nil
+	}
+	chartRepo, err := impl.chartRepoRepository.FindByName(chartRepository.Name)
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart repo", "name", chartRepository.Name, "err", err)
+		return err
+	}
+	if !chartRepo.IsOCIRegistry {
+		return nil
+	}
+	store, err := impl.dockerArtifactStoreRepository.FindOne(chartRepo.DockerArtifactStoreId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching container registry of chart repo", "chartRepo", chartRepo.Name, "err", err)
+		return err
+	}
+	credential, err := ociRegistry.GetCredential(store)
+	if err != nil {
+		impl.logger.Errorw("error in getting credentials of container registry", "registry", store.Id, "err", err)
+		return err
+	}
+	chartRepository.Url = ociRegistry.GetChartRepositoryUrl(chartRepo.Url, ociRegistry.SplitRepositories(chartRepo.OCIRepositories), request.ChartName)
+	chartRepository.Username = credential.Username
+	chartRepository.Password = credential.Password
+	return nil
+}
+
 func (impl *HelmAppServiceImpl) IsReleaseInstalled(ctx context.Context, app *AppIdentifier) (bool, error) {
 	config, err := impl.GetClusterConf(app.ClusterId)
 	if err != nil {
@@ -641,6 +688,10 @@ func (impl *HelmAppServiceImpl) TemplateChart(ctx context.Context, templateChart
 	}
 
 	installReleaseRequest.ReleaseIdentifier.ClusterConfig = config
+	err = impl.setOCIChartRepository(installReleaseRequest)
+	if err != nil {
+		return nil, err
+	}
 
 	templateChartResponse, err := impl.helmAppClient.TemplateChart(ctx, installReleaseRequest)
 	if err != nil {
@@ -0,0 +1,187 @@
+package imageSignature
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/imageSignature"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/gorilla/mux"
+	"github.com/juju/errors"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type ImageSignatureRestHandler interface {
+	CreateImageSignaturePolicy(w http.ResponseWriter, r *http.Request)
+	UpdateImageSignaturePolicy(w http.ResponseWriter, r *http.Request)
+	DeleteImageSignaturePolicy(w http.ResponseWriter, r *http.Request)
+	GetImageSignaturePolicies(w http.ResponseWriter, r *http.Request)
+	GetImageSignaturePolicyById(w http.ResponseWriter, r *http.Request)
+}
+
+type ImageSignatureRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	imageSignatureService imageSignature.ImageSignatureService
+	userService           user.UserService
+	enforcer              casbin.Enforcer
+	validator             *validator.Validate
+}
+
+func NewImageSignatureRestHandlerImpl(logger *zap.SugaredLogger,
+	imageSignatureService imageSignature.ImageSignatureService,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate,
+) *ImageSignatureRestHandlerImpl {
+	return &ImageSignatureRestHandlerImpl{
+		logger:                logger,
+		imageSignatureService: imageSignatureService,
+		userService:           userService,
+		enforcer:              enforcer,
+		validator:             validator,
+	}
+}
+
+func (impl ImageSignatureRestHandlerImpl) CreateImageSignaturePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean imageSignature.ImageSignaturePolicyDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, CreateImageSignaturePolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	bean.UserId = userId
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, CreateImageSignaturePolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.imageSignatureService.Create(&bean)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateImageSignaturePolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ImageSignatureRestHandlerImpl) UpdateImageSignaturePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var bean imageSignature.ImageSignaturePolicyDto
+	err = decoder.Decode(&bean)
+	if err != nil {
+		impl.logger.Errorw("request err, UpdateImageSignaturePolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	bean.UserId = userId
+	err = impl.validator.Struct(bean)
+	if err != nil {
+		impl.logger.Errorw("validation err, UpdateImageSignaturePolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.imageSignatureService.Update(&bean)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateImageSignaturePolicy", "err", err, "payload", bean)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ImageSignatureRestHandlerImpl) DeleteImageSignaturePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = impl.imageSignatureService.Delete(id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteImageSignaturePolicy", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, id, http.StatusOK)
+}
+
+func (impl ImageSignatureRestHandlerImpl) GetImageSignaturePolicies(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.imageSignatureService.GetAll()
+	if err != nil {
+		impl.logger.Errorw("service err, GetImageSignaturePolicies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl ImageSignatureRestHandlerImpl) GetImageSignaturePolicyById(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.imageSignatureService.GetById(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetImageSignaturePolicyById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
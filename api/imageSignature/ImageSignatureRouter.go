@@ -0,0 +1,24 @@
+package imageSignature
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ImageSignatureRouter interface {
+	InitImageSignatureRouter(configRouter *mux.Router)
+}
+type ImageSignatureRouterImpl struct {
+	imageSignatureRestHandler ImageSignatureRestHandler
+}
+
+func NewImageSignatureRouterImpl(imageSignatureRestHandler ImageSignatureRestHandler) *ImageSignatureRouterImpl {
+	return &ImageSignatureRouterImpl{imageSignatureRestHandler: imageSignatureRestHandler}
+}
+
+func (impl ImageSignatureRouterImpl) InitImageSignatureRouter(configRouter *mux.Router) {
+	configRouter.Path("").HandlerFunc(impl.imageSignatureRestHandler.CreateImageSignaturePolicy).Methods("POST")
+	configRouter.Path("").HandlerFunc(impl.imageSignatureRestHandler.UpdateImageSignaturePolicy).Methods("PUT")
+	configRouter.Path("").HandlerFunc(impl.imageSignatureRestHandler.GetImageSignaturePolicies).Methods("GET")
+	configRouter.Path("/{id}").HandlerFunc(impl.imageSignatureRestHandler.GetImageSignaturePolicyById).Methods("GET")
+	configRouter.Path("/{id}").HandlerFunc(impl.imageSignatureRestHandler.DeleteImageSignaturePolicy).Methods("DELETE")
+}
@@ -0,0 +1,17 @@
+package imageSignature
+
+import (
+	"github.com/devtron-labs/devtron/pkg/imageSignature"
+	"github.com/google/wire"
+)
+
+var ImageSignatureWireSet = wire.NewSet(
+	imageSignature.NewImageSignaturePolicyRepositoryImpl,
+	wire.Bind(new(imageSignature.ImageSignaturePolicyRepository), new(*imageSignature.ImageSignaturePolicyRepositoryImpl)),
+	imageSignature.NewImageSignatureServiceImpl,
+	wire.Bind(new(imageSignature.ImageSignatureService), new(*imageSignature.ImageSignatureServiceImpl)),
+	NewImageSignatureRestHandlerImpl,
+	wire.Bind(new(ImageSignatureRestHandler), new(*ImageSignatureRestHandlerImpl)),
+	NewImageSignatureRouterImpl,
+	wire.Bind(new(ImageSignatureRouter), new(*ImageSignatureRouterImpl)),
+)
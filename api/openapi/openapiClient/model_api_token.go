@@ -36,6 +36,8 @@ type ApiToken struct {
 	LastUsedByIp *string `json:"lastUsedByIp,omitempty"`
 	// token last updatedAt
 	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Scope of api-token
+	Scope *ApiTokenScope `json:"scope,omitempty"`
 }
 
 // NewApiToken instantiates a new ApiToken object
@@ -375,6 +377,38 @@ func (o *ApiToken) SetUpdatedAt(v string) {
 	o.UpdatedAt = &v
 }
 
+// GetScope returns the Scope field value if set, zero value otherwise.
+func (o *ApiToken) GetScope() ApiTokenScope {
+	if o == nil || o.Scope == nil {
+		var ret ApiTokenScope
+		return ret
+	}
+	return *o.Scope
+}
+
+// GetScopeOk returns a tuple with the Scope field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetScopeOk() (*ApiTokenScope, bool) {
+	if o == nil || o.Scope == nil {
+		return nil, false
+	}
+	return o.Scope, true
+}
+
+// HasScope returns a boolean if a field has been set.
+func (o *ApiToken) HasScope() bool {
+	if o != nil && o.Scope != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScope gets a reference to the given ApiTokenScope and assigns it to the Scope field.
+func (o *ApiToken) SetScope(v ApiTokenScope) {
+	o.Scope = &v
+}
+
 func (o ApiToken) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Id != nil {
@@ -407,6 +441,9 @@ func (o ApiToken) MarshalJSON() ([]byte, error) {
 	if o.UpdatedAt != nil {
 		toSerialize["updatedAt"] = o.UpdatedAt
 	}
+	if o.Scope != nil {
+		toSerialize["scope"] = o.Scope
+	}
 	return json.Marshal(toSerialize)
 }
 
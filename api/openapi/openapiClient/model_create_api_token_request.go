@@ -23,6 +23,8 @@ type CreateApiTokenRequest struct {
 	Description *string `json:"description,omitempty,notnull" validate:"required"`
 	// Expiration time of api-token in milliseconds
 	ExpireAtInMs *int64 `json:"expireAtInMs,omitempty"`
+	// Scope of api-token
+	Scope *ApiTokenScope `json:"scope,omitempty"`
 }
 
 // NewCreateApiTokenRequest instantiates a new CreateApiTokenRequest object
@@ -138,6 +140,38 @@ func (o *CreateApiTokenRequest) SetExpireAtInMs(v int64) {
 	o.ExpireAtInMs = &v
 }
 
+// GetScope returns the Scope field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetScope() ApiTokenScope {
+	if o == nil || o.Scope == nil {
+		var ret ApiTokenScope
+		return ret
+	}
+	return *o.Scope
+}
+
+// GetScopeOk returns a tuple with the Scope field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetScopeOk() (*ApiTokenScope, bool) {
+	if o == nil || o.Scope == nil {
+		return nil, false
+	}
+	return o.Scope, true
+}
+
+// HasScope returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasScope() bool {
+	if o != nil && o.Scope != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScope gets a reference to the given ApiTokenScope and assigns it to the Scope field.
+func (o *CreateApiTokenRequest) SetScope(v ApiTokenScope) {
+	o.Scope = &v
+}
+
 func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Name != nil {
@@ -149,6 +183,9 @@ func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	if o.ExpireAtInMs != nil {
 		toSerialize["expireAtInMs"] = o.ExpireAtInMs
 	}
+	if o.Scope != nil {
+		toSerialize["scope"] = o.Scope
+	}
 	return json.Marshal(toSerialize)
 }
 
@@ -58,6 +58,8 @@ type AppListingRestHandler interface {
 
 	FetchOtherEnvironment(w http.ResponseWriter, r *http.Request)
 	RedirectToLinkouts(w http.ResponseWriter, r *http.Request)
+	FetchAppDeploymentStatusTimeline(w http.ResponseWriter, r *http.Request)
+	FetchAppDeploymentFailedResources(w http.ResponseWriter, r *http.Request)
 }
 
 type AppListingRestHandlerImpl struct {
@@ -74,6 +76,7 @@ type AppListingRestHandlerImpl struct {
 	clusterService         cluster.ClusterService
 	helmAppService         client.HelmAppService
 	argoUserService        argo.ArgoUserService
+	timelineService        app.PipelineStatusTimelineService
 }
 
 type AppStatus struct {
@@ -92,7 +95,7 @@ func NewAppListingRestHandlerImpl(application application.ServiceClient,
 	logger *zap.SugaredLogger, enforcerUtil rbac.EnforcerUtil,
 	deploymentGroupService deploymentGroup.DeploymentGroupService, userService user.UserService,
 	helmAppClient client.HelmAppClient, clusterService cluster.ClusterService, helmAppService client.HelmAppService,
-	argoUserService argo.ArgoUserService) *AppListingRestHandlerImpl {
+	argoUserService argo.ArgoUserService, timelineService app.PipelineStatusTimelineService) *AppListingRestHandlerImpl {
 	appListingHandler := &AppListingRestHandlerImpl{
 		application:            application,
 		appListingService:      appListingService,
@@ -107,6 +110,7 @@ func NewAppListingRestHandlerImpl(application application.ServiceClient,
 		clusterService:         clusterService,
 		helmAppService:         helmAppService,
 		argoUserService:        argoUserService,
+		timelineService:        timelineService,
 	}
 	return appListingHandler
 }
@@ -515,6 +519,87 @@ func (handler AppListingRestHandlerImpl) FetchOtherEnvironment(w http.ResponseWr
 	common.WriteJsonResp(w, err, otherEnvironment, http.StatusOK)
 }
 
+// getDeploymentStatusRequest parses app, env and optional wfrId of a deployment status request and checks that the
+// user has access to the app in the environment
+func (handler AppListingRestHandlerImpl) getDeploymentStatusRequest(w http.ResponseWriter, r *http.Request) (appId int, envId int, wfrId int, ok bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, 0, 0, false
+	}
+	vars := mux.Vars(r)
+	appId, err = strconv.Atoi(vars["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	envId, err = strconv.Atoi(vars["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	wfrIdParam := r.URL.Query().Get("wfrId")
+	if len(wfrIdParam) != 0 {
+		wfrId, err = strconv.Atoi(wfrIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return 0, 0, 0, false
+		}
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, 0, 0, false
+	}
+	object = handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, 0, 0, false
+	}
+	//RBAC enforcer Ends
+	return appId, envId, wfrId, true
+}
+
+func (handler AppListingRestHandlerImpl) FetchAppDeploymentStatusTimeline(w http.ResponseWriter, r *http.Request) {
+	appId, envId, wfrId, ok := handler.getDeploymentStatusRequest(w, r)
+	if !ok {
+		return
+	}
+	timelines, err := handler.timelineService.FetchTimelines(appId, envId, wfrId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			err = &util.ApiError{Code: "404", HttpStatusCode: 200, UserMessage: "no deployment found"}
+			common.WriteJsonResp(w, err, nil, http.StatusOK)
+			return
+		}
+		handler.logger.Errorw("service err, FetchAppDeploymentStatusTimeline", "err", err, "appId", appId, "envId", envId, "wfrId", wfrId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, timelines, http.StatusOK)
+}
+
+func (handler AppListingRestHandlerImpl) FetchAppDeploymentFailedResources(w http.ResponseWriter, r *http.Request) {
+	appId, envId, wfrId, ok := handler.getDeploymentStatusRequest(w, r)
+	if !ok {
+		return
+	}
+	resources, err := handler.timelineService.FetchFailedResources(appId, envId, wfrId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			err = &util.ApiError{Code: "404", HttpStatusCode: 200, UserMessage: "no deployment found"}
+			common.WriteJsonResp(w, err, nil, http.StatusOK)
+			return
+		}
+		handler.logger.Errorw("service err, FetchAppDeploymentFailedResources", "err", err, "appId", appId, "envId", envId, "wfrId", wfrId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resources, http.StatusOK)
+}
+
 func (handler AppListingRestHandlerImpl) RedirectToLinkouts(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("token")
 	vars := mux.Vars(r)
@@ -0,0 +1,201 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type CanaryAnalysisRestHandler interface {
+	SaveConfig(w http.ResponseWriter, r *http.Request)
+	GetConfig(w http.ResponseWriter, r *http.Request)
+	DeleteConfig(w http.ResponseWriter, r *http.Request)
+	GetRuns(w http.ResponseWriter, r *http.Request)
+}
+
+type CanaryAnalysisRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	canaryAnalysisService pipeline.CanaryAnalysisService
+	pipelineRepository    pipelineConfig.PipelineRepository
+	userAuthService       user.UserService
+	validator             *validator.Validate
+	enforcer              casbin.Enforcer
+	enforcerUtil          rbac.EnforcerUtil
+}
+
+func NewCanaryAnalysisRestHandlerImpl(logger *zap.SugaredLogger, canaryAnalysisService pipeline.CanaryAnalysisService,
+	pipelineRepository pipelineConfig.PipelineRepository, userAuthService user.UserService, validator *validator.Validate,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil) *CanaryAnalysisRestHandlerImpl {
+	return &CanaryAnalysisRestHandlerImpl{
+		logger:                logger,
+		canaryAnalysisService: canaryAnalysisService,
+		pipelineRepository:    pipelineRepository,
+		userAuthService:       userAuthService,
+		validator:             validator,
+		enforcer:              enforcer,
+		enforcerUtil:          enforcerUtil,
+	}
+}
+
+func (handler CanaryAnalysisRestHandlerImpl) SaveConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipeline.CanaryAnalysisConfigDto
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	handler.logger.Infow("request payload, SaveConfig", "payload", request)
+	res, err := handler.canaryAnalysisService.SaveConfig(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CanaryAnalysisRestHandlerImpl) GetConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.canaryAnalysisService.GetConfig(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			err = &util.ApiError{Code: "404", HttpStatusCode: 200, UserMessage: "canary analysis not configured"}
+			common.WriteJsonResp(w, err, nil, http.StatusOK)
+			return
+		}
+		handler.logger.Errorw("service err, GetConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CanaryAnalysisRestHandlerImpl) DeleteConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	err = handler.canaryAnalysisService.DeleteConfig(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteConfig", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler CanaryAnalysisRestHandlerImpl) GetRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	offset := 0
+	size := 20
+	if offsetQueryParam := r.URL.Query().Get("offset"); len(offsetQueryParam) > 0 {
+		offset, err = strconv.Atoi(offsetQueryParam)
+		if err != nil || offset < 0 {
+			common.WriteJsonResp(w, err, "invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+	if sizeQueryParam := r.URL.Query().Get("size"); len(sizeQueryParam) > 0 {
+		size, err = strconv.Atoi(sizeQueryParam)
+		if err != nil || size <= 0 {
+			common.WriteJsonResp(w, err, "invalid size", http.StatusBadRequest)
+			return
+		}
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.canaryAnalysisService.GetRuns(pipelineId, offset, size)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRuns", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CanaryAnalysisRestHandlerImpl) checkPipelineAuth(w http.ResponseWriter, r *http.Request, pipelineId int, action string) bool {
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	token := r.Header.Get("token")
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, pipelineId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type CdApprovalRestHandler interface {
+	SavePolicy(w http.ResponseWriter, r *http.Request)
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	RaiseRequest(w http.ResponseWriter, r *http.Request)
+	GetRequests(w http.ResponseWriter, r *http.Request)
+	ApproveRequest(w http.ResponseWriter, r *http.Request)
+	RejectRequest(w http.ResponseWriter, r *http.Request)
+}
+
+type CdApprovalRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	cdApprovalService  pipeline.CdApprovalService
+	pipelineRepository pipelineConfig.PipelineRepository
+	userAuthService    user.UserService
+	validator          *validator.Validate
+	enforcer           casbin.Enforcer
+	enforcerUtil       rbac.EnforcerUtil
+}
+
+func NewCdApprovalRestHandlerImpl(logger *zap.SugaredLogger, cdApprovalService pipeline.CdApprovalService,
+	pipelineRepository pipelineConfig.PipelineRepository, userAuthService user.UserService, validator *validator.Validate,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil) *CdApprovalRestHandlerImpl {
+	return &CdApprovalRestHandlerImpl{
+		logger:             logger,
+		cdApprovalService:  cdApprovalService,
+		pipelineRepository: pipelineRepository,
+		userAuthService:    userAuthService,
+		validator:          validator,
+		enforcer:           enforcer,
+		enforcerUtil:       enforcerUtil,
+	}
+}
+
+type CdApprovalActionRequest struct {
+	Comment string `json:"comment"`
+}
+
+func (handler CdApprovalRestHandlerImpl) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipeline.CdApprovalPolicyDto
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	handler.logger.Infow("request payload, SavePolicy", "payload", request)
+	res, err := handler.cdApprovalService.SavePolicy(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdApprovalRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.cdApprovalService.GetPolicy(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			err = &util.ApiError{Code: "404", HttpStatusCode: 200, UserMessage: "approval not configured"}
+			common.WriteJsonResp(w, err, nil, http.StatusOK)
+			return
+		}
+		handler.logger.Errorw("service err, GetPolicy", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdApprovalRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	err = handler.cdApprovalService.DeletePolicy(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePolicy", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler CdApprovalRestHandlerImpl) RaiseRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipeline.CdApprovalRequestDto
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, RaiseRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, RaiseRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionTrigger); !ok {
+		return
+	}
+	handler.logger.Infow("request payload, RaiseRequest", "payload", request)
+	res, err := handler.cdApprovalService.RaiseRequest(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, RaiseRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdApprovalRestHandlerImpl) GetRequests(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	offset := 0
+	size := 20
+	if offsetQueryParam := r.URL.Query().Get("offset"); len(offsetQueryParam) > 0 {
+		offset, err = strconv.Atoi(offsetQueryParam)
+		if err != nil || offset < 0 {
+			common.WriteJsonResp(w, err, "invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+	if sizeQueryParam := r.URL.Query().Get("size"); len(sizeQueryParam) > 0 {
+		size, err = strconv.Atoi(sizeQueryParam)
+		if err != nil || size <= 0 {
+			common.WriteJsonResp(w, err, "invalid size", http.StatusBadRequest)
+			return
+		}
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.cdApprovalService.GetRequests(pipelineId, offset, size)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRequests", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdApprovalRestHandlerImpl) ApproveRequest(w http.ResponseWriter, r *http.Request) {
+	handler.takeAction(w, r, pipelineConfig.CD_APPROVAL_ACTION_APPROVE)
+}
+
+func (handler CdApprovalRestHandlerImpl) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	handler.takeAction(w, r, pipelineConfig.CD_APPROVAL_ACTION_REJECT)
+}
+
+func (handler CdApprovalRestHandlerImpl) takeAction(w http.ResponseWriter, r *http.Request, action pipelineConfig.CdApprovalActionType) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	requestId, err := strconv.Atoi(mux.Vars(r)["requestId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var actionRequest CdApprovalActionRequest
+	if r.ContentLength > 0 {
+		decoder := json.NewDecoder(r.Body)
+		err = decoder.Decode(&actionRequest)
+		if err != nil {
+			handler.logger.Errorw("request err, takeAction", "err", err, "requestId", requestId)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	approvalRequest, err := handler.cdApprovalService.GetRequestById(requestId)
+	if err != nil {
+		handler.logger.Errorw("service err, takeAction", "err", err, "requestId", requestId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// approvers are validated against the policy user group in service, only visibility is checked here
+	if ok := handler.checkPipelineAuth(w, r, approvalRequest.PipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	handler.logger.Infow("request payload, takeAction", "requestId", requestId, "action", action, "userId", userId)
+	res, err := handler.cdApprovalService.TakeAction(&pipeline.CdApprovalActionDto{
+		RequestId: requestId,
+		Action:    action,
+		Comment:   actionRequest.Comment,
+		UserId:    userId,
+	})
+	if err != nil {
+		handler.logger.Errorw("service err, takeAction", "err", err, "requestId", requestId, "action", action)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdApprovalRestHandlerImpl) checkPipelineAuth(w http.ResponseWriter, r *http.Request, pipelineId int, action string) bool {
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	token := r.Header.Get("token")
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, pipelineId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type CdPromotionPolicyRestHandler interface {
+	SavePolicy(w http.ResponseWriter, r *http.Request)
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	GetArtifactEligibility(w http.ResponseWriter, r *http.Request)
+}
+
+type CdPromotionPolicyRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	cdPromotionPolicyService pipeline.CdPromotionPolicyService
+	pipelineRepository       pipelineConfig.PipelineRepository
+	userAuthService          user.UserService
+	validator                *validator.Validate
+	enforcer                 casbin.Enforcer
+	enforcerUtil             rbac.EnforcerUtil
+}
+
+func NewCdPromotionPolicyRestHandlerImpl(logger *zap.SugaredLogger, cdPromotionPolicyService pipeline.CdPromotionPolicyService,
+	pipelineRepository pipelineConfig.PipelineRepository, userAuthService user.UserService, validator *validator.Validate,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil) *CdPromotionPolicyRestHandlerImpl {
+	return &CdPromotionPolicyRestHandlerImpl{
+		logger:                   logger,
+		cdPromotionPolicyService: cdPromotionPolicyService,
+		pipelineRepository:       pipelineRepository,
+		userAuthService:          userAuthService,
+		validator:                validator,
+		enforcer:                 enforcer,
+		enforcerUtil:             enforcerUtil,
+	}
+}
+
+func (handler CdPromotionPolicyRestHandlerImpl) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipeline.CdPromotionPolicyDto
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.PipelineId = pipelineId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	handler.logger.Infow("request payload, SavePolicy", "payload", request)
+	res, err := handler.cdPromotionPolicyService.SavePolicy(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdPromotionPolicyRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.cdPromotionPolicyService.GetPolicy(pipelineId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			err = &util.ApiError{Code: "404", HttpStatusCode: 200, UserMessage: "promotion policy not configured"}
+			common.WriteJsonResp(w, err, nil, http.StatusOK)
+			return
+		}
+		handler.logger.Errorw("service err, GetPolicy", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdPromotionPolicyRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pipelineId, err := strconv.Atoi(mux.Vars(r)["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	err = handler.cdPromotionPolicyService.DeletePolicy(pipelineId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePolicy", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pipelineId, http.StatusOK)
+}
+
+func (handler CdPromotionPolicyRestHandlerImpl) GetArtifactEligibility(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	pipelineId, err := strconv.Atoi(vars["pipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	ciArtifactId, err := strconv.Atoi(vars["artifactId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkPipelineAuth(w, r, pipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	reasons, err := handler.cdPromotionPolicyService.GetUnmetConditions(pipelineId, ciArtifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetArtifactEligibility", "err", err, "pipelineId", pipelineId, "ciArtifactId", ciArtifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res := &pipeline.CdPromotionEligibilityDto{
+		PipelineId:   pipelineId,
+		CiArtifactId: ciArtifactId,
+		Eligible:     len(reasons) == 0,
+		Reasons:      reasons,
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CdPromotionPolicyRestHandlerImpl) checkPipelineAuth(w http.ResponseWriter, r *http.Request, pipelineId int, action string) bool {
+	cdPipeline, err := handler.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", pipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	token := r.Header.Get("token")
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(cdPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(cdPipeline.AppId, pipelineId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
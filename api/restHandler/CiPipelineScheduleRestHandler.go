@@ -0,0 +1,196 @@
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type CiPipelineScheduleRestHandler interface {
+	SaveSchedule(w http.ResponseWriter, r *http.Request)
+	GetSchedules(w http.ResponseWriter, r *http.Request)
+	DeleteSchedule(w http.ResponseWriter, r *http.Request)
+	GetRuns(w http.ResponseWriter, r *http.Request)
+}
+
+type CiPipelineScheduleRestHandlerImpl struct {
+	logger                    *zap.SugaredLogger
+	ciPipelineScheduleService pipeline.CiPipelineScheduleService
+	ciPipelineRepository      pipelineConfig.CiPipelineRepository
+	userAuthService           user.UserService
+	validator                 *validator.Validate
+	enforcer                  casbin.Enforcer
+	enforcerUtil              rbac.EnforcerUtil
+}
+
+func NewCiPipelineScheduleRestHandlerImpl(logger *zap.SugaredLogger, ciPipelineScheduleService pipeline.CiPipelineScheduleService,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository, userAuthService user.UserService, validator *validator.Validate,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil) *CiPipelineScheduleRestHandlerImpl {
+	return &CiPipelineScheduleRestHandlerImpl{
+		logger:                    logger,
+		ciPipelineScheduleService: ciPipelineScheduleService,
+		ciPipelineRepository:      ciPipelineRepository,
+		userAuthService:           userAuthService,
+		validator:                 validator,
+		enforcer:                  enforcer,
+		enforcerUtil:              enforcerUtil,
+	}
+}
+
+func (handler CiPipelineScheduleRestHandlerImpl) SaveSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciPipelineId, err := strconv.Atoi(mux.Vars(r)["ciPipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var request pipeline.CiPipelineScheduleDto
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.CiPipelineId = ciPipelineId
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkCiPipelineAuth(w, r, ciPipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	handler.logger.Infow("request payload, SaveSchedule", "payload", request)
+	res, err := handler.ciPipelineScheduleService.SaveSchedule(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSchedule", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CiPipelineScheduleRestHandlerImpl) GetSchedules(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciPipelineId, err := strconv.Atoi(mux.Vars(r)["ciPipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkCiPipelineAuth(w, r, ciPipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.ciPipelineScheduleService.GetSchedules(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSchedules", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CiPipelineScheduleRestHandlerImpl) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	scheduleId, err := strconv.Atoi(mux.Vars(r)["scheduleId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	schedule, err := handler.ciPipelineScheduleService.GetSchedule(scheduleId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSchedule", "err", err, "scheduleId", scheduleId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkCiPipelineAuth(w, r, schedule.CiPipelineId, casbin.ActionUpdate); !ok {
+		return
+	}
+	err = handler.ciPipelineScheduleService.DeleteSchedule(scheduleId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSchedule", "err", err, "scheduleId", scheduleId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, scheduleId, http.StatusOK)
+}
+
+func (handler CiPipelineScheduleRestHandlerImpl) GetRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	ciPipelineId, err := strconv.Atoi(mux.Vars(r)["ciPipelineId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	offset := 0
+	size := 20
+	if offsetQueryParam := r.URL.Query().Get("offset"); len(offsetQueryParam) > 0 {
+		offset, err = strconv.Atoi(offsetQueryParam)
+		if err != nil || offset < 0 {
+			common.WriteJsonResp(w, err, "invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+	if sizeQueryParam := r.URL.Query().Get("size"); len(sizeQueryParam) > 0 {
+		size, err = strconv.Atoi(sizeQueryParam)
+		if err != nil || size <= 0 {
+			common.WriteJsonResp(w, err, "invalid size", http.StatusBadRequest)
+			return
+		}
+	}
+	if ok := handler.checkCiPipelineAuth(w, r, ciPipelineId, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := handler.ciPipelineScheduleService.GetRuns(ciPipelineId, offset, size)
+	if err != nil {
+		handler.logger.Errorw("service err, GetRuns", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler CiPipelineScheduleRestHandlerImpl) checkCiPipelineAuth(w http.ResponseWriter, r *http.Request, ciPipelineId int, action string) bool {
+	ciPipeline, err := handler.ciPipelineRepository.FindById(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	token := r.Header.Get("token")
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(ciPipeline.AppId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
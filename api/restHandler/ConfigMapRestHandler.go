@@ -23,6 +23,7 @@ import (
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/team"
@@ -66,12 +67,14 @@ type ConfigMapRestHandlerImpl struct {
 	pipelineRepository pipelineConfig.PipelineRepository
 	enforcerUtil       rbac.EnforcerUtil
 	configMapService   pipeline.ConfigMapService
+	auditEventService  auditLog.AuditEventService
 }
 
 func NewConfigMapRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger *zap.SugaredLogger,
 	chartService chart.ChartService, userAuthService user.UserService, teamService team.TeamService,
 	enforcer casbin.Enforcer, pipelineRepository pipelineConfig.PipelineRepository,
-	enforcerUtil rbac.EnforcerUtil, configMapService pipeline.ConfigMapService) *ConfigMapRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, configMapService pipeline.ConfigMapService,
+	auditEventService auditLog.AuditEventService) *ConfigMapRestHandlerImpl {
 	return &ConfigMapRestHandlerImpl{
 		pipelineBuilder:    pipelineBuilder,
 		Logger:             Logger,
@@ -82,6 +85,7 @@ func NewConfigMapRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logge
 		pipelineRepository: pipelineRepository,
 		enforcerUtil:       enforcerUtil,
 		configMapService:   configMapService,
+		auditEventService:  auditEventService,
 	}
 }
 
@@ -112,12 +116,14 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalAddUpdate(w http.ResponseWriter,
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_CONFIG_MAP, configMapRequest.AppId, 0)
 	res, err := handler.configMapService.CMGlobalAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMGlobalAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_CONFIG_MAP, configMapRequest.AppId, 0, getConfigDataName(&configMapRequest), existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -152,12 +158,14 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentAddUpdate(w http.ResponseWr
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_CONFIG_MAP, configMapRequest.AppId, configMapRequest.EnvironmentId)
 	res, err := handler.configMapService.CMEnvironmentAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMEnvironmentAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_CONFIG_MAP, configMapRequest.AppId, configMapRequest.EnvironmentId, getConfigDataName(&configMapRequest), existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -258,12 +266,14 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalAddUpdate(w http.ResponseWriter,
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_SECRET, configMapRequest.AppId, 0)
 	res, err := handler.configMapService.CSGlobalAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSGlobalAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_SECRET, configMapRequest.AppId, 0, getConfigDataName(&configMapRequest), existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -299,12 +309,14 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentAddUpdate(w http.ResponseWr
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_SECRET, configMapRequest.AppId, configMapRequest.EnvironmentId)
 	res, err := handler.configMapService.CSEnvironmentAddUpdate(&configMapRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSEnvironmentAddUpdate", "err", err, "payload", configMapRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_SECRET, configMapRequest.AppId, configMapRequest.EnvironmentId, getConfigDataName(&configMapRequest), existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -409,12 +421,14 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalDelete(w http.ResponseWriter, r
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_CONFIG_MAP, appId, 0)
 	res, err := handler.configMapService.CMGlobalDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMGlobalDelete", "err", err, "appId", appId, "id", id, "name", name)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_CONFIG_MAP, appId, 0, name, existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -460,12 +474,14 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentDelete(w http.ResponseWrite
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_CONFIG_MAP, appId, envId)
 	res, err := handler.configMapService.CMEnvironmentDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CMEnvironmentDelete", "err", err, "appId", appId, "envId", envId, "id", id)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_CONFIG_MAP, appId, envId, name, existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -500,12 +516,14 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalDelete(w http.ResponseWriter, r
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_SECRET, appId, 0)
 	res, err := handler.configMapService.CSGlobalDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSGlobalDelete", "err", err, "appId", appId, "id", id, "name", name)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_SECRET, appId, 0, name, existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -551,12 +569,14 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentDelete(w http.ResponseWrite
 	}
 	//RBAC END
 
+	existingConfigData := handler.fetchConfigData(auditLog.RESOURCE_TYPE_SECRET, appId, envId)
 	res, err := handler.configMapService.CSEnvironmentDelete(name, id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, CSEnvironmentDelete", "err", err, "appId", appId, "envId", envId, "id", id)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditConfigData(r, auditLog.RESOURCE_TYPE_SECRET, appId, envId, name, existingConfigData)
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
@@ -703,3 +723,66 @@ func (handler ConfigMapRestHandlerImpl) ConfigSecretBulkPatch(w http.ResponseWri
 	}
 	common.WriteJsonResp(w, err, true, http.StatusOK)
 }
+
+// fetchConfigData returns the config maps or secrets of app, at environment level when envId is set, secret values
+// are masked by the fetch
+func (handler ConfigMapRestHandlerImpl) fetchConfigData(resourceType string, appId int, envId int) *pipeline.ConfigDataRequest {
+	var configData *pipeline.ConfigDataRequest
+	var err error
+	if resourceType == auditLog.RESOURCE_TYPE_SECRET {
+		if envId > 0 {
+			configData, err = handler.configMapService.CSEnvironmentFetch(appId, envId)
+		} else {
+			configData, err = handler.configMapService.CSGlobalFetch(appId)
+		}
+	} else {
+		if envId > 0 {
+			configData, err = handler.configMapService.CMEnvironmentFetch(appId, envId)
+		} else {
+			configData, err = handler.configMapService.CMGlobalFetch(appId)
+		}
+	}
+	if err != nil {
+		handler.Logger.Errorw("error in fetching config data for audit", "err", err, "resourceType", resourceType, "appId", appId, "envId", envId)
+		return nil
+	}
+	return configData
+}
+
+// auditConfigData records the change made to config map or secret with name, states are the config data of app or
+// environment holding it
+func (handler ConfigMapRestHandlerImpl) auditConfigData(r *http.Request, resourceType string, appId int, envId int, name string,
+	existingConfigData *pipeline.ConfigDataRequest) {
+	auditEvent := &auditLog.AuditEventRequest{
+		ResourceType: resourceType,
+		ResourceName: name,
+		AppId:        appId,
+		EnvId:        envId,
+		Action:       auditLog.ACTION_CREATE,
+	}
+	if existingConfigData != nil {
+		auditEvent.ResourceId = existingConfigData.Id
+		auditEvent.Before = existingConfigData
+		for _, item := range existingConfigData.ConfigData {
+			if item.Name == name {
+				auditEvent.Action = auditLog.ACTION_UPDATE
+			}
+		}
+	}
+	configData := handler.fetchConfigData(resourceType, appId, envId)
+	if configData != nil {
+		auditEvent.ResourceId = configData.Id
+		auditEvent.After = configData
+	}
+	if r.Method == http.MethodDelete {
+		auditEvent.Action = auditLog.ACTION_DELETE
+	}
+	handler.auditEventService.Record(r, auditEvent)
+}
+
+func getConfigDataName(configDataRequest *pipeline.ConfigDataRequest) string {
+	if len(configDataRequest.ConfigData) == 0 {
+		return ""
+	}
+	return configDataRequest.ConfigData[0].Name
+}
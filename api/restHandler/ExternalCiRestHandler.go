@@ -22,6 +22,7 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/pubsub"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/security"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -36,13 +37,16 @@ type ExternalCiRestHandlerImpl struct {
 	logger         *zap.SugaredLogger
 	webhookService pipeline.WebhookService
 	ciEventHandler pubsub.CiEventHandler
+	sbomService    security.SbomService
 }
 
-func NewExternalCiRestHandlerImpl(logger *zap.SugaredLogger, webhookService pipeline.WebhookService, ciEventHandler pubsub.CiEventHandler) *ExternalCiRestHandlerImpl {
+func NewExternalCiRestHandlerImpl(logger *zap.SugaredLogger, webhookService pipeline.WebhookService, ciEventHandler pubsub.CiEventHandler,
+	sbomService security.SbomService) *ExternalCiRestHandlerImpl {
 	return &ExternalCiRestHandlerImpl{
 		webhookService: webhookService,
 		logger:         logger,
 		ciEventHandler: ciEventHandler,
+		sbomService:    sbomService,
 	}
 }
 
@@ -80,12 +84,18 @@ func (impl ExternalCiRestHandlerImpl) HandleExternalCiWebhook(w http.ResponseWri
 		return
 	}
 
-	_, err = impl.webhookService.SaveCiArtifactWebhook(ciPipelineId, ciArtifactReq)
+	ciArtifactId, err := impl.webhookService.SaveCiArtifactWebhook(ciPipelineId, ciArtifactReq)
 	if err != nil {
 		impl.logger.Errorw("service err, HandleExternalCiWebhook", "err", err, "payload", req)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	go func() {
+		err := impl.sbomService.GenerateSbom(ciArtifactId, req.TriggeredBy)
+		if err != nil {
+			impl.logger.Errorw("error in generating sbom of ci artifact", "err", err, "ciArtifactId", ciArtifactId)
+		}
+	}()
 
 	common.WriteJsonResp(w, err, nil, http.StatusOK)
 }
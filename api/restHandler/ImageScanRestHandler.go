@@ -19,6 +19,7 @@ package restHandler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	security2 "github.com/devtron-labs/devtron/internal/sql/repository/security"
@@ -28,6 +29,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
@@ -38,6 +40,11 @@ type ImageScanRestHandler interface {
 	FetchExecutionDetail(w http.ResponseWriter, r *http.Request)
 	FetchMinScanResultByAppIdAndEnvId(w http.ResponseWriter, r *http.Request)
 	VulnerabilityExposure(w http.ResponseWriter, r *http.Request)
+	SaveSbom(w http.ResponseWriter, r *http.Request)
+	GenerateSbom(w http.ResponseWriter, r *http.Request)
+	FetchSboms(w http.ResponseWriter, r *http.Request)
+	DownloadSbom(w http.ResponseWriter, r *http.Request)
+	SearchSbomComponents(w http.ResponseWriter, r *http.Request)
 }
 
 type ImageScanRestHandlerImpl struct {
@@ -47,11 +54,13 @@ type ImageScanRestHandlerImpl struct {
 	enforcer           casbin.Enforcer
 	enforcerUtil       rbac.EnforcerUtil
 	environmentService cluster.EnvironmentService
+	sbomService        security.SbomService
 }
 
 func NewImageScanRestHandlerImpl(logger *zap.SugaredLogger,
 	imageScanService security.ImageScanService, userService user.UserService, enforcer casbin.Enforcer,
-	enforcerUtil rbac.EnforcerUtil, environmentService cluster.EnvironmentService) *ImageScanRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, environmentService cluster.EnvironmentService,
+	sbomService security.SbomService) *ImageScanRestHandlerImpl {
 	return &ImageScanRestHandlerImpl{
 		logger:             logger,
 		imageScanService:   imageScanService,
@@ -59,6 +68,7 @@ func NewImageScanRestHandlerImpl(logger *zap.SugaredLogger,
 		enforcer:           enforcer,
 		enforcerUtil:       enforcerUtil,
 		environmentService: environmentService,
+		sbomService:        sbomService,
 	}
 }
 
@@ -326,3 +336,201 @@ func (impl ImageScanRestHandlerImpl) VulnerabilityExposure(w http.ResponseWriter
 	results.VulnerabilityExposure = vulnerabilityExposure
 	common.WriteJsonResp(w, err, results, http.StatusOK)
 }
+
+func (impl ImageScanRestHandlerImpl) SaveSbom(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request security.SbomRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveSbom", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if request.CiArtifactId == 0 || len(request.Format) == 0 || len(request.Document) == 0 {
+		common.WriteJsonResp(w, errors.New("ciArtifactId, format and document are required"), nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC
+	if ok := impl.checkArtifactRbac(w, r, request.CiArtifactId, casbin.ActionTrigger); !ok {
+		return
+	}
+	//RBAC
+	res, err := impl.sbomService.SaveSbom(&request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveSbom", "err", err, "ciArtifactId", request.CiArtifactId, "format", request.Format)
+		if apiErr, ok := err.(*util.ApiError); ok {
+			common.WriteJsonResp(w, err, nil, apiErr.HttpStatusCode)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ImageScanRestHandlerImpl) GenerateSbom(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	artifactId, err := strconv.Atoi(mux.Vars(r)["artifactId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC
+	if ok := impl.checkArtifactRbac(w, r, artifactId, casbin.ActionTrigger); !ok {
+		return
+	}
+	//RBAC
+	if !impl.sbomService.IsSbomGenerationEnabled() {
+		common.WriteJsonResp(w, errors.New("sbom generation is not enabled, configure SBOM_FORMATS"), nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.sbomService.GenerateSbom(artifactId, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, GenerateSbom", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	res, err := impl.sbomService.FetchSboms(artifactId)
+	if err != nil {
+		impl.logger.Errorw("service err, GenerateSbom", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ImageScanRestHandlerImpl) FetchSboms(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	artifactId, err := strconv.Atoi(mux.Vars(r)["artifactId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC
+	if ok := impl.checkArtifactRbac(w, r, artifactId, casbin.ActionGet); !ok {
+		return
+	}
+	//RBAC
+	res, err := impl.sbomService.FetchSboms(artifactId)
+	if err != nil {
+		impl.logger.Errorw("service err, FetchSboms", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// DownloadSbom writes the sbom document of the artifact as a json file, format is spdx or cyclonedx
+func (impl ImageScanRestHandlerImpl) DownloadSbom(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	artifactId, err := strconv.Atoi(mux.Vars(r)["artifactId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if len(format) == 0 {
+		format = security2.SbomFormat_SPDX
+	}
+	//RBAC
+	if ok := impl.checkArtifactRbac(w, r, artifactId, casbin.ActionGet); !ok {
+		return
+	}
+	//RBAC
+	sbom, err := impl.sbomService.FetchSbomDocument(artifactId, format)
+	if err != nil {
+		impl.logger.Errorw("service err, DownloadSbom", "err", err, "artifactId", artifactId, "format", format)
+		if util.IsErrNoRows(err) {
+			err = &util.ApiError{Code: "404", HttpStatusCode: http.StatusNotFound, UserMessage: "no sbom found for artifact in format " + format}
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%d.%s.json", artifactId, sbom.Format))
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write([]byte(sbom.Document))
+	if err != nil {
+		impl.logger.Errorw("error in writing sbom, DownloadSbom", "err", err, "artifactId", artifactId)
+	}
+}
+
+// SearchSbomComponents finds the deployed images containing a component, like log4j-core of version 2.14
+func (impl ImageScanRestHandlerImpl) SearchSbomComponents(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request security2.SbomComponentSearchRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, SearchSbomComponents", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if len(request.Name) == 0 {
+		common.WriteJsonResp(w, errors.New("component name is required"), nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC
+	token := r.Header.Get("token")
+	isVisible := func(item *security2.SbomComponentExposure) bool {
+		if item.AppId > 0 {
+			object := impl.enforcerUtil.GetAppRBACNameByAppId(item.AppId)
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+				return false
+			}
+			object = impl.enforcerUtil.GetEnvRBACNameByAppId(item.AppId, item.EnvId)
+			return impl.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, object)
+		}
+		// images of pods not deployed through devtron are visible to super admins only
+		return impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*")
+	}
+	//RBAC
+	exposures, err := impl.sbomService.SearchComponents(&request, isVisible)
+	if err != nil {
+		impl.logger.Errorw("service err, SearchSbomComponents", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, exposures, http.StatusOK)
+}
+
+func (impl ImageScanRestHandlerImpl) checkArtifactRbac(w http.ResponseWriter, r *http.Request, artifactId int, action string) bool {
+	appId, err := impl.sbomService.GetAppIdByCiArtifactId(artifactId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return false
+	}
+	token := r.Header.Get("token")
+	object := impl.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -23,6 +23,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	"io/ioutil"
 	"net/http"
@@ -45,9 +47,10 @@ import (
 )
 
 const (
-	SLACK_CONFIG_DELETE_SUCCESS_RESP = "Slack config deleted successfully."
-	SES_CONFIG_DELETE_SUCCESS_RESP   = "SES config deleted successfully."
-	SMTP_CONFIG_DELETE_SUCCESS_RESP  = "SMTP config deleted successfully."
+	SLACK_CONFIG_DELETE_SUCCESS_RESP   = "Slack config deleted successfully."
+	SES_CONFIG_DELETE_SUCCESS_RESP     = "SES config deleted successfully."
+	SMTP_CONFIG_DELETE_SUCCESS_RESP    = "SMTP config deleted successfully."
+	CHANNEL_CONFIG_DELETE_SUCCESS_RESP = "%s config deleted successfully."
 )
 
 type NotificationRestHandler interface {
@@ -58,6 +61,7 @@ type NotificationRestHandler interface {
 	FindSESConfig(w http.ResponseWriter, r *http.Request)
 	FindSlackConfig(w http.ResponseWriter, r *http.Request)
 	FindSMTPConfig(w http.ResponseWriter, r *http.Request)
+	FindNotificationChannelConfig(w http.ResponseWriter, r *http.Request)
 	FindAllNotificationConfig(w http.ResponseWriter, r *http.Request)
 	GetAllNotificationSettings(w http.ResponseWriter, r *http.Request)
 	DeleteNotificationSettings(w http.ResponseWriter, r *http.Request)
@@ -66,6 +70,12 @@ type NotificationRestHandler interface {
 	RecipientListingSuggestion(w http.ResponseWriter, r *http.Request)
 	FindAllNotificationConfigAutocomplete(w http.ResponseWriter, r *http.Request)
 	GetOptionsForNotificationSettings(w http.ResponseWriter, r *http.Request)
+
+	SaveNotificationTemplate(w http.ResponseWriter, r *http.Request)
+	FindAllNotificationTemplates(w http.ResponseWriter, r *http.Request)
+	FindNotificationTemplate(w http.ResponseWriter, r *http.Request)
+	DeleteNotificationTemplate(w http.ResponseWriter, r *http.Request)
+	PreviewNotificationTemplate(w http.ResponseWriter, r *http.Request)
 }
 type NotificationRestHandlerImpl struct {
 	dockerRegistryConfig pipeline.DockerRegistryConfig
@@ -83,6 +93,10 @@ type NotificationRestHandlerImpl struct {
 	environmentService   cluster.EnvironmentService
 	pipelineBuilder      pipeline.PipelineBuilder
 	enforcerUtil         rbac.EnforcerUtil
+	channelRegistry      notifier.NotificationChannelRegistry
+	templateService      notifier.NotificationTemplateService
+	eventFactory         client.EventFactory
+	auditEventService    auditLog.AuditEventService
 }
 
 type ChannelDto struct {
@@ -95,7 +109,9 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 	validator *validator.Validate, notificationService notifier.NotificationConfigService,
 	slackService notifier.SlackNotificationService, sesService notifier.SESNotificationService, smtpService notifier.SMTPNotificationService,
 	enforcer casbin.Enforcer, teamService team.TeamService, environmentService cluster.EnvironmentService, pipelineBuilder pipeline.PipelineBuilder,
-	enforcerUtil rbac.EnforcerUtil) *NotificationRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, channelRegistry notifier.NotificationChannelRegistry,
+	templateService notifier.NotificationTemplateService, eventFactory client.EventFactory,
+	auditEventService auditLog.AuditEventService) *NotificationRestHandlerImpl {
 	return &NotificationRestHandlerImpl{
 		dockerRegistryConfig: dockerRegistryConfig,
 		logger:               logger,
@@ -112,6 +128,10 @@ func NewNotificationRestHandlerImpl(dockerRegistryConfig pipeline.DockerRegistry
 		environmentService:   environmentService,
 		pipelineBuilder:      pipelineBuilder,
 		enforcerUtil:         enforcerUtil,
+		channelRegistry:      channelRegistry,
+		templateService:      templateService,
+		eventFactory:         eventFactory,
+		auditEventService:    auditEventService,
 	}
 }
 
@@ -161,6 +181,12 @@ func (impl NotificationRestHandlerImpl) SaveNotificationSettings(w http.Response
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	impl.auditEventService.Record(r, &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_NOTIFICATION_SETTING,
+		ResourceId:   res,
+		Action:       auditLog.ACTION_CREATE,
+		After:        notificationSetting.NotificationConfigRequest,
+	})
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, nil, res, http.StatusOK)
 }
@@ -313,6 +339,17 @@ func (impl NotificationRestHandlerImpl) UpdateNotificationSettings(w http.Respon
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	updatedNsViews, err := impl.notificationService.FetchNSViewByIds(ids)
+	if err != nil {
+		impl.logger.Errorw("error in fetching updated notification settings for audit", "err", err, "ids", ids)
+	}
+	impl.auditEventService.Record(r, &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_NOTIFICATION_SETTING,
+		ResourceId:   getNotificationSettingId(ids),
+		Action:       auditLog.ACTION_UPDATE,
+		Before:       nsViews,
+		After:        updatedNsViews,
+	})
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, nil, res, http.StatusOK)
 }
@@ -353,10 +390,25 @@ func (impl NotificationRestHandlerImpl) DeleteNotificationSettings(w http.Respon
 	err = impl.notificationService.DeleteNotificationSettings(request)
 	if err != nil {
 		impl.logger.Errorw("service err, DeleteNotificationSettings", "err", err, "payload", request)
+	} else {
+		impl.auditEventService.Record(r, &auditLog.AuditEventRequest{
+			ResourceType: auditLog.RESOURCE_TYPE_NOTIFICATION_SETTING,
+			ResourceId:   getNotificationSettingId(request.Id),
+			Action:       auditLog.ACTION_DELETE,
+			Before:       nsViews,
+		})
 	}
 	common.WriteJsonResp(w, err, nil, http.StatusOK)
 }
 
+// getNotificationSettingId returns the id of the only setting changed by request, it is 0 for a bulk change
+func getNotificationSettingId(ids []*int) int {
+	if len(ids) == 1 && ids[0] != nil {
+		return *ids[0]
+	}
+	return 0
+}
+
 func (impl NotificationRestHandlerImpl) GetAllNotificationSettings(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	size, err := strconv.Atoi(vars["size"])
@@ -551,13 +603,49 @@ func (impl NotificationRestHandlerImpl) SaveNotificationChannelConfig(w http.Res
 		}
 		w.Header().Set("Content-Type", "application/json")
 		common.WriteJsonResp(w, nil, res, http.StatusOK)
+	} else if channelService, ok := impl.channelRegistry.GetChannelService(channelReq.Channel); ok {
+		var channelConfigReq *notifier.NotificationChannelRequest
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&channelConfigReq)
+		if err != nil {
+			impl.logger.Errorw("request err, SaveNotificationChannelConfig", "err", err, "channelConfigReq", channelConfigReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		err = impl.validator.Struct(channelConfigReq)
+		if err != nil {
+			impl.logger.Errorw("validation err, SaveNotificationChannelConfig", "err", err, "channel", channelReq.Channel)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		// RBAC enforcer applying
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+		//RBAC enforcer Ends
+
+		res, cErr := channelService.SaveOrEditNotificationConfig(channelConfigReq.Configs, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, SaveNotificationChannelConfig", "err", cErr, "channel", channelReq.Channel)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		common.WriteJsonResp(w, nil, res, http.StatusOK)
+	} else {
+		common.WriteJsonResp(w, fmt.Errorf(" The channel you requested is not supported"), nil, http.StatusBadRequest)
 	}
 }
 
 type ChannelResponseDTO struct {
-	SlackConfigs []*notifier.SlackConfigDto `json:"slackConfigs"`
-	SESConfigs   []*notifier.SESConfigDto   `json:"sesConfigs"`
-	SMTPConfigs  []*notifier.SMTPConfigDto  `json:"smtpConfigs"`
+	SlackConfigs     []*notifier.SlackConfigDto               `json:"slackConfigs"`
+	SESConfigs       []*notifier.SESConfigDto                 `json:"sesConfigs"`
+	SMTPConfigs      []*notifier.SMTPConfigDto                `json:"smtpConfigs"`
+	TeamsConfigs     []*notifier.NotificationChannelConfigDto `json:"teamsConfigs"`
+	WebhookConfigs   []*notifier.NotificationChannelConfigDto `json:"webhookConfigs"`
+	PagerDutyConfigs []*notifier.NotificationChannelConfigDto `json:"pagerDutyConfigs"`
 }
 
 func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.ResponseWriter, r *http.Request) {
@@ -631,6 +719,25 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfig(w http.Respons
 	if pass {
 		channelsResponse.SMTPConfigs = smtpConfigs
 	}
+	for _, channelService := range impl.channelRegistry.GetChannelServices() {
+		channelConfigs, err := channelService.FetchAllNotificationConfig()
+		if err != nil {
+			impl.logger.Errorw("service err, FindAllNotificationConfig", "err", err, "channel", channelService.GetChannel())
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		if !pass {
+			continue
+		}
+		switch channelService.GetChannel() {
+		case util.Teams:
+			channelsResponse.TeamsConfigs = channelConfigs
+		case util.Webhook:
+			channelsResponse.WebhookConfigs = channelConfigs
+		case util.PagerDuty:
+			channelsResponse.PagerDutyConfigs = channelConfigs
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	common.WriteJsonResp(w, fErr, channelsResponse, http.StatusOK)
 }
@@ -717,6 +824,44 @@ func (impl NotificationRestHandlerImpl) FindSMTPConfig(w http.ResponseWriter, r
 	common.WriteJsonResp(w, fErr, smtpConfig, http.StatusOK)
 }
 
+func (impl NotificationRestHandlerImpl) FindNotificationChannelConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, FindNotificationChannelConfig", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	channelService, ok := impl.channelRegistry.GetChannelService(util.Channel(vars["channel"]))
+	if !ok {
+		common.WriteJsonResp(w, fmt.Errorf(" The channel you requested is not supported"), nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionGet, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+
+	channelConfig, err := channelService.FetchNotificationConfigById(id)
+	if err != nil {
+		impl.logger.Errorw("service err, FindNotificationChannelConfig", "err", err, "id", id)
+		if err == pg.ErrNoRows {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	common.WriteJsonResp(w, nil, channelConfig, http.StatusOK)
+}
+
 func (impl NotificationRestHandlerImpl) RecipientListingSuggestion(w http.ResponseWriter, r *http.Request) {
 	userId, err := impl.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -799,6 +944,16 @@ func (impl NotificationRestHandlerImpl) FindAllNotificationConfigAutocomplete(w
 			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 			return
 		}
+	} else if channelService, ok := impl.channelRegistry.GetChannelService(util.Channel(cType)); ok {
+		channelConfigs, err := channelService.FetchAllNotificationConfig()
+		if err != nil {
+			impl.logger.Errorw("service err, FindAllNotificationConfigAutocomplete", "err", err, "channel", cType)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		for _, item := range channelConfigs {
+			channelsResponse = append(channelsResponse, &notifier.NotificationChannelAutoResponse{Id: item.Id, ConfigName: item.ConfigName})
+		}
 	}
 	if channelsResponse == nil {
 		channelsResponse = make([]*notifier.NotificationChannelAutoResponse, 0)
@@ -985,7 +1140,209 @@ func (impl NotificationRestHandlerImpl) DeleteNotificationChannelConfig(w http.R
 			return
 		}
 		common.WriteJsonResp(w, nil, SMTP_CONFIG_DELETE_SUCCESS_RESP, http.StatusOK)
+	} else if channelService, ok := impl.channelRegistry.GetChannelService(channelReq.Channel); ok {
+		var deleteReq *notifier.NotificationChannelDeleteRequest
+		err = json.NewDecoder(ioutil.NopCloser(bytes.NewBuffer(data))).Decode(&deleteReq)
+		if err != nil {
+			impl.logger.Errorw("request err, DeleteNotificationChannelConfig", "err", err, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		err = impl.validator.Struct(deleteReq)
+		if err != nil {
+			impl.logger.Errorw("validation err, DeleteNotificationChannelConfig", "err", err, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+
+		// RBAC enforcer applying
+		token := r.Header.Get("token")
+		if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+		//RBAC enforcer Ends
+
+		cErr := channelService.DeleteNotificationConfig(deleteReq.Id, userId)
+		if cErr != nil {
+			impl.logger.Errorw("service err, DeleteNotificationChannelConfig", "err", cErr, "deleteReq", deleteReq)
+			common.WriteJsonResp(w, cErr, nil, http.StatusInternalServerError)
+			return
+		}
+		common.WriteJsonResp(w, nil, fmt.Sprintf(CHANNEL_CONFIG_DELETE_SUCCESS_RESP, channelReq.Channel), http.StatusOK)
 	} else {
 		common.WriteJsonResp(w, fmt.Errorf(" The channel you requested is not supported"), nil, http.StatusBadRequest)
 	}
 }
+
+func (impl NotificationRestHandlerImpl) SaveNotificationTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request notifier.NotificationTemplateDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveNotificationTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, SaveNotificationTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	res, err := impl.templateService.SaveOrUpdateTemplate(&request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveNotificationTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl NotificationRestHandlerImpl) FindAllNotificationTemplates(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionGet, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	res, err := impl.templateService.FetchAllTemplates()
+	if err != nil {
+		impl.logger.Errorw("service err, FindAllNotificationTemplates", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl NotificationRestHandlerImpl) FindNotificationTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, FindNotificationTemplate", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionGet, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	res, err := impl.templateService.FetchTemplateById(id)
+	if err != nil {
+		impl.logger.Errorw("service err, FindNotificationTemplate", "err", err, "id", id)
+		if err == pg.ErrNoRows {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl NotificationRestHandlerImpl) DeleteNotificationTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		impl.logger.Errorw("request err, DeleteNotificationTemplate", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionCreate, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	err = impl.templateService.DeleteTemplate(id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteNotificationTemplate", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "Notification template deleted successfully.", http.StatusOK)
+}
+
+// PreviewNotificationTemplate renders a saved or unsaved template against the event of a past workflow, rendering
+// errors are returned in the response as the default message is sent for them
+func (impl NotificationRestHandlerImpl) PreviewNotificationTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request notifier.NotificationTemplatePreviewRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, PreviewNotificationTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, PreviewNotificationTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceNotification, casbin.ActionGet, "*"); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	notificationTemplate := request.Template
+	if request.TemplateId > 0 {
+		savedTemplate, err := impl.templateService.FetchTemplateById(request.TemplateId)
+		if err != nil {
+			impl.logger.Errorw("service err, PreviewNotificationTemplate", "err", err, "templateId", request.TemplateId)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		notificationTemplate = savedTemplate.Template
+	}
+	if len(notificationTemplate) == 0 {
+		common.WriteJsonResp(w, fmt.Errorf("template or templateId is required"), nil, http.StatusBadRequest)
+		return
+	}
+	event, err := impl.eventFactory.BuildEventForWorkflow(util.EventType(request.EventTypeId), request.PipelineType, request.WorkflowId)
+	if err != nil {
+		impl.logger.Errorw("service err, PreviewNotificationTemplate", "err", err, "payload", request)
+		if err == pg.ErrNoRows {
+			common.WriteJsonResp(w, fmt.Errorf("workflow not found"), nil, http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, impl.enforcerUtil.GetAppRBACNameByAppId(event.AppId)); !ok {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return
+	}
+	res := &notifier.NotificationTemplatePreviewResponse{}
+	res.Message, err = impl.templateService.RenderTemplate(notificationTemplate, event)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
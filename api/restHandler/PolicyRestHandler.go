@@ -32,6 +32,7 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type PolicyRestHandler interface {
@@ -39,6 +40,9 @@ type PolicyRestHandler interface {
 	UpdatePolicy(w http.ResponseWriter, r *http.Request)
 	GetPolicy(w http.ResponseWriter, r *http.Request)
 	VerifyImage(w http.ResponseWriter, r *http.Request)
+	GetExpiringExceptions(w http.ResponseWriter, r *http.Request)
+	GetPendingExceptions(w http.ResponseWriter, r *http.Request)
+	ReviewException(w http.ResponseWriter, r *http.Request)
 }
 type PolicyRestHandlerImpl struct {
 	logger             *zap.SugaredLogger
@@ -298,6 +302,127 @@ func (impl PolicyRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Reque
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
+// GetExpiringExceptions reports the cve exceptions expiring in given days(default 30) which the user can view
+func (impl PolicyRestHandlerImpl) GetExpiringExceptions(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	days := 30
+	if daysParam := r.URL.Query().Get("days"); len(daysParam) > 0 {
+		days, err = strconv.Atoi(daysParam)
+		if err != nil || days <= 0 {
+			impl.logger.Errorw("request err, GetExpiringExceptions", "err", err, "days", daysParam)
+			common.WriteJsonResp(w, fmt.Errorf("invalid days %s", daysParam), nil, http.StatusBadRequest)
+			return
+		}
+	}
+	exceptions, err := impl.policyService.GetExpiringExceptions(time.Now().AddDate(0, 0, days))
+	if err != nil {
+		impl.logger.Errorw("service err, GetExpiringExceptions", "err", err, "days", days)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	result := make([]*bean.CveExceptionDetail, 0)
+	for _, exception := range exceptions {
+		//AUTH - check from casbin db
+		if exception.AppId > 0 && exception.EnvId > 0 {
+			object := impl.enforcerUtil.GetAppRBACNameByAppId(exception.AppId)
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+				continue
+			}
+			object = impl.enforcerUtil.GetEnvRBACNameByAppId(exception.AppId, exception.EnvId)
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, object); !ok {
+				continue
+			}
+		} else if exception.EnvId > 0 {
+			environment, err := impl.environmentService.FindById(exception.EnvId)
+			if err != nil {
+				common.WriteJsonResp(w, err, "Failed to get environment by id", http.StatusInternalServerError)
+				return
+			}
+			if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, casbin.ActionGet, environment.EnvironmentIdentifier); !ok {
+				continue
+			}
+		}
+		//AUTH
+		result = append(result, exception)
+	}
+	common.WriteJsonResp(w, nil, result, http.StatusOK)
+}
+
+// GetPendingExceptions lists the exceptions waiting for approval, exceptions are reviewed by super admins only
+func (impl PolicyRestHandlerImpl) GetPendingExceptions(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	//AUTH - check from casbin db
+	if ok, err := impl.isSuperAdmin(userId); err != nil || !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
+		return
+	}
+	//AUTH
+	exceptions, err := impl.policyService.GetPendingExceptions()
+	if err != nil {
+		impl.logger.Errorw("service err, GetPendingExceptions", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if exceptions == nil {
+		exceptions = make([]*bean.CveExceptionDetail, 0)
+	}
+	common.WriteJsonResp(w, nil, exceptions, http.StatusOK)
+}
+
+// ReviewException approves or rejects a pending exception, exceptions are reviewed by super admins only
+func (impl PolicyRestHandlerImpl) ReviewException(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var req bean.ReviewCveExceptionRequest
+	err = decoder.Decode(&req)
+	if err != nil || req.Id == 0 {
+		impl.logger.Errorw("request err, ReviewException", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	impl.logger.Infow("request payload, ReviewException", "payload", req)
+	//AUTH - check from casbin db
+	if ok, err := impl.isSuperAdmin(userId); err != nil || !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
+		return
+	}
+	//AUTH
+	res, err := impl.policyService.ReviewException(&req, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, ReviewException", "err", err, "payload", req)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl PolicyRestHandlerImpl) isSuperAdmin(userId int32) (bool, error) {
+	roles, err := impl.userService.CheckUserRoles(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user roles", "err", err, "userId", userId)
+		return false, err
+	}
+	for _, item := range roles {
+		if item == bean.SUPERADMIN {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 //TODO - move to image-scanner
 func (impl PolicyRestHandlerImpl) VerifyImage(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
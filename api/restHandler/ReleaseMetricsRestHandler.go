@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/client/lens"
+	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/team"
@@ -31,12 +32,14 @@ import (
 	"github.com/gorilla/schema"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
 type ReleaseMetricsRestHandler interface {
 	ResetDataForAppEnvironment(w http.ResponseWriter, r *http.Request)
 	ResetDataForAllAppEnvironment(w http.ResponseWriter, r *http.Request)
 	GetDeploymentMetrics(w http.ResponseWriter, r *http.Request)
+	GetDoraMetrics(w http.ResponseWriter, r *http.Request)
 }
 
 type ReleaseMetricsRestHandlerImpl struct {
@@ -47,6 +50,8 @@ type ReleaseMetricsRestHandlerImpl struct {
 	teamService        team.TeamService
 	pipelineRepository pipelineConfig.PipelineRepository
 	enforcerUtil       rbac.EnforcerUtil
+	doraMetricsService app.DoraMetricsService
+	appRepository      app2.AppRepository
 }
 
 func NewReleaseMetricsRestHandlerImpl(
@@ -55,7 +60,8 @@ func NewReleaseMetricsRestHandlerImpl(
 	ReleaseDataService app.ReleaseDataService,
 	userAuthService user.UserService,
 	teamService team.TeamService,
-	pipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil) *ReleaseMetricsRestHandlerImpl {
+	pipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil,
+	doraMetricsService app.DoraMetricsService, appRepository app2.AppRepository) *ReleaseMetricsRestHandlerImpl {
 	return &ReleaseMetricsRestHandlerImpl{
 		logger:             logger,
 		enforcer:           enforcer,
@@ -64,6 +70,8 @@ func NewReleaseMetricsRestHandlerImpl(
 		teamService:        teamService,
 		pipelineRepository: pipelineRepository,
 		enforcerUtil:       enforcerUtil,
+		doraMetricsService: doraMetricsService,
+		appRepository:      appRepository,
 	}
 }
 
@@ -183,3 +191,91 @@ func (impl *ReleaseMetricsRestHandlerImpl) GetDeploymentMetrics(w http.ResponseW
 		impl.logger.Errorw("service err, GetDeploymentMetrics", "err", err, "resCode", resCode)
 	}
 }
+
+type DoraMetricsQuery struct {
+	AppId  int    `schema:"appId"`
+	EnvId  int    `schema:"envId"`
+	TeamId int    `schema:"teamId"`
+	From   string `schema:"from"`
+	To     string `schema:"to"`
+}
+
+func parseDoraMetricsTime(value string, defaultTime time.Time) (time.Time, error) {
+	if len(value) == 0 {
+		return defaultTime, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", value)
+	}
+	return t, err
+}
+
+func (impl *ReleaseMetricsRestHandlerImpl) GetDoraMetrics(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	query := &DoraMetricsQuery{}
+	err = decoder.Decode(query, r.URL.Query())
+	if err != nil {
+		impl.logger.Errorw("request err, GetDoraMetrics", "err", err, "payload", query)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	to, err := parseDoraMetricsTime(query.To, time.Now())
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid to", http.StatusBadRequest)
+		return
+	}
+	from, err := parseDoraMetricsTime(query.From, to.AddDate(0, 0, -30))
+	if err != nil || !from.Before(to) {
+		common.WriteJsonResp(w, fmt.Errorf("invalid range"), "from must be a time before to", http.StatusBadRequest)
+		return
+	}
+	var appIds []int
+	if query.AppId > 0 {
+		appIds = append(appIds, query.AppId)
+	} else if query.TeamId > 0 {
+		apps, err := impl.appRepository.FindAppsByTeamId(query.TeamId)
+		if err != nil {
+			impl.logger.Errorw("service err, GetDoraMetrics", "err", err, "teamId", query.TeamId)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		for _, teamApp := range apps {
+			appIds = append(appIds, teamApp.Id)
+		}
+	} else {
+		common.WriteJsonResp(w, fmt.Errorf("appId or teamId is required"), nil, http.StatusBadRequest)
+		return
+	}
+	//RBAC, metrics of team are computed only for the apps the user has access to
+	token := r.Header.Get("token")
+	var authorizedAppIds []int
+	for _, appId := range appIds {
+		appRbacObject := impl.enforcerUtil.GetAppRBACNameByAppId(appId)
+		if appRbacObject != "" && impl.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, appRbacObject) {
+			authorizedAppIds = append(authorizedAppIds, appId)
+		}
+	}
+	if query.AppId > 0 && len(authorizedAppIds) == 0 {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC end
+	request := &app.DoraMetricsRequest{
+		AppIds:        authorizedAppIds,
+		EnvironmentId: query.EnvId,
+		From:          from,
+		To:            to,
+	}
+	res, err := impl.doraMetricsService.GetDoraMetrics(request)
+	if err != nil {
+		impl.logger.Errorw("service err, GetDoraMetrics", "err", err, "payload", query)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
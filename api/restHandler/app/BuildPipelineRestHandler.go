@@ -11,6 +11,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/user/casbin"
 	"github.com/gorilla/mux"
@@ -251,15 +252,58 @@ func (handler PipelineConfigRestHandlerImpl) PatchCiPipelines(w http.ResponseWri
 		}
 	}
 
+	var existingCiPipeline *bean.CiPipeline
+	if patchRequest.Action != bean.CREATE {
+		existingCiPipeline, err = handler.pipelineBuilder.GetCiPipelineById(patchRequest.CiPipeline.Id)
+		if err != nil {
+			handler.Logger.Errorw("service err, GetCiPipelineById", "err", err, "ciPipelineId", patchRequest.CiPipeline.Id)
+		}
+	}
 	createResp, err := handler.pipelineBuilder.PatchCiPipeline(&patchRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, PatchCiPipelines", "err", err, "PatchCiPipelines", patchRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditCiPipelinePatch(r, &patchRequest, existingCiPipeline, createResp)
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 }
 
+// auditCiPipelinePatch records the change made to ci pipeline, state after the change is fetched again as the patch
+// request carries only the changed fields
+func (handler PipelineConfigRestHandlerImpl) auditCiPipelinePatch(r *http.Request, patchRequest *bean.CiPatchRequest,
+	existingCiPipeline *bean.CiPipeline, patchResp *bean.CiConfigRequest) {
+	auditEvent := &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_CI_PIPELINE,
+		ResourceId:   patchRequest.CiPipeline.Id,
+		ResourceName: patchRequest.CiPipeline.Name,
+		AppId:        patchRequest.AppId,
+	}
+	if existingCiPipeline != nil {
+		auditEvent.Before = existingCiPipeline
+	}
+	switch patchRequest.Action {
+	case bean.CREATE:
+		auditEvent.Action = auditLog.ACTION_CREATE
+		if patchResp != nil && len(patchResp.CiPipelines) > 0 {
+			auditEvent.ResourceId = patchResp.CiPipelines[0].Id
+		}
+	case bean.DELETE:
+		auditEvent.Action = auditLog.ACTION_DELETE
+	default:
+		auditEvent.Action = auditLog.ACTION_UPDATE
+	}
+	if auditEvent.Action != auditLog.ACTION_DELETE && auditEvent.ResourceId > 0 {
+		ciPipeline, err := handler.pipelineBuilder.GetCiPipelineById(auditEvent.ResourceId)
+		if err != nil {
+			handler.Logger.Errorw("service err, GetCiPipelineById", "err", err, "ciPipelineId", auditEvent.ResourceId)
+		} else {
+			auditEvent.After = ciPipeline
+		}
+	}
+	handler.auditEventService.Record(r, auditEvent)
+}
+
 func (handler PipelineConfigRestHandlerImpl) GetCiPipeline(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	appId, err := strconv.Atoi(vars["appId"])
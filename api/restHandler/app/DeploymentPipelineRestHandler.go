@@ -8,6 +8,7 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auditLog"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
@@ -130,12 +131,14 @@ func (handler PipelineConfigRestHandlerImpl) ConfigureDeploymentTemplateForApp(w
 		return
 	}
 	ctx = context.WithValue(r.Context(), "token", acdToken)
+	existingTemplate := handler.getLatestDeploymentTemplate(templateRequest.AppId)
 	createResp, err := handler.chartService.Create(templateRequest, ctx)
 	if err != nil {
 		handler.Logger.Errorw("service err, ConfigureDeploymentTemplateForApp", "err", err, "payload", templateRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditDeploymentTemplate(r, app.AppName, templateRequest.AppId, existingTemplate)
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 }
 
@@ -270,15 +273,61 @@ func (handler PipelineConfigRestHandlerImpl) PatchCdPipeline(w http.ResponseWrit
 		return
 	}
 	ctx := context.WithValue(r.Context(), "token", acdToken)
+	var existingCdPipeline *bean.CDPipelineConfigObject
+	if cdPipeline.Action != bean.CD_CREATE {
+		existingCdPipeline, err = handler.pipelineBuilder.GetCdPipelineById(cdPipeline.Pipeline.Id)
+		if err != nil {
+			handler.Logger.Errorw("service err, GetCdPipelineById", "err", err, "cdPipelineId", cdPipeline.Pipeline.Id)
+		}
+	}
 	createResp, err := handler.pipelineBuilder.PatchCdPipelines(&cdPipeline, ctx)
 	if err != nil {
 		handler.Logger.Errorw("service err, PatchCdPipeline", "err", err, "payload", cdPipeline)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditCdPipelinePatch(r, &cdPipeline, existingCdPipeline, createResp)
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 }
 
+// auditCdPipelinePatch records the change made to cd pipeline, state after the change is fetched again so that it
+// is comparable with the state before
+func (handler PipelineConfigRestHandlerImpl) auditCdPipelinePatch(r *http.Request, patchRequest *bean.CDPatchRequest,
+	existingCdPipeline *bean.CDPipelineConfigObject, patchResp *bean.CdPipelines) {
+	auditEvent := &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_CD_PIPELINE,
+		ResourceId:   patchRequest.Pipeline.Id,
+		ResourceName: patchRequest.Pipeline.Name,
+		AppId:        patchRequest.AppId,
+		EnvId:        patchRequest.Pipeline.EnvironmentId,
+	}
+	if existingCdPipeline != nil {
+		auditEvent.Before = existingCdPipeline
+		auditEvent.ResourceName = existingCdPipeline.Name
+		auditEvent.EnvId = existingCdPipeline.EnvironmentId
+	}
+	switch patchRequest.Action {
+	case bean.CD_CREATE:
+		auditEvent.Action = auditLog.ACTION_CREATE
+		if patchResp != nil && len(patchResp.Pipelines) > 0 {
+			auditEvent.ResourceId = patchResp.Pipelines[0].Id
+		}
+	case bean.CD_DELETE:
+		auditEvent.Action = auditLog.ACTION_DELETE
+	default:
+		auditEvent.Action = auditLog.ACTION_UPDATE
+	}
+	if auditEvent.Action != auditLog.ACTION_DELETE && auditEvent.ResourceId > 0 {
+		cdPipeline, err := handler.pipelineBuilder.GetCdPipelineById(auditEvent.ResourceId)
+		if err != nil {
+			handler.Logger.Errorw("service err, GetCdPipelineById", "err", err, "cdPipelineId", auditEvent.ResourceId)
+		} else {
+			auditEvent.After = cdPipeline
+		}
+	}
+	handler.auditEventService.Record(r, auditEvent)
+}
+
 func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideCreate(w http.ResponseWriter, r *http.Request) {
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -425,13 +474,34 @@ func (handler PipelineConfigRestHandlerImpl) EnvConfigOverrideUpdate(w http.Resp
 		return
 	}
 
+	existingProperties, err := handler.propertiesConfigService.GetLatestEnvironmentProperties(appId, envId)
+	if err != nil {
+		handler.Logger.Errorw("service err, GetLatestEnvironmentProperties", "err", err, "appId", appId, "envId", envId)
+	}
 	createResp, err := handler.propertiesConfigService.UpdateEnvironmentProperties(appId, &envConfigProperties, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvConfigOverrideUpdate", "err", err, "payload", envConfigProperties)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
-	common.WriteJsonResp(w, err, createResp, http.StatusOK)
+	auditEvent := &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_DEPLOYMENT_TEMPLATE,
+		ResourceId:   envConfigProperties.Id,
+		AppId:        appId,
+		EnvId:        envId,
+		Action:       auditLog.ACTION_UPDATE,
+	}
+	if existingProperties != nil {
+		auditEvent.Before = existingProperties
+	}
+	updatedProperties, err := handler.propertiesConfigService.GetLatestEnvironmentProperties(appId, envId)
+	if err != nil {
+		handler.Logger.Errorw("service err, GetLatestEnvironmentProperties", "err", err, "appId", appId, "envId", envId)
+	} else if updatedProperties != nil {
+		auditEvent.After = updatedProperties
+	}
+	handler.auditEventService.Record(r, auditEvent)
+	common.WriteJsonResp(w, nil, createResp, http.StatusOK)
 }
 
 func (handler PipelineConfigRestHandlerImpl) GetEnvConfigOverride(w http.ResponseWriter, r *http.Request) {
@@ -848,12 +918,14 @@ func (handler PipelineConfigRestHandlerImpl) UpdateAppOverride(w http.ResponseWr
 		return
 	}
 
+	existingTemplate := handler.getLatestDeploymentTemplate(templateRequest.AppId)
 	createResp, err := handler.chartService.UpdateAppOverride(&templateRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, UpdateAppOverride", "err", err, "payload", templateRequest)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	handler.auditDeploymentTemplate(r, app.AppName, templateRequest.AppId, existingTemplate)
 	common.WriteJsonResp(w, err, createResp, http.StatusOK)
 
 }
@@ -1812,3 +1884,35 @@ func (handler PipelineConfigRestHandlerImpl) UpgradeForAllApps(w http.ResponseWr
 	response["failed"] = failedIds
 	common.WriteJsonResp(w, err, response, http.StatusOK)
 }
+
+// getLatestDeploymentTemplate returns the base deployment template of app, nil when app has none yet
+func (handler PipelineConfigRestHandlerImpl) getLatestDeploymentTemplate(appId int) *chart.TemplateRequest {
+	template, err := handler.chartService.FindLatestChartForAppByAppId(appId)
+	if err != nil {
+		if !util.IsErrNoRows(err) {
+			handler.Logger.Errorw("service err, FindLatestChartForAppByAppId", "err", err, "appId", appId)
+		}
+		return nil
+	}
+	return template
+}
+
+// auditDeploymentTemplate records the change made to base deployment template of app
+func (handler PipelineConfigRestHandlerImpl) auditDeploymentTemplate(r *http.Request, appName string, appId int, existingTemplate *chart.TemplateRequest) {
+	auditEvent := &auditLog.AuditEventRequest{
+		ResourceType: auditLog.RESOURCE_TYPE_DEPLOYMENT_TEMPLATE,
+		ResourceName: appName,
+		AppId:        appId,
+		Action:       auditLog.ACTION_CREATE,
+	}
+	if existingTemplate != nil {
+		auditEvent.Action = auditLog.ACTION_UPDATE
+		auditEvent.ResourceId = existingTemplate.Id
+		auditEvent.Before = existingTemplate
+	}
+	if template := handler.getLatestDeploymentTemplate(appId); template != nil {
+		auditEvent.ResourceId = template.Id
+		auditEvent.After = template
+	}
+	handler.auditEventService.Record(r, auditEvent)
+}
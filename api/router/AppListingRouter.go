@@ -64,4 +64,12 @@ func (router AppListingRouterImpl) initAppListingRouter(appListingRouter *mux.Ro
 		Queries("containerName", "{containerName}").
 		HandlerFunc(router.appListingRestHandler.RedirectToLinkouts).
 		Methods("GET")
+
+	appListingRouter.Path("/deployment-status/timeline/{appId}/{envId}").
+		HandlerFunc(router.appListingRestHandler.FetchAppDeploymentStatusTimeline).
+		Methods("GET")
+
+	appListingRouter.Path("/deployment-status/failed-resources/{appId}/{envId}").
+		HandlerFunc(router.appListingRestHandler.FetchAppDeploymentFailedResources).
+		Methods("GET")
 }
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/devtron-labs/devtron/api/restHandler"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type CanaryAnalysisRouter interface {
+	initCanaryAnalysisRouter(canaryAnalysisRouter *mux.Router)
+}
+
+func NewCanaryAnalysisRouterImpl(logger *zap.SugaredLogger, canaryAnalysisRestHandler restHandler.CanaryAnalysisRestHandler) *CanaryAnalysisRouterImpl {
+	return &CanaryAnalysisRouterImpl{
+		logger:                    logger,
+		canaryAnalysisRestHandler: canaryAnalysisRestHandler,
+	}
+}
+
+type CanaryAnalysisRouterImpl struct {
+	logger                    *zap.SugaredLogger
+	canaryAnalysisRestHandler restHandler.CanaryAnalysisRestHandler
+}
+
+func (impl *CanaryAnalysisRouterImpl) initCanaryAnalysisRouter(canaryAnalysisRouter *mux.Router) {
+	canaryAnalysisRouter.Path("/pipeline/{pipelineId}/config").
+		HandlerFunc(impl.canaryAnalysisRestHandler.GetConfig).Methods("GET")
+	canaryAnalysisRouter.Path("/pipeline/{pipelineId}/config").
+		HandlerFunc(impl.canaryAnalysisRestHandler.SaveConfig).Methods("POST")
+	canaryAnalysisRouter.Path("/pipeline/{pipelineId}/config").
+		HandlerFunc(impl.canaryAnalysisRestHandler.DeleteConfig).Methods("DELETE")
+	canaryAnalysisRouter.Path("/pipeline/{pipelineId}/runs").
+		HandlerFunc(impl.canaryAnalysisRestHandler.GetRuns).Methods("GET")
+}
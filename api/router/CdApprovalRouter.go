@@ -0,0 +1,41 @@
+package router
+
+import (
+	"github.com/devtron-labs/devtron/api/restHandler"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type CdApprovalRouter interface {
+	initCdApprovalRouter(cdApprovalRouter *mux.Router)
+}
+
+func NewCdApprovalRouterImpl(logger *zap.SugaredLogger, cdApprovalRestHandler restHandler.CdApprovalRestHandler) *CdApprovalRouterImpl {
+	return &CdApprovalRouterImpl{
+		logger:                logger,
+		cdApprovalRestHandler: cdApprovalRestHandler,
+	}
+}
+
+type CdApprovalRouterImpl struct {
+	logger                *zap.SugaredLogger
+	cdApprovalRestHandler restHandler.CdApprovalRestHandler
+}
+
+func (impl *CdApprovalRouterImpl) initCdApprovalRouter(cdApprovalRouter *mux.Router) {
+	cdApprovalRouter.Path("/pipeline/{pipelineId}/policy").
+		HandlerFunc(impl.cdApprovalRestHandler.GetPolicy).Methods("GET")
+	cdApprovalRouter.Path("/pipeline/{pipelineId}/policy").
+		HandlerFunc(impl.cdApprovalRestHandler.SavePolicy).Methods("POST")
+	cdApprovalRouter.Path("/pipeline/{pipelineId}/policy").
+		HandlerFunc(impl.cdApprovalRestHandler.DeletePolicy).Methods("DELETE")
+
+	cdApprovalRouter.Path("/pipeline/{pipelineId}/request").
+		HandlerFunc(impl.cdApprovalRestHandler.GetRequests).Methods("GET")
+	cdApprovalRouter.Path("/pipeline/{pipelineId}/request").
+		HandlerFunc(impl.cdApprovalRestHandler.RaiseRequest).Methods("POST")
+	cdApprovalRouter.Path("/request/{requestId}/approve").
+		HandlerFunc(impl.cdApprovalRestHandler.ApproveRequest).Methods("PUT")
+	cdApprovalRouter.Path("/request/{requestId}/reject").
+		HandlerFunc(impl.cdApprovalRestHandler.RejectRequest).Methods("PUT")
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/devtron-labs/devtron/api/restHandler"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type CdPromotionPolicyRouter interface {
+	initCdPromotionPolicyRouter(cdPromotionPolicyRouter *mux.Router)
+}
+
+func NewCdPromotionPolicyRouterImpl(logger *zap.SugaredLogger, cdPromotionPolicyRestHandler restHandler.CdPromotionPolicyRestHandler) *CdPromotionPolicyRouterImpl {
+	return &CdPromotionPolicyRouterImpl{
+		logger:                       logger,
+		cdPromotionPolicyRestHandler: cdPromotionPolicyRestHandler,
+	}
+}
+
+type CdPromotionPolicyRouterImpl struct {
+	logger                       *zap.SugaredLogger
+	cdPromotionPolicyRestHandler restHandler.CdPromotionPolicyRestHandler
+}
+
+func (impl *CdPromotionPolicyRouterImpl) initCdPromotionPolicyRouter(cdPromotionPolicyRouter *mux.Router) {
+	cdPromotionPolicyRouter.Path("/pipeline/{pipelineId}/policy").
+		HandlerFunc(impl.cdPromotionPolicyRestHandler.GetPolicy).Methods("GET")
+	cdPromotionPolicyRouter.Path("/pipeline/{pipelineId}/policy").
+		HandlerFunc(impl.cdPromotionPolicyRestHandler.SavePolicy).Methods("POST")
+	cdPromotionPolicyRouter.Path("/pipeline/{pipelineId}/policy").
+		HandlerFunc(impl.cdPromotionPolicyRestHandler.DeletePolicy).Methods("DELETE")
+	cdPromotionPolicyRouter.Path("/pipeline/{pipelineId}/artifact/{artifactId}").
+		HandlerFunc(impl.cdPromotionPolicyRestHandler.GetArtifactEligibility).Methods("GET")
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/devtron-labs/devtron/api/restHandler"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type CiPipelineScheduleRouter interface {
+	initCiPipelineScheduleRouter(ciPipelineScheduleRouter *mux.Router)
+}
+
+func NewCiPipelineScheduleRouterImpl(logger *zap.SugaredLogger, ciPipelineScheduleRestHandler restHandler.CiPipelineScheduleRestHandler) *CiPipelineScheduleRouterImpl {
+	return &CiPipelineScheduleRouterImpl{
+		logger:                        logger,
+		ciPipelineScheduleRestHandler: ciPipelineScheduleRestHandler,
+	}
+}
+
+type CiPipelineScheduleRouterImpl struct {
+	logger                        *zap.SugaredLogger
+	ciPipelineScheduleRestHandler restHandler.CiPipelineScheduleRestHandler
+}
+
+func (impl *CiPipelineScheduleRouterImpl) initCiPipelineScheduleRouter(ciPipelineScheduleRouter *mux.Router) {
+	ciPipelineScheduleRouter.Path("/pipeline/{ciPipelineId}").
+		HandlerFunc(impl.ciPipelineScheduleRestHandler.GetSchedules).Methods("GET")
+	ciPipelineScheduleRouter.Path("/pipeline/{ciPipelineId}").
+		HandlerFunc(impl.ciPipelineScheduleRestHandler.SaveSchedule).Methods("POST")
+	ciPipelineScheduleRouter.Path("/pipeline/{ciPipelineId}/runs").
+		HandlerFunc(impl.ciPipelineScheduleRestHandler.GetRuns).Methods("GET")
+	ciPipelineScheduleRouter.Path("/{scheduleId}").
+		HandlerFunc(impl.ciPipelineScheduleRestHandler.DeleteSchedule).Methods("DELETE")
+}
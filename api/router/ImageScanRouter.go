@@ -42,4 +42,10 @@ func (impl ImageScanRouterImpl) InitImageScanRouter(configRouter *mux.Router) {
 
 	configRouter.Path("/cve/exposure").HandlerFunc(impl.imageScanRestHandler.VulnerabilityExposure).Methods("POST")
 
+	configRouter.Path("/sbom").HandlerFunc(impl.imageScanRestHandler.SaveSbom).Methods("POST")
+	configRouter.Path("/sbom/search").HandlerFunc(impl.imageScanRestHandler.SearchSbomComponents).Methods("POST")
+	configRouter.Path("/sbom/{artifactId:[0-9]+}").HandlerFunc(impl.imageScanRestHandler.FetchSboms).Methods("GET")
+	configRouter.Path("/sbom/{artifactId:[0-9]+}/download").HandlerFunc(impl.imageScanRestHandler.DownloadSbom).Methods("GET")
+	configRouter.Path("/sbom/{artifactId:[0-9]+}/generate").HandlerFunc(impl.imageScanRestHandler.GenerateSbom).Methods("POST")
+
 }
@@ -63,6 +63,9 @@ func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.R
 	configRouter.Path("/channel/smtp/{id}").
 		HandlerFunc(impl.notificationRestHandler.FindSMTPConfig).
 		Methods("GET")
+	configRouter.Path("/channel/{channel:teams|webhook|pagerduty}/{id}").
+		HandlerFunc(impl.notificationRestHandler.FindNotificationChannelConfig).
+		Methods("GET")
 	configRouter.Path("/channel").
 		HandlerFunc(impl.notificationRestHandler.DeleteNotificationChannelConfig).
 		Methods("DELETE")
@@ -78,4 +81,20 @@ func (impl NotificationRouterImpl) InitNotificationRegRouter(configRouter *mux.R
 		HandlerFunc(impl.notificationRestHandler.GetOptionsForNotificationSettings).
 		Methods("POST")
 
+	configRouter.Path("/template").
+		HandlerFunc(impl.notificationRestHandler.SaveNotificationTemplate).
+		Methods("POST")
+	configRouter.Path("/template").
+		HandlerFunc(impl.notificationRestHandler.FindAllNotificationTemplates).
+		Methods("GET")
+	configRouter.Path("/template/preview").
+		HandlerFunc(impl.notificationRestHandler.PreviewNotificationTemplate).
+		Methods("POST")
+	configRouter.Path("/template/{id}").
+		HandlerFunc(impl.notificationRestHandler.FindNotificationTemplate).
+		Methods("GET")
+	configRouter.Path("/template/{id}").
+		HandlerFunc(impl.notificationRestHandler.DeleteNotificationTemplate).
+		Methods("DELETE")
+
 }
@@ -38,5 +38,8 @@ func (impl PolicyRouterImpl) InitPolicyRouter(configRouter *mux.Router) {
 	configRouter.Path("/save").HandlerFunc(impl.policyRestHandler.SavePolicy).Methods("POST")
 	configRouter.Path("/update").HandlerFunc(impl.policyRestHandler.UpdatePolicy).Methods("POST")
 	configRouter.Path("/list").HandlerFunc(impl.policyRestHandler.GetPolicy).Methods("GET")
+	configRouter.Path("/exception/expiring").HandlerFunc(impl.policyRestHandler.GetExpiringExceptions).Methods("GET")
+	configRouter.Path("/exception/pending").HandlerFunc(impl.policyRestHandler.GetPendingExceptions).Methods("GET")
+	configRouter.Path("/exception/review").HandlerFunc(impl.policyRestHandler.ReviewException).Methods("POST")
 	configRouter.Path("/verify/webhook").HandlerFunc(impl.policyRestHandler.VerifyImage).Methods("POST")
 }
@@ -47,6 +47,9 @@ func (impl ReleaseMetricsRouterImpl) initReleaseMetricsRouter(router *mux.Router
 	router.Path("/reset-all-app-environment").
 		HandlerFunc(impl.releaseMetricsRestHandler.ResetDataForAllAppEnvironment).
 		Methods("POST")
+	router.Path("/dora").
+		HandlerFunc(impl.releaseMetricsRestHandler.GetDoraMetrics).
+		Methods("GET")
 	router.Path("/").
 		HandlerFunc(impl.releaseMetricsRestHandler.GetDeploymentMetrics).
 		Methods("GET")
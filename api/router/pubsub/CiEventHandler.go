@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/security"
 	"github.com/devtron-labs/devtron/util"
 	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
@@ -40,6 +41,7 @@ type CiEventHandlerImpl struct {
 	logger         *zap.SugaredLogger
 	pubsubClient   *pubsub.PubSubClient
 	webhookService pipeline.WebhookService
+	sbomService    security.SbomService
 }
 
 type CiCompleteEvent struct {
@@ -54,11 +56,13 @@ type CiCompleteEvent struct {
 	MaterialType     string                      `json:"materialType" validate:"required"`
 }
 
-func NewCiEventHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClient, webhookService pipeline.WebhookService) *CiEventHandlerImpl {
+func NewCiEventHandlerImpl(logger *zap.SugaredLogger, pubsubClient *pubsub.PubSubClient, webhookService pipeline.WebhookService,
+	sbomService security.SbomService) *CiEventHandlerImpl {
 	ciEventHandlerImpl := &CiEventHandlerImpl{
 		logger:         logger,
 		pubsubClient:   pubsubClient,
 		webhookService: webhookService,
+		sbomService:    sbomService,
 	}
 	err := util.AddStream(ciEventHandlerImpl.pubsubClient.JetStrCtxt, util.CI_RUNNER_STREAM)
 	if err != nil {
@@ -94,6 +98,12 @@ func (impl *CiEventHandlerImpl) Subscribe() error {
 			return
 		}
 		impl.logger.Debug(resp)
+		go func() {
+			err := impl.sbomService.GenerateSbom(resp, ciCompleteEvent.TriggeredBy)
+			if err != nil {
+				impl.logger.Errorw("error in generating sbom of ci artifact", "err", err, "ciArtifactId", resp)
+			}
+		}()
 	}, nats.Durable(util.CI_COMPLETE_DURABLE), nats.DeliverLast(), nats.ManualAck(), nats.BindStream(util.CI_RUNNER_STREAM))
 	if err != nil {
 		impl.logger.Error(err)
@@ -22,15 +22,21 @@ import (
 	"github.com/devtron-labs/devtron/api/apiToken"
 	"github.com/devtron-labs/devtron/api/appStore"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/externalLink"
+	"github.com/devtron-labs/devtron/api/gitopsDrift"
+	"github.com/devtron-labs/devtron/api/gitopsRepo"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSignature"
 	"github.com/devtron-labs/devtron/api/module"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/pubsub"
+	"github.com/devtron-labs/devtron/api/scim"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sso"
 	"github.com/devtron-labs/devtron/api/team"
@@ -112,6 +118,28 @@ type MuxRouter struct {
 	helmApplicationStatusUpdateHandler cron.HelmApplicationStatusUpdateHandler
 	k8sCapacityRouter                  k8s.K8sCapacityRouter
 	webhookHelmRouter                  webhookHelm.WebhookHelmRouter
+	canaryAnalysisRouter               CanaryAnalysisRouter
+	canaryAnalysisHandler              cron.CanaryAnalysisHandler
+	cdApprovalRouter                   CdApprovalRouter
+	deploymentWindowRouter             deploymentWindow.DeploymentWindowRouter
+	ciPipelineScheduleRouter           CiPipelineScheduleRouter
+	cdPromotionPolicyRouter            CdPromotionPolicyRouter
+	imageSignatureRouter               imageSignature.ImageSignatureRouter
+	cveExceptionExpiryHandler          cron.CveExceptionExpiryHandler
+	deployedImageRescanHandler         cron.DeployedImageRescanHandler
+	apiTokenScopeMiddleware            apiToken.ApiTokenScopeMiddleware
+	roleGrantExpiryHandler             cron.RoleGrantExpiryHandler
+	scimRouter                         scim.ScimRouter
+	auditEventRouter                   auditLog.AuditEventRouter
+	auditRequestIdMiddleware           auditLog.AuditRequestIdMiddleware
+	auditEventRetentionHandler         cron.AuditEventRetentionHandler
+	siemExportHandler                  cron.SiemExportHandler
+	gitOpsPullRequestHandler           cron.GitOpsPullRequestHandler
+	gitOpsDriftRouter                  gitopsDrift.GitOpsDriftRouter
+	gitOpsDriftHandler                 cron.GitOpsDriftHandler
+	ociChartSyncHandler                cron.OCIChartSyncHandler
+	cdPromotionHandler                 cron.CdPromotionHandler
+	gitOpsRepoRouter                   gitopsRepo.GitOpsRepoRouter
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter HelmRouter, PipelineConfigRouter PipelineConfigRouter,
@@ -137,7 +165,18 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter HelmRouter, PipelineConf
 	commonDeploymentRouter appStoreDeployment.CommonDeploymentRouter, externalLinkRouter externalLink.ExternalLinkRouter,
 	globalPluginRouter GlobalPluginRouter, moduleRouter module.ModuleRouter,
 	serverRouter server.ServerRouter, apiTokenRouter apiToken.ApiTokenRouter,
-	helmApplicationStatusUpdateHandler cron.HelmApplicationStatusUpdateHandler, k8sCapacityRouter k8s.K8sCapacityRouter, webhookHelmRouter webhookHelm.WebhookHelmRouter) *MuxRouter {
+	helmApplicationStatusUpdateHandler cron.HelmApplicationStatusUpdateHandler, k8sCapacityRouter k8s.K8sCapacityRouter, webhookHelmRouter webhookHelm.WebhookHelmRouter,
+	canaryAnalysisRouter CanaryAnalysisRouter, canaryAnalysisHandler cron.CanaryAnalysisHandler,
+	cdApprovalRouter CdApprovalRouter, deploymentWindowRouter deploymentWindow.DeploymentWindowRouter,
+	ciPipelineScheduleRouter CiPipelineScheduleRouter, cdPromotionPolicyRouter CdPromotionPolicyRouter,
+	imageSignatureRouter imageSignature.ImageSignatureRouter, cveExceptionExpiryHandler cron.CveExceptionExpiryHandler,
+	deployedImageRescanHandler cron.DeployedImageRescanHandler, apiTokenScopeMiddleware apiToken.ApiTokenScopeMiddleware,
+	roleGrantExpiryHandler cron.RoleGrantExpiryHandler, scimRouter scim.ScimRouter,
+	auditEventRouter auditLog.AuditEventRouter, auditRequestIdMiddleware auditLog.AuditRequestIdMiddleware,
+	auditEventRetentionHandler cron.AuditEventRetentionHandler, siemExportHandler cron.SiemExportHandler,
+	gitOpsPullRequestHandler cron.GitOpsPullRequestHandler, gitOpsDriftRouter gitopsDrift.GitOpsDriftRouter,
+	gitOpsDriftHandler cron.GitOpsDriftHandler, gitOpsRepoRouter gitopsRepo.GitOpsRepoRouter,
+	ociChartSyncHandler cron.OCIChartSyncHandler, cdPromotionHandler cron.CdPromotionHandler) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
 		HelmRouter:                         HelmRouter,
@@ -201,11 +240,35 @@ func NewMuxRouter(logger *zap.SugaredLogger, HelmRouter HelmRouter, PipelineConf
 		helmApplicationStatusUpdateHandler: helmApplicationStatusUpdateHandler,
 		k8sCapacityRouter:                  k8sCapacityRouter,
 		webhookHelmRouter:                  webhookHelmRouter,
+		canaryAnalysisRouter:               canaryAnalysisRouter,
+		canaryAnalysisHandler:              canaryAnalysisHandler,
+		cdApprovalRouter:                   cdApprovalRouter,
+		deploymentWindowRouter:             deploymentWindowRouter,
+		ciPipelineScheduleRouter:           ciPipelineScheduleRouter,
+		cdPromotionPolicyRouter:            cdPromotionPolicyRouter,
+		imageSignatureRouter:               imageSignatureRouter,
+		cveExceptionExpiryHandler:          cveExceptionExpiryHandler,
+		deployedImageRescanHandler:         deployedImageRescanHandler,
+		apiTokenScopeMiddleware:            apiTokenScopeMiddleware,
+		roleGrantExpiryHandler:             roleGrantExpiryHandler,
+		scimRouter:                         scimRouter,
+		auditEventRouter:                   auditEventRouter,
+		auditRequestIdMiddleware:           auditRequestIdMiddleware,
+		auditEventRetentionHandler:         auditEventRetentionHandler,
+		siemExportHandler:                  siemExportHandler,
+		gitOpsPullRequestHandler:           gitOpsPullRequestHandler,
+		gitOpsDriftRouter:                  gitOpsDriftRouter,
+		gitOpsDriftHandler:                 gitOpsDriftHandler,
+		ociChartSyncHandler:                ociChartSyncHandler,
+		cdPromotionHandler:                 cdPromotionHandler,
+		gitOpsRepoRouter:                   gitOpsRepoRouter,
 	}
 	return r
 }
 
 func (r MuxRouter) Init() {
+	r.Router.Use(r.apiTokenScopeMiddleware.Handle)
+	r.Router.Use(r.auditRequestIdMiddleware.Handle)
 
 	r.Router.PathPrefix("/orchestrator/api/vi/pod/exec/ws").Handler(terminal.CreateAttachHandler("/orchestrator/api/vi/pod/exec/ws"))
 
@@ -320,6 +383,10 @@ func (r MuxRouter) Init() {
 
 	gitOpsRouter := r.Router.PathPrefix("/orchestrator/gitops").Subrouter()
 	r.gitOpsConfigRouter.InitGitOpsConfigRouter(gitOpsRouter)
+	gitOpsDriftRouter := gitOpsRouter.PathPrefix("/drift").Subrouter()
+	r.gitOpsDriftRouter.InitGitOpsDriftRouter(gitOpsDriftRouter)
+	gitOpsRepoRouter := gitOpsRouter.PathPrefix("/repo").Subrouter()
+	r.gitOpsRepoRouter.InitGitOpsRepoRouter(gitOpsRepoRouter)
 
 	attributeRouter := r.Router.PathPrefix("/orchestrator/attributes").Subrouter()
 	r.attributesRouter.initAttributesRouter(attributeRouter)
@@ -389,10 +456,36 @@ func (r MuxRouter) Init() {
 	apiTokenRouter := r.Router.PathPrefix("/orchestrator/api-token").Subrouter()
 	r.apiTokenRouter.InitApiTokenRouter(apiTokenRouter)
 
+	// scim provisioning router
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
+
+	// audit event router
+	auditEventRouter := r.Router.PathPrefix("/orchestrator/audit").Subrouter()
+	r.auditEventRouter.InitAuditEventRouter(auditEventRouter)
+
 	k8sCapacityApp := r.Router.PathPrefix("/orchestrator/k8s/capacity").Subrouter()
 	r.k8sCapacityRouter.InitK8sCapacityRouter(k8sCapacityApp)
 
 	// webhook helm app router
 	webhookHelmRouter := r.Router.PathPrefix("/orchestrator/webhook/helm").Subrouter()
 	r.webhookHelmRouter.InitWebhookHelmRouter(webhookHelmRouter)
+
+	canaryAnalysisRouter := r.Router.PathPrefix("/orchestrator/canary-analysis").Subrouter()
+	r.canaryAnalysisRouter.initCanaryAnalysisRouter(canaryAnalysisRouter)
+
+	cdApprovalRouter := r.Router.PathPrefix("/orchestrator/deployment-approval").Subrouter()
+	r.cdApprovalRouter.initCdApprovalRouter(cdApprovalRouter)
+
+	deploymentWindowRouter := r.Router.PathPrefix("/orchestrator/deployment-window").Subrouter()
+	r.deploymentWindowRouter.InitDeploymentWindowRouter(deploymentWindowRouter)
+
+	ciPipelineScheduleRouter := r.Router.PathPrefix("/orchestrator/ci-pipeline-schedule").Subrouter()
+	r.ciPipelineScheduleRouter.initCiPipelineScheduleRouter(ciPipelineScheduleRouter)
+
+	cdPromotionPolicyRouter := r.Router.PathPrefix("/orchestrator/deployment-promotion").Subrouter()
+	r.cdPromotionPolicyRouter.initCdPromotionPolicyRouter(cdPromotionPolicyRouter)
+
+	imageSignaturePolicyRouter := r.Router.PathPrefix("/orchestrator/image-signature/policy").Subrouter()
+	r.imageSignatureRouter.InitImageSignatureRouter(imageSignaturePolicyRouter)
 }
@@ -0,0 +1,366 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/devtron-labs/devtron/pkg/scim"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+const scimContentType = "application/scim+json"
+
+type ScimRestHandler interface {
+	// Authenticate allows the requests made by a super-admin api-token dedicated to scim, the token is sent by
+	// identity providers as bearer token
+	Authenticate(next http.Handler) http.Handler
+	GetServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	GetUser(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	ReplaceUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
+	DeleteUser(w http.ResponseWriter, r *http.Request)
+
+	ListGroups(w http.ResponseWriter, r *http.Request)
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	ReplaceGroup(w http.ResponseWriter, r *http.Request)
+	PatchGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+}
+
+type ScimRestHandlerImpl struct {
+	logger          *zap.SugaredLogger
+	scimService     scim.ScimService
+	apiTokenService apiToken.ApiTokenService
+	userService     user.UserService
+	enforcer        casbin.Enforcer
+	validator       *validator.Validate
+}
+
+func NewScimRestHandlerImpl(logger *zap.SugaredLogger, scimService scim.ScimService, apiTokenService apiToken.ApiTokenService,
+	userService user.UserService, enforcer casbin.Enforcer, validator *validator.Validate) *ScimRestHandlerImpl {
+	return &ScimRestHandlerImpl{
+		logger:          logger,
+		scimService:     scimService,
+		apiTokenService: apiTokenService,
+		userService:     userService,
+		enforcer:        enforcer,
+		validator:       validator,
+	}
+}
+
+func (impl ScimRestHandlerImpl) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if len(authorization) > len("Bearer ") && strings.EqualFold(authorization[:len("Bearer ")], "Bearer ") {
+			r.Header.Set("token", strings.TrimSpace(authorization[len("Bearer "):]))
+		}
+		err := impl.apiTokenService.VerifyScimApiToken(r)
+		if err != nil {
+			impl.logger.Errorw("scim api-token verification failed", "err", err, "method", r.Method, "path", r.URL.Path)
+			impl.writeError(w, err)
+			return
+		}
+		userId, err := impl.userService.GetLoggedInUser(r)
+		if userId == 0 || err != nil {
+			impl.writeError(w, &util.ApiError{HttpStatusCode: http.StatusUnauthorized, InternalMessage: "Unauthorized User"})
+			return
+		}
+		// provisioning creates users and changes their groups, so scim token is required to be super-admin
+		isSuperAdmin, err := impl.userService.IsSuperAdmin(int(userId))
+		if err != nil || !isSuperAdmin {
+			impl.writeError(w, &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: "scim api-token must have super-admin access"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (impl ScimRestHandlerImpl) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	config := &scim.ServiceProviderConfig{
+		Schemas:        []string{scim.SCHEMA_SP_CONFIG},
+		Patch:          scim.Supported{Supported: true},
+		Filter:         scim.FilterSupported{Supported: true, MaxResults: scim.MAX_PAGE_SIZE},
+		ChangePassword: scim.Supported{Supported: false},
+		Sort:           scim.Supported{Supported: false},
+		Etag:           scim.Supported{Supported: false},
+		AuthenticationSchemes: []*scim.AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "API Token",
+			Description: "Devtron api-token restricted to the scim route group",
+		}},
+	}
+	impl.writeResponse(w, config, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) ListUsers(w http.ResponseWriter, r *http.Request) {
+	filter, startIndex, count := getListParams(r)
+	res, err := impl.scimService.ListUsers(filter, startIndex, count)
+	if err != nil {
+		impl.logger.Errorw("service err, ListUsers", "err", err, "filter", filter)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	res, err := impl.scimService.GetUser(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetUser", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var request scim.User
+	if !impl.decodeRequest(w, r, &request) {
+		return
+	}
+	res, err := impl.scimService.CreateUser(&request, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, CreateUser", "err", err, "userName", request.UserName)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusCreated)
+}
+
+func (impl ScimRestHandlerImpl) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.User
+	if !impl.decodeRequest(w, r, &request) {
+		return
+	}
+	res, err := impl.scimService.ReplaceUser(id, &request, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, ReplaceUser", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) PatchUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.PatchRequest
+	if !impl.decodeRequest(w, r, &request) {
+		return
+	}
+	res, err := impl.scimService.PatchUser(id, &request, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, PatchUser", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	err := impl.scimService.DeleteUser(id, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteUser", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (impl ScimRestHandlerImpl) ListGroups(w http.ResponseWriter, r *http.Request) {
+	filter, startIndex, count := getListParams(r)
+	res, err := impl.scimService.ListGroups(filter, startIndex, count)
+	if err != nil {
+		impl.logger.Errorw("service err, ListGroups", "err", err, "filter", filter)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) GetGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	res, err := impl.scimService.GetGroup(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetGroup", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var request scim.Group
+	if !impl.decodeRequest(w, r, &request) {
+		return
+	}
+	res, err := impl.scimService.CreateGroup(&request, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, CreateGroup", "err", err, "displayName", request.DisplayName)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusCreated)
+}
+
+func (impl ScimRestHandlerImpl) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.Group
+	if !impl.decodeRequest(w, r, &request) {
+		return
+	}
+	res, err := impl.scimService.ReplaceGroup(id, &request, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, ReplaceGroup", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	var request scim.PatchRequest
+	if !impl.decodeRequest(w, r, &request) {
+		return
+	}
+	res, err := impl.scimService.PatchGroup(id, &request, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, PatchGroup", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	impl.writeResponse(w, res, http.StatusOK)
+}
+
+func (impl ScimRestHandlerImpl) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	id, ok := impl.getPathId(w, r)
+	if !ok {
+		return
+	}
+	err := impl.scimService.DeleteGroup(id, impl.getRequestContext(r))
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteGroup", "err", err, "id", id)
+		impl.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (impl ScimRestHandlerImpl) getRequestContext(r *http.Request) *scim.RequestContext {
+	// user is already verified in Authenticate
+	userId, _ := impl.userService.GetLoggedInUser(r)
+	return &scim.RequestContext{
+		UserId:      userId,
+		Token:       r.Header.Get("token"),
+		ManagerAuth: impl.checkManagerAuth,
+	}
+}
+
+func (impl ScimRestHandlerImpl) checkManagerAuth(token string, object string) bool {
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceUser, casbin.ActionUpdate, strings.ToLower(object)); !ok {
+		return false
+	}
+	return true
+}
+
+func (impl ScimRestHandlerImpl) getPathId(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		// ids which are not devtron ids can not exist
+		impl.writeError(w, &util.ApiError{HttpStatusCode: http.StatusNotFound, InternalMessage: "resource not found"})
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func (impl ScimRestHandlerImpl) decodeRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		impl.logger.Errorw("err in decoding scim request", "err", err, "path", r.URL.Path)
+		impl.writeError(w, &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: scim.SCIM_TYPE_INVALID_SYNTAX, InternalMessage: err.Error()})
+		return false
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err in scim request", "err", err, "request", request)
+		impl.writeError(w, &util.ApiError{HttpStatusCode: http.StatusBadRequest, Code: scim.SCIM_TYPE_INVALID_VALUE, InternalMessage: err.Error()})
+		return false
+	}
+	return true
+}
+
+func (impl ScimRestHandlerImpl) writeResponse(w http.ResponseWriter, body interface{}, status int) {
+	response, err := json.Marshal(body)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling scim response", "err", err)
+		status = http.StatusInternalServerError
+		response, _ = json.Marshal(&scim.Error{Schemas: []string{scim.SCHEMA_ERROR}, Status: strconv.Itoa(status)})
+	}
+	w.Header().Set("Content-Type", scimContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(response)
+	if err != nil {
+		impl.logger.Errorw("error in writing scim response", "err", err)
+	}
+}
+
+// writeError writes the error in scim error format, identity providers rely on status and scimType to retry or skip
+func (impl ScimRestHandlerImpl) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	scimError := &scim.Error{Schemas: []string{scim.SCHEMA_ERROR}, Detail: err.Error()}
+	if apiErr, ok := err.(*util.ApiError); ok {
+		if apiErr.HttpStatusCode != 0 {
+			status = apiErr.HttpStatusCode
+		}
+		if scim.IsScimType(apiErr.Code) {
+			scimError.ScimType = apiErr.Code
+		}
+	} else if util.IsErrNoRows(err) {
+		status = http.StatusNotFound
+	}
+	scimError.Status = strconv.Itoa(status)
+	impl.writeResponse(w, scimError, status)
+}
+
+func getListParams(r *http.Request) (string, int, int) {
+	query := r.URL.Query()
+	startIndex, _ := strconv.Atoi(query.Get("startIndex"))
+	count, _ := strconv.Atoi(query.Get("count"))
+	return query.Get("filter"), startIndex, count
+}
@@ -0,0 +1,36 @@
+package scim
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ScimRouter interface {
+	InitScimRouter(scimRouter *mux.Router)
+}
+
+type ScimRouterImpl struct {
+	scimRestHandler ScimRestHandler
+}
+
+func NewScimRouterImpl(scimRestHandler ScimRestHandler) *ScimRouterImpl {
+	return &ScimRouterImpl{scimRestHandler: scimRestHandler}
+}
+
+func (impl ScimRouterImpl) InitScimRouter(scimRouter *mux.Router) {
+	scimRouter.Use(impl.scimRestHandler.Authenticate)
+	scimRouter.Path("/ServiceProviderConfig").HandlerFunc(impl.scimRestHandler.GetServiceProviderConfig).Methods("GET")
+
+	scimRouter.Path("/Users").HandlerFunc(impl.scimRestHandler.ListUsers).Methods("GET")
+	scimRouter.Path("/Users").HandlerFunc(impl.scimRestHandler.CreateUser).Methods("POST")
+	scimRouter.Path("/Users/{id}").HandlerFunc(impl.scimRestHandler.GetUser).Methods("GET")
+	scimRouter.Path("/Users/{id}").HandlerFunc(impl.scimRestHandler.ReplaceUser).Methods("PUT")
+	scimRouter.Path("/Users/{id}").HandlerFunc(impl.scimRestHandler.PatchUser).Methods("PATCH")
+	scimRouter.Path("/Users/{id}").HandlerFunc(impl.scimRestHandler.DeleteUser).Methods("DELETE")
+
+	scimRouter.Path("/Groups").HandlerFunc(impl.scimRestHandler.ListGroups).Methods("GET")
+	scimRouter.Path("/Groups").HandlerFunc(impl.scimRestHandler.CreateGroup).Methods("POST")
+	scimRouter.Path("/Groups/{id}").HandlerFunc(impl.scimRestHandler.GetGroup).Methods("GET")
+	scimRouter.Path("/Groups/{id}").HandlerFunc(impl.scimRestHandler.ReplaceGroup).Methods("PUT")
+	scimRouter.Path("/Groups/{id}").HandlerFunc(impl.scimRestHandler.PatchGroup).Methods("PATCH")
+	scimRouter.Path("/Groups/{id}").HandlerFunc(impl.scimRestHandler.DeleteGroup).Methods("DELETE")
+}
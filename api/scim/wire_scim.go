@@ -0,0 +1,15 @@
+package scim
+
+import (
+	"github.com/devtron-labs/devtron/pkg/scim"
+	"github.com/google/wire"
+)
+
+var ScimWireSet = wire.NewSet(
+	scim.NewScimServiceImpl,
+	wire.Bind(new(scim.ScimService), new(*scim.ScimServiceImpl)),
+	NewScimRestHandlerImpl,
+	wire.Bind(new(ScimRestHandler), new(*ScimRestHandlerImpl)),
+	NewScimRouterImpl,
+	wire.Bind(new(ScimRouter), new(*ScimRouterImpl)),
+)
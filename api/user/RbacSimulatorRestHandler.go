@@ -0,0 +1,118 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type RbacSimulatorRestHandler interface {
+	ExplainPermission(w http.ResponseWriter, r *http.Request)
+	GetPermittedUsers(w http.ResponseWriter, r *http.Request)
+}
+
+type RbacSimulatorRestHandlerImpl struct {
+	logger               *zap.SugaredLogger
+	userService          user.UserService
+	rbacSimulatorService user.RbacSimulatorService
+	enforcerUtil         rbac.EnforcerUtil
+	validator            *validator.Validate
+}
+
+func NewRbacSimulatorRestHandlerImpl(logger *zap.SugaredLogger, userService user.UserService,
+	rbacSimulatorService user.RbacSimulatorService, enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *RbacSimulatorRestHandlerImpl {
+	return &RbacSimulatorRestHandlerImpl{
+		logger:               logger,
+		userService:          userService,
+		rbacSimulatorService: rbacSimulatorService,
+		enforcerUtil:         enforcerUtil,
+		validator:            validator,
+	}
+}
+
+func (handler RbacSimulatorRestHandlerImpl) ExplainPermission(w http.ResponseWriter, r *http.Request) {
+	if !handler.checkSuperAdmin(w, r) {
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request bean.RbacExplainRequest
+	err := decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, ExplainPermission", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, ExplainPermission", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.rbacSimulatorService.ExplainPermission(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, ExplainPermission", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler RbacSimulatorRestHandlerImpl) GetPermittedUsers(w http.ResponseWriter, r *http.Request) {
+	if !handler.checkSuperAdmin(w, r) {
+		return
+	}
+	v := r.URL.Query()
+	appId, err := strconv.Atoi(v.Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	envId, err := strconv.Atoi(v.Get("envId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+		return
+	}
+	request := bean.RbacPermittedUsersRequest{AppId: appId, EnvId: envId, Action: v.Get("action")}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, GetPermittedUsers", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	appObject := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	envObject := handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+	res, err := handler.rbacSimulatorService.GetPermittedUsers(request.Action, appObject, envObject)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPermittedUsers", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// checkSuperAdmin writes the error response and returns false if the logged-in user is not a super-admin
+func (handler RbacSimulatorRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	isSuperAdmin, err := handler.userService.IsSuperAdmin(int(userId))
+	if err != nil {
+		common.WriteJsonResp(w, err, "Failed to check is super admin", http.StatusInternalServerError)
+		return false
+	}
+	if !isSuperAdmin {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -26,12 +26,14 @@ type UserRouter interface {
 }
 
 type UserRouterImpl struct {
-	userRestHandler UserRestHandler
+	userRestHandler          UserRestHandler
+	rbacSimulatorRestHandler RbacSimulatorRestHandler
 }
 
-func NewUserRouterImpl(userRestHandler UserRestHandler) *UserRouterImpl {
+func NewUserRouterImpl(userRestHandler UserRestHandler, rbacSimulatorRestHandler RbacSimulatorRestHandler) *UserRouterImpl {
 	router := &UserRouterImpl{
-		userRestHandler: userRestHandler,
+		userRestHandler:          userRestHandler,
+		rbacSimulatorRestHandler: rbacSimulatorRestHandler,
 	}
 	return router
 }
@@ -73,4 +75,11 @@ func (router UserRouterImpl) InitUserRouter(userAuthRouter *mux.Router) {
 		HandlerFunc(router.userRestHandler.GetRoleCacheDump).Methods("GET")
 	userAuthRouter.Path("/role/cache/invalidate").
 		HandlerFunc(router.userRestHandler.InvalidateRoleCache).Methods("GET")
+
+	//rbac simulator
+	userAuthRouter.Path("/rbac/explain").
+		HandlerFunc(router.rbacSimulatorRestHandler.ExplainPermission).Methods("POST")
+	userAuthRouter.Path("/rbac/permitted-users").
+		Queries("appId", "{appId}", "envId", "{envId}", "action", "{action}").
+		HandlerFunc(router.rbacSimulatorRestHandler.GetPermittedUsers).Methods("GET")
 }
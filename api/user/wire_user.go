@@ -29,12 +29,18 @@ var UserWireSet = wire.NewSet(
 	wire.Bind(new(UserRouter), new(*UserRouterImpl)),
 	NewUserRestHandlerImpl,
 	wire.Bind(new(UserRestHandler), new(*UserRestHandlerImpl)),
+	NewRbacSimulatorRestHandlerImpl,
+	wire.Bind(new(RbacSimulatorRestHandler), new(*RbacSimulatorRestHandlerImpl)),
+	user.NewRbacSimulatorServiceImpl,
+	wire.Bind(new(user.RbacSimulatorService), new(*user.RbacSimulatorServiceImpl)),
 	user.NewUserServiceImpl,
 	wire.Bind(new(user.UserService), new(*user.UserServiceImpl)),
 	repository.NewUserRepositoryImpl,
 	wire.Bind(new(repository.UserRepository), new(*repository.UserRepositoryImpl)),
 	user.NewRoleGroupServiceImpl,
 	wire.Bind(new(user.RoleGroupService), new(*user.RoleGroupServiceImpl)),
+	user.NewRoleGrantExpiryServiceImpl,
+	wire.Bind(new(user.RoleGrantExpiryService), new(*user.RoleGrantExpiryServiceImpl)),
 	repository.NewRoleGroupRepositoryImpl,
 	wire.Bind(new(repository.RoleGroupRepository), new(*repository.RoleGroupRepositoryImpl)),
 
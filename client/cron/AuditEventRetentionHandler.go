@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auditLog"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type AuditEventRetentionHandler interface {
+	PurgeExpiredAuditEvents()
+}
+
+type AuditEventRetentionHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	cron              *cron.Cron
+	auditEventService auditLog.AuditEventService
+}
+
+const AuditEventRetentionCronExpr string = "30 2 * * *"
+
+func NewAuditEventRetentionHandlerImpl(logger *zap.SugaredLogger, auditEventService auditLog.AuditEventService) *AuditEventRetentionHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &AuditEventRetentionHandlerImpl{
+		logger:            logger,
+		cron:              cron,
+		auditEventService: auditEventService,
+	}
+	_, err := cron.AddFunc(AuditEventRetentionCronExpr, impl.PurgeExpiredAuditEvents)
+	if err != nil {
+		logger.Errorw("error in starting audit event retention cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// PurgeExpiredAuditEvents deletes the audit events older than configured retention, once a day
+func (impl *AuditEventRetentionHandlerImpl) PurgeExpiredAuditEvents() {
+	_, err := impl.auditEventService.PurgeExpiredAuditEvents()
+	if err != nil {
+		impl.logger.Errorw("error in purging expired audit events - cron job", "err", err)
+	}
+}
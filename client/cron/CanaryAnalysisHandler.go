@@ -0,0 +1,54 @@
+package cron
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type CanaryAnalysisHandler interface {
+	EvaluateCanaryAnalysis()
+}
+
+type CanaryAnalysisHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	cron                  *cron.Cron
+	canaryAnalysisService pipeline.CanaryAnalysisService
+	workflowDagExecutor   pipeline.WorkflowDagExecutor
+}
+
+const CanaryAnalysisCronExpr string = "*/1 * * * *"
+
+func NewCanaryAnalysisHandlerImpl(logger *zap.SugaredLogger, canaryAnalysisService pipeline.CanaryAnalysisService,
+	workflowDagExecutor pipeline.WorkflowDagExecutor) *CanaryAnalysisHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &CanaryAnalysisHandlerImpl{
+		logger:                logger,
+		cron:                  cron,
+		canaryAnalysisService: canaryAnalysisService,
+		workflowDagExecutor:   workflowDagExecutor,
+	}
+	_, err := cron.AddFunc(CanaryAnalysisCronExpr, impl.EvaluateCanaryAnalysis)
+	if err != nil {
+		logger.Errorw("error in starting canary analysis cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func (impl *CanaryAnalysisHandlerImpl) EvaluateCanaryAnalysis() {
+	runsToRollback, err := impl.canaryAnalysisService.EvaluateDueRuns()
+	if err != nil {
+		impl.logger.Errorw("error in canary analysis - cron job", "err", err)
+		return
+	}
+	for _, run := range runsToRollback {
+		rollbackErr := impl.workflowDagExecutor.RollbackCanaryDeployment(run)
+		err = impl.canaryAnalysisService.MarkRolledBack(run, rollbackErr)
+		if err != nil {
+			impl.logger.Errorw("error in updating canary analysis run after rollback", "err", err, "runId", run.Id)
+		}
+	}
+}
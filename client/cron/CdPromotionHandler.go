@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type CdPromotionHandler interface {
+	TriggerPendingPromotions()
+}
+
+type CdPromotionHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	cron                *cron.Cron
+	workflowDagExecutor pipeline.WorkflowDagExecutor
+}
+
+const CdPromotionCronExpr string = "*/5 * * * *"
+
+func NewCdPromotionHandlerImpl(logger *zap.SugaredLogger, workflowDagExecutor pipeline.WorkflowDagExecutor) *CdPromotionHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &CdPromotionHandlerImpl{
+		logger:              logger,
+		cron:                cron,
+		workflowDagExecutor: workflowDagExecutor,
+	}
+	_, err := cron.AddFunc(CdPromotionCronExpr, impl.TriggerPendingPromotions)
+	if err != nil {
+		logger.Errorw("error in starting cd promotion cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// TriggerPendingPromotions re-evaluates the artifacts held back by promotion policies, e.g. for soak time
+func (impl *CdPromotionHandlerImpl) TriggerPendingPromotions() {
+	err := impl.workflowDagExecutor.TriggerPendingPromotions()
+	if err != nil {
+		impl.logger.Errorw("error in triggering pending promotions - cron job", "err", err)
+	}
+}
@@ -0,0 +1,87 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/security"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type CveExceptionExpiryHandler interface {
+	NotifyExpiringExceptions()
+}
+
+type CveExceptionExpiryConfig struct {
+	// NotifyBeforeDays is the number of days before expiry of a cve exception when its expiry is notified
+	NotifyBeforeDays int `env:"CVE_EXCEPTION_EXPIRY_NOTIFY_BEFORE_DAYS" envDefault:"7"`
+}
+
+type CveExceptionExpiryHandlerImpl struct {
+	logger        *zap.SugaredLogger
+	cron          *cron.Cron
+	config        *CveExceptionExpiryConfig
+	policyService security.PolicyService
+	eventClient   client.EventClient
+	eventFactory  client.EventFactory
+}
+
+const CveExceptionExpiryCronExpr string = "0 * * * *"
+
+func NewCveExceptionExpiryHandlerImpl(logger *zap.SugaredLogger, policyService security.PolicyService,
+	eventClient client.EventClient, eventFactory client.EventFactory) *CveExceptionExpiryHandlerImpl {
+	config := &CveExceptionExpiryConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing cve exception expiry config", "err", err)
+		return nil
+	}
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &CveExceptionExpiryHandlerImpl{
+		logger:        logger,
+		cron:          cron,
+		config:        config,
+		policyService: policyService,
+		eventClient:   eventClient,
+		eventFactory:  eventFactory,
+	}
+	_, err = cron.AddFunc(CveExceptionExpiryCronExpr, impl.NotifyExpiringExceptions)
+	if err != nil {
+		logger.Errorw("error in starting cve exception expiry cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// NotifyExpiringExceptions sends an event to notifier once for every exception expiring in configured days
+func (impl *CveExceptionExpiryHandlerImpl) NotifyExpiringExceptions() {
+	exceptions, err := impl.policyService.GetExpiringExceptions(time.Now().AddDate(0, 0, impl.config.NotifyBeforeDays))
+	if err != nil {
+		impl.logger.Errorw("error in fetching expiring cve exceptions - cron job", "err", err)
+		return
+	}
+	var notifiedIds []int
+	for _, exception := range exceptions {
+		if exception.ExpiryNotified {
+			continue
+		}
+		envId := exception.EnvId
+		event := impl.eventFactory.Build(util.CveExceptionExpiry, nil, exception.AppId, &envId, util.CD)
+		event = impl.eventFactory.BuildExtraCveExceptionData(event, exception)
+		_, err = impl.eventClient.WriteEvent(event)
+		if err != nil {
+			impl.logger.Errorw("error in sending cve exception expiry event", "err", err, "exceptionId", exception.Id)
+			continue
+		}
+		notifiedIds = append(notifiedIds, exception.Id)
+	}
+	err = impl.policyService.MarkExceptionsExpiryNotified(notifiedIds)
+	if err != nil {
+		impl.logger.Errorw("error in marking cve exceptions expiry notified - cron job", "err", err)
+	}
+}
@@ -0,0 +1,61 @@
+package cron
+
+import (
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/security"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type DeployedImageRescanHandler interface {
+	RescanDeployedImages()
+}
+
+type DeployedImageRescanHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	cron               *cron.Cron
+	imageRescanService security.ImageRescanService
+	eventClient        client.EventClient
+	eventFactory       client.EventFactory
+}
+
+const DeployedImageRescanCronExpr string = "0 */6 * * *"
+
+func NewDeployedImageRescanHandlerImpl(logger *zap.SugaredLogger, imageRescanService security.ImageRescanService,
+	eventClient client.EventClient, eventFactory client.EventFactory) *DeployedImageRescanHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &DeployedImageRescanHandlerImpl{
+		logger:             logger,
+		cron:               cron,
+		imageRescanService: imageRescanService,
+		eventClient:        eventClient,
+		eventFactory:       eventFactory,
+	}
+	_, err := cron.AddFunc(DeployedImageRescanCronExpr, impl.RescanDeployedImages)
+	if err != nil {
+		logger.Errorw("error in starting deployed image rescan cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// RescanDeployedImages notifies the cves blocked by policy which are found in images running in production environments
+func (impl *DeployedImageRescanHandlerImpl) RescanDeployedImages() {
+	findings, err := impl.imageRescanService.RescanDeployedImages()
+	if err != nil {
+		impl.logger.Errorw("error in rescanning deployed images - cron job", "err", err)
+		return
+	}
+	for _, finding := range findings {
+		envId := finding.EnvId
+		event := impl.eventFactory.Build(util.BlockedCveDetected, nil, finding.AppId, &envId, util.CD)
+		event = impl.eventFactory.BuildExtraDetectedCveData(event, finding)
+		_, err = impl.eventClient.WriteEvent(event)
+		if err != nil {
+			impl.logger.Errorw("error in sending blocked cve detected event", "err", err, "imageScanDeployInfoId", finding.ImageScanDeployInfoId)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package cron
+
+import (
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/gitops"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type GitOpsDriftHandler interface {
+	DetectGitOpsDrifts()
+}
+
+type GitOpsDriftHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	cron               *cron.Cron
+	gitOpsDriftService gitops.GitOpsDriftService
+	eventClient        client.EventClient
+	eventFactory       client.EventFactory
+}
+
+const GitOpsDriftCronExpr string = "0 * * * *"
+
+func NewGitOpsDriftHandlerImpl(logger *zap.SugaredLogger, gitOpsDriftService gitops.GitOpsDriftService,
+	eventClient client.EventClient, eventFactory client.EventFactory) *GitOpsDriftHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &GitOpsDriftHandlerImpl{
+		logger:             logger,
+		cron:               cron,
+		gitOpsDriftService: gitOpsDriftService,
+		eventClient:        eventClient,
+		eventFactory:       eventFactory,
+	}
+	_, err := cron.AddFunc(GitOpsDriftCronExpr, impl.DetectGitOpsDrifts)
+	if err != nil {
+		logger.Errorw("error in starting gitops drift cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// DetectGitOpsDrifts notifies the deployments which drifted from the state deployed by devtron since last check
+func (impl *GitOpsDriftHandlerImpl) DetectGitOpsDrifts() {
+	reports, err := impl.gitOpsDriftService.DetectDrifts()
+	if err != nil {
+		impl.logger.Errorw("error in detecting gitops drifts - cron job", "err", err)
+		return
+	}
+	for _, report := range reports {
+		pipelineId := report.PipelineId
+		envId := report.EnvId
+		event := impl.eventFactory.Build(util.GitOpsDriftDetected, &pipelineId, report.AppId, &envId, util.CD)
+		event = impl.eventFactory.BuildExtraGitOpsDriftData(event, report)
+		_, err = impl.eventClient.WriteEvent(event)
+		if err != nil {
+			impl.logger.Errorw("error in sending gitops drift detected event", "err", err, "pipelineId", report.PipelineId)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type GitOpsPullRequestHandler interface {
+	SyncGitOpsPullRequests()
+}
+
+type GitOpsPullRequestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	cron                     *cron.Cron
+	gitOpsPullRequestService app.GitOpsPullRequestService
+}
+
+const GitOpsPullRequestSyncCronExpr string = "*/2 * * * *"
+
+func NewGitOpsPullRequestHandlerImpl(logger *zap.SugaredLogger, gitOpsPullRequestService app.GitOpsPullRequestService) *GitOpsPullRequestHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &GitOpsPullRequestHandlerImpl{
+		logger:                   logger,
+		cron:                     cron,
+		gitOpsPullRequestService: gitOpsPullRequestService,
+	}
+	_, err := cron.AddFunc(GitOpsPullRequestSyncCronExpr, impl.SyncGitOpsPullRequests)
+	if err != nil {
+		logger.Errorw("error in starting gitops pull request sync cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// SyncGitOpsPullRequests deploys the releases whose gitops pull request got merged
+func (impl *GitOpsPullRequestHandlerImpl) SyncGitOpsPullRequests() {
+	err := impl.gitOpsPullRequestService.SyncPullRequests()
+	if err != nil {
+		impl.logger.Errorw("error in syncing gitops pull requests - cron job", "err", err)
+	}
+}
@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"github.com/devtron-labs/devtron/pkg/chartRepo"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type OCIChartSyncHandler interface {
+	SyncOCICharts()
+}
+
+type OCIChartSyncHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	cron                *cron.Cron
+	ociChartSyncService chartRepo.OCIChartSyncService
+}
+
+const OCIChartSyncCronExpr string = "*/30 * * * *"
+
+func NewOCIChartSyncHandlerImpl(logger *zap.SugaredLogger, ociChartSyncService chartRepo.OCIChartSyncService) *OCIChartSyncHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &OCIChartSyncHandlerImpl{
+		logger:              logger,
+		cron:                cron,
+		ociChartSyncService: ociChartSyncService,
+	}
+	_, err := cron.AddFunc(OCIChartSyncCronExpr, impl.SyncOCICharts)
+	if err != nil {
+		logger.Errorw("error in starting oci chart sync cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// SyncOCICharts adds charts pushed to oci registries to app store, index.yaml repos are synced by the chart sync job
+func (impl *OCIChartSyncHandlerImpl) SyncOCICharts() {
+	err := impl.ociChartSyncService.SyncOCIChartRepos()
+	if err != nil {
+		impl.logger.Errorw("error in syncing oci charts - cron job", "err", err)
+	}
+}
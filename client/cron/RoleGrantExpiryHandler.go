@@ -0,0 +1,97 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/caarlos0/env"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/user"
+	util "github.com/devtron-labs/devtron/util/event"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type RoleGrantExpiryHandler interface {
+	NotifyExpiringRoleGrants()
+	RevokeExpiredRoleGrants()
+}
+
+type RoleGrantExpiryConfig struct {
+	// NotifyBeforeHours is the number of hours before expiry of a time-bound role when its expiry is notified
+	NotifyBeforeHours int `env:"ROLE_GRANT_EXPIRY_NOTIFY_BEFORE_HOURS" envDefault:"24"`
+}
+
+type RoleGrantExpiryHandlerImpl struct {
+	logger                 *zap.SugaredLogger
+	cron                   *cron.Cron
+	config                 *RoleGrantExpiryConfig
+	roleGrantExpiryService user.RoleGrantExpiryService
+	eventClient            client.EventClient
+	eventFactory           client.EventFactory
+}
+
+const RoleGrantExpiryNotifyCronExpr string = "*/15 * * * *"
+const RoleGrantExpiryRevokeCronExpr string = "* * * * *"
+
+func NewRoleGrantExpiryHandlerImpl(logger *zap.SugaredLogger, roleGrantExpiryService user.RoleGrantExpiryService,
+	eventClient client.EventClient, eventFactory client.EventFactory) *RoleGrantExpiryHandlerImpl {
+	config := &RoleGrantExpiryConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing role grant expiry config", "err", err)
+		return nil
+	}
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &RoleGrantExpiryHandlerImpl{
+		logger:                 logger,
+		cron:                   cron,
+		config:                 config,
+		roleGrantExpiryService: roleGrantExpiryService,
+		eventClient:            eventClient,
+		eventFactory:           eventFactory,
+	}
+	_, err = cron.AddFunc(RoleGrantExpiryNotifyCronExpr, impl.NotifyExpiringRoleGrants)
+	if err != nil {
+		logger.Errorw("error in starting role grant expiry notify cron job", "err", err)
+		return nil
+	}
+	_, err = cron.AddFunc(RoleGrantExpiryRevokeCronExpr, impl.RevokeExpiredRoleGrants)
+	if err != nil {
+		logger.Errorw("error in starting role grant expiry revoke cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// NotifyExpiringRoleGrants sends an event to notifier once for every time-bound role expiring in configured hours
+func (impl *RoleGrantExpiryHandlerImpl) NotifyExpiringRoleGrants() {
+	expiresBefore := time.Now().Add(time.Duration(impl.config.NotifyBeforeHours) * time.Hour)
+	grants, err := impl.roleGrantExpiryService.GetExpiringRoleGrants(expiresBefore)
+	if err != nil {
+		impl.logger.Errorw("error in fetching expiring role grants - cron job", "err", err)
+		return
+	}
+	for _, grant := range grants {
+		event := impl.eventFactory.Build(util.RoleGrantExpiry, nil, 0, nil, "")
+		event = impl.eventFactory.BuildExtraRoleGrantExpiryData(event, grant)
+		_, err = impl.eventClient.WriteEvent(event)
+		if err != nil {
+			impl.logger.Errorw("error in sending role grant expiry event", "err", err, "grant", grant)
+		}
+	}
+	// grants are marked even if sending fails, so that a failing notifier does not spam the users on every run
+	err = impl.roleGrantExpiryService.MarkRoleGrantsExpiryNotified(grants)
+	if err != nil {
+		impl.logger.Errorw("error in marking role grants expiry notified - cron job", "err", err)
+	}
+}
+
+// RevokeExpiredRoleGrants removes the casbin policies of expired time-bound roles
+func (impl *RoleGrantExpiryHandlerImpl) RevokeExpiredRoleGrants() {
+	err := impl.roleGrantExpiryService.RevokeExpiredRoleGrants()
+	if err != nil {
+		impl.logger.Errorw("error in revoking expired role grants - cron job", "err", err)
+	}
+}
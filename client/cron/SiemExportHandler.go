@@ -0,0 +1,52 @@
+package cron
+
+import (
+	"fmt"
+
+	"github.com/devtron-labs/devtron/pkg/auditLog/siem"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type SiemExportHandler interface {
+	FlushSiemEvents()
+}
+
+type SiemExportHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	cron              *cron.Cron
+	siemExportService siem.SiemExportService
+}
+
+func NewSiemExportHandlerImpl(logger *zap.SugaredLogger, siemExportService siem.SiemExportService) *SiemExportHandlerImpl {
+	cron := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	cron.Start()
+	impl := &SiemExportHandlerImpl{
+		logger:            logger,
+		cron:              cron,
+		siemExportService: siemExportService,
+	}
+	config := siemExportService.GetConfig()
+	if !config.Enabled {
+		return impl
+	}
+	interval := config.FlushIntervalSeconds
+	if interval <= 0 {
+		interval = 10
+	}
+	_, err := cron.AddFunc(fmt.Sprintf("@every %ds", interval), impl.FlushSiemEvents)
+	if err != nil {
+		logger.Errorw("error in starting siem export cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+// FlushSiemEvents delivers the buffered audit events to SIEM, a flush still retrying a batch skips the next runs
+func (impl *SiemExportHandlerImpl) FlushSiemEvents() {
+	err := impl.siemExportService.Flush()
+	if err != nil {
+		impl.logger.Errorw("error in flushing siem events - cron job", "err", err)
+	}
+}
@@ -20,8 +20,10 @@ package client
 import (
 	"fmt"
 	bean2 "github.com/devtron-labs/devtron/api/bean"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/notifier"
 	"github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/devtron-labs/devtron/util/event"
 	"github.com/satori/go.uuid"
@@ -34,6 +36,15 @@ type EventFactory interface {
 	Build(eventType util.EventType, sourceId *int, appId int, envId *int, pipelineType util.PipelineType) Event
 	BuildExtraCDData(event Event, wfr *pipelineConfig.CdWorkflowRunner, pipelineOverrideId int, stage bean2.WorkflowType) Event
 	BuildExtraCIData(event Event, material *MaterialTriggerInfo, dockerImage string) Event
+	// BuildEventForWorkflow builds the event of a past ci workflow or cd workflow runner as it would have been sent to
+	// notifier, used for previewing notification templates
+	BuildEventForWorkflow(eventType util.EventType, pipelineType util.PipelineType, workflowId int) (Event, error)
+	BuildExtraCveExceptionData(event Event, exception *bean2.CveExceptionDetail) Event
+	BuildExtraDetectedCveData(event Event, finding *bean2.DeployedImageCveFinding) Event
+	// BuildExtraRoleGrantExpiryData addresses the event to the users losing the role through default email config
+	BuildExtraRoleGrantExpiryData(event Event, grant *bean2.RoleGrantExpiry) Event
+	BuildExtraGitOpsDriftData(event Event, report *bean2.GitOpsDriftReport) Event
+	BuildExtraDeploymentWindowOverrideData(event Event, override *bean2.DeploymentWindowOverride) Event
 	//BuildFinalData(event Event) *Payload
 }
 
@@ -46,13 +57,16 @@ type EventSimpleFactoryImpl struct {
 	ciPipelineRepository         pipelineConfig.CiPipelineRepository
 	pipelineRepository           pipelineConfig.PipelineRepository
 	userRepository               repository.UserRepository
+	sesNotificationRepository    repository2.SESNotificationRepository
+	smtpNotificationRepository   repository2.SMTPNotificationRepository
 }
 
 func NewEventSimpleFactoryImpl(logger *zap.SugaredLogger, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
 	pipelineOverrideRepository chartConfig.PipelineOverrideRepository, ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
 	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
 	ciPipelineRepository pipelineConfig.CiPipelineRepository, pipelineRepository pipelineConfig.PipelineRepository,
-	userRepository repository.UserRepository) *EventSimpleFactoryImpl {
+	userRepository repository.UserRepository, sesNotificationRepository repository2.SESNotificationRepository,
+	smtpNotificationRepository repository2.SMTPNotificationRepository) *EventSimpleFactoryImpl {
 	return &EventSimpleFactoryImpl{
 		logger:                       logger,
 		cdWorkflowRepository:         cdWorkflowRepository,
@@ -62,6 +76,8 @@ func NewEventSimpleFactoryImpl(logger *zap.SugaredLogger, cdWorkflowRepository p
 		ciPipelineRepository:         ciPipelineRepository,
 		pipelineRepository:           pipelineRepository,
 		userRepository:               userRepository,
+		sesNotificationRepository:    sesNotificationRepository,
+		smtpNotificationRepository:   smtpNotificationRepository,
 	}
 }
 
@@ -82,6 +98,134 @@ func (impl *EventSimpleFactoryImpl) Build(eventType util.EventType, sourceId *in
 	return event
 }
 
+func (impl *EventSimpleFactoryImpl) BuildExtraCveExceptionData(event Event, exception *bean2.CveExceptionDetail) Event {
+	target := "all clusters"
+	if exception.AppId > 0 {
+		target = fmt.Sprintf("%s/%s", exception.AppName, exception.EnvName)
+	} else if exception.EnvId > 0 {
+		target = exception.EnvName
+	} else if exception.ClusterId > 0 {
+		target = exception.ClusterName
+	}
+	expiresOn := exception.ExpiresOn.Format(time.RFC1123)
+	event.TeamId = exception.TeamId
+	event.Payload = &Payload{
+		AppName: exception.AppName,
+		EnvName: exception.EnvName,
+		Message: fmt.Sprintf("CVE exception expiring: %s allowed for %s until %s", exception.CveName, target, expiresOn),
+		CveException: &CveExceptionPayload{
+			CveName:       exception.CveName,
+			Severity:      exception.Severity,
+			ClusterName:   exception.ClusterName,
+			Justification: exception.Justification,
+			ApprovedBy:    exception.ApprovedBy,
+			ExpiresOn:     expiresOn,
+		},
+	}
+	return event
+}
+
+func (impl *EventSimpleFactoryImpl) BuildExtraDetectedCveData(event Event, finding *bean2.DeployedImageCveFinding) Event {
+	event.TeamId = finding.TeamId
+	event.Payload = &Payload{
+		AppName:        finding.AppName,
+		EnvName:        finding.EnvName,
+		DockerImageUrl: finding.Image,
+		AppDetailLink:  fmt.Sprintf("/dashboard/app/%d/details/%d/pod", finding.AppId, finding.EnvId),
+		Message: fmt.Sprintf("Blocked CVEs detected in running image of %s/%s: %s", finding.AppName, finding.EnvName,
+			strings.Join(finding.BlockedCves, ", ")),
+		DetectedCves: &DetectedCvePayload{
+			Image:    finding.Image,
+			CveNames: finding.BlockedCves,
+		},
+	}
+	return event
+}
+
+func (impl *EventSimpleFactoryImpl) BuildExtraRoleGrantExpiryData(event Event, grant *bean2.RoleGrantExpiry) Event {
+	expiresOn := grant.ExpiresOn.Format(time.RFC1123)
+	message := fmt.Sprintf("Role expiring: %s granted to you until %s", grant.Role.Role, expiresOn)
+	if len(grant.RoleGroupName) > 0 {
+		message = fmt.Sprintf("Role expiring: %s granted to your group %s until %s", grant.Role.Role, grant.RoleGroupName, expiresOn)
+	}
+	event.Payload = &Payload{
+		AppName: grant.Role.EntityName,
+		EnvName: grant.Role.Environment,
+		Message: message,
+		RoleGrant: &RoleGrantPayload{
+			Role:          grant.Role.Role,
+			RoleGroupName: grant.RoleGroupName,
+			ExpiresOn:     expiresOn,
+		},
+	}
+	destination, configId := impl.getDefaultEmailConfig()
+	if configId == 0 {
+		impl.logger.Warnw("no default email config found, role grant expiry is sent only as per notification settings", "role", grant.Role.Role)
+		return event
+	}
+	for _, emailId := range grant.EmailIds {
+		event.Payload.Providers = append(event.Payload.Providers, &notifier.Provider{Destination: destination, ConfigId: configId, Recipient: emailId})
+	}
+	return event
+}
+
+func (impl *EventSimpleFactoryImpl) BuildExtraGitOpsDriftData(event Event, report *bean2.GitOpsDriftReport) Event {
+	var drifts, driftedResources []string
+	if report.GitDrift {
+		drifts = append(drifts, "gitops repo edited outside devtron")
+	}
+	if report.LiveDrift {
+		drifts = append(drifts, "live state differs from gitops repo")
+	}
+	for _, resource := range report.DriftedResources {
+		driftedResources = append(driftedResources, fmt.Sprintf("%s/%s", resource.Kind, resource.Name))
+	}
+	event.TeamId = report.TeamId
+	event.Payload = &Payload{
+		AppName:       report.AppName,
+		EnvName:       report.EnvName,
+		AppDetailLink: fmt.Sprintf("/dashboard/app/%d/details/%d/pod", report.AppId, report.EnvId),
+		Message:       fmt.Sprintf("GitOps drift detected in %s/%s: %s", report.AppName, report.EnvName, strings.Join(drifts, ", ")),
+		GitOpsDrift: &GitOpsDriftPayload{
+			GitDrift:         report.GitDrift,
+			GitDriftPaths:    report.GitDriftPaths,
+			LiveDrift:        report.LiveDrift,
+			DriftedResources: driftedResources,
+		},
+	}
+	return event
+}
+
+func (impl *EventSimpleFactoryImpl) BuildExtraDeploymentWindowOverrideData(event Event, override *bean2.DeploymentWindowOverride) Event {
+	event.TeamId = override.TeamId
+	event.Payload = &Payload{
+		AppName:       override.AppName,
+		EnvName:       override.EnvName,
+		AppDetailLink: fmt.Sprintf("/dashboard/app/%d/details/%d/pod", override.AppId, override.EnvId),
+		Message: fmt.Sprintf("Deployment freeze %s overridden by %s for %s/%s: %s", strings.Join(override.WindowNames, ", "),
+			override.OverriddenBy, override.AppName, override.EnvName, override.Reason),
+		DeploymentWindowOverride: &DeploymentWindowOverridePayload{
+			WindowNames:  override.WindowNames,
+			OverriddenBy: override.OverriddenBy,
+			Reason:       override.Reason,
+		},
+	}
+	return event
+}
+
+// getDefaultEmailConfig returns the default smtp config, or the default ses config if smtp is not configured
+func (impl *EventSimpleFactoryImpl) getDefaultEmailConfig() (util.Channel, int) {
+	smtpConfig, err := impl.smtpNotificationRepository.FindDefault()
+	if err == nil && smtpConfig.Id > 0 {
+		return util.SMTP, smtpConfig.Id
+	}
+	sesConfig, err := impl.sesNotificationRepository.FindDefault()
+	if err == nil && sesConfig.Id > 0 {
+		return util.SES, sesConfig.Id
+	}
+	return "", 0
+}
+
 func (impl *EventSimpleFactoryImpl) BuildExtraCDData(event Event, wfr *pipelineConfig.CdWorkflowRunner, pipelineOverrideId int, stage bean2.WorkflowType) Event {
 	//event.CdWorkflowRunnerId =
 	event.CdWorkflowType = stage
@@ -228,3 +372,38 @@ func (impl *EventSimpleFactoryImpl) getCiMaterialInfo(ciPipelineId int, ciArtifa
 	}
 	return materialTriggerInfo, nil
 }
+
+func (impl *EventSimpleFactoryImpl) BuildEventForWorkflow(eventType util.EventType, pipelineType util.PipelineType, workflowId int) (Event, error) {
+	var event Event
+	if pipelineType == util.CI {
+		ciWorkflow, err := impl.ciWorkflowRepository.FindById(workflowId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching ci workflow", "err", err, "workflowId", workflowId)
+			return event, err
+		}
+		event = impl.Build(eventType, &ciWorkflow.CiPipelineId, ciWorkflow.CiPipeline.AppId, nil, pipelineType)
+		event.CiWorkflowRunnerId = ciWorkflow.Id
+		event.UserId = int(ciWorkflow.TriggeredBy)
+		event.TeamId = ciWorkflow.CiPipeline.App.TeamId
+		event = impl.BuildExtraCIData(event, &MaterialTriggerInfo{GitTriggers: ciWorkflow.GitTriggers}, "")
+		event.Payload = buildFinalPayload(event, nil, ciWorkflow.CiPipeline)
+	} else if pipelineType == util.CD {
+		wfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(workflowId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching cd workflow runner", "err", err, "workflowId", workflowId)
+			return event, err
+		}
+		pipeline, err := impl.pipelineRepository.FindById(wfr.CdWorkflow.PipelineId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching cd pipeline", "err", err, "pipelineId", wfr.CdWorkflow.PipelineId)
+			return event, err
+		}
+		event = impl.Build(eventType, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, pipelineType)
+		event.TeamId = pipeline.App.TeamId
+		event = impl.BuildExtraCDData(event, wfr, 0, wfr.WorkflowType)
+		event.Payload = buildFinalPayload(event, pipeline, nil)
+	} else {
+		return event, fmt.Errorf("unsupported pipeline type %s", pipelineType)
+	}
+	return event, nil
+}
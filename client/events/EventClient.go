@@ -32,6 +32,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/pkg/attributes"
+	"github.com/devtron-labs/devtron/pkg/notifier"
 	util1 "github.com/devtron-labs/devtron/util"
 	util "github.com/devtron-labs/devtron/util/event"
 	"github.com/nats-io/nats.go"
@@ -41,6 +42,10 @@ import (
 type EventClientConfig struct {
 	DestinationURL string `env:"EVENT_URL" envDefault:"http://localhost:3000/notify"`
 	TestSuitURL    string `env:"TEST_SUIT_URL" envDefault:"http://localhost:3000"`
+	// NotificationDispatchWorkers deliver events to channels of orchestrator, i.e. teams, webhook and pagerduty,
+	// events are dropped when NotificationDispatchQueueSize events are already waiting
+	NotificationDispatchWorkers   int `env:"NOTIFICATION_DISPATCH_WORKERS" envDefault:"4"`
+	NotificationDispatchQueueSize int `env:"NOTIFICATION_DISPATCH_QUEUE_SIZE" envDefault:"1000"`
 }
 
 func GetEventClientConfig() (*EventClientConfig, error) {
@@ -75,6 +80,8 @@ type Event struct {
 	CiArtifactId       int               `json:"ciArtifactId"`
 	BaseUrl            string            `json:"baseUrl"`
 	UserId             int               `json:"-"`
+	// CustomMessages are messages of channels rendered from notification templates configured for the event
+	CustomMessages map[util.Channel]string `json:"customMessages,omitempty"`
 }
 
 type Payload struct {
@@ -90,6 +97,52 @@ type Payload struct {
 	DownloadLink          string               `json:"downloadLink"`
 	BuildHistoryLink      string               `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *MaterialTriggerInfo `json:"material"`
+	// Message describes events not related to a pipeline run
+	Message      string               `json:"message,omitempty"`
+	CveException *CveExceptionPayload `json:"cveException,omitempty"`
+	DetectedCves *DetectedCvePayload  `json:"detectedCves,omitempty"`
+	RoleGrant    *RoleGrantPayload    `json:"roleGrant,omitempty"`
+	GitOpsDrift  *GitOpsDriftPayload  `json:"gitOpsDrift,omitempty"`
+	// DeploymentWindowOverride describes the freeze windows a super admin deployed through
+	DeploymentWindowOverride *DeploymentWindowOverridePayload `json:"deploymentWindowOverride,omitempty"`
+	// Providers are the recipients of events which are sent to users directly instead of notification settings
+	Providers []*notifier.Provider `json:"providers,omitempty"`
+}
+
+type CveExceptionPayload struct {
+	CveName       string `json:"cveName"`
+	Severity      string `json:"severity"`
+	ClusterName   string `json:"clusterName,omitempty"`
+	Justification string `json:"justification"`
+	ApprovedBy    string `json:"approvedBy"`
+	ExpiresOn     string `json:"expiresOn"`
+}
+
+// RoleGrantPayload is the time-bound role of a user or role group which is expiring
+type RoleGrantPayload struct {
+	Role          string `json:"role"`
+	RoleGroupName string `json:"roleGroupName,omitempty"`
+	ExpiresOn     string `json:"expiresOn"`
+}
+
+// DetectedCvePayload lists the cves found by rescanning an image already running in an environment
+type DetectedCvePayload struct {
+	Image    string   `json:"image"`
+	CveNames []string `json:"cveNames"`
+}
+
+// GitOpsDriftPayload describes how a deployment drifted from the state deployed by devtron
+type GitOpsDriftPayload struct {
+	GitDrift         bool     `json:"gitDrift"`
+	GitDriftPaths    []string `json:"gitDriftPaths,omitempty"`
+	LiveDrift        bool     `json:"liveDrift"`
+	DriftedResources []string `json:"driftedResources,omitempty"`
+}
+
+type DeploymentWindowOverridePayload struct {
+	WindowNames  []string `json:"windowNames"`
+	OverriddenBy string   `json:"overriddenBy"`
+	Reason       string   `json:"reason"`
 }
 
 type CiPipelineMaterialResponse struct {
@@ -122,21 +175,37 @@ type EventRESTClientImpl struct {
 	ciPipelineRepository pipelineConfig.CiPipelineRepository
 	pipelineRepository   pipelineConfig.PipelineRepository
 	attributesRepository repository.AttributesRepository
+	channelRegistry      notifier.NotificationChannelRegistry
+	templateService      notifier.NotificationTemplateService
+	dispatchQueue        chan *notifier.NotificationChannelEvent
 }
 
 func NewEventRESTClientImpl(logger *zap.SugaredLogger, client *http.Client, config *EventClientConfig, pubsubClient *pubsub.PubSubClient,
 	ciPipelineRepository pipelineConfig.CiPipelineRepository, pipelineRepository pipelineConfig.PipelineRepository,
-	attributesRepository repository.AttributesRepository) *EventRESTClientImpl {
-	return &EventRESTClientImpl{logger: logger, client: client, config: config, pubsubClient: pubsubClient,
+	attributesRepository repository.AttributesRepository, channelRegistry notifier.NotificationChannelRegistry,
+	templateService notifier.NotificationTemplateService) *EventRESTClientImpl {
+	impl := &EventRESTClientImpl{logger: logger, client: client, config: config, pubsubClient: pubsubClient,
 		ciPipelineRepository: ciPipelineRepository, pipelineRepository: pipelineRepository,
-		attributesRepository: attributesRepository}
+		attributesRepository: attributesRepository, channelRegistry: channelRegistry, templateService: templateService,
+		dispatchQueue: make(chan *notifier.NotificationChannelEvent, config.NotificationDispatchQueueSize)}
+	for i := 0; i < config.NotificationDispatchWorkers; i++ {
+		go impl.dispatchNotificationChannelEvents()
+	}
+	return impl
 }
 
-func (impl *EventRESTClientImpl) buildFinalPayload(event Event, cdPipeline *pipelineConfig.Pipeline, ciPipeline *pipelineConfig.CiPipeline) *Payload {
+func buildFinalPayload(event Event, cdPipeline *pipelineConfig.Pipeline, ciPipeline *pipelineConfig.CiPipeline) *Payload {
 	payload := event.Payload
 	if payload == nil {
 		payload = &Payload{}
 	}
+	if event.EventTypeId == int(util.CveExceptionExpiry) || event.EventTypeId == int(util.BlockedCveDetected) ||
+		event.EventTypeId == int(util.RoleGrantExpiry) || event.EventTypeId == int(util.GitOpsDriftDetected) ||
+		event.EventTypeId == int(util.DeploymentWindowOverridden) {
+		// payload of security, role grant, drift and freeze override events is complete as built, they are not
+		// related to a pipeline run
+		return payload
+	}
 	if event.PipelineType == string(util.CD) {
 		if cdPipeline != nil {
 			payload.AppName = cdPipeline.App.AppName
@@ -185,7 +254,7 @@ func (impl *EventRESTClientImpl) WriteEvent(event Event) (bool, error) {
 		}
 	}
 
-	payload := impl.buildFinalPayload(event, cdPipeline, ciPipeline)
+	payload := buildFinalPayload(event, cdPipeline, ciPipeline)
 	event.Payload = payload
 
 	isPreStageExist := false
@@ -232,12 +301,19 @@ func (impl *EventRESTClientImpl) WriteEvent(event Event) (bool, error) {
 }
 
 func (impl *EventRESTClientImpl) SendEvent(event Event) (bool, error) {
+	event.CustomMessages = impl.templateService.RenderTemplates(event.EventTypeId, event.PipelineType, event)
 	impl.logger.Debugw("event before send", "event", event)
 	body, err := json.Marshal(event)
 	if err != nil {
 		impl.logger.Errorw("error while marshaling event request ", "err", err)
 		return false, err
 	}
+	// channels delivered by orchestrator itself are not known to notifier, they are dispatched independent of it
+	select {
+	case impl.dispatchQueue <- impl.buildNotificationChannelEvent(event):
+	default:
+		impl.logger.Errorw("notification dispatch queue is full, event not sent to orchestrator channels", "eventTypeId", event.EventTypeId, "pipelineId", event.PipelineId)
+	}
 	var reqBody = []byte(body)
 	req, err := http.NewRequest(http.MethodPost, impl.config.DestinationURL, bytes.NewBuffer(reqBody))
 	if err != nil {
@@ -254,6 +330,59 @@ func (impl *EventRESTClientImpl) SendEvent(event Event) (bool, error) {
 	return true, err
 }
 
+func (impl *EventRESTClientImpl) dispatchNotificationChannelEvents() {
+	for channelEvent := range impl.dispatchQueue {
+		impl.dispatchNotificationChannelEvent(channelEvent)
+	}
+}
+
+// dispatchNotificationChannelEvent recovers from panic in delivery of an event so that the worker keeps dispatching
+func (impl *EventRESTClientImpl) dispatchNotificationChannelEvent(channelEvent *notifier.NotificationChannelEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			impl.logger.Errorw("panic in dispatching event to notification channels", "panic", r, "eventTypeId", channelEvent.EventTypeId, "pipelineId", channelEvent.PipelineId)
+		}
+	}()
+	impl.channelRegistry.DispatchEvent(channelEvent)
+}
+
+func (impl *EventRESTClientImpl) buildNotificationChannelEvent(event Event) *notifier.NotificationChannelEvent {
+	channelEvent := &notifier.NotificationChannelEvent{
+		EventTypeId:    event.EventTypeId,
+		EventType:      notifier.GetEventTypeName(event.EventTypeId),
+		PipelineType:   event.PipelineType,
+		PipelineId:     event.PipelineId,
+		TeamId:         event.TeamId,
+		AppId:          event.AppId,
+		EnvId:          event.EnvId,
+		EventTime:      event.EventTime,
+		CustomMessages: event.CustomMessages,
+	}
+	if event.Payload != nil {
+		channelEvent.AppName = event.Payload.AppName
+		channelEvent.EnvName = event.Payload.EnvName
+		channelEvent.PipelineName = event.Payload.PipelineName
+		channelEvent.Stage = event.Payload.Stage
+		channelEvent.TriggeredBy = event.Payload.TriggeredBy
+		channelEvent.Message = event.Payload.Message
+		link := event.Payload.BuildHistoryLink
+		if event.PipelineType == string(util.CD) {
+			link = event.Payload.DeploymentHistoryLink
+		}
+		if len(link) > 0 {
+			channelEvent.Link = event.BaseUrl + link
+		}
+	}
+	eventJson, err := json.Marshal(event)
+	if err == nil {
+		err = json.Unmarshal(eventJson, &channelEvent.Event)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in converting event for notification channels", "err", err)
+	}
+	return channelEvent
+}
+
 func (impl *EventRESTClientImpl) WriteNatsEvent(topic string, payload interface{}) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/notifier"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeChannelRegistry struct {
+	notifier.NotificationChannelRegistry
+	dispatched chan *notifier.NotificationChannelEvent
+}
+
+func (f *fakeChannelRegistry) DispatchEvent(event *notifier.NotificationChannelEvent) {
+	if event.PipelineId == 0 {
+		panic("pipeline not found")
+	}
+	f.dispatched <- event
+}
+
+func TestEventRESTClient_dispatchNotificationChannelEvents(t *testing.T) {
+	channelRegistry := &fakeChannelRegistry{dispatched: make(chan *notifier.NotificationChannelEvent, 1)}
+	config := &EventClientConfig{NotificationDispatchWorkers: 1, NotificationDispatchQueueSize: 2}
+	impl := NewEventRESTClientImpl(zap.NewNop().Sugar(), nil, config, nil, nil, nil, nil, channelRegistry, nil)
+
+	// worker keeps dispatching after delivery of an event panics
+	impl.dispatchQueue <- &notifier.NotificationChannelEvent{}
+	impl.dispatchQueue <- &notifier.NotificationChannelEvent{PipelineId: 2}
+	event := <-channelRegistry.dispatched
+	assert.Equal(t, 2, event.PipelineId)
+}
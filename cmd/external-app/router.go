@@ -6,6 +6,7 @@ import (
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
@@ -13,11 +14,13 @@ import (
 	client "github.com/devtron-labs/devtron/api/helm-app"
 	"github.com/devtron-labs/devtron/api/module"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/api/scim"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sso"
 	"github.com/devtron-labs/devtron/api/team"
 	"github.com/devtron-labs/devtron/api/user"
 	webhookHelm "github.com/devtron-labs/devtron/api/webhook/helm"
+	"github.com/devtron-labs/devtron/client/cron"
 	"github.com/devtron-labs/devtron/client/dashboard"
 	"github.com/devtron-labs/devtron/util"
 	"github.com/devtron-labs/devtron/util/k8s"
@@ -50,6 +53,13 @@ type MuxRouter struct {
 	apiTokenRouter           apiToken.ApiTokenRouter
 	k8sCapacityRouter        k8s.K8sCapacityRouter
 	webhookHelmRouter        webhookHelm.WebhookHelmRouter
+	apiTokenScopeMiddleware  apiToken.ApiTokenScopeMiddleware
+	scimRouter               scim.ScimRouter
+	auditEventRouter         auditLog.AuditEventRouter
+	auditRequestIdMiddleware auditLog.AuditRequestIdMiddleware
+	auditEventRetention      cron.AuditEventRetentionHandler
+	siemExport               cron.SiemExportHandler
+	ociChartSync             cron.OCIChartSyncHandler
 }
 
 func NewMuxRouter(
@@ -74,6 +84,13 @@ func NewMuxRouter(
 	serverRouter server.ServerRouter, apiTokenRouter apiToken.ApiTokenRouter,
 	k8sCapacityRouter k8s.K8sCapacityRouter,
 	webhookHelmRouter webhookHelm.WebhookHelmRouter,
+	apiTokenScopeMiddleware apiToken.ApiTokenScopeMiddleware,
+	scimRouter scim.ScimRouter,
+	auditEventRouter auditLog.AuditEventRouter,
+	auditRequestIdMiddleware auditLog.AuditRequestIdMiddleware,
+	auditEventRetention cron.AuditEventRetentionHandler,
+	siemExport cron.SiemExportHandler,
+	ociChartSync cron.OCIChartSyncHandler,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                   mux.NewRouter(),
@@ -99,10 +116,19 @@ func NewMuxRouter(
 		apiTokenRouter:           apiTokenRouter,
 		k8sCapacityRouter:        k8sCapacityRouter,
 		webhookHelmRouter:        webhookHelmRouter,
+		apiTokenScopeMiddleware:  apiTokenScopeMiddleware,
+		scimRouter:               scimRouter,
+		auditEventRouter:         auditEventRouter,
+		auditRequestIdMiddleware: auditRequestIdMiddleware,
+		auditEventRetention:      auditEventRetention,
+		siemExport:               siemExport,
+		ociChartSync:             ociChartSync,
 	}
 	return r
 }
 func (r *MuxRouter) Init() {
+	r.Router.Use(r.apiTokenScopeMiddleware.Handle)
+	r.Router.Use(r.auditRequestIdMiddleware.Handle)
 	r.Router.StrictSlash(true)
 	r.Router.Path("/health").HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		writer.Header().Set("Content-Type", "application/json")
@@ -203,6 +229,14 @@ func (r *MuxRouter) Init() {
 	apiTokenRouter := r.Router.PathPrefix("/orchestrator/api-token").Subrouter()
 	r.apiTokenRouter.InitApiTokenRouter(apiTokenRouter)
 
+	// scim provisioning router
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.InitScimRouter(scimRouter)
+
+	// audit event router
+	auditEventRouter := r.Router.PathPrefix("/orchestrator/audit").Subrouter()
+	r.auditEventRouter.InitAuditEventRouter(auditEventRouter)
+
 	// webhook helm app router
 	webhookHelmRouter := r.Router.PathPrefix("/orchestrator/webhook/helm").Subrouter()
 	r.webhookHelmRouter.InitWebhookHelmRouter(webhookHelmRouter)
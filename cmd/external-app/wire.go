@@ -9,6 +9,7 @@ import (
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/auditLog"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/connector"
@@ -16,12 +17,14 @@ import (
 	"github.com/devtron-labs/devtron/api/externalLink"
 	client "github.com/devtron-labs/devtron/api/helm-app"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/scim"
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/sso"
 	"github.com/devtron-labs/devtron/api/team"
 	"github.com/devtron-labs/devtron/api/user"
 	webhookHelm "github.com/devtron-labs/devtron/api/webhook/helm"
 	"github.com/devtron-labs/devtron/client/argocdServer/session"
+	"github.com/devtron-labs/devtron/client/cron"
 	"github.com/devtron-labs/devtron/client/dashboard"
 	"github.com/devtron-labs/devtron/client/telemetry"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
@@ -33,6 +36,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/attributes"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	delete2 "github.com/devtron-labs/devtron/pkg/delete"
+	"github.com/devtron-labs/devtron/pkg/imageSignature"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	util2 "github.com/devtron-labs/devtron/pkg/util"
 	util3 "github.com/devtron-labs/devtron/util"
@@ -63,6 +67,14 @@ func InitializeApp() (*App, error) {
 		server.ServerWireSet,
 		module.ModuleWireSet,
 		apiToken.ApiTokenWireSet,
+		scim.ScimWireSet,
+		auditLog.AuditLogWireSet,
+		cron.NewAuditEventRetentionHandlerImpl,
+		wire.Bind(new(cron.AuditEventRetentionHandler), new(*cron.AuditEventRetentionHandlerImpl)),
+		cron.NewSiemExportHandlerImpl,
+		wire.Bind(new(cron.SiemExportHandler), new(*cron.SiemExportHandlerImpl)),
+		cron.NewOCIChartSyncHandlerImpl,
+		wire.Bind(new(cron.OCIChartSyncHandler), new(*cron.OCIChartSyncHandlerImpl)),
 		webhookHelm.WebhookHelmWireSet,
 
 		NewApp,
@@ -120,6 +132,14 @@ func InitializeApp() (*App, error) {
 		//binding argoUserService to helm via dummy implementation(HelmUserServiceImpl)
 		argo.NewHelmUserServiceImpl,
 		wire.Bind(new(argo.ArgoUserService), new(*argo.HelmUserServiceImpl)),
+
+		//needed for verifying image signatures of chart deployments
+		imageSignature.NewImageSignaturePolicyRepositoryImpl,
+		wire.Bind(new(imageSignature.ImageSignaturePolicyRepository), new(*imageSignature.ImageSignaturePolicyRepositoryImpl)),
+		imageSignature.NewImageSignatureServiceImpl,
+		wire.Bind(new(imageSignature.ImageSignatureService), new(*imageSignature.ImageSignatureServiceImpl)),
+		repository.NewDockerArtifactStoreRepositoryImpl,
+		wire.Bind(new(repository.DockerArtifactStoreRepository), new(*repository.DockerArtifactStoreRepositoryImpl)),
 	)
 	return &App{}, nil
 }
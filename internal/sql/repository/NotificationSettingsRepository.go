@@ -41,6 +41,7 @@ type NotificationSettingsRepository interface {
 	FindNotificationSettingBuildOptions(settingRequest *SearchRequest) ([]*SettingOptionDTO, error)
 	FetchNotificationSettingGroupBy(viewId int) ([]NotificationSettings, error)
 	FindNotificationSettingsByConfigIdAndConfigType(configId int, configType string) ([]*NotificationSettings, error)
+	FindNotificationSettingsForEvent(eventTypeId int, pipelineType string, pipelineId int, teamId int, appId int, envId int) ([]*NotificationSettings, error)
 }
 
 type NotificationSettingsRepositoryImpl struct {
@@ -316,4 +317,22 @@ func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsByConfig
 		return nil, err
 	}
 	return notificationSettings, nil
-}
\ No newline at end of file
+}
+
+// FindNotificationSettingsForEvent returns settings whose every configured selector (team, app, env, pipeline) matches
+// the event, selectors not configured in a setting match any value
+func (impl *NotificationSettingsRepositoryImpl) FindNotificationSettingsForEvent(eventTypeId int, pipelineType string, pipelineId int, teamId int, appId int, envId int) ([]*NotificationSettings, error) {
+	var notificationSettings []*NotificationSettings
+	err := impl.dbConnection.Model(&notificationSettings).
+		Where("event_type_id = ?", eventTypeId).
+		Where("pipeline_type = ?", pipelineType).
+		Where("(pipeline_id IS NULL OR pipeline_id = ?)", pipelineId).
+		Where("(team_id IS NULL OR team_id = ?)", teamId).
+		Where("(app_id IS NULL OR app_id = ?)", appId).
+		Where("(env_id IS NULL OR env_id = ?)", envId).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return notificationSettings, nil
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type NotificationTemplatesRepository interface {
+	GetConnection() *pg.DB
+	FindById(id int) (*NotificationTemplates, error)
+	FindAll() ([]*NotificationTemplates, error)
+	FindByEventTypeIdAndPipelineType(eventTypeId int, pipelineType string) ([]*NotificationTemplates, error)
+	FindByChannelTypeEventTypeIdAndPipelineType(channelType string, eventTypeId int, pipelineType string) (*NotificationTemplates, error)
+	FindByIdForUpdate(id int, tx *pg.Tx) (*NotificationTemplates, error)
+	FindByChannelTypeEventTypeIdAndPipelineTypeForUpdate(channelType string, eventTypeId int, pipelineType string, tx *pg.Tx) (*NotificationTemplates, error)
+	Save(template *NotificationTemplates, tx *pg.Tx) (*NotificationTemplates, error)
+	Update(template *NotificationTemplates, tx *pg.Tx) (*NotificationTemplates, error)
+}
+
+type NotificationTemplatesRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewNotificationTemplatesRepositoryImpl(dbConnection *pg.DB) *NotificationTemplatesRepositoryImpl {
+	return &NotificationTemplatesRepositoryImpl{dbConnection: dbConnection}
+}
+
+// NotificationTemplates is the template of the message sent on a channel for an event type of a pipeline type, only
+// one template is active for a combination
+type NotificationTemplates struct {
+	tableName    struct{} `sql:"notification_message_template" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	ChannelType  string   `sql:"channel_type"`
+	EventTypeId  int      `sql:"event_type_id"`
+	PipelineType string   `sql:"pipeline_type"`
+	TemplateName string   `sql:"template_name"`
+	Template     string   `sql:"template"`
+	Active       bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) FindById(id int) (*NotificationTemplates, error) {
+	template := &NotificationTemplates{}
+	err := impl.dbConnection.Model(template).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return template, err
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) FindAll() ([]*NotificationTemplates, error) {
+	var templates []*NotificationTemplates
+	err := impl.dbConnection.Model(&templates).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return templates, err
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) FindByEventTypeIdAndPipelineType(eventTypeId int, pipelineType string) ([]*NotificationTemplates, error) {
+	var templates []*NotificationTemplates
+	err := impl.dbConnection.Model(&templates).
+		Where("event_type_id = ?", eventTypeId).
+		Where("pipeline_type = ?", pipelineType).
+		Where("active = ?", true).
+		Select()
+	return templates, err
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) FindByChannelTypeEventTypeIdAndPipelineType(channelType string, eventTypeId int, pipelineType string) (*NotificationTemplates, error) {
+	template := &NotificationTemplates{}
+	err := impl.dbConnection.Model(template).
+		Where("channel_type = ?", channelType).
+		Where("event_type_id = ?", eventTypeId).
+		Where("pipeline_type = ?", pipelineType).
+		Where("active = ?", true).
+		Select()
+	return template, err
+}
+
+// FindByIdForUpdate locks the active template so that concurrent updates of it are serialised
+func (impl *NotificationTemplatesRepositoryImpl) FindByIdForUpdate(id int, tx *pg.Tx) (*NotificationTemplates, error) {
+	template := &NotificationTemplates{}
+	err := tx.Model(template).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		For("UPDATE").
+		Select()
+	return template, err
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) FindByChannelTypeEventTypeIdAndPipelineTypeForUpdate(channelType string, eventTypeId int, pipelineType string, tx *pg.Tx) (*NotificationTemplates, error) {
+	template := &NotificationTemplates{}
+	err := tx.Model(template).
+		Where("channel_type = ?", channelType).
+		Where("event_type_id = ?", eventTypeId).
+		Where("pipeline_type = ?", pipelineType).
+		Where("active = ?", true).
+		For("UPDATE").
+		Select()
+	return template, err
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) Save(template *NotificationTemplates, tx *pg.Tx) (*NotificationTemplates, error) {
+	return template, tx.Insert(template)
+}
+
+func (impl *NotificationTemplatesRepositoryImpl) Update(template *NotificationTemplates, tx *pg.Tx) (*NotificationTemplates, error) {
+	return template, tx.Update(template)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type PagerDutyNotificationRepository interface {
+	FindOne(id int) (*PagerDutyConfig, error)
+	UpdatePagerDutyConfig(config *PagerDutyConfig) (*PagerDutyConfig, error)
+	SavePagerDutyConfig(config *PagerDutyConfig) (*PagerDutyConfig, error)
+	FindAll() ([]*PagerDutyConfig, error)
+	FindByIdsIn(ids []int) ([]*PagerDutyConfig, error)
+	MarkPagerDutyConfigDeleted(config *PagerDutyConfig) error
+}
+
+type PagerDutyNotificationRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewPagerDutyNotificationRepositoryImpl(dbConnection *pg.DB) *PagerDutyNotificationRepositoryImpl {
+	return &PagerDutyNotificationRepositoryImpl{dbConnection: dbConnection}
+}
+
+type PagerDutyConfig struct {
+	tableName   struct{} `sql:"pager_duty_config" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	RoutingKey  string   `sql:"routing_key"`
+	Severity    string   `sql:"severity"`
+	ConfigName  string   `sql:"config_name"`
+	Description string   `sql:"description"`
+	OwnerId     int32    `sql:"owner_id"`
+	Deleted     bool     `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (impl *PagerDutyNotificationRepositoryImpl) FindOne(id int) (*PagerDutyConfig, error) {
+	details := &PagerDutyConfig{}
+	err := impl.dbConnection.Model(details).Where("id = ?", id).
+		Where("deleted = ?", false).Select()
+	return details, err
+}
+
+func (impl *PagerDutyNotificationRepositoryImpl) FindAll() ([]*PagerDutyConfig, error) {
+	var configs []*PagerDutyConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("deleted = ?", false).Select()
+	return configs, err
+}
+
+func (impl *PagerDutyNotificationRepositoryImpl) FindByIdsIn(ids []int) ([]*PagerDutyConfig, error) {
+	var configs []*PagerDutyConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("id in (?)", pg.In(ids)).
+		Where("deleted = ?", false).
+		Select()
+	return configs, err
+}
+
+func (impl *PagerDutyNotificationRepositoryImpl) UpdatePagerDutyConfig(config *PagerDutyConfig) (*PagerDutyConfig, error) {
+	return config, impl.dbConnection.Update(config)
+}
+
+func (impl *PagerDutyNotificationRepositoryImpl) SavePagerDutyConfig(config *PagerDutyConfig) (*PagerDutyConfig, error) {
+	return config, impl.dbConnection.Insert(config)
+}
+
+func (impl *PagerDutyNotificationRepositoryImpl) MarkPagerDutyConfigDeleted(config *PagerDutyConfig) error {
+	config.Deleted = true
+	return impl.dbConnection.Update(config)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type TeamsNotificationRepository interface {
+	FindOne(id int) (*TeamsConfig, error)
+	UpdateTeamsConfig(config *TeamsConfig) (*TeamsConfig, error)
+	SaveTeamsConfig(config *TeamsConfig) (*TeamsConfig, error)
+	FindAll() ([]*TeamsConfig, error)
+	FindByIdsIn(ids []int) ([]*TeamsConfig, error)
+	MarkTeamsConfigDeleted(config *TeamsConfig) error
+}
+
+type TeamsNotificationRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewTeamsNotificationRepositoryImpl(dbConnection *pg.DB) *TeamsNotificationRepositoryImpl {
+	return &TeamsNotificationRepositoryImpl{dbConnection: dbConnection}
+}
+
+type TeamsConfig struct {
+	tableName   struct{} `sql:"teams_config" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	WebHookUrl  string   `sql:"web_hook_url"`
+	ConfigName  string   `sql:"config_name"`
+	Description string   `sql:"description"`
+	OwnerId     int32    `sql:"owner_id"`
+	Deleted     bool     `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (impl *TeamsNotificationRepositoryImpl) FindOne(id int) (*TeamsConfig, error) {
+	details := &TeamsConfig{}
+	err := impl.dbConnection.Model(details).Where("id = ?", id).
+		Where("deleted = ?", false).Select()
+	return details, err
+}
+
+func (impl *TeamsNotificationRepositoryImpl) FindAll() ([]*TeamsConfig, error) {
+	var configs []*TeamsConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("deleted = ?", false).Select()
+	return configs, err
+}
+
+func (impl *TeamsNotificationRepositoryImpl) FindByIdsIn(ids []int) ([]*TeamsConfig, error) {
+	var configs []*TeamsConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("id in (?)", pg.In(ids)).
+		Where("deleted = ?", false).
+		Select()
+	return configs, err
+}
+
+func (impl *TeamsNotificationRepositoryImpl) UpdateTeamsConfig(config *TeamsConfig) (*TeamsConfig, error) {
+	return config, impl.dbConnection.Update(config)
+}
+
+func (impl *TeamsNotificationRepositoryImpl) SaveTeamsConfig(config *TeamsConfig) (*TeamsConfig, error) {
+	return config, impl.dbConnection.Insert(config)
+}
+
+func (impl *TeamsNotificationRepositoryImpl) MarkTeamsConfigDeleted(config *TeamsConfig) error {
+	config.Deleted = true
+	return impl.dbConnection.Update(config)
+}
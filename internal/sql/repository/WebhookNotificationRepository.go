@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type WebhookNotificationRepository interface {
+	FindOne(id int) (*WebhookConfig, error)
+	UpdateWebhookConfig(config *WebhookConfig) (*WebhookConfig, error)
+	SaveWebhookConfig(config *WebhookConfig) (*WebhookConfig, error)
+	FindAll() ([]*WebhookConfig, error)
+	FindByIdsIn(ids []int) ([]*WebhookConfig, error)
+	MarkWebhookConfigDeleted(config *WebhookConfig) error
+}
+
+type WebhookNotificationRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewWebhookNotificationRepositoryImpl(dbConnection *pg.DB) *WebhookNotificationRepositoryImpl {
+	return &WebhookNotificationRepositoryImpl{dbConnection: dbConnection}
+}
+
+type WebhookConfig struct {
+	tableName   struct{} `sql:"webhook_config" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	WebHookUrl  string   `sql:"web_hook_url"`
+	ConfigName  string   `sql:"config_name"`
+	Description string   `sql:"description"`
+	Header      string   `sql:"header"`
+	Payload     string   `sql:"payload"`
+	Secret      string   `sql:"secret"`
+	OwnerId     int32    `sql:"owner_id"`
+	Deleted     bool     `sql:"deleted,notnull"`
+	sql.AuditLog
+}
+
+func (impl *WebhookNotificationRepositoryImpl) FindOne(id int) (*WebhookConfig, error) {
+	details := &WebhookConfig{}
+	err := impl.dbConnection.Model(details).Where("id = ?", id).
+		Where("deleted = ?", false).Select()
+	return details, err
+}
+
+func (impl *WebhookNotificationRepositoryImpl) FindAll() ([]*WebhookConfig, error) {
+	var configs []*WebhookConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("deleted = ?", false).Select()
+	return configs, err
+}
+
+func (impl *WebhookNotificationRepositoryImpl) FindByIdsIn(ids []int) ([]*WebhookConfig, error) {
+	var configs []*WebhookConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("id in (?)", pg.In(ids)).
+		Where("deleted = ?", false).
+		Select()
+	return configs, err
+}
+
+func (impl *WebhookNotificationRepositoryImpl) UpdateWebhookConfig(config *WebhookConfig) (*WebhookConfig, error) {
+	return config, impl.dbConnection.Update(config)
+}
+
+func (impl *WebhookNotificationRepositoryImpl) SaveWebhookConfig(config *WebhookConfig) (*WebhookConfig, error) {
+	return config, impl.dbConnection.Insert(config)
+}
+
+func (impl *WebhookNotificationRepositoryImpl) MarkWebhookConfigDeleted(config *WebhookConfig) error {
+	config.Deleted = true
+	return impl.dbConnection.Update(config)
+}
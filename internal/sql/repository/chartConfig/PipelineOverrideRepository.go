@@ -65,6 +65,8 @@ type PipelineOverrideRepository interface {
 	GetLatestReleaseByPipelineIds(pipelineIds []int) (pipelineOverrides []*PipelineOverride, err error)
 	GetLatestReleaseDeploymentType(pipelineIds []int) ([]*PipelineOverride, error)
 	FetchHelmTypePipelineOverridesForStatusUpdate() (pipelines []*PipelineOverride, err error)
+	FindLatestByCdWorkflowId(cdWorkflowId int) (pipelineOverride *PipelineOverride, err error)
+	FindLatestByPipelineId(pipelineId int) (pipelineOverride *PipelineOverride, err error)
 }
 
 type PipelineOverrideRepositoryImpl struct {
@@ -233,3 +235,25 @@ func (impl PipelineOverrideRepositoryImpl) FetchHelmTypePipelineOverridesForStat
 		Select()
 	return pipelines, err
 }
+
+func (impl PipelineOverrideRepositoryImpl) FindLatestByCdWorkflowId(cdWorkflowId int) (pipelineOverride *PipelineOverride, err error) {
+	pipelineOverride = &PipelineOverride{}
+	err = impl.dbConnection.Model(pipelineOverride).
+		Column("pipeline_override.*", "Pipeline", "Pipeline.App", "Pipeline.Environment").
+		Where("pipeline_override.cd_workflow_id = ?", cdWorkflowId).
+		Order("pipeline_override.id DESC").
+		Limit(1).
+		Select()
+	return pipelineOverride, err
+}
+
+// FindLatestByPipelineId returns the release of latest deployment trigger of pipeline
+func (impl PipelineOverrideRepositoryImpl) FindLatestByPipelineId(pipelineId int) (pipelineOverride *PipelineOverride, err error) {
+	pipelineOverride = &PipelineOverride{}
+	err = impl.dbConnection.Model(pipelineOverride).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return pipelineOverride, err
+}
@@ -0,0 +1,123 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type CanaryMetricOperator string
+
+const (
+	CANARY_METRIC_OPERATOR_LESS_THAN             CanaryMetricOperator = "<"
+	CANARY_METRIC_OPERATOR_LESS_THAN_OR_EQUAL    CanaryMetricOperator = "<="
+	CANARY_METRIC_OPERATOR_GREATER_THAN          CanaryMetricOperator = ">"
+	CANARY_METRIC_OPERATOR_GREATER_THAN_OR_EQUAL CanaryMetricOperator = ">="
+)
+
+type CanaryAnalysisConfig struct {
+	tableName             struct{} `sql:"canary_analysis_config" pg:",discard_unknown_columns"`
+	Id                    int      `sql:"id,pk"`
+	PipelineId            int      `sql:"pipeline_id,notnull"`
+	InitialDelayInSeconds int      `sql:"initial_delay_in_seconds,notnull"`
+	IntervalInSeconds     int      `sql:"interval_in_seconds,notnull"`
+	Iterations            int      `sql:"iterations,notnull"`
+	FailureLimit          int      `sql:"failure_limit,notnull"`
+	AutoRollback          bool     `sql:"auto_rollback,notnull"`
+	Active                bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisMetric struct {
+	tableName              struct{}             `sql:"canary_analysis_metric" pg:",discard_unknown_columns"`
+	Id                     int                  `sql:"id,pk"`
+	CanaryAnalysisConfigId int                  `sql:"canary_analysis_config_id,notnull"`
+	Name                   string               `sql:"name,notnull"`
+	Query                  string               `sql:"query,notnull"`
+	Operator               CanaryMetricOperator `sql:"operator,notnull"`
+	Threshold              float64              `sql:"threshold,notnull"`
+	Active                 bool                 `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisConfigRepository interface {
+	GetConnection() *pg.DB
+	SaveConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error
+	UpdateConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error
+	FindActiveConfigByPipelineId(pipelineId int) (*CanaryAnalysisConfig, error)
+	FindConfigById(id int) (*CanaryAnalysisConfig, error)
+	SaveMetrics(metrics []*CanaryAnalysisMetric, tx *pg.Tx) error
+	DeactivateMetricsByConfigId(configId int, userId int32, tx *pg.Tx) error
+	FindActiveMetricsByConfigId(configId int) ([]*CanaryAnalysisMetric, error)
+}
+
+type CanaryAnalysisConfigRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCanaryAnalysisConfigRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CanaryAnalysisConfigRepositoryImpl {
+	return &CanaryAnalysisConfigRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) SaveConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error {
+	return tx.Insert(config)
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) UpdateConfig(config *CanaryAnalysisConfig, tx *pg.Tx) error {
+	return tx.Update(config)
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) FindActiveConfigByPipelineId(pipelineId int) (*CanaryAnalysisConfig, error) {
+	config := &CanaryAnalysisConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Order("id DESC").Limit(1).
+		Select()
+	return config, err
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) FindConfigById(id int) (*CanaryAnalysisConfig, error) {
+	config := &CanaryAnalysisConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("id = ?", id).
+		Select()
+	return config, err
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) SaveMetrics(metrics []*CanaryAnalysisMetric, tx *pg.Tx) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&metrics).Insert()
+	return err
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) DeactivateMetricsByConfigId(configId int, userId int32, tx *pg.Tx) error {
+	_, err := tx.Model((*CanaryAnalysisMetric)(nil)).
+		Set("active = ?", false).
+		Set("updated_by = ?", userId).
+		Set("updated_on = now()").
+		Where("canary_analysis_config_id = ?", configId).
+		Where("active = ?", true).
+		Update()
+	return err
+}
+
+func (impl *CanaryAnalysisConfigRepositoryImpl) FindActiveMetricsByConfigId(configId int) ([]*CanaryAnalysisMetric, error) {
+	var metrics []*CanaryAnalysisMetric
+	err := impl.dbConnection.Model(&metrics).
+		Where("canary_analysis_config_id = ?", configId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return metrics, err
+}
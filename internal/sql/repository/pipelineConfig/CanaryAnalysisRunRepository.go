@@ -0,0 +1,124 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CanaryAnalysisRunStatus string
+
+const (
+	// CANARY_ANALYSIS_RUN_STATUS_PENDING is a run of deployment awaiting merge of its gitops pull request, it starts
+	// running once the pull request is merged
+	CANARY_ANALYSIS_RUN_STATUS_PENDING     CanaryAnalysisRunStatus = "PENDING"
+	CANARY_ANALYSIS_RUN_STATUS_RUNNING     CanaryAnalysisRunStatus = "RUNNING"
+	CANARY_ANALYSIS_RUN_STATUS_PROMOTED    CanaryAnalysisRunStatus = "PROMOTED"
+	CANARY_ANALYSIS_RUN_STATUS_ROLLED_BACK CanaryAnalysisRunStatus = "ROLLED_BACK"
+	CANARY_ANALYSIS_RUN_STATUS_FAILED      CanaryAnalysisRunStatus = "FAILED"
+	CANARY_ANALYSIS_RUN_STATUS_ABORTED     CanaryAnalysisRunStatus = "ABORTED"
+)
+
+type CanaryAnalysisRun struct {
+	tableName              struct{}                `sql:"canary_analysis_run" pg:",discard_unknown_columns"`
+	Id                     int                     `sql:"id,pk"`
+	CanaryAnalysisConfigId int                     `sql:"canary_analysis_config_id,notnull"`
+	PipelineId             int                     `sql:"pipeline_id,notnull"`
+	CdWorkflowRunnerId     int                     `sql:"cd_workflow_runner_id,notnull"`
+	CiArtifactId           int                     `sql:"ci_artifact_id,notnull"`
+	Status                 CanaryAnalysisRunStatus `sql:"status,notnull"`
+	SuccessfulSteps        int                     `sql:"successful_steps,notnull"`
+	FailedSteps            int                     `sql:"failed_steps,notnull"`
+	NextEvaluationOn       time.Time               `sql:"next_evaluation_on"`
+	FinishedOn             time.Time               `sql:"finished_on"`
+	Message                string                  `sql:"message"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisRunRepository interface {
+	Save(run *CanaryAnalysisRun) error
+	Update(run *CanaryAnalysisRun) error
+	FindById(id int) (*CanaryAnalysisRun, error)
+	FindByCdWorkflowRunnerId(wfrId int) (*CanaryAnalysisRun, error)
+	FindByPipelineId(pipelineId int, offset int, limit int) ([]*CanaryAnalysisRun, error)
+	FindDueRunningRuns(evaluateBefore time.Time) ([]*CanaryAnalysisRun, error)
+	AbortRunningRunsByPipelineId(pipelineId int, message string) error
+}
+
+type CanaryAnalysisRunRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCanaryAnalysisRunRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CanaryAnalysisRunRepositoryImpl {
+	return &CanaryAnalysisRunRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CanaryAnalysisRunRepositoryImpl) Save(run *CanaryAnalysisRun) error {
+	err := impl.dbConnection.Insert(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis run", "err", err, "run", run)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisRunRepositoryImpl) Update(run *CanaryAnalysisRun) error {
+	err := impl.dbConnection.Update(run)
+	if err != nil {
+		impl.logger.Errorw("error in updating canary analysis run", "err", err, "run", run)
+		return err
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisRunRepositoryImpl) FindById(id int) (*CanaryAnalysisRun, error) {
+	run := &CanaryAnalysisRun{}
+	err := impl.dbConnection.Model(run).Where("id = ?", id).Select()
+	return run, err
+}
+
+func (impl *CanaryAnalysisRunRepositoryImpl) FindByCdWorkflowRunnerId(wfrId int) (*CanaryAnalysisRun, error) {
+	run := &CanaryAnalysisRun{}
+	err := impl.dbConnection.Model(run).
+		Where("cd_workflow_runner_id = ?", wfrId).
+		Order("id DESC").Limit(1).
+		Select()
+	return run, err
+}
+
+func (impl *CanaryAnalysisRunRepositoryImpl) FindByPipelineId(pipelineId int, offset int, limit int) ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := impl.dbConnection.Model(&runs).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Select()
+	return runs, err
+}
+
+func (impl *CanaryAnalysisRunRepositoryImpl) FindDueRunningRuns(evaluateBefore time.Time) ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := impl.dbConnection.Model(&runs).
+		Where("status = ?", CANARY_ANALYSIS_RUN_STATUS_RUNNING).
+		Where("next_evaluation_on <= ?", evaluateBefore).
+		Order("id ASC").
+		Select()
+	return runs, err
+}
+
+func (impl *CanaryAnalysisRunRepositoryImpl) AbortRunningRunsByPipelineId(pipelineId int, message string) error {
+	_, err := impl.dbConnection.Model((*CanaryAnalysisRun)(nil)).
+		Set("status = ?", CANARY_ANALYSIS_RUN_STATUS_ABORTED).
+		Set("message = ?", message).
+		Set("finished_on = now()").
+		Set("updated_on = now()").
+		Where("pipeline_id = ?", pipelineId).
+		Where("status in (?)", pg.In([]CanaryAnalysisRunStatus{CANARY_ANALYSIS_RUN_STATUS_PENDING, CANARY_ANALYSIS_RUN_STATUS_RUNNING})).
+		Update()
+	return err
+}
@@ -0,0 +1,169 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type CdApprovalRequestStatus string
+type CdApprovalActionType string
+
+const (
+	CD_APPROVAL_REQUEST_STATUS_REQUESTED CdApprovalRequestStatus = "REQUESTED"
+	CD_APPROVAL_REQUEST_STATUS_APPROVED  CdApprovalRequestStatus = "APPROVED"
+	CD_APPROVAL_REQUEST_STATUS_REJECTED  CdApprovalRequestStatus = "REJECTED"
+	CD_APPROVAL_REQUEST_STATUS_CANCELLED CdApprovalRequestStatus = "CANCELLED"
+)
+
+const (
+	CD_APPROVAL_ACTION_APPROVE CdApprovalActionType = "APPROVE"
+	CD_APPROVAL_ACTION_REJECT  CdApprovalActionType = "REJECT"
+)
+
+type CdApprovalPolicy struct {
+	tableName         struct{} `sql:"cd_approval_policy" pg:",discard_unknown_columns"`
+	Id                int      `sql:"id,pk"`
+	PipelineId        int      `sql:"pipeline_id,notnull"`
+	RoleGroupId       int32    `sql:"role_group_id,notnull"`
+	RequiredApprovals int      `sql:"required_approvals,notnull"`
+	Active            bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CdApprovalRequest struct {
+	tableName    struct{}                `sql:"cd_approval_request" pg:",discard_unknown_columns"`
+	Id           int                     `sql:"id,pk"`
+	PipelineId   int                     `sql:"pipeline_id,notnull"`
+	CiArtifactId int                     `sql:"ci_artifact_id,notnull"`
+	Status       CdApprovalRequestStatus `sql:"status,notnull"`
+	Comment      string                  `sql:"comment"`
+	sql.AuditLog
+}
+
+type CdApprovalAction struct {
+	tableName           struct{}             `sql:"cd_approval_action" pg:",discard_unknown_columns"`
+	Id                  int                  `sql:"id,pk"`
+	CdApprovalRequestId int                  `sql:"cd_approval_request_id,notnull"`
+	UserId              int32                `sql:"user_id,notnull"`
+	Action              CdApprovalActionType `sql:"action,notnull"`
+	Comment             string               `sql:"comment"`
+	sql.AuditLog
+}
+
+type CdApprovalRepository interface {
+	SavePolicy(policy *CdApprovalPolicy) error
+	UpdatePolicy(policy *CdApprovalPolicy) error
+	FindActivePolicyByPipelineId(pipelineId int) (*CdApprovalPolicy, error)
+	GetConnection() *pg.DB
+	SaveRequest(request *CdApprovalRequest) error
+	UpdateRequest(request *CdApprovalRequest, tx *pg.Tx) error
+	FindRequestById(id int) (*CdApprovalRequest, error)
+	// FindRequestByIdForUpdate locks the request till tx ends so that actions on it are taken one at a time
+	FindRequestByIdForUpdate(id int, tx *pg.Tx) (*CdApprovalRequest, error)
+	FindLatestRequestByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int) (*CdApprovalRequest, error)
+	FindRequestsByPipelineId(pipelineId int, offset int, limit int) ([]*CdApprovalRequest, error)
+	SaveAction(action *CdApprovalAction, tx *pg.Tx) error
+	FindActionsByRequestIds(requestIds []int) ([]*CdApprovalAction, error)
+	FindActionsByRequestId(requestId int, tx *pg.Tx) ([]*CdApprovalAction, error)
+}
+
+type CdApprovalRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCdApprovalRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CdApprovalRepositoryImpl {
+	return &CdApprovalRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CdApprovalRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *CdApprovalRepositoryImpl) SavePolicy(policy *CdApprovalPolicy) error {
+	return impl.dbConnection.Insert(policy)
+}
+
+func (impl *CdApprovalRepositoryImpl) UpdatePolicy(policy *CdApprovalPolicy) error {
+	return impl.dbConnection.Update(policy)
+}
+
+func (impl *CdApprovalRepositoryImpl) FindActivePolicyByPipelineId(pipelineId int) (*CdApprovalPolicy, error) {
+	policy := &CdApprovalPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Order("id DESC").Limit(1).
+		Select()
+	return policy, err
+}
+
+func (impl *CdApprovalRepositoryImpl) SaveRequest(request *CdApprovalRequest) error {
+	return impl.dbConnection.Insert(request)
+}
+
+func (impl *CdApprovalRepositoryImpl) UpdateRequest(request *CdApprovalRequest, tx *pg.Tx) error {
+	return tx.Update(request)
+}
+
+func (impl *CdApprovalRepositoryImpl) FindRequestById(id int) (*CdApprovalRequest, error) {
+	request := &CdApprovalRequest{}
+	err := impl.dbConnection.Model(request).Where("id = ?", id).Select()
+	return request, err
+}
+
+func (impl *CdApprovalRepositoryImpl) FindRequestByIdForUpdate(id int, tx *pg.Tx) (*CdApprovalRequest, error) {
+	request := &CdApprovalRequest{}
+	err := tx.Model(request).Where("id = ?", id).For("UPDATE").Select()
+	return request, err
+}
+
+func (impl *CdApprovalRepositoryImpl) FindLatestRequestByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int) (*CdApprovalRequest, error) {
+	request := &CdApprovalRequest{}
+	err := impl.dbConnection.Model(request).
+		Where("pipeline_id = ?", pipelineId).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("status != ?", CD_APPROVAL_REQUEST_STATUS_CANCELLED).
+		Order("id DESC").Limit(1).
+		Select()
+	return request, err
+}
+
+func (impl *CdApprovalRepositoryImpl) FindRequestsByPipelineId(pipelineId int, offset int, limit int) ([]*CdApprovalRequest, error) {
+	var requests []*CdApprovalRequest
+	err := impl.dbConnection.Model(&requests).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Select()
+	return requests, err
+}
+
+func (impl *CdApprovalRepositoryImpl) SaveAction(action *CdApprovalAction, tx *pg.Tx) error {
+	return tx.Insert(action)
+}
+
+func (impl *CdApprovalRepositoryImpl) FindActionsByRequestIds(requestIds []int) ([]*CdApprovalAction, error) {
+	var actions []*CdApprovalAction
+	if len(requestIds) == 0 {
+		return actions, nil
+	}
+	err := impl.dbConnection.Model(&actions).
+		Where("cd_approval_request_id in (?)", pg.In(requestIds)).
+		Order("id ASC").
+		Select()
+	return actions, err
+}
+
+func (impl *CdApprovalRepositoryImpl) FindActionsByRequestId(requestId int, tx *pg.Tx) ([]*CdApprovalAction, error) {
+	var actions []*CdApprovalAction
+	err := tx.Model(&actions).
+		Where("cd_approval_request_id = ?", requestId).
+		Order("id ASC").
+		Select()
+	return actions, err
+}
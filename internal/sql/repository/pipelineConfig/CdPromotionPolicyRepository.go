@@ -0,0 +1,134 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// CdPromotionPolicy is one condition an artifact has to satisfy on a source pipeline before it can be deployed on
+// the pipeline, a pipeline is gated by all of its active conditions
+type CdPromotionPolicy struct {
+	tableName                struct{} `sql:"cd_promotion_policy" pg:",discard_unknown_columns"`
+	Id                       int      `sql:"id,pk"`
+	PipelineId               int      `sql:"pipeline_id,notnull"`
+	SourcePipelineId         int      `sql:"source_pipeline_id,notnull"`
+	MinSoakDurationInMinutes int      `sql:"min_soak_duration_in_minutes,notnull"`
+	RequirePostStageSuccess  bool     `sql:"require_post_stage_success,notnull"`
+	Active                   bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// CdPromotionPendingArtifact is an artifact whose auto promotion to the pipeline was held back by unmet promotion
+// conditions, it is promoted once conditions are met unless a newer artifact becomes pending for the pipeline
+type CdPromotionPendingArtifact struct {
+	tableName    struct{} `sql:"cd_promotion_pending_artifact" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	PipelineId   int      `sql:"pipeline_id,notnull"`
+	CiArtifactId int      `sql:"ci_artifact_id,notnull"`
+	TriggeredBy  int32    `sql:"triggered_by,notnull"`
+	Active       bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CdPromotionPolicyRepository interface {
+	GetConnection() *pg.DB
+	SavePolicies(policies []*CdPromotionPolicy, tx *pg.Tx) error
+	DeactivatePoliciesByPipelineId(pipelineId int, userId int32, tx *pg.Tx) error
+	FindActiveByPipelineId(pipelineId int) ([]*CdPromotionPolicy, error)
+	// SavePendingArtifact replaces the pending artifact of the pipeline
+	SavePendingArtifact(pendingArtifact *CdPromotionPendingArtifact) error
+	FindActivePendingArtifacts() ([]*CdPromotionPendingArtifact, error)
+	// DeactivatePendingArtifact returns false if the pending artifact was already deactivated, i.e. by another instance
+	DeactivatePendingArtifact(id int) (bool, error)
+}
+
+type CdPromotionPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCdPromotionPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CdPromotionPolicyRepositoryImpl {
+	return &CdPromotionPolicyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CdPromotionPolicyRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl *CdPromotionPolicyRepositoryImpl) SavePolicies(policies []*CdPromotionPolicy, tx *pg.Tx) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&policies).Insert()
+	return err
+}
+
+func (impl *CdPromotionPolicyRepositoryImpl) DeactivatePoliciesByPipelineId(pipelineId int, userId int32, tx *pg.Tx) error {
+	_, err := tx.Model((*CdPromotionPolicy)(nil)).
+		Set("active = ?", false).
+		Set("updated_by = ?", userId).
+		Set("updated_on = now()").
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Update()
+	return err
+}
+
+func (impl *CdPromotionPolicyRepositoryImpl) FindActiveByPipelineId(pipelineId int) ([]*CdPromotionPolicy, error) {
+	var policies []*CdPromotionPolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("pipeline_id = ?", pipelineId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return policies, err
+}
+
+func (impl *CdPromotionPolicyRepositoryImpl) SavePendingArtifact(pendingArtifact *CdPromotionPendingArtifact) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	_, err = tx.Model((*CdPromotionPendingArtifact)(nil)).
+		Set("active = ?", false).
+		Set("updated_on = now()").
+		Where("pipeline_id = ?", pendingArtifact.PipelineId).
+		Where("active = ?", true).
+		Update()
+	if err != nil {
+		return err
+	}
+	err = tx.Insert(pendingArtifact)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *CdPromotionPolicyRepositoryImpl) FindActivePendingArtifacts() ([]*CdPromotionPendingArtifact, error) {
+	var pendingArtifacts []*CdPromotionPendingArtifact
+	err := impl.dbConnection.Model(&pendingArtifacts).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return pendingArtifacts, err
+}
+
+func (impl *CdPromotionPolicyRepositoryImpl) DeactivatePendingArtifact(id int) (bool, error) {
+	result, err := impl.dbConnection.Model((*CdPromotionPendingArtifact)(nil)).
+		Set("active = ?", false).
+		Set("updated_on = now()").
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
@@ -47,6 +47,15 @@ type CdWorkflowRepository interface {
 
 	FindByWorkflowIdAndRunnerType(wfId int, runnerType bean.WorkflowType) (CdWorkflowRunner, error)
 	FindLastStatusByPipelineIdAndRunnerType(pipelineId int, runnerType bean.WorkflowType) (CdWorkflowRunner, error)
+	FindLastDeployRunnerByPipelineIdAndStatusExcludingArtifact(pipelineId int, status string, ciArtifactId int) (CdWorkflowRunner, error)
+	// FindRunnersByPipelineIdAndArtifactIdsAndStatus returns runners of the artifacts on pipeline, latest first
+	FindRunnersByPipelineIdAndArtifactIdsAndStatus(pipelineId int, ciArtifactIds []int, runnerType bean.WorkflowType, status string) ([]CdWorkflowRunner, error)
+	// FindDeployRunnersByPipelineIdAfterRunnerId returns deploy runners of pipeline after the runner, oldest first
+	FindDeployRunnersByPipelineIdAfterRunnerId(pipelineId int, runnerId int) ([]CdWorkflowRunner, error)
+	FindDeployRunnersForMetrics(appIds []int, environmentId int, from time.Time, to time.Time) ([]*DeployRunnerMetricData, error)
+	// FindOpenDeployFailuresForMetrics returns, for every pipeline failing at before, the first failed deploy runner
+	// after its last successful one
+	FindOpenDeployFailuresForMetrics(appIds []int, environmentId int, before time.Time, successStatuses []string, failedStatuses []string) ([]*DeployRunnerMetricData, error)
 	SaveWorkFlows(wfs ...*CdWorkflow) error
 	IsLatestWf(pipelineId int, wfId int) (bool, error)
 	FindLatestCdWorkflowByPipelineId(pipelineIds []int) (*CdWorkflow, error)
@@ -54,6 +63,7 @@ type CdWorkflowRepository interface {
 	FetchAllCdStagesLatestEntity(pipelineIds []int) ([]*CdWorkflowStatus, error)
 	FetchAllCdStagesLatestEntityStatus(wfrIds []int) ([]*CdWorkflowRunner, error)
 	ExistsByStatus(status string) (bool, error)
+	FindDeployRunnersByStatus(status string) ([]*CdWorkflowRunner, error)
 }
 
 type CdWorkflowRepositoryImpl struct {
@@ -117,43 +127,65 @@ const WORKFLOW_EXECUTOR_TYPE_AWF = "AWF"
 const WORKFLOW_EXECUTOR_TYPE_SYSTEM = "SYSTEM"
 
 type CdWorkflowRunner struct {
-	tableName    struct{}             `sql:"cd_workflow_runner" pg:",discard_unknown_columns"`
-	Id           int                  `sql:"id,pk"`
-	Name         string               `sql:"name"`
-	WorkflowType bean.WorkflowType    `sql:"workflow_type"` //pre,post,deploy
-	ExecutorType WorkflowExecutorType `sql:"executor_type"` //awf, system
-	Status       string               `sql:"status"`
-	PodStatus    string               `sql:"pod_status"`
-	Message      string               `sql:"message"`
-	StartedOn    time.Time            `sql:"started_on"`
-	FinishedOn   time.Time            `sql:"finished_on"`
-	Namespace    string               `sql:"namespace"`
-	LogLocation  string               `sql:"log_file_path"`
-	TriggeredBy  int32                `sql:"triggered_by"`
-	CdWorkflowId int                  `sql:"cd_workflow_id"`
-	CdWorkflow   *CdWorkflow
+	tableName                    struct{}             `sql:"cd_workflow_runner" pg:",discard_unknown_columns"`
+	Id                           int                  `sql:"id,pk"`
+	Name                         string               `sql:"name"`
+	WorkflowType                 bean.WorkflowType    `sql:"workflow_type"` //pre,post,deploy
+	ExecutorType                 WorkflowExecutorType `sql:"executor_type"` //awf, system
+	Status                       string               `sql:"status"`
+	PodStatus                    string               `sql:"pod_status"`
+	Message                      string               `sql:"message"`
+	StartedOn                    time.Time            `sql:"started_on"`
+	FinishedOn                   time.Time            `sql:"finished_on"`
+	Namespace                    string               `sql:"namespace"`
+	LogLocation                  string               `sql:"log_file_path"`
+	TriggeredBy                  int32                `sql:"triggered_by"`
+	CdWorkflowId                 int                  `sql:"cd_workflow_id"`
+	SignatureVerificationStatus  string               `sql:"signature_verification_status"`
+	SignatureVerificationMessage string               `sql:"signature_verification_message"`
+	GitOpsPullRequestId          int                  `sql:"gitops_pull_request_id"`
+	GitOpsPullRequestUrl         string               `sql:"gitops_pull_request_url"`
+	CdWorkflow                   *CdWorkflow
+}
+
+// DeployRunnerMetricData is a deployment of an artifact along with the git material info of the artifact, used for
+// computing delivery metrics
+type DeployRunnerMetricData struct {
+	RunnerId      int       `sql:"runner_id"`
+	PipelineId    int       `sql:"pipeline_id"`
+	AppId         int       `sql:"app_id"`
+	EnvironmentId int       `sql:"environment_id"`
+	Status        string    `sql:"status"`
+	StartedOn     time.Time `sql:"started_on"`
+	FinishedOn    time.Time `sql:"finished_on"`
+	CiArtifactId  int       `sql:"ci_artifact_id"`
+	MaterialInfo  string    `sql:"material_info"`
+	DataSource    string    `sql:"data_source"`
 }
 
 type CdWorkflowWithArtifact struct {
-	Id           int       `json:"id"`
-	CdWorkflowId int       `json:"cd_workflow_id"`
-	Name         string    `json:"name"`
-	Status       string    `json:"status"`
-	PodStatus    string    `json:"pod_status"`
-	Message      string    `json:"message"`
-	StartedOn    time.Time `json:"started_on"`
-	FinishedOn   time.Time `json:"finished_on"`
-	PipelineId   int       `json:"pipeline_id"`
-	Namespace    string    `json:"namespace"`
-	LogFilePath  string    `json:"log_file_path"`
-	TriggeredBy  int32     `json:"triggered_by"`
-	EmailId      string    `json:"email_id"`
-	Image        string    `json:"image"`
-	MaterialInfo string    `json:"material_info,omitempty"`
-	DataSource   string    `json:"data_source,omitempty"`
-	CiArtifactId int       `json:"ci_artifact_id,omitempty"`
-	WorkflowType string    `json:"workflow_type,omitempty"`
-	ExecutorType string    `json:"executor_type,omitempty"`
+	Id                           int       `json:"id"`
+	CdWorkflowId                 int       `json:"cd_workflow_id"`
+	Name                         string    `json:"name"`
+	Status                       string    `json:"status"`
+	PodStatus                    string    `json:"pod_status"`
+	Message                      string    `json:"message"`
+	StartedOn                    time.Time `json:"started_on"`
+	FinishedOn                   time.Time `json:"finished_on"`
+	PipelineId                   int       `json:"pipeline_id"`
+	Namespace                    string    `json:"namespace"`
+	LogFilePath                  string    `json:"log_file_path"`
+	TriggeredBy                  int32     `json:"triggered_by"`
+	EmailId                      string    `json:"email_id"`
+	Image                        string    `json:"image"`
+	MaterialInfo                 string    `json:"material_info,omitempty"`
+	DataSource                   string    `json:"data_source,omitempty"`
+	CiArtifactId                 int       `json:"ci_artifact_id,omitempty"`
+	WorkflowType                 string    `json:"workflow_type,omitempty"`
+	ExecutorType                 string    `json:"executor_type,omitempty"`
+	SignatureVerificationStatus  string    `json:"signature_verification_status,omitempty"`
+	SignatureVerificationMessage string    `json:"signature_verification_message,omitempty"`
+	GitOpsPullRequestUrl         string    `json:"gitops_pull_request_url,omitempty"`
 }
 
 type TriggerWorkflowStatus struct {
@@ -403,6 +435,101 @@ func (impl *CdWorkflowRepositoryImpl) FindLastStatusByPipelineIdAndRunnerType(pi
 	return wfr, err
 }
 
+func (impl *CdWorkflowRepositoryImpl) FindLastDeployRunnerByPipelineIdAndStatusExcludingArtifact(pipelineId int, status string, ciArtifactId int) (CdWorkflowRunner, error) {
+	wfr := CdWorkflowRunner{}
+	err := impl.dbConnection.
+		Model(&wfr).
+		Column("cd_workflow_runner.*", "CdWorkflow").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow.ci_artifact_id <> ?", ciArtifactId).
+		Where("cd_workflow_runner.workflow_type = ?", bean.CD_WORKFLOW_TYPE_DEPLOY).
+		Where("cd_workflow_runner.status = ?", status).
+		Order("cd_workflow_runner.id DESC").
+		Limit(1).
+		Select()
+	return wfr, err
+}
+
+func (impl *CdWorkflowRepositoryImpl) FindRunnersByPipelineIdAndArtifactIdsAndStatus(pipelineId int, ciArtifactIds []int, runnerType bean.WorkflowType, status string) ([]CdWorkflowRunner, error) {
+	var wfrs []CdWorkflowRunner
+	if len(ciArtifactIds) == 0 {
+		return wfrs, nil
+	}
+	err := impl.dbConnection.
+		Model(&wfrs).
+		Column("cd_workflow_runner.*", "CdWorkflow").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow.ci_artifact_id in (?)", pg.In(ciArtifactIds)).
+		Where("cd_workflow_runner.workflow_type = ?", runnerType).
+		Where("cd_workflow_runner.status = ?", status).
+		Order("cd_workflow_runner.id DESC").
+		Select()
+	return wfrs, err
+}
+
+func (impl *CdWorkflowRepositoryImpl) FindDeployRunnersByPipelineIdAfterRunnerId(pipelineId int, runnerId int) ([]CdWorkflowRunner, error) {
+	var wfrs []CdWorkflowRunner
+	err := impl.dbConnection.
+		Model(&wfrs).
+		Column("cd_workflow_runner.*", "CdWorkflow").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow_runner.workflow_type = ?", bean.CD_WORKFLOW_TYPE_DEPLOY).
+		Where("cd_workflow_runner.id > ?", runnerId).
+		Order("cd_workflow_runner.id ASC").
+		Select()
+	return wfrs, err
+}
+
+func (impl *CdWorkflowRepositoryImpl) FindDeployRunnersForMetrics(appIds []int, environmentId int, from time.Time, to time.Time) ([]*DeployRunnerMetricData, error) {
+	var runners []*DeployRunnerMetricData
+	if len(appIds) == 0 {
+		return runners, nil
+	}
+	query := "SELECT wfr.id as runner_id, p.id as pipeline_id, p.app_id, p.environment_id, wfr.status, wfr.started_on, wfr.finished_on," +
+		" cia.id as ci_artifact_id, cia.material_info, cia.data_source" +
+		" FROM cd_workflow_runner wfr" +
+		" INNER JOIN cd_workflow wf ON wf.id = wfr.cd_workflow_id" +
+		" INNER JOIN pipeline p ON p.id = wf.pipeline_id" +
+		" INNER JOIN ci_artifact cia ON cia.id = wf.ci_artifact_id" +
+		" WHERE wfr.workflow_type = ? AND p.deleted = false AND p.app_id in (?)" +
+		" AND wfr.started_on >= ? AND wfr.started_on < ?"
+	params := []interface{}{bean.CD_WORKFLOW_TYPE_DEPLOY, pg.In(appIds), from, to}
+	if environmentId > 0 {
+		query += " AND p.environment_id = ?"
+		params = append(params, environmentId)
+	}
+	query += " ORDER BY wfr.started_on ASC;"
+	_, err := impl.dbConnection.Query(&runners, query, params...)
+	return runners, err
+}
+
+func (impl *CdWorkflowRepositoryImpl) FindOpenDeployFailuresForMetrics(appIds []int, environmentId int, before time.Time, successStatuses []string, failedStatuses []string) ([]*DeployRunnerMetricData, error) {
+	var runners []*DeployRunnerMetricData
+	if len(appIds) == 0 {
+		return runners, nil
+	}
+	query := "SELECT DISTINCT ON (p.id) wfr.id as runner_id, p.id as pipeline_id, p.app_id, p.environment_id, wfr.status, wfr.started_on, wfr.finished_on," +
+		" cia.id as ci_artifact_id" +
+		" FROM cd_workflow_runner wfr" +
+		" INNER JOIN cd_workflow wf ON wf.id = wfr.cd_workflow_id" +
+		" INNER JOIN pipeline p ON p.id = wf.pipeline_id" +
+		" INNER JOIN ci_artifact cia ON cia.id = wf.ci_artifact_id" +
+		" WHERE wfr.workflow_type = ? AND p.deleted = false AND p.app_id in (?)" +
+		" AND wfr.started_on < ? AND wfr.status in (?)" +
+		" AND wfr.id > COALESCE((SELECT max(swfr.id) FROM cd_workflow_runner swfr" +
+		" INNER JOIN cd_workflow swf ON swf.id = swfr.cd_workflow_id" +
+		" WHERE swf.pipeline_id = p.id AND swfr.workflow_type = ? AND swfr.started_on < ? AND swfr.status in (?)), 0)"
+	params := []interface{}{bean.CD_WORKFLOW_TYPE_DEPLOY, pg.In(appIds), before, pg.In(failedStatuses),
+		bean.CD_WORKFLOW_TYPE_DEPLOY, before, pg.In(successStatuses)}
+	if environmentId > 0 {
+		query += " AND p.environment_id = ?"
+		params = append(params, environmentId)
+	}
+	query += " ORDER BY p.id, wfr.id ASC;"
+	_, err := impl.dbConnection.Query(&runners, query, params...)
+	return runners, err
+}
+
 func (impl *CdWorkflowRepositoryImpl) IsLatestWf(pipelineId int, wfId int) (bool, error) {
 	exists, err := impl.dbConnection.Model(&CdWorkflow{}).
 		Where("pipeline_id =?", pipelineId).
@@ -442,3 +569,15 @@ func (impl *CdWorkflowRepositoryImpl) ExistsByStatus(status string) (bool, error
 		Exists()
 	return exists, err
 }
+
+func (impl *CdWorkflowRepositoryImpl) FindDeployRunnersByStatus(status string) ([]*CdWorkflowRunner, error) {
+	var runners []*CdWorkflowRunner
+	err := impl.dbConnection.
+		Model(&runners).
+		Column("cd_workflow_runner.*", "CdWorkflow").
+		Where("cd_workflow_runner.status = ?", status).
+		Where("cd_workflow_runner.workflow_type = ?", bean.CD_WORKFLOW_TYPE_DEPLOY).
+		Order("cd_workflow_runner.id ASC").
+		Select()
+	return runners, err
+}
@@ -0,0 +1,161 @@
+package pipelineConfig
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+)
+
+type CiPipelineScheduleRunStatus string
+
+const (
+	CI_PIPELINE_SCHEDULE_RUN_STATUS_TRIGGERED CiPipelineScheduleRunStatus = "TRIGGERED"
+	CI_PIPELINE_SCHEDULE_RUN_STATUS_SKIPPED   CiPipelineScheduleRunStatus = "SKIPPED"
+	CI_PIPELINE_SCHEDULE_RUN_STATUS_FAILED    CiPipelineScheduleRunStatus = "FAILED"
+)
+
+type CiPipelineSchedule struct {
+	tableName         struct{} `sql:"ci_pipeline_schedule" pg:",discard_unknown_columns"`
+	Id                int      `sql:"id,pk"`
+	CiPipelineId      int      `sql:"ci_pipeline_id,notnull"`
+	CronExpression    string   `sql:"cron_expression,notnull"`
+	Timezone          string   `sql:"timezone,notnull"`
+	SkipIfNoNewCommit bool     `sql:"skip_if_no_new_commit,notnull"`
+	Active            bool     `sql:"active,notnull"`
+	// LastTriggeredOn is the latest occurrence of the schedule claimed for trigger by an instance
+	LastTriggeredOn time.Time `sql:"last_triggered_on"`
+	sql.AuditLog
+}
+
+type CiPipelineScheduleRun struct {
+	tableName            struct{}                    `sql:"ci_pipeline_schedule_run" pg:",discard_unknown_columns"`
+	Id                   int                         `sql:"id,pk"`
+	CiPipelineScheduleId int                         `sql:"ci_pipeline_schedule_id,notnull"`
+	CiPipelineId         int                         `sql:"ci_pipeline_id,notnull"`
+	CiWorkflowId         int                         `sql:"ci_workflow_id"`
+	Status               CiPipelineScheduleRunStatus `sql:"status,notnull"`
+	Message              string                      `sql:"message"`
+	sql.AuditLog
+}
+
+type CiPipelineScheduleRepository interface {
+	Save(schedule *CiPipelineSchedule) error
+	Update(schedule *CiPipelineSchedule) error
+	FindById(id int) (*CiPipelineSchedule, error)
+	FindActiveByCiPipelineId(ciPipelineId int) ([]*CiPipelineSchedule, error)
+	FindAllActive() ([]*CiPipelineSchedule, error)
+	// ClaimOccurrence marks the occurrence of schedule as triggered, it returns false if the occurrence was already
+	// claimed so that only one instance triggers it
+	ClaimOccurrence(id int, occurrence time.Time) (bool, error)
+	SaveRun(run *CiPipelineScheduleRun) error
+	FindRunsByCiPipelineId(ciPipelineId int, offset int, limit int) ([]*CiPipelineScheduleRun, error)
+	FindRunsByCiWorkflowIds(ciWorkflowIds []int) ([]*CiPipelineScheduleRun, error)
+}
+
+type CiPipelineScheduleRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCiPipelineScheduleRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CiPipelineScheduleRepositoryImpl {
+	return &CiPipelineScheduleRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) Save(schedule *CiPipelineSchedule) error {
+	err := impl.dbConnection.Insert(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci pipeline schedule", "err", err, "schedule", schedule)
+		return err
+	}
+	return nil
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) Update(schedule *CiPipelineSchedule) error {
+	err := impl.dbConnection.Update(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in updating ci pipeline schedule", "err", err, "schedule", schedule)
+		return err
+	}
+	return nil
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindById(id int) (*CiPipelineSchedule, error) {
+	schedule := &CiPipelineSchedule{}
+	err := impl.dbConnection.Model(schedule).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return schedule, err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindActiveByCiPipelineId(ciPipelineId int) ([]*CiPipelineSchedule, error) {
+	var schedules []*CiPipelineSchedule
+	err := impl.dbConnection.Model(&schedules).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return schedules, err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindAllActive() ([]*CiPipelineSchedule, error) {
+	var schedules []*CiPipelineSchedule
+	err := impl.dbConnection.Model(&schedules).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return schedules, err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) ClaimOccurrence(id int, occurrence time.Time) (bool, error) {
+	result, err := impl.dbConnection.Model(&CiPipelineSchedule{}).
+		Set("last_triggered_on = ?", occurrence).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("last_triggered_on IS NULL").WhereOr("last_triggered_on < ?", occurrence)
+			return q, nil
+		}).
+		Update()
+	if err != nil {
+		impl.logger.Errorw("error in claiming ci pipeline schedule occurrence", "err", err, "id", id, "occurrence", occurrence)
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) SaveRun(run *CiPipelineScheduleRun) error {
+	err := impl.dbConnection.Insert(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci pipeline schedule run", "err", err, "run", run)
+		return err
+	}
+	return nil
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindRunsByCiPipelineId(ciPipelineId int, offset int, limit int) ([]*CiPipelineScheduleRun, error) {
+	var runs []*CiPipelineScheduleRun
+	err := impl.dbConnection.Model(&runs).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Select()
+	return runs, err
+}
+
+func (impl *CiPipelineScheduleRepositoryImpl) FindRunsByCiWorkflowIds(ciWorkflowIds []int) ([]*CiPipelineScheduleRun, error) {
+	var runs []*CiPipelineScheduleRun
+	if len(ciWorkflowIds) == 0 {
+		return runs, nil
+	}
+	err := impl.dbConnection.Model(&runs).
+		Where("ci_workflow_id in (?)", pg.In(ciWorkflowIds)).
+		Select()
+	return runs, err
+}
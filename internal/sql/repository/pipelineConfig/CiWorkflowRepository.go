@@ -29,6 +29,7 @@ type CiWorkflowRepository interface {
 
 	SaveWorkFlow(wf *CiWorkflow) error
 	FindLastTriggeredWorkflow(pipelineId int) (*CiWorkflow, error)
+	FindLastTriggeredWorkflowByStatusesIn(pipelineId int, statuses []string) (*CiWorkflow, error)
 	UpdateWorkFlow(wf *CiWorkflow) error
 	FindByStatusesIn(activeStatuses []string) ([]*CiWorkflow, error)
 	FindByPipelineId(pipelineId int, offset int, size int) ([]WorkflowWithArtifact, error)
@@ -140,6 +141,18 @@ func (impl *CiWorkflowRepositoryImpl) FindLastTriggeredWorkflow(pipelineId int)
 	return workflow, err
 }
 
+func (impl *CiWorkflowRepositoryImpl) FindLastTriggeredWorkflowByStatusesIn(pipelineId int, statuses []string) (*CiWorkflow, error) {
+	workflow := &CiWorkflow{}
+	err := impl.dbConnection.Model(workflow).
+		Column("ci_workflow.*", "CiPipeline").
+		Where("ci_workflow.ci_pipeline_id = ? ", pipelineId).
+		Where("ci_workflow.status in (?)", pg.In(statuses)).
+		Order("ci_workflow.started_on Desc").
+		Limit(1).
+		Select()
+	return workflow, err
+}
+
 func (impl *CiWorkflowRepositoryImpl) FindByStatusesIn(activeStatuses []string) ([]*CiWorkflow, error) {
 	var ciWorkFlows []*CiWorkflow
 	err := impl.dbConnection.Model(&ciWorkFlows).
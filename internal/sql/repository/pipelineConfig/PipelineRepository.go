@@ -93,6 +93,7 @@ type PipelineRepository interface {
 	UpdateCdPipeline(pipeline *Pipeline) error
 	FindNumberOfAppsWithCdPipeline(appIds []int) (count int, err error)
 	GetAppAndEnvDetailsForDeploymentAppTypePipeline(deploymentAppType string, clusterIds []int) ([]*Pipeline, error)
+	FindActiveByDeploymentAppType(deploymentAppType string) ([]*Pipeline, error)
 }
 
 type CiArtifactDTO struct {
@@ -410,4 +411,16 @@ func (impl PipelineRepositoryImpl) GetAppAndEnvDetailsForDeploymentAppTypePipeli
 		Where("pipeline.deployment_app_type = ?", deploymentAppType).
 		Select()
 	return pipelines, err
+}
+
+func (impl PipelineRepositoryImpl) FindActiveByDeploymentAppType(deploymentAppType string) ([]*Pipeline, error) {
+	var pipelines []*Pipeline
+	err := impl.dbConnection.
+		Model(&pipelines).
+		Column("pipeline.*", "App", "Environment").
+		Where("app.active = ?", true).
+		Where("pipeline.deleted = ?", false).
+		Where("pipeline.deployment_app_type = ?", deploymentAppType).
+		Select()
+	return pipelines, err
 }
\ No newline at end of file
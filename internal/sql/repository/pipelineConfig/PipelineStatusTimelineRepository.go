@@ -11,10 +11,24 @@ type TimelineStatus string
 
 const (
 	TIMELINE_STATUS_GIT_COMMIT            TimelineStatus = "GIT_COMMIT"
+	TIMELINE_STATUS_PULL_REQUEST_OPENED   TimelineStatus = "PULL_REQUEST_OPENED"
+	TIMELINE_STATUS_PULL_REQUEST_MERGED   TimelineStatus = "PULL_REQUEST_MERGED"
 	TIMELINE_STATUS_KUBECTL_APPLY_STARTED TimelineStatus = "KUBECTL_APPLY_STARTED"
 	TIMELINE_STATUS_KUBECTL_APPLY_SYNCED  TimelineStatus = "KUBECTL_APPLY_SYNCED"
 	TIMELINE_STATUS_APP_HEALTHY           TimelineStatus = "HEALTHY"
 	TIMELINE_STATUS_APP_DEGRADED          TimelineStatus = "DEGRADED"
+	TIMELINE_STATUS_CANARY_STARTED        TimelineStatus = "CANARY_ANALYSIS_STARTED"
+	TIMELINE_STATUS_CANARY_STEP_SUCCEEDED TimelineStatus = "CANARY_ANALYSIS_STEP_SUCCEEDED"
+	TIMELINE_STATUS_CANARY_STEP_FAILED    TimelineStatus = "CANARY_ANALYSIS_STEP_FAILED"
+	TIMELINE_STATUS_CANARY_PROMOTED       TimelineStatus = "CANARY_PROMOTED"
+	TIMELINE_STATUS_CANARY_ROLLED_BACK    TimelineStatus = "CANARY_ROLLED_BACK"
+	TIMELINE_STATUS_PRE_DEPLOY            TimelineStatus = "PRE_DEPLOY"
+	TIMELINE_STATUS_HELM_UPGRADE_STARTED  TimelineStatus = "HELM_UPGRADE_STARTED"
+	TIMELINE_STATUS_HELM_UPGRADE_FINISHED TimelineStatus = "HELM_UPGRADE_FINISHED"
+	TIMELINE_STATUS_RESOURCES_READY       TimelineStatus = "RESOURCES_READY"
+	TIMELINE_STATUS_POST_DEPLOY           TimelineStatus = "POST_DEPLOY"
+	TIMELINE_STATUS_DEPLOYMENT_TIMED_OUT  TimelineStatus = "TIMED_OUT"
+	TIMELINE_STATUS_DEPLOYMENT_FAILED     TimelineStatus = "FAILED"
 )
 
 type PipelineStatusTimelineRepository interface {
@@ -24,6 +38,8 @@ type PipelineStatusTimelineRepository interface {
 	FetchTimelinesByWfrId(wfrId int) ([]*PipelineStatusTimeline, error)
 	FetchTimelineOfLatestWfByCdWorkflowIdAndStatus(pipelineId int, status TimelineStatus) (*PipelineStatusTimeline, error)
 	CheckTimelineExistsOfLatestWfByCdWorkflowIdAndStatus(cdWorkflowId int, status TimelineStatus) (bool, error)
+	FetchTimelinesByWfrIdsAndStatus(wfrIds []int, status TimelineStatus) ([]*PipelineStatusTimeline, error)
+	CheckTimelineExistsByWfrIdAndStatus(wfrId int, status TimelineStatus) (bool, error)
 }
 
 type PipelineStatusTimelineRepositoryImpl struct {
@@ -121,3 +137,33 @@ func (impl *PipelineStatusTimelineRepositoryImpl) CheckTimelineExistsOfLatestWfB
 	}
 	return exists, nil
 }
+
+func (impl *PipelineStatusTimelineRepositoryImpl) FetchTimelinesByWfrIdsAndStatus(wfrIds []int, status TimelineStatus) ([]*PipelineStatusTimeline, error) {
+	var timelines []*PipelineStatusTimeline
+	if len(wfrIds) == 0 {
+		return timelines, nil
+	}
+	err := impl.dbConnection.Model(&timelines).
+		Where("cd_workflow_runner_id in (?)", pg.In(wfrIds)).
+		Where("status = ?", status).
+		Order("status_time ASC").
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting timelines by wfrIds and status", "err", err, "wfrIds", wfrIds, "status", status)
+		return nil, err
+	}
+	return timelines, nil
+}
+
+func (impl *PipelineStatusTimelineRepositoryImpl) CheckTimelineExistsByWfrIdAndStatus(wfrId int, status TimelineStatus) (bool, error) {
+	timeline := &PipelineStatusTimeline{}
+	exists, err := impl.dbConnection.Model(timeline).
+		Where("cd_workflow_runner_id = ?", wfrId).
+		Where("status = ?", status).
+		Exists()
+	if err != nil {
+		impl.logger.Errorw("error in checking timeline by wfrId and status", "err", err, "wfrId", wfrId, "status", status)
+		return false, err
+	}
+	return exists, nil
+}
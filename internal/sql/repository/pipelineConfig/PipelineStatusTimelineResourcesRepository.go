@@ -0,0 +1,91 @@
+package pipelineConfig
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type PipelineStatusTimelineResourcesRepository interface {
+	SaveTimelineResources(timelineResources []*PipelineStatusTimelineResources) error
+	UpdateTimelineResources(timelineResources []*PipelineStatusTimelineResources) error
+	FetchTimelineResourcesByWfrId(wfrId int) ([]*PipelineStatusTimelineResources, error)
+	FetchFailedTimelineResourcesByWfrId(wfrId int) ([]*PipelineStatusTimelineResources, error)
+}
+
+type PipelineStatusTimelineResourcesRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewPipelineStatusTimelineResourcesRepositoryImpl(dbConnection *pg.DB,
+	logger *zap.SugaredLogger) *PipelineStatusTimelineResourcesRepositoryImpl {
+	return &PipelineStatusTimelineResourcesRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+// PipelineStatusTimelineResources is the latest sync result and health of a resource of a deployment
+type PipelineStatusTimelineResources struct {
+	tableName          struct{} `sql:"pipeline_status_timeline_resources" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	CdWorkflowRunnerId int      `sql:"cd_workflow_runner_id"`
+	ResourceGroup      string   `sql:"resource_group"`
+	ResourceVersion    string   `sql:"resource_version"`
+	ResourceKind       string   `sql:"resource_kind"`
+	ResourceName       string   `sql:"resource_name"`
+	ResourceNamespace  string   `sql:"resource_namespace"`
+	SyncStatus         string   `sql:"sync_status"`
+	SyncPhase          string   `sql:"sync_phase"`
+	HealthStatus       string   `sql:"health_status"`
+	StatusMessage      string   `sql:"status_message"`
+	sql.AuditLog
+}
+
+func (impl *PipelineStatusTimelineResourcesRepositoryImpl) SaveTimelineResources(timelineResources []*PipelineStatusTimelineResources) error {
+	err := impl.dbConnection.Insert(&timelineResources)
+	if err != nil {
+		impl.logger.Errorw("error in saving timeline resources of cd pipeline status", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *PipelineStatusTimelineResourcesRepositoryImpl) UpdateTimelineResources(timelineResources []*PipelineStatusTimelineResources) error {
+	for _, timelineResource := range timelineResources {
+		err := impl.dbConnection.Update(timelineResource)
+		if err != nil {
+			impl.logger.Errorw("error in updating timeline resource of cd pipeline status", "err", err, "timelineResource", timelineResource)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *PipelineStatusTimelineResourcesRepositoryImpl) FetchTimelineResourcesByWfrId(wfrId int) ([]*PipelineStatusTimelineResources, error) {
+	var timelineResources []*PipelineStatusTimelineResources
+	err := impl.dbConnection.Model(&timelineResources).
+		Where("cd_workflow_runner_id = ?", wfrId).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting timeline resources by wfrId", "err", err, "wfrId", wfrId)
+		return nil, err
+	}
+	return timelineResources, nil
+}
+
+// FetchFailedTimelineResourcesByWfrId returns resources of a deployment which failed to sync or are not healthy
+func (impl *PipelineStatusTimelineResourcesRepositoryImpl) FetchFailedTimelineResourcesByWfrId(wfrId int) ([]*PipelineStatusTimelineResources, error) {
+	var timelineResources []*PipelineStatusTimelineResources
+	err := impl.dbConnection.Model(&timelineResources).
+		Where("cd_workflow_runner_id = ?", wfrId).
+		Where("(sync_status in (?) OR health_status in (?))",
+			pg.In([]string{"SyncFailed", "PruneSkipped", "OutOfSync"}), pg.In([]string{"Degraded", "Missing"})).
+		Order("id ASC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting failed timeline resources by wfrId", "err", err, "wfrId", wfrId)
+		return nil, err
+	}
+	return timelineResources, nil
+}
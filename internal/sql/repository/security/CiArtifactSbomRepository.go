@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package security
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+const (
+	SbomFormat_SPDX      = "spdx"
+	SbomFormat_CYCLONEDX = "cyclonedx"
+)
+
+// CiArtifactSbom is the software bill of materials document of a ci artifact, one per format
+type CiArtifactSbom struct {
+	tableName      struct{} `sql:"ci_artifact_sbom" pg:",discard_unknown_columns"`
+	Id             int      `sql:"id,pk"`
+	CiArtifactId   int      `sql:"ci_artifact_id,notnull"`
+	Image          string   `sql:"image,notnull"`
+	Format         string   `sql:"format,notnull"`
+	SpecVersion    string   `sql:"spec_version"`
+	Document       string   `sql:"document,notnull"`
+	ComponentCount int      `sql:"component_count,notnull"`
+	// Generated is true for documents generated by image scanner, they can not be overwritten by uploads
+	Generated bool `sql:"generated,notnull"`
+	sql.AuditLog
+}
+
+// ciArtifactSbomSummaryColumns are the columns of CiArtifactSbom except the document, read when listing sboms
+var ciArtifactSbomSummaryColumns = []string{"id", "ci_artifact_id", "image", "format", "spec_version", "component_count",
+	"generated", "created_on", "created_by", "updated_on", "updated_by"}
+
+// SbomComponent is a package listed in a sbom document, kept separately to search images by package
+type SbomComponent struct {
+	tableName        struct{} `sql:"sbom_component" pg:",discard_unknown_columns"`
+	Id               int      `sql:"id,pk"`
+	CiArtifactSbomId int      `sql:"ci_artifact_sbom_id,notnull"`
+	CiArtifactId     int      `sql:"ci_artifact_id,notnull"`
+	Image            string   `sql:"image,notnull"`
+	Name             string   `sql:"name,notnull"`
+	Version          string   `sql:"version"`
+	Purl             string   `sql:"purl"`
+	Type             string   `sql:"type"`
+}
+
+type SbomComponentSearchRequest struct {
+	Name string `json:"name" validate:"required"`
+	// Version matches the exact version and versions below it, 2.14 matches 2.14 and 2.14.1
+	Version        string `json:"version"`
+	EnvironmentIds []int  `json:"envIds"`
+	ClusterIds     []int  `json:"clusterIds"`
+	// Offset and Size paginate the results visible to the user, 20 results are returned if size is not given
+	Offset int `json:"offset"`
+	Size   int `json:"size"`
+}
+
+// SbomComponentExposure is a component found in an image currently deployed by an app or chart, or running in a pod
+type SbomComponentExposure struct {
+	ImageScanDeployInfoId int       `json:"imageScanDeployInfoId"`
+	ObjectType            string    `json:"objectType"`
+	ScanObjectMetaId      int       `json:"scanObjectMetaId"`
+	ObjectName            string    `json:"objectName"`
+	AppId                 int       `json:"appId"`
+	EnvId                 int       `json:"envId"`
+	EnvName               string    `json:"envName"`
+	ClusterId             int       `json:"clusterId"`
+	CiArtifactId          int       `json:"ciArtifactId"`
+	Image                 string    `json:"image"`
+	Name                  string    `json:"name"`
+	Version               string    `json:"version"`
+	Purl                  string    `json:"purl"`
+	LastDeployedOn        time.Time `json:"lastDeployedOn"`
+}
+
+type CiArtifactSbomRepository interface {
+	GetConnection() *pg.DB
+	Save(model *CiArtifactSbom, tx *pg.Tx) error
+	Update(model *CiArtifactSbom, tx *pg.Tx) error
+	FindByCiArtifactId(ciArtifactId int) ([]*CiArtifactSbom, error)
+	FindByCiArtifactIdAndFormat(ciArtifactId int, format string) (*CiArtifactSbom, error)
+	SaveComponents(components []*SbomComponent, tx *pg.Tx) error
+	DeleteComponentsBySbomId(ciArtifactSbomId int, tx *pg.Tx) error
+	FindComponentsBySbomId(ciArtifactSbomId int) ([]*SbomComponent, error)
+	SearchDeployedComponents(request *SbomComponentSearchRequest, offset int, limit int) ([]*SbomComponentExposure, error)
+}
+
+type CiArtifactSbomRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCiArtifactSbomRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CiArtifactSbomRepositoryImpl {
+	return &CiArtifactSbomRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl CiArtifactSbomRepositoryImpl) GetConnection() *pg.DB {
+	return impl.dbConnection
+}
+
+func (impl CiArtifactSbomRepositoryImpl) Save(model *CiArtifactSbom, tx *pg.Tx) error {
+	return tx.Insert(model)
+}
+
+func (impl CiArtifactSbomRepositoryImpl) Update(model *CiArtifactSbom, tx *pg.Tx) error {
+	return tx.Update(model)
+}
+
+func (impl CiArtifactSbomRepositoryImpl) FindByCiArtifactId(ciArtifactId int) ([]*CiArtifactSbom, error) {
+	var models []*CiArtifactSbom
+	err := impl.dbConnection.Model(&models).
+		Column(ciArtifactSbomSummaryColumns...).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Order("format ASC").
+		Select()
+	return models, err
+}
+
+func (impl CiArtifactSbomRepositoryImpl) FindByCiArtifactIdAndFormat(ciArtifactId int, format string) (*CiArtifactSbom, error) {
+	model := &CiArtifactSbom{}
+	err := impl.dbConnection.Model(model).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("format = ?", format).
+		Select()
+	return model, err
+}
+
+func (impl CiArtifactSbomRepositoryImpl) SaveComponents(components []*SbomComponent, tx *pg.Tx) error {
+	if len(components) == 0 {
+		return nil
+	}
+	return tx.Insert(&components)
+}
+
+func (impl CiArtifactSbomRepositoryImpl) DeleteComponentsBySbomId(ciArtifactSbomId int, tx *pg.Tx) error {
+	_, err := tx.Model((*SbomComponent)(nil)).
+		Where("ci_artifact_sbom_id = ?", ciArtifactSbomId).
+		Delete()
+	return err
+}
+
+func (impl CiArtifactSbomRepositoryImpl) FindComponentsBySbomId(ciArtifactSbomId int) ([]*SbomComponent, error) {
+	var components []*SbomComponent
+	err := impl.dbConnection.Model(&components).
+		Where("ci_artifact_sbom_id = ?", ciArtifactSbomId).
+		Order("name ASC").
+		Select()
+	return components, err
+}
+
+// SearchDeployedComponents looks up the component in images of the latest deploy info of every app, chart and pod,
+// limit results from offset are returned
+func (impl CiArtifactSbomRepositoryImpl) SearchDeployedComponents(request *SbomComponentSearchRequest, offset int, limit int) ([]*SbomComponentExposure, error) {
+	var models []*SbomComponentExposure
+	query := "select distinct on (info.id, comp.name, comp.version) info.id as image_scan_deploy_info_id, info.object_type," +
+		" info.scan_object_meta_id, coalesce(a.app_name, om.name) as object_name, a.id as app_id, env.id as env_id," +
+		" env.environment_name as env_name, env.cluster_id, comp.ci_artifact_id, comp.image, comp.name, comp.version, comp.purl," +
+		" info.created_on as last_deployed_on" +
+		" from image_scan_deploy_info info" +
+		" INNER JOIN image_scan_execution_history his on his.id = any (info.image_scan_execution_history_id)" +
+		" INNER JOIN sbom_component comp on comp.image = his.image" +
+		" INNER JOIN environment env on env.id = info.env_id" +
+		" LEFT JOIN app a on a.id = info.scan_object_meta_id and info.object_type in ('app', 'chart')" +
+		" LEFT JOIN image_scan_object_meta om on om.id = info.scan_object_meta_id and info.object_type = 'pod'" +
+		" WHERE info.id in (select max(id) from image_scan_deploy_info where scan_object_meta_id > 0 group by scan_object_meta_id, object_type, env_id)" +
+		" AND env.active = true AND lower(comp.name) like lower(?)"
+	params := []interface{}{"%" + request.Name + "%"}
+	if len(request.Version) > 0 {
+		query = query + " AND (comp.version = ? OR comp.version like ?)"
+		params = append(params, request.Version, request.Version+".%")
+	}
+	if len(request.EnvironmentIds) > 0 {
+		query = query + " AND env.id in (?)"
+		params = append(params, pg.In(request.EnvironmentIds))
+	}
+	if len(request.ClusterIds) > 0 {
+		query = query + " AND env.cluster_id in (?)"
+		params = append(params, pg.In(request.ClusterIds))
+	}
+	query = query + " order by info.id desc, comp.name, comp.version limit ? offset ?"
+	params = append(params, limit, offset)
+	_, err := impl.dbConnection.Query(&models, query, params...)
+	if err != nil {
+		impl.logger.Errorw("error in searching sbom components", "err", err, "request", request)
+		return nil, err
+	}
+	return models, nil
+}
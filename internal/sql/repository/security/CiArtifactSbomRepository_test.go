@@ -0,0 +1,37 @@
+package security
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getSqlColumns(model reflect.Type) []string {
+	var columns []string
+	for i := 0; i < model.NumField(); i++ {
+		field := model.Field(i)
+		if field.Anonymous {
+			columns = append(columns, getSqlColumns(field.Type)...)
+			continue
+		}
+		column := strings.Split(field.Tag.Get("sql"), ",")[0]
+		if len(column) > 0 && field.Name != "tableName" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func TestCiArtifactSbomSummaryColumns(t *testing.T) {
+	// every column except the document is read when listing, generated flag of listed sboms is read back
+	var expected []string
+	for _, column := range getSqlColumns(reflect.TypeOf(CiArtifactSbom{})) {
+		if column != "document" {
+			expected = append(expected, column)
+		}
+	}
+	assert.ElementsMatch(t, expected, ciArtifactSbomSummaryColumns)
+	assert.Contains(t, ciArtifactSbomSummaryColumns, "generated")
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"github.com/go-pg/pg/orm"
+	"time"
 )
 
 type CvePolicy struct {
@@ -35,10 +36,30 @@ type CvePolicy struct {
 	Action        PolicyAction `sql:"action, notnull"`
 	Severity      *Severity    `sql:"severity, notnull "`
 	Deleted       bool         `sql:"deleted, notnull"`
+	// ExpiresOn is set for exceptions, an exception is in effect only once approved and is ignored after expiry so
+	// the inherited policy applies again
+	ExpiresOn      time.Time          `sql:"expires_on"`
+	Justification  string             `sql:"justification"`
+	ApprovalStatus CveExceptionStatus `sql:"approval_status"`
+	ApprovedBy     int32              `sql:"approved_by"`
+	ApprovedOn     time.Time          `sql:"approved_on"`
+	ExpiryNotified bool               `sql:"expiry_notified,notnull"`
 	sql.AuditLog
 	CveStore *CveStore
 }
 
+func (policy *CvePolicy) IsException() bool {
+	return !policy.ExpiresOn.IsZero()
+}
+
+type CveExceptionStatus string
+
+const (
+	CVE_EXCEPTION_PENDING  CveExceptionStatus = "PENDING"
+	CVE_EXCEPTION_APPROVED CveExceptionStatus = "APPROVED"
+	CVE_EXCEPTION_REJECTED CveExceptionStatus = "REJECTED"
+)
+
 type PolicyAction int
 
 const (
@@ -111,6 +132,11 @@ type CvePolicyRepository interface {
 	UpdatePolicy(policy *CvePolicy) (*CvePolicy, error)
 	GetById(id int) (*CvePolicy, error)
 	GetBlockedCVEList(cves []*CveStore, clusterId, envId, appId int, isAppstore bool) ([]*CveStore, error)
+	GetExceptionsExpiringBefore(expiresBefore time.Time) (policies []*CvePolicy, err error)
+	MarkExpiryNotified(ids []int) error
+	GetPendingExceptions() (policies []*CvePolicy, err error)
+	// ReviewException approves or rejects a pending exception, false is returned if it is no longer pending
+	ReviewException(id int, status CveExceptionStatus, reviewedBy int32) (bool, error)
 }
 type CvePolicyRepositoryImpl struct {
 	dbConnection *pg.DB
@@ -125,6 +151,7 @@ func (impl *CvePolicyRepositoryImpl) GetGlobalPolicies() (policies []*CvePolicy,
 		Relation("CveStore").
 		Where("global = true").
 		Where("deleted = false").
+		Where("(expires_on IS NULL OR (expires_on > now() AND approval_status = ?))", CVE_EXCEPTION_APPROVED).
 		Select()
 	return policies, err
 }
@@ -139,6 +166,7 @@ func (impl *CvePolicyRepositoryImpl) GetClusterPolicies(clusterId int) (policies
 			return q, nil
 		}).
 		Where("deleted = false").
+		Where("(expires_on IS NULL OR (expires_on > now() AND approval_status = ?))", CVE_EXCEPTION_APPROVED).
 		Select()
 	return policies, err
 }
@@ -154,6 +182,7 @@ func (impl *CvePolicyRepositoryImpl) GetEnvPolicies(clusterId int, environmentId
 			return q, nil
 		}).
 		Where("deleted = false").
+		Where("(expires_on IS NULL OR (expires_on > now() AND approval_status = ?))", CVE_EXCEPTION_APPROVED).
 		Select()
 	return policies, err
 }
@@ -170,10 +199,61 @@ func (impl *CvePolicyRepositoryImpl) GetAppEnvPolicies(clusterId int, environmen
 			return q, nil
 		}).
 		Where("deleted = false").
+		Where("(expires_on IS NULL OR (expires_on > now() AND approval_status = ?))", CVE_EXCEPTION_APPROVED).
+		Select()
+	return policies, err
+}
+
+// GetExceptionsExpiringBefore returns the exceptions which are in effect now and expire before the given time
+func (impl *CvePolicyRepositoryImpl) GetExceptionsExpiringBefore(expiresBefore time.Time) (policies []*CvePolicy, err error) {
+	err = impl.dbConnection.Model(&policies).
+		Column("cve_policy.*").
+		Relation("CveStore").
+		Where("deleted = false").
+		Where("expires_on > now()").
+		Where("expires_on <= ?", expiresBefore).
+		Where("approval_status = ?", CVE_EXCEPTION_APPROVED).
+		Order("expires_on ASC").
 		Select()
 	return policies, err
 }
 
+func (impl *CvePolicyRepositoryImpl) MarkExpiryNotified(ids []int) error {
+	_, err := impl.dbConnection.Model((*CvePolicy)(nil)).
+		Set("expiry_notified = ?", true).
+		Where("id IN (?)", pg.In(ids)).
+		Update()
+	return err
+}
+
+func (impl *CvePolicyRepositoryImpl) GetPendingExceptions() (policies []*CvePolicy, err error) {
+	err = impl.dbConnection.Model(&policies).
+		Column("cve_policy.*").
+		Relation("CveStore").
+		Where("deleted = false").
+		Where("expires_on > now()").
+		Where("approval_status = ?", CVE_EXCEPTION_PENDING).
+		Order("created_on ASC").
+		Select()
+	return policies, err
+}
+
+func (impl *CvePolicyRepositoryImpl) ReviewException(id int, status CveExceptionStatus, reviewedBy int32) (bool, error) {
+	result, err := impl.dbConnection.Model((*CvePolicy)(nil)).
+		Set("approval_status = ?", status).
+		Set("approved_by = ?", reviewedBy).
+		Set("approved_on = ?", time.Now()).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", reviewedBy).
+		Where("id = ?", id).
+		Where("approval_status = ?", CVE_EXCEPTION_PENDING).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
 func (impl *CvePolicyRepositoryImpl) SavePolicy(policy *CvePolicy) (*CvePolicy, error) {
 	err := impl.dbConnection.Insert(policy)
 	return policy, err
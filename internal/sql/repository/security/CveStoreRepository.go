@@ -55,6 +55,8 @@ type VulnerabilityExposure struct {
 	Blocked       bool `json:"blocked"`
 	PipelineEnvId int  `json:"-"`
 	ChartEnvId    int  `json:"-"`
+	// NewSinceLastDeploy is true if cve was found by rescanning the running image after it was deployed
+	NewSinceLastDeploy bool `json:"newSinceLastDeploy"`
 }
 
 type VulnerabilityExposureListingResponse struct {
@@ -0,0 +1,70 @@
+package security
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// ImageScanNewCve is a cve found by rescanning a deployed image which was not present in the scan of the image when it
+// was deployed, records of a deploy info are removed on next deployment
+type ImageScanNewCve struct {
+	tableName                           struct{}  `sql:"image_scan_new_cve" pg:",discard_unknown_columns"`
+	Id                                  int       `sql:"id,pk"`
+	ImageScanDeployInfoId               int       `sql:"image_scan_deploy_info_id,notnull"`
+	CveStoreName                        string    `sql:"cve_store_name,notnull"`
+	Image                               string    `sql:"image,notnull"`
+	PreviousImageScanExecutionHistoryId int       `sql:"previous_image_scan_execution_history_id"`
+	ImageScanExecutionHistoryId         int       `sql:"image_scan_execution_history_id,notnull"`
+	DetectedOn                          time.Time `sql:"detected_on,notnull"`
+	ImageScanDeployInfo                 *ImageScanDeployInfo
+}
+
+type ImageScanNewCveRepository interface {
+	SaveAll(models []*ImageScanNewCve) error
+	FindByCveName(cveName string) ([]*ImageScanNewCve, error)
+	FindByDeployInfoId(deployInfoId int) ([]*ImageScanNewCve, error)
+	DeleteByDeployInfoId(deployInfoId int) error
+}
+
+type ImageScanNewCveRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewImageScanNewCveRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ImageScanNewCveRepositoryImpl {
+	return &ImageScanNewCveRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl ImageScanNewCveRepositoryImpl) SaveAll(models []*ImageScanNewCve) error {
+	err := impl.dbConnection.Insert(&models)
+	return err
+}
+
+func (impl ImageScanNewCveRepositoryImpl) FindByCveName(cveName string) ([]*ImageScanNewCve, error) {
+	var models []*ImageScanNewCve
+	err := impl.dbConnection.Model(&models).Column("image_scan_new_cve.*", "ImageScanDeployInfo").
+		Where("image_scan_new_cve.cve_store_name = ?", cveName).
+		Select()
+	return models, err
+}
+
+func (impl ImageScanNewCveRepositoryImpl) FindByDeployInfoId(deployInfoId int) ([]*ImageScanNewCve, error) {
+	var models []*ImageScanNewCve
+	err := impl.dbConnection.Model(&models).
+		Where("image_scan_deploy_info_id = ?", deployInfoId).
+		Order("detected_on desc").
+		Select()
+	return models, err
+}
+
+func (impl ImageScanNewCveRepositoryImpl) DeleteByDeployInfoId(deployInfoId int) error {
+	_, err := impl.dbConnection.Model((*ImageScanNewCve)(nil)).
+		Where("image_scan_deploy_info_id = ?", deployInfoId).
+		Delete()
+	return err
+}
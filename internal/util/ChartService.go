@@ -36,6 +36,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -59,12 +60,25 @@ type ChartTemplateService interface {
 	GetDir() string
 	GetUserEmailIdAndNameForGitOpsCommit(userId int32) (emailId, name string)
 	GetGitOpsRepoName(appName string) string
+	// GetGitOpsRepoNameForApp returns gitops repo of devtron app as per configured layout, appDir is the directory of
+	// app in repo shared by many apps and empty for repo of app. App named like a shared repo is rejected
+	GetGitOpsRepoNameForApp(appName string, teamName string) (gitOpsRepoName string, appDir string, err error)
+	// GetAppNameOfSharedGitOpsRepo returns name of app whose repo of its own is named like the shared repo of team,
+	// empty when layout is not shared
+	GetAppNameOfSharedGitOpsRepo(teamName string) string
+	IsSharedGitOpsRepoLayout() bool
 	GetGitOpsRepoNameFromUrl(gitRepoUrl string) string
-	CreateGitRepositoryForApp(gitOpsRepoName, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error)
+	CreateGitRepositoryForApp(gitOpsRepoName, appDir, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error)
 	RegisterInArgo(chartGitAttribute *ChartGitAttribute, ctx context.Context) error
-	BuildChartAndPushToGitRepo(chartMetaData *chart.Metadata, referenceTemplatePath string, gitOpsRepoName, referenceTemplate, version, repoUrl string, userId int32) error
+	BuildChartAndPushToGitRepo(chartMetaData *chart.Metadata, referenceTemplatePath string, gitOpsRepoName, chartLocation, repoUrl string, userId int32) error
+	// CopyGitOpsRepoToDir copies content of gitops repo to a directory of another gitops repo, used to move an app
+	// from its repo to a repo shared by many apps
+	CopyGitOpsRepoToDir(sourceRepoUrl string, gitOpsRepoName string, repoUrl string, dir string, userId int32) error
 	GetByteArrayRefChart(chartMetaData *chart.Metadata, referenceTemplatePath string) ([]byte, error)
 	CreateReadmeInGitRepo(gitOpsRepoName string, userId int32) error
+	// ReadGitOpsRepoFiles returns the content of files at head of gitops repo by path, files missing in repo are not
+	// in the map
+	ReadGitOpsRepoFiles(repoUrl string, filePaths []string) (map[string]string, error)
 }
 type ChartTemplateServiceImpl struct {
 	randSource             rand.Source
@@ -182,8 +196,8 @@ func (impl ChartTemplateServiceImpl) FetchValuesFromReferenceChart(chartMetaData
 	return values, chartGitAttr, nil
 }
 
-func (impl ChartTemplateServiceImpl) BuildChartAndPushToGitRepo(chartMetaData *chart.Metadata, referenceTemplatePath string, gitOpsRepoName, referenceTemplate, version, repoUrl string, userId int32) error {
-	impl.logger.Debugw("package chart and push to git", "gitOpsRepoName", gitOpsRepoName, "version", version, "referenceTemplate", referenceTemplate, "repoUrl", repoUrl)
+func (impl ChartTemplateServiceImpl) BuildChartAndPushToGitRepo(chartMetaData *chart.Metadata, referenceTemplatePath string, gitOpsRepoName, chartLocation, repoUrl string, userId int32) error {
+	impl.logger.Debugw("package chart and push to git", "gitOpsRepoName", gitOpsRepoName, "chartLocation", chartLocation, "repoUrl", repoUrl)
 	chartMetaData.ApiVersion = "v1" // ensure always v1
 	dir := impl.GetDir()
 	tempReferenceTemplateDir := filepath.Join(string(impl.chartWorkingDir), dir)
@@ -206,7 +220,7 @@ func (impl ChartTemplateServiceImpl) BuildChartAndPushToGitRepo(chartMetaData *c
 		return err
 	}
 
-	err = impl.pushChartToGitRepo(gitOpsRepoName, referenceTemplate, version, tempReferenceTemplateDir, repoUrl, userId)
+	err = impl.pushChartToGitRepo(gitOpsRepoName, chartLocation, tempReferenceTemplateDir, repoUrl, userId)
 	if err != nil {
 		impl.logger.Errorw("error in pushing chart to git ", "err", err)
 		return err
@@ -218,7 +232,7 @@ type ChartGitAttribute struct {
 	RepoUrl, ChartLocation string
 }
 
-func (impl ChartTemplateServiceImpl) CreateGitRepositoryForApp(gitOpsRepoName, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error) {
+func (impl ChartTemplateServiceImpl) CreateGitRepositoryForApp(gitOpsRepoName, appDir, baseTemplateName, version string, userId int32) (chartGitAttribute *ChartGitAttribute, err error) {
 	//baseTemplateName  replace whitespace
 	space := regexp.MustCompile(`\s+`)
 	gitOpsRepoName = space.ReplaceAllString(gitOpsRepoName, "-")
@@ -243,10 +257,11 @@ func (impl ChartTemplateServiceImpl) CreateGitRepositoryForApp(gitOpsRepoName, b
 			return nil, err
 		}
 	}
-	return &ChartGitAttribute{RepoUrl: repoUrl, ChartLocation: filepath.Join(baseTemplateName, version)}, nil
+	return &ChartGitAttribute{RepoUrl: repoUrl, ChartLocation: GetChartLocationInGitOpsRepo(appDir, baseTemplateName, version)}, nil
 }
 
-func (impl ChartTemplateServiceImpl) pushChartToGitRepo(gitOpsRepoName, referenceTemplate, version, tempReferenceTemplateDir string, repoUrl string, userId int32) (err error) {
+func (impl ChartTemplateServiceImpl) pushChartToGitRepo(gitOpsRepoName, chartLocation, tempReferenceTemplateDir string, repoUrl string, userId int32) (err error) {
+	defer impl.gitFactory.repoLock.Lock(gitOpsRepoName)()
 	chartDir := fmt.Sprintf("%s-%s", gitOpsRepoName, impl.GetDir())
 	clonedDir := impl.gitFactory.gitService.GetCloneDirectory(chartDir)
 	if _, err := os.Stat(clonedDir); os.IsNotExist(err) {
@@ -263,7 +278,7 @@ func (impl ChartTemplateServiceImpl) pushChartToGitRepo(gitOpsRepoName, referenc
 		}
 	}
 
-	dir := filepath.Join(clonedDir, referenceTemplate, version)
+	dir := filepath.Join(clonedDir, chartLocation)
 	pushChartToGit := true
 
 	//if chart already exists don't overrides it by reference template
@@ -583,6 +598,79 @@ func (impl ChartTemplateServiceImpl) GitPull(clonedDir string, repoUrl string, a
 	return nil
 }
 
+// gitOpsReadLock guards the read only checkouts of gitops repos, they are shared by all reads of a repo
+var gitOpsReadLock sync.Mutex
+
+func (impl ChartTemplateServiceImpl) ReadGitOpsRepoFiles(repoUrl string, filePaths []string) (map[string]string, error) {
+	gitOpsReadLock.Lock()
+	defer gitOpsReadLock.Unlock()
+	// checkout is kept apart from the clones used for commits and only fetches head of repo, repeated reads of a
+	// repo transfer the new commits only
+	clonedDir, err := impl.gitFactory.gitService.ShallowFetchHead(repoUrl, filepath.Join("gitops-read", impl.GetGitOpsRepoNameFromUrl(repoUrl)))
+	if err != nil {
+		impl.logger.Errorw("error in fetching head of repo", "url", repoUrl, "err", err)
+		return nil, err
+	}
+	files := make(map[string]string)
+	for _, filePath := range filePaths {
+		content, err := ioutil.ReadFile(filepath.Join(clonedDir, filePath))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in reading file of gitops repo", "url", repoUrl, "file", filePath, "err", err)
+			return nil, err
+		}
+		files[filePath] = string(content)
+	}
+	return files, nil
+}
+
+func (impl ChartTemplateServiceImpl) CopyGitOpsRepoToDir(sourceRepoUrl string, gitOpsRepoName string, repoUrl string, dir string, userId int32) error {
+	sourceDir, err := impl.gitFactory.gitService.Clone(sourceRepoUrl, fmt.Sprintf("gitops-copy/%s", impl.GetDir()))
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo", "url", sourceRepoUrl, "err", err)
+		return err
+	}
+	defer os.RemoveAll(sourceDir)
+	entries, err := ioutil.ReadDir(sourceDir)
+	if err != nil {
+		impl.logger.Errorw("error in reading cloned repo", "url", sourceRepoUrl, "err", err)
+		return err
+	}
+
+	defer impl.gitFactory.repoLock.Lock(gitOpsRepoName)()
+	clonedDir, err := impl.gitFactory.gitService.Clone(repoUrl, fmt.Sprintf("%s-%s", gitOpsRepoName, impl.GetDir()))
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo", "url", repoUrl, "err", err)
+		return err
+	}
+	defer impl.CleanDir(clonedDir)
+	targetDir := filepath.Join(clonedDir, dir)
+	err = os.MkdirAll(targetDir, os.ModePerm)
+	if err != nil {
+		impl.logger.Errorw("error in making dir", "dir", targetDir, "err", err)
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+		err = dirCopy.Copy(filepath.Join(sourceDir, entry.Name()), filepath.Join(targetDir, entry.Name()))
+		if err != nil {
+			impl.logger.Errorw("error copying content of repo", "url", sourceRepoUrl, "file", entry.Name(), "err", err)
+			return err
+		}
+	}
+	userEmailId, userName := impl.GetUserEmailIdAndNameForGitOpsCommit(userId)
+	commit, err := impl.gitFactory.gitService.CommitAndPushAllChanges(clonedDir, fmt.Sprintf("copy %s to %s", sourceRepoUrl, dir), userName, userEmailId)
+	if err != nil {
+		impl.logger.Errorw("error in pushing git", "url", repoUrl, "err", err)
+		return err
+	}
+	impl.logger.Infow("gitops repo copied", "from", sourceRepoUrl, "to", repoUrl, "dir", dir, "commit", commit)
+	return nil
+}
+
 func (impl *ChartTemplateServiceImpl) GetUserEmailIdAndNameForGitOpsCommit(userId int32) (string, string) {
 	emailId := "devtron-bot@devtron.ai"
 	name := "devtron bot"
@@ -617,6 +705,35 @@ func (impl ChartTemplateServiceImpl) GetGitOpsRepoName(appName string) string {
 	return repoName
 }
 
+func (impl ChartTemplateServiceImpl) GetGitOpsRepoNameForApp(appName string, teamName string) (string, string, error) {
+	if !impl.IsSharedGitOpsRepoLayout() {
+		return impl.GetGitOpsRepoName(appName), "", nil
+	}
+	// repo of its own of such an app would be the shared repo
+	if (impl.globalEnvVariables.GitOpsRepoLayout == GITOPS_REPO_LAYOUT_TEAM && strings.HasPrefix(appName, GITOPS_TEAM_REPO_PREFIX)) ||
+		appName == impl.GetAppNameOfSharedGitOpsRepo(teamName) {
+		message := fmt.Sprintf("app name %s collides with name of shared gitops repo, rename the app", appName)
+		return "", "", &ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: message, UserMessage: message}
+	}
+	return impl.GetGitOpsRepoName(impl.GetAppNameOfSharedGitOpsRepo(teamName)), appName, nil
+}
+
+func (impl ChartTemplateServiceImpl) GetAppNameOfSharedGitOpsRepo(teamName string) string {
+	switch impl.globalEnvVariables.GitOpsRepoLayout {
+	case GITOPS_REPO_LAYOUT_TEAM:
+		return GITOPS_TEAM_REPO_PREFIX + teamName
+	case GITOPS_REPO_LAYOUT_GLOBAL:
+		return impl.globalEnvVariables.GitOpsSharedRepoName
+	default:
+		return ""
+	}
+}
+
+func (impl ChartTemplateServiceImpl) IsSharedGitOpsRepoLayout() bool {
+	layout := impl.globalEnvVariables.GitOpsRepoLayout
+	return layout == GITOPS_REPO_LAYOUT_TEAM || layout == GITOPS_REPO_LAYOUT_GLOBAL
+}
+
 func (impl ChartTemplateServiceImpl) GetGitOpsRepoNameFromUrl(gitRepoUrl string) string {
 	gitRepoUrl = gitRepoUrl[strings.LastIndex(gitRepoUrl, "/")+1:]
 	gitRepoUrl = strings.ReplaceAll(gitRepoUrl, ".git", "")
@@ -18,6 +18,7 @@
 package util
 
 import (
+	"encoding/base64"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -27,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/juju/errors"
 	"log"
+	"strings"
 )
 
 //FIXME: this code is temp
@@ -99,3 +101,42 @@ func CreateEcrRepo(repoName string, reg string, accessKey string, secretKey stri
 	fmt.Println(result)
 	return err
 }
+
+// GetEcrAuthorizationToken returns the username and password for docker login to the ecr registry of the region
+func GetEcrAuthorizationToken(accessKey string, secretKey string, region string) (string, string, error) {
+	var creds *credentials.Credentials
+	if len(accessKey) == 0 || len(secretKey) == 0 {
+		sess, err := session.NewSession(&aws.Config{
+			Region: &region,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		creds = ec2rolecreds.NewCredentials(sess)
+	} else {
+		creds = credentials.NewStaticCredentials(accessKey, secretKey, "")
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:      &region,
+		Credentials: creds,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	output, err := ecr.New(sess).GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", "", err
+	}
+	if len(output.AuthorizationData) == 0 || output.AuthorizationData[0].AuthorizationToken == nil {
+		return "", "", fmt.Errorf("no authorization data returned by ecr")
+	}
+	token, err := base64.StdEncoding.DecodeString(*output.AuthorizationData[0].AuthorizationToken)
+	if err != nil {
+		return "", "", err
+	}
+	credential := strings.SplitN(string(token), ":", 2)
+	if len(credential) != 2 {
+		return "", "", fmt.Errorf("invalid authorization token returned by ecr")
+	}
+	return credential[0], credential[1], nil
+}
@@ -7,6 +7,7 @@ import (
 	"gopkg.in/src-d/go-git.v4/config"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -24,17 +25,25 @@ const GIT_ASK_PASS = "/git-ask-pass.sh"
 const Branch_Master = "master"
 
 func (impl *GitCliUtil) Fetch(rootDir string, username string, password string) (response, errMsg string, err error) {
+	return impl.fetch(rootDir, impl.credEnv(username, password))
+}
+
+func (impl *GitCliUtil) fetch(rootDir string, env []string) (response, errMsg string, err error) {
 	impl.logger.Debugw("git fetch ", "location", rootDir)
 	cmd := exec.Command("git", "-C", rootDir, "fetch", "origin", "--tags", "--force")
-	output, errMsg, err := impl.runCommandWithCred(cmd, username, password)
+	output, errMsg, err := impl.runCommandWithEnv(cmd, env)
 	impl.logger.Debugw("fetch output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
 
 func (impl *GitCliUtil) Pull(rootDir string, username string, password string, branch string) (response, errMsg string, err error) {
+	return impl.pull(rootDir, impl.credEnv(username, password), branch)
+}
+
+func (impl *GitCliUtil) pull(rootDir string, env []string, branch string) (response, errMsg string, err error) {
 	impl.logger.Debugw("git pull ", "location", rootDir)
 	cmd := exec.Command("git", "-C", rootDir, "pull", "origin", branch, "--force")
-	output, errMsg, err := impl.runCommandWithCred(cmd, username, password)
+	output, errMsg, err := impl.runCommandWithEnv(cmd, env)
 	impl.logger.Debugw("pull output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -47,19 +56,87 @@ func (impl *GitCliUtil) Checkout(rootDir string, branch string) (response, errMs
 }
 
 func (impl *GitCliUtil) ListBranch(rootDir string, username string, password string) (response, errMsg string, err error) {
+	return impl.listBranch(rootDir, impl.credEnv(username, password))
+}
+
+func (impl *GitCliUtil) listBranch(rootDir string, env []string) (response, errMsg string, err error) {
 	impl.logger.Debugw("git branch ", "location", rootDir)
 	cmd := exec.Command("git", "-C", rootDir, "branch", "-r")
-	output, errMsg, err := impl.runCommandWithCred(cmd, username, password)
+	output, errMsg, err := impl.runCommandWithEnv(cmd, env)
 	impl.logger.Debugw("branch output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
 
+// LsRemote lists the refs of remote, response is empty for a repository without commits
+func (impl *GitCliUtil) LsRemote(remoteUrl string, username string, password string) (response, errMsg string, err error) {
+	return impl.lsRemote(remoteUrl, impl.credEnv(username, password))
+}
+
+// LsRemoteWithSshKey is LsRemote over ssh authenticated with the private key at sshKeyPath
+func (impl *GitCliUtil) LsRemoteWithSshKey(remoteUrl string, sshKeyPath string, knownHostsPath string) (response, errMsg string, err error) {
+	return impl.lsRemote(remoteUrl, impl.sshEnv(sshKeyPath, knownHostsPath))
+}
+
+func (impl *GitCliUtil) lsRemote(remoteUrl string, env []string) (response, errMsg string, err error) {
+	impl.logger.Debugw("git ls-remote ", "url", remoteUrl)
+	cmd := exec.Command("git", "ls-remote", remoteUrl)
+	output, errMsg, err := impl.runCommandWithEnv(cmd, env)
+	impl.logger.Debugw("ls-remote output", "url", remoteUrl, "opt", output, "errMsg", errMsg, "error", err)
+	return output, errMsg, err
+}
+
+// ShallowFetchHead updates the work tree at rootDir to head of default branch of remote with history of depth 1,
+// rootDir is initialised when it is not a repository yet so the checkout is reused across calls
+func (impl *GitCliUtil) ShallowFetchHead(rootDir string, remoteUrl string, username string, password string) (response, errMsg string, err error) {
+	return impl.shallowFetchHead(rootDir, remoteUrl, impl.credEnv(username, password))
+}
+
+// ShallowFetchHeadWithSshKey is ShallowFetchHead over ssh authenticated with the private key at sshKeyPath
+func (impl *GitCliUtil) ShallowFetchHeadWithSshKey(rootDir string, remoteUrl string, sshKeyPath string, knownHostsPath string) (response, errMsg string, err error) {
+	return impl.shallowFetchHead(rootDir, remoteUrl, impl.sshEnv(sshKeyPath, knownHostsPath))
+}
+
+func (impl *GitCliUtil) shallowFetchHead(rootDir string, remoteUrl string, env []string) (response, errMsg string, err error) {
+	if _, err = os.Stat(filepath.Join(rootDir, ".git")); os.IsNotExist(err) {
+		err = impl.Init(rootDir, remoteUrl, false)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	impl.logger.Debugw("git shallow fetch ", "location", rootDir)
+	cmd := exec.Command("git", "-C", rootDir, "fetch", "--depth", "1", remoteUrl, "HEAD")
+	response, errMsg, err = impl.runCommandWithEnv(cmd, env)
+	if err != nil || errMsg != "" {
+		return response, errMsg, err
+	}
+	cmd = exec.Command("git", "-C", rootDir, "reset", "--hard", "FETCH_HEAD")
+	response, errMsg, err = impl.runCommand(cmd)
+	impl.logger.Debugw("reset output", "root", rootDir, "opt", response, "errMsg", errMsg, "error", err)
+	return response, errMsg, err
+}
+
 func (impl *GitCliUtil) runCommandWithCred(cmd *exec.Cmd, userName, password string) (response, errMsg string, err error) {
-	cmd.Env = append(os.Environ(),
+	return impl.runCommandWithEnv(cmd, impl.credEnv(userName, password))
+}
+
+func (impl *GitCliUtil) credEnv(userName, password string) []string {
+	return []string{
 		fmt.Sprintf("GIT_ASKPASS=%s", GIT_ASK_PASS),
 		fmt.Sprintf("GIT_USERNAME=%s", userName),
 		fmt.Sprintf("GIT_PASSWORD=%s", password),
-	)
+	}
+}
+
+// sshEnv makes git use the private key at sshKeyPath, host key is verified against knownHostsPath and a host
+// which is not in it is rejected
+func (impl *GitCliUtil) sshEnv(sshKeyPath, knownHostsPath string) []string {
+	return []string{
+		fmt.Sprintf("GIT_SSH_COMMAND=ssh -i %s -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o UserKnownHostsFile=%s", sshKeyPath, knownHostsPath),
+	}
+}
+
+func (impl *GitCliUtil) runCommandWithEnv(cmd *exec.Cmd, env []string) (response, errMsg string, err error) {
+	cmd.Env = append(os.Environ(), env...)
 	return impl.runCommand(cmd)
 }
 
@@ -103,15 +180,28 @@ func (impl *GitCliUtil) Init(rootDir string, remoteUrl string, isBare bool) erro
 
 func (impl *GitCliUtil) Clone(rootDir string, remoteUrl string, username string, password string) (response, errMsg string, err error) {
 	impl.logger.Infow("input", rootDir, remoteUrl, username)
+	return impl.clone(rootDir, remoteUrl, impl.credEnv(username, password))
+}
+
+// CloneWithSshKey is Clone over ssh authenticated with the private key at sshKeyPath
+func (impl *GitCliUtil) CloneWithSshKey(rootDir string, remoteUrl string, sshKeyPath string, knownHostsPath string) (response, errMsg string, err error) {
+	impl.logger.Infow("input", rootDir, remoteUrl)
+	return impl.clone(rootDir, remoteUrl, impl.sshEnv(sshKeyPath, knownHostsPath))
+}
+
+func (impl *GitCliUtil) clone(rootDir string, remoteUrl string, env []string) (response, errMsg string, err error) {
 	err = impl.Init(rootDir, remoteUrl, false)
 	if err != nil {
 		return "", "", err
 	}
-	response, errMsg, err = impl.Fetch(rootDir, username, password)
+	response, errMsg, err = impl.fetch(rootDir, env)
 	if err == nil && errMsg == "" {
 		impl.logger.Warn("git fetch completed, pulling master branch data from remote origin")
-		response, errMsg, err = impl.ListBranch(rootDir, username, password)
-		branches := strings.Split(response, "\n")
+		response, errMsg, err = impl.listBranch(rootDir, env)
+		var branches []string
+		if len(strings.TrimSpace(response)) > 0 {
+			branches = strings.Split(response, "\n")
+		}
 		impl.logger.Info(branches)
 		branch := ""
 		for _, item := range branches {
@@ -125,7 +215,7 @@ func (impl *GitCliUtil) Clone(rootDir string, remoteUrl string, username string,
 			// only fetch will work, as we don't have any branch for pull
 			return "", "", nil
 		}
-		response, errMsg, err = impl.Pull(rootDir, username, password, branch)
+		response, errMsg, err = impl.pull(rootDir, env, branch)
 		if err != nil {
 			impl.logger.Errorw("error on git pull", "branch", branch, "err", err)
 			return "", "", err
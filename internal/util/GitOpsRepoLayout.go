@@ -0,0 +1,142 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+)
+
+const (
+	GITOPS_REPO_LAYOUT_APP    = "APP"
+	GITOPS_REPO_LAYOUT_TEAM   = "TEAM"
+	GITOPS_REPO_LAYOUT_GLOBAL = "GLOBAL"
+)
+
+// GITOPS_TEAM_REPO_PREFIX prefixes gitops repo of a team so that it is not taken for the repo of an app named like
+// the team
+const GITOPS_TEAM_REPO_PREFIX = "team-"
+
+// GetChartLocationInGitOpsRepo returns path of chart in gitops repo, appDir is empty for apps having a repo of their own
+func GetChartLocationInGitOpsRepo(appDir string, referenceTemplate string, version string) string {
+	return filepath.Join(appDir, referenceTemplate, version)
+}
+
+// GetAppDirInGitOpsRepo returns directory of app in gitops repo shared by many apps, empty when the chart location is
+// not in a directory of app i.e. app has a repo of its own
+func GetAppDirInGitOpsRepo(appName string, chartLocation string) string {
+	if strings.HasPrefix(chartLocation, appName+"/") {
+		return appName
+	}
+	return ""
+}
+
+// GitOpsRepoLock serializes changes to a gitops repo within devtron, repos shared by many apps get commits of
+// concurrent deployments which otherwise fail to push. Lock is held in memory of the process as devtron runs as a
+// single replica, with more replicas pushes from different replicas to a shared repo can still conflict, such
+// commits are retried by serializedGitClient on top of the changes of other replica
+type GitOpsRepoLock struct {
+	mutex     sync.Mutex
+	repoLocks map[string]*sync.Mutex
+}
+
+func NewGitOpsRepoLock() *GitOpsRepoLock {
+	return &GitOpsRepoLock{repoLocks: make(map[string]*sync.Mutex)}
+}
+
+// Lock locks the repo and returns func to unlock it
+func (lock *GitOpsRepoLock) Lock(repoName string) func() {
+	lock.mutex.Lock()
+	repoLock, ok := lock.repoLocks[repoName]
+	if !ok {
+		repoLock = &sync.Mutex{}
+		lock.repoLocks[repoName] = repoLock
+	}
+	lock.mutex.Unlock()
+	repoLock.Lock()
+	return repoLock.Unlock
+}
+
+const (
+	gitOpsCommitRetries      = 3
+	gitOpsCommitRetryBackoff = time.Second
+)
+
+// serializedGitClient takes lock of repo for changes made through git provider. A commit rejected as the branch
+// moved since it was read, i.e. by a push of another replica, is made again as providers read the branch afresh.
+type serializedGitClient struct {
+	GitClient
+	repoLock     *GitOpsRepoLock
+	retries      int
+	retryBackoff time.Duration
+}
+
+func newSerializedGitClient(client GitClient, repoLock *GitOpsRepoLock) GitClient {
+	if client == nil {
+		return nil
+	}
+	return &serializedGitClient{GitClient: client, repoLock: repoLock, retries: gitOpsCommitRetries,
+		retryBackoff: gitOpsCommitRetryBackoff}
+}
+
+func (impl *serializedGitClient) CreateRepository(name, description, bitbucketWorkspaceId, bitbucketProjectKey, userName, userEmailId string) (string, bool, DetailedErrorGitOpsConfigActions) {
+	defer impl.repoLock.Lock(name)()
+	return impl.GitClient.CreateRepository(name, description, bitbucketWorkspaceId, bitbucketProjectKey, userName, userEmailId)
+}
+
+func (impl *serializedGitClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId string) (string, error) {
+	defer impl.repoLock.Lock(config.ChartRepoName)()
+	return impl.retryOnConflict(config.ChartRepoName, func() (string, error) {
+		return impl.GitClient.CommitValues(config, bitbucketWorkspaceId)
+	})
+}
+
+func (impl *serializedGitClient) CreateReadme(name, userName, userEmailId, owner string) (string, error) {
+	defer impl.repoLock.Lock(name)()
+	return impl.retryOnConflict(name, func() (string, error) {
+		return impl.GitClient.CreateReadme(name, userName, userEmailId, owner)
+	})
+}
+
+func (impl *serializedGitClient) retryOnConflict(repoName string, commit func() (string, error)) (string, error) {
+	backoff := impl.retryBackoff
+	commitHash, err := commit()
+	for attempt := 1; attempt <= impl.retries && isGitOpsCommitConflict(err); attempt++ {
+		time.Sleep(backoff)
+		backoff *= 2
+		commitHash, err = commit()
+	}
+	if isGitOpsCommitConflict(err) {
+		err = fmt.Errorf("commit to gitops repo %s conflicted with other changes %d times: %w", repoName, impl.retries+1, err)
+	}
+	return commitHash, err
+}
+
+// isGitOpsCommitConflict is true for a push which is not a fast-forward of the remote branch and for an api commit
+// rejected as the file or branch changed since it was read
+func isGitOpsCommitConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch e := err.(type) {
+	case *github.ErrorResponse:
+		return e.Response != nil && e.Response.StatusCode == http.StatusConflict
+	case azuredevops.WrappedError:
+		return e.StatusCode != nil && *e.StatusCode == http.StatusConflict
+	case *GiteaErrorResponse:
+		return e.StatusCode == http.StatusConflict
+	}
+	message := err.Error()
+	return strings.Contains(message, "non-fast-forward") || strings.Contains(message, "fetch first") ||
+		strings.Contains(message, "sha does not match")
+}
+
+func (impl *serializedGitClient) CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (*PullRequest, error) {
+	defer impl.repoLock.Lock(config.ChartRepoName)()
+	return impl.GitClient.CreatePullRequest(config, bitbucketWorkspaceId)
+}
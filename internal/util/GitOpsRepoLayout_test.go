@@ -0,0 +1,162 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/devtron-labs/devtron/util"
+	"github.com/google/go-github/github"
+)
+
+func TestChartLocationInGitOpsRepo(t *testing.T) {
+	tests := []struct {
+		name          string
+		appName       string
+		chartLocation string
+		appDir        string
+		newLocation   string
+	}{
+		{
+			name:          "repo of app",
+			appName:       "app1",
+			chartLocation: "reference-chart_4-14-0/4.14.0",
+			newLocation:   "reference-chart_4-15-0/4.15.0",
+		},
+		{
+			name:          "repo shared by many apps",
+			appName:       "app1",
+			chartLocation: "app1/reference-chart_4-14-0/4.14.0",
+			appDir:        "app1",
+			newLocation:   "app1/reference-chart_4-15-0/4.15.0",
+		},
+		{
+			name:          "directory of other app with same prefix",
+			appName:       "app",
+			chartLocation: "app1/reference-chart_4-14-0/4.14.0",
+			newLocation:   "reference-chart_4-15-0/4.15.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appDir := GetAppDirInGitOpsRepo(tt.appName, tt.chartLocation)
+			if appDir != tt.appDir {
+				t.Errorf("expected app dir %q, got %q", tt.appDir, appDir)
+			}
+			newLocation := GetChartLocationInGitOpsRepo(appDir, "reference-chart_4-15-0", "4.15.0")
+			if newLocation != tt.newLocation {
+				t.Errorf("expected chart location %q, got %q", tt.newLocation, newLocation)
+			}
+		})
+	}
+}
+
+func TestGitOpsRepoLock(t *testing.T) {
+	repoLock := NewGitOpsRepoLock()
+	var wg sync.WaitGroup
+	inRepo := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := repoLock.Lock("devtron-gitops")
+			inRepo++
+			if inRepo != 1 {
+				t.Errorf("expected one change in repo at a time, found %d", inRepo)
+			}
+			inRepo--
+			unlock()
+		}()
+	}
+	wg.Wait()
+	// lock of a repo does not block other repos
+	unlock := repoLock.Lock("team-a")
+	repoLock.Lock("team-b")()
+	unlock()
+}
+
+type conflictingGitClient struct {
+	GitClient
+	conflicts int
+	err       error
+	commits   int
+}
+
+func (impl *conflictingGitClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId string) (string, error) {
+	impl.commits++
+	if impl.commits <= impl.conflicts {
+		return "", impl.err
+	}
+	return "commit-hash", nil
+}
+
+func TestSerializedGitClient_CommitValuesRetriesConflicts(t *testing.T) {
+	nonFastForward := errors.New("non-fast-forward update: refs/heads/master")
+	tests := []struct {
+		name      string
+		conflicts int
+		err       error
+		commits   int
+		wantErr   bool
+	}{
+		{name: "no conflict", commits: 1},
+		{name: "push of other replica", conflicts: 2, err: nonFastForward, commits: 3},
+		{name: "api commit of stale file", conflicts: 1, err: &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusConflict}}, commits: 2},
+		{name: "conflicts exhaust retries", conflicts: 10, err: nonFastForward, commits: 4, wantErr: true},
+		{name: "other errors are not retried", conflicts: 10, err: errors.New("authentication required"), commits: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &conflictingGitClient{conflicts: tt.conflicts, err: tt.err}
+			impl := &serializedGitClient{GitClient: client, repoLock: NewGitOpsRepoLock(), retries: gitOpsCommitRetries}
+			commitHash, err := impl.CommitValues(&ChartConfig{ChartRepoName: "devtron-team-payments"}, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CommitValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && commitHash != "commit-hash" {
+				t.Errorf("expected commit hash, got %q", commitHash)
+			}
+			if client.commits != tt.commits {
+				t.Errorf("expected %d commits, got %d", tt.commits, client.commits)
+			}
+			if tt.wantErr && !errors.Is(err, tt.err) {
+				t.Errorf("expected error to wrap %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestChartTemplateService_GetGitOpsRepoNameForApp(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   string
+		appName  string
+		repoName string
+		appDir   string
+		conflict bool
+	}{
+		{name: "app layout", layout: "APP", appName: "payments", repoName: "devtron-payments"},
+		{name: "team layout", layout: GITOPS_REPO_LAYOUT_TEAM, appName: "payments", repoName: "devtron-team-payments", appDir: "payments"},
+		{name: "app named like team repo", layout: GITOPS_REPO_LAYOUT_TEAM, appName: "team-payments", conflict: true},
+		{name: "app with team prefix", layout: GITOPS_REPO_LAYOUT_TEAM, appName: "team-billing", conflict: true},
+		{name: "global layout", layout: GITOPS_REPO_LAYOUT_GLOBAL, appName: "payments", repoName: "devtron-devtron-gitops", appDir: "payments"},
+		{name: "app named like global repo", layout: GITOPS_REPO_LAYOUT_GLOBAL, appName: "devtron-gitops", conflict: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			impl := ChartTemplateServiceImpl{globalEnvVariables: &util.GlobalEnvVariables{GitOpsRepoPrefix: "devtron",
+				GitOpsRepoLayout: tt.layout, GitOpsSharedRepoName: "devtron-gitops"}}
+			repoName, appDir, err := impl.GetGitOpsRepoNameForApp(tt.appName, "payments")
+			if tt.conflict {
+				if apiErr, ok := err.(*ApiError); !ok || apiErr.HttpStatusCode != 409 {
+					t.Errorf("expected conflict, got %v", err)
+				}
+				return
+			}
+			if err != nil || repoName != tt.repoName || appDir != tt.appDir {
+				t.Errorf("GetGitOpsRepoNameForApp() = %s, %s, %v, want %s, %s", repoName, appDir, err, tt.repoName, tt.appDir)
+			}
+		})
+	}
+}
@@ -23,9 +23,11 @@ import (
 	"io/ioutil"
 	http2 "net/http"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	bean2 "github.com/devtron-labs/devtron/api/bean"
@@ -35,14 +37,18 @@ import (
 	"github.com/ktrysmt/go-bitbucket"
 	"github.com/xanzy/go-gitlab"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh/knownhosts"
 	"golang.org/x/oauth2"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
 )
 
 const (
 	GIT_WORKING_DIR       = "/tmp/gitops/"
+	GIT_SSH_DIR           = ".ssh"
 	GetRepoUrlStage       = "Get Repo Url"
 	CreateRepoStage       = "Create Repo"
 	CloneHttpStage        = "Clone Http"
@@ -52,8 +58,11 @@ const (
 	GITHUB_PROVIDER       = "GITHUB"
 	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
 	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
+	GITEA_PROVIDER        = "GITEA"
+	BARE_GIT_PROVIDER     = "BARE_GIT"
 	GITHUB_API_V3         = "api/v3"
 	GITHUB_HOST           = "github.com"
+	GITOPS_DEFAULT_BRANCH = "master"
 )
 
 type GitClient interface {
@@ -62,6 +71,11 @@ type GitClient interface {
 	GetRepoUrl(projectName string, repoOptions *bitbucket.RepositoryOptions) (repoUrl string, err error)
 	DeleteRepository(name, userName, gitHubOrgName, azureProjectName string, repoOptions *bitbucket.RepositoryOptions) error
 	CreateReadme(name, userName, userEmailId, owner string) (string, error)
+	// CreatePullRequest creates config.Branch from master, commits the values to it and opens a pull request of it into master
+	CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error)
+	GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error)
+	// ClosePullRequest closes the pull request without merge, it is used for pull requests superseded by a newer release
+	ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error
 }
 
 type GitFactory struct {
@@ -71,6 +85,7 @@ type GitFactory struct {
 	logger           *zap.SugaredLogger
 	gitOpsRepository repository.GitOpsConfigRepository
 	gitCliUtil       *GitCliUtil
+	repoLock         *GitOpsRepoLock
 }
 
 type DetailedErrorGitOpsConfigActions struct {
@@ -92,7 +107,7 @@ func (factory *GitFactory) Reload() error {
 	if err != nil {
 		return err
 	}
-	factory.Client = client
+	factory.Client = newSerializedGitClient(client, factory.repoLock)
 	logger.Infow(" gitops details reload success")
 	return nil
 }
@@ -140,6 +155,9 @@ func (factory *GitFactory) NewClientForValidation(gitOpsConfig *bean2.GitOpsConf
 		GitHost:            gitOpsConfig.Host,
 		AzureToken:         gitOpsConfig.Token,
 		AzureProject:       gitOpsConfig.AzureProjectName,
+		GiteaOrgId:         gitOpsConfig.GiteaOrgId,
+		SshPrivateKey:      gitOpsConfig.SshPrivateKey,
+		SshKnownHosts:      gitOpsConfig.SshKnownHosts,
 	}
 	gitService := NewGitServiceImpl(cfg, logger, factory.gitCliUtil)
 	//factory.gitService = gitService
@@ -163,13 +181,15 @@ func NewGitFactory(logger *zap.SugaredLogger, gitOpsRepository repository.GitOps
 	if err != nil {
 		logger.Errorw("error in creating gitOps client", "err", err, "gitProvider", cfg.GitProvider)
 	}
+	repoLock := NewGitOpsRepoLock()
 	return &GitFactory{
-		Client:           client,
+		Client:           newSerializedGitClient(client, repoLock),
 		logger:           logger,
 		gitService:       gitService,
 		gitOpsRepository: gitOpsRepository,
 		GitWorkingDir:    cfg.GitWorkingDir,
 		gitCliUtil:       gitCliUtil,
+		repoLock:         repoLock,
 	}, nil
 }
 
@@ -186,6 +206,11 @@ type GitConfig struct {
 	AzureProject         string
 	BitbucketWorkspaceId string
 	BitbucketProjectKey  string
+	GiteaOrgId           string
+	// SshPrivateKey authenticates ssh urls of bare git provider, http urls use GitUserName and GitToken
+	SshPrivateKey string
+	// SshKnownHosts verifies host key of git server for ssh urls, unknown hosts are rejected
+	SshKnownHosts string
 }
 
 func GetGitConfig(gitOpsRepository repository.GitOpsConfigRepository) (*GitConfig, error) {
@@ -215,6 +240,9 @@ func GetGitConfig(gitOpsRepository repository.GitOpsConfigRepository) (*GitConfi
 		AzureProject:         gitOpsConfig.AzureProject,
 		BitbucketWorkspaceId: gitOpsConfig.BitBucketWorkspaceId,
 		BitbucketProjectKey:  gitOpsConfig.BitBucketProjectKey,
+		GiteaOrgId:           gitOpsConfig.GiteaOrgId,
+		SshPrivateKey:        gitOpsConfig.SshPrivateKey,
+		SshKnownHosts:        gitOpsConfig.SshKnownHosts,
 	}
 	return cfg, err
 }
@@ -239,6 +267,12 @@ func NewGitOpsClient(config *GitConfig, logger *zap.SugaredLogger, gitService Gi
 	} else if config.GitProvider == BITBUCKET_PROVIDER {
 		gitBitbucketClient := NewGitBitbucketClient(config.GitUserName, config.GitToken, config.GitHost, logger, gitService)
 		return gitBitbucketClient, nil
+	} else if config.GitProvider == GITEA_PROVIDER {
+		gitGiteaClient, err := NewGitGiteaClient(config.GitHost, config.GitToken, config.GiteaOrgId, logger, gitService)
+		return gitGiteaClient, err
+	} else if config.GitProvider == BARE_GIT_PROVIDER {
+		gitBareClient, err := NewGitBareClient(config.GitHost, logger, gitService)
+		return gitBareClient, err
 	} else {
 		logger.Errorw("no gitops config provided, gitops will not work ")
 		return nil, nil
@@ -471,7 +505,7 @@ func (impl GitLabClient) checkIfFileExists(projectName, ref, file string) (exist
 }
 
 func (impl GitLabClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId string) (commitHash string, err error) {
-	branch := "master"
+	branch := config.getBranch()
 	path := filepath.Join(config.ChartLocation, config.FileName)
 	exists, err := impl.checkIfFileExists(config.ChartRepoName, branch, path)
 	var fileAction gitlab.FileActionValue
@@ -494,6 +528,69 @@ func (impl GitLabClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId
 	return c.ID, err
 }
 
+func (impl GitLabClient) CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	pid := fmt.Sprintf("%s/%s", impl.config.GitlabGroupPath, config.ChartRepoName)
+	_, _, err = impl.client.Branches.CreateBranch(pid, &gitlab.CreateBranchOptions{Branch: &config.Branch, Ref: gitlab.String(GITOPS_DEFAULT_BRANCH)})
+	if err != nil {
+		impl.logger.Errorw("error in creating branch gitlab", "pid", pid, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	_, err = impl.CommitValues(config, bitbucketWorkspaceId)
+	if err != nil {
+		impl.logger.Errorw("error in commit gitlab", "pid", pid, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	mr, _, err := impl.client.MergeRequests.CreateMergeRequest(pid, &gitlab.CreateMergeRequestOptions{
+		Title:              gitlab.String(strings.TrimSpace(config.ReleaseMessage)),
+		SourceBranch:       &config.Branch,
+		TargetBranch:       gitlab.String(GITOPS_DEFAULT_BRANCH),
+		RemoveSourceBranch: gitlab.Bool(true),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating merge request gitlab", "pid", pid, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(mr), nil
+}
+
+func (impl GitLabClient) GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	pid := fmt.Sprintf("%s/%s", impl.config.GitlabGroupPath, repoName)
+	mr, _, err := impl.client.MergeRequests.GetMergeRequest(pid, pullRequestId, &gitlab.GetMergeRequestsOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in getting merge request gitlab", "pid", pid, "mergeRequest", pullRequestId, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(mr), nil
+}
+
+func (impl GitLabClient) ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error {
+	pid := fmt.Sprintf("%s/%s", impl.config.GitlabGroupPath, repoName)
+	_, _, err := impl.client.MergeRequests.UpdateMergeRequest(pid, pullRequestId, &gitlab.UpdateMergeRequestOptions{StateEvent: gitlab.String("close")})
+	if err != nil {
+		impl.logger.Errorw("error in closing merge request gitlab", "pid", pid, "mergeRequest", pullRequestId, "err", err)
+	}
+	return err
+}
+
+func (impl GitLabClient) toPullRequest(mr *gitlab.MergeRequest) *PullRequest {
+	pullRequest := &PullRequest{Id: mr.IID, Url: mr.WebURL, State: PULL_REQUEST_STATE_OPEN}
+	switch mr.State {
+	case "merged":
+		pullRequest.State = PULL_REQUEST_STATE_MERGED
+		//fast forward merges have no merge commit, master points to the head of merge request
+		pullRequest.MergeCommitHash = mr.MergeCommitSHA
+		if len(pullRequest.MergeCommitHash) == 0 {
+			pullRequest.MergeCommitHash = mr.SquashCommitSHA
+		}
+		if len(pullRequest.MergeCommitHash) == 0 {
+			pullRequest.MergeCommitHash = mr.SHA
+		}
+	case "closed":
+		pullRequest.State = PULL_REQUEST_STATE_CLOSED
+	}
+	return pullRequest
+}
+
 type ChartConfig struct {
 	ChartName      string
 	ChartLocation  string
@@ -503,6 +600,31 @@ type ChartConfig struct {
 	ChartRepoName  string
 	UserName       string
 	UserEmailId    string
+	Branch         string //branch to commit to, master when empty
+}
+
+func (config *ChartConfig) getBranch() string {
+	if len(config.Branch) > 0 {
+		return config.Branch
+	}
+	return GITOPS_DEFAULT_BRANCH
+}
+
+type PullRequestState string
+
+const (
+	PULL_REQUEST_STATE_OPEN   PullRequestState = "OPEN"
+	PULL_REQUEST_STATE_MERGED PullRequestState = "MERGED"
+	PULL_REQUEST_STATE_CLOSED PullRequestState = "CLOSED"
+)
+
+// PullRequest is a pull request (merge request on gitlab) of a gitops branch into master, MergeCommitHash is the
+// commit on master once it is merged
+type PullRequest struct {
+	Id              int
+	Url             string
+	State           PullRequestState
+	MergeCommitHash string
 }
 
 //-------------------- go-git integration -------------------
@@ -514,6 +636,11 @@ type GitService interface {
 
 	GetCloneDirectory(targetDir string) (clonedDir string)
 	Pull(repoRoot string) (err error)
+	// LsRemote returns the refs of remote repository, it is empty for a repository without commits
+	LsRemote(url string) (refs string, err error)
+	// ShallowFetchHead updates the checkout of targetDir to head of default branch of url without its history, the
+	// checkout is kept for later calls
+	ShallowFetchHead(url, targetDir string) (checkoutDir string, err error)
 }
 type GitServiceImpl struct {
 	Auth       *http.BasicAuth
@@ -540,7 +667,17 @@ func (impl GitServiceImpl) GetCloneDirectory(targetDir string) (clonedDir string
 func (impl GitServiceImpl) Clone(url, targetDir string) (clonedDir string, err error) {
 	impl.logger.Debugw("git checkout ", "url", url, "dir", targetDir)
 	clonedDir = filepath.Join(impl.config.GitWorkingDir, targetDir)
-	_, errorMsg, err := impl.gitCliUtil.Clone(clonedDir, url, impl.Auth.Username, impl.Auth.Password)
+	var errorMsg string
+	if impl.isSshUrl(url) {
+		var sshKeyPath, knownHostsPath string
+		sshKeyPath, knownHostsPath, err = impl.writeSshKey()
+		if err != nil {
+			return "", err
+		}
+		_, errorMsg, err = impl.gitCliUtil.CloneWithSshKey(clonedDir, url, sshKeyPath, knownHostsPath)
+	} else {
+		_, errorMsg, err = impl.gitCliUtil.Clone(clonedDir, url, impl.Auth.Username, impl.Auth.Password)
+	}
 	if err != nil {
 		impl.logger.Errorw("error in git checkout", "url", url, "targetDir", targetDir, "err", err)
 		return "", err
@@ -551,6 +688,29 @@ func (impl GitServiceImpl) Clone(url, targetDir string) (clonedDir string, err e
 	return clonedDir, nil
 }
 
+func (impl GitServiceImpl) ShallowFetchHead(url, targetDir string) (checkoutDir string, err error) {
+	checkoutDir = filepath.Join(impl.config.GitWorkingDir, targetDir)
+	var errorMsg string
+	if impl.isSshUrl(url) {
+		var sshKeyPath, knownHostsPath string
+		sshKeyPath, knownHostsPath, err = impl.writeSshKey()
+		if err != nil {
+			return "", err
+		}
+		_, errorMsg, err = impl.gitCliUtil.ShallowFetchHeadWithSshKey(checkoutDir, url, sshKeyPath, knownHostsPath)
+	} else {
+		_, errorMsg, err = impl.gitCliUtil.ShallowFetchHead(checkoutDir, url, impl.Auth.Username, impl.Auth.Password)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in git shallow fetch", "url", url, "targetDir", targetDir, "errorMsg", errorMsg, "err", err)
+		return "", err
+	}
+	if errorMsg != "" {
+		return "", fmt.Errorf(errorMsg)
+	}
+	return checkoutDir, nil
+}
+
 func (impl GitServiceImpl) CommitAndPushAllChanges(repoRoot, commitMsg, name, emailId string) (commitHash string, err error) {
 	repo, workTree, err := impl.getRepoAndWorktree(repoRoot)
 	if err != nil {
@@ -578,8 +738,12 @@ func (impl GitServiceImpl) CommitAndPushAllChanges(repoRoot, commitMsg, name, em
 	}
 	impl.logger.Debugw("git hash", "repo", repoRoot, "hash", commit.String())
 	//-----------push
+	auth, err := impl.getRemoteAuth(repo)
+	if err != nil {
+		return commit.String(), err
+	}
 	err = repo.Push(&git.PushOptions{
-		Auth: impl.Auth,
+		Auth: auth,
 	})
 
 	return commit.String(), err
@@ -595,7 +759,7 @@ func (impl GitServiceImpl) getRepoAndWorktree(repoRoot string) (*git.Repository,
 }
 
 func (impl GitServiceImpl) ForceResetHead(repoRoot string) (err error) {
-	_, workTree, err := impl.getRepoAndWorktree(repoRoot)
+	repo, workTree, err := impl.getRepoAndWorktree(repoRoot)
 	if err != nil {
 		return err
 	}
@@ -603,8 +767,12 @@ func (impl GitServiceImpl) ForceResetHead(repoRoot string) (err error) {
 	if err != nil {
 		return err
 	}
+	auth, err := impl.getRemoteAuth(repo)
+	if err != nil {
+		return err
+	}
 	err = workTree.Pull(&git.PullOptions{
-		Auth:         impl.Auth,
+		Auth:         auth,
 		Force:        true,
 		SingleBranch: true,
 	})
@@ -626,13 +794,17 @@ func (impl GitServiceImpl) CommitValues(config *ChartConfig) (commitHash string,
 }
 
 func (impl GitServiceImpl) Pull(repoRoot string) (err error) {
-	_, workTree, err := impl.getRepoAndWorktree(repoRoot)
+	repo, workTree, err := impl.getRepoAndWorktree(repoRoot)
+	if err != nil {
+		return err
+	}
+	auth, err := impl.getRemoteAuth(repo)
 	if err != nil {
 		return err
 	}
 	//-----------pull
 	err = workTree.PullContext(context.Background(), &git.PullOptions{
-		Auth: impl.Auth,
+		Auth: auth,
 	})
 	if err != nil && err.Error() == "already up-to-date" {
 		err = nil
@@ -641,6 +813,101 @@ func (impl GitServiceImpl) Pull(repoRoot string) (err error) {
 	return err
 }
 
+func (impl GitServiceImpl) LsRemote(url string) (refs string, err error) {
+	var errorMsg string
+	if impl.isSshUrl(url) {
+		var sshKeyPath, knownHostsPath string
+		sshKeyPath, knownHostsPath, err = impl.writeSshKey()
+		if err != nil {
+			return "", err
+		}
+		refs, errorMsg, err = impl.gitCliUtil.LsRemoteWithSshKey(url, sshKeyPath, knownHostsPath)
+	} else {
+		refs, errorMsg, err = impl.gitCliUtil.LsRemote(url, impl.Auth.Username, impl.Auth.Password)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in git ls-remote", "url", url, "errorMsg", errorMsg, "err", err)
+		return "", err
+	}
+	return refs, nil
+}
+
+// getRemoteAuth returns the auth for origin of repo, ssh key for ssh urls and basic auth for http urls, git
+// protocol has no auth
+func (impl GitServiceImpl) getRemoteAuth(repo *git.Repository) (transport.AuthMethod, error) {
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return nil, err
+	}
+	if len(remote.Config().URLs) == 0 {
+		return impl.Auth, nil
+	}
+	remoteUrl := remote.Config().URLs[0]
+	endpoint, err := transport.NewEndpoint(remoteUrl)
+	if err != nil {
+		return nil, err
+	}
+	switch endpoint.Protocol {
+	case "ssh":
+		_, knownHostsPath, err := impl.writeSshKey()
+		if err != nil {
+			return nil, err
+		}
+		user := endpoint.User
+		if len(user) == 0 {
+			user = "git"
+		}
+		auth, err := ssh.NewPublicKeys(user, []byte(impl.config.SshPrivateKey), "")
+		if err != nil {
+			return nil, err
+		}
+		auth.HostKeyCallback, err = knownhosts.New(knownHostsPath)
+		return auth, err
+	case "git", "file":
+		return nil, nil
+	default:
+		return impl.Auth, nil
+	}
+}
+
+func (impl GitServiceImpl) isSshUrl(url string) bool {
+	endpoint, err := transport.NewEndpoint(url)
+	return err == nil && endpoint.Protocol == "ssh" && len(impl.config.SshPrivateKey) > 0
+}
+
+// writeSshKey writes ssh private key and known hosts of gitops config used by git cli, returns the key and the
+// known hosts file
+func (impl GitServiceImpl) writeSshKey() (sshKeyPath string, knownHostsPath string, err error) {
+	if len(strings.TrimSpace(impl.config.SshKnownHosts)) == 0 {
+		return "", "", fmt.Errorf("ssh known hosts are required in gitops config for ssh urls")
+	}
+	sshDir := filepath.Join(impl.config.GitWorkingDir, GIT_SSH_DIR)
+	err = os.MkdirAll(sshDir, 0700)
+	if err != nil {
+		return "", "", err
+	}
+	sshKeyPath = filepath.Join(sshDir, "id_gitops")
+	privateKey := impl.config.SshPrivateKey
+	if !strings.HasSuffix(privateKey, "\n") {
+		// ssh rejects a key without trailing new line
+		privateKey += "\n"
+	}
+	err = ioutil.WriteFile(sshKeyPath, []byte(privateKey), 0600)
+	if err != nil {
+		return "", "", err
+	}
+	knownHostsPath = filepath.Join(sshDir, "known_hosts")
+	knownHosts := impl.config.SshKnownHosts
+	if !strings.HasSuffix(knownHosts, "\n") {
+		knownHosts += "\n"
+	}
+	err = ioutil.WriteFile(knownHostsPath, []byte(knownHosts), 0600)
+	if err != nil {
+		return "", "", err
+	}
+	return sshKeyPath, knownHostsPath, nil
+}
+
 //github
 
 type GitHubClient struct {
@@ -776,7 +1043,7 @@ func (impl GitHubClient) CreateReadme(repoName, userName, userEmailId, owner str
 }
 
 func (impl GitHubClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId string) (commitHash string, err error) {
-	branch := "master"
+	branch := config.getBranch()
 	path := filepath.Join(config.ChartLocation, config.FileName)
 	ctx := context.Background()
 	newFile := false
@@ -819,6 +1086,63 @@ func (impl GitHubClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId
 	return *c.SHA, nil
 }
 
+func (impl GitHubClient) CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	ctx := context.Background()
+	masterRef, _, err := impl.client.Git.GetRef(ctx, impl.org, config.ChartRepoName, "refs/heads/"+GITOPS_DEFAULT_BRANCH)
+	if err != nil {
+		impl.logger.Errorw("error in getting master ref github", "repo", config.ChartRepoName, "err", err)
+		return nil, err
+	}
+	branchRef := "refs/heads/" + config.Branch
+	_, _, err = impl.client.Git.CreateRef(ctx, impl.org, config.ChartRepoName, &github.Reference{Ref: &branchRef, Object: &github.GitObject{SHA: masterRef.Object.SHA}})
+	if err != nil {
+		impl.logger.Errorw("error in creating branch github", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	_, err = impl.CommitValues(config, bitbucketWorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	pr, _, err := impl.client.PullRequests.Create(ctx, impl.org, config.ChartRepoName, &github.NewPullRequest{
+		Title: github.String(strings.TrimSpace(config.ReleaseMessage)),
+		Head:  &config.Branch,
+		Base:  github.String(GITOPS_DEFAULT_BRANCH),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request github", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(pr), nil
+}
+
+func (impl GitHubClient) GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	pr, _, err := impl.client.PullRequests.Get(context.Background(), impl.org, repoName, pullRequestId)
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request github", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(pr), nil
+}
+
+func (impl GitHubClient) ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error {
+	_, _, err := impl.client.PullRequests.Edit(context.Background(), impl.org, repoName, pullRequestId, &github.PullRequest{State: github.String("closed")})
+	if err != nil {
+		impl.logger.Errorw("error in closing pull request github", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+	}
+	return err
+}
+
+func (impl GitHubClient) toPullRequest(pr *github.PullRequest) *PullRequest {
+	pullRequest := &PullRequest{Id: pr.GetNumber(), Url: pr.GetHTMLURL(), State: PULL_REQUEST_STATE_OPEN}
+	if pr.GetMerged() {
+		pullRequest.State = PULL_REQUEST_STATE_MERGED
+		pullRequest.MergeCommitHash = pr.GetMergeCommitSHA()
+	} else if pr.GetState() == "closed" {
+		pullRequest.State = PULL_REQUEST_STATE_CLOSED
+	}
+	return pullRequest
+}
+
 func (impl GitHubClient) GetRepoUrl(projectName string, repoOptions *bitbucket.RepositoryOptions) (repoUrl string, err error) {
 	ctx := context.Background()
 	repo, _, err := impl.client.Repositories.Get(ctx, impl.org, projectName)
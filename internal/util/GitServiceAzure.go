@@ -8,6 +8,7 @@ import (
 	"github.com/microsoft/azure-devops-go-api/azuredevops/git"
 	"go.uber.org/zap"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -137,8 +138,8 @@ func (impl GitAzureClient) CreateReadme(repoName, userName, userEmailId, owner s
 }
 
 func (impl GitAzureClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId string) (commitHash string, err error) {
-	branch := "master"
-	branchfull := "refs/heads/master"
+	branch := config.getBranch()
+	branchfull := "refs/heads/" + branch
 	path := filepath.Join(config.ChartLocation, config.FileName)
 	ctx := context.Background()
 	newFile := true
@@ -148,9 +149,10 @@ func (impl GitAzureClient) CommitValues(config *ChartConfig, bitbucketWorkspaceI
 	// if branch doesn't exists use default hash
 	clientAzure := *impl.client
 	fc, err := clientAzure.GetItem(ctx, git.GetItemArgs{
-		RepositoryId: &config.ChartRepoName,
-		Path:         &path,
-		Project:      &impl.project,
+		RepositoryId:      &config.ChartRepoName,
+		Path:              &path,
+		Project:           &impl.project,
+		VersionDescriptor: &git.GitVersionDescriptor{Version: &branch, VersionType: &git.GitVersionTypeValues.Branch},
 	})
 	if err != nil {
 		notFoundStatus := 404
@@ -235,6 +237,94 @@ func (impl GitAzureClient) CommitValues(config *ChartConfig, bitbucketWorkspaceI
 	return commitId, nil
 }
 
+func (impl GitAzureClient) CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	ctx := context.Background()
+	clientAzure := *impl.client
+	masterBranch := GITOPS_DEFAULT_BRANCH
+	master, err := clientAzure.GetBranch(ctx, git.GetBranchArgs{Project: &impl.project, Name: &masterBranch, RepositoryId: &config.ChartRepoName})
+	if err != nil {
+		impl.logger.Errorw("error in fetching master branch from azure devops", "repo", config.ChartRepoName, "err", err)
+		return nil, err
+	}
+	sourceRef := "refs/heads/" + config.Branch
+	targetRef := "refs/heads/" + GITOPS_DEFAULT_BRANCH
+	newBranchObjId := "0000000000000000000000000000000000000000"
+	_, err = clientAzure.UpdateRefs(ctx, git.UpdateRefsArgs{
+		RefUpdates:   &[]git.GitRefUpdate{{Name: &sourceRef, OldObjectId: &newBranchObjId, NewObjectId: master.Commit.CommitId}},
+		RepositoryId: &config.ChartRepoName,
+		Project:      &impl.project,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating branch azure", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	_, err = impl.CommitValues(config, bitbucketWorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(config.ReleaseMessage)
+	pr, err := clientAzure.CreatePullRequest(ctx, git.CreatePullRequestArgs{
+		GitPullRequestToCreate: &git.GitPullRequest{SourceRefName: &sourceRef, TargetRefName: &targetRef, Title: &title},
+		RepositoryId:           &config.ChartRepoName,
+		Project:                &impl.project,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request azure", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(pr), nil
+}
+
+func (impl GitAzureClient) GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	clientAzure := *impl.client
+	pr, err := clientAzure.GetPullRequest(context.Background(), git.GetPullRequestArgs{
+		RepositoryId:  &repoName,
+		PullRequestId: &pullRequestId,
+		Project:       &impl.project,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request azure", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(pr), nil
+}
+
+func (impl GitAzureClient) ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error {
+	clientAzure := *impl.client
+	_, err := clientAzure.UpdatePullRequest(context.Background(), git.UpdatePullRequestArgs{
+		GitPullRequestToUpdate: &git.GitPullRequest{Status: &git.PullRequestStatusValues.Abandoned},
+		RepositoryId:           &repoName,
+		PullRequestId:          &pullRequestId,
+		Project:                &impl.project,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in abandoning pull request azure", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+	}
+	return err
+}
+
+func (impl GitAzureClient) toPullRequest(pr *git.GitPullRequest) *PullRequest {
+	pullRequest := &PullRequest{State: PULL_REQUEST_STATE_OPEN}
+	if pr.PullRequestId != nil {
+		pullRequest.Id = *pr.PullRequestId
+	}
+	if pr.Repository != nil && pr.Repository.WebUrl != nil {
+		pullRequest.Url = fmt.Sprintf("%s/pullrequest/%d", *pr.Repository.WebUrl, pullRequest.Id)
+	}
+	if pr.Status != nil {
+		switch *pr.Status {
+		case git.PullRequestStatusValues.Completed:
+			pullRequest.State = PULL_REQUEST_STATE_MERGED
+			if pr.LastMergeCommit != nil && pr.LastMergeCommit.CommitId != nil {
+				pullRequest.MergeCommitHash = *pr.LastMergeCommit.CommitId
+			}
+		case git.PullRequestStatusValues.Abandoned:
+			pullRequest.State = PULL_REQUEST_STATE_CLOSED
+		}
+	}
+	return pullRequest
+}
+
 func (impl GitAzureClient) repoExists(repoName, projectName string) (repoUrl string, exists bool, err error) {
 	ctx := context.Background()
 	// Get first page of the list of team projects for your organization
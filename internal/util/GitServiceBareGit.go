@@ -0,0 +1,134 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ktrysmt/go-bitbucket"
+	"go.uber.org/zap"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+)
+
+// GitBareClient works with repositories of a plain git server over https, ssh or git protocol. Git servers have
+// no api to create and delete repositories so repositories are created on server before use, their url is
+// <host>/<repo name>.git where host is the base url of repositories.
+type GitBareClient struct {
+	baseUrl    string
+	logger     *zap.SugaredLogger
+	gitService GitService
+}
+
+func NewGitBareClient(host string, logger *zap.SugaredLogger, gitService GitService) (GitBareClient, error) {
+	if len(host) == 0 {
+		return GitBareClient{}, fmt.Errorf("host is required for %s provider, i.e. ssh://git@git.example.com/gitops", BARE_GIT_PROVIDER)
+	}
+	_, err := transport.NewEndpoint(host)
+	if err != nil {
+		logger.Errorw("error in creating bare git client", "host", host, "err", err)
+		return GitBareClient{}, err
+	}
+	return GitBareClient{baseUrl: strings.TrimSuffix(host, "/"), logger: logger, gitService: gitService}, nil
+}
+
+func (impl GitBareClient) getRepoUrl(name string) string {
+	return fmt.Sprintf("%s/%s.git", impl.baseUrl, name)
+}
+
+func (impl GitBareClient) DeleteRepository(name, userName, gitHubOrgName, azureProjectName string, repoOptions *bitbucket.RepositoryOptions) error {
+	return fmt.Errorf("deleting repository is not supported for %s provider, delete %s on git server", BARE_GIT_PROVIDER, impl.getRepoUrl(name))
+}
+
+func (impl GitBareClient) CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	return nil, fmt.Errorf("pull requests are not supported for %s provider", BARE_GIT_PROVIDER)
+}
+
+func (impl GitBareClient) GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	return nil, fmt.Errorf("pull requests are not supported for %s provider", BARE_GIT_PROVIDER)
+}
+
+func (impl GitBareClient) ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error {
+	return fmt.Errorf("pull requests are not supported for %s provider", BARE_GIT_PROVIDER)
+}
+
+// GetRepoUrl returns the url of repo when it is accessible
+func (impl GitBareClient) GetRepoUrl(repoName string, repoOptions *bitbucket.RepositoryOptions) (repoUrl string, err error) {
+	repoUrl = impl.getRepoUrl(repoName)
+	_, err = impl.gitService.LsRemote(repoUrl)
+	if err != nil {
+		return "", fmt.Errorf("repository %s is not accessible, repositories of %s provider are created on git server before use: %v", repoUrl, BARE_GIT_PROVIDER, err)
+	}
+	return repoUrl, nil
+}
+
+// CreateRepository validates the pre-created repository, an empty repository is initialised with readme like the
+// repositories created by other providers and is reported as new
+func (impl GitBareClient) CreateRepository(name, description, bitbucketWorkspaceId, bitbucketProjectKey, userName, userEmailId string) (url string, isNew bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	repoUrl := impl.getRepoUrl(name)
+	refs, err := impl.gitService.LsRemote(repoUrl)
+	if err != nil {
+		impl.logger.Errorw("error in accessing bare git repo", "repoUrl", repoUrl, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[GetRepoUrlStage] = fmt.Errorf("repository %s is not accessible, repositories of %s provider are created on git server before use: %v", repoUrl, BARE_GIT_PROVIDER, err)
+		return "", false, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, GetRepoUrlStage)
+	if len(strings.TrimSpace(refs)) > 0 {
+		return repoUrl, false, detailedErrorGitOpsConfigActions
+	}
+	_, err = impl.CreateReadme(name, userName, userEmailId, "")
+	if err != nil {
+		impl.logger.Errorw("error in creating readme bare git", "repoUrl", repoUrl, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateReadmeStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateReadmeStage)
+	return repoUrl, true, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitBareClient) CreateReadme(repoName, userName, userEmailId, owner string) (string, error) {
+	cfg := &ChartConfig{
+		ChartName:      repoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "readme",
+		ChartRepoName:  repoName,
+		UserName:       userName,
+		UserEmailId:    userEmailId,
+	}
+	hash, err := impl.CommitValues(cfg, "")
+	if err != nil {
+		impl.logger.Errorw("error in creating readme bare git", "repo", repoName, "err", err)
+	}
+	return hash, err
+}
+
+// CommitValues clones the repo, commits the file and pushes it as there is no api to commit a file
+func (impl GitBareClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId string) (commitHash string, err error) {
+	repoUrl := impl.getRepoUrl(config.ChartRepoName)
+	clonedDir, err := impl.gitService.Clone(repoUrl, fmt.Sprintf("bare-git/%s-%d", config.ChartRepoName, time.Now().UnixNano()))
+	if err != nil {
+		impl.logger.Errorw("error in cloning bare git repo", "repoUrl", repoUrl, "err", err)
+		return "", err
+	}
+	defer os.RemoveAll(clonedDir)
+	dir := filepath.Join(clonedDir, config.ChartLocation)
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, config.FileName), []byte(config.FileContent), 0600)
+	if err != nil {
+		return "", err
+	}
+	commitHash, err = impl.gitService.CommitAndPushAllChanges(clonedDir, config.ReleaseMessage, config.UserName, config.UserEmailId)
+	if err != nil {
+		impl.logger.Errorw("error in commit bare git", "repoUrl", repoUrl, "file", config.FileName, "err", err)
+		return "", err
+	}
+	return commitHash, nil
+}
@@ -0,0 +1,138 @@
+package util
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getTestGitBareClient(t *testing.T) (GitBareClient, string) {
+	serverDir := t.TempDir()
+	logger, err := NewSugardLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitCliUtil := NewGitCliUtil(logger)
+	gitService := NewGitServiceImpl(&GitConfig{GitWorkingDir: t.TempDir()}, logger, gitCliUtil)
+	client, err := NewGitBareClient("file://"+serverDir, logger, gitService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, serverDir
+}
+
+func initBareRepo(t *testing.T, serverDir, name string) {
+	out, err := exec.Command("git", "init", "--bare", filepath.Join(serverDir, name+".git")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("error in init bare repo %s: %v", out, err)
+	}
+}
+
+func TestGitBareClient_CreateRepository(t *testing.T) {
+	client, serverDir := getTestGitBareClient(t)
+	initBareRepo(t, serverDir, "app-one")
+
+	url, isNew, detailedError := client.CreateRepository("app-one", "", "", "", "admin", "admin@example.com")
+	if len(detailedError.StageErrorMap) > 0 || !isNew {
+		t.Fatalf("expected empty repo to be initialised, isNew %v, errors %v", isNew, detailedError.StageErrorMap)
+	}
+	if url != "file://"+serverDir+"/app-one.git" {
+		t.Errorf("unexpected repo url %s", url)
+	}
+	_, isNew, detailedError = client.CreateRepository("app-one", "", "", "", "admin", "admin@example.com")
+	if len(detailedError.StageErrorMap) > 0 || isNew {
+		t.Errorf("expected existing repo to be reused, isNew %v, errors %v", isNew, detailedError.StageErrorMap)
+	}
+	_, _, detailedError = client.CreateRepository("missing", "", "", "", "admin", "admin@example.com")
+	if detailedError.StageErrorMap[GetRepoUrlStage] == nil {
+		t.Errorf("expected error for repo not created on server")
+	}
+}
+
+func TestGitBareClient_CommitValues(t *testing.T) {
+	client, serverDir := getTestGitBareClient(t)
+	initBareRepo(t, serverDir, "app-one")
+	if _, _, detailedError := client.CreateRepository("app-one", "", "", "", "admin", "admin@example.com"); len(detailedError.StageErrorMap) > 0 {
+		t.Fatal(detailedError.StageErrorMap)
+	}
+	commitHash, err := client.CommitValues(&ChartConfig{
+		ChartLocation:  "app-one/dev",
+		FileName:       "values.yaml",
+		FileContent:    "replicaCount: 2",
+		ReleaseMessage: "release",
+		ChartRepoName:  "app-one",
+		UserName:       "admin",
+		UserEmailId:    "admin@example.com",
+	}, "")
+	if err != nil || len(commitHash) == 0 {
+		t.Fatalf("expected commit, got hash %s, err %v", commitHash, err)
+	}
+	checkoutDir := filepath.Join(t.TempDir(), "checkout")
+	if out, err := exec.Command("git", "clone", filepath.Join(serverDir, "app-one.git"), checkoutDir).CombinedOutput(); err != nil {
+		t.Fatalf("error in clone %s: %v", out, err)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(checkoutDir, "app-one", "dev", "values.yaml"))
+	if err != nil || string(content) != "replicaCount: 2" {
+		t.Errorf("expected pushed values, got %s, err %v", content, err)
+	}
+}
+
+func TestGitServiceImpl_writeSshKey(t *testing.T) {
+	logger, err := NewSugardLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitService := NewGitServiceImpl(&GitConfig{GitWorkingDir: t.TempDir(), SshPrivateKey: "key"}, logger, NewGitCliUtil(logger))
+	if _, _, err = gitService.writeSshKey(); err == nil {
+		t.Errorf("expected error for ssh key without known hosts")
+	}
+	gitService.config.SshKnownHosts = "git.example.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+	sshKeyPath, knownHostsPath, err := gitService.writeSshKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	knownHosts, err := ioutil.ReadFile(knownHostsPath)
+	if err != nil || string(knownHosts) != gitService.config.SshKnownHosts+"\n" {
+		t.Errorf("expected configured known hosts, got %s, err %v", knownHosts, err)
+	}
+	if key, err := ioutil.ReadFile(sshKeyPath); err != nil || string(key) != "key\n" {
+		t.Errorf("expected private key, got %s, err %v", key, err)
+	}
+}
+
+func TestGitServiceImpl_ShallowFetchHead(t *testing.T) {
+	client, serverDir := getTestGitBareClient(t)
+	initBareRepo(t, serverDir, "app-one")
+	if _, _, detailedError := client.CreateRepository("app-one", "", "", "", "admin", "admin@example.com"); len(detailedError.StageErrorMap) > 0 {
+		t.Fatal(detailedError.StageErrorMap)
+	}
+	config := &ChartConfig{
+		ChartLocation:  "app-one/dev",
+		FileName:       "values.yaml",
+		ReleaseMessage: "release",
+		ChartRepoName:  "app-one",
+		UserName:       "admin",
+		UserEmailId:    "admin@example.com",
+	}
+	url := "file://" + filepath.Join(serverDir, "app-one.git")
+	for _, content := range []string{"replicaCount: 1", "replicaCount: 2"} {
+		config.FileContent = content
+		if _, err := client.CommitValues(config, ""); err != nil {
+			t.Fatal(err)
+		}
+		checkoutDir, err := client.gitService.ShallowFetchHead(url, "gitops-read/app-one")
+		if err != nil {
+			t.Fatalf("expected head to be fetched, err %v", err)
+		}
+		values, err := ioutil.ReadFile(filepath.Join(checkoutDir, "app-one", "dev", "values.yaml"))
+		if err != nil || string(values) != content {
+			t.Errorf("expected values at head %s, got %s, err %v", content, values, err)
+		}
+		out, err := exec.Command("git", "-C", checkoutDir, "rev-list", "--count", "HEAD").CombinedOutput()
+		if err != nil || strings.TrimSpace(string(out)) != "1" {
+			t.Errorf("expected checkout with history of depth 1, got %s, err %v", out, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/ktrysmt/go-bitbucket"
 	"go.uber.org/zap"
@@ -8,6 +9,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -202,7 +205,7 @@ func (impl GitBitbucketClient) CommitValues(config *ChartConfig, bitbucketWorksp
 		FilePath: bitbucketCommitFilePath,
 		FileName: fileName,
 		Message:  config.ReleaseMessage,
-		Branch:   "master",
+		Branch:   config.getBranch(),
 		Author:   authorBitbucket,
 	}
 	err = impl.client.Repositories.Repository.WriteFileBlob(repoWriteOptions)
@@ -213,7 +216,7 @@ func (impl GitBitbucketClient) CommitValues(config *ChartConfig, bitbucketWorksp
 	commitOptions := &bitbucket.CommitsOptions{
 		RepoSlug:    config.ChartRepoName,
 		Owner:       bitbucketWorkspaceId,
-		Branchortag: "master",
+		Branchortag: config.getBranch(),
 	}
 	commits, err := impl.client.Repositories.Commits.GetCommits(commitOptions)
 	if err != nil {
@@ -224,3 +227,86 @@ func (impl GitBitbucketClient) CommitValues(config *ChartConfig, bitbucketWorksp
 	commitHash = commits.(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})["hash"].(string)
 	return commitHash, nil
 }
+
+// CreatePullRequest commits the values to config.Branch, bitbucket creates the branch from head of main branch on
+// commit of a file to a new branch
+func (impl GitBitbucketClient) CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	_, err = impl.CommitValues(config, bitbucketWorkspaceId)
+	if err != nil {
+		impl.logger.Errorw("error in commit bitbucket", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	res, err := impl.client.Repositories.PullRequests.Create(&bitbucket.PullRequestsOptions{
+		Owner:             bitbucketWorkspaceId,
+		RepoSlug:          config.ChartRepoName,
+		Title:             strings.TrimSpace(config.ReleaseMessage),
+		SourceBranch:      config.Branch,
+		DestinationBranch: GITOPS_DEFAULT_BRANCH,
+		CloseSourceBranch: true,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request bitbucket", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(res)
+}
+
+func (impl GitBitbucketClient) GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	res, err := impl.client.Repositories.PullRequests.Get(&bitbucket.PullRequestsOptions{
+		ID:       strconv.Itoa(pullRequestId),
+		Owner:    bitbucketWorkspaceId,
+		RepoSlug: repoName,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request bitbucket", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+		return nil, err
+	}
+	return impl.toPullRequest(res)
+}
+
+func (impl GitBitbucketClient) ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error {
+	_, err := impl.client.Repositories.PullRequests.Decline(&bitbucket.PullRequestsOptions{
+		ID:       strconv.Itoa(pullRequestId),
+		Owner:    bitbucketWorkspaceId,
+		RepoSlug: repoName,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in declining pull request bitbucket", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+	}
+	return err
+}
+
+// toPullRequest maps the pull request response, reference of api & response - https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-get
+func (impl GitBitbucketClient) toPullRequest(res interface{}) (*PullRequest, error) {
+	payload, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	pr := &struct {
+		Id    int    `json:"id"`
+		State string `json:"state"`
+		Links struct {
+			Html struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+		MergeCommit *struct {
+			Hash string `json:"hash"`
+		} `json:"merge_commit"`
+	}{}
+	err = json.Unmarshal(payload, pr)
+	if err != nil {
+		return nil, err
+	}
+	pullRequest := &PullRequest{Id: pr.Id, Url: pr.Links.Html.Href, State: PULL_REQUEST_STATE_OPEN}
+	switch pr.State {
+	case "MERGED":
+		pullRequest.State = PULL_REQUEST_STATE_MERGED
+		if pr.MergeCommit != nil {
+			pullRequest.MergeCommitHash = pr.MergeCommit.Hash
+		}
+	case "DECLINED", "SUPERSEDED":
+		pullRequest.State = PULL_REQUEST_STATE_CLOSED
+	}
+	return pullRequest, nil
+}
@@ -0,0 +1,420 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ktrysmt/go-bitbucket"
+	"go.uber.org/zap"
+)
+
+const (
+	GITEA_API_V1         = "api/v1"
+	GITEA_DEFAULT_BRANCH = "master"
+)
+
+// GiteaErrorResponse is the error returned by gitea api, forgejo has the same api
+type GiteaErrorResponse struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+}
+
+func (e *GiteaErrorResponse) Error() string {
+	return fmt.Sprintf("gitea responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+type giteaRepository struct {
+	Name     string `json:"name"`
+	CloneUrl string `json:"clone_url"`
+	SshUrl   string `json:"ssh_url"`
+}
+
+type giteaCreateRepoOption struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Private       bool   `json:"private"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type giteaIdentity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type giteaFileOptions struct {
+	Content   string         `json:"content"`
+	Message   string         `json:"message"`
+	Branch    string         `json:"branch"`
+	Sha       string         `json:"sha,omitempty"`
+	Author    *giteaIdentity `json:"author,omitempty"`
+	Committer *giteaIdentity `json:"committer,omitempty"`
+}
+
+type giteaContents struct {
+	Sha string `json:"sha"`
+}
+
+type giteaFileResponse struct {
+	Commit struct {
+		Sha string `json:"sha"`
+	} `json:"commit"`
+}
+
+type giteaCreateBranchOption struct {
+	NewBranchName string `json:"new_branch_name"`
+	OldBranchName string `json:"old_branch_name"`
+}
+
+type giteaCreatePullRequestOption struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+type giteaEditPullRequestOption struct {
+	State string `json:"state"`
+}
+
+type giteaPullRequest struct {
+	Number         int    `json:"number"`
+	HtmlUrl        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	MergeCommitSha string `json:"merge_commit_sha"`
+}
+
+// GitGiteaClient manages repositories of a gitea or forgejo organization, repositories are created in the
+// organization of token user when org is empty
+type GitGiteaClient struct {
+	client     *http.Client
+	apiUrl     string
+	token      string
+	org        string
+	logger     *zap.SugaredLogger
+	gitService GitService
+}
+
+func NewGitGiteaClient(host, token, org string, logger *zap.SugaredLogger, gitService GitService) (GitGiteaClient, error) {
+	hostUrl, err := url.ParseRequestURI(host)
+	if err != nil {
+		logger.Errorw("error in creating gitea client", "host", host, "err", err)
+		return GitGiteaClient{}, err
+	}
+	hostUrl.Path = path.Join(hostUrl.Path, GITEA_API_V1)
+	return GitGiteaClient{
+		client:     &http.Client{Timeout: 30 * time.Second},
+		apiUrl:     hostUrl.String(),
+		token:      token,
+		org:        org,
+		logger:     logger,
+		gitService: gitService,
+	}, nil
+}
+
+func (impl GitGiteaClient) DeleteRepository(name, userName, gitHubOrgName, azureProjectName string, repoOptions *bitbucket.RepositoryOptions) error {
+	owner, err := impl.getOwner()
+	if err != nil {
+		return err
+	}
+	err = impl.doRequest(http.MethodDelete, fmt.Sprintf("/repos/%s/%s", owner, name), nil, nil)
+	if err != nil {
+		impl.logger.Errorw("repo deletion failed for gitea", "repo", name, "err", err)
+	}
+	return err
+}
+
+func (impl GitGiteaClient) CreateRepository(name, description, bitbucketWorkspaceId, bitbucketProjectKey, userName, userEmailId string) (url string, isNew bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	url, err := impl.GetRepoUrl(name, nil)
+	if err != nil && !isGiteaNotFound(err) {
+		impl.logger.Errorw("error in getting gitea repo", "repo", name, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[GetRepoUrlStage] = err
+		return "", false, detailedErrorGitOpsConfigActions
+	}
+	if err == nil {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, GetRepoUrlStage)
+		return url, false, detailedErrorGitOpsConfigActions
+	}
+	createPath := "/user/repos"
+	if len(impl.org) > 0 {
+		createPath = fmt.Sprintf("/orgs/%s/repos", impl.org)
+	}
+	repo := &giteaRepository{}
+	err = impl.doRequest(http.MethodPost, createPath, &giteaCreateRepoOption{
+		Name:          name,
+		Description:   description,
+		Private:       true,
+		DefaultBranch: GITEA_DEFAULT_BRANCH,
+	}, repo)
+	if err != nil {
+		impl.logger.Errorw("error in creating gitea repo", "repo", name, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateRepoStage] = err
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	impl.logger.Infow("gitea repo created", "repoUrl", repo.CloneUrl)
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateRepoStage)
+
+	validated, err := impl.ensureProjectAvailabilityOnHttp(name)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "repo", name, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = err
+		return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneHttpStage] = fmt.Errorf("unable to validate project:%s in given time", name)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneHttpStage)
+
+	_, err = impl.CreateReadme(name, userName, userEmailId, "")
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repo", name, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CreateReadmeStage] = err
+		return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CreateReadmeStage)
+
+	validated, err = impl.ensureProjectAvailabilityOnSsh(name, repo.CloneUrl)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "repo", name, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = err
+		return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		detailedErrorGitOpsConfigActions.StageErrorMap[CloneSshStage] = fmt.Errorf("unable to validate project:%s in given time", name)
+		return "", true, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, CloneSshStage)
+	return repo.CloneUrl, true, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitGiteaClient) CreateReadme(repoName, userName, userEmailId, owner string) (string, error) {
+	cfg := &ChartConfig{
+		ChartName:      repoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "readme",
+		ChartRepoName:  repoName,
+		UserName:       userName,
+		UserEmailId:    userEmailId,
+	}
+	hash, err := impl.CommitValues(cfg, "")
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repo", repoName, "err", err)
+	}
+	return hash, err
+}
+
+func (impl GitGiteaClient) CommitValues(config *ChartConfig, bitbucketWorkspaceId string) (commitHash string, err error) {
+	owner, err := impl.getOwner()
+	if err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(config.ChartLocation, config.FileName)
+	contentsPath := fmt.Sprintf("/repos/%s/%s/contents/%s", owner, config.ChartRepoName, filePath)
+	method := http.MethodPost
+	existing := &giteaContents{}
+	err = impl.doRequest(http.MethodGet, contentsPath+"?ref="+url.QueryEscape(config.getBranch()), nil, existing)
+	if err == nil {
+		method = http.MethodPut
+	} else if !isGiteaNotFound(err) {
+		impl.logger.Errorw("error in getting file gitea", "err", err, "repo", config.ChartRepoName, "path", filePath)
+		return "", err
+	}
+	identity := &giteaIdentity{Name: config.UserName, Email: config.UserEmailId}
+	res := &giteaFileResponse{}
+	err = impl.doRequest(method, contentsPath, &giteaFileOptions{
+		Content:   base64.StdEncoding.EncodeToString([]byte(config.FileContent)),
+		Message:   config.ReleaseMessage,
+		Branch:    config.getBranch(),
+		Sha:       existing.Sha,
+		Author:    identity,
+		Committer: identity,
+	}, res)
+	if err != nil {
+		impl.logger.Errorw("error in commit gitea", "err", err, "repo", config.ChartRepoName, "path", filePath)
+		return "", err
+	}
+	return res.Commit.Sha, nil
+}
+
+func (impl GitGiteaClient) CreatePullRequest(config *ChartConfig, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	owner, err := impl.getOwner()
+	if err != nil {
+		return nil, err
+	}
+	err = impl.doRequest(http.MethodPost, fmt.Sprintf("/repos/%s/%s/branches", owner, config.ChartRepoName), &giteaCreateBranchOption{
+		NewBranchName: config.Branch,
+		OldBranchName: GITOPS_DEFAULT_BRANCH,
+	}, nil)
+	if err != nil {
+		impl.logger.Errorw("error in creating branch gitea", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	_, err = impl.CommitValues(config, bitbucketWorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	pr := &giteaPullRequest{}
+	err = impl.doRequest(http.MethodPost, fmt.Sprintf("/repos/%s/%s/pulls", owner, config.ChartRepoName), &giteaCreatePullRequestOption{
+		Title: strings.TrimSpace(config.ReleaseMessage),
+		Head:  config.Branch,
+		Base:  GITOPS_DEFAULT_BRANCH,
+	}, pr)
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request gitea", "repo", config.ChartRepoName, "branch", config.Branch, "err", err)
+		return nil, err
+	}
+	return pr.toPullRequest(), nil
+}
+
+func (impl GitGiteaClient) GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (pullRequest *PullRequest, err error) {
+	owner, err := impl.getOwner()
+	if err != nil {
+		return nil, err
+	}
+	pr := &giteaPullRequest{}
+	err = impl.doRequest(http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repoName, pullRequestId), nil, pr)
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request gitea", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+		return nil, err
+	}
+	return pr.toPullRequest(), nil
+}
+
+func (impl GitGiteaClient) ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error {
+	owner, err := impl.getOwner()
+	if err != nil {
+		return err
+	}
+	err = impl.doRequest(http.MethodPatch, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repoName, pullRequestId), &giteaEditPullRequestOption{State: "closed"}, nil)
+	if err != nil {
+		impl.logger.Errorw("error in closing pull request gitea", "repo", repoName, "pullRequest", pullRequestId, "err", err)
+	}
+	return err
+}
+
+func (pr *giteaPullRequest) toPullRequest() *PullRequest {
+	pullRequest := &PullRequest{Id: pr.Number, Url: pr.HtmlUrl, State: PULL_REQUEST_STATE_OPEN}
+	if pr.Merged {
+		pullRequest.State = PULL_REQUEST_STATE_MERGED
+		pullRequest.MergeCommitHash = pr.MergeCommitSha
+	} else if pr.State == "closed" {
+		pullRequest.State = PULL_REQUEST_STATE_CLOSED
+	}
+	return pullRequest
+}
+
+func (impl GitGiteaClient) GetRepoUrl(projectName string, repoOptions *bitbucket.RepositoryOptions) (repoUrl string, err error) {
+	owner, err := impl.getOwner()
+	if err != nil {
+		return "", err
+	}
+	repo := &giteaRepository{}
+	err = impl.doRequest(http.MethodGet, fmt.Sprintf("/repos/%s/%s", owner, projectName), nil, repo)
+	if err != nil {
+		return "", err
+	}
+	return repo.CloneUrl, nil
+}
+
+// getOwner returns the org, or the token user when org is not configured
+func (impl GitGiteaClient) getOwner() (string, error) {
+	if len(impl.org) > 0 {
+		return impl.org, nil
+	}
+	user := &struct {
+		Login string `json:"login"`
+	}{}
+	err := impl.doRequest(http.MethodGet, "/user", nil, user)
+	if err != nil {
+		impl.logger.Errorw("error in getting gitea token user", "err", err)
+		return "", err
+	}
+	return user.Login, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnHttp(projectName string) (bool, error) {
+	for count := 0; count < 3; count++ {
+		_, err := impl.GetRepoUrl(projectName, nil)
+		if err == nil {
+			return true, nil
+		}
+		if !isGiteaNotFound(err) {
+			impl.logger.Errorw("error in validating repo gitea", "project", projectName, "err", err)
+			return false, err
+		}
+		impl.logger.Errorw("repo not available on http gitea", "project", projectName)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) ensureProjectAvailabilityOnSsh(projectName string, repoUrl string) (bool, error) {
+	for count := 0; count < 3; count++ {
+		_, err := impl.gitService.Clone(repoUrl, fmt.Sprintf("/ensure-clone/%s", projectName))
+		if err == nil {
+			impl.logger.Infow("gitea ensureProjectAvailability clone passed", "try count", count, "repoUrl", repoUrl)
+			return true, nil
+		}
+		impl.logger.Errorw("gitea ensureProjectAvailability clone failed", "try count", count, "err", err)
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GitGiteaClient) doRequest(method, apiPath string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, impl.apiUrl+apiPath, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+impl.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		errorResponse := &GiteaErrorResponse{StatusCode: res.StatusCode}
+		if json.Unmarshal(resBody, errorResponse) != nil || len(errorResponse.Message) == 0 {
+			errorResponse.Message = strings.TrimSpace(string(resBody))
+		}
+		return errorResponse
+	}
+	if result != nil && len(resBody) > 0 {
+		return json.Unmarshal(resBody, result)
+	}
+	return nil
+}
+
+func isGiteaNotFound(err error) bool {
+	errorResponse, ok := err.(*GiteaErrorResponse)
+	return ok && errorResponse.StatusCode == http.StatusNotFound
+}
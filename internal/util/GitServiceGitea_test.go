@@ -0,0 +1,259 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testGiteaToken = "gitea-token"
+
+// fakeGitea serves the part of gitea api used by GitGiteaClient, files are kept per branch in memory
+type fakeGitea struct {
+	lock         sync.Mutex
+	repos        map[string]bool
+	files        map[string]string
+	branches     map[string]bool
+	pullRequests map[int]*giteaPullRequest
+	requests     []string
+}
+
+func newFakeGitea() *fakeGitea {
+	return &fakeGitea{
+		repos:        map[string]bool{},
+		files:        map[string]string{},
+		branches:     map[string]bool{GITOPS_DEFAULT_BRANCH: true},
+		pullRequests: map[int]*giteaPullRequest{},
+	}
+}
+
+func (f *fakeGitea) writeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "token "+testGiteaToken {
+		f.writeJson(w, http.StatusUnauthorized, &GiteaErrorResponse{Message: "token is required"})
+		return
+	}
+	apiPath := strings.TrimPrefix(r.URL.Path, "/"+GITEA_API_V1)
+	parts := strings.Split(strings.Trim(apiPath, "/"), "/")
+	switch {
+	case apiPath == "/user":
+		f.writeJson(w, http.StatusOK, map[string]string{"login": "devtron-bot"})
+	case len(parts) == 3 && parts[0] == "repos" && r.Method == http.MethodGet:
+		repo := parts[1] + "/" + parts[2]
+		if !f.repos[repo] {
+			f.writeJson(w, http.StatusNotFound, &GiteaErrorResponse{Message: "repo not found"})
+			return
+		}
+		f.writeJson(w, http.StatusOK, &giteaRepository{Name: parts[2], CloneUrl: "https://gitea.example.com/" + repo + ".git"})
+	case len(parts) >= 5 && parts[3] == "contents":
+		f.serveContents(w, r, parts[1]+"/"+parts[2]+"/"+strings.Join(parts[4:], "/"))
+	case len(parts) == 4 && parts[3] == "branches" && r.Method == http.MethodPost:
+		option := &giteaCreateBranchOption{}
+		json.NewDecoder(r.Body).Decode(option)
+		f.branches[option.NewBranchName] = true
+		f.writeJson(w, http.StatusCreated, map[string]string{"name": option.NewBranchName})
+	case len(parts) == 4 && parts[3] == "pulls" && r.Method == http.MethodPost:
+		option := &giteaCreatePullRequestOption{}
+		json.NewDecoder(r.Body).Decode(option)
+		number := len(f.pullRequests) + 1
+		pr := &giteaPullRequest{Number: number, State: "open", HtmlUrl: fmt.Sprintf("https://gitea.example.com/%s/%s/pulls/%d", parts[1], parts[2], number)}
+		f.pullRequests[number] = pr
+		f.writeJson(w, http.StatusCreated, pr)
+	case len(parts) == 5 && parts[3] == "pulls" && r.Method == http.MethodGet:
+		var number int
+		fmt.Sscan(parts[4], &number)
+		pr, ok := f.pullRequests[number]
+		if !ok {
+			f.writeJson(w, http.StatusNotFound, &GiteaErrorResponse{Message: "pull request not found"})
+			return
+		}
+		f.writeJson(w, http.StatusOK, pr)
+	default:
+		f.writeJson(w, http.StatusNotFound, &GiteaErrorResponse{Message: "not found"})
+	}
+}
+
+func (f *fakeGitea) serveContents(w http.ResponseWriter, r *http.Request, filePath string) {
+	switch r.Method {
+	case http.MethodGet:
+		key := r.URL.Query().Get("ref") + ":" + filePath
+		if _, ok := f.files[key]; !ok {
+			f.writeJson(w, http.StatusNotFound, &GiteaErrorResponse{Message: "file not found"})
+			return
+		}
+		f.writeJson(w, http.StatusOK, &giteaContents{Sha: "sha-" + key})
+	case http.MethodPost, http.MethodPut:
+		option := &giteaFileOptions{}
+		json.NewDecoder(r.Body).Decode(option)
+		key := option.Branch + ":" + filePath
+		_, exists := f.files[key]
+		if !f.branches[option.Branch] || exists != (r.Method == http.MethodPut) || (exists && option.Sha != "sha-"+key) {
+			f.writeJson(w, http.StatusUnprocessableEntity, &GiteaErrorResponse{Message: "invalid file update"})
+			return
+		}
+		content, _ := base64.StdEncoding.DecodeString(option.Content)
+		f.files[key] = string(content)
+		response := &giteaFileResponse{}
+		response.Commit.Sha = fmt.Sprintf("commit-%d", len(f.requests))
+		f.writeJson(w, http.StatusCreated, response)
+	}
+}
+
+func getTestGiteaClient(t *testing.T, org string) (GitGiteaClient, *fakeGitea) {
+	fake := newFakeGitea()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	logger, err := NewSugardLogger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewGitGiteaClient(server.URL, testGiteaToken, org, logger, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, fake
+}
+
+func TestGitGiteaClient_GetRepoUrl(t *testing.T) {
+	client, fake := getTestGiteaClient(t, "devtron")
+	fake.repos["devtron/app-one"] = true
+
+	repoUrl, err := client.GetRepoUrl("app-one", nil)
+	if err != nil || repoUrl != "https://gitea.example.com/devtron/app-one.git" {
+		t.Errorf("expected repo url of org, got %s, err %v", repoUrl, err)
+	}
+	_, err = client.GetRepoUrl("missing", nil)
+	if !isGiteaNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestGitGiteaClient_GetRepoUrlOfTokenUser(t *testing.T) {
+	client, fake := getTestGiteaClient(t, "")
+	fake.repos["devtron-bot/app-one"] = true
+
+	repoUrl, err := client.GetRepoUrl("app-one", nil)
+	if err != nil || repoUrl != "https://gitea.example.com/devtron-bot/app-one.git" {
+		t.Errorf("expected repo url of token user, got %s, err %v", repoUrl, err)
+	}
+	if fake.requests[0] != "GET /"+GITEA_API_V1+"/user" {
+		t.Errorf("expected owner to be resolved from token user, requests %v", fake.requests)
+	}
+}
+
+func TestGitGiteaClient_CreateRepositoryExisting(t *testing.T) {
+	client, fake := getTestGiteaClient(t, "devtron")
+	fake.repos["devtron/app-one"] = true
+
+	repoUrl, isNew, detailedError := client.CreateRepository("app-one", "", "", "", "admin", "admin@example.com")
+	if len(detailedError.StageErrorMap) > 0 || isNew || repoUrl != "https://gitea.example.com/devtron/app-one.git" {
+		t.Errorf("expected existing repo to be reused, url %s, isNew %v, errors %v", repoUrl, isNew, detailedError.StageErrorMap)
+	}
+}
+
+func TestGitGiteaClient_CreateRepositoryUnauthorized(t *testing.T) {
+	client, _ := getTestGiteaClient(t, "devtron")
+	client.token = "invalid"
+
+	_, _, detailedError := client.CreateRepository("app-one", "", "", "", "admin", "admin@example.com")
+	err, ok := detailedError.StageErrorMap[GetRepoUrlStage].(*GiteaErrorResponse)
+	if !ok || err.StatusCode != http.StatusUnauthorized || err.Message != "token is required" {
+		t.Errorf("expected unauthorized error in get repo stage, got %v", detailedError.StageErrorMap)
+	}
+}
+
+func TestGitGiteaClient_CommitValues(t *testing.T) {
+	client, fake := getTestGiteaClient(t, "devtron")
+	config := &ChartConfig{
+		ChartLocation:  "app-one/dev",
+		FileName:       "values.yaml",
+		FileContent:    "replicaCount: 1",
+		ReleaseMessage: "release",
+		ChartRepoName:  "app-one",
+		UserName:       "admin",
+		UserEmailId:    "admin@example.com",
+	}
+	commitHash, err := client.CommitValues(config, "")
+	if err != nil || len(commitHash) == 0 {
+		t.Fatalf("expected file to be created, hash %s, err %v", commitHash, err)
+	}
+	config.FileContent = "replicaCount: 2"
+	commitHash, err = client.CommitValues(config, "")
+	if err != nil || len(commitHash) == 0 {
+		t.Fatalf("expected file to be updated, hash %s, err %v", commitHash, err)
+	}
+	if content := fake.files["master:devtron/app-one/app-one/dev/values.yaml"]; content != "replicaCount: 2" {
+		t.Errorf("expected updated values, got %s", content)
+	}
+}
+
+func TestGitGiteaClient_PullRequest(t *testing.T) {
+	client, fake := getTestGiteaClient(t, "devtron")
+	config := &ChartConfig{
+		ChartLocation:  "app-one/dev",
+		FileName:       "values.yaml",
+		FileContent:    "replicaCount: 3",
+		ReleaseMessage: "release 3 ",
+		ChartRepoName:  "app-one",
+		Branch:         "devtron/app-one-dev-3",
+		UserName:       "admin",
+		UserEmailId:    "admin@example.com",
+	}
+	pullRequest, err := client.CreatePullRequest(config, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pullRequest.Id != 1 || pullRequest.State != PULL_REQUEST_STATE_OPEN || pullRequest.Url != "https://gitea.example.com/devtron/app-one/pulls/1" {
+		t.Errorf("unexpected pull request %+v", pullRequest)
+	}
+	if content := fake.files["devtron/app-one-dev-3:devtron/app-one/app-one/dev/values.yaml"]; content != "replicaCount: 3" {
+		t.Errorf("expected values on pull request branch, got %s", content)
+	}
+	if _, ok := fake.files["master:devtron/app-one/app-one/dev/values.yaml"]; ok {
+		t.Errorf("expected default branch to be unchanged")
+	}
+
+	fake.pullRequests[1].Merged = true
+	fake.pullRequests[1].State = "closed"
+	fake.pullRequests[1].MergeCommitSha = "merge-sha"
+	pullRequest, err = client.GetPullRequest("app-one", 1, "")
+	if err != nil || pullRequest.State != PULL_REQUEST_STATE_MERGED || pullRequest.MergeCommitHash != "merge-sha" {
+		t.Errorf("expected merged pull request, got %+v, err %v", pullRequest, err)
+	}
+	_, err = client.GetPullRequest("app-one", 2, "")
+	if !isGiteaNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestGiteaPullRequest_toPullRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   *giteaPullRequest
+		want PullRequestState
+	}{
+		{name: "open", pr: &giteaPullRequest{State: "open"}, want: PULL_REQUEST_STATE_OPEN},
+		{name: "merged", pr: &giteaPullRequest{State: "closed", Merged: true}, want: PULL_REQUEST_STATE_MERGED},
+		{name: "closed", pr: &giteaPullRequest{State: "closed"}, want: PULL_REQUEST_STATE_CLOSED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pr.toPullRequest().State; got != tt.want {
+				t.Errorf("toPullRequest() state = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/google/go-github/github"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/git"
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestGitLabClient_toPullRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		mr   *gitlab.MergeRequest
+		want PullRequest
+	}{
+		{name: "opened", mr: &gitlab.MergeRequest{IID: 1, WebURL: "https://gitlab.com/mr/1", State: "opened"},
+			want: PullRequest{Id: 1, Url: "https://gitlab.com/mr/1", State: PULL_REQUEST_STATE_OPEN}},
+		{name: "merged", mr: &gitlab.MergeRequest{IID: 2, State: "merged", MergeCommitSHA: "merge-sha", SHA: "head-sha"},
+			want: PullRequest{Id: 2, State: PULL_REQUEST_STATE_MERGED, MergeCommitHash: "merge-sha"}},
+		{name: "squashed", mr: &gitlab.MergeRequest{IID: 3, State: "merged", SquashCommitSHA: "squash-sha", SHA: "head-sha"},
+			want: PullRequest{Id: 3, State: PULL_REQUEST_STATE_MERGED, MergeCommitHash: "squash-sha"}},
+		{name: "fast forward", mr: &gitlab.MergeRequest{IID: 4, State: "merged", SHA: "head-sha"},
+			want: PullRequest{Id: 4, State: PULL_REQUEST_STATE_MERGED, MergeCommitHash: "head-sha"}},
+		{name: "closed", mr: &gitlab.MergeRequest{IID: 5, State: "closed"},
+			want: PullRequest{Id: 5, State: PULL_REQUEST_STATE_CLOSED}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (GitLabClient{}).toPullRequest(tt.mr); *got != tt.want {
+				t.Errorf("toPullRequest() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubClient_toPullRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   *github.PullRequest
+		want PullRequest
+	}{
+		{name: "open", pr: &github.PullRequest{Number: github.Int(1), HTMLURL: github.String("https://github.com/pull/1"), State: github.String("open")},
+			want: PullRequest{Id: 1, Url: "https://github.com/pull/1", State: PULL_REQUEST_STATE_OPEN}},
+		{name: "merged", pr: &github.PullRequest{Number: github.Int(2), State: github.String("closed"), Merged: github.Bool(true), MergeCommitSHA: github.String("merge-sha")},
+			want: PullRequest{Id: 2, State: PULL_REQUEST_STATE_MERGED, MergeCommitHash: "merge-sha"}},
+		{name: "closed", pr: &github.PullRequest{Number: github.Int(3), State: github.String("closed"), MergeCommitSHA: github.String("test-merge-sha")},
+			want: PullRequest{Id: 3, State: PULL_REQUEST_STATE_CLOSED}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (GitHubClient{}).toPullRequest(tt.pr); *got != tt.want {
+				t.Errorf("toPullRequest() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitAzureClient_toPullRequest(t *testing.T) {
+	id := 7
+	webUrl := "https://dev.azure.com/devtron/gitops/_git/app-one"
+	mergeCommit := "merge-sha"
+	repository := &git.GitRepository{WebUrl: &webUrl}
+	tests := []struct {
+		name   string
+		status git.PullRequestStatus
+		want   PullRequest
+	}{
+		{name: "active", status: git.PullRequestStatusValues.Active,
+			want: PullRequest{Id: id, Url: webUrl + "/pullrequest/7", State: PULL_REQUEST_STATE_OPEN}},
+		{name: "completed", status: git.PullRequestStatusValues.Completed,
+			want: PullRequest{Id: id, Url: webUrl + "/pullrequest/7", State: PULL_REQUEST_STATE_MERGED, MergeCommitHash: mergeCommit}},
+		{name: "abandoned", status: git.PullRequestStatusValues.Abandoned,
+			want: PullRequest{Id: id, Url: webUrl + "/pullrequest/7", State: PULL_REQUEST_STATE_CLOSED}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			pr := &git.GitPullRequest{PullRequestId: &id, Repository: repository, Status: &status,
+				LastMergeCommit: &git.GitCommitRef{CommitId: &mergeCommit}}
+			if tt.status != git.PullRequestStatusValues.Completed {
+				pr.LastMergeCommit = nil
+			}
+			if got := (GitAzureClient{}).toPullRequest(pr); *got != tt.want {
+				t.Errorf("toPullRequest() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitBitbucketClient_toPullRequest(t *testing.T) {
+	response := func(state string) map[string]interface{} {
+		res := map[string]interface{}{
+			"id":    9,
+			"state": state,
+			"links": map[string]interface{}{"html": map[string]interface{}{"href": "https://bitbucket.org/pull-requests/9"}},
+		}
+		if state == "MERGED" {
+			res["merge_commit"] = map[string]interface{}{"hash": "merge-sha"}
+		}
+		return res
+	}
+	tests := []struct {
+		state string
+		want  PullRequest
+	}{
+		{state: "OPEN", want: PullRequest{Id: 9, Url: "https://bitbucket.org/pull-requests/9", State: PULL_REQUEST_STATE_OPEN}},
+		{state: "MERGED", want: PullRequest{Id: 9, Url: "https://bitbucket.org/pull-requests/9", State: PULL_REQUEST_STATE_MERGED, MergeCommitHash: "merge-sha"}},
+		{state: "DECLINED", want: PullRequest{Id: 9, Url: "https://bitbucket.org/pull-requests/9", State: PULL_REQUEST_STATE_CLOSED}},
+		{state: "SUPERSEDED", want: PullRequest{Id: 9, Url: "https://bitbucket.org/pull-requests/9", State: PULL_REQUEST_STATE_CLOSED}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			got, err := (GitBitbucketClient{}).toPullRequest(response(tt.state))
+			if err != nil || *got != tt.want {
+				t.Errorf("toPullRequest() = %+v, err %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package util
 
 import (
@@ -38,7 +41,7 @@ func TestGitHubClient_CreateRepository(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			impl := getTestGithubClient()
-			_, gotIsNew, _ := impl.CreateRepository(tt.args.name, tt.args.description, tt.args.bitbucketWorkspaceId, tt.args.bitbucketProjectKey)
+			_, gotIsNew, _ := impl.CreateRepository(tt.args.name, tt.args.description, tt.args.bitbucketWorkspaceId, tt.args.bitbucketProjectKey, "", "")
 
 			if gotIsNew != tt.wantIsNew {
 				t.Errorf("CreateRepository() gotIsNew = %v, want %v", gotIsNew, tt.wantIsNew)
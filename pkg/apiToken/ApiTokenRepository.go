@@ -22,6 +22,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/go-pg/pg"
 	"github.com/go-pg/pg/orm"
+	"time"
 )
 
 type ApiToken struct {
@@ -32,7 +33,15 @@ type ApiToken struct {
 	Description  string   `sql:"description, notnull"`
 	ExpireAtInMs int64    `sql:"expire_at_in_ms"`
 	Token        string   `sql:"token, notnull"`
-	User         *repository.UserModel
+	// scope of api-token, empty lists mean no restriction
+	AllowedRouteGroups []string  `sql:"allowed_route_groups" pg:",array"`
+	AllowedAppIds      []int     `sql:"allowed_app_ids" pg:",array"`
+	AllowedEnvIds      []int     `sql:"allowed_env_ids" pg:",array"`
+	SourceCidrs        []string  `sql:"source_cidrs" pg:",array"`
+	ReadOnly           bool      `sql:"read_only,notnull"`
+	LastUsedAt         time.Time `sql:"last_used_at"`
+	LastUsedByIp       string    `sql:"last_used_by_ip"`
+	User               *repository.UserModel
 	sql.AuditLog
 }
 
@@ -42,6 +51,7 @@ type ApiTokenRepository interface {
 	FindAllActive() ([]*ApiToken, error)
 	FindActiveById(id int) (*ApiToken, error)
 	FindByName(name string) (*ApiToken, error)
+	UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error
 }
 
 type ApiTokenRepositoryImpl struct {
@@ -91,3 +101,12 @@ func (impl ApiTokenRepositoryImpl) FindByName(name string) (*ApiToken, error) {
 		Select()
 	return apiToken, err
 }
+
+func (impl ApiTokenRepositoryImpl) UpdateLastUsed(id int, lastUsedAt time.Time, lastUsedByIp string) error {
+	_, err := impl.dbConnection.Model(&ApiToken{}).
+		Set("last_used_at = ?", lastUsedAt).
+		Set("last_used_by_ip = ?", lastUsedByIp).
+		Where("id = ?", id).
+		Update()
+	return err
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package apiToken
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	ROUTE_GROUP_CI_TRIGGER      = "ci-trigger"
+	ROUTE_GROUP_CD_TRIGGER      = "cd-trigger"
+	ROUTE_GROUP_APP_READ        = "app-read"
+	ROUTE_GROUP_HELM_APP        = "helm-app"
+	ROUTE_GROUP_SECURITY_SCAN   = "security-scan"
+	ROUTE_GROUP_EXTERNAL_CI     = "external-ci"
+	ROUTE_GROUP_SCIM            = "scim"
+	CI_TRIGGER_PATH             = "/orchestrator/app/ci-pipeline/trigger"
+	CD_TRIGGER_PATH             = "/orchestrator/app/cd-pipeline/trigger"
+	SCIM_PATH_PREFIX            = "/orchestrator/scim/v2/"
+	ROUTE_GROUP_ANY_HTTP_METHOD = ""
+)
+
+type routeGroupRoute struct {
+	method     string
+	pathPrefix string
+}
+
+// routeGroups maps the route groups an api-token can be restricted to with the routes of the group
+var routeGroups = map[string][]routeGroupRoute{
+	ROUTE_GROUP_CI_TRIGGER: {
+		{method: http.MethodPost, pathPrefix: CI_TRIGGER_PATH},
+	},
+	ROUTE_GROUP_CD_TRIGGER: {
+		{method: http.MethodPost, pathPrefix: CD_TRIGGER_PATH},
+	},
+	ROUTE_GROUP_APP_READ: {
+		{method: http.MethodGet, pathPrefix: "/orchestrator/app/"},
+		{method: http.MethodGet, pathPrefix: "/orchestrator/api/v1/applications/"},
+	},
+	ROUTE_GROUP_HELM_APP: {
+		{method: ROUTE_GROUP_ANY_HTTP_METHOD, pathPrefix: "/orchestrator/application/"},
+	},
+	ROUTE_GROUP_SECURITY_SCAN: {
+		{method: ROUTE_GROUP_ANY_HTTP_METHOD, pathPrefix: "/orchestrator/security/scan/"},
+	},
+	ROUTE_GROUP_EXTERNAL_CI: {
+		{method: http.MethodPost, pathPrefix: "/orchestrator/webhook/ext-ci/"},
+	},
+	ROUTE_GROUP_SCIM: {
+		{method: ROUTE_GROUP_ANY_HTTP_METHOD, pathPrefix: SCIM_PATH_PREFIX},
+	},
+}
+
+var appIdKeys = []string{"appId"}
+var envIdKeys = []string{"envId", "environmentId"}
+var pipelineIdKeys = []string{"pipelineId"}
+var ciPipelineIdKeys = []string{"ciPipelineId"}
+var cdPipelineIdKeys = []string{"cdPipelineId"}
+
+// ApiTokenScopeRequest is the part of an http request which is matched with the scope of api-token
+type ApiTokenScopeRequest struct {
+	Method   string
+	Path     string
+	ClientIp string
+	AppIds   []int
+	EnvIds   []int
+	// Unresolved are the ids of request which could not be resolved to apps and envs, e.g. helm app ids or pipeline
+	// ids of routes not telling the pipeline type
+	Unresolved []string
+}
+
+func validateApiTokenScope(scope *openapi.ApiTokenScope) error {
+	if scope == nil {
+		return nil
+	}
+	for _, routeGroup := range scope.RouteGroups {
+		if _, ok := routeGroups[routeGroup]; !ok {
+			return errors.New(fmt.Sprintf("route group '%s' is not supported", routeGroup))
+		}
+	}
+	for _, cidr := range scope.SourceCidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.New(fmt.Sprintf("source cidr '%s' is not valid", cidr))
+		}
+	}
+	for _, appId := range scope.AppIds {
+		if appId <= 0 {
+			return errors.New(fmt.Sprintf("app id '%d' is not valid", appId))
+		}
+	}
+	for _, envId := range scope.EnvIds {
+		if envId <= 0 {
+			return errors.New(fmt.Sprintf("env id '%d' is not valid", envId))
+		}
+	}
+	return nil
+}
+
+func buildApiTokenScope(apiToken *ApiToken) *openapi.ApiTokenScope {
+	scope := &openapi.ApiTokenScope{
+		RouteGroups: apiToken.AllowedRouteGroups,
+		SourceCidrs: apiToken.SourceCidrs,
+		ReadOnly:    &apiToken.ReadOnly,
+	}
+	for _, appId := range apiToken.AllowedAppIds {
+		scope.AppIds = append(scope.AppIds, int32(appId))
+	}
+	for _, envId := range apiToken.AllowedEnvIds {
+		scope.EnvIds = append(scope.EnvIds, int32(envId))
+	}
+	return scope
+}
+
+func setApiTokenScope(apiToken *ApiToken, scope *openapi.ApiTokenScope) {
+	if scope == nil {
+		return
+	}
+	apiToken.AllowedRouteGroups = scope.RouteGroups
+	apiToken.SourceCidrs = scope.SourceCidrs
+	apiToken.ReadOnly = scope.GetReadOnly()
+	for _, appId := range scope.AppIds {
+		apiToken.AllowedAppIds = append(apiToken.AllowedAppIds, int(appId))
+	}
+	for _, envId := range scope.EnvIds {
+		apiToken.AllowedEnvIds = append(apiToken.AllowedEnvIds, int(envId))
+	}
+}
+
+// checkApiTokenScope returns error if the request is not within the scope of api-token
+func checkApiTokenScope(apiToken *ApiToken, request *ApiTokenScopeRequest) error {
+	if apiToken.ReadOnly && !isReadMethod(request.Method) {
+		return errors.New("api-token is read-only")
+	}
+	if len(apiToken.SourceCidrs) > 0 && !isIpInCidrs(request.ClientIp, apiToken.SourceCidrs) {
+		return errors.New(fmt.Sprintf("api-token is not allowed from ip '%s'", request.ClientIp))
+	}
+	if len(apiToken.AllowedRouteGroups) > 0 && !isRouteInGroups(request.Method, request.Path, apiToken.AllowedRouteGroups) {
+		return errors.New(fmt.Sprintf("api-token is not allowed to call '%s %s'", request.Method, request.Path))
+	}
+	// requests of tokens restricted to apps or envs fail closed when their apps and envs can not be resolved
+	if (len(apiToken.AllowedAppIds) > 0 || len(apiToken.AllowedEnvIds) > 0) && len(request.Unresolved) > 0 {
+		return errors.New(fmt.Sprintf("api-token scope can not be verified for %s", strings.Join(request.Unresolved, ", ")))
+	}
+	if len(apiToken.AllowedAppIds) > 0 && len(request.AppIds) == 0 {
+		return errors.New(fmt.Sprintf("api-token restricted to apps is not allowed to call '%s %s'", request.Method, request.Path))
+	}
+	if len(apiToken.AllowedEnvIds) > 0 && len(request.EnvIds) == 0 {
+		return errors.New(fmt.Sprintf("api-token restricted to envs is not allowed to call '%s %s'", request.Method, request.Path))
+	}
+	if len(apiToken.AllowedAppIds) > 0 {
+		for _, appId := range request.AppIds {
+			if !containsId(apiToken.AllowedAppIds, appId) {
+				return errors.New(fmt.Sprintf("api-token is not allowed for app '%d'", appId))
+			}
+		}
+	}
+	if len(apiToken.AllowedEnvIds) > 0 {
+		for _, envId := range request.EnvIds {
+			if !containsId(apiToken.AllowedEnvIds, envId) {
+				return errors.New(fmt.Sprintf("api-token is not allowed for env '%d'", envId))
+			}
+		}
+	}
+	return nil
+}
+
+// isScimApiToken tells whether the api-token is dedicated to scim provisioning, i.e. it is restricted to scim routes only
+func isScimApiToken(apiToken *ApiToken) bool {
+	return len(apiToken.AllowedRouteGroups) == 1 && apiToken.AllowedRouteGroups[0] == ROUTE_GROUP_SCIM
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func isRouteInGroups(method string, path string, allowedRouteGroups []string) bool {
+	for _, routeGroup := range allowedRouteGroups {
+		for _, route := range routeGroups[routeGroup] {
+			if route.method != ROUTE_GROUP_ANY_HTTP_METHOD && route.method != method {
+				continue
+			}
+			if strings.HasPrefix(path, route.pathPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isIpInCidrs matches the client ip, which can be a remote address with port, with the cidrs
+func isIpInCidrs(clientIp string, cidrs []string) bool {
+	if host, _, err := net.SplitHostPort(clientIp); err == nil {
+		clientIp = host
+	}
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsId(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// getRequestIds returns the ids found for the keys in route vars, query params and top level fields of json body,
+// values of the keys which are not numeric ids are returned as unresolved
+func getRequestIds(r *http.Request, bodyFields []map[string]interface{}, keys []string) ([]int, []string) {
+	var ids []int
+	var unresolved []string
+	addId := func(key string, value string) {
+		if id, err := strconv.Atoi(value); err == nil {
+			ids = append(ids, id)
+		} else {
+			unresolved = append(unresolved, fmt.Sprintf("%s '%s'", key, value))
+		}
+	}
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+	for _, key := range keys {
+		if value, ok := vars[key]; ok {
+			addId(key, value)
+		}
+		for _, value := range query[key] {
+			addId(key, value)
+		}
+		for _, fields := range bodyFields {
+			value, ok := fields[key]
+			if !ok || value == nil {
+				continue
+			}
+			switch value := value.(type) {
+			case float64:
+				ids = append(ids, int(value))
+			case string:
+				addId(key, value)
+			default:
+				unresolved = append(unresolved, fmt.Sprintf("%s '%v'", key, value))
+			}
+		}
+	}
+	return ids, unresolved
+}
+
+// readJsonBodyFields returns the top level fields of json object or array of objects in request body, body is
+// restored so that it can be read again by the handler
+func readJsonBodyFields(r *http.Request) []map[string]interface{} {
+	if r.Body == nil || isReadMethod(r.Method) {
+		return nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil || len(body) == 0 {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(body, &fields); err == nil {
+		return []map[string]interface{}{fields}
+	}
+	var fieldsList []map[string]interface{}
+	if err = json.Unmarshal(body, &fieldsList); err == nil {
+		return fieldsList
+	}
+	return nil
+}
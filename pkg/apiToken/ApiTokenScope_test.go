@@ -0,0 +1,78 @@
+package apiToken
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckApiTokenScope(t *testing.T) {
+	apiToken := &ApiToken{
+		AllowedRouteGroups: []string{ROUTE_GROUP_CD_TRIGGER},
+		AllowedAppIds:      []int{1, 2},
+		AllowedEnvIds:      []int{3},
+		SourceCidrs:        []string{"10.0.0.0/8"},
+	}
+	request := &ApiTokenScopeRequest{Method: http.MethodPost, Path: CD_TRIGGER_PATH, ClientIp: "10.1.2.3:5432", AppIds: []int{2}, EnvIds: []int{3}}
+	assert.Nil(t, checkApiTokenScope(apiToken, request))
+
+	request.ClientIp = "192.168.1.1, 10.1.2.3"
+	assert.NotNil(t, checkApiTokenScope(apiToken, request))
+	request.ClientIp = "10.1.2.3"
+
+	request.EnvIds = []int{4}
+	assert.NotNil(t, checkApiTokenScope(apiToken, request))
+	// routes without env can not be called by tokens restricted to envs
+	request.EnvIds = nil
+	assert.NotNil(t, checkApiTokenScope(apiToken, request))
+	request.EnvIds = []int{3}
+
+	request.Unresolved = []string{"appId 'cluster|namespace|app'"}
+	assert.NotNil(t, checkApiTokenScope(apiToken, request))
+	request.Unresolved = nil
+
+	request.Path = CI_TRIGGER_PATH
+	assert.NotNil(t, checkApiTokenScope(apiToken, request))
+	request.Path = CD_TRIGGER_PATH
+
+	apiToken.ReadOnly = true
+	assert.NotNil(t, checkApiTokenScope(apiToken, request))
+	assert.Nil(t, checkApiTokenScope(&ApiToken{ReadOnly: true}, &ApiTokenScopeRequest{Method: http.MethodGet, Path: "/orchestrator/app/list"}))
+}
+
+func TestGetRequestIds(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, CD_TRIGGER_PATH+"?envId=7", strings.NewReader(`{"pipelineId": 5, "appId": 3}`))
+	bodyFields := readJsonBodyFields(r)
+	ids, unresolved := getRequestIds(r, bodyFields, appIdKeys)
+	assert.Equal(t, []int{3}, ids)
+	assert.Empty(t, unresolved)
+	ids, _ = getRequestIds(r, bodyFields, envIdKeys)
+	assert.Equal(t, []int{7}, ids)
+	ids, _ = getRequestIds(r, bodyFields, pipelineIdKeys)
+	assert.Equal(t, []int{5}, ids)
+
+	// body is still readable by the handler
+	body, err := ioutil.ReadAll(r.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"pipelineId": 5, "appId": 3}`, string(body))
+
+	// helm app ids are not ids of devtron apps
+	r = httptest.NewRequest(http.MethodGet, "/orchestrator/application/app?appId=1|default|redis", nil)
+	ids, unresolved = getRequestIds(r, nil, appIdKeys)
+	assert.Empty(t, ids)
+	assert.Equal(t, []string{"appId '1|default|redis'"}, unresolved)
+}
+
+func TestScimApiTokenScope(t *testing.T) {
+	apiToken := &ApiToken{AllowedRouteGroups: []string{ROUTE_GROUP_SCIM}}
+	assert.True(t, isScimApiToken(apiToken))
+	assert.Nil(t, checkApiTokenScope(apiToken, &ApiTokenScopeRequest{Method: http.MethodPatch, Path: SCIM_PATH_PREFIX + "Users/3"}))
+	assert.NotNil(t, checkApiTokenScope(apiToken, &ApiTokenScopeRequest{Method: http.MethodPost, Path: "/orchestrator/user"}))
+
+	apiToken.AllowedRouteGroups = append(apiToken.AllowedRouteGroups, ROUTE_GROUP_APP_READ)
+	assert.False(t, isScimApiToken(apiToken))
+}
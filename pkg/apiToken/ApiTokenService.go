@@ -23,13 +23,19 @@ import (
 	"github.com/devtron-labs/authenticator/middleware"
 	"github.com/devtron-labs/devtron/api/bean"
 	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/util"
 	"github.com/go-pg/pg"
 	"github.com/golang-jwt/jwt/v4"
+	uuid "github.com/satori/go.uuid"
 	"go.uber.org/zap"
+	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -38,6 +44,14 @@ type ApiTokenService interface {
 	CreateApiToken(request *openapi.CreateApiTokenRequest, createdBy int32, managerAuth func(token string, object string) bool) (*openapi.CreateApiTokenResponse, error)
 	UpdateApiToken(apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error)
 	DeleteApiToken(apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error)
+	// RotateApiToken issues a new token for the api-token keeping its scope and expiry, the old token stops working
+	RotateApiToken(apiTokenId int, rotatedBy int32) (*openapi.UpdateApiTokenResponse, error)
+	// VerifyApiTokenScope returns error if the request is made by an api-token which is rotated or the request is not
+	// within the scope of api-token, requests made by other users are not checked
+	VerifyApiTokenScope(r *http.Request) error
+	// VerifyScimApiToken returns error if the request is not made by an api-token dedicated to scim provisioning or
+	// the token is not valid, the scope of token is verified as in VerifyApiTokenScope
+	VerifyScimApiToken(r *http.Request) error
 }
 
 type ApiTokenServiceImpl struct {
@@ -46,21 +60,28 @@ type ApiTokenServiceImpl struct {
 	userService           user.UserService
 	userAuditService      user.UserAuditService
 	apiTokenRepository    ApiTokenRepository
+	ciPipelineRepository  pipelineConfig.CiPipelineRepository
+	pipelineRepository    pipelineConfig.PipelineRepository
 }
 
 func NewApiTokenServiceImpl(logger *zap.SugaredLogger, apiTokenSecretService ApiTokenSecretService, userService user.UserService, userAuditService user.UserAuditService,
-	apiTokenRepository ApiTokenRepository) *ApiTokenServiceImpl {
+	apiTokenRepository ApiTokenRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository, pipelineRepository pipelineConfig.PipelineRepository) *ApiTokenServiceImpl {
 	return &ApiTokenServiceImpl{
 		logger:                logger,
 		apiTokenSecretService: apiTokenSecretService,
 		userService:           userService,
 		userAuditService:      userAuditService,
 		apiTokenRepository:    apiTokenRepository,
+		ciPipelineRepository:  ciPipelineRepository,
+		pipelineRepository:    pipelineRepository,
 	}
 }
 
 const API_TOKEN_USER_EMAIL_PREFIX = "API-TOKEN:"
 
+// LAST_USED_UPDATE_INTERVAL is the minimum interval between updates of last used time of api-token from same ip
+const LAST_USED_UPDATE_INTERVAL = time.Minute
+
 var invalidCharsInApiTokenName = regexp.MustCompile("[,\\s]")
 
 type ApiTokenCustomClaims struct {
@@ -96,12 +117,18 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 			ExpireAtInMs:   &apiTokenFromDb.ExpireAtInMs,
 			Token:          &apiTokenFromDb.Token,
 			UpdatedAt:      &updatedAtStr,
+			Scope:          buildApiTokenScope(apiTokenFromDb),
 		}
 		if latestAuditLog != nil {
 			lastUsedAtStr := latestAuditLog.CreatedOn.String()
 			apiToken.LastUsedAt = &lastUsedAtStr
 			apiToken.LastUsedByIp = &latestAuditLog.ClientIp
 		}
+		if !apiTokenFromDb.LastUsedAt.IsZero() && (latestAuditLog == nil || apiTokenFromDb.LastUsedAt.After(latestAuditLog.CreatedOn)) {
+			lastUsedAtStr := apiTokenFromDb.LastUsedAt.String()
+			apiToken.LastUsedAt = &lastUsedAtStr
+			apiToken.LastUsedByIp = &apiTokenFromDb.LastUsedByIp
+		}
 		apiTokens = append(apiTokens, apiToken)
 	}
 
@@ -116,6 +143,10 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 	if invalidCharsInApiTokenName.MatchString(name) {
 		return nil, errors.New(fmt.Sprintf("name '%s' contains either white-space or comma, which is not allowed", name))
 	}
+	err := validateApiTokenScope(request.Scope)
+	if err != nil {
+		return nil, err
+	}
 
 	// step-1 - check if the name exists, if exists with active user - throw error
 	apiToken, err := impl.apiTokenRepository.FindByName(name)
@@ -168,6 +199,7 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		Token:        token,
 		AuditLog:     sql.AuditLog{UpdatedOn: time.Now()},
 	}
+	setApiTokenScope(apiTokenSaveRequest, request.Scope)
 	if apiTokenExists {
 		apiTokenSaveRequest.Id = apiToken.Id
 		apiTokenSaveRequest.CreatedBy = apiToken.CreatedBy
@@ -267,6 +299,189 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 
 }
 
+func (impl ApiTokenServiceImpl) RotateApiToken(apiTokenId int, rotatedBy int32) (*openapi.UpdateApiTokenResponse, error) {
+	impl.logger.Infow("Rotating API token", "rotatedBy", rotatedBy, "apiTokenId", apiTokenId)
+
+	// step-1 - check if the api-token exists, if not exists - throw error
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 {
+		return nil, errors.New(fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId))
+	}
+
+	// step-2 - generate new token, scope and expiry are kept as it is
+	token, err := impl.createApiJwtToken(apiToken.User.EmailId, apiToken.ExpireAtInMs)
+	if err != nil {
+		return nil, err
+	}
+
+	// step-3 - update in DB, old token is rejected from now on as it does not match the saved one
+	apiToken.Token = token
+	apiToken.UpdatedBy = rotatedBy
+	apiToken.UpdatedOn = time.Now()
+	err = impl.apiTokenRepository.Update(apiToken)
+	if err != nil {
+		impl.logger.Errorw("error while rotating api-token", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+
+	success := true
+	return &openapi.UpdateApiTokenResponse{
+		Success: &success,
+		Token:   &apiToken.Token,
+	}, nil
+}
+
+func (impl ApiTokenServiceImpl) VerifyApiTokenScope(r *http.Request) error {
+	token := r.Header.Get("token")
+	if len(token) == 0 {
+		return nil
+	}
+	claims := &ApiTokenCustomClaims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(token, claims)
+	if err != nil || claims.Issuer != middleware.ApiTokenClaimIssuer {
+		// token signature and claims are verified by auth middleware, only api-tokens are checked here
+		return nil
+	}
+
+	name := strings.TrimPrefix(claims.Email, API_TOKEN_USER_EMAIL_PREFIX)
+	apiToken, err := impl.apiTokenRepository.FindByName(name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by name", "name", name, "error", err)
+		return err
+	}
+	if apiToken == nil || apiToken.Id == 0 || apiToken.User == nil || !apiToken.User.Active || apiToken.Token != token {
+		return &util2.ApiError{
+			HttpStatusCode:  http.StatusUnauthorized,
+			InternalMessage: "api-token is either deleted or rotated",
+			UserMessage:     "api-token is either deleted or rotated",
+		}
+	}
+
+	clientIp := util.GetTrustedClientIP(r)
+	scopeRequest, err := impl.buildApiTokenScopeRequest(r, apiToken, clientIp)
+	if err != nil {
+		return err
+	}
+	err = checkApiTokenScope(apiToken, scopeRequest)
+	if err != nil {
+		impl.logger.Infow("request is not within api-token scope", "name", name, "method", r.Method, "path", r.URL.Path, "reason", err)
+		return &util2.ApiError{
+			HttpStatusCode:  http.StatusForbidden,
+			InternalMessage: err.Error(),
+			UserMessage:     err.Error(),
+		}
+	}
+
+	if time.Since(apiToken.LastUsedAt) > LAST_USED_UPDATE_INTERVAL || apiToken.LastUsedByIp != clientIp {
+		err = impl.apiTokenRepository.UpdateLastUsed(apiToken.Id, time.Now(), clientIp)
+		if err != nil {
+			impl.logger.Errorw("error while updating last used of api-token", "apiTokenId", apiToken.Id, "error", err)
+		}
+	}
+	return nil
+}
+
+func (impl ApiTokenServiceImpl) VerifyScimApiToken(r *http.Request) error {
+	token := r.Header.Get("token")
+	_, err := impl.userService.GetEmailFromToken(token)
+	if len(token) == 0 || err != nil {
+		return &util2.ApiError{
+			HttpStatusCode:  http.StatusUnauthorized,
+			InternalMessage: "scim request without valid token",
+			UserMessage:     "Unauthorized",
+		}
+	}
+	claims := &ApiTokenCustomClaims{}
+	_, _, err = new(jwt.Parser).ParseUnverified(token, claims)
+	if err != nil || claims.Issuer != middleware.ApiTokenClaimIssuer {
+		return &util2.ApiError{
+			HttpStatusCode:  http.StatusForbidden,
+			InternalMessage: "scim request is not made by an api-token",
+			UserMessage:     "scim is allowed only for api-token dedicated to scim",
+		}
+	}
+	name := strings.TrimPrefix(claims.Email, API_TOKEN_USER_EMAIL_PREFIX)
+	apiToken, err := impl.apiTokenRepository.FindByName(name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by name", "name", name, "error", err)
+		return err
+	}
+	if apiToken != nil && apiToken.Id > 0 && !isScimApiToken(apiToken) {
+		return &util2.ApiError{
+			HttpStatusCode:  http.StatusForbidden,
+			InternalMessage: fmt.Sprintf("api-token %s is not restricted to scim route group", name),
+			UserMessage:     "scim is allowed only for api-token dedicated to scim",
+		}
+	}
+	return impl.VerifyApiTokenScope(r)
+}
+
+// buildApiTokenScopeRequest collects the apps and envs of the request, for pipeline requests they are resolved from
+// the pipeline
+func (impl ApiTokenServiceImpl) buildApiTokenScopeRequest(r *http.Request, apiToken *ApiToken, clientIp string) (*ApiTokenScopeRequest, error) {
+	scopeRequest := &ApiTokenScopeRequest{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		ClientIp: clientIp,
+	}
+	if len(apiToken.AllowedAppIds) == 0 && len(apiToken.AllowedEnvIds) == 0 {
+		return scopeRequest, nil
+	}
+	bodyFields := readJsonBodyFields(r)
+	var unresolved []string
+	scopeRequest.AppIds, unresolved = getRequestIds(r, bodyFields, appIdKeys)
+	scopeRequest.Unresolved = append(scopeRequest.Unresolved, unresolved...)
+	scopeRequest.EnvIds, unresolved = getRequestIds(r, bodyFields, envIdKeys)
+	scopeRequest.Unresolved = append(scopeRequest.Unresolved, unresolved...)
+
+	ciPipelineIds, unresolved := getRequestIds(r, bodyFields, ciPipelineIdKeys)
+	scopeRequest.Unresolved = append(scopeRequest.Unresolved, unresolved...)
+	cdPipelineIds, unresolved := getRequestIds(r, bodyFields, cdPipelineIdKeys)
+	scopeRequest.Unresolved = append(scopeRequest.Unresolved, unresolved...)
+	pipelineIds, unresolved := getRequestIds(r, bodyFields, pipelineIdKeys)
+	scopeRequest.Unresolved = append(scopeRequest.Unresolved, unresolved...)
+	// pipelineId is of a ci or cd pipeline as told by the route
+	if len(pipelineIds) > 0 {
+		if r.URL.Path == CI_TRIGGER_PATH || strings.Contains(r.URL.Path, "/ci-pipeline") {
+			ciPipelineIds = append(ciPipelineIds, pipelineIds...)
+		} else if r.URL.Path == CD_TRIGGER_PATH || strings.Contains(r.URL.Path, "/cd-pipeline") {
+			cdPipelineIds = append(cdPipelineIds, pipelineIds...)
+		} else {
+			scopeRequest.Unresolved = append(scopeRequest.Unresolved, fmt.Sprintf("pipelineId of route '%s'", r.URL.Path))
+		}
+	}
+	for _, pipelineId := range ciPipelineIds {
+		ciPipeline, err := impl.ciPipelineRepository.FindById(pipelineId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while getting ci pipeline", "pipelineId", pipelineId, "error", err)
+			return nil, err
+		}
+		if ciPipeline == nil || ciPipeline.AppId == 0 {
+			scopeRequest.Unresolved = append(scopeRequest.Unresolved, fmt.Sprintf("ci pipeline '%d'", pipelineId))
+			continue
+		}
+		scopeRequest.AppIds = append(scopeRequest.AppIds, ciPipeline.AppId)
+	}
+	for _, pipelineId := range cdPipelineIds {
+		pipeline, err := impl.pipelineRepository.FindById(pipelineId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while getting cd pipeline", "pipelineId", pipelineId, "error", err)
+			return nil, err
+		}
+		if pipeline == nil || pipeline.Id == 0 {
+			scopeRequest.Unresolved = append(scopeRequest.Unresolved, fmt.Sprintf("cd pipeline '%d'", pipelineId))
+			continue
+		}
+		scopeRequest.AppIds = append(scopeRequest.AppIds, pipeline.AppId)
+		scopeRequest.EnvIds = append(scopeRequest.EnvIds, pipeline.EnvironmentId)
+	}
+	return scopeRequest, nil
+}
+
 func (impl ApiTokenServiceImpl) createApiJwtToken(email string, expireAtInMs int64) (string, error) {
 	secretByteArr, err := impl.apiTokenSecretService.GetApiTokenSecretByteArr()
 	if err != nil {
@@ -274,8 +489,10 @@ func (impl ApiTokenServiceImpl) createApiJwtToken(email string, expireAtInMs int
 		return "", err
 	}
 
+	// unique id makes the rotated token differ from the old one even if rest of the claims are same
 	registeredClaims := jwt.RegisteredClaims{
 		Issuer: middleware.ApiTokenClaimIssuer,
+		ID:     uuid.NewV4().String(),
 	}
 	if expireAtInMs > 0 {
 		registeredClaims.ExpiresAt = jwt.NewNumericDate(time.Unix(expireAtInMs/1000, 0))
@@ -28,7 +28,6 @@ import (
 	chart2 "k8s.io/helm/pkg/proto/hapi/chart"
 	"net/url"
 	"path"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -99,6 +98,7 @@ type AppServiceImpl struct {
 	commonService                    commonService.CommonService
 	imageScanDeployInfoRepository    security.ImageScanDeployInfoRepository
 	imageScanHistoryRepository       security.ImageScanHistoryRepository
+	imageScanNewCveRepository        security.ImageScanNewCveRepository
 	ArgoK8sClient                    argocdServer.ArgoK8sClient
 	gitOpsRepository                 repository.GitOpsConfigRepository
 	pipelineStrategyHistoryService   history2.PipelineStrategyHistoryService
@@ -111,6 +111,8 @@ type AppServiceImpl struct {
 	chartService                     chart.ChartService
 	argoUserService                  argo.ArgoUserService
 	cdPipelineStatusTimelineRepo     pipelineConfig.PipelineStatusTimelineRepository
+	timelineResourcesRepository      pipelineConfig.PipelineStatusTimelineResourcesRepository
+	pipelineStatusTimelineService    PipelineStatusTimelineService
 }
 
 type AppService interface {
@@ -123,6 +125,7 @@ type AppService interface {
 	GetCmSecretNew(appId int, envId int) (*bean.ConfigMapJson, *bean.ConfigSecretJson, error)
 	MarkImageScanDeployed(appId int, envId int, imageDigest string, clusterId int) error
 	GetChartRepoName(gitRepoUrl string) string
+	UpdateArgoAppForRelease(pipelineOverride *chartConfig.PipelineOverride) error
 }
 
 func NewAppService(
@@ -155,7 +158,10 @@ func NewAppService(
 	chartRefRepository chartRepoRepository.ChartRefRepository,
 	chartService chart.ChartService, helmAppClient client2.HelmAppClient,
 	argoUserService argo.ArgoUserService,
-	cdPipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository) *AppServiceImpl {
+	cdPipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository,
+	timelineResourcesRepository pipelineConfig.PipelineStatusTimelineResourcesRepository,
+	imageScanNewCveRepository security.ImageScanNewCveRepository,
+	pipelineStatusTimelineService PipelineStatusTimelineService) *AppServiceImpl {
 	appServiceImpl := &AppServiceImpl{
 		environmentConfigRepository:      environmentConfigRepository,
 		mergeUtil:                        mergeUtil,
@@ -185,6 +191,7 @@ func NewAppService(
 		commonService:                    commonService,
 		imageScanDeployInfoRepository:    imageScanDeployInfoRepository,
 		imageScanHistoryRepository:       imageScanHistoryRepository,
+		imageScanNewCveRepository:        imageScanNewCveRepository,
 		ArgoK8sClient:                    ArgoK8sClient,
 		gitFactory:                       gitFactory,
 		gitOpsRepository:                 gitOpsRepository,
@@ -198,12 +205,16 @@ func NewAppService(
 		helmAppClient:                    helmAppClient,
 		argoUserService:                  argoUserService,
 		cdPipelineStatusTimelineRepo:     cdPipelineStatusTimelineRepo,
+		timelineResourcesRepository:      timelineResourcesRepository,
+		pipelineStatusTimelineService:    pipelineStatusTimelineService,
 	}
 	return appServiceImpl
 }
 
 const WorkflowAborted = "Aborted"
 const WorkflowFailed = "Failed"
+const WorkflowInProgress = "Progressing"
+const WorkflowAwaitingMerge = "AwaitingMerge"
 
 func (impl AppServiceImpl) getValuesFileForEnv(environmentId int) string {
 	return fmt.Sprintf("_%d-values.yaml", environmentId) //-{envId}-values.yaml
@@ -392,6 +403,10 @@ func (impl *AppServiceImpl) UpdatePipelineStatusTimelineForApplicationChanges(ne
 		impl.logger.Errorw("error in finding cd wfr by workflowId and runnerType", "err", err)
 		return err
 	}
+	err = impl.UpdatePipelineStatusTimelineResources(newApp, cdWfr.Id)
+	if err != nil {
+		impl.logger.Errorw("error in updating resources of pipeline status timeline", "err", err, "wfrId", cdWfr.Id)
+	}
 	// creating cd pipeline status timeline
 	timeline := &pipelineConfig.PipelineStatusTimeline{
 		CdWorkflowRunnerId: cdWfr.Id,
@@ -477,6 +492,94 @@ func (impl *AppServiceImpl) UpdatePipelineStatusTimelineForApplicationChanges(ne
 	return nil
 }
 
+// UpdatePipelineStatusTimelineResources persists sync result and health of every resource of the application against
+// the deployment, resources already captured for the deployment are updated in place
+func (impl *AppServiceImpl) UpdatePipelineStatusTimelineResources(newApp *v1alpha1.Application, wfrId int) error {
+	resourceKey := func(group, kind, namespace, name string) string {
+		return fmt.Sprintf("%s/%s/%s/%s", group, kind, namespace, name)
+	}
+	existingResources, err := impl.timelineResourcesRepository.FetchTimelineResourcesByWfrId(wfrId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	resourcesMap := make(map[string]*pipelineConfig.PipelineStatusTimelineResources)
+	for _, existingResource := range existingResources {
+		resourcesMap[resourceKey(existingResource.ResourceGroup, existingResource.ResourceKind, existingResource.ResourceNamespace, existingResource.ResourceName)] = existingResource
+	}
+	var newResources []*pipelineConfig.PipelineStatusTimelineResources
+	changedResources := make(map[string]*pipelineConfig.PipelineStatusTimelineResources)
+	getResource := func(group, version, kind, namespace, name string) *pipelineConfig.PipelineStatusTimelineResources {
+		key := resourceKey(group, kind, namespace, name)
+		if resource, ok := resourcesMap[key]; ok {
+			if resource.Id > 0 {
+				changedResources[key] = resource
+			}
+			return resource
+		}
+		resource := &pipelineConfig.PipelineStatusTimelineResources{
+			CdWorkflowRunnerId: wfrId,
+			ResourceGroup:      group,
+			ResourceVersion:    version,
+			ResourceKind:       kind,
+			ResourceNamespace:  namespace,
+			ResourceName:       name,
+			AuditLog:           sql.AuditLog{CreatedBy: 1, CreatedOn: time.Now(), UpdatedBy: 1, UpdatedOn: time.Now()},
+		}
+		resourcesMap[key] = resource
+		newResources = append(newResources, resource)
+		return resource
+	}
+	for _, resourceStatus := range newApp.Status.Resources {
+		resource := getResource(resourceStatus.Group, resourceStatus.Version, resourceStatus.Kind, resourceStatus.Namespace, resourceStatus.Name)
+		resource.SyncStatus = string(resourceStatus.Status)
+		if resourceStatus.Health != nil {
+			resource.HealthStatus = string(resourceStatus.Health.Status)
+			resource.StatusMessage = resourceStatus.Health.Message
+		}
+	}
+	// result of the last sync operation is more specific than the comparison status, so it takes precedence
+	if newApp.Status.OperationState != nil && newApp.Status.OperationState.SyncResult != nil &&
+		newApp.Status.OperationState.SyncResult.Revision == newApp.Status.Sync.Revision {
+		for _, resourceResult := range newApp.Status.OperationState.SyncResult.Resources {
+			resource := getResource(resourceResult.Group, resourceResult.Version, resourceResult.Kind, resourceResult.Namespace, resourceResult.Name)
+			if len(resourceResult.Status) > 0 {
+				resource.SyncStatus = string(resourceResult.Status)
+			}
+			resource.SyncPhase = string(resourceResult.SyncPhase)
+			if len(resourceResult.Message) > 0 {
+				resource.StatusMessage = resourceResult.Message
+			}
+		}
+	}
+	if len(newResources) > 0 {
+		err = impl.timelineResourcesRepository.SaveTimelineResources(newResources)
+		if err != nil {
+			return err
+		}
+	}
+	if len(changedResources) > 0 {
+		var updatedResources []*pipelineConfig.PipelineStatusTimelineResources
+		for _, resource := range changedResources {
+			resource.UpdatedOn = time.Now()
+			resource.UpdatedBy = 1
+			updatedResources = append(updatedResources, resource)
+		}
+		err = impl.timelineResourcesRepository.UpdateTimelineResources(updatedResources)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveHelmTimeline records a step of helm deployment, failure in saving it is logged as it must not fail the deployment
+func (impl *AppServiceImpl) saveHelmTimeline(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) {
+	err := impl.pipelineStatusTimelineService.SaveTimeline(wfrId, status, statusDetail, userId)
+	if err != nil {
+		impl.logger.Errorw("error in saving timeline of helm deployment", "err", err, "wfrId", wfrId, "status", status)
+	}
+}
+
 func (impl *AppServiceImpl) SavePipelineStatusTimelineIfNotAlreadyPresent(cdWorkflowId int, timelineStatus pipelineConfig.TimelineStatus, timeline *pipelineConfig.PipelineStatusTimeline) (latestTimeline *pipelineConfig.PipelineStatusTimeline, err error) {
 	latestTimeline, err = impl.cdPipelineStatusTimelineRepo.FetchTimelineOfLatestWfByCdWorkflowIdAndStatus(cdWorkflowId, timelineStatus)
 	if err != nil && err != pg.ErrNoRows {
@@ -739,7 +842,7 @@ func (impl AppServiceImpl) TriggerRelease(overrideRequest *bean.ValuesOverrideRe
 	referenceTemplatePath := path.Join(string(impl.refChartDir), envOverride.Chart.ReferenceTemplate)
 	if pipeline.DeploymentAppType == PIPELINE_DEPLOYMENT_TYPE_ACD {
 		// CHART COMMIT and PUSH STARTS HERE, it will push latest version, if found modified on deployment template and overrides
-		gitOpsRepoName := impl.chartTemplateService.GetGitOpsRepoName(pipeline.App.AppName)
+		gitOpsRepoName := impl.chartTemplateService.GetGitOpsRepoNameFromUrl(envOverride.Chart.GitRepoUrl)
 
 		chartData, err = impl.chartRefRepository.FindById(envOverride.Chart.ChartRefId)
 		if err != nil {
@@ -753,7 +856,7 @@ func (impl AppServiceImpl) TriggerRelease(overrideRequest *bean.ValuesOverrideRe
 		}
 
 		userUploaded = chartData.UserUploaded
-		err = impl.chartTemplateService.BuildChartAndPushToGitRepo(chartMetaData, referenceTemplatePath, gitOpsRepoName, envOverride.Chart.ReferenceTemplate, envOverride.Chart.ChartVersion, envOverride.Chart.GitRepoUrl, overrideRequest.UserId)
+		err = impl.chartTemplateService.BuildChartAndPushToGitRepo(chartMetaData, referenceTemplatePath, gitOpsRepoName, envOverride.Chart.ChartLocation, envOverride.Chart.GitRepoUrl, overrideRequest.UserId)
 		if err != nil {
 			impl.logger.Errorw("Ref chart commit error on cd trigger", "err", err, "req", overrideRequest)
 			return 0, err
@@ -848,10 +951,11 @@ func (impl AppServiceImpl) TriggerRelease(overrideRequest *bean.ValuesOverrideRe
 		configMapJson = nil
 	}
 
-	releaseId, pipelineOverrideId, mergeAndSave, saveErr := impl.mergeAndSave(envOverride, overrideRequest, dbMigrationOverride, artifact, pipeline, configMapJson, strategy, ctx, triggeredAt, deployedBy)
+	releaseId, pipelineOverrideId, mergeAndSave, saveErr := impl.mergeAndSave(envOverride, overrideRequest, dbMigrationOverride, artifact, pipeline, configMapJson, strategy, ctx, triggeredAt, deployedBy, wfrId)
 	if releaseId != 0 {
-		//updating the acd app with updated values and sync operation
-		if pipeline.DeploymentAppType == PIPELINE_DEPLOYMENT_TYPE_ACD {
+		//updating the acd app with updated values and sync operation, for pull request based environments it is
+		//updated once the pull request is merged
+		if pipeline.DeploymentAppType == PIPELINE_DEPLOYMENT_TYPE_ACD && !env.GitOpsPullRequest {
 			updateAppInArgocd, err := impl.updateArgoPipeline(overrideRequest.AppId, pipeline.Name, envOverride, ctx)
 			if err != nil {
 				impl.logger.Errorw("error in updating argocd  app ", "err", err)
@@ -892,7 +996,7 @@ func (impl AppServiceImpl) TriggerRelease(overrideRequest *bean.ValuesOverrideRe
 
 		//for helm type cd pipeline, create install helm application, update deployment status, update workflow runner for app detail status.
 		if pipeline.DeploymentAppType == PIPELINE_DEPLOYMENT_TYPE_HELM {
-			_, err = impl.createHelmAppForCdPipeline(overrideRequest, envOverride, referenceTemplatePath, chartMetaData, triggeredAt, pipeline, mergeAndSave, ctx)
+			_, err = impl.createHelmAppForCdPipeline(overrideRequest, envOverride, referenceTemplatePath, chartMetaData, triggeredAt, pipeline, mergeAndSave, ctx, wfrId)
 			if err != nil {
 				impl.logger.Errorw("error in creating or updating helm application for cd pipeline", "err", err)
 				return 0, err
@@ -935,8 +1039,9 @@ func (impl AppServiceImpl) autoHealChartLocationInChart(envOverride *chartConfig
 		return err
 	}
 
-	// build new chart location
-	newChartLocation := filepath.Join(chartRef.Location, envOverride.Chart.ChartVersion)
+	// build new chart location, keeping directory of app in gitops repo shared by many apps
+	appDir := GetAppDirInGitOpsRepo(envOverride.Chart.ChartName, envOverride.Chart.ChartLocation)
+	newChartLocation := GetChartLocationInGitOpsRepo(appDir, chartRef.Location, envOverride.Chart.ChartVersion)
 	impl.logger.Infow("new chart location build", "chartId", chartId, "newChartLocation", newChartLocation)
 
 	// update chart in DB
@@ -989,6 +1094,20 @@ func (impl AppServiceImpl) MarkImageScanDeployed(appId int, envId int, imageDige
 		if err != nil {
 			impl.logger.Errorw("error in creating deploy info", "err", err)
 		}
+	} else if ot.EnvId == envId && (len(ot.ImageScanExecutionHistoryId) != 1 || ot.ImageScanExecutionHistoryId[0] != executionHistory.Id) {
+		// new image deployed, cves found by rescanning the previous image are no more new for this deployment
+		ot.ImageScanExecutionHistoryId = ids
+		ot.UpdatedOn = time.Now()
+		ot.UpdatedBy = 1
+		err = impl.imageScanDeployInfoRepository.Update(ot)
+		if err != nil {
+			impl.logger.Errorw("error in updating deploy info", "err", err)
+			return err
+		}
+		err = impl.imageScanNewCveRepository.DeleteByDeployInfoId(ot.Id)
+		if err != nil {
+			impl.logger.Errorw("error in deleting new cves of previous deployment", "err", err, "imageScanDeployInfoId", ot.Id)
+		}
 	} else {
 		impl.logger.Debugw("pt", "ot", ot)
 	}
@@ -1382,7 +1501,7 @@ func (impl AppServiceImpl) mergeAndSave(envOverride *chartConfig.EnvConfigOverri
 	dbMigrationOverride []byte,
 	artifact *repository.CiArtifact,
 	pipeline *pipelineConfig.Pipeline, configMapJson []byte, strategy *chartConfig.PipelineStrategy, ctx context.Context,
-	triggeredAt time.Time, deployedBy int32) (releaseId int, overrideId int, mergedValues string, err error) {
+	triggeredAt time.Time, deployedBy int32, wfrId int) (releaseId int, overrideId int, mergedValues string, err error) {
 
 	//register release , obtain release id TODO: populate releaseId to template
 	override, err := impl.savePipelineOverride(overrideRequest, envOverride.Id, triggeredAt)
@@ -1460,10 +1579,20 @@ func (impl AppServiceImpl) mergeAndSave(envOverride *chartConfig.EnvConfigOverri
 				return 0, 0, "", err
 			}
 		}
-		commitHash, err = impl.gitFactory.Client.CommitValues(chartGitAttr, gitOpsConfigBitbucket.BitBucketWorkspaceId)
-		if err != nil {
-			impl.logger.Errorw("error in git commit", "err", err)
-			return 0, 0, "", err
+		if envOverride.Environment != nil && envOverride.Environment.GitOpsPullRequest {
+			//git hash of release is the merge commit, it is set once the pull request is merged
+			chartGitAttr.Branch = fmt.Sprintf("devtron-release-%d-env-%d", override.Id, envOverride.TargetEnvironment)
+			err = impl.createGitOpsPullRequest(chartGitAttr, gitOpsConfigBitbucket.BitBucketWorkspaceId, wfrId, deployedBy)
+			if err != nil {
+				impl.logger.Errorw("error in creating gitops pull request", "err", err)
+				return 0, 0, "", err
+			}
+		} else {
+			commitHash, err = impl.gitFactory.Client.CommitValues(chartGitAttr, gitOpsConfigBitbucket.BitBucketWorkspaceId)
+			if err != nil {
+				impl.logger.Errorw("error in git commit", "err", err)
+				return 0, 0, "", err
+			}
 		}
 	}
 	pipelineOverride := &chartConfig.PipelineOverride{
@@ -1489,6 +1618,47 @@ func (impl AppServiceImpl) mergeAndSave(envOverride *chartConfig.EnvConfigOverri
 	return override.PipelineReleaseCounter, override.Id, mergedValues, nil
 }
 
+// createGitOpsPullRequest opens a pull request of values into master for environments which deploy through pull
+// requests, the deployment runner awaits merge of the pull request
+func (impl AppServiceImpl) createGitOpsPullRequest(chartGitAttr *ChartConfig, bitbucketWorkspaceId string, wfrId int, userId int32) error {
+	pullRequest, err := impl.gitFactory.Client.CreatePullRequest(chartGitAttr, bitbucketWorkspaceId)
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request", "repo", chartGitAttr.ChartRepoName, "branch", chartGitAttr.Branch, "err", err)
+		return err
+	}
+	runner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(wfrId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd workflow runner", "wfrId", wfrId, "err", err)
+		return err
+	}
+	runner.Status = WorkflowAwaitingMerge
+	runner.Message = fmt.Sprintf("waiting for merge of pull request %s", pullRequest.Url)
+	runner.GitOpsPullRequestId = pullRequest.Id
+	runner.GitOpsPullRequestUrl = pullRequest.Url
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating cd workflow runner", "wfrId", wfrId, "err", err)
+		return err
+	}
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: wfrId,
+		Status:             pipelineConfig.TIMELINE_STATUS_PULL_REQUEST_OPENED,
+		StatusDetail:       fmt.Sprintf("Pull request %s opened, deployment starts once it is merged.", pullRequest.Url),
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: userId,
+			CreatedOn: time.Now(),
+			UpdatedBy: userId,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err = impl.cdPipelineStatusTimelineRepo.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeline status for pull request", "err", err, "timeline", timeline)
+	}
+	return nil
+}
+
 func (impl AppServiceImpl) savePipelineOverride(overrideRequest *bean.ValuesOverrideRequest, envOverrideId int, triggeredAt time.Time) (override *chartConfig.PipelineOverride, err error) {
 	currentReleaseNo, err := impl.pipelineOverrideRepository.GetCurrentPipelineReleaseCounter(overrideRequest.PipelineId)
 	if err != nil {
@@ -1576,7 +1746,7 @@ func (impl AppServiceImpl) updateArgoPipeline(appId int, pipelineName string, en
 
 	if appStatus.Code() == codes.OK {
 		impl.logger.Debugw("argo app exists", "app", argoAppName, "pipeline", pipelineName)
-		if application.Spec.Source.Path != envOverride.Chart.ChartLocation || application.Spec.Source.TargetRevision != "master" {
+		if application.Spec.Source.Path != envOverride.Chart.ChartLocation || application.Spec.Source.RepoURL != envOverride.Chart.GitRepoUrl || application.Spec.Source.TargetRevision != "master" {
 			patchReq := v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: v1alpha1.ApplicationSource{Path: envOverride.Chart.ChartLocation, RepoURL: envOverride.Chart.GitRepoUrl, TargetRevision: "master"}}}
 			reqbyte, err := json.Marshal(patchReq)
 			if err != nil {
@@ -1603,6 +1773,34 @@ func (impl AppServiceImpl) updateArgoPipeline(appId int, pipelineName string, en
 	}
 }
 
+// UpdateArgoAppForRelease points the argo app of release pipeline to chart of the release, used for releases of
+// pull request based environments once the pull request is merged
+func (impl AppServiceImpl) UpdateArgoAppForRelease(pipelineOverride *chartConfig.PipelineOverride) error {
+	envOverride, err := impl.environmentConfigRepository.Get(pipelineOverride.EnvConfigOverrideId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching env config override", "id", pipelineOverride.EnvConfigOverrideId, "err", err)
+		return err
+	}
+	if !envOverride.IsOverride {
+		chart, err := impl.chartRepository.FindLatestChartForAppByAppId(pipelineOverride.Pipeline.AppId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching latest chart", "appId", pipelineOverride.Pipeline.AppId, "err", err)
+			return err
+		}
+		envOverride.Chart = chart
+	}
+	ctx, err := impl.buildACDContext()
+	if err != nil {
+		return err
+	}
+	_, err = impl.updateArgoPipeline(pipelineOverride.Pipeline.AppId, pipelineOverride.Pipeline.Name, envOverride, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in updating argocd app", "pipelineOverrideId", pipelineOverride.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
 func (impl *AppServiceImpl) UpdateCdWorkflowRunnerByACDObject(app *v1alpha1.Application, cdWorkflowId int) error {
 	cdWorkflow, err := impl.cdWorkflowRepository.FindById(cdWorkflowId)
 	if err != nil {
@@ -1731,7 +1929,7 @@ func (impl AppServiceImpl) updatePipeline(pipeline *pipelineConfig.Pipeline, use
 
 func (impl AppServiceImpl) createHelmAppForCdPipeline(overrideRequest *bean.ValuesOverrideRequest,
 	envOverride *chartConfig.EnvConfigOverride, referenceTemplatePath string, chartMetaData *chart2.Metadata,
-	triggeredAt time.Time, pipeline *pipelineConfig.Pipeline, mergeAndSave string, ctx context.Context) (bool, error) {
+	triggeredAt time.Time, pipeline *pipelineConfig.Pipeline, mergeAndSave string, ctx context.Context, wfrId int) (bool, error) {
 	if pipeline.DeploymentAppType == PIPELINE_DEPLOYMENT_TYPE_HELM {
 		referenceChartByte := envOverride.Chart.ReferenceChart
 		// here updating reference chart into database.
@@ -1756,6 +1954,8 @@ func (impl AppServiceImpl) createHelmAppForCdPipeline(overrideRequest *bean.Valu
 		releaseName := fmt.Sprintf("%s-%s", pipeline.App.AppName, envOverride.Environment.Name)
 		bearerToken := envOverride.Environment.Cluster.Config["bearer_token"]
 		isSuccess := false
+		// timeline is informational, failure in saving it is only logged and must not fail the deployment
+		impl.saveHelmTimeline(wfrId, pipelineConfig.TIMELINE_STATUS_HELM_UPGRADE_STARTED, "Helm upgrade started.", overrideRequest.UserId)
 		if pipeline.DeploymentAppCreated {
 			req := &client2.UpgradeReleaseRequest{
 				ReleaseIdentifier: &client2.ReleaseIdentifier{
@@ -1773,6 +1973,7 @@ func (impl AppServiceImpl) createHelmAppForCdPipeline(overrideRequest *bean.Valu
 			updateApplicationResponse, err := impl.helmAppClient.UpdateApplication(ctx, req)
 			if err != nil {
 				impl.logger.Errorw("error in updating helm application for cd pipeline", "err", err)
+				impl.saveHelmTimeline(wfrId, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, fmt.Sprintf("Helm upgrade failed: %s", err.Error()), overrideRequest.UserId)
 				return false, err
 			}
 			isSuccess = updateApplicationResponse.Success
@@ -1794,10 +1995,16 @@ func (impl AppServiceImpl) createHelmAppForCdPipeline(overrideRequest *bean.Valu
 			helmResponse, err := impl.helmAppClient.InstallReleaseWithCustomChart(ctx, helmInstallRequest)
 			if err != nil {
 				impl.logger.Errorw("error in helm install custom chart", "err", err)
+				impl.saveHelmTimeline(wfrId, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, fmt.Sprintf("Helm install failed: %s", err.Error()), overrideRequest.UserId)
 				return false, err
 			}
 			isSuccess = helmResponse.Success
 		}
+		if isSuccess {
+			impl.saveHelmTimeline(wfrId, pipelineConfig.TIMELINE_STATUS_HELM_UPGRADE_FINISHED, "Helm upgrade finished, waiting for resources to be ready.", overrideRequest.UserId)
+		} else {
+			impl.saveHelmTimeline(wfrId, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, "Helm upgrade was not successful.", overrideRequest.UserId)
+		}
 
 		// update deployment status, used in deployment history
 		deploymentStatus := &repository.DeploymentStatus{
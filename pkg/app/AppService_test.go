@@ -0,0 +1,79 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeTimelineResourcesRepository struct {
+	pipelineConfig.PipelineStatusTimelineResourcesRepository
+	resources []*pipelineConfig.PipelineStatusTimelineResources
+	updated   []*pipelineConfig.PipelineStatusTimelineResources
+}
+
+func (f *fakeTimelineResourcesRepository) SaveTimelineResources(timelineResources []*pipelineConfig.PipelineStatusTimelineResources) error {
+	for _, resource := range timelineResources {
+		resource.Id = len(f.resources) + 1
+		f.resources = append(f.resources, resource)
+	}
+	return nil
+}
+
+func (f *fakeTimelineResourcesRepository) UpdateTimelineResources(timelineResources []*pipelineConfig.PipelineStatusTimelineResources) error {
+	f.updated = append(f.updated, timelineResources...)
+	return nil
+}
+
+func (f *fakeTimelineResourcesRepository) FetchTimelineResourcesByWfrId(wfrId int) ([]*pipelineConfig.PipelineStatusTimelineResources, error) {
+	return f.resources, nil
+}
+
+func getTestSyncedApp(revision string, syncedRevision string) *v1alpha1.Application {
+	argoApp := &v1alpha1.Application{}
+	argoApp.Status.Sync.Revision = revision
+	argoApp.Status.Resources = []v1alpha1.ResourceStatus{
+		{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "dev", Name: "app-one", Status: v1alpha1.SyncStatusCodeSynced,
+			Health: &v1alpha1.HealthStatus{Status: health.HealthStatusDegraded, Message: "Deployment exceeded its progress deadline"}},
+		{Version: "v1", Kind: "Service", Namespace: "dev", Name: "app-one", Status: v1alpha1.SyncStatusCodeSynced,
+			Health: &v1alpha1.HealthStatus{Status: health.HealthStatusHealthy}},
+	}
+	argoApp.Status.OperationState = &v1alpha1.OperationState{SyncResult: &v1alpha1.SyncOperationResult{Revision: syncedRevision,
+		Resources: v1alpha1.ResourceResults{
+			{Version: "v1", Kind: "ConfigMap", Namespace: "dev", Name: "app-one-cm", Status: common.ResultCodeSyncFailed,
+				SyncPhase: common.SyncPhaseSync, Message: "the server could not find the requested resource"},
+		}}}
+	return argoApp
+}
+
+func TestAppService_UpdatePipelineStatusTimelineResources(t *testing.T) {
+	resourcesRepository := &fakeTimelineResourcesRepository{}
+	impl := &AppServiceImpl{logger: zap.NewNop().Sugar(), timelineResourcesRepository: resourcesRepository}
+
+	err := impl.UpdatePipelineStatusTimelineResources(getTestSyncedApp("rev-1", "rev-1"), 5)
+	assert.Nil(t, err)
+	assert.Len(t, resourcesRepository.resources, 3)
+	deployment, service, configMap := resourcesRepository.resources[0], resourcesRepository.resources[1], resourcesRepository.resources[2]
+	assert.Equal(t, 5, deployment.CdWorkflowRunnerId)
+	assert.Equal(t, string(health.HealthStatusDegraded), deployment.HealthStatus)
+	assert.Equal(t, "Deployment exceeded its progress deadline", deployment.StatusMessage)
+	assert.Equal(t, string(health.HealthStatusHealthy), service.HealthStatus)
+	assert.Equal(t, string(common.ResultCodeSyncFailed), configMap.SyncStatus)
+	assert.Equal(t, string(common.SyncPhaseSync), configMap.SyncPhase)
+	assert.Equal(t, "the server could not find the requested resource", configMap.StatusMessage)
+
+	// resources captured earlier for the deployment are updated in place, sync result of an older revision is ignored
+	argoApp := getTestSyncedApp("rev-2", "rev-1")
+	argoApp.Status.Resources[0].Health = &v1alpha1.HealthStatus{Status: health.HealthStatusHealthy}
+	err = impl.UpdatePipelineStatusTimelineResources(argoApp, 5)
+	assert.Nil(t, err)
+	assert.Len(t, resourcesRepository.resources, 3)
+	assert.Len(t, resourcesRepository.updated, 2)
+	assert.Equal(t, string(health.HealthStatusHealthy), deployment.HealthStatus)
+	assert.Equal(t, string(common.ResultCodeSyncFailed), configMap.SyncStatus)
+}
@@ -0,0 +1,300 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package app
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"go.uber.org/zap"
+)
+
+const doraWeek = 7 * 24 * time.Hour
+
+// deploymentStatusFailed is the status of a deploy runner which failed before reaching the cluster
+const deploymentStatusFailed = "Failed"
+
+type DoraMetricsRequest struct {
+	AppIds        []int
+	EnvironmentId int
+	From          time.Time
+	To            time.Time
+}
+
+type DoraMetrics struct {
+	TotalDeployments            int     `json:"totalDeployments"`
+	SuccessfulDeployments       int     `json:"successfulDeployments"`
+	FailedDeployments           int     `json:"failedDeployments"`
+	DeploymentFrequencyPerWeek  float64 `json:"deploymentFrequencyPerWeek"`
+	LeadTimeForChangesInMinutes float64 `json:"leadTimeForChangesInMinutes"`
+	// ChangeFailureRate is the percentage of finished deployments which failed
+	ChangeFailureRate           float64 `json:"changeFailureRate"`
+	MeanTimeToRecoveryInMinutes float64 `json:"meanTimeToRecoveryInMinutes"`
+	Recoveries                  int     `json:"recoveries"`
+}
+
+type DoraWeeklyMetrics struct {
+	WeekStart time.Time `json:"weekStart"`
+	DoraMetrics
+}
+
+type DoraMetricsResponse struct {
+	From    time.Time            `json:"from"`
+	To      time.Time            `json:"to"`
+	Summary *DoraMetrics         `json:"summary"`
+	Weekly  []*DoraWeeklyMetrics `json:"weekly"`
+}
+
+type DoraMetricsService interface {
+	// GetDoraMetrics computes deployment frequency, lead time for changes, change failure rate and mean time to
+	// recovery of the deployments started in the request range, overall and in weekly buckets starting on monday
+	GetDoraMetrics(request *DoraMetricsRequest) (*DoraMetricsResponse, error)
+}
+
+type DoraMetricsServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	cdWorkflowRepository       pipelineConfig.CdWorkflowRepository
+	pipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository
+}
+
+func NewDoraMetricsServiceImpl(logger *zap.SugaredLogger, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository) *DoraMetricsServiceImpl {
+	return &DoraMetricsServiceImpl{
+		logger:                     logger,
+		cdWorkflowRepository:       cdWorkflowRepository,
+		pipelineStatusTimelineRepo: pipelineStatusTimelineRepo,
+	}
+}
+
+// doraMetricsAccumulator collects the raw sums of a bucket, converted to DoraMetrics once all deployments are seen
+type doraMetricsAccumulator struct {
+	start         time.Time
+	end           time.Time
+	total         int
+	successful    int
+	failed        int
+	leadTimeSum   time.Duration
+	leadTimeCount int
+	recoverySum   time.Duration
+	recoveryCount int
+}
+
+func (acc *doraMetricsAccumulator) toDoraMetrics() DoraMetrics {
+	metrics := DoraMetrics{
+		TotalDeployments:      acc.total,
+		SuccessfulDeployments: acc.successful,
+		FailedDeployments:     acc.failed,
+		Recoveries:            acc.recoveryCount,
+	}
+	if period := acc.end.Sub(acc.start); period > 0 {
+		metrics.DeploymentFrequencyPerWeek = float64(acc.successful) * float64(doraWeek) / float64(period)
+	}
+	if acc.leadTimeCount > 0 {
+		metrics.LeadTimeForChangesInMinutes = acc.leadTimeSum.Minutes() / float64(acc.leadTimeCount)
+	}
+	if finished := acc.successful + acc.failed; finished > 0 {
+		metrics.ChangeFailureRate = float64(acc.failed) * 100 / float64(finished)
+	}
+	if acc.recoveryCount > 0 {
+		metrics.MeanTimeToRecoveryInMinutes = acc.recoverySum.Minutes() / float64(acc.recoveryCount)
+	}
+	return metrics
+}
+
+var successfulDeploymentStatuses = []string{application.Healthy, application.SUCCEEDED}
+
+var failedDeploymentStatuses = []string{application.Degraded, deploymentStatusFailed}
+
+func isSuccessfulDeployment(status string) bool {
+	return status == application.Healthy || status == application.SUCCEEDED
+}
+
+func isFailedDeployment(status string) bool {
+	return status == application.Degraded || status == deploymentStatusFailed
+}
+
+func getFailedOn(runner *pipelineConfig.DeployRunnerMetricData) time.Time {
+	if runner.FinishedOn.IsZero() {
+		return runner.StartedOn
+	}
+	return runner.FinishedOn
+}
+
+// getWeekStart returns monday 00:00 UTC of the week of t
+func getWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+func (impl *DoraMetricsServiceImpl) GetDoraMetrics(request *DoraMetricsRequest) (*DoraMetricsResponse, error) {
+	runners, err := impl.cdWorkflowRepository.FindDeployRunnersForMetrics(request.AppIds, request.EnvironmentId, request.From, request.To)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployments for dora metrics", "err", err, "request", request)
+		return nil, err
+	}
+	successTimes, err := impl.getSuccessTimes(runners)
+	if err != nil {
+		return nil, err
+	}
+	// failures open at the start of the range are recovered by deployments inside it
+	openFailures, err := impl.cdWorkflowRepository.FindOpenDeployFailuresForMetrics(request.AppIds, request.EnvironmentId, request.From,
+		successfulDeploymentStatuses, failedDeploymentStatuses)
+	if err != nil {
+		impl.logger.Errorw("error in fetching open deployment failures for dora metrics", "err", err, "request", request)
+		return nil, err
+	}
+	return impl.computeDoraMetrics(request, runners, successTimes, openFailures), nil
+}
+
+// computeDoraMetrics expects runners ordered by start time and openFailures as the first failure of every pipeline
+// failing at the start of the range
+func (impl *DoraMetricsServiceImpl) computeDoraMetrics(request *DoraMetricsRequest, runners []*pipelineConfig.DeployRunnerMetricData,
+	successTimes map[int]time.Time, openFailures []*pipelineConfig.DeployRunnerMetricData) *DoraMetricsResponse {
+	summary := &doraMetricsAccumulator{start: request.From, end: request.To}
+	var weeks []*doraMetricsAccumulator
+	for weekStart := getWeekStart(request.From); weekStart.Before(request.To); weekStart = weekStart.Add(doraWeek) {
+		week := &doraMetricsAccumulator{start: weekStart, end: weekStart.Add(doraWeek)}
+		if week.start.Before(request.From) {
+			week.start = request.From
+		}
+		if week.end.After(request.To) {
+			week.end = request.To
+		}
+		weeks = append(weeks, week)
+	}
+	bucketsOf := func(t time.Time) []*doraMetricsAccumulator {
+		buckets := []*doraMetricsAccumulator{summary}
+		for _, week := range weeks {
+			if !t.Before(week.start) && t.Before(week.end) {
+				buckets = append(buckets, week)
+				break
+			}
+		}
+		return buckets
+	}
+
+	// start of the ongoing failure of every pipeline, cleared by its next successful deployment
+	failureStartByPipeline := make(map[int]time.Time)
+	for _, openFailure := range openFailures {
+		failureStartByPipeline[openFailure.PipelineId] = getFailedOn(openFailure)
+	}
+	for _, runner := range runners {
+		for _, bucket := range bucketsOf(runner.StartedOn) {
+			bucket.total += 1
+		}
+		if isSuccessfulDeployment(runner.Status) {
+			successTime := successTimes[runner.RunnerId]
+			commitTime, ok := impl.getCommitTime(runner)
+			for _, bucket := range bucketsOf(runner.StartedOn) {
+				bucket.successful += 1
+				if ok && successTime.After(commitTime) {
+					bucket.leadTimeSum += successTime.Sub(commitTime)
+					bucket.leadTimeCount += 1
+				}
+			}
+			if failureStart, ok := failureStartByPipeline[runner.PipelineId]; ok {
+				for _, bucket := range bucketsOf(successTime) {
+					if successTime.After(failureStart) {
+						bucket.recoverySum += successTime.Sub(failureStart)
+					}
+					bucket.recoveryCount += 1
+				}
+				delete(failureStartByPipeline, runner.PipelineId)
+			}
+		} else if isFailedDeployment(runner.Status) {
+			for _, bucket := range bucketsOf(runner.StartedOn) {
+				bucket.failed += 1
+			}
+			if _, ok := failureStartByPipeline[runner.PipelineId]; !ok {
+				failureStartByPipeline[runner.PipelineId] = getFailedOn(runner)
+			}
+		}
+	}
+
+	summaryMetrics := summary.toDoraMetrics()
+	response := &DoraMetricsResponse{
+		From:    request.From,
+		To:      request.To,
+		Summary: &summaryMetrics,
+		Weekly:  make([]*DoraWeeklyMetrics, 0, len(weeks)),
+	}
+	for _, week := range weeks {
+		response.Weekly = append(response.Weekly, &DoraWeeklyMetrics{
+			WeekStart:   getWeekStart(week.start),
+			DoraMetrics: week.toDoraMetrics(),
+		})
+	}
+	return response
+}
+
+// getSuccessTimes returns the time each successful deployment turned healthy, taken from the pipeline status
+// timeline (HEALTHY for gitops, RESOURCES_READY for helm) when available and from the runner otherwise
+func (impl *DoraMetricsServiceImpl) getSuccessTimes(runners []*pipelineConfig.DeployRunnerMetricData) (map[int]time.Time, error) {
+	successTimes := make(map[int]time.Time)
+	var successfulRunnerIds []int
+	for _, runner := range runners {
+		if isSuccessfulDeployment(runner.Status) {
+			successfulRunnerIds = append(successfulRunnerIds, runner.RunnerId)
+			successTime := runner.FinishedOn
+			if successTime.IsZero() {
+				successTime = runner.StartedOn
+			}
+			successTimes[runner.RunnerId] = successTime
+		}
+	}
+	for _, healthyStatus := range []pipelineConfig.TimelineStatus{pipelineConfig.TIMELINE_STATUS_APP_HEALTHY, pipelineConfig.TIMELINE_STATUS_RESOURCES_READY} {
+		timelines, err := impl.pipelineStatusTimelineRepo.FetchTimelinesByWfrIdsAndStatus(successfulRunnerIds, healthyStatus)
+		if err != nil {
+			impl.logger.Errorw("error in fetching healthy timelines for dora metrics", "err", err, "status", healthyStatus)
+			return nil, err
+		}
+		for _, timeline := range timelines {
+			successTimes[timeline.CdWorkflowRunnerId] = timeline.StatusTime
+		}
+	}
+	return successTimes, nil
+}
+
+// getCommitTime returns the time of the oldest head commit among the git materials the artifact was built from
+func (impl *DoraMetricsServiceImpl) getCommitTime(runner *pipelineConfig.DeployRunnerMetricData) (time.Time, bool) {
+	var ciMaterials []*repository.CiMaterialInfo
+	err := json.Unmarshal([]byte(runner.MaterialInfo), &ciMaterials)
+	if err != nil {
+		impl.logger.Debugw("unable to parse material info for dora metrics", "err", err, "ciArtifactId", runner.CiArtifactId)
+		return time.Time{}, false
+	}
+	var commitTime time.Time
+	for _, ciMaterial := range ciMaterials {
+		if len(ciMaterial.Modifications) == 0 {
+			continue
+		}
+		modifiedTime, err := time.Parse(bean.LayoutRFC3339, ciMaterial.Modifications[0].ModifiedTime)
+		if err != nil {
+			continue
+		}
+		if commitTime.IsZero() || modifiedTime.Before(commitTime) {
+			commitTime = modifiedTime
+		}
+	}
+	return commitTime, !commitTime.IsZero()
+}
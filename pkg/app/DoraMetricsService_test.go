@@ -0,0 +1,56 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func testMaterialInfo(commitTime time.Time) string {
+	return `[{"modifications":[{"modified-time":"` + commitTime.Format(time.RFC3339) + `"}]}]`
+}
+
+func TestComputeDoraMetrics(t *testing.T) {
+	from := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	request := &DoraMetricsRequest{From: from, To: from.Add(2 * doraWeek)}
+	openFailures := []*pipelineConfig.DeployRunnerMetricData{
+		{RunnerId: 1, PipelineId: 1, Status: deploymentStatusFailed, StartedOn: from.Add(-3 * time.Hour), FinishedOn: from.Add(-2 * time.Hour)},
+	}
+	runners := []*pipelineConfig.DeployRunnerMetricData{
+		{RunnerId: 2, PipelineId: 1, Status: application.Healthy, StartedOn: from.Add(time.Hour), MaterialInfo: testMaterialInfo(from)},
+		{RunnerId: 3, PipelineId: 2, Status: application.Degraded, StartedOn: from.Add(34 * time.Hour), FinishedOn: from.Add(34*time.Hour + 30*time.Minute)},
+		{RunnerId: 4, PipelineId: 2, Status: application.Healthy, StartedOn: from.Add(8*24*time.Hour + 10*time.Hour), FinishedOn: from.Add(8*24*time.Hour + 11*time.Hour + 30*time.Minute),
+			MaterialInfo: testMaterialInfo(from.Add(8*24*time.Hour + 9*time.Hour + 30*time.Minute))},
+	}
+	successTimes := map[int]time.Time{2: from.Add(2 * time.Hour), 4: runners[2].FinishedOn}
+	impl := &DoraMetricsServiceImpl{logger: zap.NewNop().Sugar()}
+
+	response := impl.computeDoraMetrics(request, runners, successTimes, openFailures)
+
+	summary := response.Summary
+	assert.Equal(t, 3, summary.TotalDeployments)
+	assert.Equal(t, 2, summary.SuccessfulDeployments)
+	assert.Equal(t, 1, summary.FailedDeployments)
+	assert.InDelta(t, 1.0, summary.DeploymentFrequencyPerWeek, 0.001)
+	assert.InDelta(t, 120.0, summary.LeadTimeForChangesInMinutes, 0.001)
+	assert.InDelta(t, 100.0/3, summary.ChangeFailureRate, 0.001)
+	// failure open at the start of the range recovered after 4h, failure inside the range after 7d1h
+	assert.Equal(t, 2, summary.Recoveries)
+	assert.InDelta(t, (240.0+10140.0)/2, summary.MeanTimeToRecoveryInMinutes, 0.001)
+
+	assert.Len(t, response.Weekly, 2)
+	assert.Equal(t, 2, response.Weekly[0].TotalDeployments)
+	assert.InDelta(t, 50.0, response.Weekly[0].ChangeFailureRate, 0.001)
+	assert.InDelta(t, 240.0, response.Weekly[0].MeanTimeToRecoveryInMinutes, 0.001)
+	assert.Equal(t, 1, response.Weekly[1].Recoveries)
+	assert.InDelta(t, 10140.0, response.Weekly[1].MeanTimeToRecoveryInMinutes, 0.001)
+}
+
+func TestGetWeekStart(t *testing.T) {
+	assert.Equal(t, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), getWeekStart(time.Date(2023, 1, 8, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2023, 1, 9, 0, 0, 0, 0, time.UTC), getWeekStart(time.Date(2023, 1, 9, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	. "github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type GitOpsPullRequestService interface {
+	// SyncPullRequests checks the pull requests of deployments awaiting merge, deployment of a merged pull request
+	// starts and deployment of a pull request closed without merge fails
+	SyncPullRequests() error
+	// ClosePullRequests closes the pull requests of runners superseded by a newer deployment, runners are already
+	// aborted by the newer deployment
+	ClosePullRequests(runners []*pipelineConfig.CdWorkflowRunner, userId int32)
+}
+
+type GitOpsPullRequestServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	cdWorkflowRepository           pipelineConfig.CdWorkflowRepository
+	pipelineOverrideRepository     chartConfig.PipelineOverrideRepository
+	chartRepository                chartRepoRepository.ChartRepository
+	gitOpsRepository               repository.GitOpsConfigRepository
+	pipelineStatusTimelineService  PipelineStatusTimelineService
+	canaryAnalysisConfigRepository pipelineConfig.CanaryAnalysisConfigRepository
+	canaryAnalysisRunRepository    pipelineConfig.CanaryAnalysisRunRepository
+	gitFactory                     *GitFactory
+	appService                     AppService
+}
+
+func NewGitOpsPullRequestServiceImpl(logger *zap.SugaredLogger, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository, chartRepository chartRepoRepository.ChartRepository,
+	gitOpsRepository repository.GitOpsConfigRepository, pipelineStatusTimelineService PipelineStatusTimelineService,
+	canaryAnalysisConfigRepository pipelineConfig.CanaryAnalysisConfigRepository, canaryAnalysisRunRepository pipelineConfig.CanaryAnalysisRunRepository,
+	gitFactory *GitFactory, appService AppService) *GitOpsPullRequestServiceImpl {
+	return &GitOpsPullRequestServiceImpl{
+		logger:                         logger,
+		cdWorkflowRepository:           cdWorkflowRepository,
+		pipelineOverrideRepository:     pipelineOverrideRepository,
+		chartRepository:                chartRepository,
+		gitOpsRepository:               gitOpsRepository,
+		pipelineStatusTimelineService:  pipelineStatusTimelineService,
+		canaryAnalysisConfigRepository: canaryAnalysisConfigRepository,
+		canaryAnalysisRunRepository:    canaryAnalysisRunRepository,
+		gitFactory:                     gitFactory,
+		appService:                     appService,
+	}
+}
+
+func (impl *GitOpsPullRequestServiceImpl) SyncPullRequests() error {
+	runners, err := impl.cdWorkflowRepository.FindDeployRunnersByStatus(WorkflowAwaitingMerge)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching runners awaiting merge", "err", err)
+		return err
+	}
+	if len(runners) == 0 {
+		return nil
+	}
+	bitbucketWorkspaceId, err := impl.getBitbucketWorkspaceId()
+	if err != nil {
+		return err
+	}
+	for _, runner := range runners {
+		err = impl.syncPullRequest(runner, bitbucketWorkspaceId)
+		if err != nil {
+			impl.logger.Errorw("error in syncing gitops pull request", "wfrId", runner.Id, "pullRequest", runner.GitOpsPullRequestUrl, "err", err)
+		}
+	}
+	return nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) ClosePullRequests(runners []*pipelineConfig.CdWorkflowRunner, userId int32) {
+	if len(runners) == 0 {
+		return
+	}
+	bitbucketWorkspaceId, err := impl.getBitbucketWorkspaceId()
+	if err != nil {
+		return
+	}
+	for _, runner := range runners {
+		if runner.GitOpsPullRequestId == 0 {
+			continue
+		}
+		_, repoName, err := impl.getReleaseAndRepoName(runner)
+		if err != nil {
+			continue
+		}
+		err = impl.gitFactory.Client.ClosePullRequest(repoName, runner.GitOpsPullRequestId, bitbucketWorkspaceId)
+		if err != nil {
+			impl.logger.Errorw("error in closing superseded gitops pull request", "wfrId", runner.Id, "pullRequest", runner.GitOpsPullRequestUrl, "err", err)
+			continue
+		}
+		impl.abortPendingCanaryAnalysis(runner.Id, "pull request closed")
+		impl.saveTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, fmt.Sprintf("Pull request %s closed as a newer deployment was triggered.", runner.GitOpsPullRequestUrl), userId)
+	}
+}
+
+func (impl *GitOpsPullRequestServiceImpl) getBitbucketWorkspaceId() (string, error) {
+	gitOpsConfigBitbucket, err := impl.gitOpsRepository.GetGitOpsConfigByProvider(BITBUCKET_PROVIDER)
+	if err == pg.ErrNoRows {
+		return "", nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching gitOps bitbucket config", "err", err)
+		return "", err
+	}
+	return gitOpsConfigBitbucket.BitBucketWorkspaceId, nil
+}
+
+// getReleaseAndRepoName returns the release of runner and the gitops repo its pull request is opened in
+func (impl *GitOpsPullRequestServiceImpl) getReleaseAndRepoName(runner *pipelineConfig.CdWorkflowRunner) (*chartConfig.PipelineOverride, string, error) {
+	pipelineOverride, err := impl.pipelineOverrideRepository.FindLatestByCdWorkflowId(runner.CdWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching release of cd workflow", "cdWorkflowId", runner.CdWorkflowId, "err", err)
+		return nil, "", err
+	}
+	chart, err := impl.chartRepository.FindLatestChartForAppByAppId(pipelineOverride.Pipeline.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching latest chart", "appId", pipelineOverride.Pipeline.AppId, "err", err)
+		return nil, "", err
+	}
+	return pipelineOverride, impl.appService.GetChartRepoName(chart.GitRepoUrl), nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) syncPullRequest(runner *pipelineConfig.CdWorkflowRunner, bitbucketWorkspaceId string) error {
+	pipelineOverride, repoName, err := impl.getReleaseAndRepoName(runner)
+	if err != nil {
+		return err
+	}
+	pullRequest, err := impl.gitFactory.Client.GetPullRequest(repoName, runner.GitOpsPullRequestId, bitbucketWorkspaceId)
+	if err != nil {
+		return err
+	}
+	switch pullRequest.State {
+	case PULL_REQUEST_STATE_MERGED:
+		return impl.deployMergedPullRequest(runner, pipelineOverride, pullRequest)
+	case PULL_REQUEST_STATE_CLOSED:
+		runner.Status = WorkflowFailed
+		runner.Message = fmt.Sprintf("pull request %s closed without merge", pullRequest.Url)
+		runner.FinishedOn = time.Now()
+		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+		if err != nil {
+			impl.logger.Errorw("error in updating cd workflow runner", "wfrId", runner.Id, "err", err)
+			return err
+		}
+		impl.abortPendingCanaryAnalysis(runner.Id, "pull request closed without merge")
+		impl.saveTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED, "Pull request closed without merge.", runner.TriggeredBy)
+	}
+	return nil
+}
+
+// deployMergedPullRequest records the merge commit as git hash of release, argo app status is mapped to the release
+// by the synced revision, and updates the argo app to the chart of release
+func (impl *GitOpsPullRequestServiceImpl) deployMergedPullRequest(runner *pipelineConfig.CdWorkflowRunner, pipelineOverride *chartConfig.PipelineOverride, pullRequest *PullRequest) error {
+	err := impl.pipelineOverrideRepository.Update(&chartConfig.PipelineOverride{
+		Id:       pipelineOverride.Id,
+		GitHash:  pullRequest.MergeCommitHash,
+		AuditLog: sql.AuditLog{UpdatedOn: time.Now(), UpdatedBy: runner.TriggeredBy},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in updating git hash of release", "pipelineOverrideId", pipelineOverride.Id, "err", err)
+		return err
+	}
+	runner.Status = WorkflowInProgress
+	runner.Message = ""
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating cd workflow runner", "wfrId", runner.Id, "err", err)
+		return err
+	}
+	impl.saveTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_PULL_REQUEST_MERGED, fmt.Sprintf("Pull request merged with commit %s.", pullRequest.MergeCommitHash), runner.TriggeredBy)
+	err = impl.appService.UpdateArgoAppForRelease(pipelineOverride)
+	if err != nil {
+		runner.Status = WorkflowFailed
+		runner.Message = err.Error()
+		runner.FinishedOn = time.Now()
+		if updateErr := impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner); updateErr != nil {
+			impl.logger.Errorw("error in updating cd workflow runner", "wfrId", runner.Id, "err", updateErr)
+		}
+		return err
+	}
+	impl.startPendingCanaryAnalysis(runner)
+	return nil
+}
+
+// startPendingCanaryAnalysis starts the canary analysis of runner held until merge of its pull request
+func (impl *GitOpsPullRequestServiceImpl) startPendingCanaryAnalysis(runner *pipelineConfig.CdWorkflowRunner) {
+	run, err := impl.canaryAnalysisRunRepository.FindByCdWorkflowRunnerId(runner.Id)
+	if err == pg.ErrNoRows || (err == nil && run.Status != pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING) {
+		return
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching canary analysis run", "wfrId", runner.Id, "err", err)
+		return
+	}
+	config, err := impl.canaryAnalysisConfigRepository.FindConfigById(run.CanaryAnalysisConfigId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching canary analysis config", "runId", run.Id, "err", err)
+		return
+	}
+	run.Status = pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING
+	run.NextEvaluationOn = time.Now().Add(time.Duration(config.InitialDelayInSeconds+config.IntervalInSeconds) * time.Second)
+	run.UpdatedOn = time.Now()
+	run.UpdatedBy = runner.TriggeredBy
+	err = impl.canaryAnalysisRunRepository.Update(run)
+	if err != nil {
+		impl.logger.Errorw("error in starting canary analysis run", "runId", run.Id, "err", err)
+		return
+	}
+	detail := fmt.Sprintf("Canary analysis started, %d successful step(s) required at an interval of %ds.", config.Iterations, config.IntervalInSeconds)
+	impl.saveTimeline(runner.Id, pipelineConfig.TIMELINE_STATUS_CANARY_STARTED, detail, runner.TriggeredBy)
+}
+
+func (impl *GitOpsPullRequestServiceImpl) abortPendingCanaryAnalysis(wfrId int, message string) {
+	run, err := impl.canaryAnalysisRunRepository.FindByCdWorkflowRunnerId(wfrId)
+	if err != nil || run.Status != pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING {
+		return
+	}
+	run.Status = pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_ABORTED
+	run.Message = message
+	run.FinishedOn = time.Now()
+	run.UpdatedOn = time.Now()
+	err = impl.canaryAnalysisRunRepository.Update(run)
+	if err != nil {
+		impl.logger.Errorw("error in aborting pending canary analysis run", "runId", run.Id, "err", err)
+	}
+}
+
+func (impl *GitOpsPullRequestServiceImpl) saveTimeline(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) {
+	err := impl.pipelineStatusTimelineService.SaveTimeline(wfrId, status, statusDetail, userId)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeline status for pull request", "wfrId", wfrId, "status", status, "err", err)
+	}
+}
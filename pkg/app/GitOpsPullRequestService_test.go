@@ -0,0 +1,221 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	. "github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakePullRequestGitClient struct {
+	GitClient
+	pullRequest *PullRequest
+	closedPrIds []int
+	closedRepos []string
+}
+
+func (f *fakePullRequestGitClient) GetPullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) (*PullRequest, error) {
+	return f.pullRequest, nil
+}
+
+func (f *fakePullRequestGitClient) ClosePullRequest(repoName string, pullRequestId int, bitbucketWorkspaceId string) error {
+	f.closedRepos = append(f.closedRepos, repoName)
+	f.closedPrIds = append(f.closedPrIds, pullRequestId)
+	return nil
+}
+
+type fakePullRequestCdWorkflowRepository struct {
+	pipelineConfig.CdWorkflowRepository
+	runners []*pipelineConfig.CdWorkflowRunner
+	updated []pipelineConfig.CdWorkflowRunner
+}
+
+func (f *fakePullRequestCdWorkflowRepository) FindDeployRunnersByStatus(status string) ([]*pipelineConfig.CdWorkflowRunner, error) {
+	return f.runners, nil
+}
+
+func (f *fakePullRequestCdWorkflowRepository) UpdateWorkFlowRunner(wfr *pipelineConfig.CdWorkflowRunner) error {
+	f.updated = append(f.updated, *wfr)
+	return nil
+}
+
+type fakePullRequestPipelineOverrideRepository struct {
+	chartConfig.PipelineOverrideRepository
+	updated []*chartConfig.PipelineOverride
+}
+
+func (f *fakePullRequestPipelineOverrideRepository) FindLatestByCdWorkflowId(cdWorkflowId int) (*chartConfig.PipelineOverride, error) {
+	return &chartConfig.PipelineOverride{Id: 10, Pipeline: &pipelineConfig.Pipeline{Id: 1, AppId: 2}}, nil
+}
+
+func (f *fakePullRequestPipelineOverrideRepository) Update(pipelineOverride *chartConfig.PipelineOverride) error {
+	f.updated = append(f.updated, pipelineOverride)
+	return nil
+}
+
+type fakePullRequestChartRepository struct {
+	chartRepoRepository.ChartRepository
+}
+
+func (f *fakePullRequestChartRepository) FindLatestChartForAppByAppId(appId int) (*chartRepoRepository.Chart, error) {
+	return &chartRepoRepository.Chart{AppId: appId, GitRepoUrl: "https://github.com/devtron/app-one.git"}, nil
+}
+
+type fakePullRequestGitOpsConfigRepository struct {
+	repository.GitOpsConfigRepository
+}
+
+func (f *fakePullRequestGitOpsConfigRepository) GetGitOpsConfigByProvider(provider string) (*repository.GitOpsConfig, error) {
+	return nil, pg.ErrNoRows
+}
+
+type fakePullRequestTimelineService struct {
+	PipelineStatusTimelineService
+	statuses []pipelineConfig.TimelineStatus
+	userIds  []int32
+}
+
+func (f *fakePullRequestTimelineService) SaveTimeline(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) error {
+	f.statuses = append(f.statuses, status)
+	f.userIds = append(f.userIds, userId)
+	return nil
+}
+
+type fakePullRequestCanaryConfigRepository struct {
+	pipelineConfig.CanaryAnalysisConfigRepository
+}
+
+func (f *fakePullRequestCanaryConfigRepository) FindConfigById(id int) (*pipelineConfig.CanaryAnalysisConfig, error) {
+	return &pipelineConfig.CanaryAnalysisConfig{Id: id, InitialDelayInSeconds: 60, IntervalInSeconds: 30, Iterations: 3}, nil
+}
+
+type fakePullRequestCanaryRunRepository struct {
+	pipelineConfig.CanaryAnalysisRunRepository
+	run *pipelineConfig.CanaryAnalysisRun
+}
+
+func (f *fakePullRequestCanaryRunRepository) FindByCdWorkflowRunnerId(wfrId int) (*pipelineConfig.CanaryAnalysisRun, error) {
+	if f.run == nil {
+		return nil, pg.ErrNoRows
+	}
+	return f.run, nil
+}
+
+func (f *fakePullRequestCanaryRunRepository) Update(run *pipelineConfig.CanaryAnalysisRun) error {
+	f.run = run
+	return nil
+}
+
+type fakePullRequestAppService struct {
+	AppService
+	updatedReleases []*chartConfig.PipelineOverride
+}
+
+func (f *fakePullRequestAppService) GetChartRepoName(gitRepoUrl string) string {
+	return "app-one"
+}
+
+func (f *fakePullRequestAppService) UpdateArgoAppForRelease(pipelineOverride *chartConfig.PipelineOverride) error {
+	f.updatedReleases = append(f.updatedReleases, pipelineOverride)
+	return nil
+}
+
+type gitOpsPullRequestServiceFakes struct {
+	gitClient            *fakePullRequestGitClient
+	cdWorkflowRepo       *fakePullRequestCdWorkflowRepository
+	pipelineOverrideRepo *fakePullRequestPipelineOverrideRepository
+	timelineService      *fakePullRequestTimelineService
+	canaryRunRepo        *fakePullRequestCanaryRunRepository
+	appService           *fakePullRequestAppService
+}
+
+func getTestGitOpsPullRequestService(runners []*pipelineConfig.CdWorkflowRunner, pullRequest *PullRequest, canaryRun *pipelineConfig.CanaryAnalysisRun) (*GitOpsPullRequestServiceImpl, *gitOpsPullRequestServiceFakes) {
+	fakes := &gitOpsPullRequestServiceFakes{
+		gitClient:            &fakePullRequestGitClient{pullRequest: pullRequest},
+		cdWorkflowRepo:       &fakePullRequestCdWorkflowRepository{runners: runners},
+		pipelineOverrideRepo: &fakePullRequestPipelineOverrideRepository{},
+		timelineService:      &fakePullRequestTimelineService{},
+		canaryRunRepo:        &fakePullRequestCanaryRunRepository{run: canaryRun},
+		appService:           &fakePullRequestAppService{},
+	}
+	impl := NewGitOpsPullRequestServiceImpl(zap.NewNop().Sugar(), fakes.cdWorkflowRepo, fakes.pipelineOverrideRepo,
+		&fakePullRequestChartRepository{}, &fakePullRequestGitOpsConfigRepository{}, fakes.timelineService,
+		&fakePullRequestCanaryConfigRepository{}, fakes.canaryRunRepo, &GitFactory{Client: fakes.gitClient}, fakes.appService)
+	return impl, fakes
+}
+
+func testAwaitingMergeRunner() *pipelineConfig.CdWorkflowRunner {
+	return &pipelineConfig.CdWorkflowRunner{Id: 5, CdWorkflowId: 4, Status: WorkflowAwaitingMerge, TriggeredBy: 7,
+		GitOpsPullRequestId: 3, GitOpsPullRequestUrl: "https://github.com/devtron/app-one/pull/3"}
+}
+
+func TestGitOpsPullRequestService_SyncMergedPullRequest(t *testing.T) {
+	canaryRun := &pipelineConfig.CanaryAnalysisRun{Id: 1, CanaryAnalysisConfigId: 2, Status: pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING}
+	impl, fakes := getTestGitOpsPullRequestService([]*pipelineConfig.CdWorkflowRunner{testAwaitingMergeRunner()},
+		&PullRequest{Id: 3, State: PULL_REQUEST_STATE_MERGED, MergeCommitHash: "merge-sha"}, canaryRun)
+
+	before := time.Now()
+	err := impl.SyncPullRequests()
+	assert.Nil(t, err)
+
+	assert.Len(t, fakes.pipelineOverrideRepo.updated, 1)
+	assert.Equal(t, "merge-sha", fakes.pipelineOverrideRepo.updated[0].GitHash)
+	assert.Equal(t, int32(7), fakes.pipelineOverrideRepo.updated[0].UpdatedBy)
+	assert.Len(t, fakes.cdWorkflowRepo.updated, 1)
+	assert.Equal(t, WorkflowInProgress, fakes.cdWorkflowRepo.updated[0].Status)
+	assert.Len(t, fakes.appService.updatedReleases, 1)
+
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING, fakes.canaryRunRepo.run.Status)
+	assert.True(t, !fakes.canaryRunRepo.run.NextEvaluationOn.Before(before.Add(90*time.Second)))
+	assert.Equal(t, []pipelineConfig.TimelineStatus{pipelineConfig.TIMELINE_STATUS_PULL_REQUEST_MERGED, pipelineConfig.TIMELINE_STATUS_CANARY_STARTED}, fakes.timelineService.statuses)
+	assert.Equal(t, []int32{7, 7}, fakes.timelineService.userIds)
+}
+
+func TestGitOpsPullRequestService_SyncOpenPullRequest(t *testing.T) {
+	canaryRun := &pipelineConfig.CanaryAnalysisRun{Id: 1, CanaryAnalysisConfigId: 2, Status: pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING}
+	impl, fakes := getTestGitOpsPullRequestService([]*pipelineConfig.CdWorkflowRunner{testAwaitingMergeRunner()},
+		&PullRequest{Id: 3, State: PULL_REQUEST_STATE_OPEN}, canaryRun)
+
+	err := impl.SyncPullRequests()
+	assert.Nil(t, err)
+	assert.Empty(t, fakes.pipelineOverrideRepo.updated)
+	assert.Empty(t, fakes.cdWorkflowRepo.updated)
+	assert.Empty(t, fakes.timelineService.statuses)
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING, fakes.canaryRunRepo.run.Status)
+}
+
+func TestGitOpsPullRequestService_SyncClosedPullRequest(t *testing.T) {
+	canaryRun := &pipelineConfig.CanaryAnalysisRun{Id: 1, CanaryAnalysisConfigId: 2, Status: pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING}
+	impl, fakes := getTestGitOpsPullRequestService([]*pipelineConfig.CdWorkflowRunner{testAwaitingMergeRunner()},
+		&PullRequest{Id: 3, State: PULL_REQUEST_STATE_CLOSED, Url: "https://github.com/devtron/app-one/pull/3"}, canaryRun)
+
+	err := impl.SyncPullRequests()
+	assert.Nil(t, err)
+	assert.Empty(t, fakes.pipelineOverrideRepo.updated)
+	assert.Len(t, fakes.cdWorkflowRepo.updated, 1)
+	assert.Equal(t, WorkflowFailed, fakes.cdWorkflowRepo.updated[0].Status)
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_ABORTED, fakes.canaryRunRepo.run.Status)
+	assert.Equal(t, []pipelineConfig.TimelineStatus{pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED}, fakes.timelineService.statuses)
+	assert.Equal(t, []int32{7}, fakes.timelineService.userIds)
+}
+
+func TestGitOpsPullRequestService_ClosePullRequests(t *testing.T) {
+	canaryRun := &pipelineConfig.CanaryAnalysisRun{Id: 1, CanaryAnalysisConfigId: 2, Status: pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING}
+	impl, fakes := getTestGitOpsPullRequestService(nil, nil, canaryRun)
+	withoutPullRequest := &pipelineConfig.CdWorkflowRunner{Id: 6, CdWorkflowId: 4, Status: WorkflowAwaitingMerge}
+
+	impl.ClosePullRequests([]*pipelineConfig.CdWorkflowRunner{testAwaitingMergeRunner(), withoutPullRequest}, 9)
+
+	assert.Equal(t, []int{3}, fakes.gitClient.closedPrIds)
+	assert.Equal(t, []string{"app-one"}, fakes.gitClient.closedRepos)
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_ABORTED, fakes.canaryRunRepo.run.Status)
+	assert.Equal(t, []pipelineConfig.TimelineStatus{pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_FAILED}, fakes.timelineService.statuses)
+	assert.Equal(t, []int32{9}, fakes.timelineService.userIds)
+}
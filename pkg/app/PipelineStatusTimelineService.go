@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type PipelineTimelineDetailDto struct {
+	PipelineId           int                          `json:"pipelineId"`
+	WfrId                int                          `json:"wfrId"`
+	DeploymentAppType    string                       `json:"deploymentAppType"`
+	WfrStatus            string                       `json:"wfrStatus"`
+	DeploymentStartedOn  time.Time                    `json:"deploymentStartedOn"`
+	DeploymentFinishedOn time.Time                    `json:"deploymentFinishedOn"`
+	TriggeredBy          int32                        `json:"triggeredBy"`
+	Timelines            []*PipelineStatusTimelineDto `json:"timelines"`
+}
+
+type PipelineStatusTimelineDto struct {
+	Id           int                           `json:"id"`
+	Status       pipelineConfig.TimelineStatus `json:"status"`
+	StatusDetail string                        `json:"statusDetail"`
+	StatusTime   time.Time                     `json:"statusTime"`
+}
+
+type SyncResourceDto struct {
+	Group         string `json:"group"`
+	Version       string `json:"version"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	SyncStatus    string `json:"syncStatus"`
+	SyncPhase     string `json:"syncPhase"`
+	HealthStatus  string `json:"healthStatus"`
+	StatusMessage string `json:"statusMessage"`
+}
+
+type PipelineStatusTimelineService interface {
+	// FetchTimelines returns the status timeline of a deployment for both gitops and helm based pipelines, if wfrId is
+	// not given timeline of the latest deployment of the app in the environment is returned
+	FetchTimelines(appId, envId, wfrId int) (*PipelineTimelineDetailDto, error)
+	// FetchFailedResources returns resources of a gitops deployment which failed to sync or are not healthy, if wfrId
+	// is not given resources of the latest deployment of the app in the environment are returned
+	FetchFailedResources(appId, envId, wfrId int) ([]*SyncResourceDto, error)
+	// SaveTimeline records a step of the workflow runner, i.e. of a helm deployment, a canary analysis or a pre/post
+	// stage, nothing is recorded for wfrId 0
+	SaveTimeline(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) error
+	// SaveTimelineIfNotPresent is SaveTimeline for steps observed repeatedly, i.e. by status polling
+	SaveTimelineIfNotPresent(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) error
+}
+
+type PipelineStatusTimelineServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	pipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository
+	cdWorkflowRepository       pipelineConfig.CdWorkflowRepository
+	pipelineRepository         pipelineConfig.PipelineRepository
+	timelineResourcesRepo      pipelineConfig.PipelineStatusTimelineResourcesRepository
+}
+
+func NewPipelineStatusTimelineServiceImpl(logger *zap.SugaredLogger,
+	pipelineStatusTimelineRepo pipelineConfig.PipelineStatusTimelineRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	timelineResourcesRepo pipelineConfig.PipelineStatusTimelineResourcesRepository) *PipelineStatusTimelineServiceImpl {
+	return &PipelineStatusTimelineServiceImpl{
+		logger:                     logger,
+		pipelineStatusTimelineRepo: pipelineStatusTimelineRepo,
+		cdWorkflowRepository:       cdWorkflowRepository,
+		pipelineRepository:         pipelineRepository,
+		timelineResourcesRepo:      timelineResourcesRepo,
+	}
+}
+
+// getDeploymentRunner returns the deploy runner of given id after verifying it belongs to the app and environment, or
+// the latest deploy runner of the app in the environment if wfrId is 0
+func (impl *PipelineStatusTimelineServiceImpl) getDeploymentRunner(appId, envId, wfrId int) (*pipelineConfig.CdWorkflowRunner, error) {
+	var wfr *pipelineConfig.CdWorkflowRunner
+	if wfrId == 0 {
+		pipelines, err := impl.pipelineRepository.FindActiveByAppIdAndEnvironmentId(appId, envId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching pipeline", "err", err, "appId", appId, "envId", envId)
+			return nil, err
+		}
+		if len(pipelines) == 0 {
+			return nil, pg.ErrNoRows
+		}
+		latestWfr, err := impl.cdWorkflowRepository.FindLastStatusByPipelineIdAndRunnerType(pipelines[0].Id, bean.CD_WORKFLOW_TYPE_DEPLOY)
+		if err != nil {
+			impl.logger.Errorw("error in fetching latest deployment", "err", err, "pipelineId", pipelines[0].Id)
+			return nil, err
+		}
+		wfr = &latestWfr
+	} else {
+		var err error
+		wfr, err = impl.cdWorkflowRepository.FindWorkflowRunnerById(wfrId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching deployment", "err", err, "wfrId", wfrId)
+			return nil, err
+		}
+		if wfr.CdWorkflow.Pipeline == nil || wfr.CdWorkflow.Pipeline.AppId != appId || wfr.CdWorkflow.Pipeline.EnvironmentId != envId {
+			return nil, pg.ErrNoRows
+		}
+	}
+	return wfr, nil
+}
+
+func (impl *PipelineStatusTimelineServiceImpl) FetchTimelines(appId, envId, wfrId int) (*PipelineTimelineDetailDto, error) {
+	wfr, err := impl.getDeploymentRunner(appId, envId, wfrId)
+	if err != nil {
+		return nil, err
+	}
+	timelines, err := impl.pipelineStatusTimelineRepo.FetchTimelinesByWfrId(wfr.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching timelines", "err", err, "wfrId", wfr.Id)
+		return nil, err
+	}
+	timelineDtos := make([]*PipelineStatusTimelineDto, 0, len(timelines)+2)
+	for _, timeline := range timelines {
+		timelineDtos = append(timelineDtos, &PipelineStatusTimelineDto{
+			Id:           timeline.Id,
+			Status:       timeline.Status,
+			StatusDetail: timeline.StatusDetail,
+			StatusTime:   timeline.StatusTime,
+		})
+	}
+	// pre and post deployment stages run as their own workflow runners of the same cd workflow, their timelines are
+	// merged into the timeline of the deployment
+	for _, runnerType := range []bean.WorkflowType{bean.CD_WORKFLOW_TYPE_PRE, bean.CD_WORKFLOW_TYPE_POST} {
+		stageWfr, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(wfr.CdWorkflowId, runnerType)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching stage of deployment", "err", err, "cdWorkflowId", wfr.CdWorkflowId, "runnerType", runnerType)
+			return nil, err
+		}
+		if stageWfr.Id == 0 {
+			continue
+		}
+		stageTimelines, err := impl.pipelineStatusTimelineRepo.FetchTimelinesByWfrId(stageWfr.Id)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching timelines of stage", "err", err, "wfrId", stageWfr.Id)
+			return nil, err
+		}
+		for _, timeline := range stageTimelines {
+			timelineDtos = append(timelineDtos, &PipelineStatusTimelineDto{
+				Id:           timeline.Id,
+				Status:       timeline.Status,
+				StatusDetail: timeline.StatusDetail,
+				StatusTime:   timeline.StatusTime,
+			})
+		}
+	}
+	sort.SliceStable(timelineDtos, func(i, j int) bool {
+		return timelineDtos[i].StatusTime.Before(timelineDtos[j].StatusTime)
+	})
+
+	detail := &PipelineTimelineDetailDto{
+		WfrId:                wfr.Id,
+		WfrStatus:            wfr.Status,
+		DeploymentStartedOn:  wfr.StartedOn,
+		DeploymentFinishedOn: wfr.FinishedOn,
+		TriggeredBy:          wfr.TriggeredBy,
+		Timelines:            timelineDtos,
+	}
+	if wfr.CdWorkflow != nil && wfr.CdWorkflow.Pipeline != nil {
+		detail.PipelineId = wfr.CdWorkflow.Pipeline.Id
+		detail.DeploymentAppType = wfr.CdWorkflow.Pipeline.DeploymentAppType
+	}
+	return detail, nil
+}
+
+func (impl *PipelineStatusTimelineServiceImpl) FetchFailedResources(appId, envId, wfrId int) ([]*SyncResourceDto, error) {
+	wfr, err := impl.getDeploymentRunner(appId, envId, wfrId)
+	if err != nil {
+		return nil, err
+	}
+	timelineResources, err := impl.timelineResourcesRepo.FetchFailedTimelineResourcesByWfrId(wfr.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching failed resources of deployment", "err", err, "wfrId", wfr.Id)
+		return nil, err
+	}
+	resources := make([]*SyncResourceDto, 0, len(timelineResources))
+	for _, timelineResource := range timelineResources {
+		resources = append(resources, &SyncResourceDto{
+			Group:         timelineResource.ResourceGroup,
+			Version:       timelineResource.ResourceVersion,
+			Kind:          timelineResource.ResourceKind,
+			Name:          timelineResource.ResourceName,
+			Namespace:     timelineResource.ResourceNamespace,
+			SyncStatus:    timelineResource.SyncStatus,
+			SyncPhase:     timelineResource.SyncPhase,
+			HealthStatus:  timelineResource.HealthStatus,
+			StatusMessage: timelineResource.StatusMessage,
+		})
+	}
+	return resources, nil
+}
+
+func (impl *PipelineStatusTimelineServiceImpl) SaveTimeline(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) error {
+	if wfrId == 0 {
+		return nil
+	}
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: wfrId,
+		Status:             status,
+		StatusDetail:       statusDetail,
+		StatusTime:         time.Now(),
+		AuditLog: sql.AuditLog{
+			CreatedBy: userId,
+			CreatedOn: time.Now(),
+			UpdatedBy: userId,
+			UpdatedOn: time.Now(),
+		},
+	}
+	err := impl.pipelineStatusTimelineRepo.SaveTimeline(timeline)
+	if err != nil {
+		impl.logger.Errorw("error in saving timeline", "err", err, "timeline", timeline)
+		return err
+	}
+	return nil
+}
+
+func (impl *PipelineStatusTimelineServiceImpl) SaveTimelineIfNotPresent(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) error {
+	exists, err := impl.pipelineStatusTimelineRepo.CheckTimelineExistsByWfrIdAndStatus(wfrId, status)
+	if err != nil {
+		impl.logger.Errorw("error in checking timeline", "err", err, "wfrId", wfrId, "status", status)
+		return err
+	} else if exists {
+		return nil
+	}
+	return impl.SaveTimeline(wfrId, status, statusDetail, userId)
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeTimelineRepository struct {
+	pipelineConfig.PipelineStatusTimelineRepository
+	timelines []*pipelineConfig.PipelineStatusTimeline
+}
+
+func (f *fakeTimelineRepository) SaveTimeline(timeline *pipelineConfig.PipelineStatusTimeline) error {
+	timeline.Id = len(f.timelines) + 1
+	f.timelines = append(f.timelines, timeline)
+	return nil
+}
+
+func (f *fakeTimelineRepository) FetchTimelinesByWfrId(wfrId int) ([]*pipelineConfig.PipelineStatusTimeline, error) {
+	var timelines []*pipelineConfig.PipelineStatusTimeline
+	for _, timeline := range f.timelines {
+		if timeline.CdWorkflowRunnerId == wfrId {
+			timelines = append(timelines, timeline)
+		}
+	}
+	return timelines, nil
+}
+
+func (f *fakeTimelineRepository) CheckTimelineExistsByWfrIdAndStatus(wfrId int, status pipelineConfig.TimelineStatus) (bool, error) {
+	for _, timeline := range f.timelines {
+		if timeline.CdWorkflowRunnerId == wfrId && timeline.Status == status {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeTimelineCdWorkflowRepository struct {
+	pipelineConfig.CdWorkflowRepository
+	runners []*pipelineConfig.CdWorkflowRunner
+}
+
+func (f *fakeTimelineCdWorkflowRepository) FindWorkflowRunnerById(wfrId int) (*pipelineConfig.CdWorkflowRunner, error) {
+	for _, runner := range f.runners {
+		if runner.Id == wfrId {
+			return runner, nil
+		}
+	}
+	return nil, pg.ErrNoRows
+}
+
+func (f *fakeTimelineCdWorkflowRepository) FindByWorkflowIdAndRunnerType(wfId int, runnerType bean.WorkflowType) (pipelineConfig.CdWorkflowRunner, error) {
+	for _, runner := range f.runners {
+		if runner.CdWorkflowId == wfId && runner.WorkflowType == runnerType {
+			return *runner, nil
+		}
+	}
+	return pipelineConfig.CdWorkflowRunner{}, pg.ErrNoRows
+}
+
+func getTestPipelineStatusTimelineService() (*PipelineStatusTimelineServiceImpl, *fakeTimelineRepository) {
+	cdPipeline := &pipelineConfig.Pipeline{Id: 1, AppId: 2, EnvironmentId: 3, DeploymentAppType: "helm"}
+	cdWorkflow := &pipelineConfig.CdWorkflow{Id: 10, Pipeline: cdPipeline}
+	cdWorkflowRepository := &fakeTimelineCdWorkflowRepository{runners: []*pipelineConfig.CdWorkflowRunner{
+		{Id: 100, CdWorkflowId: 10, WorkflowType: bean.CD_WORKFLOW_TYPE_PRE, CdWorkflow: cdWorkflow},
+		{Id: 101, CdWorkflowId: 10, WorkflowType: bean.CD_WORKFLOW_TYPE_DEPLOY, CdWorkflow: cdWorkflow},
+	}}
+	timelineRepository := &fakeTimelineRepository{}
+	impl := NewPipelineStatusTimelineServiceImpl(zap.NewNop().Sugar(), timelineRepository, cdWorkflowRepository, nil, nil)
+	return impl, timelineRepository
+}
+
+func TestPipelineStatusTimelineService_SaveTimelineIfNotPresent(t *testing.T) {
+	impl, timelineRepository := getTestPipelineStatusTimelineService()
+
+	assert.Nil(t, impl.SaveTimelineIfNotPresent(101, pipelineConfig.TIMELINE_STATUS_RESOURCES_READY, "All resources are ready.", 1))
+	assert.Nil(t, impl.SaveTimelineIfNotPresent(101, pipelineConfig.TIMELINE_STATUS_RESOURCES_READY, "All resources are ready.", 1))
+	assert.Nil(t, impl.SaveTimelineIfNotPresent(101, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_TIMED_OUT, "Resources were not ready.", 1))
+	assert.Len(t, timelineRepository.timelines, 2)
+
+	// runner is not known for deployments triggered before timelines were recorded
+	assert.Nil(t, impl.SaveTimeline(0, pipelineConfig.TIMELINE_STATUS_HELM_UPGRADE_STARTED, "Helm upgrade started.", 1))
+	assert.Len(t, timelineRepository.timelines, 2)
+}
+
+func TestPipelineStatusTimelineService_FetchTimelines(t *testing.T) {
+	impl, timelineRepository := getTestPipelineStatusTimelineService()
+	start := time.Now()
+	timelineRepository.timelines = []*pipelineConfig.PipelineStatusTimeline{
+		{Id: 1, CdWorkflowRunnerId: 101, Status: pipelineConfig.TIMELINE_STATUS_HELM_UPGRADE_STARTED, StatusTime: start.Add(2 * time.Minute)},
+		{Id: 2, CdWorkflowRunnerId: 101, Status: pipelineConfig.TIMELINE_STATUS_HELM_UPGRADE_FINISHED, StatusTime: start.Add(3 * time.Minute)},
+		{Id: 3, CdWorkflowRunnerId: 100, Status: pipelineConfig.TIMELINE_STATUS_PRE_DEPLOY, StatusTime: start.Add(time.Minute)},
+	}
+
+	detail, err := impl.FetchTimelines(2, 3, 101)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, detail.PipelineId)
+	assert.Equal(t, "helm", detail.DeploymentAppType)
+	var statuses []pipelineConfig.TimelineStatus
+	for _, timeline := range detail.Timelines {
+		statuses = append(statuses, timeline.Status)
+	}
+	assert.Equal(t, []pipelineConfig.TimelineStatus{pipelineConfig.TIMELINE_STATUS_PRE_DEPLOY,
+		pipelineConfig.TIMELINE_STATUS_HELM_UPGRADE_STARTED, pipelineConfig.TIMELINE_STATUS_HELM_UPGRADE_FINISHED}, statuses)
+
+	// runner of another environment is not returned
+	_, err = impl.FetchTimelines(2, 4, 101)
+	assert.Equal(t, pg.ErrNoRows, err)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	cluster2 "github.com/devtron-labs/devtron/pkg/cluster"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/imageSignature"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	util2 "github.com/devtron-labs/devtron/util"
 	"github.com/go-pg/pg"
@@ -62,6 +63,9 @@ type AppStoreDeploymentService interface {
 	UpdateInstalledApp(ctx context.Context, installAppVersionRequest *appStoreBean.InstallAppVersionDTO) (*appStoreBean.InstallAppVersionDTO, error)
 	GetInstalledAppVersion(id int, userId int32) (*appStoreBean.InstallAppVersionDTO, error)
 	InstallAppByHelm(installAppVersionRequest *appStoreBean.InstallAppVersionDTO, ctx context.Context) (*appStoreBean.InstallAppVersionDTO, error)
+	// VerifyChartImageSignatures checks the images of the chart version and values to be deployed against the image
+	// signature policies of the app and environment, an error is returned when a policy blocks the deployment
+	VerifyChartImageSignatures(installAppVersionRequest *appStoreBean.InstallAppVersionDTO) error
 }
 
 type AppStoreDeploymentServiceImpl struct {
@@ -80,6 +84,7 @@ type AppStoreDeploymentServiceImpl struct {
 	globalEnvVariables                   *util2.GlobalEnvVariables
 	installedAppRepositoryHistory        repository.InstalledAppVersionHistoryRepository
 	gitOpsRepository                     repository2.GitOpsConfigRepository
+	imageSignatureService                imageSignature.ImageSignatureService
 }
 
 func NewAppStoreDeploymentServiceImpl(logger *zap.SugaredLogger, installedAppRepository repository.InstalledAppRepository,
@@ -89,7 +94,8 @@ func NewAppStoreDeploymentServiceImpl(logger *zap.SugaredLogger, installedAppRep
 	appStoreDeploymentArgoCdService appStoreDeploymentGitopsTool.AppStoreDeploymentArgoCdService, environmentService cluster.EnvironmentService,
 	clusterService cluster.ClusterService, helmAppService client.HelmAppService, appStoreDeploymentCommonService appStoreDeploymentCommon.AppStoreDeploymentCommonService,
 	globalEnvVariables *util2.GlobalEnvVariables,
-	installedAppRepositoryHistory repository.InstalledAppVersionHistoryRepository, gitOpsRepository repository2.GitOpsConfigRepository) *AppStoreDeploymentServiceImpl {
+	installedAppRepositoryHistory repository.InstalledAppVersionHistoryRepository, gitOpsRepository repository2.GitOpsConfigRepository,
+	imageSignatureService imageSignature.ImageSignatureService) *AppStoreDeploymentServiceImpl {
 	return &AppStoreDeploymentServiceImpl{
 		logger:                               logger,
 		installedAppRepository:               installedAppRepository,
@@ -106,6 +112,7 @@ func NewAppStoreDeploymentServiceImpl(logger *zap.SugaredLogger, installedAppRep
 		globalEnvVariables:                   globalEnvVariables,
 		installedAppRepositoryHistory:        installedAppRepositoryHistory,
 		gitOpsRepository:                     gitOpsRepository,
+		imageSignatureService:                imageSignatureService,
 	}
 }
 
@@ -167,7 +174,9 @@ func (impl AppStoreDeploymentServiceImpl) AppStoreDeployOperationDB(installAppVe
 		EnvironmentId: environment.Id,
 		Status:        appStoreBean.DEPLOY_INIT,
 	}
-	if isGitOpsConfigured && appInstallationMode == util2.SERVER_MODE_FULL {
+	// charts of oci registries are installed through helm as argocd reads charts of index.yaml repos only
+	isOCIChart := appStoreAppVersion.AppStore != nil && appStoreAppVersion.AppStore.ChartRepo != nil && appStoreAppVersion.AppStore.ChartRepo.IsOCIRegistry
+	if isGitOpsConfigured && appInstallationMode == util2.SERVER_MODE_FULL && !isOCIChart {
 		installedAppModel.DeploymentAppType = util.PIPELINE_DEPLOYMENT_TYPE_ACD
 	} else {
 		installedAppModel.DeploymentAppType = util.PIPELINE_DEPLOYMENT_TYPE_HELM
@@ -291,6 +300,12 @@ func (impl AppStoreDeploymentServiceImpl) InstallApp(installAppVersionRequest *a
 		return nil, err
 	}
 
+	//checking image signature policies for images of the chart values
+	err = impl.VerifyChartImageSignatures(installAppVersionRequest)
+	if err != nil {
+		return nil, err
+	}
+
 	if util2.GetDevtronVersion().ServerMode == util2.SERVER_MODE_HYPERION || installAppVersionRequest.AppOfferingMode == util2.SERVER_MODE_HYPERION ||
 		installAppVersionRequest.DeploymentAppType == util.PIPELINE_DEPLOYMENT_TYPE_HELM {
 		installAppVersionRequest, err = impl.appStoreDeploymentHelmService.InstallApp(installAppVersionRequest, ctx)
@@ -871,6 +886,13 @@ func (impl AppStoreDeploymentServiceImpl) UpdateInstalledApp(ctx context.Context
 		return nil, err
 	}
 
+	//checking image signature policies for images of the upgraded chart values
+	installAppVersionRequest.AppId = installedApp.AppId
+	err = impl.VerifyChartImageSignatures(installAppVersionRequest)
+	if err != nil {
+		return nil, err
+	}
+
 	isHelmApp := installedApp.App.AppOfferingMode == util2.SERVER_MODE_HYPERION || installedApp.DeploymentAppType == util.PIPELINE_DEPLOYMENT_TYPE_HELM
 
 	// handle gitOps repo name and argoCdAppName for full mode app
@@ -1096,3 +1118,20 @@ func (impl AppStoreDeploymentServiceImpl) InstallAppByHelm(installAppVersionRequ
 	}
 	return installAppVersionRequest, nil
 }
+
+func (impl AppStoreDeploymentServiceImpl) VerifyChartImageSignatures(installAppVersionRequest *appStoreBean.InstallAppVersionDTO) error {
+	appStoreAppVersion, err := impl.appStoreApplicationVersionRepository.FindById(installAppVersionRequest.AppStoreVersion)
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart version", "err", err, "appStoreVersion", installAppVersionRequest.AppStoreVersion)
+		return err
+	}
+	verificationResult, err := impl.imageSignatureService.VerifyValuesImages(appStoreAppVersion.ValuesYaml, installAppVersionRequest.ValuesOverrideYaml, installAppVersionRequest.EnvironmentId, installAppVersionRequest.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in verifying image signatures", "err", err, "appName", installAppVersionRequest.AppName)
+		return err
+	}
+	if verificationResult != nil && verificationResult.Blocked {
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: verificationResult.Message, UserMessage: verificationResult.Message}
+	}
+	return nil
+}
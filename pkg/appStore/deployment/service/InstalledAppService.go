@@ -431,6 +431,11 @@ func (impl InstalledAppServiceImpl) performDeployStage(installedAppVersionId int
 	if err != nil {
 		return nil, err
 	}
+	err = impl.appStoreDeploymentService.VerifyChartImageSignatures(installedAppVersion)
+	if err != nil {
+		impl.logger.Errorw("image signature verification failed for chart group deployment", "err", err, "installedAppVersionId", installedAppVersionId)
+		return nil, err
+	}
 
 	if installedAppVersion.DeploymentAppType == util.PIPELINE_DEPLOYMENT_TYPE_ACD {
 		_, err := impl.performDeployStageOnAcd(installedAppVersion, ctx, userId)
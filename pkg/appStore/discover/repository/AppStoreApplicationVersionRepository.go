@@ -36,6 +36,8 @@ type AppStoreApplicationVersionRepository interface {
 	GetChartInfoById(id int) (*AppStoreApplicationVersion, error)
 	FindByAppStoreName(name string) (*appStoreBean.AppStoreWithVersion, error)
 	SearchAppStoreChartByName(chartName string) ([]*appStoreBean.ChartRepoSearch, error)
+	Save(appStoreApplicationVersion *AppStoreApplicationVersion) error
+	MarkLatestVersion(appStoreId int, id int) error
 }
 
 type AppStoreApplicationVersionRepositoryImpl struct {
@@ -197,3 +199,16 @@ func (impl *AppStoreApplicationVersionRepositoryImpl) SearchAppStoreChartByName(
 	}
 	return chartRepos, err
 }
+
+func (impl AppStoreApplicationVersionRepositoryImpl) Save(appStoreApplicationVersion *AppStoreApplicationVersion) error {
+	return impl.dbConnection.Insert(appStoreApplicationVersion)
+}
+
+// MarkLatestVersion marks version of id as latest and unmarks other versions of the app store
+func (impl AppStoreApplicationVersionRepositoryImpl) MarkLatestVersion(appStoreId int, id int) error {
+	_, err := impl.dbConnection.Model((*AppStoreApplicationVersion)(nil)).
+		Set("latest = (id = ?)", id).
+		Where("app_store_id = ?", appStoreId).
+		Update()
+	return err
+}
@@ -24,7 +24,10 @@ import (
 	"time"
 )
 
-type AppStoreRepository interface{}
+type AppStoreRepository interface {
+	FindByNameAndChartRepoId(name string, chartRepoId int) (*AppStore, error)
+	Save(appStore *AppStore) error
+}
 
 type AppStoreRepositoryImpl struct {
 	dbConnection *pg.DB
@@ -36,13 +39,27 @@ func NewAppStoreRepositoryImpl(Logger *zap.SugaredLogger, dbConnection *pg.DB) *
 }
 
 type AppStore struct {
-	TableName   struct{} `sql:"app_store" pg:",discard_unknown_columns"`
-	Id          int      `sql:"id,pk"`
-	Name        string   `sql:"name"`
-	ChartRepoId int      `sql:"chart_repo_id"`
-	//Active           bool      `sql:"active"`
+	TableName        struct{}  `sql:"app_store" pg:",discard_unknown_columns"`
+	Id               int       `sql:"id,pk"`
+	Name             string    `sql:"name"`
+	ChartRepoId      int       `sql:"chart_repo_id"`
+	Active           bool      `sql:"active,notnull"`
 	ChartGitLocation string    `sql:"chart_git_location"`
 	CreatedOn        time.Time `sql:"created_on"`
 	UpdatedOn        time.Time `sql:"updated_on"`
 	ChartRepo        *chartRepoRepository.ChartRepo
 }
+
+func (impl AppStoreRepositoryImpl) FindByNameAndChartRepoId(name string, chartRepoId int) (*AppStore, error) {
+	appStore := &AppStore{}
+	err := impl.dbConnection.Model(appStore).
+		Where("name = ?", name).
+		Where("chart_repo_id = ?", chartRepoId).
+		Limit(1).
+		Select()
+	return appStore, err
+}
+
+func (impl AppStoreRepositoryImpl) Save(appStore *AppStore) error {
+	return impl.dbConnection.Insert(appStore)
+}
@@ -0,0 +1,136 @@
+package auditLog
+
+import (
+	"time"
+
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+)
+
+type AuditEvent struct {
+	tableName    struct{}  `sql:"audit_event" pg:",discard_unknown_columns"`
+	Id           int       `sql:"id,pk"`
+	ActorId      int32     `sql:"actor_id"`
+	ActorEmail   string    `sql:"actor_email"`
+	ApiTokenName string    `sql:"api_token_name"`
+	ResourceType string    `sql:"resource_type,notnull"`
+	ResourceId   int       `sql:"resource_id"`
+	ResourceName string    `sql:"resource_name"`
+	AppId        int       `sql:"app_id"`
+	EnvId        int       `sql:"env_id"`
+	Action       string    `sql:"action,notnull"`
+	Before       string    `sql:"before"`
+	After        string    `sql:"after"`
+	Diff         string    `sql:"diff"`
+	RequestId    string    `sql:"request_id"`
+	ClientIp     string    `sql:"client_ip"`
+	Method       string    `sql:"method"`
+	Path         string    `sql:"path"`
+	CreatedOn    time.Time `sql:"created_on,notnull"`
+}
+
+// AuditEventFilter selects the audit events, zero values are not applied
+type AuditEventFilter struct {
+	ActorId      int32
+	ActorEmail   string
+	ApiTokenName string
+	ResourceType string
+	ResourceId   int
+	AppId        int
+	EnvId        int
+	Action       string
+	RequestId    string
+	From         time.Time
+	To           time.Time
+	// AfterId selects events after the id in ascending order, it is used to page through events while exporting
+	AfterId int
+	Offset  int
+	Size    int
+}
+
+type AuditEventRepository interface {
+	Save(auditEvent *AuditEvent) error
+	// FindByFilter returns the events matching filter, latest first, and the total count of matching events
+	FindByFilter(filter *AuditEventFilter) ([]*AuditEvent, int, error)
+	// FindAfterId returns the events matching filter with id greater than filter.AfterId, oldest first
+	FindAfterId(filter *AuditEventFilter) ([]*AuditEvent, error)
+	DeleteCreatedBefore(createdBefore time.Time) (int, error)
+}
+
+type AuditEventRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAuditEventRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AuditEventRepositoryImpl {
+	return &AuditEventRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl AuditEventRepositoryImpl) Save(auditEvent *AuditEvent) error {
+	return impl.dbConnection.Insert(auditEvent)
+}
+
+func (impl AuditEventRepositoryImpl) FindByFilter(filter *AuditEventFilter) ([]*AuditEvent, int, error) {
+	var auditEvents []*AuditEvent
+	query := impl.dbConnection.Model(&auditEvents)
+	applyAuditEventFilter(query, filter)
+	count, err := query.Order("id DESC").Offset(filter.Offset).Limit(filter.Size).SelectAndCount()
+	return auditEvents, count, err
+}
+
+func (impl AuditEventRepositoryImpl) FindAfterId(filter *AuditEventFilter) ([]*AuditEvent, error) {
+	var auditEvents []*AuditEvent
+	query := impl.dbConnection.Model(&auditEvents).Where("id > ?", filter.AfterId)
+	applyAuditEventFilter(query, filter)
+	err := query.Order("id ASC").Limit(filter.Size).Select()
+	return auditEvents, err
+}
+
+func (impl AuditEventRepositoryImpl) DeleteCreatedBefore(createdBefore time.Time) (int, error) {
+	var auditEvent AuditEvent
+	res, err := impl.dbConnection.Model(&auditEvent).Where("created_on < ?", createdBefore).Delete()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+func applyAuditEventFilter(query *orm.Query, filter *AuditEventFilter) {
+	if filter.ActorId > 0 {
+		query.Where("actor_id = ?", filter.ActorId)
+	}
+	if len(filter.ActorEmail) > 0 {
+		query.Where("actor_email ILIKE ?", filter.ActorEmail)
+	}
+	if len(filter.ApiTokenName) > 0 {
+		query.Where("api_token_name = ?", filter.ApiTokenName)
+	}
+	if len(filter.ResourceType) > 0 {
+		query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceId > 0 {
+		query.Where("resource_id = ?", filter.ResourceId)
+	}
+	if filter.AppId > 0 {
+		query.Where("app_id = ?", filter.AppId)
+	}
+	if filter.EnvId > 0 {
+		query.Where("env_id = ?", filter.EnvId)
+	}
+	if len(filter.Action) > 0 {
+		query.Where("action = ?", filter.Action)
+	}
+	if len(filter.RequestId) > 0 {
+		query.Where("request_id = ?", filter.RequestId)
+	}
+	if !filter.From.IsZero() {
+		query.Where("created_on >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query.Where("created_on <= ?", filter.To)
+	}
+}
@@ -0,0 +1,331 @@
+package auditLog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/devtron-labs/devtron/pkg/auditLog/siem"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/repository"
+	util2 "github.com/devtron-labs/devtron/util"
+	"go.uber.org/zap"
+)
+
+const (
+	RESOURCE_TYPE_CLUSTER              = "cluster"
+	RESOURCE_TYPE_CI_PIPELINE          = "ci-pipeline"
+	RESOURCE_TYPE_CD_PIPELINE          = "cd-pipeline"
+	RESOURCE_TYPE_DEPLOYMENT_TEMPLATE  = "deployment-template"
+	RESOURCE_TYPE_CONFIG_MAP           = "config-map"
+	RESOURCE_TYPE_SECRET               = "secret"
+	RESOURCE_TYPE_NOTIFICATION_SETTING = "notification-setting"
+
+	ACTION_CREATE = "CREATE"
+	ACTION_UPDATE = "UPDATE"
+	ACTION_DELETE = "DELETE"
+
+	// REQUEST_ID_HEADER carries the id correlating the audit events of a request, it is set by audit middleware
+	// when the client does not send it
+	REQUEST_ID_HEADER = "X-Request-Id"
+
+	DEFAULT_AUDIT_EVENT_PAGE_SIZE = 20
+	MAX_AUDIT_EVENT_PAGE_SIZE     = 500
+	auditEventExportBatchSize     = 500
+)
+
+type AuditEventConfig struct {
+	// RetentionDays is the number of days audit events are kept for, events are kept forever when it is 0
+	RetentionDays int `env:"AUDIT_EVENT_RETENTION_DAYS" envDefault:"90"`
+	// QueueSize is the number of audit events waiting to be saved off the request, requests save their events
+	// themselves when the queue is full
+	QueueSize int `env:"AUDIT_EVENT_QUEUE_SIZE" envDefault:"1000"`
+}
+
+// AuditEventRequest is the change made to a resource, Before and After are the states of resource which are
+// stored as json, nil for created and deleted resources respectively
+type AuditEventRequest struct {
+	ResourceType string
+	ResourceId   int
+	ResourceName string
+	AppId        int
+	EnvId        int
+	Action       string
+	Before       interface{}
+	After        interface{}
+}
+
+type AuditEventDto struct {
+	Id           int             `json:"id"`
+	ActorId      int32           `json:"actorId"`
+	ActorEmail   string          `json:"actorEmail"`
+	ApiTokenName string          `json:"apiTokenName,omitempty"`
+	ResourceType string          `json:"resourceType"`
+	ResourceId   int             `json:"resourceId,omitempty"`
+	ResourceName string          `json:"resourceName,omitempty"`
+	AppId        int             `json:"appId,omitempty"`
+	EnvId        int             `json:"envId,omitempty"`
+	Action       string          `json:"action"`
+	Before       json.RawMessage `json:"before,omitempty"`
+	After        json.RawMessage `json:"after,omitempty"`
+	Diff         json.RawMessage `json:"diff,omitempty"`
+	RequestId    string          `json:"requestId,omitempty"`
+	ClientIp     string          `json:"clientIp,omitempty"`
+	Method       string          `json:"method,omitempty"`
+	Path         string          `json:"path,omitempty"`
+	CreatedOn    time.Time       `json:"createdOn"`
+}
+
+type AuditEventListResponse struct {
+	TotalCount  int              `json:"totalCount"`
+	AuditEvents []*AuditEventDto `json:"auditEvents"`
+}
+
+type AuditEventService interface {
+	// Record saves the audit event of a change made by request r asynchronously, failures are logged and not returned
+	// so that auditing does not fail a change which is already made
+	Record(r *http.Request, request *AuditEventRequest)
+	GetAuditEvents(filter *AuditEventFilter) (*AuditEventListResponse, error)
+	// ExportAuditEvents writes the events matching filter to w as json lines, oldest first
+	ExportAuditEvents(filter *AuditEventFilter, w io.Writer) error
+	// PurgeExpiredAuditEvents deletes the events older than configured retention
+	PurgeExpiredAuditEvents() (int, error)
+}
+
+type AuditEventServiceImpl struct {
+	logger               *zap.SugaredLogger
+	config               *AuditEventConfig
+	auditEventRepository AuditEventRepository
+	userService          user.UserService
+	userRepository       repository.UserRepository
+	siemExportService    siem.SiemExportService
+	auditEventQueue      chan *auditEventTask
+}
+
+// auditEventTask is an audit event captured from the request, the actor is resolved from token when it is saved
+type auditEventTask struct {
+	auditEvent *AuditEvent
+	token      string
+	before     []byte
+	after      []byte
+}
+
+func NewAuditEventServiceImpl(logger *zap.SugaredLogger, auditEventRepository AuditEventRepository,
+	userService user.UserService, userRepository repository.UserRepository,
+	siemExportService siem.SiemExportService) *AuditEventServiceImpl {
+	config := &AuditEventConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing audit event config, using default", "err", err)
+		config.RetentionDays = 90
+		config.QueueSize = 1000
+	}
+	impl := &AuditEventServiceImpl{
+		logger:               logger,
+		config:               config,
+		auditEventRepository: auditEventRepository,
+		userService:          userService,
+		userRepository:       userRepository,
+		siemExportService:    siemExportService,
+		auditEventQueue:      make(chan *auditEventTask, config.QueueSize),
+	}
+	go impl.processAuditEvents()
+	return impl
+}
+
+func (impl AuditEventServiceImpl) Record(r *http.Request, request *AuditEventRequest) {
+	// states are serialized on the request as callers may change them after recording
+	before, err := toAuditJson(request.Before)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling before state of audit event", "resourceType", request.ResourceType, "err", err)
+	}
+	after, err := toAuditJson(request.After)
+	if err != nil {
+		impl.logger.Errorw("error in marshaling after state of audit event", "resourceType", request.ResourceType, "err", err)
+	}
+	task := &auditEventTask{
+		auditEvent: newAuditEvent(r, request),
+		token:      r.Header.Get("token"),
+		before:     before,
+		after:      after,
+	}
+	select {
+	case impl.auditEventQueue <- task:
+	default:
+		impl.logger.Warnw("audit event queue is full, saving audit event on request", "resourceType", request.ResourceType, "resourceId", request.ResourceId)
+		impl.saveAuditEvent(task)
+	}
+}
+
+func (impl AuditEventServiceImpl) processAuditEvents() {
+	for task := range impl.auditEventQueue {
+		impl.saveAuditEvent(task)
+	}
+}
+
+func newAuditEvent(r *http.Request, request *AuditEventRequest) *AuditEvent {
+	return &AuditEvent{
+		ResourceType: request.ResourceType,
+		ResourceId:   request.ResourceId,
+		ResourceName: request.ResourceName,
+		AppId:        request.AppId,
+		EnvId:        request.EnvId,
+		Action:       request.Action,
+		RequestId:    r.Header.Get(REQUEST_ID_HEADER),
+		ClientIp:     util2.GetClientIP(r),
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		CreatedOn:    time.Now(),
+	}
+}
+
+func (impl AuditEventServiceImpl) saveAuditEvent(task *auditEventTask) {
+	auditEvent := task.auditEvent
+	email, err := impl.userService.GetEmailFromToken(task.token)
+	if err == nil {
+		auditEvent.ActorEmail = email
+		userInfo, err := impl.userRepository.FetchActiveUserByEmail(email)
+		if err != nil {
+			impl.logger.Errorw("error in fetching actor of audit event", "email", email, "err", err)
+		} else {
+			auditEvent.ActorId = userInfo.Id
+			if userInfo.UserType == bean.USER_TYPE_API_TOKEN {
+				auditEvent.ApiTokenName = strings.TrimPrefix(email, apiToken.API_TOKEN_USER_EMAIL_PREFIX)
+			}
+		}
+	}
+
+	diff, err := createAuditDiff(task.before, task.after)
+	if err != nil {
+		impl.logger.Errorw("error in creating diff of audit event", "resourceType", auditEvent.ResourceType, "err", err)
+	}
+	auditEvent.Before = string(task.before)
+	auditEvent.After = string(task.after)
+	auditEvent.Diff = string(diff)
+
+	err = impl.auditEventRepository.Save(auditEvent)
+	if err != nil {
+		impl.logger.Errorw("error in saving audit event", "resourceType", auditEvent.ResourceType, "resourceId", auditEvent.ResourceId,
+			"action", auditEvent.Action, "actor", auditEvent.ActorEmail, "err", err)
+		return
+	}
+	impl.siemExportService.Export(toSiemEvent(auditEvent))
+}
+
+func (impl AuditEventServiceImpl) GetAuditEvents(filter *AuditEventFilter) (*AuditEventListResponse, error) {
+	if filter.Size <= 0 {
+		filter.Size = DEFAULT_AUDIT_EVENT_PAGE_SIZE
+	} else if filter.Size > MAX_AUDIT_EVENT_PAGE_SIZE {
+		filter.Size = MAX_AUDIT_EVENT_PAGE_SIZE
+	}
+	auditEvents, count, err := impl.auditEventRepository.FindByFilter(filter)
+	if err != nil {
+		impl.logger.Errorw("error in fetching audit events", "filter", filter, "err", err)
+		return nil, err
+	}
+	response := &AuditEventListResponse{
+		TotalCount:  count,
+		AuditEvents: make([]*AuditEventDto, 0, len(auditEvents)),
+	}
+	for _, auditEvent := range auditEvents {
+		response.AuditEvents = append(response.AuditEvents, toAuditEventDto(auditEvent))
+	}
+	return response, nil
+}
+
+func (impl AuditEventServiceImpl) ExportAuditEvents(filter *AuditEventFilter, w io.Writer) error {
+	filter.AfterId = 0
+	filter.Size = auditEventExportBatchSize
+	encoder := json.NewEncoder(w)
+	for {
+		auditEvents, err := impl.auditEventRepository.FindAfterId(filter)
+		if err != nil {
+			impl.logger.Errorw("error in fetching audit events for export", "filter", filter, "err", err)
+			return err
+		}
+		for _, auditEvent := range auditEvents {
+			// encoder terminates every event with new line
+			err = encoder.Encode(toAuditEventDto(auditEvent))
+			if err != nil {
+				impl.logger.Errorw("error in writing audit event for export", "id", auditEvent.Id, "err", err)
+				return err
+			}
+			filter.AfterId = auditEvent.Id
+		}
+		if len(auditEvents) < filter.Size {
+			return nil
+		}
+	}
+}
+
+func (impl AuditEventServiceImpl) PurgeExpiredAuditEvents() (int, error) {
+	if impl.config.RetentionDays <= 0 {
+		return 0, nil
+	}
+	createdBefore := time.Now().AddDate(0, 0, -impl.config.RetentionDays)
+	count, err := impl.auditEventRepository.DeleteCreatedBefore(createdBefore)
+	if err != nil {
+		impl.logger.Errorw("error in deleting expired audit events", "createdBefore", createdBefore, "err", err)
+		return 0, err
+	}
+	impl.logger.Infow("deleted expired audit events", "count", count, "createdBefore", createdBefore)
+	return count, nil
+}
+
+func toAuditEventDto(auditEvent *AuditEvent) *AuditEventDto {
+	return &AuditEventDto{
+		Id:           auditEvent.Id,
+		ActorId:      auditEvent.ActorId,
+		ActorEmail:   auditEvent.ActorEmail,
+		ApiTokenName: auditEvent.ApiTokenName,
+		ResourceType: auditEvent.ResourceType,
+		ResourceId:   auditEvent.ResourceId,
+		ResourceName: auditEvent.ResourceName,
+		AppId:        auditEvent.AppId,
+		EnvId:        auditEvent.EnvId,
+		Action:       auditEvent.Action,
+		Before:       toRawJson(auditEvent.Before),
+		After:        toRawJson(auditEvent.After),
+		Diff:         toRawJson(auditEvent.Diff),
+		RequestId:    auditEvent.RequestId,
+		ClientIp:     auditEvent.ClientIp,
+		Method:       auditEvent.Method,
+		Path:         auditEvent.Path,
+		CreatedOn:    auditEvent.CreatedOn,
+	}
+}
+
+func toSiemEvent(auditEvent *AuditEvent) *siem.SiemEvent {
+	return &siem.SiemEvent{
+		Id:           fmt.Sprintf("%s-%d", siem.CATEGORY_AUDIT_EVENT, auditEvent.Id),
+		Category:     siem.CATEGORY_AUDIT_EVENT,
+		Action:       auditEvent.Action,
+		ActorId:      auditEvent.ActorId,
+		ActorEmail:   auditEvent.ActorEmail,
+		ApiTokenName: auditEvent.ApiTokenName,
+		ResourceType: auditEvent.ResourceType,
+		ResourceId:   auditEvent.ResourceId,
+		ResourceName: auditEvent.ResourceName,
+		AppId:        auditEvent.AppId,
+		EnvId:        auditEvent.EnvId,
+		Diff:         auditEvent.Diff,
+		RequestId:    auditEvent.RequestId,
+		ClientIp:     auditEvent.ClientIp,
+		Method:       auditEvent.Method,
+		Path:         auditEvent.Path,
+		CreatedOn:    auditEvent.CreatedOn,
+	}
+}
+
+func toRawJson(value string) json.RawMessage {
+	if len(value) == 0 {
+		return nil
+	}
+	return json.RawMessage(value)
+}
@@ -0,0 +1,79 @@
+package auditLog
+
+import (
+	"encoding/json"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+const maskedValue = "*****"
+
+// sensitiveKeys are the lower cased keys whose values are masked before states of resources are stored
+var sensitiveKeys = map[string]bool{
+	"password":        true,
+	"token":           true,
+	"bearer_token":    true,
+	"bearertoken":     true,
+	"accesstoken":     true,
+	"apikey":          true,
+	"accesskey":       true,
+	"secretkey":       true,
+	"secretaccesskey": true,
+	"privatekey":      true,
+	"sshprivatekey":   true,
+	"tlskey":          true,
+	"tlsclientkey":    true,
+	"key_data":        true,
+	"cert_data":       true,
+	"cert_auth_data":  true,
+}
+
+// toAuditJson marshals the state of resource with sensitive values masked, nil state gives nil
+func toAuditJson(state interface{}) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(maskSensitiveValues(value))
+}
+
+func maskSensitiveValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitiveKeys[strings.ToLower(key)] {
+				if s, ok := item.(string); !ok || len(s) > 0 {
+					v[key] = maskedValue
+				}
+				continue
+			}
+			v[key] = maskSensitiveValues(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = maskSensitiveValues(item)
+		}
+	}
+	return value
+}
+
+// createAuditDiff returns the json merge patch turning before into after, there is no diff for created and
+// deleted resources as the whole state is stored
+func createAuditDiff(before []byte, after []byte) ([]byte, error) {
+	if len(before) == 0 || len(after) == 0 {
+		return nil, nil
+	}
+	return jsonpatch.CreateMergePatch(before, after)
+}
@@ -0,0 +1,32 @@
+package auditLog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAuditJsonMasksSensitiveValues(t *testing.T) {
+	state := map[string]interface{}{
+		"cluster_name": "default",
+		"config":       map[string]string{"bearer_token": "abc"},
+		"users":        []interface{}{map[string]interface{}{"password": "xyz", "token": ""}},
+	}
+	data, err := toAuditJson(state)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"cluster_name":"default","config":{"bearer_token":"*****"},"users":[{"password":"*****","token":""}]}`, string(data))
+
+	data, err = toAuditJson(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, data)
+}
+
+func TestCreateAuditDiff(t *testing.T) {
+	diff, err := createAuditDiff([]byte(`{"name":"a","replicas":1,"labels":{"team":"x"}}`), []byte(`{"name":"a","replicas":2,"labels":{}}`))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"replicas":2,"labels":{"team":null}}`, string(diff))
+
+	diff, err = createAuditDiff(nil, []byte(`{"name":"a"}`))
+	assert.Nil(t, err)
+	assert.Nil(t, diff)
+}
@@ -0,0 +1,202 @@
+package siem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env"
+	"go.uber.org/zap"
+)
+
+type SiemExportService interface {
+	// Export queues event to be buffered on disk and delivered on a later flush, it does not wait for the disk and
+	// drops the event when the queue is full. It is a no-op when export is disabled.
+	Export(event *SiemEvent)
+	// Flush delivers the buffered events in batches, a batch is retried with backoff and kept in buffer when
+	// retries are exhausted
+	Flush() error
+	GetConfig() *SiemExportConfig
+}
+
+type SiemExportServiceImpl struct {
+	logger *zap.SugaredLogger
+	config *SiemExportConfig
+	buffer *DiskBuffer
+	sender Sender
+	// exportQueue keeps the exported events till they are written to buffer
+	exportQueue chan *SiemEvent
+}
+
+func NewSiemExportServiceImpl(logger *zap.SugaredLogger) *SiemExportServiceImpl {
+	impl := &SiemExportServiceImpl{
+		logger: logger,
+		config: &SiemExportConfig{},
+	}
+	err := env.Parse(impl.config)
+	if err != nil {
+		logger.Errorw("error in parsing siem export config, export disabled", "err", err)
+		impl.config.Enabled = false
+		return impl
+	}
+	if !impl.config.Enabled {
+		return impl
+	}
+	if impl.config.Format != FORMAT_CEF && impl.config.Format != FORMAT_JSON {
+		logger.Errorw("unsupported siem format, export disabled", "format", impl.config.Format)
+		impl.config.Enabled = false
+		return impl
+	}
+	if impl.config.BatchSize <= 0 {
+		impl.config.BatchSize = 100
+	}
+	if impl.config.QueueSize <= 0 {
+		impl.config.QueueSize = 1000
+	}
+	err = validateBufferDir(impl.config.BufferDir)
+	if err != nil {
+		logger.Errorw("invalid siem buffer dir, export disabled", "dir", impl.config.BufferDir, "err", err)
+		impl.config.Enabled = false
+		return impl
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.Warnw("error in getting hostname for siem export", "err", err)
+	}
+	impl.sender, err = NewSender(impl.config, hostname)
+	if err != nil {
+		logger.Errorw("error in creating siem sender, export disabled", "protocol", impl.config.Protocol, "err", err)
+		impl.config.Enabled = false
+		return impl
+	}
+	impl.buffer, err = NewDiskBuffer(logger, impl.config.BufferDir, impl.config.BufferMaxSizeMb)
+	if err != nil {
+		logger.Errorw("error in creating siem buffer, export disabled", "dir", impl.config.BufferDir, "err", err)
+		impl.config.Enabled = false
+		return impl
+	}
+	impl.exportQueue = make(chan *SiemEvent, impl.config.QueueSize)
+	go impl.processExports()
+	return impl
+}
+
+// validateBufferDir refuses temp directories for buffer, they are not persisted across restarts of the pod
+func validateBufferDir(dir string) error {
+	if len(dir) == 0 || !filepath.IsAbs(dir) {
+		return fmt.Errorf("buffer dir must be an absolute path")
+	}
+	dir = filepath.Clean(dir)
+	for _, tempDir := range []string{"/tmp", filepath.Clean(os.TempDir())} {
+		if dir == tempDir || strings.HasPrefix(dir, tempDir+string(filepath.Separator)) {
+			return fmt.Errorf("buffer dir %s is in temp dir %s, use a persistent volume", dir, tempDir)
+		}
+	}
+	return nil
+}
+
+func (impl *SiemExportServiceImpl) GetConfig() *SiemExportConfig {
+	return impl.config
+}
+
+func (impl *SiemExportServiceImpl) Export(event *SiemEvent) {
+	if !impl.config.Enabled {
+		return
+	}
+	select {
+	case impl.exportQueue <- event:
+	default:
+		droppedEventsCounter.WithLabelValues(DROP_REASON_QUEUE_FULL).Inc()
+		impl.logger.Errorw("siem export queue is full, dropped event", "id", event.Id, "category", event.Category, "action", event.Action)
+	}
+}
+
+func (impl *SiemExportServiceImpl) processExports() {
+	for event := range impl.exportQueue {
+		impl.bufferEvent(event)
+	}
+}
+
+func (impl *SiemExportServiceImpl) bufferEvent(event *SiemEvent) {
+	err := impl.buffer.Append(event)
+	if err != nil {
+		droppedEventsCounter.WithLabelValues(DROP_REASON_BUFFER_ERR).Inc()
+		impl.logger.Errorw("error in buffering siem event, dropped event", "id", event.Id, "category", event.Category, "err", err)
+	}
+}
+
+func (impl *SiemExportServiceImpl) Flush() error {
+	if !impl.config.Enabled {
+		return nil
+	}
+	err := impl.buffer.Rotate()
+	if err != nil {
+		impl.logger.Errorw("error in rotating siem buffer", "err", err)
+		return err
+	}
+	segments, err := impl.buffer.Segments()
+	if err != nil {
+		impl.logger.Errorw("error in listing siem buffer segments", "err", err)
+		return err
+	}
+	for _, segment := range segments {
+		err = impl.flushSegment(segment)
+		if err != nil {
+			// later segments are not sent so that events are delivered in order
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *SiemExportServiceImpl) flushSegment(segment string) error {
+	events, err := impl.buffer.Read(segment)
+	if err != nil {
+		impl.logger.Errorw("error in reading siem buffer segment", "segment", segment, "err", err)
+		return err
+	}
+	for start := 0; start < len(events); start += impl.config.BatchSize {
+		end := start + impl.config.BatchSize
+		if end > len(events) {
+			end = len(events)
+		}
+		err = impl.sendWithRetry(events[start:end])
+		if err != nil {
+			impl.logger.Errorw("error in delivering events to siem, kept in buffer", "segment", segment,
+				"pending", len(events)-start, "err", err)
+			if start > 0 {
+				// delivered batches are removed from segment to avoid duplicates on next flush
+				replaceErr := impl.buffer.Replace(segment, events[start:])
+				if replaceErr != nil {
+					impl.logger.Errorw("error in updating siem buffer segment", "segment", segment, "err", replaceErr)
+				}
+			}
+			return err
+		}
+	}
+	err = impl.buffer.Remove(segment)
+	if err != nil {
+		impl.logger.Errorw("error in removing delivered siem buffer segment", "segment", segment, "err", err)
+		return err
+	}
+	impl.logger.Debugw("delivered events to siem", "segment", segment, "count", len(events))
+	return nil
+}
+
+func (impl *SiemExportServiceImpl) sendWithRetry(events []*SiemEvent) error {
+	backoff := time.Duration(impl.config.RetryBackoffSeconds) * time.Second
+	var err error
+	for attempt := 0; attempt <= impl.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		err = impl.sender.Send(events)
+		if err == nil {
+			return nil
+		}
+		impl.logger.Warnw("error in sending events to siem", "attempt", attempt+1, "count", len(events), "err", err)
+	}
+	return err
+}
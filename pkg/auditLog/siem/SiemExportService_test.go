@@ -0,0 +1,182 @@
+package siem
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+type fakeSender struct {
+	failAfter int
+	sent      []string
+}
+
+func (impl *fakeSender) Send(events []*SiemEvent) error {
+	if impl.failAfter >= 0 && len(impl.sent)+len(events) > impl.failAfter {
+		return errors.New("siem unavailable")
+	}
+	for _, event := range events {
+		impl.sent = append(impl.sent, event.Id)
+	}
+	return nil
+}
+
+func (impl *fakeSender) Close() {}
+
+func newTestSiemExportService(t *testing.T, sender Sender) *SiemExportServiceImpl {
+	logger := zap.NewNop().Sugar()
+	buffer, err := NewDiskBuffer(logger, t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SiemExportServiceImpl{
+		logger:      logger,
+		config:      &SiemExportConfig{Enabled: true, BatchSize: 2, QueueSize: 10},
+		buffer:      buffer,
+		sender:      sender,
+		exportQueue: make(chan *SiemEvent, 10),
+	}
+}
+
+// bufferQueuedEvents writes the queued events to buffer as the export worker does
+func bufferQueuedEvents(impl *SiemExportServiceImpl) {
+	for {
+		select {
+		case event := <-impl.exportQueue:
+			impl.bufferEvent(event)
+		default:
+			return
+		}
+	}
+}
+
+func droppedEvents(t *testing.T, reason string) float64 {
+	metric := &dto.Metric{}
+	if err := droppedEventsCounter.WithLabelValues(reason).Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestFlushKeepsUndeliveredEvents(t *testing.T) {
+	sender := &fakeSender{failAfter: 2}
+	impl := newTestSiemExportService(t, sender)
+	for i := 1; i <= 3; i++ {
+		impl.Export(&SiemEvent{Id: fmt.Sprintf("event-%d", i)})
+	}
+	bufferQueuedEvents(impl)
+	if err := impl.Flush(); err == nil {
+		t.Fatal("expected flush to fail")
+	}
+	if len(sender.sent) != 2 {
+		t.Fatalf("expected first batch to be delivered, sent %v", sender.sent)
+	}
+	segments, err := impl.buffer.Segments()
+	if err != nil || len(segments) != 1 {
+		t.Fatalf("expected a segment to be kept, got %v, err %v", segments, err)
+	}
+	pending, err := impl.buffer.Read(segments[0])
+	if err != nil || len(pending) != 1 || pending[0].Id != "event-3" {
+		t.Fatalf("expected only undelivered event in buffer, got %v, err %v", pending, err)
+	}
+
+	// siem is back, pending and new events are delivered in order
+	sender.failAfter = -1
+	impl.Export(&SiemEvent{Id: "event-4"})
+	bufferQueuedEvents(impl)
+	if err = impl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"event-1", "event-2", "event-3", "event-4"}
+	if fmt.Sprint(sender.sent) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, sent %v", expected, sender.sent)
+	}
+	if segments, _ = impl.buffer.Segments(); len(segments) != 0 {
+		t.Errorf("expected empty buffer, got %v", segments)
+	}
+}
+
+func TestExportDropsEventsWhenQueueIsFull(t *testing.T) {
+	impl := newTestSiemExportService(t, &fakeSender{failAfter: -1})
+	impl.exportQueue = make(chan *SiemEvent, 1)
+	dropped := droppedEvents(t, DROP_REASON_QUEUE_FULL)
+
+	// export does not wait for the worker writing to disk
+	impl.Export(&SiemEvent{Id: "event-1"})
+	impl.Export(&SiemEvent{Id: "event-2"})
+	if len(impl.exportQueue) != 1 {
+		t.Fatalf("expected one queued event, got %d", len(impl.exportQueue))
+	}
+	if got := droppedEvents(t, DROP_REASON_QUEUE_FULL) - dropped; got != 1 {
+		t.Errorf("expected one dropped event, got %v", got)
+	}
+}
+
+func TestRotateCountsDroppedEvents(t *testing.T) {
+	buffer, err := NewDiskBuffer(zap.NewNop().Sugar(), t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropped := droppedEvents(t, DROP_REASON_BUFFER_FULL)
+	for i := 1; i <= 3; i++ {
+		if err = buffer.Append(&SiemEvent{Id: fmt.Sprintf("event-%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = buffer.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err = buffer.Append(&SiemEvent{Id: "event-4"}); err != nil {
+		t.Fatal(err)
+	}
+	// buffer fits only the newest segment
+	segments, _ := buffer.Segments()
+	first, _ := os.Stat(filepath.Join(buffer.dir, segments[0]))
+	buffer.maxBytes = first.Size()
+	if err = buffer.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	segments, _ = buffer.Segments()
+	if len(segments) != 1 {
+		t.Fatalf("expected oldest segment to be dropped, got %v", segments)
+	}
+	if events, _ := buffer.Read(segments[0]); len(events) != 1 || events[0].Id != "event-4" {
+		t.Errorf("expected newest event to be kept, got %v", events)
+	}
+	if got := droppedEvents(t, DROP_REASON_BUFFER_FULL) - dropped; got != 3 {
+		t.Errorf("expected three dropped events, got %v", got)
+	}
+}
+
+func TestExportIsNoopWhenDisabled(t *testing.T) {
+	impl := &SiemExportServiceImpl{logger: zap.NewNop().Sugar(), config: &SiemExportConfig{}}
+	impl.Export(&SiemEvent{Id: "event-1"})
+	if err := impl.Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateBufferDir(t *testing.T) {
+	tests := []struct {
+		dir     string
+		wantErr bool
+	}{
+		{dir: "/var/lib/devtron/siem"},
+		{dir: "/tmp", wantErr: true},
+		{dir: "/tmp/devtron/siem", wantErr: true},
+		{dir: "/tmp/../tmp/siem", wantErr: true},
+		{dir: "/tmpfs/siem"},
+		{dir: "devtron/siem", wantErr: true},
+		{dir: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := validateBufferDir(tt.dir); (err != nil) != tt.wantErr {
+			t.Errorf("validateBufferDir(%q) error = %v, wantErr %v", tt.dir, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package siem
+
+import "time"
+
+const (
+	PROTOCOL_SYSLOG_TCP = "syslog-tcp"
+	PROTOCOL_SYSLOG_TLS = "syslog-tls"
+	PROTOCOL_HTTP       = "http"
+
+	FORMAT_CEF  = "cef"
+	FORMAT_JSON = "json"
+
+	CATEGORY_AUDIT_EVENT = "audit-event"
+	CATEGORY_USER_AUDIT  = "user-audit"
+	// CATEGORY_MODULE_ACTION and CATEGORY_SERVER_ACTION are installs and upgrades of modules and of devtron itself
+	CATEGORY_MODULE_ACTION = "module-action"
+	CATEGORY_SERVER_ACTION = "server-action"
+)
+
+type SiemExportConfig struct {
+	Enabled bool `env:"SIEM_EXPORT_ENABLED" envDefault:"false"`
+	// Protocol is one of syslog-tcp, syslog-tls and http
+	Protocol string `env:"SIEM_PROTOCOL" envDefault:"syslog-tcp"`
+	// Format is one of cef and json
+	Format string `env:"SIEM_FORMAT" envDefault:"cef"`
+	// Address is host:port of syslog receiver
+	Address string `env:"SIEM_SYSLOG_ADDRESS" envDefault:""`
+	// Url is the http endpoint receiving batches of events
+	Url string `env:"SIEM_HTTP_URL" envDefault:""`
+	// AuthHeader is sent as Authorization header of http requests, i.e. "Splunk <token>"
+	AuthHeader            string `env:"SIEM_HTTP_AUTH_HEADER" envDefault:""`
+	TlsCaCertPath         string `env:"SIEM_TLS_CA_CERT_PATH" envDefault:""`
+	TlsInsecureSkipVerify bool   `env:"SIEM_TLS_INSECURE_SKIP_VERIFY" envDefault:"false"`
+	TimeoutSeconds        int    `env:"SIEM_TIMEOUT_SECONDS" envDefault:"10"`
+	BatchSize             int    `env:"SIEM_BATCH_SIZE" envDefault:"100"`
+	FlushIntervalSeconds  int    `env:"SIEM_FLUSH_INTERVAL_SECONDS" envDefault:"10"`
+	MaxRetries            int    `env:"SIEM_MAX_RETRIES" envDefault:"3"`
+	RetryBackoffSeconds   int    `env:"SIEM_RETRY_BACKOFF_SECONDS" envDefault:"2"`
+	// BufferDir keeps the events which are not yet delivered, it must be on a persistent volume for events to
+	// survive restarts during a SIEM outage, temp directories are refused
+	BufferDir string `env:"SIEM_BUFFER_DIR" envDefault:"/var/lib/devtron/siem"`
+	// BufferMaxSizeMb bounds the buffer, oldest events are dropped when it is full
+	BufferMaxSizeMb int `env:"SIEM_BUFFER_MAX_SIZE_MB" envDefault:"512"`
+	// QueueSize is the number of events waiting to be written to buffer off the request path, events are dropped
+	// when the queue is full so that a slow disk does not slow down logins and other audited requests
+	QueueSize int `env:"SIEM_QUEUE_SIZE" envDefault:"1000"`
+}
+
+// SiemEvent is an audit event, a user audit (login, role grant and revoke) or a module or server action exported
+// to SIEM
+type SiemEvent struct {
+	// Id is unique across categories, i.e. audit-event-12
+	Id           string    `json:"id"`
+	Category     string    `json:"category"`
+	Action       string    `json:"action"`
+	ActorId      int32     `json:"actorId,omitempty"`
+	ActorEmail   string    `json:"actorEmail,omitempty"`
+	ApiTokenName string    `json:"apiTokenName,omitempty"`
+	ResourceType string    `json:"resourceType,omitempty"`
+	ResourceId   int       `json:"resourceId,omitempty"`
+	ResourceName string    `json:"resourceName,omitempty"`
+	AppId        int       `json:"appId,omitempty"`
+	EnvId        int       `json:"envId,omitempty"`
+	Diff         string    `json:"diff,omitempty"`
+	RequestId    string    `json:"requestId,omitempty"`
+	ClientIp     string    `json:"clientIp,omitempty"`
+	Method       string    `json:"method,omitempty"`
+	Path         string    `json:"path,omitempty"`
+	CreatedOn    time.Time `json:"createdOn"`
+}
@@ -0,0 +1,215 @@
+package siem
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	activeSegmentName = "active.jsonl"
+	segmentSuffix     = ".jsonl"
+)
+
+// DiskBuffer keeps the events to be exported as json lines. Events are appended to the active segment which is
+// rotated to a numbered segment before flush, segments are removed once delivered so that the events which are
+// not delivered survive SIEM outages and restarts.
+type DiskBuffer struct {
+	logger   *zap.SugaredLogger
+	dir      string
+	maxBytes int64
+	lock     sync.Mutex
+}
+
+func NewDiskBuffer(logger *zap.SugaredLogger, dir string, maxSizeMb int) (*DiskBuffer, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskBuffer{
+		logger:   logger,
+		dir:      dir,
+		maxBytes: int64(maxSizeMb) * 1024 * 1024,
+	}, nil
+}
+
+func (impl *DiskBuffer) Append(event *SiemEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+	file, err := os.OpenFile(filepath.Join(impl.dir, activeSegmentName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Rotate moves the active segment to a numbered segment and drops the oldest segments when the buffer is full
+func (impl *DiskBuffer) Rotate() error {
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+	activePath := filepath.Join(impl.dir, activeSegmentName)
+	info, err := os.Stat(activePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil && info.Size() > 0 {
+		// zero padded time keeps lexical order of segments same as their order of creation
+		segmentPath := filepath.Join(impl.dir, fmt.Sprintf("%020d%s", time.Now().UnixNano(), segmentSuffix))
+		err = os.Rename(activePath, segmentPath)
+		if err != nil {
+			return err
+		}
+	}
+	return impl.enforceMaxSize()
+}
+
+func (impl *DiskBuffer) enforceMaxSize() error {
+	if impl.maxBytes <= 0 {
+		return nil
+	}
+	files, err := impl.segmentFiles()
+	if err != nil {
+		return err
+	}
+	var size int64
+	for _, file := range files {
+		size += file.Size()
+	}
+	for i := 0; size > impl.maxBytes && i < len(files); i++ {
+		segmentPath := filepath.Join(impl.dir, files[i].Name())
+		count, err := countLines(segmentPath)
+		if err != nil {
+			impl.logger.Errorw("error in counting events of dropped siem buffer segment", "segment", files[i].Name(), "err", err)
+		}
+		err = os.Remove(segmentPath)
+		if err != nil {
+			return err
+		}
+		size -= files[i].Size()
+		droppedEventsCounter.WithLabelValues(DROP_REASON_BUFFER_FULL).Add(float64(count))
+		impl.logger.Errorw("siem buffer is full, dropped oldest events", "segment", files[i].Name(), "count", count, "maxBytes", impl.maxBytes)
+	}
+	return nil
+}
+
+// countLines returns the number of events in segment, one event is written per line
+func countLines(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	count := 0
+	chunk := make([]byte, 64*1024)
+	for {
+		n, err := file.Read(chunk)
+		count += bytes.Count(chunk[:n], []byte{'\n'})
+		if err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return count, err
+		}
+	}
+}
+
+// Segments returns the rotated segments, oldest first
+func (impl *DiskBuffer) Segments() ([]string, error) {
+	impl.lock.Lock()
+	defer impl.lock.Unlock()
+	files, err := impl.segmentFiles()
+	if err != nil {
+		return nil, err
+	}
+	var segments []string
+	for _, file := range files {
+		segments = append(segments, file.Name())
+	}
+	return segments, nil
+}
+
+func (impl *DiskBuffer) segmentFiles() ([]os.FileInfo, error) {
+	files, err := ioutil.ReadDir(impl.dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []os.FileInfo
+	for _, file := range files {
+		if file.IsDir() || file.Name() == activeSegmentName || !strings.HasSuffix(file.Name(), segmentSuffix) {
+			continue
+		}
+		segments = append(segments, file)
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Name() < segments[j].Name()
+	})
+	return segments, nil
+}
+
+// Read returns the events of segment, a line which is not a valid event, i.e. partially written on crash, is skipped
+func (impl *DiskBuffer) Read(segment string) ([]*SiemEvent, error) {
+	file, err := os.Open(filepath.Join(impl.dir, segment))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var events []*SiemEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		event := &SiemEvent{}
+		err = json.Unmarshal(line, event)
+		if err != nil {
+			impl.logger.Errorw("skipping invalid event in siem buffer", "segment", segment, "err", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Replace rewrites segment with events, used to keep the events remaining after a partial delivery
+func (impl *DiskBuffer) Replace(segment string, events []*SiemEvent) error {
+	var content []byte
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		content = append(content, line...)
+		content = append(content, '\n')
+	}
+	tmpPath := filepath.Join(impl.dir, segment+".tmp")
+	err := ioutil.WriteFile(tmpPath, content, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(impl.dir, segment))
+}
+
+func (impl *DiskBuffer) Remove(segment string) error {
+	return os.Remove(filepath.Join(impl.dir, segment))
+}
@@ -0,0 +1,126 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	cefVendor  = "Devtron"
+	cefProduct = "Devtron Orchestrator"
+	cefVersion = "1.0"
+
+	// syslogPriority is facility authpriv(10) with severity informational(6)
+	syslogPriority = 10*8 + 6
+	syslogAppName  = "devtron"
+)
+
+var cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+var cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+
+// formatEvent formats event as a single line in configured format
+func formatEvent(format string, event *SiemEvent) ([]byte, error) {
+	switch format {
+	case FORMAT_CEF:
+		return []byte(formatCef(event)), nil
+	case FORMAT_JSON:
+		return json.Marshal(event)
+	default:
+		return nil, fmt.Errorf("unsupported siem format '%s'", format)
+	}
+}
+
+func formatCef(event *SiemEvent) string {
+	signatureId := event.Action
+	name := event.Action
+	if len(event.ResourceType) > 0 {
+		signatureId = fmt.Sprintf("%s:%s", event.ResourceType, event.Action)
+		name = fmt.Sprintf("%s %s", event.Action, event.ResourceType)
+	}
+	// custom extensions cs and cn have their label as the meaning of key differs across products
+	extensions := []struct {
+		key   string
+		label string
+		value string
+	}{
+		{key: "externalId", value: event.Id},
+		{key: "rt", value: strconv.FormatInt(event.CreatedOn.UnixNano()/int64(time.Millisecond), 10)},
+		{key: "cat", value: event.Category},
+		{key: "act", value: event.Action},
+		{key: "suid", value: formatNonZero(int(event.ActorId))},
+		{key: "suser", value: event.ActorEmail},
+		{key: "src", value: event.ClientIp},
+		{key: "requestMethod", value: event.Method},
+		{key: "request", value: event.Path},
+		{key: "cs1", label: "resourceType", value: event.ResourceType},
+		{key: "cs2", label: "resourceName", value: event.ResourceName},
+		{key: "cs3", label: "requestId", value: event.RequestId},
+		{key: "cs4", label: "apiTokenName", value: event.ApiTokenName},
+		{key: "cs5", label: "diff", value: event.Diff},
+		{key: "cn1", label: "resourceId", value: formatNonZero(event.ResourceId)},
+		{key: "cn2", label: "appId", value: formatNonZero(event.AppId)},
+		{key: "cn3", label: "envId", value: formatNonZero(event.EnvId)},
+	}
+	var ext []string
+	for _, extension := range extensions {
+		if len(extension.value) == 0 {
+			continue
+		}
+		if len(extension.label) > 0 {
+			ext = append(ext, extension.key+"Label="+extension.label)
+		}
+		ext = append(ext, extension.key+"="+cefExtensionEscaper.Replace(extension.value))
+	}
+	return fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s", cefHeaderEscaper.Replace(cefVendor), cefHeaderEscaper.Replace(cefProduct),
+		cefVersion, cefHeaderEscaper.Replace(signatureId), cefHeaderEscaper.Replace(name), cefSeverity(event.Action), strings.Join(ext, " "))
+}
+
+// cefSeverity is higher for the actions removing resources or access
+func cefSeverity(action string) int {
+	switch action {
+	case "DELETE", "ROLE_REVOKE":
+		return 6
+	case "ROLE_GRANT":
+		return 5
+	default:
+		return 3
+	}
+}
+
+func formatNonZero(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+// formatSyslog wraps msg in a RFC 5424 syslog message with octet counting framing of RFC 6587, used over tcp and tls
+func formatSyslog(hostname string, event *SiemEvent, msg []byte) []byte {
+	if len(hostname) == 0 {
+		hostname = "-"
+	}
+	message := fmt.Sprintf("<%d>1 %s %s %s - %s - %s", syslogPriority, event.CreatedOn.UTC().Format(time.RFC3339Nano),
+		hostname, syslogAppName, event.Category, msg)
+	return []byte(fmt.Sprintf("%d %s", len(message), message))
+}
+
+// formatHttpBatch returns the body and content type of a http batch, json events are sent as an array and cef
+// events as lines
+func formatHttpBatch(format string, events []*SiemEvent) ([]byte, string, error) {
+	if format == FORMAT_JSON {
+		body, err := json.Marshal(events)
+		return body, "application/json", err
+	}
+	var lines []string
+	for _, event := range events {
+		line, err := formatEvent(format, event)
+		if err != nil {
+			return nil, "", err
+		}
+		lines = append(lines, string(line))
+	}
+	return []byte(strings.Join(lines, "\n")), "text/plain", nil
+}
@@ -0,0 +1,82 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatCef(t *testing.T) {
+	event := &SiemEvent{
+		Id:           "audit-event-7",
+		Category:     CATEGORY_AUDIT_EVENT,
+		Action:       "DELETE",
+		ActorId:      2,
+		ActorEmail:   "admin@example.com",
+		ResourceType: "config-map",
+		ResourceName: "app|cm=1",
+		AppId:        5,
+		Diff:         "{\"data\":null}\n",
+		ClientIp:     "10.0.0.1",
+		CreatedOn:    time.Unix(1600000000, 0),
+	}
+	cef := formatCef(event)
+	expectedPrefix := "CEF:0|Devtron|Devtron Orchestrator|1.0|config-map:DELETE|DELETE config-map|6|"
+	if !strings.HasPrefix(cef, expectedPrefix) {
+		t.Fatalf("unexpected cef header %s", cef)
+	}
+	for _, expected := range []string{
+		"externalId=audit-event-7",
+		"rt=1600000000000",
+		"suid=2",
+		"suser=admin@example.com",
+		"src=10.0.0.1",
+		"cs2Label=resourceName cs2=app|cm\\=1",
+		"cs5Label=diff cs5={\"data\":null}\\n",
+		"cn2Label=appId cn2=5",
+	} {
+		if !strings.Contains(cef, expected) {
+			t.Errorf("expected %s in %s", expected, cef)
+		}
+	}
+	if strings.Contains(cef, "cn3Label") || strings.Contains(cef, "cs4Label") {
+		t.Errorf("labels of empty extensions are not expected in %s", cef)
+	}
+}
+
+func TestFormatCefHeaderEscaping(t *testing.T) {
+	cef := formatCef(&SiemEvent{Action: "LOGIN", ResourceType: "a|b\\c"})
+	if !strings.Contains(cef, "|a\\|b\\\\c:LOGIN|") {
+		t.Errorf("header is not escaped in %s", cef)
+	}
+}
+
+func TestFormatSyslog(t *testing.T) {
+	event := &SiemEvent{Category: CATEGORY_USER_AUDIT, CreatedOn: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)}
+	framed := string(formatSyslog("host-1", event, []byte("msg")))
+	message := "<86>1 2022-01-02T03:04:05Z host-1 devtron - user-audit - msg"
+	if framed != fmt.Sprintf("%d %s", len(message), message) {
+		t.Errorf("unexpected syslog message %q", framed)
+	}
+}
+
+func TestFormatHttpBatch(t *testing.T) {
+	events := []*SiemEvent{{Id: "1", Action: "LOGIN"}, {Id: "2", Action: "LOGIN"}}
+	body, contentType, err := formatHttpBatch(FORMAT_JSON, events)
+	if err != nil || contentType != "application/json" {
+		t.Fatalf("unexpected json batch, contentType %s, err %v", contentType, err)
+	}
+	var decoded []*SiemEvent
+	if err = json.Unmarshal(body, &decoded); err != nil || len(decoded) != 2 {
+		t.Errorf("expected array of 2 events, got %s", body)
+	}
+	body, contentType, err = formatHttpBatch(FORMAT_CEF, events)
+	if err != nil || contentType != "text/plain" {
+		t.Fatalf("unexpected cef batch, contentType %s, err %v", contentType, err)
+	}
+	if lines := strings.Split(string(body), "\n"); len(lines) != 2 {
+		t.Errorf("expected 2 lines, got %s", body)
+	}
+}
@@ -0,0 +1,17 @@
+package siem
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	DROP_REASON_QUEUE_FULL  = "queue_full"
+	DROP_REASON_BUFFER_FULL = "buffer_full"
+	DROP_REASON_BUFFER_ERR  = "buffer_error"
+)
+
+var droppedEventsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "orchestrator_siem_dropped_events_total",
+	Help: "Audit events dropped before delivery to SIEM, partitioned by reason.",
+}, []string{"reason"})
@@ -0,0 +1,170 @@
+package siem
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Sender delivers a batch of events to SIEM, a batch is delivered completely or not at all
+type Sender interface {
+	Send(events []*SiemEvent) error
+	Close()
+}
+
+func NewSender(config *SiemExportConfig, hostname string) (Sender, error) {
+	timeout := time.Duration(config.TimeoutSeconds) * time.Second
+	switch config.Protocol {
+	case PROTOCOL_SYSLOG_TCP, PROTOCOL_SYSLOG_TLS:
+		if len(config.Address) == 0 {
+			return nil, fmt.Errorf("syslog address is required for protocol '%s'", config.Protocol)
+		}
+		sender := &SyslogSender{
+			address:  config.Address,
+			format:   config.Format,
+			hostname: hostname,
+			timeout:  timeout,
+		}
+		if config.Protocol == PROTOCOL_SYSLOG_TLS {
+			tlsConfig, err := buildTlsConfig(config)
+			if err != nil {
+				return nil, err
+			}
+			sender.tlsConfig = tlsConfig
+		}
+		return sender, nil
+	case PROTOCOL_HTTP:
+		if len(config.Url) == 0 {
+			return nil, fmt.Errorf("http url is required for protocol '%s'", config.Protocol)
+		}
+		tlsConfig, err := buildTlsConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return &HttpSender{
+			url:        config.Url,
+			format:     config.Format,
+			authHeader: config.AuthHeader,
+			client: &http.Client{
+				Timeout:   timeout,
+				Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported siem protocol '%s'", config.Protocol)
+	}
+}
+
+func buildTlsConfig(config *SiemExportConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.TlsInsecureSkipVerify}
+	if len(config.TlsCaCertPath) > 0 {
+		caCert, err := ioutil.ReadFile(config.TlsCaCertPath)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if ok := pool.AppendCertsFromPEM(caCert); !ok {
+			return nil, fmt.Errorf("no valid certificate found in '%s'", config.TlsCaCertPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// SyslogSender writes events as RFC 5424 messages over a tcp or tls connection which is kept open across batches
+// and dialed again after a failure
+type SyslogSender struct {
+	address   string
+	format    string
+	hostname  string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+	conn      net.Conn
+}
+
+func (impl *SyslogSender) Send(events []*SiemEvent) error {
+	var payload []byte
+	for _, event := range events {
+		msg, err := formatEvent(impl.format, event)
+		if err != nil {
+			return err
+		}
+		payload = append(payload, formatSyslog(impl.hostname, event, msg)...)
+	}
+	if impl.conn == nil {
+		conn, err := impl.dial()
+		if err != nil {
+			return err
+		}
+		impl.conn = conn
+	}
+	err := impl.conn.SetWriteDeadline(time.Now().Add(impl.timeout))
+	if err == nil {
+		_, err = impl.conn.Write(payload)
+	}
+	if err != nil {
+		impl.Close()
+		return err
+	}
+	return nil
+}
+
+func (impl *SyslogSender) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: impl.timeout}
+	if impl.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", impl.address, impl.tlsConfig)
+	}
+	return dialer.Dial("tcp", impl.address)
+}
+
+func (impl *SyslogSender) Close() {
+	if impl.conn != nil {
+		impl.conn.Close()
+		impl.conn = nil
+	}
+}
+
+// HttpSender posts a batch of events in a request, json events as an array and cef events as lines
+type HttpSender struct {
+	url        string
+	format     string
+	authHeader string
+	client     *http.Client
+}
+
+func (impl *HttpSender) Send(events []*SiemEvent) error {
+	body, contentType, err := formatHttpBatch(impl.format, events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, impl.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if len(impl.authHeader) > 0 {
+		req.Header.Set("Authorization", impl.authHeader)
+	}
+	res, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		resBody, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("siem responded with status %d: %s", res.StatusCode, string(resBody))
+	}
+	return nil
+}
+
+func (impl *HttpSender) Close() {
+	impl.client.CloseIdleConnections()
+}
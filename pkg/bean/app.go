@@ -101,6 +101,7 @@ type CiPipeline struct {
 	LinkedCount              int                    `json:"linkedCount"`
 	PipelineType             PipelineType           `json:"pipelineType,omitempty"`
 	ScanEnabled              bool                   `json:"scanEnabled,notnull"`
+	ImageSigningEnabled      bool                   `json:"imageSigningEnabled"`
 	AppWorkflowId            int                    `json:"appWorkflowId,omitempty"`
 	PreBuildStage            *bean.PipelineStageDto `json:"preBuildStage,omitempty"`
 	PostBuildStage           *bean.PipelineStageDto `json:"postBuildStage,omitempty"`
@@ -540,12 +541,15 @@ type CiArtifactBean struct {
 	IsVulnerable                  bool            `json:"vulnerable,notnull"`
 	ScanEnabled                   bool            `json:"scanEnabled,notnull"`
 	Scanned                       bool            `json:"scanned,notnull"`
+	PromotionBlocked              bool            `json:"promotionBlocked,omitempty"`
+	PromotionBlockedReasons       []string        `json:"promotionBlockedReasons,omitempty"`
 }
 
 type CiArtifactResponse struct {
 	//AppId           int      `json:"app_id"`
-	CdPipelineId int              `json:"cd_pipeline_id,notnull"`
-	CiArtifacts  []CiArtifactBean `json:"ci_artifacts,notnull"`
+	CdPipelineId       int              `json:"cd_pipeline_id,notnull"`
+	CiArtifacts        []CiArtifactBean `json:"ci_artifacts,notnull"`
+	BlockedCiArtifacts []CiArtifactBean `json:"blocked_ci_artifacts,omitempty"`
 }
 
 type AppLabelsDto struct {
@@ -370,7 +370,8 @@ func (impl ChartServiceImpl) Create(templateRequest TemplateRequest, ctx context
 	if err != nil {
 		return nil, err
 	}
-	chartLocation := filepath.Join(templateName, version)
+	// apps sharing a gitops repo keep charts of all versions in directory of app
+	chartLocation := util.GetChartLocationInGitOpsRepo(util.GetAppDirInGitOpsRepo(chartMeta.Name, currentLatestChart.ChartLocation), templateName, version)
 	override, err := templateRequest.ValuesOverride.MarshalJSON()
 	if err != nil {
 		return nil, err
@@ -499,7 +500,8 @@ func (impl ChartServiceImpl) CreateChartFromEnvOverride(templateRequest Template
 	if err != nil && pg.ErrNoRows != err {
 		return nil, err
 	}
-	chartLocation := filepath.Join(templateName, version)
+	// apps sharing a gitops repo keep charts of all versions in directory of app
+	chartLocation := util.GetChartLocationInGitOpsRepo(util.GetAppDirInGitOpsRepo(chartMeta.Name, currentLatestChart.ChartLocation), templateName, version)
 	gitRepoUrl := ""
 	if currentLatestChart.Id > 0 {
 		gitRepoUrl = currentLatestChart.GitRepoUrl
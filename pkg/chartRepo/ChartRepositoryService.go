@@ -25,6 +25,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/util"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/ociRegistry"
 	serverEnvConfig "github.com/devtron-labs/devtron/pkg/server/config"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	util2 "github.com/devtron-labs/devtron/pkg/util"
@@ -56,25 +57,28 @@ type ChartRepositoryService interface {
 }
 
 type ChartRepositoryServiceImpl struct {
-	logger          *zap.SugaredLogger
-	repoRepository  chartRepoRepository.ChartRepoRepository
-	K8sUtil         *util.K8sUtil
-	clusterService  cluster.ClusterService
-	aCDAuthConfig   *util2.ACDAuthConfig
-	client          *http.Client
-	serverEnvConfig *serverEnvConfig.ServerEnvConfig
+	logger              *zap.SugaredLogger
+	repoRepository      chartRepoRepository.ChartRepoRepository
+	K8sUtil             *util.K8sUtil
+	clusterService      cluster.ClusterService
+	aCDAuthConfig       *util2.ACDAuthConfig
+	client              *http.Client
+	serverEnvConfig     *serverEnvConfig.ServerEnvConfig
+	ociChartSyncService OCIChartSyncService
 }
 
 func NewChartRepositoryServiceImpl(logger *zap.SugaredLogger, repoRepository chartRepoRepository.ChartRepoRepository, K8sUtil *util.K8sUtil, clusterService cluster.ClusterService,
-	aCDAuthConfig *util2.ACDAuthConfig, client *http.Client, serverEnvConfig *serverEnvConfig.ServerEnvConfig) *ChartRepositoryServiceImpl {
+	aCDAuthConfig *util2.ACDAuthConfig, client *http.Client, serverEnvConfig *serverEnvConfig.ServerEnvConfig,
+	ociChartSyncService OCIChartSyncService) *ChartRepositoryServiceImpl {
 	return &ChartRepositoryServiceImpl{
-		logger:          logger,
-		repoRepository:  repoRepository,
-		K8sUtil:         K8sUtil,
-		clusterService:  clusterService,
-		aCDAuthConfig:   aCDAuthConfig,
-		client:          client,
-		serverEnvConfig: serverEnvConfig,
+		logger:              logger,
+		repoRepository:      repoRepository,
+		K8sUtil:             K8sUtil,
+		clusterService:      clusterService,
+		aCDAuthConfig:       aCDAuthConfig,
+		client:              client,
+		serverEnvConfig:     serverEnvConfig,
+		ociChartSyncService: ociChartSyncService,
 	}
 }
 
@@ -99,10 +103,22 @@ func (impl *ChartRepositoryServiceImpl) CreateChartRepo(request *ChartRepoDto) (
 	chartRepo.Active = true
 	chartRepo.Default = false
 	chartRepo.External = true
+	err = impl.setOCIRegistryDetails(chartRepo, request)
+	if err != nil {
+		return nil, err
+	}
 	err = impl.repoRepository.Save(chartRepo, tx)
 	if err != nil && !util.IsErrNoRows(err) {
 		return nil, err
 	}
+	if chartRepo.IsOCIRegistry {
+		// argocd is not configured with oci registries, their charts are installed through helm
+		err = tx.Commit()
+		if err != nil {
+			return nil, err
+		}
+		return chartRepo, nil
+	}
 
 	clusterBean, err := impl.clusterService.FindOne(cluster.DefaultClusterName)
 	if err != nil {
@@ -172,10 +188,21 @@ func (impl *ChartRepositoryServiceImpl) UpdateChartRepo(request *ChartRepoDto) (
 	chartRepo.Active = request.Active
 	chartRepo.UpdatedBy = request.UserId
 	chartRepo.UpdatedOn = time.Now()
+	err = impl.setOCIRegistryDetails(chartRepo, request)
+	if err != nil {
+		return nil, err
+	}
 	err = impl.repoRepository.Update(chartRepo, tx)
 	if err != nil && !util.IsErrNoRows(err) {
 		return nil, err
 	}
+	if chartRepo.IsOCIRegistry {
+		err = tx.Commit()
+		if err != nil {
+			return nil, err
+		}
+		return chartRepo, nil
+	}
 
 	// modify configmap
 	clusterBean, err := impl.clusterService.FindOne(cluster.DefaultClusterName)
@@ -253,9 +280,34 @@ func (impl *ChartRepositoryServiceImpl) convertFromDbResponse(model *chartRepoRe
 	chartRepo.AccessToken = model.AccessToken
 	chartRepo.Default = model.Default
 	chartRepo.Active = model.Active
+	chartRepo.IsOCIRegistry = model.IsOCIRegistry
+	chartRepo.DockerArtifactStoreId = model.DockerArtifactStoreId
+	chartRepo.OCIRepositories = ociRegistry.SplitRepositories(model.OCIRepositories)
 	return chartRepo
 }
 
+// setOCIRegistryDetails sets registry and repositories of oci chart repo, url is of the registry as credentials of
+// container registry are used for the charts
+func (impl *ChartRepositoryServiceImpl) setOCIRegistryDetails(chartRepo *chartRepoRepository.ChartRepo, request *ChartRepoDto) error {
+	chartRepo.IsOCIRegistry = request.IsOCIRegistry
+	if !request.IsOCIRegistry {
+		chartRepo.DockerArtifactStoreId = ""
+		chartRepo.OCIRepositories = ""
+		return nil
+	}
+	chartRepoUrl, err := impl.ociChartSyncService.GetOCIChartRepoUrl(request.DockerArtifactStoreId)
+	if err != nil {
+		return err
+	}
+	chartRepo.Url = chartRepoUrl
+	chartRepo.AuthMode = repository.AUTH_MODE_ANONYMOUS
+	chartRepo.UserName = ""
+	chartRepo.Password = ""
+	chartRepo.DockerArtifactStoreId = request.DockerArtifactStoreId
+	chartRepo.OCIRepositories = strings.Join(ociRegistry.SplitRepositories(strings.Join(request.OCIRepositories, ",")), ",")
+	return nil
+}
+
 func (impl *ChartRepositoryServiceImpl) GetChartRepoList() ([]*ChartRepoDto, error) {
 	var chartRepos []*ChartRepoDto
 	models, err := impl.repoRepository.FindAll()
@@ -274,12 +326,18 @@ func (impl *ChartRepositoryServiceImpl) GetChartRepoList() ([]*ChartRepoDto, err
 		chartRepo.AccessToken = model.AccessToken
 		chartRepo.Default = model.Default
 		chartRepo.Active = model.Active
+		chartRepo.IsOCIRegistry = model.IsOCIRegistry
+		chartRepo.DockerArtifactStoreId = model.DockerArtifactStoreId
+		chartRepo.OCIRepositories = ociRegistry.SplitRepositories(model.OCIRepositories)
 		chartRepos = append(chartRepos, chartRepo)
 	}
 	return chartRepos, nil
 }
 
 func (impl *ChartRepositoryServiceImpl) ValidateChartRepo(request *ChartRepoDto) *DetailedErrorHelmRepoValidation {
+	if request.IsOCIRegistry {
+		return impl.ociChartSyncService.ValidateOCIChartRepo(request)
+	}
 	var detailedErrorHelmRepoValidation DetailedErrorHelmRepoValidation
 	helmRepoConfig := &repo.Entry{
 		Name:     request.Name,
@@ -340,7 +398,7 @@ func (impl *ChartRepositoryServiceImpl) ValidateAndCreateChartRepo(request *Char
 	}
 
 	// Trigger chart sync job, ignore error
-	err = impl.TriggerChartSyncManual()
+	err = impl.triggerChartSync(chartRepo)
 	if err != nil {
 		impl.logger.Errorw("Error in triggering chart sync job manually ", "err", err)
 	}
@@ -359,7 +417,7 @@ func (impl *ChartRepositoryServiceImpl) ValidateAndUpdateChartRepo(request *Char
 	}
 
 	// Trigger chart sync job, ignore error
-	err = impl.TriggerChartSyncManual()
+	err = impl.triggerChartSync(chartRepo)
 	if err != nil {
 		impl.logger.Errorw("Error in triggering chart sync job manually", "err", err)
 	}
@@ -367,6 +425,14 @@ func (impl *ChartRepositoryServiceImpl) ValidateAndUpdateChartRepo(request *Char
 	return chartRepo, err, validationResult
 }
 
+// triggerChartSync syncs charts of oci registries in place, index.yaml repos are synced by the chart sync job
+func (impl *ChartRepositoryServiceImpl) triggerChartSync(chartRepo *chartRepoRepository.ChartRepo) error {
+	if chartRepo.IsOCIRegistry {
+		return impl.ociChartSyncService.SyncOCIChartRepo(chartRepo)
+	}
+	return impl.TriggerChartSyncManual()
+}
+
 func (impl *ChartRepositoryServiceImpl) TriggerChartSyncManual() error {
 	defaultClusterBean, err := impl.clusterService.FindOne(cluster.DefaultClusterName)
 	if err != nil {
@@ -35,6 +35,9 @@ func (impl ChartRepoRepositoryImplMock) GetConnection() *pg.DB {
 func (impl ChartRepoRepositoryImplMock) MarkChartRepoDeleted(chartRepo *chartRepoRepository.ChartRepo, tx *pg.Tx) error {
 	panic("implement me")
 }
+func (impl ChartRepoRepositoryImplMock) FindAllOCIRegistries() ([]*chartRepoRepository.ChartRepo, error) {
+	panic("implement me")
+}
 
 //----------
 type ClusterServiceImplMock struct {
@@ -0,0 +1,282 @@
+package chartRepo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/ociRegistry"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/ghodss/yaml"
+	"go.uber.org/zap"
+)
+
+type OCIChartSyncService interface {
+	// SyncOCIChartRepos adds versions of charts pushed to oci registries since last sync to app store
+	SyncOCIChartRepos() error
+	SyncOCIChartRepo(chartRepo *chartRepoRepository.ChartRepo) error
+	ValidateOCIChartRepo(request *ChartRepoDto) *DetailedErrorHelmRepoValidation
+	GetOCIChartRepoUrl(dockerArtifactStoreId string) (string, error)
+}
+
+type OCIChartSyncServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	chartRepoRepository                  chartRepoRepository.ChartRepoRepository
+	dockerArtifactStoreRepository        repository.DockerArtifactStoreRepository
+	appStoreRepository                   appStoreDiscoverRepository.AppStoreRepository
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
+	client                               *http.Client
+}
+
+func NewOCIChartSyncServiceImpl(logger *zap.SugaredLogger, chartRepoRepository chartRepoRepository.ChartRepoRepository,
+	dockerArtifactStoreRepository repository.DockerArtifactStoreRepository, appStoreRepository appStoreDiscoverRepository.AppStoreRepository,
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository, client *http.Client) *OCIChartSyncServiceImpl {
+	return &OCIChartSyncServiceImpl{
+		logger:                               logger,
+		chartRepoRepository:                  chartRepoRepository,
+		dockerArtifactStoreRepository:        dockerArtifactStoreRepository,
+		appStoreRepository:                   appStoreRepository,
+		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
+		client:                               client,
+	}
+}
+
+func (impl *OCIChartSyncServiceImpl) SyncOCIChartRepos() error {
+	chartRepos, err := impl.chartRepoRepository.FindAllOCIRegistries()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching oci chart repos", "err", err)
+		return err
+	}
+	for _, chartRepo := range chartRepos {
+		err = impl.SyncOCIChartRepo(chartRepo)
+		if err != nil {
+			impl.logger.Errorw("error in syncing oci chart repo", "chartRepo", chartRepo.Name, "err", err)
+		}
+	}
+	return nil
+}
+
+func (impl *OCIChartSyncServiceImpl) SyncOCIChartRepo(chartRepo *chartRepoRepository.ChartRepo) error {
+	client, registry, err := impl.getRegistryClient(chartRepo.DockerArtifactStoreId)
+	if err != nil {
+		return err
+	}
+	var syncErr error
+	for _, ociRepository := range ociRegistry.SplitRepositories(chartRepo.OCIRepositories) {
+		reference := &ociRegistry.Reference{Registry: registry, Repository: ociRepository}
+		err = impl.syncChart(client, reference, chartRepo)
+		if err != nil {
+			impl.logger.Errorw("error in syncing chart of oci repository", "chartRepo", chartRepo.Name, "repository", ociRepository, "err", err)
+			syncErr = err
+		}
+	}
+	return syncErr
+}
+
+// syncChart adds tags of repository which are not in app store yet as versions of the chart and marks the highest
+// version as latest, tags which are not semver like latest are skipped
+func (impl *OCIChartSyncServiceImpl) syncChart(client *ociRegistry.Client, reference *ociRegistry.Reference, chartRepo *chartRepoRepository.ChartRepo) error {
+	tags, err := client.ListTags(reference)
+	if err != nil {
+		return err
+	}
+	appStore, err := impl.getOrCreateAppStore(path.Base(reference.Repository), chartRepo.Id)
+	if err != nil {
+		return err
+	}
+	existingVersions, err := impl.appStoreApplicationVersionRepository.FindChartVersionByAppStoreId(appStore.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		return err
+	}
+	versionIds := make(map[string]int)
+	for _, existingVersion := range existingVersions {
+		versionIds[existingVersion.Version] = existingVersion.Id
+	}
+	added := false
+	for _, tag := range tags {
+		// helm pushes build metadata of version with _ as + is not allowed in tags
+		version := strings.ReplaceAll(tag, "_", "+")
+		if _, err := semver.StrictNewVersion(version); err != nil {
+			continue
+		}
+		if _, ok := versionIds[version]; ok {
+			continue
+		}
+		chart, err := client.GetHelmChart(reference, tag)
+		if err != nil {
+			impl.logger.Errorw("error in fetching chart from registry", "repository", reference.Repository, "tag", tag, "err", err)
+			continue
+		}
+		if chart == nil {
+			continue
+		}
+		appStoreApplicationVersion, err := buildAppStoreApplicationVersion(chart, appStore.Id, version)
+		if err != nil {
+			impl.logger.Errorw("error in reading chart", "repository", reference.Repository, "tag", tag, "err", err)
+			continue
+		}
+		err = impl.appStoreApplicationVersionRepository.Save(appStoreApplicationVersion)
+		if err != nil {
+			return err
+		}
+		versionIds[version] = appStoreApplicationVersion.Id
+		added = true
+	}
+	if !added {
+		return nil
+	}
+	latestVersion := getLatestVersion(versionIds)
+	return impl.appStoreApplicationVersionRepository.MarkLatestVersion(appStore.Id, versionIds[latestVersion])
+}
+
+func (impl *OCIChartSyncServiceImpl) getOrCreateAppStore(name string, chartRepoId int) (*appStoreDiscoverRepository.AppStore, error) {
+	appStore, err := impl.appStoreRepository.FindByNameAndChartRepoId(name, chartRepoId)
+	if err == nil {
+		return appStore, nil
+	}
+	if !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	appStore = &appStoreDiscoverRepository.AppStore{
+		Name:        name,
+		ChartRepoId: chartRepoId,
+		Active:      true,
+		CreatedOn:   time.Now(),
+		UpdatedOn:   time.Now(),
+	}
+	err = impl.appStoreRepository.Save(appStore)
+	return appStore, err
+}
+
+func buildAppStoreApplicationVersion(chart *ociRegistry.HelmChart, appStoreId int, version string) (*appStoreDiscoverRepository.AppStoreApplicationVersion, error) {
+	chartYaml, err := yaml.YAMLToJSON(chart.ChartYaml)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &struct {
+		Name        string   `json:"name"`
+		AppVersion  string   `json:"appVersion"`
+		Description string   `json:"description"`
+		Deprecated  bool     `json:"deprecated"`
+		Icon        string   `json:"icon"`
+		Home        string   `json:"home"`
+		Sources     []string `json:"sources"`
+	}{}
+	err = json.Unmarshal(chartYaml, metadata)
+	if err != nil {
+		return nil, err
+	}
+	valuesYaml, err := yaml.YAMLToJSON(chart.ValuesYaml)
+	if err != nil {
+		return nil, err
+	}
+	if len(valuesYaml) == 0 || string(valuesYaml) == "null" {
+		valuesYaml = []byte("{}")
+	}
+	appStoreApplicationVersion := &appStoreDiscoverRepository.AppStoreApplicationVersion{
+		Version:          version,
+		AppVersion:       metadata.AppVersion,
+		Created:          time.Now(),
+		Deprecated:       metadata.Deprecated,
+		Description:      metadata.Description,
+		Digest:           chart.Digest,
+		Icon:             metadata.Icon,
+		Name:             metadata.Name,
+		Home:             metadata.Home,
+		ValuesYaml:       string(valuesYaml),
+		ChartYaml:        string(chartYaml),
+		AppStoreId:       appStoreId,
+		RawValues:        string(chart.ValuesYaml),
+		Readme:           string(chart.Readme),
+		ValuesSchemaJson: string(chart.ValuesSchemaJson),
+		Notes:            string(chart.Notes),
+		AuditLog:         sql.AuditLog{CreatedOn: time.Now(), UpdatedOn: time.Now(), CreatedBy: 1, UpdatedBy: 1},
+	}
+	if len(metadata.Sources) > 0 {
+		appStoreApplicationVersion.Source = metadata.Sources[0]
+	}
+	return appStoreApplicationVersion, nil
+}
+
+// getLatestVersion returns the highest semver of versions, pre releases are latest only when there is no release
+func getLatestVersion(versionIds map[string]int) string {
+	var latest, latestPrerelease *semver.Version
+	var latestVersion, latestPrereleaseVersion string
+	for version := range versionIds {
+		parsed, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if len(parsed.Prerelease()) > 0 {
+			if latestPrerelease == nil || parsed.GreaterThan(latestPrerelease) {
+				latestPrerelease, latestPrereleaseVersion = parsed, version
+			}
+		} else if latest == nil || parsed.GreaterThan(latest) {
+			latest, latestVersion = parsed, version
+		}
+	}
+	if latest == nil {
+		return latestPrereleaseVersion
+	}
+	return latestVersion
+}
+
+func (impl *OCIChartSyncServiceImpl) ValidateOCIChartRepo(request *ChartRepoDto) *DetailedErrorHelmRepoValidation {
+	detailedErrorHelmRepoValidation := &DetailedErrorHelmRepoValidation{}
+	repositories := ociRegistry.SplitRepositories(strings.Join(request.OCIRepositories, ","))
+	if len(repositories) == 0 {
+		detailedErrorHelmRepoValidation.ActualErrMsg = "no repositories in request"
+		detailedErrorHelmRepoValidation.CustomErrMsg = "Please add repositories of charts in the registry."
+		return detailedErrorHelmRepoValidation
+	}
+	client, registry, err := impl.getRegistryClient(request.DockerArtifactStoreId)
+	if err != nil {
+		detailedErrorHelmRepoValidation.ActualErrMsg = err.Error()
+		detailedErrorHelmRepoValidation.CustomErrMsg = "Could not get credentials of the registry. Please verify the container registry."
+		return detailedErrorHelmRepoValidation
+	}
+	for _, ociRepository := range repositories {
+		_, err = client.ListTags(&ociRegistry.Reference{Registry: registry, Repository: ociRepository})
+		if err != nil {
+			impl.logger.Errorw("error in listing tags of oci repository", "registry", registry, "repository", ociRepository, "err", err)
+			detailedErrorHelmRepoValidation.ActualErrMsg = err.Error()
+			detailedErrorHelmRepoValidation.CustomErrMsg = fmt.Sprintf("Could not list charts of repository %s. Please verify the repository and credentials.", ociRepository)
+			return detailedErrorHelmRepoValidation
+		}
+	}
+	detailedErrorHelmRepoValidation.CustomErrMsg = ValidationSuccessMsg
+	return detailedErrorHelmRepoValidation
+}
+
+func (impl *OCIChartSyncServiceImpl) GetOCIChartRepoUrl(dockerArtifactStoreId string) (string, error) {
+	store, err := impl.dockerArtifactStoreRepository.FindOne(dockerArtifactStoreId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching container registry", "id", dockerArtifactStoreId, "err", err)
+		return "", err
+	}
+	return ociRegistry.OCIScheme + ociRegistry.RegistryHost(store.RegistryURL), nil
+}
+
+// getRegistryClient returns client with credentials of container registry and host of registry used in references
+func (impl *OCIChartSyncServiceImpl) getRegistryClient(dockerArtifactStoreId string) (*ociRegistry.Client, string, error) {
+	store, err := impl.dockerArtifactStoreRepository.FindOne(dockerArtifactStoreId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching container registry", "id", dockerArtifactStoreId, "err", err)
+		return nil, "", err
+	}
+	credential, err := ociRegistry.GetCredential(store)
+	if err != nil {
+		impl.logger.Errorw("error in getting credentials of container registry", "id", dockerArtifactStoreId, "err", err)
+		return nil, "", err
+	}
+	client := ociRegistry.NewClient(impl.client, credential, ociRegistry.IsPlainHttp(store.RegistryURL))
+	return client, ociRegistry.RegistryHost(store.RegistryURL), nil
+}
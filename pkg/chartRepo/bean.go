@@ -16,6 +16,10 @@ type ChartRepoDto struct {
 	Active      bool                `json:"active"`
 	Default     bool                `json:"default"`
 	UserId      int32               `json:"-"`
+	// oci registry chart repos read charts of OCIRepositories from registry of DockerArtifactStoreId
+	IsOCIRegistry         bool     `json:"isOCIRegistry"`
+	DockerArtifactStoreId string   `json:"dockerArtifactStoreId,omitempty"`
+	OCIRepositories       []string `json:"ociRepositories,omitempty"`
 }
 
 type DetailedErrorHelmRepoValidation struct {
@@ -68,6 +68,8 @@ type ChartRepository interface {
 	FindPreviousChartByAppId(appId int) (chart *Chart, err error)
 	FindNumberOfAppsWithDeploymentTemplate(appIds []int) (int, error)
 	FindChartByGitRepoUrl(gitRepoUrl string) (*Chart, error)
+	// UpdateAll updates charts in a transaction, none of them is updated on error
+	UpdateAll(charts []*Chart) error
 }
 
 func NewChartRepository(dbConnection *pg.DB) *ChartRepositoryImpl {
@@ -191,6 +193,22 @@ func (repositoryImpl ChartRepositoryImpl) Update(chart *Chart) error {
 	return err
 }
 
+func (repositoryImpl ChartRepositoryImpl) UpdateAll(charts []*Chart) error {
+	tx, err := repositoryImpl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	for _, chart := range charts {
+		_, err = tx.Model(chart).WherePK().UpdateNotNull()
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (repositoryImpl ChartRepositoryImpl) FindById(id int) (chart *Chart, err error) {
 	chart = &Chart{}
 	err = repositoryImpl.dbConnection.Model(chart).
@@ -240,6 +258,10 @@ type ChartRepo struct {
 	AuthMode    repository.AuthMode `sql:"auth_mode,notnull"`
 	External    bool                `sql:"external,notnull"`
 	Deleted     bool                `sql:"deleted,notnull"`
+	// oci registry chart repos have charts in repositories of a docker registry instead of an index.yaml
+	IsOCIRegistry         bool   `sql:"is_oci_registry,notnull"`
+	DockerArtifactStoreId string `sql:"docker_artifact_store_id"`
+	OCIRepositories       string `sql:"oci_repositories"` //comma separated repositories of charts in registry
 	sql.AuditLog
 }
 
@@ -252,6 +274,7 @@ type ChartRepoRepository interface {
 	GetConnection() *pg.DB
 	MarkChartRepoDeleted(chartRepo *ChartRepo, tx *pg.Tx) error
 	FindByName(name string) (*ChartRepo, error)
+	FindAllOCIRegistries() ([]*ChartRepo, error)
 }
 type ChartRepoRepositoryImpl struct {
 	dbConnection *pg.DB
@@ -316,6 +339,16 @@ func (impl ChartRepoRepositoryImpl) FindByName(name string) (*ChartRepo, error)
 	return repo, err
 }
 
+func (impl ChartRepoRepositoryImpl) FindAllOCIRegistries() ([]*ChartRepo, error) {
+	var repo []*ChartRepo
+	err := impl.dbConnection.Model(&repo).
+		Where("is_oci_registry = ?", true).
+		Where("active = ?", true).
+		Where("deleted = ?", false).
+		Select()
+	return repo, err
+}
+
 // ------------------------ CHART REF REPOSITORY ---------------
 type RefChartDir string
 type ChartRef struct {
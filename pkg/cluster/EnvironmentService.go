@@ -43,6 +43,7 @@ type EnvironmentBean struct {
 	Namespace             string `json:"namespace,omitempty" validate:"max=50"`
 	CdArgoSetup           bool   `json:"isClusterCdActive"`
 	EnvironmentIdentifier string `json:"environmentIdentifier"`
+	GitOpsPullRequest     bool   `json:"gitOpsPullRequest"`
 }
 
 type EnvDto struct {
@@ -137,6 +138,7 @@ func (impl EnvironmentServiceImpl) Create(mappings *EnvironmentBean, userId int3
 		Namespace:             mappings.Namespace,
 		Default:               mappings.Default,
 		EnvironmentIdentifier: identifier,
+		GitOpsPullRequest:     mappings.GitOpsPullRequest,
 	}
 	model.CreatedBy = userId
 	model.UpdatedBy = userId
@@ -183,6 +185,7 @@ func (impl EnvironmentServiceImpl) FindOne(environment string) (*EnvironmentBean
 		PrometheusEndpoint:    model.Cluster.PrometheusEndpoint,
 		Namespace:             model.Namespace,
 		Default:               model.Default,
+		GitOpsPullRequest:     model.GitOpsPullRequest,
 		EnvironmentIdentifier: model.EnvironmentIdentifier,
 	}
 	return bean, nil
@@ -204,6 +207,7 @@ func (impl EnvironmentServiceImpl) GetAll() ([]EnvironmentBean, error) {
 			PrometheusEndpoint:    model.Cluster.PrometheusEndpoint,
 			Namespace:             model.Namespace,
 			Default:               model.Default,
+			GitOpsPullRequest:     model.GitOpsPullRequest,
 			CdArgoSetup:           model.Cluster.CdArgoSetup,
 			EnvironmentIdentifier: model.EnvironmentIdentifier,
 		})
@@ -227,6 +231,7 @@ func (impl EnvironmentServiceImpl) GetAllActive() ([]EnvironmentBean, error) {
 			PrometheusEndpoint:    model.Cluster.PrometheusEndpoint,
 			Namespace:             model.Namespace,
 			Default:               model.Default,
+			GitOpsPullRequest:     model.GitOpsPullRequest,
 			EnvironmentIdentifier: model.EnvironmentIdentifier,
 		})
 	}
@@ -247,6 +252,7 @@ func (impl EnvironmentServiceImpl) FindById(id int) (*EnvironmentBean, error) {
 		PrometheusEndpoint:    model.Cluster.PrometheusEndpoint,
 		Namespace:             model.Namespace,
 		Default:               model.Default,
+		GitOpsPullRequest:     model.GitOpsPullRequest,
 		EnvironmentIdentifier: model.EnvironmentIdentifier,
 	}
 
@@ -278,6 +284,7 @@ func (impl EnvironmentServiceImpl) Update(mappings *EnvironmentBean, userId int3
 	model.Active = mappings.Active
 	model.Namespace = mappings.Namespace
 	model.Default = mappings.Default
+	model.GitOpsPullRequest = mappings.GitOpsPullRequest
 	model.UpdatedBy = userId
 	model.UpdatedOn = time.Now()
 
@@ -392,6 +399,7 @@ func (impl EnvironmentServiceImpl) FindByIds(ids []*int) ([]*EnvironmentBean, er
 			Active:                model.Active,
 			Namespace:             model.Namespace,
 			Default:               model.Default,
+			GitOpsPullRequest:     model.GitOpsPullRequest,
 			EnvironmentIdentifier: model.EnvironmentIdentifier,
 		})
 	}
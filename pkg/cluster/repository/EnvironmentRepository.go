@@ -34,6 +34,7 @@ type Environment struct {
 	GrafanaDatasourceId   int    `sql:"grafana_datasource_id"`
 	Namespace             string `sql:"namespace"`
 	EnvironmentIdentifier string `sql:"environment_identifier"`
+	GitOpsPullRequest     bool   `sql:"gitops_pull_request,notnull"`
 	sql.AuditLog
 }
 
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"net/http"
 	"strings"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"go.uber.org/zap"
 )
@@ -58,9 +60,11 @@ type EnvironmentAppListForDG struct {
 }
 
 type DeploymentGroupTriggerRequest struct {
-	DeploymentGroupId int   `json:"deploymentGroupId"`
-	UserId            int32 `json:"userId"`
-	CiArtifactId      int   `json:"ciArtifactId"`
+	DeploymentGroupId        int    `json:"deploymentGroupId"`
+	UserId                   int32  `json:"userId"`
+	CiArtifactId             int    `json:"ciArtifactId"`
+	OverrideDeploymentWindow bool   `json:"overrideDeploymentWindow"`
+	OverrideReason           string `json:"overrideReason"`
 }
 
 type DeploymentGroupHibernateRequest struct {
@@ -111,6 +115,7 @@ type DeploymentGroupServiceImpl struct {
 	ciArtifactRepository         repository.CiArtifactRepository
 	appWorkflowRepository        appWorkflow.AppWorkflowRepository
 	workflowDagExecutor          pipeline.WorkflowDagExecutor
+	deploymentWindowService      deploymentWindow.DeploymentWindowService
 }
 
 func NewDeploymentGroupServiceImpl(appRepository app.AppRepository, logger *zap.SugaredLogger,
@@ -119,7 +124,8 @@ func NewDeploymentGroupServiceImpl(appRepository app.AppRepository, logger *zap.
 	deploymentGroupAppRepository repository.DeploymentGroupAppRepository,
 	ciArtifactRepository repository.CiArtifactRepository,
 	appWorkflowRepository appWorkflow.AppWorkflowRepository,
-	workflowDagExecutor pipeline.WorkflowDagExecutor) *DeploymentGroupServiceImpl {
+	workflowDagExecutor pipeline.WorkflowDagExecutor,
+	deploymentWindowService deploymentWindow.DeploymentWindowService) *DeploymentGroupServiceImpl {
 	return &DeploymentGroupServiceImpl{
 		appRepository:                appRepository,
 		logger:                       logger,
@@ -131,6 +137,7 @@ func NewDeploymentGroupServiceImpl(appRepository app.AppRepository, logger *zap.
 		ciArtifactRepository:         ciArtifactRepository,
 		appWorkflowRepository:        appWorkflowRepository,
 		workflowDagExecutor:          workflowDagExecutor,
+		deploymentWindowService:      deploymentWindowService,
 	}
 }
 
@@ -488,7 +495,17 @@ func (impl *DeploymentGroupServiceImpl) TriggerReleaseForDeploymentGroup(trigger
 	}
 	for _, cdPipeline := range cdPipelines {
 		if val, ok := ciArtefactMapping[cdPipeline.CiPipelineId]; ok {
-			//do something here
+			//pipelines still frozen after override are recorded as blocked on bulk trigger, so are the pipelines which
+			//user is not allowed to override instead of failing the whole release
+			if triggerRequest.OverrideDeploymentWindow {
+				err = impl.deploymentWindowService.OverrideDeploymentWindow(cdPipeline, val.Id, triggerRequest.UserId, triggerRequest.OverrideReason)
+				if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode == http.StatusForbidden {
+					impl.logger.Warnw("deployment window override not allowed, pipeline remains frozen", "pipelineId", cdPipeline.Id, "userId", triggerRequest.UserId)
+				} else if err != nil {
+					impl.logger.Errorw("error in overriding deployment window", "err", err, "pipelineId", cdPipeline.Id)
+					return nil, err
+				}
+			}
 			req := &pipeline.BulkTriggerRequest{
 				CiArtifactId: val.Id,
 				PipelineId:   cdPipeline.Id,
@@ -500,11 +517,11 @@ func (impl *DeploymentGroupServiceImpl) TriggerReleaseForDeploymentGroup(trigger
 	}
 	//trigger
 	// apply mapping
-	_, err = impl.workflowDagExecutor.TriggerBulkDeploymentAsync(requests, triggerRequest.UserId)
+	responses, err := impl.workflowDagExecutor.TriggerBulkDeploymentAsync(requests, triggerRequest.UserId)
 	if err != nil {
 		return nil, err
 	}
-	return nil, nil
+	return responses, nil
 }
 
 func (impl *DeploymentGroupServiceImpl) UpdateDeploymentGroup(deploymentGroupRequest *DeploymentGroupRequest) (*DeploymentGroupRequest, error) {
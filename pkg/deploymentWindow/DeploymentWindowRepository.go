@@ -0,0 +1,127 @@
+package deploymentWindow
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type WindowType string
+
+const (
+	WINDOW_TYPE_CRON  WindowType = "CRON"
+	WINDOW_TYPE_RANGE WindowType = "RANGE"
+)
+
+type DeploymentWindow struct {
+	tableName         struct{}   `sql:"deployment_window" pg:",discard_unknown_columns"`
+	Id                int        `sql:"id,pk"`
+	Name              string     `sql:"name,notnull"`
+	Description       string     `sql:"description"`
+	WindowType        WindowType `sql:"window_type,notnull"`
+	CronExpression    string     `sql:"cron_expression"`
+	DurationInMinutes int        `sql:"duration_in_minutes"`
+	StartTime         time.Time  `sql:"start_time"`
+	EndTime           time.Time  `sql:"end_time"`
+	Timezone          string     `sql:"timezone,notnull"`
+	ClusterId         int        `sql:"cluster_id"`
+	EnvironmentId     int        `sql:"environment_id"`
+	AppId             int        `sql:"app_id"`
+	Active            bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentWindowOverride struct {
+	tableName          struct{} `sql:"deployment_window_override" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	DeploymentWindowId int      `sql:"deployment_window_id,notnull"`
+	PipelineId         int      `sql:"pipeline_id,notnull"`
+	CiArtifactId       int      `sql:"ci_artifact_id,notnull"`
+	UserId             int32    `sql:"user_id,notnull"`
+	Reason             string   `sql:"reason"`
+	sql.AuditLog
+}
+
+type DeploymentWindowRepository interface {
+	Save(window *DeploymentWindow) error
+	Update(window *DeploymentWindow) error
+	FindById(id int) (*DeploymentWindow, error)
+	FindAllActive() ([]*DeploymentWindow, error)
+	FindActiveByScope(clusterId int, environmentId int, appId int) ([]*DeploymentWindow, error)
+	SaveOverride(override *DeploymentWindowOverride) error
+	FindOverridesAfter(windowId int, pipelineId int, ciArtifactId int, after time.Time) ([]*DeploymentWindowOverride, error)
+	FindOverridesByWindowId(windowId int) ([]*DeploymentWindowOverride, error)
+}
+
+type DeploymentWindowRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewDeploymentWindowRepositoryImpl(dbConnection *pg.DB) *DeploymentWindowRepositoryImpl {
+	return &DeploymentWindowRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl DeploymentWindowRepositoryImpl) Save(window *DeploymentWindow) error {
+	return impl.dbConnection.Insert(window)
+}
+
+func (impl DeploymentWindowRepositoryImpl) Update(window *DeploymentWindow) error {
+	return impl.dbConnection.Update(window)
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindById(id int) (*DeploymentWindow, error) {
+	window := &DeploymentWindow{}
+	err := impl.dbConnection.Model(window).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return window, err
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindAllActive() ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	err := impl.dbConnection.Model(&windows).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return windows, err
+}
+
+// FindActiveByScope returns the windows applicable to a deployment, a window without cluster, environment or app
+// applies to all of them
+func (impl DeploymentWindowRepositoryImpl) FindActiveByScope(clusterId int, environmentId int, appId int) ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	err := impl.dbConnection.Model(&windows).
+		Where("active = ?", true).
+		Where("(cluster_id IS NULL OR cluster_id = ?)", clusterId).
+		Where("(environment_id IS NULL OR environment_id = ?)", environmentId).
+		Where("(app_id IS NULL OR app_id = ?)", appId).
+		Order("id ASC").
+		Select()
+	return windows, err
+}
+
+func (impl DeploymentWindowRepositoryImpl) SaveOverride(override *DeploymentWindowOverride) error {
+	return impl.dbConnection.Insert(override)
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindOverridesAfter(windowId int, pipelineId int, ciArtifactId int, after time.Time) ([]*DeploymentWindowOverride, error) {
+	var overrides []*DeploymentWindowOverride
+	err := impl.dbConnection.Model(&overrides).
+		Where("deployment_window_id = ?", windowId).
+		Where("pipeline_id = ?", pipelineId).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("created_on >= ?", after).
+		Select()
+	return overrides, err
+}
+
+func (impl DeploymentWindowRepositoryImpl) FindOverridesByWindowId(windowId int) ([]*DeploymentWindowOverride, error) {
+	var overrides []*DeploymentWindowOverride
+	err := impl.dbConnection.Model(&overrides).
+		Where("deployment_window_id = ?", windowId).
+		Order("id DESC").
+		Select()
+	return overrides, err
+}
@@ -0,0 +1,390 @@
+package deploymentWindow
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type DeploymentWindowDto struct {
+	Id                int        `json:"id"`
+	Name              string     `json:"name" validate:"required"`
+	Description       string     `json:"description"`
+	WindowType        WindowType `json:"windowType" validate:"oneof=CRON RANGE"`
+	CronExpression    string     `json:"cronExpression,omitempty"`
+	DurationInMinutes int        `json:"durationInMinutes,omitempty"`
+	StartTime         time.Time  `json:"startTime,omitempty"`
+	EndTime           time.Time  `json:"endTime,omitempty"`
+	Timezone          string     `json:"timezone"`
+	ClusterId         int        `json:"clusterId,omitempty"`
+	EnvironmentId     int        `json:"environmentId,omitempty"`
+	AppId             int        `json:"appId,omitempty"`
+	UserId            int32      `json:"-"`
+}
+
+type DeploymentWindowOverrideDto struct {
+	Id           int       `json:"id"`
+	PipelineId   int       `json:"pipelineId"`
+	CiArtifactId int       `json:"ciArtifactId"`
+	UserId       int32     `json:"userId"`
+	Reason       string    `json:"reason"`
+	CreatedOn    time.Time `json:"createdOn"`
+}
+
+// DeploymentWindowBlock describes the freeze window which currently blocks a deployment
+type DeploymentWindowBlock struct {
+	WindowId   int
+	WindowName string
+	Until      time.Time
+}
+
+func (block *DeploymentWindowBlock) Message() string {
+	return fmt.Sprintf("Deployment blocked by freeze window %s until %s", block.WindowName, block.Until.Format(time.RFC1123))
+}
+
+type DeploymentWindowService interface {
+	Create(request *DeploymentWindowDto) (*DeploymentWindowDto, error)
+	Update(request *DeploymentWindowDto) (*DeploymentWindowDto, error)
+	Delete(id int, userId int32) error
+	GetAll() ([]*DeploymentWindowDto, error)
+	GetById(id int) (*DeploymentWindowDto, error)
+	GetOverrides(windowId int) ([]*DeploymentWindowOverrideDto, error)
+	// CheckDeploymentWindow returns the freeze window blocking deployment of the artifact on the pipeline at this
+	// moment, nil if deployment is allowed
+	CheckDeploymentWindow(pipeline *pipelineConfig.Pipeline, ciArtifactId int) (*DeploymentWindowBlock, error)
+	// OverrideDeploymentWindow lets a super admin deploy the artifact on the pipeline through all freeze windows
+	// active at this moment, every override is recorded for audit and notified
+	OverrideDeploymentWindow(pipeline *pipelineConfig.Pipeline, ciArtifactId int, userId int32, reason string) error
+}
+
+type DeploymentWindowServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	deploymentWindowRepository DeploymentWindowRepository
+	environmentRepository      repository.EnvironmentRepository
+	userService                user.UserService
+	pipelineRepository         pipelineConfig.PipelineRepository
+	eventClient                client.EventClient
+	eventFactory               client.EventFactory
+}
+
+func NewDeploymentWindowServiceImpl(logger *zap.SugaredLogger, deploymentWindowRepository DeploymentWindowRepository,
+	environmentRepository repository.EnvironmentRepository, userService user.UserService,
+	pipelineRepository pipelineConfig.PipelineRepository, eventClient client.EventClient, eventFactory client.EventFactory) *DeploymentWindowServiceImpl {
+	return &DeploymentWindowServiceImpl{
+		logger:                     logger,
+		deploymentWindowRepository: deploymentWindowRepository,
+		environmentRepository:      environmentRepository,
+		userService:                userService,
+		pipelineRepository:         pipelineRepository,
+		eventClient:                eventClient,
+		eventFactory:               eventFactory,
+	}
+}
+
+func (impl DeploymentWindowServiceImpl) Create(request *DeploymentWindowDto) (*DeploymentWindowDto, error) {
+	err := validateDeploymentWindow(request)
+	if err != nil {
+		return nil, err
+	}
+	window := &DeploymentWindow{Active: true, AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId}}
+	impl.copyDtoToModel(request, window)
+	err = impl.deploymentWindowRepository.Save(window)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window", "err", err, "window", window)
+		return nil, err
+	}
+	request.Id = window.Id
+	return request, nil
+}
+
+func (impl DeploymentWindowServiceImpl) Update(request *DeploymentWindowDto) (*DeploymentWindowDto, error) {
+	err := validateDeploymentWindow(request)
+	if err != nil {
+		return nil, err
+	}
+	window, err := impl.deploymentWindowRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", request.Id)
+		return nil, err
+	}
+	impl.copyDtoToModel(request, window)
+	err = impl.deploymentWindowRepository.Update(window)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment window", "err", err, "window", window)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl DeploymentWindowServiceImpl) Delete(id int, userId int32) error {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", id)
+		return err
+	}
+	window.Active = false
+	window.UpdatedOn = time.Now()
+	window.UpdatedBy = userId
+	return impl.deploymentWindowRepository.Update(window)
+}
+
+func (impl DeploymentWindowServiceImpl) GetAll() ([]*DeploymentWindowDto, error) {
+	windows, err := impl.deploymentWindowRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err)
+		return nil, err
+	}
+	windowDtos := make([]*DeploymentWindowDto, 0, len(windows))
+	for _, window := range windows {
+		windowDtos = append(windowDtos, impl.copyModelToDto(window))
+	}
+	return windowDtos, nil
+}
+
+func (impl DeploymentWindowServiceImpl) GetById(id int) (*DeploymentWindowDto, error) {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "err", err, "id", id)
+		return nil, err
+	}
+	return impl.copyModelToDto(window), nil
+}
+
+func (impl DeploymentWindowServiceImpl) GetOverrides(windowId int) ([]*DeploymentWindowOverrideDto, error) {
+	overrides, err := impl.deploymentWindowRepository.FindOverridesByWindowId(windowId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment window overrides", "err", err, "windowId", windowId)
+		return nil, err
+	}
+	overrideDtos := make([]*DeploymentWindowOverrideDto, 0, len(overrides))
+	for _, override := range overrides {
+		overrideDtos = append(overrideDtos, &DeploymentWindowOverrideDto{
+			Id:           override.Id,
+			PipelineId:   override.PipelineId,
+			CiArtifactId: override.CiArtifactId,
+			UserId:       override.UserId,
+			Reason:       override.Reason,
+			CreatedOn:    override.CreatedOn,
+		})
+	}
+	return overrideDtos, nil
+}
+
+func (impl DeploymentWindowServiceImpl) CheckDeploymentWindow(pipeline *pipelineConfig.Pipeline, ciArtifactId int) (*DeploymentWindowBlock, error) {
+	now := time.Now()
+	activeWindows, err := impl.findActiveWindows(pipeline, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, activeWindow := range activeWindows {
+		overrides, err := impl.deploymentWindowRepository.FindOverridesAfter(activeWindow.window.Id, pipeline.Id, ciArtifactId, activeWindow.start)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching deployment window overrides", "err", err, "windowId", activeWindow.window.Id)
+			return nil, err
+		}
+		if len(overrides) > 0 {
+			impl.logger.Infow("deployment window overridden", "windowId", activeWindow.window.Id, "pipelineId", pipeline.Id, "ciArtifactId", ciArtifactId)
+			continue
+		}
+		return &DeploymentWindowBlock{
+			WindowId:   activeWindow.window.Id,
+			WindowName: activeWindow.window.Name,
+			Until:      activeWindow.end,
+		}, nil
+	}
+	return nil, nil
+}
+
+func (impl DeploymentWindowServiceImpl) OverrideDeploymentWindow(pipeline *pipelineConfig.Pipeline, ciArtifactId int, userId int32, reason string) error {
+	isSuperAdmin, err := impl.userService.IsSuperAdmin(int(userId))
+	if err != nil {
+		impl.logger.Errorw("error in checking super admin", "err", err, "userId", userId)
+		return err
+	}
+	if !isSuperAdmin {
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: "deployment window override by non super admin", UserMessage: "only super admin can override deployment freeze"}
+	}
+	activeWindows, err := impl.findActiveWindows(pipeline, time.Now())
+	if err != nil {
+		return err
+	}
+	var windowNames []string
+	for _, activeWindow := range activeWindows {
+		override := &DeploymentWindowOverride{
+			DeploymentWindowId: activeWindow.window.Id,
+			PipelineId:         pipeline.Id,
+			CiArtifactId:       ciArtifactId,
+			UserId:             userId,
+			Reason:             reason,
+			AuditLog:           sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId, UpdatedOn: time.Now(), UpdatedBy: userId},
+		}
+		err = impl.deploymentWindowRepository.SaveOverride(override)
+		if err != nil {
+			impl.logger.Errorw("error in saving deployment window override", "err", err, "override", override)
+			return err
+		}
+		impl.logger.Infow("deployment window overridden by super admin", "windowId", activeWindow.window.Id, "pipelineId", pipeline.Id, "ciArtifactId", ciArtifactId, "userId", userId, "reason", reason)
+		windowNames = append(windowNames, activeWindow.window.Name)
+	}
+	if len(windowNames) > 0 {
+		impl.notifyOverride(pipeline.Id, ciArtifactId, userId, reason, windowNames)
+	}
+	return nil
+}
+
+// notifyOverride sends the override to notification subscribers of the pipeline, failures are logged as the override
+// is already recorded
+func (impl DeploymentWindowServiceImpl) notifyOverride(pipelineId int, ciArtifactId int, userId int32, reason string, windowNames []string) {
+	// pipelines of bulk triggers are fetched without app and environment
+	pipeline, err := impl.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline of deployment window override", "err", err, "pipelineId", pipelineId)
+		return
+	}
+	override := &bean.DeploymentWindowOverride{
+		PipelineId:   pipeline.Id,
+		AppId:        pipeline.AppId,
+		AppName:      pipeline.App.AppName,
+		EnvId:        pipeline.EnvironmentId,
+		EnvName:      pipeline.Environment.Name,
+		TeamId:       pipeline.App.TeamId,
+		CiArtifactId: ciArtifactId,
+		WindowNames:  windowNames,
+		Reason:       reason,
+	}
+	userInfo, err := impl.userService.GetById(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user of deployment window override", "err", err, "userId", userId)
+	} else {
+		override.OverriddenBy = userInfo.EmailId
+	}
+	envId := pipeline.EnvironmentId
+	event := impl.eventFactory.Build(util2.DeploymentWindowOverridden, &pipelineId, pipeline.AppId, &envId, util2.CD)
+	event = impl.eventFactory.BuildExtraDeploymentWindowOverrideData(event, override)
+	_, err = impl.eventClient.WriteEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in sending deployment window overridden event", "err", err, "pipelineId", pipeline.Id)
+	}
+}
+
+type activeDeploymentWindow struct {
+	window *DeploymentWindow
+	start  time.Time
+	end    time.Time
+}
+
+func (impl DeploymentWindowServiceImpl) findActiveWindows(pipeline *pipelineConfig.Pipeline, now time.Time) ([]*activeDeploymentWindow, error) {
+	env, err := impl.environmentRepository.FindById(pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "err", err, "envId", pipeline.EnvironmentId)
+		return nil, err
+	}
+	windows, err := impl.deploymentWindowRepository.FindActiveByScope(env.ClusterId, pipeline.EnvironmentId, pipeline.AppId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err, "pipelineId", pipeline.Id)
+		return nil, err
+	}
+	var activeWindows []*activeDeploymentWindow
+	for _, window := range windows {
+		start, end, active, err := getActiveOccurrence(window, now)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment window, skipping", "err", err, "windowId", window.Id)
+			continue
+		}
+		if active {
+			activeWindows = append(activeWindows, &activeDeploymentWindow{window: window, start: start, end: end})
+		}
+	}
+	return activeWindows, nil
+}
+
+func (impl DeploymentWindowServiceImpl) copyDtoToModel(request *DeploymentWindowDto, window *DeploymentWindow) {
+	window.Name = request.Name
+	window.Description = request.Description
+	window.WindowType = request.WindowType
+	window.CronExpression = request.CronExpression
+	window.DurationInMinutes = request.DurationInMinutes
+	window.StartTime = request.StartTime
+	window.EndTime = request.EndTime
+	window.Timezone = request.Timezone
+	window.ClusterId = request.ClusterId
+	window.EnvironmentId = request.EnvironmentId
+	window.AppId = request.AppId
+	window.UpdatedOn = time.Now()
+	window.UpdatedBy = request.UserId
+}
+
+func (impl DeploymentWindowServiceImpl) copyModelToDto(window *DeploymentWindow) *DeploymentWindowDto {
+	return &DeploymentWindowDto{
+		Id:                window.Id,
+		Name:              window.Name,
+		Description:       window.Description,
+		WindowType:        window.WindowType,
+		CronExpression:    window.CronExpression,
+		DurationInMinutes: window.DurationInMinutes,
+		StartTime:         window.StartTime,
+		EndTime:           window.EndTime,
+		Timezone:          window.Timezone,
+		ClusterId:         window.ClusterId,
+		EnvironmentId:     window.EnvironmentId,
+		AppId:             window.AppId,
+	}
+}
+
+func validateDeploymentWindow(request *DeploymentWindowDto) error {
+	if len(request.Timezone) == 0 {
+		request.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(request.Timezone); err != nil {
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: "invalid timezone"}
+	}
+	switch request.WindowType {
+	case WINDOW_TYPE_CRON:
+		if _, err := parseWindowSchedule(request.CronExpression, request.Timezone); err != nil {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: "invalid cron expression"}
+		}
+		if request.DurationInMinutes <= 0 {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "invalid duration", UserMessage: "duration must be greater than zero"}
+		}
+	case WINDOW_TYPE_RANGE:
+		if request.StartTime.IsZero() || !request.EndTime.After(request.StartTime) {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "invalid range", UserMessage: "end time must be after start time"}
+		}
+	}
+	return nil
+}
+
+func parseWindowSchedule(cronExpression string, timezone string) (cron.Schedule, error) {
+	return cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", timezone, cronExpression))
+}
+
+// getActiveOccurrence returns the occurrence of the window which covers now, for cron windows this is the earliest
+// start within the last duration
+func getActiveOccurrence(window *DeploymentWindow, now time.Time) (start time.Time, end time.Time, active bool, err error) {
+	switch window.WindowType {
+	case WINDOW_TYPE_CRON:
+		schedule, err := parseWindowSchedule(window.CronExpression, window.Timezone)
+		if err != nil {
+			return start, end, false, err
+		}
+		duration := time.Duration(window.DurationInMinutes) * time.Minute
+		start = schedule.Next(now.Add(-duration))
+		end = start.Add(duration)
+		return start, end, !start.After(now), nil
+	case WINDOW_TYPE_RANGE:
+		return window.StartTime, window.EndTime, !now.Before(window.StartTime) && now.Before(window.EndTime), nil
+	}
+	return start, end, false, fmt.Errorf("unsupported window type %s", window.WindowType)
+}
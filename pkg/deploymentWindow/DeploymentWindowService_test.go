@@ -0,0 +1,193 @@
+package deploymentWindow
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/user"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeDeploymentWindowRepository struct {
+	DeploymentWindowRepository
+	windows   []*DeploymentWindow
+	overrides []*DeploymentWindowOverride
+}
+
+func (f *fakeDeploymentWindowRepository) FindActiveByScope(clusterId int, environmentId int, appId int) ([]*DeploymentWindow, error) {
+	return f.windows, nil
+}
+
+func (f *fakeDeploymentWindowRepository) SaveOverride(override *DeploymentWindowOverride) error {
+	f.overrides = append(f.overrides, override)
+	return nil
+}
+
+func (f *fakeDeploymentWindowRepository) FindOverridesAfter(windowId int, pipelineId int, ciArtifactId int, after time.Time) ([]*DeploymentWindowOverride, error) {
+	var overrides []*DeploymentWindowOverride
+	for _, override := range f.overrides {
+		if override.DeploymentWindowId == windowId && override.PipelineId == pipelineId && override.CiArtifactId == ciArtifactId && override.CreatedOn.After(after) {
+			overrides = append(overrides, override)
+		}
+	}
+	return overrides, nil
+}
+
+type fakeWindowEnvironmentRepository struct {
+	repository.EnvironmentRepository
+}
+
+func (f *fakeWindowEnvironmentRepository) FindById(id int) (*repository.Environment, error) {
+	return &repository.Environment{Id: id, ClusterId: 1}, nil
+}
+
+type fakeWindowUserService struct {
+	user.UserService
+	superAdmins map[int]bool
+}
+
+func (f *fakeWindowUserService) IsSuperAdmin(userId int) (bool, error) {
+	return f.superAdmins[userId], nil
+}
+
+func (f *fakeWindowUserService) GetById(id int32) (*bean.UserInfo, error) {
+	return &bean.UserInfo{Id: id, EmailId: "admin@example.com"}, nil
+}
+
+type fakeWindowPipelineRepository struct {
+	pipelineConfig.PipelineRepository
+}
+
+func (f *fakeWindowPipelineRepository) FindById(id int) (*pipelineConfig.Pipeline, error) {
+	return &pipelineConfig.Pipeline{Id: id, AppId: 2, EnvironmentId: 3}, nil
+}
+
+type fakeWindowEventFactory struct {
+	client.EventFactory
+}
+
+func (f *fakeWindowEventFactory) Build(eventType util2.EventType, sourceId *int, appId int, envId *int, pipelineType util2.PipelineType) client.Event {
+	return client.Event{EventTypeId: int(eventType), AppId: appId}
+}
+
+func (f *fakeWindowEventFactory) BuildExtraDeploymentWindowOverrideData(event client.Event, override *bean.DeploymentWindowOverride) client.Event {
+	return event
+}
+
+type fakeWindowEventClient struct {
+	client.EventClient
+	events []client.Event
+}
+
+func (f *fakeWindowEventClient) WriteEvent(event client.Event) (bool, error) {
+	f.events = append(f.events, event)
+	return true, nil
+}
+
+func TestGetActiveOccurrence(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	// every friday from 22:00 to 00:00 in Asia/Kolkata
+	cronWindow := &DeploymentWindow{WindowType: WINDOW_TYPE_CRON, CronExpression: "0 22 * * 5", DurationInMinutes: 120, Timezone: "Asia/Kolkata"}
+	rangeWindow := &DeploymentWindow{WindowType: WINDOW_TYPE_RANGE,
+		StartTime: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name      string
+		window    *DeploymentWindow
+		now       time.Time
+		active    bool
+		wantStart time.Time
+	}{
+		{name: "cron in window", window: cronWindow, now: time.Date(2023, 3, 3, 23, 0, 0, 0, kolkata), active: true,
+			wantStart: time.Date(2023, 3, 3, 22, 0, 0, 0, kolkata)},
+		{name: "cron in window after midnight utc", window: cronWindow, now: time.Date(2023, 3, 3, 18, 0, 0, 0, time.UTC), active: true,
+			wantStart: time.Date(2023, 3, 3, 22, 0, 0, 0, kolkata)},
+		{name: "cron at window start", window: cronWindow, now: time.Date(2023, 3, 3, 22, 0, 0, 0, kolkata), active: true,
+			wantStart: time.Date(2023, 3, 3, 22, 0, 0, 0, kolkata)},
+		{name: "cron before window", window: cronWindow, now: time.Date(2023, 3, 3, 21, 59, 0, 0, kolkata)},
+		{name: "cron after window", window: cronWindow, now: time.Date(2023, 3, 4, 0, 0, 0, 0, kolkata)},
+		{name: "cron next recurrence", window: cronWindow, now: time.Date(2023, 3, 10, 23, 30, 0, 0, kolkata), active: true,
+			wantStart: time.Date(2023, 3, 10, 22, 0, 0, 0, kolkata)},
+		{name: "range in window", window: rangeWindow, now: time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC), active: true,
+			wantStart: rangeWindow.StartTime},
+		{name: "range at window end", window: rangeWindow, now: rangeWindow.EndTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, _, active, err := getActiveOccurrence(tt.window, tt.now)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.active, active)
+			if tt.active {
+				assert.True(t, tt.wantStart.Equal(start), "start %s, want %s", start, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestValidateDeploymentWindow(t *testing.T) {
+	request := &DeploymentWindowDto{WindowType: WINDOW_TYPE_CRON, CronExpression: "0 22 * * 5", DurationInMinutes: 60}
+	assert.Nil(t, validateDeploymentWindow(request))
+	assert.Equal(t, "UTC", request.Timezone)
+
+	request = &DeploymentWindowDto{WindowType: WINDOW_TYPE_CRON, CronExpression: "0 22 * * 5", DurationInMinutes: 60, Timezone: "Mars/Olympus"}
+	assert.Equal(t, "invalid timezone", validateDeploymentWindow(request).(*util.ApiError).UserMessage)
+}
+
+func getTestDeploymentWindowService() (*DeploymentWindowServiceImpl, *fakeDeploymentWindowRepository, *fakeWindowEventClient) {
+	now := time.Now()
+	windowRepository := &fakeDeploymentWindowRepository{windows: []*DeploymentWindow{
+		{Id: 1, Name: "release freeze", WindowType: WINDOW_TYPE_RANGE, StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)},
+		{Id: 2, Name: "weekend freeze", WindowType: WINDOW_TYPE_RANGE, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(2 * time.Hour)},
+		{Id: 3, Name: "past freeze", WindowType: WINDOW_TYPE_RANGE, StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-2 * time.Hour)},
+	}}
+	eventClient := &fakeWindowEventClient{}
+	impl := NewDeploymentWindowServiceImpl(zap.NewNop().Sugar(), windowRepository, &fakeWindowEnvironmentRepository{},
+		&fakeWindowUserService{superAdmins: map[int]bool{1: true}}, &fakeWindowPipelineRepository{}, eventClient, &fakeWindowEventFactory{})
+	return impl, windowRepository, eventClient
+}
+
+func TestDeploymentWindowService_CheckDeploymentWindowOverlap(t *testing.T) {
+	impl, windowRepository, _ := getTestDeploymentWindowService()
+	cdPipeline := &pipelineConfig.Pipeline{Id: 5, AppId: 2, EnvironmentId: 3}
+
+	block, err := impl.CheckDeploymentWindow(cdPipeline, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, block.WindowId)
+
+	// override of one of the overlapping windows leaves deployment blocked by the other
+	windowRepository.overrides = append(windowRepository.overrides, &DeploymentWindowOverride{DeploymentWindowId: 1, PipelineId: 5, CiArtifactId: 10})
+	windowRepository.overrides[0].CreatedOn = time.Now()
+	block, err = impl.CheckDeploymentWindow(cdPipeline, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, block.WindowId)
+}
+
+func TestDeploymentWindowService_OverrideDeploymentWindow(t *testing.T) {
+	impl, windowRepository, eventClient := getTestDeploymentWindowService()
+	cdPipeline := &pipelineConfig.Pipeline{Id: 5, AppId: 2, EnvironmentId: 3}
+
+	err := impl.OverrideDeploymentWindow(cdPipeline, 10, 2, "hotfix")
+	assert.Equal(t, http.StatusForbidden, err.(*util.ApiError).HttpStatusCode)
+	assert.Empty(t, windowRepository.overrides)
+
+	err = impl.OverrideDeploymentWindow(cdPipeline, 10, 1, "hotfix")
+	assert.Nil(t, err)
+	assert.Len(t, windowRepository.overrides, 2)
+	assert.Len(t, eventClient.events, 1)
+	assert.Equal(t, int(util2.DeploymentWindowOverridden), eventClient.events[0].EventTypeId)
+
+	block, err := impl.CheckDeploymentWindow(cdPipeline, 10)
+	assert.Nil(t, err)
+	assert.Nil(t, block)
+	// override is for the artifact only
+	block, err = impl.CheckDeploymentWindow(cdPipeline, 11)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, block.WindowId)
+}
@@ -44,7 +44,11 @@ import (
 	"github.com/microsoft/azure-devops-go-api/azuredevops"
 	"github.com/xanzy/go-gitlab"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"io"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	v12 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 type GitOpsConfigService interface {
@@ -74,7 +78,15 @@ const (
 	GITLAB_PROVIDER       = "GITLAB"
 	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
 	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
+	GITEA_PROVIDER        = "GITEA"
+	BARE_GIT_PROVIDER     = "BARE_GIT"
 	BITBUCKET_API_HOST    = "https://api.bitbucket.org/2.0/"
+	// BareGitDryrunRepoName is the repo used for validating bare git provider, it has to be created on git server
+	// as repositories can not be created on it
+	BareGitDryrunRepoName = "devtron-gitops-dryrun"
+	// ArgoCdKnownHostsConfigMapName is the config map of host keys trusted by argocd for ssh repositories
+	ArgoCdKnownHostsConfigMapName = "argocd-ssh-known-hosts-cm"
+	ArgoCdKnownHostsKey           = "ssh_known_hosts"
 )
 
 type DetailedErrorGitOpsConfigResponse struct {
@@ -127,6 +139,10 @@ func (impl *GitOpsConfigServiceImpl) ValidateAndCreateGitOpsConfig(config *bean2
 	return detailedErrorGitOpsConfigResponse, nil
 }
 func (impl *GitOpsConfigServiceImpl) ValidateAndUpdateGitOpsConfig(config *bean2.GitOpsConfigDto) (DetailedErrorGitOpsConfigResponse, error) {
+	model, err := impl.gitOpsRepository.GetGitOpsConfigById(config.Id)
+	if err == nil {
+		keepSshPrivateKey(config, model)
+	}
 	detailedErrorGitOpsConfigResponse := impl.GitOpsValidateDryRun(config)
 	if len(detailedErrorGitOpsConfigResponse.StageErrorMap) == 0 {
 		err := impl.UpdateGitOpsConfig(config)
@@ -148,6 +164,10 @@ func (impl *GitOpsConfigServiceImpl) buildGithubOrgUrl(host, orgId string) (orgU
 }
 func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(request *bean2.GitOpsConfigDto) (*bean2.GitOpsConfigDto, error) {
 	impl.logger.Debugw("gitops create request", "req", request)
+	err := validateSshConfig(request)
+	if err != nil {
+		return nil, err
+	}
 	dbConnection := impl.gitOpsRepository.GetConnection()
 	tx, err := dbConnection.Begin()
 	if err != nil {
@@ -182,6 +202,9 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(request *bean2.GitOpsCon
 		AzureProject:         request.AzureProjectName,
 		BitBucketWorkspaceId: request.BitBucketWorkspaceId,
 		BitBucketProjectKey:  request.BitBucketProjectKey,
+		GiteaOrgId:           request.GiteaOrgId,
+		SshPrivateKey:        request.SshPrivateKey,
+		SshKnownHosts:        request.SshKnownHosts,
 		AuditLog:             sql.AuditLog{CreatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: request.UserId},
 	}
 	model, err = impl.gitOpsRepository.CreateGitOpsConfig(model, tx)
@@ -216,6 +239,9 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(request *bean2.GitOpsCon
 	data := make(map[string][]byte)
 	data["username"] = []byte(request.Username)
 	data["password"] = []byte(request.Token)
+	if len(request.SshPrivateKey) > 0 {
+		data["sshPrivateKey"] = []byte(request.SshPrivateKey)
+	}
 	if secret == nil {
 		secret, err = impl.K8sUtil.CreateSecret(impl.aCDAuthConfig.ACDConfigMapNamespace, data, GitOpsSecretName, client)
 		if err != nil {
@@ -269,6 +295,13 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(request *bean2.GitOpsCon
 	if strings.ToUpper(request.Provider) == BITBUCKET_PROVIDER {
 		request.Host = util.BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
 	}
+	if strings.ToUpper(request.Provider) == GITEA_PROVIDER && len(request.GiteaOrgId) > 0 {
+		orgUrl, err := impl.buildGithubOrgUrl(request.Host, request.GiteaOrgId)
+		if err != nil {
+			return nil, err
+		}
+		request.Host = orgUrl
+	}
 	operationComplete := false
 	retryCount := 0
 	for !operationComplete && retryCount < 3 {
@@ -296,6 +329,11 @@ func (impl *GitOpsConfigServiceImpl) CreateGitOpsConfig(request *bean2.GitOpsCon
 	if !operationComplete {
 		return nil, fmt.Errorf("resouce version not matched with config map attempted 3 times")
 	}
+	err = impl.updateArgoCdKnownHosts(request.SshKnownHosts, client)
+	if err != nil {
+		impl.logger.Errorw("error in updating argocd known hosts", "err", err)
+		return nil, err
+	}
 
 	err = tx.Commit()
 	if err != nil {
@@ -349,6 +387,11 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 		}
 	}
 
+	keepSshPrivateKey(request, model)
+	err = validateSshConfig(request)
+	if err != nil {
+		return err
+	}
 	model.Provider = strings.ToUpper(request.Provider)
 	model.Username = request.Username
 	model.Token = request.Token
@@ -359,6 +402,9 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	model.AzureProject = request.AzureProjectName
 	model.BitBucketWorkspaceId = request.BitBucketWorkspaceId
 	model.BitBucketProjectKey = request.BitBucketProjectKey
+	model.GiteaOrgId = request.GiteaOrgId
+	model.SshPrivateKey = request.SshPrivateKey
+	model.SshKnownHosts = request.SshKnownHosts
 	err = impl.gitOpsRepository.UpdateGitOpsConfig(model, tx)
 	if err != nil {
 		impl.logger.Errorw("error in updating team", "data", model, "err", err)
@@ -393,6 +439,9 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	data := make(map[string][]byte)
 	data["username"] = []byte(request.Username)
 	data["password"] = []byte(request.Token)
+	if len(request.SshPrivateKey) > 0 {
+		data["sshPrivateKey"] = []byte(request.SshPrivateKey)
+	}
 	if secret == nil {
 		secret, err = impl.K8sUtil.CreateSecret(impl.aCDAuthConfig.ACDConfigMapNamespace, data, GitOpsSecretName, client)
 		if err != nil {
@@ -446,6 +495,13 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	if strings.ToUpper(request.Provider) == BITBUCKET_PROVIDER {
 		request.Host = util.BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
 	}
+	if strings.ToUpper(request.Provider) == GITEA_PROVIDER && len(request.GiteaOrgId) > 0 {
+		orgUrl, err := impl.buildGithubOrgUrl(request.Host, request.GiteaOrgId)
+		if err != nil {
+			return err
+		}
+		request.Host = orgUrl
+	}
 	operationComplete := false
 	retryCount := 0
 	for !operationComplete && retryCount < 3 {
@@ -470,6 +526,11 @@ func (impl *GitOpsConfigServiceImpl) UpdateGitOpsConfig(request *bean2.GitOpsCon
 	if !operationComplete {
 		return fmt.Errorf("resouce version not matched with config map attempted 3 times")
 	}
+	err = impl.updateArgoCdKnownHosts(request.SshKnownHosts, client)
+	if err != nil {
+		impl.logger.Errorw("error in updating argocd known hosts", "err", err)
+		return err
+	}
 
 	err = tx.Commit()
 	if err != nil {
@@ -501,6 +562,8 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigById(id int) (*bean2.GitOpsC
 		AzureProjectName:     model.AzureProject,
 		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 		BitBucketProjectKey:  model.BitBucketProjectKey,
+		GiteaOrgId:           model.GiteaOrgId,
+		SshKnownHosts:        model.SshKnownHosts,
 	}
 
 	return config, err
@@ -527,6 +590,8 @@ func (impl *GitOpsConfigServiceImpl) GetAllGitOpsConfig() ([]*bean2.GitOpsConfig
 			AzureProjectName:     model.AzureProject,
 			BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 			BitBucketProjectKey:  model.BitBucketProjectKey,
+			GiteaOrgId:           model.GiteaOrgId,
+			SshKnownHosts:        model.SshKnownHosts,
 		}
 		configs = append(configs, config)
 	}
@@ -552,6 +617,8 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigByProvider(provider string)
 		AzureProjectName:     model.AzureProject,
 		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 		BitBucketProjectKey:  model.BitBucketProjectKey,
+		GiteaOrgId:           model.GiteaOrgId,
+		SshKnownHosts:        model.SshKnownHosts,
 	}
 
 	return config, err
@@ -601,14 +668,101 @@ func (impl *GitOpsConfigServiceImpl) createRepoElement(secretName string, reques
 	passwordSecret := &KeyDto{Name: secretName, Key: "password"}
 	repoData.PasswordSecret = passwordSecret
 	repoData.UsernameSecret = usernameSecret
+	if len(request.SshPrivateKey) > 0 {
+		repoData.SshPrivateKeySecret = &KeyDto{Name: secretName, Key: "sshPrivateKey"}
+	}
 	repoData.Url = request.Host
 	return repoData
 }
 
+// keepSshPrivateKey keeps the saved ssh private key when request has none, key is not sent back to client
+func keepSshPrivateKey(request *bean2.GitOpsConfigDto, model *repository.GitOpsConfig) {
+	if len(request.SshPrivateKey) == 0 && strings.ToUpper(request.Provider) == model.Provider {
+		request.SshPrivateKey = model.SshPrivateKey
+	}
+}
+
+// validateSshConfig requires known hosts with ssh private key, host key of git server is never trusted on first use
+func validateSshConfig(request *bean2.GitOpsConfigDto) error {
+	if len(request.SshPrivateKey) == 0 {
+		return nil
+	}
+	if len(strings.TrimSpace(request.SshKnownHosts)) == 0 {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "ssh known hosts are required with ssh private key",
+			UserMessage:     "ssh known hosts are required with ssh private key",
+		}
+	}
+	rest := []byte(request.SshKnownHosts)
+	for len(rest) > 0 {
+		var err error
+		_, _, _, _, rest, err = ssh.ParseKnownHosts(rest)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: err.Error(),
+				UserMessage:     fmt.Sprintf("invalid ssh known hosts: %s", err.Error()),
+			}
+		}
+	}
+	return nil
+}
+
+// updateArgoCdKnownHosts adds known hosts of gitops config to argocd known hosts, argocd verifies host key of
+// ssh repositories against these instead of repository credentials
+func (impl *GitOpsConfigServiceImpl) updateArgoCdKnownHosts(knownHosts string, client *v12.CoreV1Client) error {
+	if len(strings.TrimSpace(knownHosts)) == 0 {
+		return nil
+	}
+	var err error
+	for retryCount := 0; retryCount < 3; retryCount++ {
+		var cm *v1.ConfigMap
+		cm, err = impl.K8sUtil.GetConfigMap(impl.aCDAuthConfig.ACDConfigMapNamespace, ArgoCdKnownHostsConfigMapName, client)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[ArgoCdKnownHostsKey] = mergeKnownHosts(cm.Data[ArgoCdKnownHostsKey], knownHosts)
+		_, err = impl.K8sUtil.UpdateConfigMap(impl.aCDAuthConfig.ACDConfigMapNamespace, cm, client)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// mergeKnownHosts appends the entries of knownHosts which are not in existing
+func mergeKnownHosts(existing string, knownHosts string) string {
+	entries := make(map[string]bool)
+	for _, line := range strings.Split(existing, "\n") {
+		entries[strings.TrimSpace(line)] = true
+	}
+	merged := strings.TrimRight(existing, "\n")
+	for _, line := range strings.Split(knownHosts, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || entries[line] {
+			continue
+		}
+		entries[line] = true
+		if len(merged) > 0 {
+			merged += "\n"
+		}
+		merged += line
+	}
+	return merged + "\n"
+}
+
 type RepositoryCredentialsDto struct {
 	Url            string  `json:"url,omitempty"`
 	UsernameSecret *KeyDto `json:"usernameSecret,omitempty"`
 	PasswordSecret *KeyDto `json:"passwordSecret,omitempty"`
+	// SshPrivateKeySecret is used by argocd for ssh urls of bare git provider
+	SshPrivateKeySecret *KeyDto `json:"sshPrivateKeySecret,omitempty"`
 }
 
 type KeyDto struct {
@@ -632,6 +786,7 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigActive() (*bean2.GitOpsConfi
 		AzureProjectName:     model.AzureProject,
 		BitBucketWorkspaceId: model.BitBucketWorkspaceId,
 		BitBucketProjectKey:  model.BitBucketProjectKey,
+		GiteaOrgId:           model.GiteaOrgId,
 	}
 	return config, err
 }
@@ -655,6 +810,10 @@ func (impl *GitOpsConfigServiceImpl) GitOpsValidateDryRun(config *bean2.GitOpsCo
 		return detailedErrorGitOpsConfigResponse
 	}
 	appName := DryrunRepoName + util2.Generate(6)
+	isBareGit := strings.ToUpper(config.Provider) == BARE_GIT_PROVIDER
+	if isBareGit {
+		appName = BareGitDryrunRepoName
+	}
 	//getting user name & emailId for commit author data
 	userEmailId, userName := impl.chartTemplateService.GetUserEmailIdAndNameForGitOpsCommit(config.UserId)
 	repoUrl, _, detailedErrorCreateRepo := client.CreateRepository(appName, "sample dry-run repo", config.BitBucketWorkspaceId, config.BitBucketProjectKey, userName, userEmailId)
@@ -707,14 +866,17 @@ func (impl *GitOpsConfigServiceImpl) GitOpsValidateDryRun(config *bean2.GitOpsCo
 		IsPrivate: "true",
 		Project:   config.BitBucketProjectKey,
 	}
-	err = client.DeleteRepository(appName, config.Username, config.GitHubOrgId, config.AzureProjectName, repoOptions)
-	if err != nil {
-		impl.logger.Errorw("error in deleting repo", "err", err)
-		//here below the assignment of delete is removed for making this stage optional, and it's failure not preventing it from saving/updating gitOps config
-		//detailedErrorGitOpsConfigActions.StageErrorMap[DeleteRepoStage] = impl.extractErrorMessageByProvider(err, config.Provider)
-		detailedErrorGitOpsConfigActions.DeleteRepoFailed = true
-	} else {
-		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, DeleteRepoStage)
+	// dry-run repo of bare git is pre-created and kept for next validations
+	if !isBareGit {
+		err = client.DeleteRepository(appName, config.Username, config.GitHubOrgId, config.AzureProjectName, repoOptions)
+		if err != nil {
+			impl.logger.Errorw("error in deleting repo", "err", err)
+			//here below the assignment of delete is removed for making this stage optional, and it's failure not preventing it from saving/updating gitOps config
+			//detailedErrorGitOpsConfigActions.StageErrorMap[DeleteRepoStage] = impl.extractErrorMessageByProvider(err, config.Provider)
+			detailedErrorGitOpsConfigActions.DeleteRepoFailed = true
+		} else {
+			detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, DeleteRepoStage)
+		}
 	}
 	detailedErrorGitOpsConfigActions.ValidatedOn = time.Now()
 	defer impl.cleanDir(clonedDir)
@@ -742,6 +904,10 @@ func (impl *GitOpsConfigServiceImpl) extractErrorMessageByProvider(err error, pr
 			errorMessage := fmt.Errorf("%s", errorResponse.Message)
 			return errorMessage
 		}
+	} else if provider == GITEA_PROVIDER {
+		if errorResponse, ok := err.(*util.GiteaErrorResponse); ok {
+			return fmt.Errorf("%s", errorResponse.Message)
+		}
 	} else if provider == AZURE_DEVOPS_PROVIDER {
 		if errorResponse, ok := err.(azuredevops.WrappedError); ok {
 			errorMessage := fmt.Errorf("%s", *errorResponse.Message)
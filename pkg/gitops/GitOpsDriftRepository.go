@@ -0,0 +1,75 @@
+package gitops
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type GitOpsDrift struct {
+	tableName                   struct{}  `sql:"gitops_drift" pg:",discard_unknown_columns"`
+	Id                          int       `sql:"id,pk"`
+	PipelineId                  int       `sql:"pipeline_id,notnull"`
+	AppId                       int       `sql:"app_id,notnull"`
+	EnvId                       int       `sql:"env_id,notnull"`
+	DeploymentTemplateHistoryId int       `sql:"deployment_template_history_id"`
+	PipelineOverrideId          int       `sql:"pipeline_override_id"`
+	GitDrift                    bool      `sql:"git_drift,notnull"`
+	GitDriftPaths               string    `sql:"git_drift_paths"`
+	ArgoSyncStatus              string    `sql:"argo_sync_status"`
+	LiveDrift                   bool      `sql:"live_drift,notnull"`
+	DriftedResources            string    `sql:"drifted_resources"`
+	Error                       string    `sql:"error"`
+	DriftDetectedOn             time.Time `sql:"drift_detected_on"`
+	CheckedOn                   time.Time `sql:"checked_on,notnull"`
+	sql.AuditLog
+}
+
+type GitOpsDriftRepository interface {
+	Save(drift *GitOpsDrift) error
+	Update(drift *GitOpsDrift) error
+	FindByPipelineId(pipelineId int) (*GitOpsDrift, error)
+	// FindAll returns the drifts filtered by app and environment, filter is not applied when id is 0
+	FindAll(appId int, envId int, driftedOnly bool) ([]*GitOpsDrift, error)
+}
+
+type GitOpsDriftRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewGitOpsDriftRepositoryImpl(dbConnection *pg.DB) *GitOpsDriftRepositoryImpl {
+	return &GitOpsDriftRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl GitOpsDriftRepositoryImpl) Save(drift *GitOpsDrift) error {
+	return impl.dbConnection.Insert(drift)
+}
+
+func (impl GitOpsDriftRepositoryImpl) Update(drift *GitOpsDrift) error {
+	return impl.dbConnection.Update(drift)
+}
+
+func (impl GitOpsDriftRepositoryImpl) FindByPipelineId(pipelineId int) (*GitOpsDrift, error) {
+	drift := &GitOpsDrift{}
+	err := impl.dbConnection.Model(drift).
+		Where("pipeline_id = ?", pipelineId).
+		Select()
+	return drift, err
+}
+
+func (impl GitOpsDriftRepositoryImpl) FindAll(appId int, envId int, driftedOnly bool) ([]*GitOpsDrift, error) {
+	var drifts []*GitOpsDrift
+	query := impl.dbConnection.Model(&drifts)
+	if appId > 0 {
+		query = query.Where("app_id = ?", appId)
+	}
+	if envId > 0 {
+		query = query.Where("env_id = ?", envId)
+	}
+	if driftedOnly {
+		query = query.Where("git_drift = ? OR live_drift = ?", true, true)
+	}
+	err := query.Order("app_id ASC", "env_id ASC").Select()
+	return drifts, err
+}
@@ -0,0 +1,413 @@
+package gitops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/pipeline/history"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type GitOpsDriftService interface {
+	// DetectDrifts checks drift of all cd pipelines deployed through gitops, returns the reports of pipelines which
+	// drifted since last check
+	DetectDrifts() ([]*bean2.GitOpsDriftReport, error)
+	// GetDriftReports returns the reports of last check, filter of app and environment is not applied when id is 0
+	GetDriftReports(appId int, envId int, driftedOnly bool) ([]*bean2.GitOpsDriftReport, error)
+	// CheckDrift checks drift of a cd pipeline now
+	CheckDrift(pipelineId int) (*bean2.GitOpsDriftReport, error)
+	// ReapplyDevtronState deploys the artifact of latest deployment again through manual cd trigger, so deployment
+	// gates of the pipeline apply to it
+	ReapplyDevtronState(request *bean2.GitOpsDriftReapplyRequest) (*bean2.GitOpsDriftReport, error)
+}
+
+type GitOpsDriftServiceImpl struct {
+	logger                           *zap.SugaredLogger
+	gitOpsDriftRepository            GitOpsDriftRepository
+	pipelineRepository               pipelineConfig.PipelineRepository
+	pipelineOverrideRepository       chartConfig.PipelineOverrideRepository
+	envConfigOverrideRepository      chartConfig.EnvConfigOverrideRepository
+	deploymentTemplateHistoryService history.DeploymentTemplateHistoryService
+	chartTemplateService             util.ChartTemplateService
+	acdClient                        application.ServiceClient
+	argoUserService                  argo.ArgoUserService
+	workflowDagExecutor              pipeline.WorkflowDagExecutor
+	cdWorkflowRepository             pipelineConfig.CdWorkflowRepository
+}
+
+func NewGitOpsDriftServiceImpl(logger *zap.SugaredLogger, gitOpsDriftRepository GitOpsDriftRepository,
+	pipelineRepository pipelineConfig.PipelineRepository, pipelineOverrideRepository chartConfig.PipelineOverrideRepository,
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository,
+	deploymentTemplateHistoryService history.DeploymentTemplateHistoryService,
+	chartTemplateService util.ChartTemplateService, acdClient application.ServiceClient,
+	argoUserService argo.ArgoUserService, workflowDagExecutor pipeline.WorkflowDagExecutor,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository) *GitOpsDriftServiceImpl {
+	return &GitOpsDriftServiceImpl{
+		logger:                           logger,
+		gitOpsDriftRepository:            gitOpsDriftRepository,
+		pipelineRepository:               pipelineRepository,
+		pipelineOverrideRepository:       pipelineOverrideRepository,
+		envConfigOverrideRepository:      envConfigOverrideRepository,
+		deploymentTemplateHistoryService: deploymentTemplateHistoryService,
+		chartTemplateService:             chartTemplateService,
+		acdClient:                        acdClient,
+		argoUserService:                  argoUserService,
+		workflowDagExecutor:              workflowDagExecutor,
+		cdWorkflowRepository:             cdWorkflowRepository,
+	}
+}
+
+// deployedState is the state of a cd pipeline deployed by devtron, release of latest deployment holds the values
+// committed to gitops repo
+type deployedState struct {
+	pipeline    *pipelineConfig.Pipeline
+	history     *history.DeploymentTemplateHistoryDto
+	release     *chartConfig.PipelineOverride
+	envOverride *chartConfig.EnvConfigOverride
+}
+
+func (state *deployedState) valuesFilePath() string {
+	return filepath.Join(state.envOverride.Chart.ChartLocation, fmt.Sprintf("_%d-values.yaml", state.envOverride.TargetEnvironment))
+}
+
+func (state *deployedState) argoAppName() string {
+	return fmt.Sprintf("%s-%s", state.pipeline.App.AppName, state.pipeline.Environment.Name)
+}
+
+func (impl GitOpsDriftServiceImpl) DetectDrifts() ([]*bean2.GitOpsDriftReport, error) {
+	pipelines, err := impl.pipelineRepository.FindActiveByDeploymentAppType(util.PIPELINE_DEPLOYMENT_TYPE_ACD)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching gitops pipelines", "err", err)
+		return nil, err
+	}
+	ctx, err := impl.buildACDContext()
+	if err != nil {
+		return nil, err
+	}
+	// pipelines of an app share the gitops repo, it is read once for all of them
+	statesByRepo := make(map[string][]*deployedState)
+	for _, pipeline := range pipelines {
+		state, err := impl.getDeployedState(pipeline)
+		if err == pg.ErrNoRows {
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching deployed state of pipeline", "pipelineId", pipeline.Id, "err", err)
+			continue
+		}
+		statesByRepo[state.envOverride.Chart.GitRepoUrl] = append(statesByRepo[state.envOverride.Chart.GitRepoUrl], state)
+	}
+	var driftedReports []*bean2.GitOpsDriftReport
+	for repoUrl, states := range statesByRepo {
+		var filePaths []string
+		for _, state := range states {
+			filePaths = append(filePaths, state.valuesFilePath())
+		}
+		files, readErr := impl.chartTemplateService.ReadGitOpsRepoFiles(repoUrl, filePaths)
+		for _, state := range states {
+			report, newlyDrifted, err := impl.checkAndSaveDrift(ctx, state, files, readErr)
+			if err != nil {
+				impl.logger.Errorw("error in saving gitops drift", "pipelineId", state.pipeline.Id, "err", err)
+				continue
+			}
+			if newlyDrifted {
+				driftedReports = append(driftedReports, report)
+			}
+		}
+	}
+	return driftedReports, nil
+}
+
+func (impl GitOpsDriftServiceImpl) CheckDrift(pipelineId int) (*bean2.GitOpsDriftReport, error) {
+	pipeline, err := impl.pipelineRepository.FindById(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	if pipeline.DeploymentAppType != util.PIPELINE_DEPLOYMENT_TYPE_ACD {
+		return nil, fmt.Errorf("pipeline %s is not deployed through gitops", pipeline.Name)
+	}
+	state, err := impl.getDeployedState(pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed state of pipeline", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	ctx, err := impl.buildACDContext()
+	if err != nil {
+		return nil, err
+	}
+	files, readErr := impl.chartTemplateService.ReadGitOpsRepoFiles(state.envOverride.Chart.GitRepoUrl, []string{state.valuesFilePath()})
+	report, _, err := impl.checkAndSaveDrift(ctx, state, files, readErr)
+	if err != nil {
+		impl.logger.Errorw("error in saving gitops drift", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func (impl GitOpsDriftServiceImpl) GetDriftReports(appId int, envId int, driftedOnly bool) ([]*bean2.GitOpsDriftReport, error) {
+	drifts, err := impl.gitOpsDriftRepository.FindAll(appId, envId, driftedOnly)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching gitops drifts", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	reports := make([]*bean2.GitOpsDriftReport, 0, len(drifts))
+	if len(drifts) == 0 {
+		return reports, nil
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByDeploymentAppType(util.PIPELINE_DEPLOYMENT_TYPE_ACD)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching gitops pipelines", "err", err)
+		return nil, err
+	}
+	pipelineById := make(map[int]*pipelineConfig.Pipeline)
+	for _, pipeline := range pipelines {
+		pipelineById[pipeline.Id] = pipeline
+	}
+	for _, drift := range drifts {
+		// drift of deleted pipelines and of pipelines moved to helm is not reported
+		if pipeline, ok := pipelineById[drift.PipelineId]; ok {
+			reports = append(reports, impl.toReport(drift, pipeline))
+		}
+	}
+	return reports, nil
+}
+
+func (impl GitOpsDriftServiceImpl) ReapplyDevtronState(request *bean2.GitOpsDriftReapplyRequest) (*bean2.GitOpsDriftReport, error) {
+	pipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "pipelineId", request.PipelineId, "err", err)
+		return nil, err
+	}
+	if pipeline.DeploymentAppType != util.PIPELINE_DEPLOYMENT_TYPE_ACD {
+		return nil, fmt.Errorf("pipeline %s is not deployed through gitops", pipeline.Name)
+	}
+	state, err := impl.getDeployedState(pipeline)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed state of pipeline", "pipelineId", request.PipelineId, "err", err)
+		return nil, err
+	}
+	ctx, err := impl.buildACDContext()
+	if err != nil {
+		return nil, err
+	}
+	// devtron state is re-applied by deploying artifact of latest deployment again, the trigger commits values to
+	// gitops repo and syncs argocd app only when approval, promotion, deployment window, image signature and
+	// vulnerability checks pass
+	overrideRequest := &bean2.ValuesOverrideRequest{
+		PipelineId:     pipeline.Id,
+		AppId:          pipeline.AppId,
+		CiArtifactId:   state.release.CiArtifactId,
+		CdWorkflowType: bean2.CD_WORKFLOW_TYPE_DEPLOY,
+		DeploymentType: models.DEPLOYMENTTYPE_DEPLOY,
+		UserId:         request.UserId,
+	}
+	_, err = impl.workflowDagExecutor.ManualCdTrigger(overrideRequest, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in triggering deployment re-applying devtron state", "pipelineId", request.PipelineId, "err", err)
+		return nil, err
+	}
+	report, err := impl.CheckDrift(request.PipelineId)
+	if err != nil {
+		return nil, err
+	}
+	if pipeline.Environment.GitOpsPullRequest {
+		// values reach the environment once the pull request is merged, drift is checked again after merge
+		runner, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(overrideRequest.CdWorkflowId, bean2.CD_WORKFLOW_TYPE_DEPLOY)
+		if err != nil {
+			impl.logger.Errorw("error in fetching runner re-applying devtron state", "cdWorkflowId", overrideRequest.CdWorkflowId, "err", err)
+			return nil, err
+		}
+		report.PullRequestUrl = runner.GitOpsPullRequestUrl
+	}
+	return report, nil
+}
+
+// getDeployedState returns pg.ErrNoRows when pipeline is not deployed yet
+func (impl GitOpsDriftServiceImpl) getDeployedState(pipeline *pipelineConfig.Pipeline) (*deployedState, error) {
+	deployedHistory, err := impl.deploymentTemplateHistoryService.GetLatestDeployedHistory(pipeline.Id)
+	if err != nil {
+		return nil, err
+	}
+	release, err := impl.pipelineOverrideRepository.FindLatestByPipelineId(pipeline.Id)
+	if err != nil {
+		return nil, err
+	}
+	envOverride, err := impl.envConfigOverrideRepository.Get(release.EnvConfigOverrideId)
+	if err != nil {
+		return nil, err
+	}
+	return &deployedState{pipeline: pipeline, history: deployedHistory, release: release, envOverride: envOverride}, nil
+}
+
+// checkAndSaveDrift compares values of gitops repo with values of release and lets argocd compare live state with
+// gitops repo, drift which could not be checked keeps the result of previous check
+func (impl GitOpsDriftServiceImpl) checkAndSaveDrift(ctx context.Context, state *deployedState, files map[string]string, readErr error) (report *bean2.GitOpsDriftReport, newlyDrifted bool, err error) {
+	drift, err := impl.gitOpsDriftRepository.FindByPipelineId(state.pipeline.Id)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, false, err
+	}
+	previouslyDrifted := drift.GitDrift || drift.LiveDrift
+	now := time.Now()
+	drift.PipelineId = state.pipeline.Id
+	drift.AppId = state.pipeline.AppId
+	drift.EnvId = state.pipeline.EnvironmentId
+	drift.DeploymentTemplateHistoryId = state.history.Id
+	drift.PipelineOverrideId = state.release.Id
+	drift.Error = ""
+	drift.CheckedOn = now
+
+	if readErr != nil {
+		drift.Error = fmt.Sprintf("error in reading gitops repo: %s", readErr.Error())
+	} else if len(state.release.GitHash) == 0 {
+		// values of release are not committed yet, pull request of release awaits merge
+		drift.GitDrift = false
+		drift.GitDriftPaths = ""
+	} else {
+		paths, err := diffValues(state.release.PipelineMergedValues, files[state.valuesFilePath()])
+		if err != nil {
+			drift.Error = fmt.Sprintf("error in reading values of gitops repo: %s", err.Error())
+		} else {
+			drift.GitDrift = len(paths) > 0
+			drift.GitDriftPaths = marshalDriftList(paths)
+		}
+	}
+
+	argoAppName := state.argoAppName()
+	argoApp, err := impl.acdClient.Get(ctx, &application2.ApplicationQuery{Name: &argoAppName})
+	if err != nil {
+		impl.logger.Errorw("error in fetching argocd app", "argoAppName", argoAppName, "err", err)
+		drift.Error = joinDriftErrors(drift.Error, fmt.Sprintf("error in fetching argocd app: %s", err.Error()))
+	} else {
+		drift.ArgoSyncStatus = string(argoApp.Status.Sync.Status)
+		drift.LiveDrift = argoApp.Status.Sync.Status == v1alpha1.SyncStatusCodeOutOfSync
+		drift.DriftedResources = ""
+		if drift.LiveDrift {
+			resources, err := impl.getDriftedResources(ctx, argoAppName)
+			if err != nil {
+				impl.logger.Errorw("error in fetching managed resources of argocd app", "argoAppName", argoAppName, "err", err)
+			} else {
+				drift.DriftedResources = marshalDriftList(resources)
+			}
+		}
+	}
+
+	drifted := drift.GitDrift || drift.LiveDrift
+	if drifted && !previouslyDrifted {
+		drift.DriftDetectedOn = now
+	} else if !drifted {
+		drift.DriftDetectedOn = time.Time{}
+	}
+	drift.UpdatedOn = now
+	drift.UpdatedBy = 1
+	if drift.Id == 0 {
+		drift.CreatedOn = now
+		drift.CreatedBy = 1
+		err = impl.gitOpsDriftRepository.Save(drift)
+	} else {
+		err = impl.gitOpsDriftRepository.Update(drift)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return impl.toReport(drift, state.pipeline), drifted && !previouslyDrifted, nil
+}
+
+func (impl GitOpsDriftServiceImpl) getDriftedResources(ctx context.Context, argoAppName string) ([]*bean2.GitOpsDriftResource, error) {
+	managedResources, err := impl.acdClient.ManagedResources(ctx, &application2.ResourcesQuery{ApplicationName: &argoAppName})
+	if err != nil {
+		return nil, err
+	}
+	var resources []*bean2.GitOpsDriftResource
+	for _, item := range managedResources.Items {
+		if item.Modified {
+			resources = append(resources, &bean2.GitOpsDriftResource{Group: item.Group, Kind: item.Kind, Namespace: item.Namespace, Name: item.Name})
+		}
+	}
+	return resources, nil
+}
+
+func (impl GitOpsDriftServiceImpl) getReport(pipeline *pipelineConfig.Pipeline) (*bean2.GitOpsDriftReport, error) {
+	drift, err := impl.gitOpsDriftRepository.FindByPipelineId(pipeline.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching gitops drift", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	drift.PipelineId = pipeline.Id
+	return impl.toReport(drift, pipeline), nil
+}
+
+func (impl GitOpsDriftServiceImpl) toReport(drift *GitOpsDrift, pipeline *pipelineConfig.Pipeline) *bean2.GitOpsDriftReport {
+	report := &bean2.GitOpsDriftReport{
+		PipelineId:                  drift.PipelineId,
+		AppId:                       pipeline.AppId,
+		AppName:                     pipeline.App.AppName,
+		EnvId:                       pipeline.EnvironmentId,
+		EnvName:                     pipeline.Environment.Name,
+		TeamId:                      pipeline.App.TeamId,
+		DeploymentTemplateHistoryId: drift.DeploymentTemplateHistoryId,
+		PipelineOverrideId:          drift.PipelineOverrideId,
+		Drifted:                     drift.GitDrift || drift.LiveDrift,
+		GitDrift:                    drift.GitDrift,
+		ArgoSyncStatus:              drift.ArgoSyncStatus,
+		LiveDrift:                   drift.LiveDrift,
+		Error:                       drift.Error,
+		CheckedOn:                   drift.CheckedOn,
+	}
+	if !drift.DriftDetectedOn.IsZero() {
+		driftDetectedOn := drift.DriftDetectedOn
+		report.DriftDetectedOn = &driftDetectedOn
+	}
+	if len(drift.GitDriftPaths) > 0 {
+		err := json.Unmarshal([]byte(drift.GitDriftPaths), &report.GitDriftPaths)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshalling git drift paths", "pipelineId", drift.PipelineId, "err", err)
+		}
+	}
+	if len(drift.DriftedResources) > 0 {
+		err := json.Unmarshal([]byte(drift.DriftedResources), &report.DriftedResources)
+		if err != nil {
+			impl.logger.Errorw("error in unmarshalling drifted resources", "pipelineId", drift.PipelineId, "err", err)
+		}
+	}
+	return report
+}
+
+func (impl GitOpsDriftServiceImpl) buildACDContext() (context.Context, error) {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "token", acdToken)
+	return ctx, nil
+}
+
+func marshalDriftList(list interface{}) string {
+	value, err := json.Marshal(list)
+	if err != nil || string(value) == "null" {
+		return ""
+	}
+	return string(value)
+}
+
+func joinDriftErrors(err string, other string) string {
+	if len(err) == 0 {
+		return other
+	}
+	return err + "; " + other
+}
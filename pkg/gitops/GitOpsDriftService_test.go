@@ -0,0 +1,173 @@
+package gitops
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/pipeline/history"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeDriftPipelineRepository struct {
+	pipelineConfig.PipelineRepository
+	pipeline *pipelineConfig.Pipeline
+}
+
+func (f *fakeDriftPipelineRepository) FindById(id int) (*pipelineConfig.Pipeline, error) {
+	return f.pipeline, nil
+}
+
+type fakeDriftHistoryService struct {
+	history.DeploymentTemplateHistoryService
+}
+
+func (f *fakeDriftHistoryService) GetLatestDeployedHistory(pipelineId int) (*history.DeploymentTemplateHistoryDto, error) {
+	return &history.DeploymentTemplateHistoryDto{Id: 3, PipelineId: pipelineId}, nil
+}
+
+type fakeDriftPipelineOverrideRepository struct {
+	chartConfig.PipelineOverrideRepository
+}
+
+func (f *fakeDriftPipelineOverrideRepository) FindLatestByPipelineId(pipelineId int) (*chartConfig.PipelineOverride, error) {
+	return &chartConfig.PipelineOverride{Id: 20, PipelineId: pipelineId, CiArtifactId: 30, EnvConfigOverrideId: 40,
+		GitHash: "commit-hash", PipelineMergedValues: `{"replicaCount":2}`}, nil
+}
+
+type fakeDriftEnvConfigOverrideRepository struct {
+	chartConfig.EnvConfigOverrideRepository
+}
+
+func (f *fakeDriftEnvConfigOverrideRepository) Get(id int) (*chartConfig.EnvConfigOverride, error) {
+	return &chartConfig.EnvConfigOverride{Id: id, TargetEnvironment: 2,
+		Chart: &chartRepoRepository.Chart{ChartLocation: "app-one", GitRepoUrl: "https://github.com/devtron/app-one.git"}}, nil
+}
+
+type fakeDriftChartTemplateService struct {
+	util.ChartTemplateService
+}
+
+func (f *fakeDriftChartTemplateService) ReadGitOpsRepoFiles(repoUrl string, filePaths []string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, filePath := range filePaths {
+		files[filePath] = "replicaCount: 2\n"
+	}
+	return files, nil
+}
+
+type fakeDriftAcdClient struct {
+	application.ServiceClient
+}
+
+func (f *fakeDriftAcdClient) Get(ctx context.Context, query *application2.ApplicationQuery) (*v1alpha1.Application, error) {
+	argoApp := &v1alpha1.Application{}
+	argoApp.Status.Sync.Status = v1alpha1.SyncStatusCodeSynced
+	return argoApp, nil
+}
+
+type fakeDriftArgoUserService struct {
+	argo.ArgoUserService
+}
+
+func (f *fakeDriftArgoUserService) GetLatestDevtronArgoCdUserToken() (string, error) {
+	return "token", nil
+}
+
+type fakeDriftRepository struct {
+	GitOpsDriftRepository
+	saved []*GitOpsDrift
+}
+
+func (f *fakeDriftRepository) FindByPipelineId(pipelineId int) (*GitOpsDrift, error) {
+	return &GitOpsDrift{}, pg.ErrNoRows
+}
+
+func (f *fakeDriftRepository) Save(drift *GitOpsDrift) error {
+	f.saved = append(f.saved, drift)
+	return nil
+}
+
+type fakeDriftWorkflowDagExecutor struct {
+	pipeline.WorkflowDagExecutor
+	err      error
+	requests []*bean2.ValuesOverrideRequest
+}
+
+func (f *fakeDriftWorkflowDagExecutor) ManualCdTrigger(overrideRequest *bean2.ValuesOverrideRequest, ctx context.Context) (int, error) {
+	f.requests = append(f.requests, overrideRequest)
+	if f.err != nil {
+		return 0, f.err
+	}
+	overrideRequest.CdWorkflowId = 50
+	return 21, nil
+}
+
+type fakeDriftCdWorkflowRepository struct {
+	pipelineConfig.CdWorkflowRepository
+}
+
+func (f *fakeDriftCdWorkflowRepository) FindByWorkflowIdAndRunnerType(wfId int, runnerType bean2.WorkflowType) (pipelineConfig.CdWorkflowRunner, error) {
+	return pipelineConfig.CdWorkflowRunner{CdWorkflowId: wfId, GitOpsPullRequestUrl: "https://github.com/devtron/app-one/pull/4"}, nil
+}
+
+func getTestGitOpsDriftService(gitOpsPullRequest bool, triggerErr error) (*GitOpsDriftServiceImpl, *fakeDriftWorkflowDagExecutor, *fakeDriftRepository) {
+	cdPipeline := &pipelineConfig.Pipeline{Id: 1, AppId: 2, EnvironmentId: 2, Name: "cd-dev", DeploymentAppType: util.PIPELINE_DEPLOYMENT_TYPE_ACD,
+		App: app.App{AppName: "app-one"}, Environment: repository.Environment{Name: "dev", GitOpsPullRequest: gitOpsPullRequest}}
+	workflowDagExecutor := &fakeDriftWorkflowDagExecutor{err: triggerErr}
+	driftRepository := &fakeDriftRepository{}
+	impl := NewGitOpsDriftServiceImpl(zap.NewNop().Sugar(), driftRepository, &fakeDriftPipelineRepository{pipeline: cdPipeline},
+		&fakeDriftPipelineOverrideRepository{}, &fakeDriftEnvConfigOverrideRepository{}, &fakeDriftHistoryService{},
+		&fakeDriftChartTemplateService{}, &fakeDriftAcdClient{}, &fakeDriftArgoUserService{}, workflowDagExecutor,
+		&fakeDriftCdWorkflowRepository{})
+	return impl, workflowDagExecutor, driftRepository
+}
+
+func TestGitOpsDriftService_ReapplyDevtronState(t *testing.T) {
+	impl, workflowDagExecutor, driftRepository := getTestGitOpsDriftService(false, nil)
+
+	report, err := impl.ReapplyDevtronState(&bean2.GitOpsDriftReapplyRequest{PipelineId: 1, UserId: 7})
+	assert.Nil(t, err)
+	assert.Len(t, workflowDagExecutor.requests, 1)
+	request := workflowDagExecutor.requests[0]
+	assert.Equal(t, 30, request.CiArtifactId)
+	assert.Equal(t, int32(7), request.UserId)
+	assert.Equal(t, bean2.CD_WORKFLOW_TYPE_DEPLOY, request.CdWorkflowType)
+	assert.False(t, request.SkipDeploymentGates)
+	assert.False(t, report.Drifted)
+	assert.Empty(t, report.PullRequestUrl)
+	assert.Len(t, driftRepository.saved, 1)
+	assert.Equal(t, 20, driftRepository.saved[0].PipelineOverrideId)
+}
+
+func TestGitOpsDriftService_ReapplyDevtronStateWithPullRequest(t *testing.T) {
+	impl, _, _ := getTestGitOpsDriftService(true, nil)
+
+	report, err := impl.ReapplyDevtronState(&bean2.GitOpsDriftReapplyRequest{PipelineId: 1, UserId: 7})
+	assert.Nil(t, err)
+	assert.Equal(t, "https://github.com/devtron/app-one/pull/4", report.PullRequestUrl)
+}
+
+func TestGitOpsDriftService_ReapplyDevtronStateBlockedByGate(t *testing.T) {
+	windowBlock := &util.ApiError{HttpStatusCode: http.StatusForbidden, UserMessage: "deployment is blocked by freeze window"}
+	impl, workflowDagExecutor, driftRepository := getTestGitOpsDriftService(false, windowBlock)
+
+	_, err := impl.ReapplyDevtronState(&bean2.GitOpsDriftReapplyRequest{PipelineId: 1, UserId: 7})
+	assert.Equal(t, windowBlock, err)
+	assert.Len(t, workflowDagExecutor.requests, 1)
+	assert.Empty(t, driftRepository.saved)
+}
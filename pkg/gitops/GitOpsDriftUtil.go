@@ -0,0 +1,85 @@
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ghodss/yaml"
+)
+
+// diffValues returns the paths of values which differ between values committed by devtron and values found in gitops
+// repo, both are yaml or json. Paths are dot separated keys with index of lists, i.e. ingress.hosts[0].host
+func diffValues(expected string, actual string) ([]string, error) {
+	var expectedValues, actualValues interface{}
+	err := unmarshalValues(expected, &expectedValues)
+	if err != nil {
+		return nil, err
+	}
+	err = unmarshalValues(actual, &actualValues)
+	if err != nil {
+		return nil, err
+	}
+	paths := diffValuePaths(expectedValues, actualValues, "")
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func unmarshalValues(values string, out *interface{}) error {
+	// json is yaml, values are converted to json so that numbers of both are compared alike
+	jsonValues, err := yaml.YAMLToJSON([]byte(values))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonValues, out)
+}
+
+func diffValuePaths(expected interface{}, actual interface{}, path string) []string {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{rootPath(path)}
+		}
+		var paths []string
+		for key, value := range expectedValue {
+			paths = append(paths, diffValuePaths(value, actualValue[key], joinPath(path, key))...)
+		}
+		for key := range actualValue {
+			if _, ok := expectedValue[key]; !ok {
+				paths = append(paths, joinPath(path, key))
+			}
+		}
+		return paths
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok || len(actualValue) != len(expectedValue) {
+			return []string{rootPath(path)}
+		}
+		var paths []string
+		for i := range expectedValue {
+			paths = append(paths, diffValuePaths(expectedValue[i], actualValue[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return paths
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			return []string{rootPath(path)}
+		}
+		return nil
+	}
+}
+
+func joinPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func rootPath(path string) string {
+	if len(path) == 0 {
+		return "."
+	}
+	return path
+}
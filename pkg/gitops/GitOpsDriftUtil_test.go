@@ -0,0 +1,46 @@
+package gitops
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffValues(t *testing.T) {
+	committed := `{"replicaCount":2,"image":{"tag":"v1"},"ingress":{"hosts":[{"host":"a.example.com"}]},"env":["A"]}`
+	tests := []struct {
+		name     string
+		actual   string
+		expected []string
+	}{
+		{
+			name:   "same values as yaml",
+			actual: "replicaCount: 2\nimage:\n  tag: v1\ningress:\n  hosts:\n  - host: a.example.com\nenv:\n- A\n",
+		},
+		{
+			name:     "edited, added and removed values",
+			actual:   `{"replicaCount":3,"image":{"tag":"v1","pullPolicy":"Always"},"ingress":{"hosts":[{"host":"b.example.com"}]}}`,
+			expected: []string{"env", "image.pullPolicy", "ingress.hosts[0].host", "replicaCount"},
+		},
+		{
+			name:     "list of different length",
+			actual:   `{"replicaCount":2,"image":{"tag":"v1"},"ingress":{"hosts":[{"host":"a.example.com"}]},"env":["A","B"]}`,
+			expected: []string{"env"},
+		},
+		{
+			name:     "values file emptied",
+			actual:   "",
+			expected: []string{"."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := diffValues(committed, tt.actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(paths) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package gitops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	app2 "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/util/argo"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type GitOpsRepoMigrationService interface {
+	// MigrateApps moves apps from their own gitops repo to the repo shared by many apps as per configured layout,
+	// content of old repo is copied to directory of app and argocd apps are pointed to it. Old repos are left as is
+	MigrateApps(request *bean2.GitOpsRepoMigrationRequest) ([]*bean2.GitOpsRepoMigrationResponse, error)
+}
+
+type GitOpsRepoMigrationServiceImpl struct {
+	logger               *zap.SugaredLogger
+	appRepository        app2.AppRepository
+	chartRepository      chartRepoRepository.ChartRepository
+	pipelineRepository   pipelineConfig.PipelineRepository
+	chartTemplateService util.ChartTemplateService
+	acdClient            application.ServiceClient
+	argoUserService      argo.ArgoUserService
+}
+
+func NewGitOpsRepoMigrationServiceImpl(logger *zap.SugaredLogger, appRepository app2.AppRepository,
+	chartRepository chartRepoRepository.ChartRepository, pipelineRepository pipelineConfig.PipelineRepository,
+	chartTemplateService util.ChartTemplateService, acdClient application.ServiceClient,
+	argoUserService argo.ArgoUserService) *GitOpsRepoMigrationServiceImpl {
+	return &GitOpsRepoMigrationServiceImpl{
+		logger:               logger,
+		appRepository:        appRepository,
+		chartRepository:      chartRepository,
+		pipelineRepository:   pipelineRepository,
+		chartTemplateService: chartTemplateService,
+		acdClient:            acdClient,
+		argoUserService:      argoUserService,
+	}
+}
+
+func (impl GitOpsRepoMigrationServiceImpl) MigrateApps(request *bean2.GitOpsRepoMigrationRequest) ([]*bean2.GitOpsRepoMigrationResponse, error) {
+	if !impl.chartTemplateService.IsSharedGitOpsRepoLayout() {
+		return nil, errors.New("gitops repo layout is not shared, set GITOPS_REPO_LAYOUT to TEAM or GLOBAL to migrate apps")
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByDeploymentAppType(util.PIPELINE_DEPLOYMENT_TYPE_ACD)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching gitops pipelines", "err", err)
+		return nil, err
+	}
+	pipelinesByApp := make(map[int][]*pipelineConfig.Pipeline)
+	var appIds []int
+	for _, pipeline := range pipelines {
+		if _, ok := pipelinesByApp[pipeline.AppId]; !ok {
+			appIds = append(appIds, pipeline.AppId)
+		}
+		pipelinesByApp[pipeline.AppId] = append(pipelinesByApp[pipeline.AppId], pipeline)
+	}
+	if len(request.AppIds) > 0 {
+		appIds = request.AppIds
+	}
+	ctx, err := impl.buildACDContext()
+	if err != nil {
+		return nil, err
+	}
+	var responses []*bean2.GitOpsRepoMigrationResponse
+	for _, appId := range appIds {
+		response := impl.migrateApp(ctx, appId, pipelinesByApp[appId], request.UserId)
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (impl GitOpsRepoMigrationServiceImpl) migrateApp(ctx context.Context, appId int, pipelines []*pipelineConfig.Pipeline, userId int32) *bean2.GitOpsRepoMigrationResponse {
+	response := &bean2.GitOpsRepoMigrationResponse{AppId: appId}
+	app, err := impl.appRepository.FindAppAndProjectByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+		return failedMigration(response, err)
+	}
+	response.AppName = app.AppName
+	latestChart, err := impl.chartRepository.FindLatestChartForAppByAppId(appId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching latest chart", "appId", appId, "err", err)
+		return failedMigration(response, err)
+	}
+	if latestChart == nil || len(latestChart.GitRepoUrl) == 0 {
+		response.Status = bean2.GitOpsRepoMigrationStatusSkipped
+		response.Message = "app has no gitops repo"
+		return response
+	}
+	if appDir := util.GetAppDirInGitOpsRepo(app.AppName, latestChart.ChartLocation); len(appDir) > 0 {
+		response.Status = bean2.GitOpsRepoMigrationStatusSkipped
+		response.Message = "app is already in a shared gitops repo"
+		response.GitRepoUrl = latestChart.GitRepoUrl
+		response.AppDir = appDir
+		return response
+	}
+	oldRepoUrl := latestChart.GitRepoUrl
+	gitOpsRepoName, appDir, err := impl.chartTemplateService.GetGitOpsRepoNameForApp(app.AppName, app.Team.Name)
+	if err != nil {
+		return failedMigration(response, err)
+	}
+	ownerApp, err := impl.appRepository.FindActiveByName(impl.chartTemplateService.GetAppNameOfSharedGitOpsRepo(app.Team.Name))
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching app named like shared gitops repo", "repo", gitOpsRepoName, "err", err)
+		return failedMigration(response, err)
+	} else if err == nil {
+		return failedMigration(response, fmt.Errorf("gitops repo %s is the repo of app %s, rename the app to migrate", gitOpsRepoName, ownerApp.AppName))
+	}
+	chartGitAttr, err := impl.chartTemplateService.CreateGitRepositoryForApp(gitOpsRepoName, appDir, latestChart.ReferenceTemplate, latestChart.ChartVersion, userId)
+	if err != nil {
+		impl.logger.Errorw("error in creating gitops repo", "repo", gitOpsRepoName, "err", err)
+		return failedMigration(response, err)
+	}
+	response.GitRepoUrl = chartGitAttr.RepoUrl
+	response.AppDir = appDir
+	err = impl.chartTemplateService.CopyGitOpsRepoToDir(oldRepoUrl, gitOpsRepoName, chartGitAttr.RepoUrl, appDir, userId)
+	if err != nil {
+		impl.logger.Errorw("error in copying gitops repo of app", "appId", appId, "from", oldRepoUrl, "to", chartGitAttr.RepoUrl, "err", err)
+		return failedMigration(response, err)
+	}
+	err = impl.chartTemplateService.RegisterInArgo(chartGitAttr, ctx)
+	if err != nil {
+		impl.logger.Errorw("error while register git repo in argo", "repo", chartGitAttr.RepoUrl, "err", err)
+		return failedMigration(response, err)
+	}
+
+	charts, err := impl.chartRepository.FindActiveChartsByAppId(appId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching charts", "appId", appId, "err", err)
+		return failedMigration(response, err)
+	}
+	var movedCharts []*chartRepoRepository.Chart
+	for _, chart := range charts {
+		if chart.GitRepoUrl != oldRepoUrl {
+			continue
+		}
+		chart.GitRepoUrl = chartGitAttr.RepoUrl
+		chart.ChartLocation = filepath.Join(appDir, chart.ChartLocation)
+		chart.UpdatedOn = time.Now()
+		chart.UpdatedBy = userId
+		movedCharts = append(movedCharts, chart)
+	}
+
+	// argocd apps are pointed to new repo on next deployment too, patched here so that they sync from it right away.
+	// App is either moved as a whole or left in old repo, argocd apps patched before a failure are pointed back
+	oldSources := make(map[string]v1alpha1.ApplicationSource)
+	for _, pipeline := range pipelines {
+		if !pipeline.DeploymentAppCreated {
+			continue
+		}
+		argoAppName := fmt.Sprintf("%s-%s", pipeline.App.AppName, pipeline.Environment.Name)
+		oldSource, err := impl.updateArgoAppSource(ctx, argoAppName, chartGitAttr.RepoUrl, appDir)
+		if err != nil {
+			impl.logger.Errorw("error in updating source of argocd app", "pipelineId", pipeline.Id, "err", err)
+			impl.revertArgoAppSources(ctx, oldSources)
+			return failedMigration(response, fmt.Errorf("argocd app of pipeline %s not updated: %v", pipeline.Name, err))
+		}
+		oldSources[argoAppName] = oldSource
+	}
+	err = impl.chartRepository.UpdateAll(movedCharts)
+	if err != nil {
+		impl.logger.Errorw("error in updating git repo of charts", "appId", appId, "err", err)
+		impl.revertArgoAppSources(ctx, oldSources)
+		return failedMigration(response, err)
+	}
+	response.Status = bean2.GitOpsRepoMigrationStatusMigrated
+	return response
+}
+
+// updateArgoAppSource points argocd app to directory of app in repo, returns the source it had
+func (impl GitOpsRepoMigrationServiceImpl) updateArgoAppSource(ctx context.Context, argoAppName string, repoUrl string, appDir string) (v1alpha1.ApplicationSource, error) {
+	argoApp, err := impl.acdClient.Get(ctx, &application2.ApplicationQuery{Name: &argoAppName})
+	if err != nil {
+		return v1alpha1.ApplicationSource{}, err
+	}
+	oldSource := v1alpha1.ApplicationSource{Path: argoApp.Spec.Source.Path, RepoURL: argoApp.Spec.Source.RepoURL}
+	source := v1alpha1.ApplicationSource{Path: filepath.Join(appDir, argoApp.Spec.Source.Path), RepoURL: repoUrl}
+	return oldSource, impl.patchArgoAppSource(ctx, argoAppName, source)
+}
+
+func (impl GitOpsRepoMigrationServiceImpl) revertArgoAppSources(ctx context.Context, oldSources map[string]v1alpha1.ApplicationSource) {
+	for argoAppName, source := range oldSources {
+		err := impl.patchArgoAppSource(ctx, argoAppName, source)
+		if err != nil {
+			impl.logger.Errorw("error in reverting source of argocd app", "argoAppName", argoAppName, "source", source, "err", err)
+		}
+	}
+}
+
+func (impl GitOpsRepoMigrationServiceImpl) patchArgoAppSource(ctx context.Context, argoAppName string, source v1alpha1.ApplicationSource) error {
+	patch, err := json.Marshal(v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: source}})
+	if err != nil {
+		return err
+	}
+	patchString := string(patch)
+	patchType := "merge"
+	_, err = impl.acdClient.Patch(ctx, &application2.ApplicationPatchRequest{Patch: &patchString, Name: &argoAppName, PatchType: &patchType})
+	return err
+}
+
+func (impl GitOpsRepoMigrationServiceImpl) buildACDContext() (context.Context, error) {
+	acdToken, err := impl.argoUserService.GetLatestDevtronArgoCdUserToken()
+	if err != nil {
+		impl.logger.Errorw("error in getting acd token", "err", err)
+		return nil, err
+	}
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "token", acdToken)
+	return ctx, nil
+}
+
+func failedMigration(response *bean2.GitOpsRepoMigrationResponse, err error) *bean2.GitOpsRepoMigrationResponse {
+	response.Status = bean2.GitOpsRepoMigrationStatusFailed
+	response.Message = err.Error()
+	return response
+}
@@ -0,0 +1,166 @@
+package gitops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	application2 "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	bean2 "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/team"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+const (
+	testOldRepoUrl    = "https://github.com/devtron/app-one.git"
+	testSharedRepoUrl = "https://github.com/devtron/team-payments.git"
+)
+
+type fakeMigrationAppRepository struct {
+	app.AppRepository
+	ownerApp *app.App
+}
+
+func (f *fakeMigrationAppRepository) FindAppAndProjectByAppId(appId int) (*app.App, error) {
+	return &app.App{Id: appId, AppName: "app-one", Team: team.Team{Name: "payments"}}, nil
+}
+
+func (f *fakeMigrationAppRepository) FindActiveByName(appName string) (*app.App, error) {
+	if f.ownerApp == nil || f.ownerApp.AppName != appName {
+		return nil, pg.ErrNoRows
+	}
+	return f.ownerApp, nil
+}
+
+type fakeMigrationChartRepository struct {
+	chartRepoRepository.ChartRepository
+	charts  []*chartRepoRepository.Chart
+	updated []*chartRepoRepository.Chart
+}
+
+func (f *fakeMigrationChartRepository) FindLatestChartForAppByAppId(appId int) (*chartRepoRepository.Chart, error) {
+	return f.charts[0], nil
+}
+
+func (f *fakeMigrationChartRepository) FindActiveChartsByAppId(appId int) ([]*chartRepoRepository.Chart, error) {
+	return f.charts, nil
+}
+
+func (f *fakeMigrationChartRepository) UpdateAll(charts []*chartRepoRepository.Chart) error {
+	f.updated = append(f.updated, charts...)
+	return nil
+}
+
+type fakeMigrationChartTemplateService struct {
+	util.ChartTemplateService
+}
+
+func (f *fakeMigrationChartTemplateService) GetGitOpsRepoNameForApp(appName string, teamName string) (string, string, error) {
+	return "team-" + teamName, appName, nil
+}
+
+func (f *fakeMigrationChartTemplateService) GetAppNameOfSharedGitOpsRepo(teamName string) string {
+	return "team-" + teamName
+}
+
+func (f *fakeMigrationChartTemplateService) CreateGitRepositoryForApp(gitOpsRepoName, appDir, baseTemplateName, version string, userId int32) (*util.ChartGitAttribute, error) {
+	return &util.ChartGitAttribute{RepoUrl: testSharedRepoUrl}, nil
+}
+
+func (f *fakeMigrationChartTemplateService) CopyGitOpsRepoToDir(sourceRepoUrl string, gitOpsRepoName string, repoUrl string, dir string, userId int32) error {
+	return nil
+}
+
+func (f *fakeMigrationChartTemplateService) RegisterInArgo(chartGitAttribute *util.ChartGitAttribute, ctx context.Context) error {
+	return nil
+}
+
+// fakeMigrationAcdClient keeps sources of argocd apps, patch of an app in failPatchOf fails once
+type fakeMigrationAcdClient struct {
+	application.ServiceClient
+	sources     map[string]v1alpha1.ApplicationSource
+	failPatchOf string
+}
+
+func (f *fakeMigrationAcdClient) Get(ctx context.Context, query *application2.ApplicationQuery) (*v1alpha1.Application, error) {
+	return &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: f.sources[*query.Name]}}, nil
+}
+
+func (f *fakeMigrationAcdClient) Patch(ctx context.Context, query *application2.ApplicationPatchRequest) (*v1alpha1.Application, error) {
+	if *query.Name == f.failPatchOf {
+		f.failPatchOf = ""
+		return nil, errors.New("argocd is unavailable")
+	}
+	argoApp := &v1alpha1.Application{}
+	err := json.Unmarshal([]byte(*query.Patch), argoApp)
+	if err != nil {
+		return nil, err
+	}
+	f.sources[*query.Name] = argoApp.Spec.Source
+	return argoApp, nil
+}
+
+func getTestGitOpsRepoMigrationService(failPatchOf string, ownerApp *app.App) (*GitOpsRepoMigrationServiceImpl, *fakeMigrationChartRepository, *fakeMigrationAcdClient) {
+	chartRepository := &fakeMigrationChartRepository{charts: []*chartRepoRepository.Chart{
+		{Id: 1, GitRepoUrl: testOldRepoUrl, ChartLocation: "reference-chart_4-11-0/4.11.0", ReferenceTemplate: "reference-chart_4-11-0", ChartVersion: "4.11.0"},
+	}}
+	acdClient := &fakeMigrationAcdClient{failPatchOf: failPatchOf, sources: map[string]v1alpha1.ApplicationSource{
+		"app-one-dev":  {RepoURL: testOldRepoUrl, Path: "reference-chart_4-11-0/4.11.0"},
+		"app-one-prod": {RepoURL: testOldRepoUrl, Path: "reference-chart_4-11-0/4.11.0"},
+	}}
+	impl := NewGitOpsRepoMigrationServiceImpl(zap.NewNop().Sugar(), &fakeMigrationAppRepository{ownerApp: ownerApp}, chartRepository,
+		nil, &fakeMigrationChartTemplateService{}, acdClient, nil)
+	return impl, chartRepository, acdClient
+}
+
+func getTestMigrationPipelines() []*pipelineConfig.Pipeline {
+	return []*pipelineConfig.Pipeline{
+		{Id: 1, Name: "cd-dev", DeploymentAppCreated: true, App: app.App{AppName: "app-one"}, Environment: repository.Environment{Name: "dev"}},
+		{Id: 2, Name: "cd-prod", DeploymentAppCreated: true, App: app.App{AppName: "app-one"}, Environment: repository.Environment{Name: "prod"}},
+	}
+}
+
+func TestGitOpsRepoMigrationService_migrateApp(t *testing.T) {
+	impl, chartRepository, acdClient := getTestGitOpsRepoMigrationService("", nil)
+
+	response := impl.migrateApp(context.Background(), 1, getTestMigrationPipelines(), 7)
+	assert.Equal(t, bean2.GitOpsRepoMigrationStatusMigrated, response.Status)
+	assert.Equal(t, "app-one", response.AppDir)
+	assert.Len(t, chartRepository.updated, 1)
+	assert.Equal(t, testSharedRepoUrl, chartRepository.updated[0].GitRepoUrl)
+	assert.Equal(t, "app-one/reference-chart_4-11-0/4.11.0", chartRepository.updated[0].ChartLocation)
+	for _, argoAppName := range []string{"app-one-dev", "app-one-prod"} {
+		assert.Equal(t, v1alpha1.ApplicationSource{RepoURL: testSharedRepoUrl, Path: "app-one/reference-chart_4-11-0/4.11.0"}, acdClient.sources[argoAppName])
+	}
+}
+
+func TestGitOpsRepoMigrationService_migrateAppArgoPatchFailed(t *testing.T) {
+	impl, chartRepository, acdClient := getTestGitOpsRepoMigrationService("app-one-prod", nil)
+
+	response := impl.migrateApp(context.Background(), 1, getTestMigrationPipelines(), 7)
+	assert.Equal(t, bean2.GitOpsRepoMigrationStatusFailed, response.Status)
+	assert.Empty(t, chartRepository.updated)
+	for _, argoAppName := range []string{"app-one-dev", "app-one-prod"} {
+		assert.Equal(t, v1alpha1.ApplicationSource{RepoURL: testOldRepoUrl, Path: "reference-chart_4-11-0/4.11.0"}, acdClient.sources[argoAppName])
+	}
+}
+
+func TestGitOpsRepoMigrationService_migrateAppNamedLikeSharedRepo(t *testing.T) {
+	impl, chartRepository, acdClient := getTestGitOpsRepoMigrationService("", &app.App{Id: 2, AppName: "team-payments"})
+
+	response := impl.migrateApp(context.Background(), 1, getTestMigrationPipelines(), 7)
+	assert.Equal(t, bean2.GitOpsRepoMigrationStatusFailed, response.Status)
+	assert.Contains(t, response.Message, "team-payments")
+	assert.Empty(t, chartRepository.updated)
+	assert.Equal(t, testOldRepoUrl, acdClient.sources["app-one-dev"].RepoURL)
+}
@@ -0,0 +1,115 @@
+package imageSignature
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+)
+
+type VerificationMode string
+
+const (
+	VERIFICATION_MODE_KEY     VerificationMode = "KEY"
+	VERIFICATION_MODE_KEYLESS VerificationMode = "KEYLESS"
+)
+
+type PolicyAction string
+
+const (
+	// POLICY_ACTION_BLOCK fails the deployment of images not satisfying the policy
+	POLICY_ACTION_BLOCK PolicyAction = "BLOCK"
+	// POLICY_ACTION_AUDIT only records the verification failure on the deployment
+	POLICY_ACTION_AUDIT PolicyAction = "AUDIT"
+	// POLICY_ACTION_ALLOW skips verification, used to exempt an app or environment from a wider policy
+	POLICY_ACTION_ALLOW PolicyAction = "ALLOW"
+)
+
+type ImageSignaturePolicy struct {
+	tableName            struct{}         `sql:"image_signature_policy" pg:",discard_unknown_columns"`
+	Id                   int              `sql:"id,pk"`
+	Name                 string           `sql:"name,notnull"`
+	Description          string           `sql:"description"`
+	ClusterId            int              `sql:"cluster_id"`
+	EnvironmentId        int              `sql:"environment_id"`
+	AppId                int              `sql:"app_id"`
+	Action               PolicyAction     `sql:"action,notnull"`
+	VerificationMode     VerificationMode `sql:"verification_mode"`
+	PublicKeys           string           `sql:"public_keys"`
+	RootCertificates     string           `sql:"root_certificates"`
+	RekorPublicKeys      string           `sql:"rekor_public_keys"`
+	KeylessIssuer        string           `sql:"keyless_issuer"`
+	KeylessSubject       string           `sql:"keyless_subject"`
+	RequiredAttestations []string         `sql:"required_attestations" pg:",array"`
+	Active               bool             `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// PolicyLevel orders policies by specificity like cve policies, the policies of the most specific level applicable to
+// a deployment are enforced
+func (policy *ImageSignaturePolicy) PolicyLevel() int {
+	if policy.AppId != 0 && policy.EnvironmentId != 0 {
+		return 4
+	} else if policy.AppId != 0 {
+		return 3
+	} else if policy.EnvironmentId != 0 {
+		return 2
+	} else if policy.ClusterId != 0 {
+		return 1
+	}
+	return 0
+}
+
+type ImageSignaturePolicyRepository interface {
+	Save(policy *ImageSignaturePolicy) error
+	Update(policy *ImageSignaturePolicy) error
+	FindById(id int) (*ImageSignaturePolicy, error)
+	FindAllActive() ([]*ImageSignaturePolicy, error)
+	FindActiveByScope(clusterId int, environmentId int, appId int) ([]*ImageSignaturePolicy, error)
+}
+
+type ImageSignaturePolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+}
+
+func NewImageSignaturePolicyRepositoryImpl(dbConnection *pg.DB) *ImageSignaturePolicyRepositoryImpl {
+	return &ImageSignaturePolicyRepositoryImpl{dbConnection: dbConnection}
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) Save(policy *ImageSignaturePolicy) error {
+	return impl.dbConnection.Insert(policy)
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) Update(policy *ImageSignaturePolicy) error {
+	return impl.dbConnection.Update(policy)
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) FindById(id int) (*ImageSignaturePolicy, error) {
+	policy := &ImageSignaturePolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
+
+func (impl ImageSignaturePolicyRepositoryImpl) FindAllActive() ([]*ImageSignaturePolicy, error) {
+	var policies []*ImageSignaturePolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return policies, err
+}
+
+// FindActiveByScope returns the policies applicable to a deployment, a policy without cluster, environment or app
+// applies to all of them
+func (impl ImageSignaturePolicyRepositoryImpl) FindActiveByScope(clusterId int, environmentId int, appId int) ([]*ImageSignaturePolicy, error) {
+	var policies []*ImageSignaturePolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Where("(cluster_id IS NULL OR cluster_id = ?)", clusterId).
+		Where("(environment_id IS NULL OR environment_id = ?)", environmentId).
+		Where("(app_id IS NULL OR app_id = ?)", appId).
+		Order("id ASC").
+		Select()
+	return policies, err
+}
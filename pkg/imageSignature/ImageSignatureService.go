@@ -0,0 +1,501 @@
+package imageSignature
+
+import (
+	"crypto"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/ociRegistry"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/ghodss/yaml"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type VerificationStatus string
+
+const (
+	VERIFICATION_STATUS_VERIFIED     VerificationStatus = "VERIFIED"
+	VERIFICATION_STATUS_FAILED       VerificationStatus = "FAILED"
+	VERIFICATION_STATUS_AUDIT_FAILED VerificationStatus = "AUDIT_FAILED"
+	VERIFICATION_STATUS_SKIPPED      VerificationStatus = "SKIPPED"
+)
+
+type ImageSignaturePolicyDto struct {
+	Id                   int              `json:"id"`
+	Name                 string           `json:"name" validate:"required"`
+	Description          string           `json:"description"`
+	ClusterId            int              `json:"clusterId,omitempty"`
+	EnvironmentId        int              `json:"environmentId,omitempty"`
+	AppId                int              `json:"appId,omitempty"`
+	Action               PolicyAction     `json:"action" validate:"oneof=BLOCK AUDIT ALLOW"`
+	VerificationMode     VerificationMode `json:"verificationMode,omitempty"`
+	PublicKeys           string           `json:"publicKeys,omitempty"`
+	RootCertificates     string           `json:"rootCertificates,omitempty"`
+	RekorPublicKeys      string           `json:"rekorPublicKeys,omitempty"`
+	KeylessIssuer        string           `json:"keylessIssuer,omitempty"`
+	KeylessSubject       string           `json:"keylessSubject,omitempty"`
+	RequiredAttestations []string         `json:"requiredAttestations,omitempty"`
+	UserId               int32            `json:"-"`
+}
+
+// VerificationResult is the outcome of verifying the images of a deployment against the applicable policies
+type VerificationResult struct {
+	Status  VerificationStatus
+	Blocked bool
+	Message string
+}
+
+type ImageSignatureService interface {
+	Create(request *ImageSignaturePolicyDto) (*ImageSignaturePolicyDto, error)
+	Update(request *ImageSignaturePolicyDto) (*ImageSignaturePolicyDto, error)
+	Delete(id int, userId int32) error
+	GetAll() ([]*ImageSignaturePolicyDto, error)
+	GetById(id int) (*ImageSignaturePolicyDto, error)
+	// VerifyArtifact verifies the signature and attestations of the artifact deployed by the cd pipeline, nil result
+	// is returned when no policy applies to the pipeline
+	VerifyArtifact(pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact) (*VerificationResult, error)
+	// VerifyValuesImages verifies all images referenced in the default values of a chart merged with the values
+	// overridden by a chart deployment
+	VerifyValuesImages(defaultValuesYaml string, valuesOverrideYaml string, environmentId int, appId int) (*VerificationResult, error)
+}
+
+type ImageSignatureServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	imageSignaturePolicyRepository ImageSignaturePolicyRepository
+	environmentRepository          repository2.EnvironmentRepository
+	dockerArtifactStoreRepository  repository.DockerArtifactStoreRepository
+	client                         *http.Client
+}
+
+func NewImageSignatureServiceImpl(logger *zap.SugaredLogger, imageSignaturePolicyRepository ImageSignaturePolicyRepository,
+	environmentRepository repository2.EnvironmentRepository, dockerArtifactStoreRepository repository.DockerArtifactStoreRepository,
+	client *http.Client) *ImageSignatureServiceImpl {
+	return &ImageSignatureServiceImpl{
+		logger:                         logger,
+		imageSignaturePolicyRepository: imageSignaturePolicyRepository,
+		environmentRepository:          environmentRepository,
+		dockerArtifactStoreRepository:  dockerArtifactStoreRepository,
+		client:                         client,
+	}
+}
+
+func (impl ImageSignatureServiceImpl) Create(request *ImageSignaturePolicyDto) (*ImageSignaturePolicyDto, error) {
+	err := validateImageSignaturePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	policy := &ImageSignaturePolicy{Active: true, AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId}}
+	impl.copyDtoToModel(request, policy)
+	err = impl.imageSignaturePolicyRepository.Save(policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving image signature policy", "err", err, "name", policy.Name)
+		return nil, err
+	}
+	request.Id = policy.Id
+	return request, nil
+}
+
+func (impl ImageSignatureServiceImpl) Update(request *ImageSignaturePolicyDto) (*ImageSignaturePolicyDto, error) {
+	err := validateImageSignaturePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := impl.imageSignaturePolicyRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "err", err, "id", request.Id)
+		return nil, err
+	}
+	impl.copyDtoToModel(request, policy)
+	err = impl.imageSignaturePolicyRepository.Update(policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating image signature policy", "err", err, "id", policy.Id)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl ImageSignatureServiceImpl) Delete(id int, userId int32) error {
+	policy, err := impl.imageSignaturePolicyRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "err", err, "id", id)
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	return impl.imageSignaturePolicyRepository.Update(policy)
+}
+
+func (impl ImageSignatureServiceImpl) GetAll() ([]*ImageSignaturePolicyDto, error) {
+	policies, err := impl.imageSignaturePolicyRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image signature policies", "err", err)
+		return nil, err
+	}
+	policyDtos := make([]*ImageSignaturePolicyDto, 0, len(policies))
+	for _, policy := range policies {
+		policyDtos = append(policyDtos, impl.copyModelToDto(policy))
+	}
+	return policyDtos, nil
+}
+
+func (impl ImageSignatureServiceImpl) GetById(id int) (*ImageSignaturePolicyDto, error) {
+	policy, err := impl.imageSignaturePolicyRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching image signature policy", "err", err, "id", id)
+		return nil, err
+	}
+	return impl.copyModelToDto(policy), nil
+}
+
+func (impl ImageSignatureServiceImpl) VerifyArtifact(pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact) (*VerificationResult, error) {
+	image := artifact.Image
+	if strings.HasPrefix(artifact.ImageDigest, "sha256:") && !strings.Contains(image, "@") {
+		image = image + "@" + artifact.ImageDigest
+	}
+	return impl.verifyImages([]string{image}, pipeline.EnvironmentId, pipeline.AppId)
+}
+
+func (impl ImageSignatureServiceImpl) VerifyValuesImages(defaultValuesYaml string, valuesOverrideYaml string, environmentId int, appId int) (*VerificationResult, error) {
+	defaultValues := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(defaultValuesYaml), &defaultValues)
+	if err != nil {
+		impl.logger.Errorw("error in parsing chart values for image signature verification", "err", err, "appId", appId)
+		return nil, err
+	}
+	overrideValues := make(map[string]interface{})
+	err = yaml.Unmarshal([]byte(valuesOverrideYaml), &overrideValues)
+	if err != nil {
+		impl.logger.Errorw("error in parsing values for image signature verification", "err", err, "appId", appId)
+		return nil, err
+	}
+	return impl.verifyImages(findValuesImages(mergeValues(defaultValues, overrideValues)), environmentId, appId)
+}
+
+func (impl ImageSignatureServiceImpl) verifyImages(images []string, environmentId int, appId int) (*VerificationResult, error) {
+	policies, err := impl.findEnforcedPolicies(environmentId, appId)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	for _, policy := range policies {
+		if policy.Action == POLICY_ACTION_ALLOW {
+			return &VerificationResult{Status: VERIFICATION_STATUS_SKIPPED, Message: fmt.Sprintf("verification skipped by policy %s", policy.Name)}, nil
+		}
+	}
+	if len(images) == 0 {
+		// nothing can be verified, enforced policies fail closed
+		result := &VerificationResult{Status: VERIFICATION_STATUS_AUDIT_FAILED, Message: "no image found to verify"}
+		for _, policy := range policies {
+			if policy.Action == POLICY_ACTION_BLOCK {
+				result.Status = VERIFICATION_STATUS_FAILED
+				result.Blocked = true
+			}
+		}
+		return result, nil
+	}
+	result := &VerificationResult{Status: VERIFICATION_STATUS_VERIFIED}
+	var failures []string
+	for _, image := range images {
+		for _, policy := range policies {
+			err = impl.verifyImage(image, policy)
+			if err == nil {
+				continue
+			}
+			impl.logger.Infow("image signature verification failed", "image", image, "policyId", policy.Id, "err", err)
+			failures = append(failures, fmt.Sprintf("%s: policy %s: %s", image, policy.Name, err.Error()))
+			if policy.Action == POLICY_ACTION_BLOCK {
+				result.Blocked = true
+			}
+		}
+	}
+	if len(failures) == 0 {
+		result.Message = fmt.Sprintf("%d image(s) verified", len(images))
+		return result, nil
+	}
+	result.Status = VERIFICATION_STATUS_AUDIT_FAILED
+	if result.Blocked {
+		result.Status = VERIFICATION_STATUS_FAILED
+	}
+	result.Message = "image signature verification failed: " + strings.Join(failures, "; ")
+	return result, nil
+}
+
+// findEnforcedPolicies returns the policies of the most specific level applicable to the app and environment
+func (impl ImageSignatureServiceImpl) findEnforcedPolicies(environmentId int, appId int) ([]*ImageSignaturePolicy, error) {
+	env, err := impl.environmentRepository.FindById(environmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "err", err, "envId", environmentId)
+		return nil, err
+	}
+	policies, err := impl.imageSignaturePolicyRepository.FindActiveByScope(env.ClusterId, environmentId, appId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image signature policies", "err", err, "envId", environmentId, "appId", appId)
+		return nil, err
+	}
+	return enforcedPolicies(policies), nil
+}
+
+func enforcedPolicies(policies []*ImageSignaturePolicy) []*ImageSignaturePolicy {
+	level := -1
+	for _, policy := range policies {
+		if policy.PolicyLevel() > level {
+			level = policy.PolicyLevel()
+		}
+	}
+	var enforced []*ImageSignaturePolicy
+	for _, policy := range policies {
+		if policy.PolicyLevel() == level {
+			enforced = append(enforced, policy)
+		}
+	}
+	return enforced
+}
+
+// verifyImage checks that the image has a valid signature and all attestations required by the policy, registry
+// errors are verification failures as an unverifiable image cannot be trusted
+func (impl ImageSignatureServiceImpl) verifyImage(image string, policy *ImageSignaturePolicy) error {
+	reference, err := ociRegistry.ParseReference(image)
+	if err != nil {
+		return err
+	}
+	keys, identity, err := getPolicyVerifiers(policy)
+	if err != nil {
+		return err
+	}
+	client := ociRegistry.NewClient(impl.client, impl.getRegistryCredential(reference.Registry), false)
+	digest, err := client.ResolveDigest(reference)
+	if err != nil {
+		return err
+	}
+	signatures, err := impl.getSignatureLayers(client, reference, signatureTag(digest, "sig"))
+	if err != nil {
+		return err
+	}
+	if len(signatures) == 0 {
+		return fmt.Errorf("image is not signed")
+	}
+	var verifyErr error
+	for _, signature := range signatures {
+		verifyErr = VerifyImageSignature(signature, digest, keys, identity)
+		if verifyErr == nil {
+			break
+		}
+	}
+	if verifyErr != nil {
+		return verifyErr
+	}
+	if len(policy.RequiredAttestations) == 0 {
+		return nil
+	}
+	attestations, err := impl.getSignatureLayers(client, reference, signatureTag(digest, "att"))
+	if err != nil {
+		return err
+	}
+	verifiedPredicates := make(map[string]bool)
+	for _, attestation := range attestations {
+		predicateType, err := VerifyAttestation(attestation, digest, keys, identity)
+		if err != nil {
+			impl.logger.Debugw("skipping unverified attestation", "image", image, "err", err)
+			continue
+		}
+		verifiedPredicates[predicateType] = true
+	}
+	for _, predicateType := range policy.RequiredAttestations {
+		if !verifiedPredicates[predicateType] {
+			return fmt.Errorf("missing verified attestation %s", predicateType)
+		}
+	}
+	return nil
+}
+
+func (impl ImageSignatureServiceImpl) getSignatureLayers(client *ociRegistry.Client, reference *ociRegistry.Reference, tag string) ([]*SignatureLayer, error) {
+	manifest, err := client.GetManifest(reference, tag)
+	if err != nil || manifest == nil {
+		return nil, err
+	}
+	var layers []*SignatureLayer
+	for _, descriptor := range manifest.Layers {
+		payload, err := client.GetBlob(reference, descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+		layer := &SignatureLayer{
+			MediaType:   descriptor.MediaType,
+			Payload:     payload,
+			Signature:   descriptor.Annotations[cosignSignatureAnnotation],
+			Certificate: descriptor.Annotations[cosignCertificateAnnotation],
+			Chain:       descriptor.Annotations[cosignChainAnnotation],
+			Bundle:      descriptor.Annotations[cosignBundleAnnotation],
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// getRegistryCredential finds the credential of a registry added in global configurations, images of other
+// registries are read anonymously
+func (impl ImageSignatureServiceImpl) getRegistryCredential(registry string) *ociRegistry.Credential {
+	stores, err := impl.dockerArtifactStoreRepository.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in fetching docker registries", "err", err)
+		return nil
+	}
+	for _, store := range stores {
+		if !store.Active || ociRegistry.RegistryHost(store.RegistryURL) != registry {
+			continue
+		}
+		credential, err := ociRegistry.GetCredential(&store)
+		if err != nil {
+			impl.logger.Errorw("error in fetching ecr authorization token", "err", err, "registry", store.Id)
+			return nil
+		}
+		return credential
+	}
+	return nil
+}
+
+func getPolicyVerifiers(policy *ImageSignaturePolicy) ([]crypto.PublicKey, *KeylessIdentity, error) {
+	if policy.VerificationMode == VERIFICATION_MODE_KEYLESS {
+		return nil, &KeylessIdentity{RootCertificates: policy.RootCertificates, RekorPublicKeys: policy.RekorPublicKeys, Issuer: policy.KeylessIssuer, Subject: policy.KeylessSubject}, nil
+	}
+	keys, err := ParsePublicKeys(policy.PublicKeys)
+	return keys, nil, err
+}
+
+// mergeValues overrides the default values of a chart like helm does, maps are merged and other values replaced
+func mergeValues(defaultValues map[string]interface{}, overrideValues map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(defaultValues))
+	for key, value := range defaultValues {
+		merged[key] = value
+	}
+	for key, value := range overrideValues {
+		defaultMap, isDefaultMap := merged[key].(map[string]interface{})
+		overrideMap, isOverrideMap := value.(map[string]interface{})
+		if isDefaultMap && isOverrideMap {
+			merged[key] = mergeValues(defaultMap, overrideMap)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// findValuesImages walks helm values for image references, both plain strings and the image: {registry, repository,
+// tag, digest} convention of charts
+func findValuesImages(values interface{}) []string {
+	var images []string
+	switch value := values.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if key == "image" {
+				if image := valuesImage(child); len(image) > 0 {
+					images = append(images, image)
+					continue
+				}
+			}
+			images = append(images, findValuesImages(child)...)
+		}
+	case []interface{}:
+		for _, child := range value {
+			images = append(images, findValuesImages(child)...)
+		}
+	}
+	return images
+}
+
+func valuesImage(value interface{}) string {
+	switch image := value.(type) {
+	case string:
+		return image
+	case map[string]interface{}:
+		repositoryName, _ := image["repository"].(string)
+		if len(repositoryName) == 0 {
+			return ""
+		}
+		if registry, ok := image["registry"].(string); ok && len(registry) > 0 {
+			repositoryName = registry + "/" + repositoryName
+		}
+		if tag, ok := image["tag"].(string); ok && len(tag) > 0 {
+			repositoryName = repositoryName + ":" + tag
+		}
+		if digest, ok := image["digest"].(string); ok && len(digest) > 0 {
+			repositoryName = repositoryName + "@" + digest
+		}
+		return repositoryName
+	}
+	return ""
+}
+
+func (impl ImageSignatureServiceImpl) copyDtoToModel(request *ImageSignaturePolicyDto, policy *ImageSignaturePolicy) {
+	policy.Name = request.Name
+	policy.Description = request.Description
+	policy.ClusterId = request.ClusterId
+	policy.EnvironmentId = request.EnvironmentId
+	policy.AppId = request.AppId
+	policy.Action = request.Action
+	policy.VerificationMode = request.VerificationMode
+	policy.PublicKeys = request.PublicKeys
+	policy.RootCertificates = request.RootCertificates
+	policy.RekorPublicKeys = request.RekorPublicKeys
+	policy.KeylessIssuer = request.KeylessIssuer
+	policy.KeylessSubject = request.KeylessSubject
+	policy.RequiredAttestations = request.RequiredAttestations
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = request.UserId
+}
+
+func (impl ImageSignatureServiceImpl) copyModelToDto(policy *ImageSignaturePolicy) *ImageSignaturePolicyDto {
+	return &ImageSignaturePolicyDto{
+		Id:                   policy.Id,
+		Name:                 policy.Name,
+		Description:          policy.Description,
+		ClusterId:            policy.ClusterId,
+		EnvironmentId:        policy.EnvironmentId,
+		AppId:                policy.AppId,
+		Action:               policy.Action,
+		VerificationMode:     policy.VerificationMode,
+		PublicKeys:           policy.PublicKeys,
+		RootCertificates:     policy.RootCertificates,
+		RekorPublicKeys:      policy.RekorPublicKeys,
+		KeylessIssuer:        policy.KeylessIssuer,
+		KeylessSubject:       policy.KeylessSubject,
+		RequiredAttestations: policy.RequiredAttestations,
+	}
+}
+
+func validateImageSignaturePolicy(request *ImageSignaturePolicyDto) error {
+	if request.Action == POLICY_ACTION_ALLOW {
+		return nil
+	}
+	switch request.VerificationMode {
+	case VERIFICATION_MODE_KEY:
+		if _, err := ParsePublicKeys(request.PublicKeys); err != nil {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: "invalid public keys"}
+		}
+	case VERIFICATION_MODE_KEYLESS:
+		block, _ := pem.Decode([]byte(request.RootCertificates))
+		if block == nil {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "no root certificate", UserMessage: "root certificates are required for keyless verification"}
+		}
+		if _, err := ParsePublicKeys(request.RekorPublicKeys); err != nil {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: "rekor public keys are required for keyless verification"}
+		}
+		if len(request.KeylessIssuer) == 0 && len(request.KeylessSubject) == 0 {
+			return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "no keyless identity", UserMessage: "issuer or subject is required for keyless verification"}
+		}
+	default:
+		return &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "invalid verification mode", UserMessage: "verification mode must be KEY or KEYLESS"}
+	}
+	return nil
+}
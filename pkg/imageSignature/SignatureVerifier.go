@@ -0,0 +1,459 @@
+package imageSignature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// annotations and media types written by cosign on signature and attestation layers
+const (
+	cosignSignatureAnnotation   = "dev.cosignproject.cosign/signature"
+	cosignCertificateAnnotation = "dev.sigstore.cosign/certificate"
+	cosignChainAnnotation       = "dev.sigstore.cosign/chain"
+	cosignBundleAnnotation      = "dev.sigstore.cosign/bundle"
+	dsseEnvelopeMediaType       = "application/vnd.dsse.envelope.v1+json"
+	inTotoPayloadType           = "application/vnd.in-toto+json"
+)
+
+var (
+	// fulcio certificate extensions holding the oidc issuer of the signer identity
+	fulcioIssuerOid   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	fulcioIssuerV2Oid = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// SignatureLayer is a signature or attestation stored by cosign as a layer of the .sig or .att artifact of an image
+type SignatureLayer struct {
+	MediaType   string
+	Payload     []byte
+	Signature   string
+	Certificate string
+	Chain       string
+	// Bundle is the rekor transparency log entry of keyless signatures
+	Bundle string
+}
+
+// KeylessIdentity is the signer expected in the fulcio certificate of keyless signatures
+type KeylessIdentity struct {
+	RootCertificates string
+	// RekorPublicKeys are the keys of the transparency logs trusted to timestamp keyless signatures
+	RekorPublicKeys string
+	Issuer          string
+	// Subject is a regular expression matched against email and uri subject alternative names
+	Subject string
+}
+
+// simpleSigningPayload is the payload signed by cosign for an image
+type simpleSigningPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the transparency log entry cosign attaches to a signature, the signed entry timestamp is the
+// signature of the log over the canonical json of the payload
+type rekorBundle struct {
+	SignedEntryTimestamp string             `json:"SignedEntryTimestamp"`
+	Payload              rekorBundlePayload `json:"Payload"`
+}
+
+// rekorBundlePayload fields are in the sorted order of canonical json
+type rekorBundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+type rekorHash struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// rekorEntry holds the fields of hashedrekord and intoto log entries that bind the entry to a signature
+type rekorEntry struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash rekorHash `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   string `json:"content"`
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+		Content struct {
+			Hash     rekorHash `json:"hash"`
+			Envelope struct {
+				Signatures []struct {
+					PublicKey string `json:"publicKey"`
+				} `json:"signatures"`
+			} `json:"envelope"`
+		} `json:"content"`
+		PublicKey string `json:"publicKey"`
+	} `json:"spec"`
+}
+
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+	Signatures  []struct {
+		KeyId string `json:"keyid"`
+		Sig   string `json:"sig"`
+	} `json:"signatures"`
+}
+
+type inTotoStatement struct {
+	Type          string `json:"_type"`
+	PredicateType string `json:"predicateType"`
+	Subject       []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// ParsePublicKeys parses all public keys of a pem bundle
+func ParsePublicKeys(publicKeys string) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	rest := []byte(publicKeys)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %s", err.Error())
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public key found in pem")
+	}
+	return keys, nil
+}
+
+// VerifySignature verifies the base64 signature of data with the key, signatures are over the sha256 digest of data
+// for ecdsa and rsa keys and over data itself for ed25519 keys
+func VerifySignature(key crypto.PublicKey, data []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %s", err.Error())
+	}
+	digest := sha256.Sum256(data)
+	switch publicKey := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(publicKey, digest[:], sig) {
+			return fmt.Errorf("invalid signature")
+		}
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sig)
+		if err != nil {
+			err = rsa.VerifyPSS(publicKey, crypto.SHA256, digest[:], sig, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(publicKey, data, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	return nil
+}
+
+// VerifyKeylessCertificate verifies that the fulcio certificate of a layer chains to the trusted roots and was issued
+// to the expected identity at signedAt, the public key of the certificate is returned to verify the signature with
+func VerifyKeylessCertificate(layer *SignatureLayer, identity *KeylessIdentity, signedAt time.Time) (crypto.PublicKey, error) {
+	certificate, err := parseCertificate(layer.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(identity.RootCertificates)) {
+		return nil, fmt.Errorf("no trusted root certificate configured")
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(layer.Chain))
+	// signing certificates are short lived, validity is checked at the time the transparency log recorded the signature
+	_, err = certificate.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("untrusted signing certificate: %s", err.Error())
+	}
+	if len(identity.Issuer) > 0 {
+		issuer := getCertificateIssuer(certificate)
+		if issuer != identity.Issuer {
+			return nil, fmt.Errorf("signing certificate issued for issuer %q, expected %q", issuer, identity.Issuer)
+		}
+	}
+	if len(identity.Subject) > 0 {
+		subjectRegex, err := regexp.Compile("^(?:" + identity.Subject + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid subject expression: %s", err.Error())
+		}
+		subjects := certificate.EmailAddresses
+		for _, uri := range certificate.URIs {
+			subjects = append(subjects, uri.String())
+		}
+		matched := false
+		for _, subject := range subjects {
+			if subjectRegex.MatchString(subject) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("signing certificate subjects %v do not match %q", subjects, identity.Subject)
+		}
+	}
+	return certificate.PublicKey, nil
+}
+
+// VerifyTransparencyLogEntry verifies the rekor bundle of a keyless signature layer and returns the time the log
+// integrated the entry. The entry must be signed by a trusted log and record the signature, the signing certificate
+// and the hash of the signed artifact, data for simple signing and the dsse envelope for attestations.
+func VerifyTransparencyLogEntry(layer *SignatureLayer, data []byte, signature string, rekorPublicKeys string) (time.Time, error) {
+	if len(layer.Bundle) == 0 {
+		return time.Time{}, fmt.Errorf("keyless signature has no transparency log entry")
+	}
+	bundle := &rekorBundle{}
+	err := json.Unmarshal([]byte(layer.Bundle), bundle)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log bundle: %s", err.Error())
+	}
+	keys, err := ParsePublicKeys(rekorPublicKeys)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("no trusted transparency log key: %s", err.Error())
+	}
+	var logKey crypto.PublicKey
+	for _, key := range keys {
+		if logId, err := getLogId(key); err == nil && logId == bundle.Payload.LogID {
+			logKey = key
+			break
+		}
+	}
+	if logKey == nil {
+		return time.Time{}, fmt.Errorf("transparency log %s is not trusted", bundle.Payload.LogID)
+	}
+	canonicalPayload, err := json.Marshal(bundle.Payload)
+	if err != nil {
+		return time.Time{}, err
+	}
+	err = VerifySignature(logKey, canonicalPayload, bundle.SignedEntryTimestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid signed entry timestamp: %s", err.Error())
+	}
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log entry encoding: %s", err.Error())
+	}
+	entry := &rekorEntry{}
+	err = json.Unmarshal(body, entry)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log entry: %s", err.Error())
+	}
+	err = matchTransparencyLogEntry(entry, layer, data, signature)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+func matchTransparencyLogEntry(entry *rekorEntry, layer *SignatureLayer, data []byte, signature string) error {
+	var entryHash rekorHash
+	var entryCertificates []string
+	var hashed []byte
+	switch entry.Kind {
+	case "hashedrekord":
+		if entry.Spec.Signature.Content != signature {
+			return fmt.Errorf("transparency log entry is for another signature")
+		}
+		entryHash = entry.Spec.Data.Hash
+		entryCertificates = []string{entry.Spec.Signature.PublicKey.Content}
+		hashed = data
+	case "intoto":
+		entryHash = entry.Spec.Content.Hash
+		entryCertificates = []string{entry.Spec.PublicKey}
+		for _, envelopeSignature := range entry.Spec.Content.Envelope.Signatures {
+			entryCertificates = append(entryCertificates, envelopeSignature.PublicKey)
+		}
+		hashed = layer.Payload
+	default:
+		return fmt.Errorf("unsupported transparency log entry kind %s", entry.Kind)
+	}
+	digest := sha256.Sum256(hashed)
+	if entryHash.Algorithm != "sha256" || entryHash.Value != hex.EncodeToString(digest[:]) {
+		return fmt.Errorf("transparency log entry is for another artifact")
+	}
+	certificate, err := parseCertificate(layer.Certificate)
+	if err != nil {
+		return err
+	}
+	for _, entryCertificate := range entryCertificates {
+		decoded, err := base64.StdEncoding.DecodeString(entryCertificate)
+		if err != nil {
+			continue
+		}
+		if recorded, err := parseCertificate(string(decoded)); err == nil && recorded.Equal(certificate) {
+			return nil
+		}
+	}
+	return fmt.Errorf("transparency log entry is for another signing certificate")
+}
+
+// getLogId is the id of a transparency log, the sha256 of its public key
+func getLogId(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+func parseCertificate(certificatePem string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificatePem))
+	if block == nil {
+		return nil, fmt.Errorf("signature has no signing certificate")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing certificate: %s", err.Error())
+	}
+	return certificate, nil
+}
+
+func getCertificateIssuer(certificate *x509.Certificate) string {
+	for _, extension := range certificate.Extensions {
+		if extension.Id.Equal(fulcioIssuerV2Oid) {
+			var issuer string
+			if _, err := asn1.Unmarshal(extension.Value, &issuer); err == nil {
+				return issuer
+			}
+		}
+	}
+	for _, extension := range certificate.Extensions {
+		if extension.Id.Equal(fulcioIssuerOid) {
+			return string(extension.Value)
+		}
+	}
+	return ""
+}
+
+// VerifyImageSignature verifies a cosign signature layer of the image digest with the keys or the keyless identity
+func VerifyImageSignature(layer *SignatureLayer, digest string, keys []crypto.PublicKey, identity *KeylessIdentity) error {
+	payload := &simpleSigningPayload{}
+	err := json.Unmarshal(layer.Payload, payload)
+	if err != nil {
+		return fmt.Errorf("invalid signature payload: %s", err.Error())
+	}
+	if payload.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signature is for digest %s", payload.Critical.Image.DockerManifestDigest)
+	}
+	return verifyWithKeysOrIdentity(layer, layer.Payload, layer.Signature, keys, identity)
+}
+
+// VerifyAttestation verifies a dsse envelope layer and returns the predicate type of the in-toto statement about
+// the image digest
+func VerifyAttestation(layer *SignatureLayer, digest string, keys []crypto.PublicKey, identity *KeylessIdentity) (string, error) {
+	envelope := &dsseEnvelope{}
+	err := json.Unmarshal(layer.Payload, envelope)
+	if err != nil {
+		return "", fmt.Errorf("invalid attestation envelope: %s", err.Error())
+	}
+	if envelope.PayloadType != inTotoPayloadType {
+		return "", fmt.Errorf("unsupported attestation payload type %s", envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return "", fmt.Errorf("invalid attestation payload encoding: %s", err.Error())
+	}
+	pae := dssePreAuthEncoding(envelope.PayloadType, payload)
+	var verifyErr error
+	for _, signature := range envelope.Signatures {
+		verifyErr = verifyWithKeysOrIdentity(layer, pae, signature.Sig, keys, identity)
+		if verifyErr == nil {
+			break
+		}
+	}
+	if len(envelope.Signatures) == 0 {
+		verifyErr = fmt.Errorf("attestation is not signed")
+	}
+	if verifyErr != nil {
+		return "", verifyErr
+	}
+	statement := &inTotoStatement{}
+	err = json.Unmarshal(payload, statement)
+	if err != nil {
+		return "", fmt.Errorf("invalid in-toto statement: %s", err.Error())
+	}
+	digestValue := strings.TrimPrefix(digest, "sha256:")
+	for _, subject := range statement.Subject {
+		if subject.Digest["sha256"] == digestValue {
+			return statement.PredicateType, nil
+		}
+	}
+	return "", fmt.Errorf("attestation %s is not about digest %s", statement.PredicateType, digest)
+}
+
+func verifyWithKeysOrIdentity(layer *SignatureLayer, data []byte, signature string, keys []crypto.PublicKey, identity *KeylessIdentity) error {
+	if identity != nil {
+		signedAt, err := VerifyTransparencyLogEntry(layer, data, signature, identity.RekorPublicKeys)
+		if err != nil {
+			return err
+		}
+		key, err := VerifyKeylessCertificate(layer, identity, signedAt)
+		if err != nil {
+			return err
+		}
+		return VerifySignature(key, data, signature)
+	}
+	var err error
+	for _, key := range keys {
+		err = VerifySignature(key, data, signature)
+		if err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("no public key to verify signature")
+	}
+	return err
+}
+
+// dssePreAuthEncoding is the message signed for a dsse envelope
+func dssePreAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// signatureTag is the tag under which cosign stores signatures (sig) and attestations (att) of a digest
+func signatureTag(digest string, suffix string) string {
+	return strings.Replace(digest, ":", "-", 1) + "." + suffix
+}
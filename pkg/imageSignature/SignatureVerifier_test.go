@@ -0,0 +1,235 @@
+package imageSignature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDigest = "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, data []byte) string {
+	digest := sha256.Sum256(data)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.Nil(t, err)
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+func publicKeyPem(t *testing.T, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func simpleSigningLayer(t *testing.T, key *ecdsa.PrivateKey, digest string) *SignatureLayer {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry.example.com/app"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, digest))
+	return &SignatureLayer{MediaType: "application/vnd.dev.cosign.simplesigning.v1+json", Payload: payload, Signature: sign(t, key, payload)}
+}
+
+func attestationLayer(t *testing.T, key *ecdsa.PrivateKey, predicateType string, digest string) *SignatureLayer {
+	statement := []byte(fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":%q,"subject":[{"name":"registry.example.com/app","digest":{"sha256":%q}}],"predicate":{}}`,
+		predicateType, digest[len("sha256:"):]))
+	envelope := map[string]interface{}{
+		"payloadType": inTotoPayloadType,
+		"payload":     base64.StdEncoding.EncodeToString(statement),
+		"signatures":  []map[string]string{{"keyid": "", "sig": sign(t, key, dssePreAuthEncoding(inTotoPayloadType, statement))}},
+	}
+	payload, err := json.Marshal(envelope)
+	assert.Nil(t, err)
+	return &SignatureLayer{MediaType: dsseEnvelopeMediaType, Payload: payload}
+}
+
+func TestVerifyImageSignatureWithKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	keys, err := ParsePublicKeys(publicKeyPem(t, otherKey) + publicKeyPem(t, key))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(keys))
+
+	t.Run("valid signature", func(t *testing.T) {
+		assert.Nil(t, VerifyImageSignature(simpleSigningLayer(t, key, testDigest), testDigest, keys, nil))
+	})
+	t.Run("signature of another digest", func(t *testing.T) {
+		layer := simpleSigningLayer(t, key, "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+		assert.NotNil(t, VerifyImageSignature(layer, testDigest, keys, nil))
+	})
+	t.Run("signature by untrusted key", func(t *testing.T) {
+		untrustedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.Nil(t, err)
+		assert.NotNil(t, VerifyImageSignature(simpleSigningLayer(t, untrustedKey, testDigest), testDigest, keys, nil))
+	})
+	t.Run("tampered payload", func(t *testing.T) {
+		layer := simpleSigningLayer(t, key, testDigest)
+		layer.Payload = append(layer.Payload, ' ')
+		assert.NotNil(t, VerifyImageSignature(layer, testDigest, keys, nil))
+	})
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	keys := []crypto.PublicKey{&key.PublicKey}
+
+	predicateType, err := VerifyAttestation(attestationLayer(t, key, "https://slsa.dev/provenance/v0.2", testDigest), testDigest, keys, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://slsa.dev/provenance/v0.2", predicateType)
+
+	otherDigest := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	_, err = VerifyAttestation(attestationLayer(t, key, "https://slsa.dev/provenance/v0.2", otherDigest), testDigest, keys, nil)
+	assert.NotNil(t, err)
+}
+
+func rekorLogBundle(t *testing.T, logKey *ecdsa.PrivateKey, layer *SignatureLayer, integratedTime time.Time) string {
+	digest := sha256.Sum256(layer.Payload)
+	entry := map[string]interface{}{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]interface{}{
+			"data": map[string]interface{}{"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(digest[:])}},
+			"signature": map[string]interface{}{
+				"content":   layer.Signature,
+				"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString([]byte(layer.Certificate))},
+			},
+		},
+	}
+	body, err := json.Marshal(entry)
+	assert.Nil(t, err)
+	logId, err := getLogId(&logKey.PublicKey)
+	assert.Nil(t, err)
+	payload := rekorBundlePayload{Body: base64.StdEncoding.EncodeToString(body), IntegratedTime: integratedTime.Unix(), LogID: logId, LogIndex: 1}
+	canonicalPayload, err := json.Marshal(payload)
+	assert.Nil(t, err)
+	bundle, err := json.Marshal(rekorBundle{SignedEntryTimestamp: sign(t, logKey, canonicalPayload), Payload: payload})
+	assert.Nil(t, err)
+	return string(bundle)
+}
+
+func TestVerifyKeylessSignature(t *testing.T) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDer, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	assert.Nil(t, err)
+	root, err := x509.ParseCertificate(rootDer)
+	assert.Nil(t, err)
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	issuer, err := asn1.Marshal("https://token.actions.githubusercontent.com")
+	assert.Nil(t, err)
+	subject, err := url.Parse("https://github.com/example/app/.github/workflows/release.yml@refs/heads/main")
+	assert.Nil(t, err)
+	leafTemplate := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Minute),
+		NotAfter:        time.Now().Add(10 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{subject},
+		ExtraExtensions: []pkix.Extension{{Id: fulcioIssuerV2Oid, Value: issuer}},
+	}
+	leafDer, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, &signingKey.PublicKey, rootKey)
+	assert.Nil(t, err)
+
+	layer := simpleSigningLayer(t, signingKey, testDigest)
+	layer.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDer}))
+	rootPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDer}))
+
+	logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	logKeyDer, err := x509.MarshalPKIXPublicKey(&logKey.PublicKey)
+	assert.Nil(t, err)
+	rekorPem := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: logKeyDer}))
+
+	identity := &KeylessIdentity{RootCertificates: rootPem, RekorPublicKeys: rekorPem, Issuer: "https://token.actions.githubusercontent.com", Subject: "https://github.com/example/.*"}
+	// without a transparency log entry the signing time is unknown
+	assert.NotNil(t, VerifyImageSignature(layer, testDigest, nil, identity))
+
+	layer.Bundle = rekorLogBundle(t, logKey, layer, time.Now())
+	assert.Nil(t, VerifyImageSignature(layer, testDigest, nil, identity))
+
+	wrongSubject := &KeylessIdentity{RootCertificates: rootPem, RekorPublicKeys: rekorPem, Subject: "https://github.com/other/.*"}
+	assert.NotNil(t, VerifyImageSignature(layer, testDigest, nil, wrongSubject))
+
+	wrongIssuer := &KeylessIdentity{RootCertificates: rootPem, RekorPublicKeys: rekorPem, Issuer: "https://accounts.google.com"}
+	assert.NotNil(t, VerifyImageSignature(layer, testDigest, nil, wrongIssuer))
+
+	otherLogKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	untrustedLog := *layer
+	untrustedLog.Bundle = rekorLogBundle(t, otherLogKey, layer, time.Now())
+	assert.NotNil(t, VerifyImageSignature(&untrustedLog, testDigest, nil, identity))
+
+	// a signature logged after the certificate expired is rejected
+	expired := *layer
+	expired.Bundle = rekorLogBundle(t, logKey, layer, time.Now().Add(20*time.Minute))
+	assert.NotNil(t, VerifyImageSignature(&expired, testDigest, nil, identity))
+
+	otherSignature := simpleSigningLayer(t, signingKey, testDigest)
+	otherSignature.Certificate = layer.Certificate
+	otherSignature.Bundle = layer.Bundle
+	assert.NotNil(t, VerifyImageSignature(otherSignature, testDigest, nil, identity))
+
+	otherRootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	otherRootDer, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &otherRootKey.PublicKey, otherRootKey)
+	assert.Nil(t, err)
+	untrusted := &KeylessIdentity{RootCertificates: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherRootDer})), RekorPublicKeys: rekorPem}
+	assert.NotNil(t, VerifyImageSignature(layer, testDigest, nil, untrusted))
+}
+
+func TestEnforcedPolicies(t *testing.T) {
+	global := &ImageSignaturePolicy{Id: 1}
+	env := &ImageSignaturePolicy{Id: 2, EnvironmentId: 1}
+	app := &ImageSignaturePolicy{Id: 3, AppId: 1}
+	assert.Equal(t, []*ImageSignaturePolicy{app}, enforcedPolicies([]*ImageSignaturePolicy{global, env, app}))
+	assert.Equal(t, []*ImageSignaturePolicy{global}, enforcedPolicies([]*ImageSignaturePolicy{global}))
+	assert.Nil(t, enforcedPolicies(nil))
+}
+
+func TestFindValuesImages(t *testing.T) {
+	values := map[string]interface{}{
+		"image": map[string]interface{}{"registry": "docker.io", "repository": "bitnami/redis", "tag": "7.0"},
+		"sidecars": []interface{}{
+			map[string]interface{}{"image": "busybox:1.36"},
+		},
+	}
+	assert.ElementsMatch(t, []string{"docker.io/bitnami/redis:7.0", "busybox:1.36"}, findValuesImages(values))
+}
+
+func TestMergeValues(t *testing.T) {
+	defaultValues := map[string]interface{}{
+		"image":   map[string]interface{}{"repository": "bitnami/redis", "tag": "7.0"},
+		"sidecar": map[string]interface{}{"image": "busybox:1.36"},
+	}
+	overrideValues := map[string]interface{}{
+		"image": map[string]interface{}{"tag": "7.2"},
+	}
+	assert.ElementsMatch(t, []string{"bitnami/redis:7.2", "busybox:1.36"}, findValuesImages(mergeValues(defaultValues, overrideValues)))
+}
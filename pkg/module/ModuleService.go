@@ -19,11 +19,15 @@ package module
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/pkg/auditLog/siem"
 	"github.com/devtron-labs/devtron/pkg/server"
 	serverBean "github.com/devtron-labs/devtron/pkg/server/bean"
 	serverEnvConfig "github.com/devtron-labs/devtron/pkg/server/config"
+	"github.com/devtron-labs/devtron/pkg/user/repository"
 	util2 "github.com/devtron-labs/devtron/util"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -46,10 +50,15 @@ type ModuleServiceImpl struct {
 	serverCacheService server.ServerCacheService
 	moduleCacheService ModuleCacheService
 	moduleCronService  ModuleCronService
+	siemExportService  siem.SiemExportService
+	userRepository     repository.UserRepository
 }
 
+const SIEM_RESOURCE_TYPE_MODULE = "module"
+
 func NewModuleServiceImpl(logger *zap.SugaredLogger, serverEnvConfig *serverEnvConfig.ServerEnvConfig, moduleRepository ModuleRepository,
-	moduleActionAuditLogRepository ModuleActionAuditLogRepository, helmAppService client.HelmAppService, serverCacheService server.ServerCacheService, moduleCacheService ModuleCacheService, moduleCronService ModuleCronService) *ModuleServiceImpl {
+	moduleActionAuditLogRepository ModuleActionAuditLogRepository, helmAppService client.HelmAppService, serverCacheService server.ServerCacheService, moduleCacheService ModuleCacheService, moduleCronService ModuleCronService,
+	siemExportService siem.SiemExportService, userRepository repository.UserRepository) *ModuleServiceImpl {
 	return &ModuleServiceImpl{
 		logger:                         logger,
 		serverEnvConfig:                serverEnvConfig,
@@ -59,7 +68,32 @@ func NewModuleServiceImpl(logger *zap.SugaredLogger, serverEnvConfig *serverEnvC
 		serverCacheService:             serverCacheService,
 		moduleCacheService:             moduleCacheService,
 		moduleCronService:              moduleCronService,
+		siemExportService:              siemExportService,
+		userRepository:                 userRepository,
+	}
+}
+
+// toSiemEvent maps the module action audit log to siem event, version requested is sent as diff
+func (impl ModuleServiceImpl) toSiemEvent(moduleActionAuditLog *ModuleActionAuditLog) *siem.SiemEvent {
+	event := &siem.SiemEvent{
+		Id:           fmt.Sprintf("%s-%d", siem.CATEGORY_MODULE_ACTION, moduleActionAuditLog.Id),
+		Category:     siem.CATEGORY_MODULE_ACTION,
+		Action:       moduleActionAuditLog.Action,
+		ActorId:      moduleActionAuditLog.CreatedBy,
+		ResourceType: SIEM_RESOURCE_TYPE_MODULE,
+		ResourceName: moduleActionAuditLog.ModuleName,
+		CreatedOn:    moduleActionAuditLog.CreatedOn,
+	}
+	user, err := impl.userRepository.GetByIdIncludeDeleted(moduleActionAuditLog.CreatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user for siem event", "userId", moduleActionAuditLog.CreatedBy, "err", err)
+	} else {
+		event.ActorEmail = user.EmailId
+	}
+	if diff, err := json.Marshal(map[string]string{"version": moduleActionAuditLog.Version}); err == nil {
+		event.Diff = string(diff)
 	}
+	return event
 }
 
 func (impl ModuleServiceImpl) GetModuleInfo(name string) (*ModuleInfoDto, error) {
@@ -108,6 +142,7 @@ func (impl ModuleServiceImpl) HandleModuleAction(userId int32, moduleName string
 		impl.logger.Errorw("error in saving into audit log for module action ", "err", err)
 		return nil, err
 	}
+	impl.siemExportService.Export(impl.toSiemEvent(moduleActionAuditLog))
 
 	// get module by name
 	// if error, throw error
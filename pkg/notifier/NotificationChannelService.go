@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// NotificationChannelConfigDto is the config of a channel delivered by orchestrator itself (teams, webhook and
+// pagerduty), fields not applicable to a channel are ignored
+type NotificationChannelConfigDto struct {
+	Id          int               `json:"id" validate:"number"`
+	ConfigName  string            `json:"configName" validate:"required"`
+	Description string            `json:"description"`
+	OwnerId     int32             `json:"userId"`
+	WebhookUrl  string            `json:"webhookUrl,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Payload     string            `json:"payload,omitempty"`
+	Secret      string            `json:"secret,omitempty"`
+	RoutingKey  string            `json:"routingKey,omitempty"`
+	Severity    string            `json:"severity,omitempty"`
+}
+
+type NotificationChannelRequest struct {
+	Channel util2.Channel                   `json:"channel" validate:"required"`
+	Configs []*NotificationChannelConfigDto `json:"configs" validate:"required,dive"`
+}
+
+type NotificationChannelDeleteRequest struct {
+	Channel util2.Channel `json:"channel" validate:"required"`
+	Id      int           `json:"id" validate:"required"`
+}
+
+// NotificationChannelEvent is the channel agnostic view of a notification event
+type NotificationChannelEvent struct {
+	EventTypeId  int    `json:"eventTypeId"`
+	EventType    string `json:"eventType"`
+	PipelineType string `json:"pipelineType"`
+	PipelineId   int    `json:"pipelineId"`
+	TeamId       int    `json:"teamId"`
+	AppId        int    `json:"appId"`
+	EnvId        int    `json:"envId"`
+	AppName      string `json:"appName"`
+	EnvName      string `json:"envName"`
+	PipelineName string `json:"pipelineName"`
+	Stage        string `json:"stage"`
+	TriggeredBy  string `json:"triggeredBy"`
+	EventTime    string `json:"eventTime"`
+	Link         string `json:"link"`
+	// Message describes events not related to a pipeline run, like expiry of a cve policy exception
+	Message string `json:"message,omitempty"`
+	// Event is the complete event as sent to notifier, available to webhook payload templates
+	Event map[string]interface{} `json:"event"`
+	// CustomMessages are the messages rendered from notification templates of channels, default message of a channel
+	// is sent when it has none
+	CustomMessages map[util2.Channel]string `json:"-"`
+}
+
+func GetEventTypeName(eventTypeId int) string {
+	switch util2.EventType(eventTypeId) {
+	case util2.Trigger:
+		return "trigger"
+	case util2.Success:
+		return "success"
+	case util2.Fail:
+		return "fail"
+	case util2.CveExceptionExpiry:
+		return "cveExceptionExpiry"
+	case util2.BlockedCveDetected:
+		return "blockedCveDetected"
+	case util2.RoleGrantExpiry:
+		return "roleGrantExpiry"
+	case util2.GitOpsDriftDetected:
+		return "gitOpsDriftDetected"
+	case util2.DeploymentWindowOverridden:
+		return "deploymentWindowOverridden"
+	}
+	return ""
+}
+
+// GetSummary returns a one line description of the event like "Deployment failed: app/env (pipeline)"
+func (event *NotificationChannelEvent) GetSummary() string {
+	if len(event.Message) > 0 {
+		return event.Message
+	}
+	subject := "Build"
+	target := event.AppName
+	if event.PipelineType == string(util2.CD) {
+		subject = "Deployment"
+		if event.Stage == "PRE" {
+			subject = "Pre-deployment"
+		} else if event.Stage == "POST" {
+			subject = "Post-deployment"
+		}
+		target = fmt.Sprintf("%s/%s", event.AppName, event.EnvName)
+	}
+	action := "triggered"
+	if event.EventTypeId == int(util2.Success) {
+		action = "succeeded"
+	} else if event.EventTypeId == int(util2.Fail) {
+		action = "failed"
+	}
+	return fmt.Sprintf("%s %s: %s (%s)", subject, action, target, event.PipelineName)
+}
+
+// NotificationChannelService is implemented by every channel delivered by orchestrator itself
+type NotificationChannelService interface {
+	GetChannel() util2.Channel
+	SaveOrEditNotificationConfig(configs []*NotificationChannelConfigDto, userId int32) ([]int, error)
+	FetchNotificationConfigById(id int) (*NotificationChannelConfigDto, error)
+	FetchAllNotificationConfig() ([]*NotificationChannelConfigDto, error)
+	FetchConfigNamesByIds(ids []int) (map[int]string, error)
+	DeleteNotificationConfig(id int, userId int32) error
+	SendNotification(configId int, event *NotificationChannelEvent) error
+}
+
+type NotificationChannelRegistry interface {
+	GetChannelService(channel util2.Channel) (NotificationChannelService, bool)
+	GetChannelServices() []NotificationChannelService
+	// DispatchEvent delivers the event to every config of registered channels subscribed to it in notification settings
+	DispatchEvent(event *NotificationChannelEvent)
+}
+
+type NotificationChannelRegistryImpl struct {
+	logger                         *zap.SugaredLogger
+	notificationSettingsRepository repository.NotificationSettingsRepository
+	channelServices                []NotificationChannelService
+}
+
+func NewNotificationChannelRegistryImpl(logger *zap.SugaredLogger, notificationSettingsRepository repository.NotificationSettingsRepository,
+	teamsService *TeamsNotificationServiceImpl, webhookService *WebhookNotificationServiceImpl,
+	pagerDutyService *PagerDutyNotificationServiceImpl) *NotificationChannelRegistryImpl {
+	return &NotificationChannelRegistryImpl{
+		logger:                         logger,
+		notificationSettingsRepository: notificationSettingsRepository,
+		channelServices:                []NotificationChannelService{teamsService, webhookService, pagerDutyService},
+	}
+}
+
+func (impl *NotificationChannelRegistryImpl) GetChannelService(channel util2.Channel) (NotificationChannelService, bool) {
+	for _, channelService := range impl.channelServices {
+		if channelService.GetChannel() == channel {
+			return channelService, true
+		}
+	}
+	return nil, false
+}
+
+func (impl *NotificationChannelRegistryImpl) GetChannelServices() []NotificationChannelService {
+	return impl.channelServices
+}
+
+func (impl *NotificationChannelRegistryImpl) DispatchEvent(event *NotificationChannelEvent) {
+	settings, err := impl.notificationSettingsRepository.FindNotificationSettingsForEvent(event.EventTypeId, event.PipelineType,
+		event.PipelineId, event.TeamId, event.AppId, event.EnvId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching notification settings for event", "err", err, "event", event)
+		return
+	}
+	// a config subscribed through multiple settings is notified once
+	notified := make(map[string]bool)
+	for _, setting := range settings {
+		var providers []Provider
+		err = json.Unmarshal([]byte(setting.Config), &providers)
+		if err != nil {
+			impl.logger.Errorw("error in parsing providers of notification setting", "err", err, "settingId", setting.Id)
+			continue
+		}
+		for _, provider := range providers {
+			channelService, ok := impl.GetChannelService(provider.Destination)
+			if !ok || provider.ConfigId == 0 {
+				continue
+			}
+			key := fmt.Sprintf("%s/%d", provider.Destination, provider.ConfigId)
+			if notified[key] {
+				continue
+			}
+			notified[key] = true
+			err = channelService.SendNotification(provider.ConfigId, event)
+			if err != nil {
+				impl.logger.Errorw("error in sending notification", "err", err, "channel", provider.Destination, "configId", provider.ConfigId)
+			}
+		}
+	}
+}
+
+// checkNotificationConfigNotInUse returns error if any notification setting sends notifications to the config
+func checkNotificationConfigNotInUse(notificationSettingsRepository repository.NotificationSettingsRepository, configId int, channel util2.Channel) error {
+	notifications, err := notificationSettingsRepository.FindNotificationSettingsByConfigIdAndConfigType(configId, string(channel))
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	if len(notifications) > 0 {
+		return fmt.Errorf(" Please delete all notifications using this config before deleting")
+	}
+	return nil
+}
+
+// notificationTimeout bounds delivery of a notification to a config, slow receivers must not hold dispatch of events
+const notificationTimeout = 30 * time.Second
+
+func postNotification(client *http.Client, url string, header map[string]string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("notification rejected with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
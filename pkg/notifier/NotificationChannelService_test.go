@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeChannelSettingsRepository struct {
+	repository.NotificationSettingsRepository
+	settings []*repository.NotificationSettings
+}
+
+func (f *fakeChannelSettingsRepository) FindNotificationSettingsForEvent(eventTypeId int, pipelineType string, pipelineId int, teamId int, appId int, envId int) ([]*repository.NotificationSettings, error) {
+	return f.settings, nil
+}
+
+type fakeChannelService struct {
+	NotificationChannelService
+	channel   util2.Channel
+	configIds []int
+}
+
+func (f *fakeChannelService) GetChannel() util2.Channel {
+	return f.channel
+}
+
+func (f *fakeChannelService) SendNotification(configId int, event *NotificationChannelEvent) error {
+	f.configIds = append(f.configIds, configId)
+	return nil
+}
+
+func TestNotificationChannelRegistry_DispatchEvent(t *testing.T) {
+	settingsRepository := &fakeChannelSettingsRepository{settings: []*repository.NotificationSettings{
+		{Id: 1, Config: `[{"dest": "webhook", "configId": 1}, {"dest": "teams", "configId": 2}, {"dest": "slack", "configId": 3}]`},
+		// app and team level settings subscribing the same config
+		{Id: 2, Config: `[{"dest": "webhook", "configId": 1}, {"dest": "webhook", "configId": 4}]`},
+		{Id: 3, Config: `not json`},
+	}}
+	webhookService := &fakeChannelService{channel: util2.Webhook}
+	teamsService := &fakeChannelService{channel: util2.Teams}
+	impl := &NotificationChannelRegistryImpl{logger: zap.NewNop().Sugar(), notificationSettingsRepository: settingsRepository,
+		channelServices: []NotificationChannelService{webhookService, teamsService}}
+
+	impl.DispatchEvent(&NotificationChannelEvent{EventTypeId: int(util2.Fail), PipelineType: string(util2.CD)})
+	assert.Equal(t, []int{1, 4}, webhookService.configIds)
+	assert.Equal(t, []int{2}, teamsService.configIds)
+}
@@ -58,6 +58,7 @@ type NotificationConfigServiceImpl struct {
 	appRepository                  app.AppRepository
 	userRepository                 repository4.UserRepository
 	ciPipelineMaterialRepository   pipelineConfig.CiPipelineMaterialRepository
+	channelRegistry                NotificationChannelRegistry
 }
 
 type NotificationSettingRequest struct {
@@ -167,7 +168,8 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 	sesRepository repository.SESNotificationRepository, smtpRepository repository.SMTPNotificationRepository,
 	teamRepository repository2.TeamRepository,
 	environmentRepository repository3.EnvironmentRepository, appRepository app.AppRepository,
-	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository) *NotificationConfigServiceImpl {
+	userRepository repository4.UserRepository, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	channelRegistry NotificationChannelRegistry) *NotificationConfigServiceImpl {
 	return &NotificationConfigServiceImpl{
 		logger:                         logger,
 		notificationSettingsRepository: notificationSettingsRepository,
@@ -182,6 +184,7 @@ func NewNotificationConfigServiceImpl(logger *zap.SugaredLogger, notificationSet
 		appRepository:                  appRepository,
 		userRepository:                 userRepository,
 		ciPipelineMaterialRepository:   ciPipelineMaterialRepository,
+		channelRegistry:                channelRegistry,
 	}
 }
 
@@ -367,6 +370,7 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 			var slackIds []*int
 			var sesUserIds []int32
 			var smtpUserIds []int32
+			channelConfigIds := make(map[util.Channel][]int)
 			var providerConfigs []*ProvidersConfig
 			for _, item := range config.Providers {
 				// if item.ConfigId > 0 that means, user is of user repository, else user email is custom
@@ -377,6 +381,8 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 						sesUserIds = append(sesUserIds, int32(item.ConfigId))
 					} else if item.Destination == util.SMTP {
 						smtpUserIds = append(smtpUserIds, int32(item.ConfigId))
+					} else if _, ok := impl.channelRegistry.GetChannelService(item.Destination); ok {
+						channelConfigIds[item.Destination] = append(channelConfigIds[item.Destination], item.ConfigId)
 					}
 				} else {
 					providerConfigs = append(providerConfigs, &ProvidersConfig{Dest: string(item.Destination), Recipient: item.Recipient})
@@ -413,6 +419,17 @@ func (impl *NotificationConfigServiceImpl) BuildNotificationSettingsResponse(not
 					providerConfigs = append(providerConfigs, &ProvidersConfig{Id: int(item.Id), ConfigName: item.EmailId, Dest: string(util.SMTP)})
 				}
 			}
+			for channel, configIds := range channelConfigIds {
+				channelService, _ := impl.channelRegistry.GetChannelService(channel)
+				configNames, err := channelService.FetchConfigNamesByIds(configIds)
+				if err != nil {
+					impl.logger.Errorw("error in fetching notification channel config", "err", err, "channel", channel)
+					return notificationSettingsResponses, deletedItemCount, err
+				}
+				for id, configName := range configNames {
+					providerConfigs = append(providerConfigs, &ProvidersConfig{Id: id, ConfigName: configName, Dest: string(channel)})
+				}
+			}
 			notificationSettingsResponse.ProvidersConfig = providerConfigs
 		}
 
@@ -471,6 +488,7 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 		sesConfigNamesMap := map[int]string{}
 		slackConfigNameMap := map[int]string{}
 		smtpConfigNamesMap := map[int]string{}
+		channelConfigNamesMap := map[util.Channel]map[int]string{}
 		for _, c := range config.Providers {
 			if util.Slack == c.Destination {
 				if _, ok := slackConfigNameMap[c.ConfigId]; ok {
@@ -487,6 +505,8 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 					continue
 				}
 				smtpConfigNamesMap[c.ConfigId] = ""
+			} else if _, ok := impl.channelRegistry.GetChannelService(c.Destination); ok {
+				channelConfigNamesMap[c.Destination] = nil
 			}
 		}
 
@@ -534,6 +554,21 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 				smtpConfigNamesMap[s.Id] = s.ConfigName
 			}
 		}
+		for channel := range channelConfigNamesMap {
+			var configIds []int
+			for _, c := range config.Providers {
+				if c.Destination == channel {
+					configIds = append(configIds, c.ConfigId)
+				}
+			}
+			channelService, _ := impl.channelRegistry.GetChannelService(channel)
+			configNames, err := channelService.FetchConfigNamesByIds(configIds)
+			if err != nil {
+				impl.logger.Errorw("error on fetch notification channel configs", "err", err, "channel", channel)
+				return []ProvidersConfig{}, err
+			}
+			channelConfigNamesMap[channel] = configNames
+		}
 		for _, c := range config.Providers {
 			var configName string
 			if c.Destination == util.Slack {
@@ -542,6 +577,8 @@ func (impl *NotificationConfigServiceImpl) buildProvidersConfig(config config) (
 				configName = sesConfigNamesMap[c.ConfigId]
 			} else if c.Destination == util.SMTP {
 				configName = smtpConfigNamesMap[c.ConfigId]
+			} else if configNames, ok := channelConfigNamesMap[c.Destination]; ok {
+				configName = configNames[c.ConfigId]
 			}
 			providerConfig := ProvidersConfig{
 				Id:         c.ConfigId,
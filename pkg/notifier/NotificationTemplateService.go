@@ -0,0 +1,265 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+)
+
+// NotificationTemplateDto is the template of the message of a channel. Templates are go templates stored by
+// orchestrator and executed against the event sent to notifier, e.g. {{.Payload.AppName}}, {{.Payload.TriggeredBy}} and
+// {{.Payload.MaterialTriggerInfo.GitTriggers}}. The rendered message is sent with the event in customMessages, teams,
+// webhook and pagerduty are delivered by orchestrator, slack, ses and smtp are delivered by notifier which sends the
+// custom message in place of its default template. The default message is sent when a template fails to render
+type NotificationTemplateDto struct {
+	Id           int                `json:"id"`
+	Channel      util2.Channel      `json:"channel" validate:"required"`
+	EventTypeId  int                `json:"eventTypeId" validate:"required,min=1"`
+	PipelineType util2.PipelineType `json:"pipelineType" validate:"required"`
+	TemplateName string             `json:"templateName"`
+	Template     string             `json:"template" validate:"required"`
+}
+
+type NotificationTemplatePreviewRequest struct {
+	// TemplateId of a saved template, Template is used when not given
+	TemplateId   int                `json:"templateId"`
+	Template     string             `json:"template"`
+	EventTypeId  int                `json:"eventTypeId" validate:"required,min=1"`
+	PipelineType util2.PipelineType `json:"pipelineType" validate:"required"`
+	// WorkflowId is the id of ci workflow for CI and of cd workflow runner for CD
+	WorkflowId int `json:"workflowId" validate:"required"`
+}
+
+type NotificationTemplatePreviewResponse struct {
+	Message string `json:"message"`
+	// Error is the reason of rendering failure, default message of the channel is sent in such case
+	Error string `json:"error,omitempty"`
+}
+
+type NotificationTemplateService interface {
+	SaveOrUpdateTemplate(request *NotificationTemplateDto, userId int32) (*NotificationTemplateDto, error)
+	FetchAllTemplates() ([]*NotificationTemplateDto, error)
+	FetchTemplateById(id int) (*NotificationTemplateDto, error)
+	DeleteTemplate(id int, userId int32) error
+	// RenderTemplate executes the template against data, error is returned if template is invalid or execution fails
+	RenderTemplate(notificationTemplate string, data interface{}) (string, error)
+	// RenderTemplates returns the rendered message of every channel having a template for the event, channels whose
+	// template fails to render are left out so that their default message is sent
+	RenderTemplates(eventTypeId int, pipelineType string, data interface{}) map[util2.Channel]string
+}
+
+type NotificationTemplateServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	notificationTemplatesRepository repository.NotificationTemplatesRepository
+}
+
+func NewNotificationTemplateServiceImpl(logger *zap.SugaredLogger,
+	notificationTemplatesRepository repository.NotificationTemplatesRepository) *NotificationTemplateServiceImpl {
+	return &NotificationTemplateServiceImpl{
+		logger:                          logger,
+		notificationTemplatesRepository: notificationTemplatesRepository,
+	}
+}
+
+// templateChannels are the channels whose messages can be replaced by a template
+var templateChannels = map[util2.Channel]bool{util2.Slack: true, util2.SES: true, util2.SMTP: true,
+	util2.Teams: true, util2.Webhook: true, util2.PagerDuty: true}
+
+func (impl *NotificationTemplateServiceImpl) SaveOrUpdateTemplate(request *NotificationTemplateDto, userId int32) (*NotificationTemplateDto, error) {
+	err := validateNotificationTemplate(request)
+	if err != nil {
+		impl.logger.Errorw("invalid notification template", "err", err, "request", request)
+		return nil, err
+	}
+	dbConnection := impl.notificationTemplatesRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	var model *repository.NotificationTemplates
+	if request.Id > 0 {
+		model, err = impl.notificationTemplatesRepository.FindByIdForUpdate(request.Id, tx)
+	} else {
+		// a template already configured for the combination is replaced
+		model, err = impl.notificationTemplatesRepository.FindByChannelTypeEventTypeIdAndPipelineTypeForUpdate(string(request.Channel),
+			request.EventTypeId, string(request.PipelineType), tx)
+	}
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching notification template", "err", err, "request", request)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) && request.Id > 0 {
+		return nil, err
+	}
+	if model == nil || model.Id == 0 {
+		model = &repository.NotificationTemplates{
+			Active:   true,
+			AuditLog: sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId},
+		}
+	}
+	model.ChannelType = string(request.Channel)
+	model.EventTypeId = request.EventTypeId
+	model.PipelineType = string(request.PipelineType)
+	model.TemplateName = request.TemplateName
+	model.Template = request.Template
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	if model.Id > 0 {
+		_, err = impl.notificationTemplatesRepository.Update(model, tx)
+	} else {
+		_, err = impl.notificationTemplatesRepository.Save(model, tx)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving notification template", "err", err, "request", request)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return adaptNotificationTemplate(model), nil
+}
+
+func (impl *NotificationTemplateServiceImpl) FetchAllTemplates() ([]*NotificationTemplateDto, error) {
+	templates, err := impl.notificationTemplatesRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching notification templates", "err", err)
+		return nil, err
+	}
+	templateDtos := make([]*NotificationTemplateDto, 0, len(templates))
+	for _, notificationTemplate := range templates {
+		templateDtos = append(templateDtos, adaptNotificationTemplate(notificationTemplate))
+	}
+	return templateDtos, nil
+}
+
+func (impl *NotificationTemplateServiceImpl) FetchTemplateById(id int) (*NotificationTemplateDto, error) {
+	notificationTemplate, err := impl.notificationTemplatesRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching notification template", "err", err, "id", id)
+		return nil, err
+	}
+	return adaptNotificationTemplate(notificationTemplate), nil
+}
+
+func (impl *NotificationTemplateServiceImpl) DeleteTemplate(id int, userId int32) error {
+	notificationTemplate, err := impl.notificationTemplatesRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("No matching entry found for delete", "err", err, "id", id)
+		return err
+	}
+	notificationTemplate.Active = false
+	notificationTemplate.UpdatedOn = time.Now()
+	notificationTemplate.UpdatedBy = userId
+
+	dbConnection := impl.notificationTemplatesRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	_, err = impl.notificationTemplatesRepository.Update(notificationTemplate, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting notification template", "err", err, "id", id)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *NotificationTemplateServiceImpl) RenderTemplate(notificationTemplate string, data interface{}) (string, error) {
+	tmpl, err := parseNotificationTemplate(notificationTemplate)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func (impl *NotificationTemplateServiceImpl) RenderTemplates(eventTypeId int, pipelineType string, data interface{}) map[util2.Channel]string {
+	messages := make(map[util2.Channel]string)
+	templates, err := impl.notificationTemplatesRepository.FindByEventTypeIdAndPipelineType(eventTypeId, pipelineType)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching notification templates, using default messages", "err", err, "eventTypeId", eventTypeId)
+		return messages
+	}
+	for _, notificationTemplate := range templates {
+		message, err := impl.RenderTemplate(notificationTemplate.Template, data)
+		if err != nil {
+			impl.logger.Errorw("error in rendering notification template, using default message", "err", err,
+				"templateId", notificationTemplate.Id, "channel", notificationTemplate.ChannelType)
+			continue
+		}
+		messages[util2.Channel(notificationTemplate.ChannelType)] = message
+	}
+	return messages
+}
+
+func adaptNotificationTemplate(notificationTemplate *repository.NotificationTemplates) *NotificationTemplateDto {
+	return &NotificationTemplateDto{
+		Id:           notificationTemplate.Id,
+		Channel:      util2.Channel(notificationTemplate.ChannelType),
+		EventTypeId:  notificationTemplate.EventTypeId,
+		PipelineType: util2.PipelineType(notificationTemplate.PipelineType),
+		TemplateName: notificationTemplate.TemplateName,
+		Template:     notificationTemplate.Template,
+	}
+}
+
+// parseNotificationTemplate parses a go template of a notification, the json function embeds a value as escaped json
+func parseNotificationTemplate(notificationTemplate string) (*template.Template, error) {
+	return template.New("notification").Option("missingkey=zero").Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+	}).Parse(notificationTemplate)
+}
+
+// validateNotificationTemplate checks the channel, pipeline type and syntax of a template, failures in executing a
+// valid template are only known at render and fall back to the default message
+func validateNotificationTemplate(request *NotificationTemplateDto) error {
+	if !templateChannels[request.Channel] {
+		return fmt.Errorf("unsupported channel %s", request.Channel)
+	}
+	if request.PipelineType != util2.CI && request.PipelineType != util2.CD {
+		return fmt.Errorf("unsupported pipeline type %s", request.PipelineType)
+	}
+	_, err := parseNotificationTemplate(request.Template)
+	if err != nil {
+		return fmt.Errorf("invalid template: %s", err.Error())
+	}
+	return nil
+}
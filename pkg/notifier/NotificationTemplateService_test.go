@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeNotificationTemplatesRepository struct {
+	repository.NotificationTemplatesRepository
+	templates []*repository.NotificationTemplates
+}
+
+func (f *fakeNotificationTemplatesRepository) FindByEventTypeIdAndPipelineType(eventTypeId int, pipelineType string) ([]*repository.NotificationTemplates, error) {
+	return f.templates, nil
+}
+
+type testTemplateEvent struct {
+	EventTypeId int
+	Payload     *testTemplatePayload
+}
+
+type testTemplatePayload struct {
+	AppName     string
+	TriggeredBy string
+	Tags        []string
+}
+
+func TestValidateNotificationTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *NotificationTemplateDto
+		valid   bool
+	}{
+		{name: "slack", request: &NotificationTemplateDto{Channel: util2.Slack, EventTypeId: int(util2.Fail), PipelineType: util2.CD, Template: "{{.Payload.AppName}} failed"}, valid: true},
+		{name: "event type added later", request: &NotificationTemplateDto{Channel: util2.Webhook, EventTypeId: int(util2.DeploymentWindowOverridden), PipelineType: util2.CD, Template: "{{json .Payload}}"}, valid: true},
+		{name: "unknown channel", request: &NotificationTemplateDto{Channel: "sms", EventTypeId: int(util2.Fail), PipelineType: util2.CD, Template: "failed"}},
+		{name: "unknown pipeline type", request: &NotificationTemplateDto{Channel: util2.SES, EventTypeId: int(util2.Fail), PipelineType: "JOB", Template: "failed"}},
+		{name: "unclosed action", request: &NotificationTemplateDto{Channel: util2.SMTP, EventTypeId: int(util2.Fail), PipelineType: util2.CI, Template: "{{range .Payload.Tags}}{{.}}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNotificationTemplate(tt.request)
+			assert.Equal(t, tt.valid, err == nil, "err %v", err)
+		})
+	}
+}
+
+func TestNotificationTemplateService_RenderTemplate(t *testing.T) {
+	impl := NewNotificationTemplateServiceImpl(zap.NewNop().Sugar(), nil)
+	event := &testTemplateEvent{Payload: &testTemplatePayload{AppName: "app-one", TriggeredBy: "admin", Tags: []string{"a", "b"}}}
+
+	message, err := impl.RenderTemplate(" {{.Payload.AppName}} by {{.Payload.TriggeredBy}} {{json .Payload.Tags}}\n", event)
+	assert.Nil(t, err)
+	assert.Equal(t, `app-one by admin ["a","b"]`, message)
+
+	_, err = impl.RenderTemplate("{{.Payload.Unknown}}", event)
+	assert.NotNil(t, err)
+}
+
+func TestNotificationTemplateService_RenderTemplates(t *testing.T) {
+	templatesRepository := &fakeNotificationTemplatesRepository{templates: []*repository.NotificationTemplates{
+		{Id: 1, ChannelType: string(util2.Slack), Template: "{{.Payload.AppName}} failed"},
+		{Id: 2, ChannelType: string(util2.Teams), Template: "{{.Payload.AppName}} failed on teams"},
+		// fails on execution, default message of the channel is sent
+		{Id: 3, ChannelType: string(util2.SES), Template: "{{.Payload.Unknown}}"},
+		{Id: 4, ChannelType: string(util2.Webhook), Template: "{{index .Payload.Tags 5}}"},
+	}}
+	impl := NewNotificationTemplateServiceImpl(zap.NewNop().Sugar(), templatesRepository)
+
+	messages := impl.RenderTemplates(int(util2.Fail), string(util2.CD), &testTemplateEvent{Payload: &testTemplatePayload{AppName: "app-one"}})
+	assert.Equal(t, map[util2.Channel]string{util2.Slack: "app-one failed", util2.Teams: "app-one failed on teams"}, messages)
+
+	// payload missing from the event
+	messages = impl.RenderTemplates(int(util2.Fail), string(util2.CD), &testTemplateEvent{})
+	assert.Empty(t, messages)
+}
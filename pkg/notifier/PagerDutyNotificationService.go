@@ -0,0 +1,268 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+)
+
+const PAGER_DUTY_EVENTS_URL = "https://events.pagerduty.com/v2/enqueue"
+
+const (
+	PAGER_DUTY_ACTION_TRIGGER = "trigger"
+	PAGER_DUTY_ACTION_RESOLVE = "resolve"
+)
+
+// pagerDutySummaryMaxLength is the limit of summary of an event, longer summaries are truncated by pagerduty
+const pagerDutySummaryMaxLength = 1024
+
+var pagerDutySeverities = map[string]bool{"critical": true, "error": true, "warning": true, "info": true}
+
+type PagerDutyNotificationServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	client                         *http.Client
+	pagerDutyRepository            repository.PagerDutyNotificationRepository
+	notificationSettingsRepository repository.NotificationSettingsRepository
+}
+
+func NewPagerDutyNotificationServiceImpl(logger *zap.SugaredLogger, client *http.Client, pagerDutyRepository repository.PagerDutyNotificationRepository,
+	notificationSettingsRepository repository.NotificationSettingsRepository) *PagerDutyNotificationServiceImpl {
+	return &PagerDutyNotificationServiceImpl{
+		logger:                         logger,
+		client:                         client,
+		pagerDutyRepository:            pagerDutyRepository,
+		notificationSettingsRepository: notificationSettingsRepository,
+	}
+}
+
+// pagerDutyEvent is the request of pagerduty events api v2
+type pagerDutyEvent struct {
+	RoutingKey  string                `json:"routing_key"`
+	EventAction string                `json:"event_action"`
+	DedupKey    string                `json:"dedup_key"`
+	Payload     *pagerDutyEventDetail `json:"payload,omitempty"`
+	Links       []pagerDutyEventLink  `json:"links,omitempty"`
+}
+
+type pagerDutyEventDetail struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+type pagerDutyEventLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+func (impl *PagerDutyNotificationServiceImpl) GetChannel() util2.Channel {
+	return util2.PagerDuty
+}
+
+func (impl *PagerDutyNotificationServiceImpl) SaveOrEditNotificationConfig(configs []*NotificationChannelConfigDto, userId int32) ([]int, error) {
+	var responseIds []int
+	for _, config := range configs {
+		if len(config.RoutingKey) == 0 {
+			return []int{}, fmt.Errorf("routing key is required for pagerduty config %s", config.ConfigName)
+		}
+		if len(config.Severity) > 0 && !pagerDutySeverities[config.Severity] {
+			return []int{}, fmt.Errorf("invalid severity %s for pagerduty config %s", config.Severity, config.ConfigName)
+		}
+		if config.Id != 0 {
+			model, err := impl.pagerDutyRepository.FindOne(config.Id)
+			if err != nil {
+				impl.logger.Errorw("err while fetching pagerduty config", "err", err, "id", config.Id)
+				return []int{}, err
+			}
+			model.RoutingKey = config.RoutingKey
+			model.Severity = config.Severity
+			model.ConfigName = config.ConfigName
+			model.Description = config.Description
+			model.UpdatedOn = time.Now()
+			model.UpdatedBy = userId
+			_, err = impl.pagerDutyRepository.UpdatePagerDutyConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while updating pagerduty config", "err", err)
+				return []int{}, err
+			}
+		} else {
+			model := &repository.PagerDutyConfig{
+				RoutingKey:  config.RoutingKey,
+				Severity:    config.Severity,
+				ConfigName:  config.ConfigName,
+				Description: config.Description,
+				OwnerId:     userId,
+				AuditLog: sql.AuditLog{
+					CreatedBy: userId,
+					CreatedOn: time.Now(),
+					UpdatedOn: time.Now(),
+					UpdatedBy: userId,
+				},
+			}
+			_, err := impl.pagerDutyRepository.SavePagerDutyConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while inserting pagerduty config", "err", err)
+				return []int{}, err
+			}
+			config.Id = model.Id
+		}
+		responseIds = append(responseIds, config.Id)
+	}
+	return responseIds, nil
+}
+
+func (impl *PagerDutyNotificationServiceImpl) FetchNotificationConfigById(id int) (*NotificationChannelConfigDto, error) {
+	pagerDutyConfig, err := impl.pagerDutyRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("cannot find pagerduty config", "err", err, "id", id)
+		return nil, err
+	}
+	return impl.adaptPagerDutyConfig(pagerDutyConfig), nil
+}
+
+func (impl *PagerDutyNotificationServiceImpl) FetchAllNotificationConfig() ([]*NotificationChannelConfigDto, error) {
+	responseDto := make([]*NotificationChannelConfigDto, 0)
+	pagerDutyConfigs, err := impl.pagerDutyRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all pagerduty config", "err", err)
+		return responseDto, err
+	}
+	for _, pagerDutyConfig := range pagerDutyConfigs {
+		responseDto = append(responseDto, impl.adaptPagerDutyConfig(pagerDutyConfig))
+	}
+	return responseDto, nil
+}
+
+func (impl *PagerDutyNotificationServiceImpl) FetchConfigNamesByIds(ids []int) (map[int]string, error) {
+	configNames := make(map[int]string)
+	if len(ids) == 0 {
+		return configNames, nil
+	}
+	pagerDutyConfigs, err := impl.pagerDutyRepository.FindByIdsIn(ids)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching pagerduty configs", "err", err)
+		return configNames, err
+	}
+	for _, pagerDutyConfig := range pagerDutyConfigs {
+		configNames[pagerDutyConfig.Id] = pagerDutyConfig.ConfigName
+	}
+	return configNames, nil
+}
+
+func (impl *PagerDutyNotificationServiceImpl) adaptPagerDutyConfig(pagerDutyConfig *repository.PagerDutyConfig) *NotificationChannelConfigDto {
+	return &NotificationChannelConfigDto{
+		Id:          pagerDutyConfig.Id,
+		ConfigName:  pagerDutyConfig.ConfigName,
+		Description: pagerDutyConfig.Description,
+		OwnerId:     pagerDutyConfig.OwnerId,
+		RoutingKey:  pagerDutyConfig.RoutingKey,
+		Severity:    pagerDutyConfig.Severity,
+	}
+}
+
+func (impl *PagerDutyNotificationServiceImpl) DeleteNotificationConfig(id int, userId int32) error {
+	existingConfig, err := impl.pagerDutyRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("No matching entry found for delete", "err", err, "id", id)
+		return err
+	}
+	err = checkNotificationConfigNotInUse(impl.notificationSettingsRepository, id, util2.PagerDuty)
+	if err != nil {
+		impl.logger.Errorw("pagerduty config cannot be deleted", "err", err, "id", id)
+		return err
+	}
+	existingConfig.UpdatedOn = time.Now()
+	existingConfig.UpdatedBy = userId
+	err = impl.pagerDutyRepository.MarkPagerDutyConfigDeleted(existingConfig)
+	if err != nil {
+		impl.logger.Errorw("error in deleting pagerduty config", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+// SendNotification triggers an incident on failure and resolves it on the next success of the same pipeline, trigger
+// events are not sent as they are not actionable
+func (impl *PagerDutyNotificationServiceImpl) SendNotification(configId int, event *NotificationChannelEvent) error {
+	var eventAction string
+	if event.EventTypeId == int(util2.Fail) {
+		eventAction = PAGER_DUTY_ACTION_TRIGGER
+	} else if event.EventTypeId == int(util2.Success) {
+		eventAction = PAGER_DUTY_ACTION_RESOLVE
+	} else {
+		return nil
+	}
+	pagerDutyConfig, err := impl.pagerDutyRepository.FindOne(configId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pagerduty config", "err", err, "id", configId)
+		return err
+	}
+	request := &pagerDutyEvent{
+		RoutingKey:  pagerDutyConfig.RoutingKey,
+		EventAction: eventAction,
+		DedupKey:    fmt.Sprintf("devtron-%s-%d", event.PipelineType, event.PipelineId),
+	}
+	if eventAction == PAGER_DUTY_ACTION_TRIGGER {
+		severity := pagerDutyConfig.Severity
+		if len(severity) == 0 {
+			severity = "error"
+		}
+		summary := event.GetSummary()
+		if message, ok := event.CustomMessages[util2.PagerDuty]; ok && len(message) > 0 {
+			summary = message
+		}
+		if len(summary) > pagerDutySummaryMaxLength {
+			summary = summary[:pagerDutySummaryMaxLength]
+		}
+		request.Payload = &pagerDutyEventDetail{
+			Summary:   summary,
+			Source:    "devtron",
+			Severity:  severity,
+			Component: event.AppName,
+			Group:     event.EnvName,
+			Class:     event.PipelineType,
+			CustomDetails: map[string]interface{}{
+				"pipeline":    event.PipelineName,
+				"stage":       event.Stage,
+				"triggeredBy": event.TriggeredBy,
+				"eventTime":   event.EventTime,
+			},
+		}
+		if len(event.Link) > 0 {
+			request.Links = []pagerDutyEventLink{{Href: event.Link, Text: "View in Devtron"}}
+		}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return postNotification(impl.client, PAGER_DUTY_EVENTS_URL, nil, body)
+}
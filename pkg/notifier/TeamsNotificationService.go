@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+)
+
+type TeamsNotificationServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	client                         *http.Client
+	teamsRepository                repository.TeamsNotificationRepository
+	notificationSettingsRepository repository.NotificationSettingsRepository
+}
+
+func NewTeamsNotificationServiceImpl(logger *zap.SugaredLogger, client *http.Client, teamsRepository repository.TeamsNotificationRepository,
+	notificationSettingsRepository repository.NotificationSettingsRepository) *TeamsNotificationServiceImpl {
+	return &TeamsNotificationServiceImpl{
+		logger:                         logger,
+		client:                         client,
+		teamsRepository:                teamsRepository,
+		notificationSettingsRepository: notificationSettingsRepository,
+	}
+}
+
+// teamsMessageCard is the legacy actionable message card accepted by teams incoming webhooks
+type teamsMessageCard struct {
+	Type            string                `json:"@type"`
+	Context         string                `json:"@context"`
+	ThemeColor      string                `json:"themeColor"`
+	Summary         string                `json:"summary"`
+	Text            string                `json:"text,omitempty"`
+	Sections        []teamsMessageSection `json:"sections,omitempty"`
+	PotentialAction []teamsMessageAction  `json:"potentialAction,omitempty"`
+}
+
+type teamsMessageSection struct {
+	ActivityTitle string             `json:"activityTitle"`
+	Facts         []teamsMessageFact `json:"facts"`
+	Markdown      bool               `json:"markdown"`
+}
+
+type teamsMessageFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type teamsMessageAction struct {
+	Type    string                     `json:"@type"`
+	Name    string                     `json:"name"`
+	Targets []teamsMessageActionTarget `json:"targets"`
+}
+
+type teamsMessageActionTarget struct {
+	Os  string `json:"os"`
+	Uri string `json:"uri"`
+}
+
+func (impl *TeamsNotificationServiceImpl) GetChannel() util2.Channel {
+	return util2.Teams
+}
+
+func (impl *TeamsNotificationServiceImpl) SaveOrEditNotificationConfig(configs []*NotificationChannelConfigDto, userId int32) ([]int, error) {
+	var responseIds []int
+	for _, config := range configs {
+		if len(config.WebhookUrl) == 0 {
+			return []int{}, fmt.Errorf("webhook url is required for teams config %s", config.ConfigName)
+		}
+		if config.Id != 0 {
+			model, err := impl.teamsRepository.FindOne(config.Id)
+			if err != nil {
+				impl.logger.Errorw("err while fetching teams config", "err", err, "id", config.Id)
+				return []int{}, err
+			}
+			model.WebHookUrl = config.WebhookUrl
+			model.ConfigName = config.ConfigName
+			model.Description = config.Description
+			model.UpdatedOn = time.Now()
+			model.UpdatedBy = userId
+			_, err = impl.teamsRepository.UpdateTeamsConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while updating teams config", "err", err)
+				return []int{}, err
+			}
+		} else {
+			model := &repository.TeamsConfig{
+				WebHookUrl:  config.WebhookUrl,
+				ConfigName:  config.ConfigName,
+				Description: config.Description,
+				OwnerId:     userId,
+				AuditLog: sql.AuditLog{
+					CreatedBy: userId,
+					CreatedOn: time.Now(),
+					UpdatedOn: time.Now(),
+					UpdatedBy: userId,
+				},
+			}
+			_, err := impl.teamsRepository.SaveTeamsConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while inserting teams config", "err", err)
+				return []int{}, err
+			}
+			config.Id = model.Id
+		}
+		responseIds = append(responseIds, config.Id)
+	}
+	return responseIds, nil
+}
+
+func (impl *TeamsNotificationServiceImpl) FetchNotificationConfigById(id int) (*NotificationChannelConfigDto, error) {
+	teamsConfig, err := impl.teamsRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("cannot find teams config", "err", err, "id", id)
+		return nil, err
+	}
+	return impl.adaptTeamsConfig(teamsConfig), nil
+}
+
+func (impl *TeamsNotificationServiceImpl) FetchAllNotificationConfig() ([]*NotificationChannelConfigDto, error) {
+	responseDto := make([]*NotificationChannelConfigDto, 0)
+	teamsConfigs, err := impl.teamsRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all teams config", "err", err)
+		return responseDto, err
+	}
+	for _, teamsConfig := range teamsConfigs {
+		responseDto = append(responseDto, impl.adaptTeamsConfig(teamsConfig))
+	}
+	return responseDto, nil
+}
+
+func (impl *TeamsNotificationServiceImpl) FetchConfigNamesByIds(ids []int) (map[int]string, error) {
+	configNames := make(map[int]string)
+	if len(ids) == 0 {
+		return configNames, nil
+	}
+	teamsConfigs, err := impl.teamsRepository.FindByIdsIn(ids)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching teams configs", "err", err)
+		return configNames, err
+	}
+	for _, teamsConfig := range teamsConfigs {
+		configNames[teamsConfig.Id] = teamsConfig.ConfigName
+	}
+	return configNames, nil
+}
+
+func (impl *TeamsNotificationServiceImpl) adaptTeamsConfig(teamsConfig *repository.TeamsConfig) *NotificationChannelConfigDto {
+	return &NotificationChannelConfigDto{
+		Id:          teamsConfig.Id,
+		ConfigName:  teamsConfig.ConfigName,
+		Description: teamsConfig.Description,
+		OwnerId:     teamsConfig.OwnerId,
+		WebhookUrl:  teamsConfig.WebHookUrl,
+	}
+}
+
+func (impl *TeamsNotificationServiceImpl) DeleteNotificationConfig(id int, userId int32) error {
+	existingConfig, err := impl.teamsRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("No matching entry found for delete", "err", err, "id", id)
+		return err
+	}
+	err = checkNotificationConfigNotInUse(impl.notificationSettingsRepository, id, util2.Teams)
+	if err != nil {
+		impl.logger.Errorw("teams config cannot be deleted", "err", err, "id", id)
+		return err
+	}
+	existingConfig.UpdatedOn = time.Now()
+	existingConfig.UpdatedBy = userId
+	err = impl.teamsRepository.MarkTeamsConfigDeleted(existingConfig)
+	if err != nil {
+		impl.logger.Errorw("error in deleting teams config", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (impl *TeamsNotificationServiceImpl) SendNotification(configId int, event *NotificationChannelEvent) error {
+	teamsConfig, err := impl.teamsRepository.FindOne(configId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching teams config", "err", err, "id", configId)
+		return err
+	}
+	body, err := json.Marshal(buildTeamsMessageCard(event))
+	if err != nil {
+		return err
+	}
+	return postNotification(impl.client, teamsConfig.WebHookUrl, nil, body)
+}
+
+func buildTeamsMessageCard(event *NotificationChannelEvent) *teamsMessageCard {
+	themeColor := "0078D7"
+	if event.EventTypeId == int(util2.Success) {
+		themeColor = "1DAD70"
+	} else if event.EventTypeId == int(util2.Fail) {
+		themeColor = "F33E3E"
+	}
+	facts := []teamsMessageFact{{Name: "Application", Value: event.AppName}, {Name: "Pipeline", Value: event.PipelineName}}
+	if len(event.EnvName) > 0 {
+		facts = append(facts, teamsMessageFact{Name: "Environment", Value: event.EnvName})
+	}
+	if len(event.Stage) > 0 {
+		facts = append(facts, teamsMessageFact{Name: "Stage", Value: event.Stage})
+	}
+	if len(event.TriggeredBy) > 0 {
+		facts = append(facts, teamsMessageFact{Name: "Triggered by", Value: event.TriggeredBy})
+	}
+	facts = append(facts, teamsMessageFact{Name: "Time", Value: event.EventTime})
+	card := &teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: themeColor,
+		Summary:    event.GetSummary(),
+	}
+	if message, ok := event.CustomMessages[util2.Teams]; ok {
+		card.Text = message
+	} else {
+		card.Sections = []teamsMessageSection{{ActivityTitle: event.GetSummary(), Facts: facts, Markdown: true}}
+	}
+	if len(event.Link) > 0 {
+		card.PotentialAction = []teamsMessageAction{{
+			Type:    "OpenUri",
+			Name:    "View details",
+			Targets: []teamsMessageActionTarget{{Os: "default", Uri: event.Link}},
+		}}
+	}
+	return card
+}
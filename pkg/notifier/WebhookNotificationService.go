@@ -0,0 +1,341 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	util2 "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+)
+
+const (
+	// WEBHOOK_SIGNATURE_HEADER carries "sha256=<hex hmac>" when the webhook config has a secret, the hmac is of
+	// "<timestamp>.<body>" keyed by the secret. A receiver recomputes it from WEBHOOK_TIMESTAMP_HEADER and the raw
+	// body, compares it in constant time and rejects timestamps older than a few minutes so that a captured
+	// notification can not be replayed
+	WEBHOOK_SIGNATURE_HEADER = "X-Devtron-Signature"
+	// WEBHOOK_TIMESTAMP_HEADER carries the unix time in seconds at which the notification was signed
+	WEBHOOK_TIMESTAMP_HEADER = "X-Devtron-Timestamp"
+)
+
+// secretMask is returned in place of a saved webhook secret, an update with masked or empty secret keeps the saved one
+const secretMask = "********"
+
+type WebhookNotificationServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	client                         *http.Client
+	webhookRepository              repository.WebhookNotificationRepository
+	notificationSettingsRepository repository.NotificationSettingsRepository
+}
+
+func NewWebhookNotificationServiceImpl(logger *zap.SugaredLogger, webhookRepository repository.WebhookNotificationRepository,
+	notificationSettingsRepository repository.NotificationSettingsRepository) *WebhookNotificationServiceImpl {
+	return &WebhookNotificationServiceImpl{
+		logger:                         logger,
+		client:                         newWebhookHttpClient(),
+		webhookRepository:              webhookRepository,
+		notificationSettingsRepository: notificationSettingsRepository,
+	}
+}
+
+func (impl *WebhookNotificationServiceImpl) GetChannel() util2.Channel {
+	return util2.Webhook
+}
+
+func (impl *WebhookNotificationServiceImpl) SaveOrEditNotificationConfig(configs []*NotificationChannelConfigDto, userId int32) ([]int, error) {
+	var responseIds []int
+	for _, config := range configs {
+		if len(config.WebhookUrl) == 0 {
+			return []int{}, fmt.Errorf("webhook url is required for webhook config %s", config.ConfigName)
+		}
+		err := validateWebhookUrl(config.WebhookUrl)
+		if err != nil {
+			impl.logger.Errorw("invalid webhook url", "err", err, "configName", config.ConfigName)
+			return []int{}, fmt.Errorf("invalid url for webhook config %s: %s", config.ConfigName, err.Error())
+		}
+		if len(config.Payload) > 0 {
+			_, err := parseNotificationTemplate(config.Payload)
+			if err != nil {
+				impl.logger.Errorw("invalid webhook payload template", "err", err, "configName", config.ConfigName)
+				return []int{}, fmt.Errorf("invalid payload template for webhook config %s: %s", config.ConfigName, err.Error())
+			}
+		}
+		header, err := json.Marshal(config.Header)
+		if err != nil {
+			return []int{}, err
+		}
+		if config.Id != 0 {
+			model, err := impl.webhookRepository.FindOne(config.Id)
+			if err != nil {
+				impl.logger.Errorw("err while fetching webhook config", "err", err, "id", config.Id)
+				return []int{}, err
+			}
+			model.WebHookUrl = config.WebhookUrl
+			model.ConfigName = config.ConfigName
+			model.Description = config.Description
+			model.Header = string(header)
+			model.Payload = config.Payload
+			if len(config.Secret) > 0 && config.Secret != secretMask {
+				model.Secret = config.Secret
+			}
+			model.UpdatedOn = time.Now()
+			model.UpdatedBy = userId
+			_, err = impl.webhookRepository.UpdateWebhookConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while updating webhook config", "err", err)
+				return []int{}, err
+			}
+		} else {
+			model := &repository.WebhookConfig{
+				WebHookUrl:  config.WebhookUrl,
+				ConfigName:  config.ConfigName,
+				Description: config.Description,
+				Header:      string(header),
+				Payload:     config.Payload,
+				Secret:      config.Secret,
+				OwnerId:     userId,
+				AuditLog: sql.AuditLog{
+					CreatedBy: userId,
+					CreatedOn: time.Now(),
+					UpdatedOn: time.Now(),
+					UpdatedBy: userId,
+				},
+			}
+			_, err = impl.webhookRepository.SaveWebhookConfig(model)
+			if err != nil {
+				impl.logger.Errorw("err while inserting webhook config", "err", err)
+				return []int{}, err
+			}
+			config.Id = model.Id
+		}
+		responseIds = append(responseIds, config.Id)
+	}
+	return responseIds, nil
+}
+
+func (impl *WebhookNotificationServiceImpl) FetchNotificationConfigById(id int) (*NotificationChannelConfigDto, error) {
+	webhookConfig, err := impl.webhookRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("cannot find webhook config", "err", err, "id", id)
+		return nil, err
+	}
+	return impl.adaptWebhookConfig(webhookConfig), nil
+}
+
+func (impl *WebhookNotificationServiceImpl) FetchAllNotificationConfig() ([]*NotificationChannelConfigDto, error) {
+	responseDto := make([]*NotificationChannelConfigDto, 0)
+	webhookConfigs, err := impl.webhookRepository.FindAll()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("cannot find all webhook config", "err", err)
+		return responseDto, err
+	}
+	for _, webhookConfig := range webhookConfigs {
+		responseDto = append(responseDto, impl.adaptWebhookConfig(webhookConfig))
+	}
+	return responseDto, nil
+}
+
+func (impl *WebhookNotificationServiceImpl) FetchConfigNamesByIds(ids []int) (map[int]string, error) {
+	configNames := make(map[int]string)
+	if len(ids) == 0 {
+		return configNames, nil
+	}
+	webhookConfigs, err := impl.webhookRepository.FindByIdsIn(ids)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching webhook configs", "err", err)
+		return configNames, err
+	}
+	for _, webhookConfig := range webhookConfigs {
+		configNames[webhookConfig.Id] = webhookConfig.ConfigName
+	}
+	return configNames, nil
+}
+
+func (impl *WebhookNotificationServiceImpl) adaptWebhookConfig(webhookConfig *repository.WebhookConfig) *NotificationChannelConfigDto {
+	configDto := &NotificationChannelConfigDto{
+		Id:          webhookConfig.Id,
+		ConfigName:  webhookConfig.ConfigName,
+		Description: webhookConfig.Description,
+		OwnerId:     webhookConfig.OwnerId,
+		WebhookUrl:  webhookConfig.WebHookUrl,
+		Payload:     webhookConfig.Payload,
+	}
+	if len(webhookConfig.Header) > 0 {
+		err := json.Unmarshal([]byte(webhookConfig.Header), &configDto.Header)
+		if err != nil {
+			impl.logger.Errorw("error in parsing webhook config header", "err", err, "id", webhookConfig.Id)
+		}
+	}
+	if len(webhookConfig.Secret) > 0 {
+		configDto.Secret = secretMask
+	}
+	return configDto
+}
+
+func (impl *WebhookNotificationServiceImpl) DeleteNotificationConfig(id int, userId int32) error {
+	existingConfig, err := impl.webhookRepository.FindOne(id)
+	if err != nil {
+		impl.logger.Errorw("No matching entry found for delete", "err", err, "id", id)
+		return err
+	}
+	err = checkNotificationConfigNotInUse(impl.notificationSettingsRepository, id, util2.Webhook)
+	if err != nil {
+		impl.logger.Errorw("webhook config cannot be deleted", "err", err, "id", id)
+		return err
+	}
+	existingConfig.UpdatedOn = time.Now()
+	existingConfig.UpdatedBy = userId
+	err = impl.webhookRepository.MarkWebhookConfigDeleted(existingConfig)
+	if err != nil {
+		impl.logger.Errorw("error in deleting webhook config", "err", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (impl *WebhookNotificationServiceImpl) SendNotification(configId int, event *NotificationChannelEvent) error {
+	webhookConfig, err := impl.webhookRepository.FindOne(configId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching webhook config", "err", err, "id", configId)
+		return err
+	}
+	// payload of the config takes precedence over the message template of the channel, which must render valid json
+	var body []byte
+	if len(webhookConfig.Payload) > 0 {
+		body, err = renderWebhookPayload(webhookConfig.Payload, event)
+	} else if message, ok := event.CustomMessages[util2.Webhook]; ok && json.Valid([]byte(message)) {
+		body = []byte(message)
+	} else {
+		body, err = json.Marshal(event)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in building webhook payload", "err", err, "id", configId)
+		return err
+	}
+	header := make(map[string]string)
+	if len(webhookConfig.Header) > 0 {
+		err = json.Unmarshal([]byte(webhookConfig.Header), &header)
+		if err != nil {
+			impl.logger.Errorw("error in parsing webhook config header", "err", err, "id", configId)
+			return err
+		}
+	}
+	if len(webhookConfig.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		header[WEBHOOK_TIMESTAMP_HEADER] = timestamp
+		header[WEBHOOK_SIGNATURE_HEADER] = "sha256=" + signWebhookPayload(webhookConfig.Secret, timestamp, body)
+	}
+	return postNotification(impl.client, webhookConfig.WebHookUrl, header, body)
+}
+
+// renderWebhookPayload executes the payload template of a webhook config against the event, the result must be valid json
+func renderWebhookPayload(payloadTemplate string, event *NotificationChannelEvent) ([]byte, error) {
+	tmpl, err := parseNotificationTemplate(payloadTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, event)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("rendered payload is not valid json")
+	}
+	return buf.Bytes(), nil
+}
+
+func signWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateWebhookUrl allows http and https urls of hosts having public addresses only, so that notifications can not
+// be used to reach devtron itself or other services internal to the cluster
+func validateWebhookUrl(webhookUrl string) error {
+	parsedUrl, err := url.Parse(webhookUrl)
+	if err != nil {
+		return err
+	}
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	host := parsedUrl.Hostname()
+	if len(host) == 0 {
+		return fmt.Errorf("host is required")
+	}
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		ips, err = net.LookupIP(host)
+		if err != nil {
+			return fmt.Errorf("host %s can not be resolved: %s", host, err.Error())
+		}
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("host %s is not a public address", host)
+		}
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// newWebhookHttpClient returns client which connects to public addresses only, the address is checked once resolved
+// so that a host resolving to an internal address after its url was validated, or a redirect to one, is refused too
+func newWebhookHttpClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: notificationTimeout,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("webhook address %s is not a public address", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
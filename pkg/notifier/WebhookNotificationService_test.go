@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeWebhookRepository struct {
+	repository.WebhookNotificationRepository
+	config *repository.WebhookConfig
+}
+
+func (f *fakeWebhookRepository) FindOne(id int) (*repository.WebhookConfig, error) {
+	return f.config, nil
+}
+
+func TestRenderWebhookPayload(t *testing.T) {
+	event := &NotificationChannelEvent{AppName: "app-one", EnvName: "dev", EventType: "fail"}
+
+	body, err := renderWebhookPayload(`{"text": {{ json .AppName }}, "env": "{{ .EnvName }}"}`, event)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"text": "app-one", "env": "dev"}`, string(body))
+
+	_, err = renderWebhookPayload(`{"text": {{ .AppName }}}`, event)
+	assert.NotNil(t, err)
+}
+
+func TestValidateWebhookUrl(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://93.184.216.34/hooks/devtron", valid: true},
+		{url: "ftp://93.184.216.34/hooks/devtron"},
+		{url: "https:///hooks/devtron"},
+		{url: "http://127.0.0.1:8080/orchestrator"},
+		{url: "http://localhost/orchestrator"},
+		{url: "http://10.96.0.1/api"},
+		{url: "http://169.254.169.254/latest/meta-data"},
+		{url: "http://[::1]/hooks"},
+		{url: "http://0.0.0.0/hooks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateWebhookUrl(tt.url)
+			assert.Equal(t, tt.valid, err == nil, "err %v", err)
+		})
+	}
+}
+
+func TestNewWebhookHttpClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := newWebhookHttpClient().Post(server.URL, "application/json", nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is not a public address")
+}
+
+func TestWebhookNotificationService_SendNotificationSigned(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	webhookRepository := &fakeWebhookRepository{config: &repository.WebhookConfig{Id: 1, WebHookUrl: server.URL,
+		Secret: "webhook-secret", Payload: `{"app": "{{ .AppName }}"}`}}
+	// client of the service refuses loopback address of test server
+	impl := &WebhookNotificationServiceImpl{logger: zap.NewNop().Sugar(), client: server.Client(), webhookRepository: webhookRepository}
+
+	err := impl.SendNotification(1, &NotificationChannelEvent{AppName: "app-one"})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"app": "app-one"}`, string(body))
+	timestamp := header.Get(WEBHOOK_TIMESTAMP_HEADER)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(signedAt, 0), time.Minute)
+
+	// receiver verifies signature of timestamp and body
+	mac := hmac.New(sha256.New, []byte("webhook-secret"))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), header.Get(WEBHOOK_SIGNATURE_HEADER))
+	// signature of the body replayed with another timestamp does not match
+	assert.NotEqual(t, signWebhookPayload("webhook-secret", strconv.FormatInt(signedAt+600, 10), body), signWebhookPayload("webhook-secret", timestamp, body))
+}
+
+func TestWebhookNotificationService_SendNotificationUnsigned(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer server.Close()
+	webhookRepository := &fakeWebhookRepository{config: &repository.WebhookConfig{Id: 1, WebHookUrl: server.URL}}
+	impl := &WebhookNotificationServiceImpl{logger: zap.NewNop().Sugar(), client: server.Client(), webhookRepository: webhookRepository}
+
+	err := impl.SendNotification(1, &NotificationChannelEvent{AppName: "app-one"})
+	assert.Nil(t, err)
+	assert.Empty(t, header.Get(WEBHOOK_SIGNATURE_HEADER))
+	assert.Empty(t, header.Get(WEBHOOK_TIMESTAMP_HEADER))
+}
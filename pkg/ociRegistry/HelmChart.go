@@ -0,0 +1,129 @@
+package ociRegistry
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+const (
+	OCIScheme                 = "oci://"
+	HelmChartConfigMediaType  = "application/vnd.cncf.helm.config.v1+json"
+	HelmChartContentMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// HelmChart has the files of a chart pushed to registry which are shown in app store
+type HelmChart struct {
+	ChartYaml        []byte
+	ValuesYaml       []byte
+	Readme           []byte
+	ValuesSchemaJson []byte
+	Notes            []byte
+	Digest           string
+}
+
+// GetHelmChart returns nil chart when the tag does not exist or is not a helm chart
+func (impl *Client) GetHelmChart(reference *Reference, tag string) (*HelmChart, error) {
+	manifest, err := impl.GetManifest(reference, tag)
+	if err != nil || manifest == nil {
+		return nil, err
+	}
+	if manifest.Config == nil || manifest.Config.MediaType != HelmChartConfigMediaType {
+		return nil, nil
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != HelmChartContentMediaType {
+			continue
+		}
+		archive, err := impl.GetBlob(reference, layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		chart, err := ReadHelmChartArchive(archive)
+		if err != nil {
+			return nil, err
+		}
+		chart.Digest = layer.Digest
+		return chart, nil
+	}
+	return nil, fmt.Errorf("chart content not found in manifest of %s:%s", reference.Repository, tag)
+}
+
+// ReadHelmChartArchive reads files of the chart from its packaged tgz, files of sub charts are skipped
+func ReadHelmChartArchive(archive []byte) (*HelmChart, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+	chart := &HelmChart{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		// files are in the directory of chart name, i.e. mychart/values.yaml
+		components := strings.SplitN(path.Clean(header.Name), "/", 2)
+		if len(components) != 2 {
+			continue
+		}
+		var file *[]byte
+		switch components[1] {
+		case "Chart.yaml":
+			file = &chart.ChartYaml
+		case "values.yaml":
+			file = &chart.ValuesYaml
+		case "README.md":
+			file = &chart.Readme
+		case "values.schema.json":
+			file = &chart.ValuesSchemaJson
+		case "templates/NOTES.txt":
+			file = &chart.Notes
+		default:
+			continue
+		}
+		*file, err = ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(chart.ChartYaml) == 0 {
+		return nil, fmt.Errorf("Chart.yaml not found in chart archive")
+	}
+	return chart, nil
+}
+
+// GetChartRepositoryUrl returns url of the repository of chart in registry which helm pulls the chart from, charts
+// are pushed to repositories named by the chart i.e. oci://registry.example.com/team/charts/mychart
+func GetChartRepositoryUrl(chartRepoUrl string, repositories []string, chartName string) string {
+	for _, repository := range repositories {
+		if path.Base(repository) == chartName && path.Dir(repository) != "." {
+			return strings.TrimSuffix(chartRepoUrl, "/") + "/" + path.Dir(repository)
+		}
+	}
+	return chartRepoUrl
+}
+
+// SplitRepositories splits comma separated repositories of chart repo
+func SplitRepositories(repositories string) []string {
+	var result []string
+	for _, repository := range strings.Split(repositories, ",") {
+		repository = strings.Trim(strings.TrimSpace(repository), "/")
+		if len(repository) > 0 {
+			result = append(result, repository)
+		}
+	}
+	return result
+}
@@ -0,0 +1,284 @@
+package ociRegistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	ManifestMediaTypes = "application/vnd.oci.image.manifest.v1+json,application/vnd.docker.distribution.manifest.v2+json," +
+		"application/vnd.oci.image.index.v1+json,application/vnd.docker.distribution.manifest.list.v2+json"
+	DockerHubRegistry    = "index.docker.io"
+	dockerHubApiRegistry = "registry-1.docker.io"
+)
+
+// Reference is a parsed image name like registry.example.com/team/app:v1@sha256:abc
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image name following docker conventions, images without registry are on docker hub
+func ParseReference(image string) (*Reference, error) {
+	if len(image) == 0 {
+		return nil, fmt.Errorf("empty image name")
+	}
+	reference := &Reference{}
+	name := image
+	if index := strings.Index(name, "@"); index >= 0 {
+		reference.Digest = name[index+1:]
+		name = name[:index]
+	}
+	if index := strings.LastIndex(name, ":"); index >= 0 && !strings.Contains(name[index+1:], "/") {
+		reference.Tag = name[index+1:]
+		name = name[:index]
+	}
+	components := strings.SplitN(name, "/", 2)
+	if len(components) == 2 && (strings.ContainsAny(components[0], ".:") || components[0] == "localhost") {
+		reference.Registry = components[0]
+		reference.Repository = components[1]
+		if reference.Registry == "docker.io" {
+			reference.Registry = DockerHubRegistry
+		}
+	} else {
+		reference.Registry = DockerHubRegistry
+		reference.Repository = name
+		if !strings.Contains(name, "/") {
+			reference.Repository = "library/" + name
+		}
+	}
+	if len(reference.Tag) == 0 && len(reference.Digest) == 0 {
+		reference.Tag = "latest"
+	}
+	if len(reference.Repository) == 0 {
+		return nil, fmt.Errorf("invalid image name %s", image)
+	}
+	return reference, nil
+}
+
+type Credential struct {
+	Username string
+	Password string
+}
+
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type Manifest struct {
+	MediaType string        `json:"mediaType"`
+	Config    *Descriptor   `json:"config,omitempty"`
+	Layers    []*Descriptor `json:"layers"`
+}
+
+// Client reads manifests, tags and blobs through the distribution api, bearer tokens are requested on the
+// challenge of the registry and reused for the repository
+type Client struct {
+	client     *http.Client
+	credential *Credential
+	plainHttp  bool
+	tokens     map[string]string
+}
+
+// NewClient returns client of registries served over https, plainHttp is for registries without tls i.e. a local
+// registry
+func NewClient(client *http.Client, credential *Credential, plainHttp bool) *Client {
+	return &Client{client: client, credential: credential, plainHttp: plainHttp, tokens: make(map[string]string)}
+}
+
+// ResolveDigest returns the manifest digest of the image, the digest of the reference is returned when present
+func (impl *Client) ResolveDigest(reference *Reference) (string, error) {
+	if len(reference.Digest) > 0 {
+		return reference.Digest, nil
+	}
+	resp, err := impl.do(http.MethodHead, reference, "manifests/"+reference.Tag, ManifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot resolve digest of %s:%s, registry returned status %d", reference.Repository, reference.Tag, resp.StatusCode)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if len(digest) == 0 {
+		return "", fmt.Errorf("registry did not return digest of %s:%s", reference.Repository, reference.Tag)
+	}
+	return digest, nil
+}
+
+// GetManifest returns nil manifest when the tag does not exist
+func (impl *Client) GetManifest(reference *Reference, tag string) (*Manifest, error) {
+	resp, err := impl.do(http.MethodGet, reference, "manifests/"+tag, ManifestMediaTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch manifest %s:%s, registry returned status %d", reference.Repository, tag, resp.StatusCode)
+	}
+	manifest := &Manifest{}
+	err = json.NewDecoder(resp.Body).Decode(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (impl *Client) GetBlob(reference *Reference, digest string) ([]byte, error) {
+	resp, err := impl.do(http.MethodGet, reference, "blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch blob %s of %s, registry returned status %d", digest, reference.Repository, resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+var nextLinkRegex = regexp.MustCompile(`<[^>]*/tags/list(\?[^>]*)>;\s*rel="?next"?`)
+
+// ListTags returns all tags of the repository, following the pages of the registry
+func (impl *Client) ListTags(reference *Reference) ([]string, error) {
+	var tags []string
+	path := "tags/list"
+	for len(path) > 0 {
+		resp, err := impl.do(http.MethodGet, reference, path, "")
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("cannot list tags of %s, registry returned status %d", reference.Repository, resp.StatusCode)
+		}
+		tagList := &struct {
+			Tags []string `json:"tags"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(tagList)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tagList.Tags...)
+		path = ""
+		if match := nextLinkRegex.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			path = "tags/list" + match[1]
+		}
+	}
+	return tags, nil
+}
+
+func (impl *Client) do(method string, reference *Reference, path string, accept string) (*http.Response, error) {
+	resp, err := impl.request(method, reference, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	err = impl.authenticate(reference, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return impl.request(method, reference, path, accept)
+}
+
+func (impl *Client) request(method string, reference *Reference, path string, accept string) (*http.Response, error) {
+	host := reference.Registry
+	if host == DockerHubRegistry {
+		host = dockerHubApiRegistry
+	}
+	scheme := "https"
+	if impl.plainHttp {
+		scheme = "http"
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf("%s://%s/v2/%s/%s", scheme, host, reference.Repository, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+	if token, ok := impl.tokens[reference.Registry+"/"+reference.Repository]; ok {
+		req.Header.Set("Authorization", token)
+	}
+	return impl.client.Do(req)
+}
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate answers the challenge of registry, basic challenges use the credential directly and bearer
+// challenges exchange it for a pull token of the repository
+func (impl *Client) authenticate(reference *Reference, challenge string) error {
+	key := reference.Registry + "/" + reference.Repository
+	if strings.HasPrefix(strings.ToLower(challenge), "basic") {
+		if impl.credential == nil {
+			return fmt.Errorf("registry %s requires credentials", reference.Registry)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(impl.credential.Username, impl.credential.Password)
+		impl.tokens[key] = req.Header.Get("Authorization")
+		return nil
+	}
+	params := make(map[string]string)
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	realm, ok := params["realm"]
+	if !ok {
+		return fmt.Errorf("unsupported authentication challenge of registry %s", reference.Registry)
+	}
+	query := url.Values{}
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if len(scope) == 0 {
+		scope = fmt.Sprintf("repository:%s:pull", reference.Repository)
+	}
+	query.Set("scope", scope)
+	req, err := http.NewRequest(http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if impl.credential != nil && len(impl.credential.Username) > 0 {
+		req.SetBasicAuth(impl.credential.Username, impl.credential.Password)
+	}
+	resp, err := impl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authentication with registry %s failed with status %d", reference.Registry, resp.StatusCode)
+	}
+	tokenResponse := &struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(tokenResponse)
+	if err != nil {
+		return err
+	}
+	token := tokenResponse.Token
+	if len(token) == 0 {
+		token = tokenResponse.AccessToken
+	}
+	impl.tokens[key] = "Bearer " + token
+	return nil
+}
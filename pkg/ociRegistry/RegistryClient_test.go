@@ -0,0 +1,171 @@
+package ociRegistry
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDigest = "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"
+
+func TestParseReference(t *testing.T) {
+	reference, err := ParseReference("nginx")
+	assert.Nil(t, err)
+	assert.Equal(t, &Reference{Registry: DockerHubRegistry, Repository: "library/nginx", Tag: "latest"}, reference)
+
+	reference, err = ParseReference("localhost:5000/team/app:v1@" + testDigest)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reference{Registry: "localhost:5000", Repository: "team/app", Tag: "v1", Digest: testDigest}, reference)
+}
+
+// testRegistry serves charts of one repository like a distribution registry with token authentication, tags are
+// listed two per page
+type testRegistry struct {
+	repository string
+	tags       []string
+	manifests  map[string][]byte
+	blobs      map[string][]byte
+}
+
+func (registry *testRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "devtron" || password != "secret" || r.URL.Query().Get("scope") != "repository:"+registry.repository+":pull" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"pull-token"}`))
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer pull-token" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="test",scope="repository:%s:pull"`, r.Host, registry.repository))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	prefix := "/v2/" + registry.repository + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	switch {
+	case path == "tags/list":
+		start := 0
+		if last := r.URL.Query().Get("last"); len(last) > 0 {
+			for i, tag := range registry.tags {
+				if tag == last {
+					start = i + 1
+				}
+			}
+		}
+		end := start + 2
+		if end < len(registry.tags) {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?n=2&last=%s>; rel="next"`, registry.repository, registry.tags[end-1]))
+		} else {
+			end = len(registry.tags)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": registry.repository, "tags": registry.tags[start:end]})
+	case strings.HasPrefix(path, "manifests/"):
+		manifest, ok := registry.manifests[strings.TrimPrefix(path, "manifests/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(manifest)
+	case strings.HasPrefix(path, "blobs/"):
+		blob, ok := registry.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(blob)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func chartArchive(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.Nil(t, err)
+		_, err = tarWriter.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tarWriter.Close())
+	assert.Nil(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func newTestRegistry(t *testing.T) (*testRegistry, *httptest.Server) {
+	archive := chartArchive(t, map[string]string{
+		"mychart/Chart.yaml":               "apiVersion: v2\nname: mychart\nversion: 1.0.0\n",
+		"mychart/values.yaml":              "replicaCount: 1\n",
+		"mychart/templates/NOTES.txt":      "installed",
+		"mychart/charts/dep/values.yaml":   "skipped: true\n",
+		"mychart/templates/deployment.yml": "kind: Deployment",
+	})
+	manifest, _ := json.Marshal(&Manifest{
+		MediaType: "application/vnd.oci.image.manifest.v1+json",
+		Config:    &Descriptor{MediaType: HelmChartConfigMediaType, Digest: "sha256:config"},
+		Layers:    []*Descriptor{{MediaType: HelmChartContentMediaType, Digest: "sha256:chart"}},
+	})
+	registry := &testRegistry{
+		repository: "charts/mychart",
+		tags:       []string{"0.1.0", "0.2.0", "1.0.0", "latest", "1.0.0_build.1"},
+		manifests:  map[string][]byte{"1.0.0": manifest},
+		blobs:      map[string][]byte{"sha256:chart": archive},
+	}
+	return registry, httptest.NewServer(registry)
+}
+
+func TestListTags(t *testing.T) {
+	registry, server := newTestRegistry(t)
+	defer server.Close()
+	reference := &Reference{Registry: RegistryHost(server.URL), Repository: registry.repository}
+
+	client := NewClient(server.Client(), &Credential{Username: "devtron", Password: "secret"}, IsPlainHttp(server.URL))
+	tags, err := client.ListTags(reference)
+	assert.Nil(t, err)
+	assert.Equal(t, registry.tags, tags)
+
+	client = NewClient(server.Client(), &Credential{Username: "devtron", Password: "wrong"}, true)
+	_, err = client.ListTags(reference)
+	assert.NotNil(t, err)
+}
+
+func TestGetHelmChart(t *testing.T) {
+	registry, server := newTestRegistry(t)
+	defer server.Close()
+	reference := &Reference{Registry: RegistryHost(server.URL), Repository: registry.repository}
+	client := NewClient(server.Client(), &Credential{Username: "devtron", Password: "secret"}, true)
+
+	chart, err := client.GetHelmChart(reference, "1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "apiVersion: v2\nname: mychart\nversion: 1.0.0\n", string(chart.ChartYaml))
+	assert.Equal(t, "replicaCount: 1\n", string(chart.ValuesYaml))
+	assert.Equal(t, "installed", string(chart.Notes))
+	assert.Empty(t, chart.Readme)
+	assert.Equal(t, "sha256:chart", chart.Digest)
+
+	chart, err = client.GetHelmChart(reference, "0.1.0")
+	assert.Nil(t, err)
+	assert.Nil(t, chart)
+}
+
+func TestGetChartRepositoryUrl(t *testing.T) {
+	repositories := SplitRepositories(" team/charts/mychart/, other ,,")
+	assert.Equal(t, []string{"team/charts/mychart", "other"}, repositories)
+	assert.Equal(t, "oci://registry.example.com/team/charts", GetChartRepositoryUrl("oci://registry.example.com", repositories, "mychart"))
+	assert.Equal(t, "oci://registry.example.com", GetChartRepositoryUrl("oci://registry.example.com", repositories, "other"))
+}
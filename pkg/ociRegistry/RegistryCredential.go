@@ -0,0 +1,39 @@
+package ociRegistry
+
+import (
+	"strings"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+)
+
+// GetCredential returns credential of a registry added in global configurations, ecr registries get a token of the
+// aws credentials as their passwords expire
+func GetCredential(store *repository.DockerArtifactStore) (*Credential, error) {
+	if store.RegistryType == repository.REGISTRYTYPE_ECR {
+		username, password, err := util.GetEcrAuthorizationToken(store.AWSAccessKeyId, store.AWSSecretAccessKey, store.AWSRegion)
+		if err != nil {
+			return nil, err
+		}
+		return &Credential{Username: username, Password: password}, nil
+	}
+	return &Credential{Username: store.Username, Password: store.Password}, nil
+}
+
+// RegistryHost returns host of registry url as used in references, i.e. index.docker.io for docker hub
+func RegistryHost(registryUrl string) string {
+	host := registryUrl
+	if index := strings.Index(host, "://"); index >= 0 {
+		host = host[index+3:]
+	}
+	host = strings.SplitN(host, "/", 2)[0]
+	if host == "docker.io" || host == dockerHubApiRegistry {
+		return DockerHubRegistry
+	}
+	return host
+}
+
+// IsPlainHttp tells if registry url is served without tls
+func IsPlainHttp(registryUrl string) bool {
+	return strings.HasPrefix(registryUrl, "http://")
+}
@@ -0,0 +1,597 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/client/k8s/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/app"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/prometheus"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/util/k8s"
+	"github.com/go-pg/pg"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rolloutGvk is the argo rollout created by rollout charts for canary and blue-green strategies
+var rolloutGvk = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
+const (
+	// rolloutPromoteFullPatch skips remaining steps and pauses of the rollout, same as kubectl argo rollouts promote --full
+	rolloutPromoteFullPatch = `{"status":{"promoteFull":true}}`
+	// rolloutAbortPatch shifts traffic back to stable version of the rollout, same as kubectl argo rollouts abort
+	rolloutAbortPatch = `{"status":{"abort":true}}`
+)
+
+type CanaryAnalysisConfigDto struct {
+	Id                    int                        `json:"id"`
+	PipelineId            int                        `json:"pipelineId" validate:"required"`
+	InitialDelayInSeconds int                        `json:"initialDelayInSeconds" validate:"min=0"`
+	IntervalInSeconds     int                        `json:"intervalInSeconds" validate:"min=30"`
+	Iterations            int                        `json:"iterations" validate:"min=1"`
+	FailureLimit          int                        `json:"failureLimit" validate:"min=0"`
+	AutoRollback          bool                       `json:"autoRollback"`
+	Metrics               []*CanaryAnalysisMetricDto `json:"metrics" validate:"required,min=1,dive"`
+	UserId                int32                      `json:"-"`
+}
+
+type CanaryAnalysisMetricDto struct {
+	Id        int                                 `json:"id"`
+	Name      string                              `json:"name" validate:"required"`
+	Query     string                              `json:"query" validate:"required"`
+	Operator  pipelineConfig.CanaryMetricOperator `json:"operator" validate:"oneof=< <= > >="`
+	Threshold float64                             `json:"threshold"`
+}
+
+type CanaryAnalysisRunDto struct {
+	Id                 int                                    `json:"id"`
+	PipelineId         int                                    `json:"pipelineId"`
+	CdWorkflowRunnerId int                                    `json:"cdWorkflowRunnerId"`
+	CiArtifactId       int                                    `json:"ciArtifactId"`
+	Status             pipelineConfig.CanaryAnalysisRunStatus `json:"status"`
+	SuccessfulSteps    int                                    `json:"successfulSteps"`
+	FailedSteps        int                                    `json:"failedSteps"`
+	Message            string                                 `json:"message"`
+	StartedOn          time.Time                              `json:"startedOn"`
+	FinishedOn         time.Time                              `json:"finishedOn"`
+}
+
+type CanaryAnalysisService interface {
+	SaveConfig(request *CanaryAnalysisConfigDto) (*CanaryAnalysisConfigDto, error)
+	GetConfig(pipelineId int) (*CanaryAnalysisConfigDto, error)
+	DeleteConfig(pipelineId int, userId int32) error
+	GetRuns(pipelineId int, offset int, size int) ([]*CanaryAnalysisRunDto, error)
+	// StartAnalysis registers an analysis run for a deployment of a canary or blue-green pipeline, it is a no-op for
+	// pipelines without canary analysis config or deployed with any other strategy
+	StartAnalysis(pipeline *pipelineConfig.Pipeline, wfrId int, ciArtifactId int, deploymentTemplate string, triggeredBy int32) error
+	// EvaluateDueRuns evaluates one step of every running analysis whose interval has elapsed and returns the runs
+	// which have failed and are configured for automatic rollback
+	EvaluateDueRuns() ([]*pipelineConfig.CanaryAnalysisRun, error)
+	MarkRolledBack(run *pipelineConfig.CanaryAnalysisRun, rollbackErr error) error
+}
+
+type CanaryAnalysisServiceImpl struct {
+	logger                         *zap.SugaredLogger
+	canaryAnalysisConfigRepository pipelineConfig.CanaryAnalysisConfigRepository
+	canaryAnalysisRunRepository    pipelineConfig.CanaryAnalysisRunRepository
+	pipelineStatusTimelineService  app.PipelineStatusTimelineService
+	pipelineRepository             pipelineConfig.PipelineRepository
+	pipelineConfigRepository       chartConfig.PipelineConfigRepository
+	cdWorkflowRepository           pipelineConfig.CdWorkflowRepository
+	envRepository                  repository2.EnvironmentRepository
+	k8sApplicationService          k8s.K8sApplicationService
+	k8sClientService               application.K8sClientService
+}
+
+func NewCanaryAnalysisServiceImpl(logger *zap.SugaredLogger,
+	canaryAnalysisConfigRepository pipelineConfig.CanaryAnalysisConfigRepository,
+	canaryAnalysisRunRepository pipelineConfig.CanaryAnalysisRunRepository,
+	pipelineStatusTimelineService app.PipelineStatusTimelineService,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	pipelineConfigRepository chartConfig.PipelineConfigRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	envRepository repository2.EnvironmentRepository,
+	k8sApplicationService k8s.K8sApplicationService,
+	k8sClientService application.K8sClientService) *CanaryAnalysisServiceImpl {
+	return &CanaryAnalysisServiceImpl{
+		logger:                         logger,
+		canaryAnalysisConfigRepository: canaryAnalysisConfigRepository,
+		canaryAnalysisRunRepository:    canaryAnalysisRunRepository,
+		pipelineStatusTimelineService:  pipelineStatusTimelineService,
+		pipelineRepository:             pipelineRepository,
+		pipelineConfigRepository:       pipelineConfigRepository,
+		cdWorkflowRepository:           cdWorkflowRepository,
+		envRepository:                  envRepository,
+		k8sApplicationService:          k8sApplicationService,
+		k8sClientService:               k8sClientService,
+	}
+}
+
+func (impl *CanaryAnalysisServiceImpl) SaveConfig(request *CanaryAnalysisConfigDto) (*CanaryAnalysisConfigDto, error) {
+	dbConnection := impl.canaryAnalysisConfigRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		impl.logger.Errorw("error in establishing connection", "err", err)
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+
+	config, err := impl.canaryAnalysisConfigRepository.FindActiveConfigByPipelineId(request.PipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		config = &pipelineConfig.CanaryAnalysisConfig{
+			PipelineId: request.PipelineId,
+			Active:     true,
+			AuditLog:   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId},
+		}
+	}
+	config.InitialDelayInSeconds = request.InitialDelayInSeconds
+	config.IntervalInSeconds = request.IntervalInSeconds
+	config.Iterations = request.Iterations
+	config.FailureLimit = request.FailureLimit
+	config.AutoRollback = request.AutoRollback
+	config.UpdatedOn = time.Now()
+	config.UpdatedBy = request.UserId
+	if config.Id == 0 {
+		err = impl.canaryAnalysisConfigRepository.SaveConfig(config, tx)
+	} else {
+		err = impl.canaryAnalysisConfigRepository.UpdateConfig(config, tx)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis config", "err", err, "config", config)
+		return nil, err
+	}
+
+	// metrics are versioned by deactivating the previous set, runs in progress keep referring to the config only
+	err = impl.canaryAnalysisConfigRepository.DeactivateMetricsByConfigId(config.Id, request.UserId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deactivating canary analysis metrics", "err", err, "configId", config.Id)
+		return nil, err
+	}
+	var metrics []*pipelineConfig.CanaryAnalysisMetric
+	for _, metricDto := range request.Metrics {
+		metrics = append(metrics, &pipelineConfig.CanaryAnalysisMetric{
+			CanaryAnalysisConfigId: config.Id,
+			Name:                   metricDto.Name,
+			Query:                  metricDto.Query,
+			Operator:               metricDto.Operator,
+			Threshold:              metricDto.Threshold,
+			Active:                 true,
+			AuditLog:               sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId, UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+		})
+	}
+	err = impl.canaryAnalysisConfigRepository.SaveMetrics(metrics, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving canary analysis metrics", "err", err, "configId", config.Id)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetConfig(request.PipelineId)
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetConfig(pipelineId int) (*CanaryAnalysisConfigDto, error) {
+	config, err := impl.canaryAnalysisConfigRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", pipelineId)
+		}
+		return nil, err
+	}
+	metrics, err := impl.canaryAnalysisConfigRepository.FindActiveMetricsByConfigId(config.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching canary analysis metrics", "err", err, "configId", config.Id)
+		return nil, err
+	}
+	configDto := &CanaryAnalysisConfigDto{
+		Id:                    config.Id,
+		PipelineId:            config.PipelineId,
+		InitialDelayInSeconds: config.InitialDelayInSeconds,
+		IntervalInSeconds:     config.IntervalInSeconds,
+		Iterations:            config.Iterations,
+		FailureLimit:          config.FailureLimit,
+		AutoRollback:          config.AutoRollback,
+	}
+	for _, metric := range metrics {
+		configDto.Metrics = append(configDto.Metrics, &CanaryAnalysisMetricDto{
+			Id:        metric.Id,
+			Name:      metric.Name,
+			Query:     metric.Query,
+			Operator:  metric.Operator,
+			Threshold: metric.Threshold,
+		})
+	}
+	return configDto, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) DeleteConfig(pipelineId int, userId int32) error {
+	config, err := impl.canaryAnalysisConfigRepository.FindActiveConfigByPipelineId(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	dbConnection := impl.canaryAnalysisConfigRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		impl.logger.Errorw("error in establishing connection", "err", err)
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	config.Active = false
+	config.UpdatedOn = time.Now()
+	config.UpdatedBy = userId
+	err = impl.canaryAnalysisConfigRepository.UpdateConfig(config, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting canary analysis config", "err", err, "config", config)
+		return err
+	}
+	err = impl.canaryAnalysisConfigRepository.DeactivateMetricsByConfigId(config.Id, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting canary analysis metrics", "err", err, "configId", config.Id)
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	return impl.canaryAnalysisRunRepository.AbortRunningRunsByPipelineId(pipelineId, "canary analysis config deleted")
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetRuns(pipelineId int, offset int, size int) ([]*CanaryAnalysisRunDto, error) {
+	runs, err := impl.canaryAnalysisRunRepository.FindByPipelineId(pipelineId, offset, size)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching canary analysis runs", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	runDtos := make([]*CanaryAnalysisRunDto, 0, len(runs))
+	for _, run := range runs {
+		runDtos = append(runDtos, &CanaryAnalysisRunDto{
+			Id:                 run.Id,
+			PipelineId:         run.PipelineId,
+			CdWorkflowRunnerId: run.CdWorkflowRunnerId,
+			CiArtifactId:       run.CiArtifactId,
+			Status:             run.Status,
+			SuccessfulSteps:    run.SuccessfulSteps,
+			FailedSteps:        run.FailedSteps,
+			Message:            run.Message,
+			StartedOn:          run.CreatedOn,
+			FinishedOn:         run.FinishedOn,
+		})
+	}
+	return runDtos, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) StartAnalysis(pipeline *pipelineConfig.Pipeline, wfrId int, ciArtifactId int, deploymentTemplate string, triggeredBy int32) error {
+	config, err := impl.canaryAnalysisConfigRepository.FindActiveConfigByPipelineId(pipeline.Id)
+	if err == pg.ErrNoRows {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching canary analysis config", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	if len(deploymentTemplate) == 0 {
+		strategy, err := impl.pipelineConfigRepository.GetDefaultStrategyByPipelineId(pipeline.Id)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching default strategy", "err", err, "pipelineId", pipeline.Id)
+			return err
+		}
+		if strategy != nil {
+			deploymentTemplate = string(strategy.Strategy)
+		}
+	}
+	if deploymentTemplate != string(pipelineConfig.DEPLOYMENT_TEMPLATE_CANARY) && deploymentTemplate != string(pipelineConfig.DEPLOYMENT_TEMPLATE_BLUE_GREEN) {
+		impl.logger.Debugw("skipping canary analysis for deployment strategy", "pipelineId", pipeline.Id, "strategy", deploymentTemplate)
+		return nil
+	}
+	// a new deployment supersedes any analysis still running for the previous one
+	err = impl.canaryAnalysisRunRepository.AbortRunningRunsByPipelineId(pipeline.Id, "triggered new deployment")
+	if err != nil {
+		impl.logger.Errorw("error in aborting running canary analysis", "err", err, "pipelineId", pipeline.Id)
+		return err
+	}
+	runner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(wfrId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd workflow runner", "err", err, "wfrId", wfrId)
+		return err
+	}
+	run := &pipelineConfig.CanaryAnalysisRun{
+		CanaryAnalysisConfigId: config.Id,
+		PipelineId:             pipeline.Id,
+		CdWorkflowRunnerId:     wfrId,
+		CiArtifactId:           ciArtifactId,
+		Status:                 pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING,
+		NextEvaluationOn:       time.Now().Add(time.Duration(config.InitialDelayInSeconds+config.IntervalInSeconds) * time.Second),
+		AuditLog:               sql.AuditLog{CreatedOn: time.Now(), CreatedBy: triggeredBy, UpdatedOn: time.Now(), UpdatedBy: triggeredBy},
+	}
+	if runner.Status == app.WorkflowAwaitingMerge {
+		// nothing is deployed until the pull request is merged, run is started by pull request sync on merge
+		run.Status = pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PENDING
+		run.NextEvaluationOn = time.Time{}
+		return impl.canaryAnalysisRunRepository.Save(run)
+	}
+	err = impl.canaryAnalysisRunRepository.Save(run)
+	if err != nil {
+		return err
+	}
+	detail := fmt.Sprintf("Canary analysis started, %d successful step(s) required at an interval of %ds.", config.Iterations, config.IntervalInSeconds)
+	return impl.pipelineStatusTimelineService.SaveTimeline(run.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_CANARY_STARTED, detail, triggeredBy)
+}
+
+func (impl *CanaryAnalysisServiceImpl) EvaluateDueRuns() ([]*pipelineConfig.CanaryAnalysisRun, error) {
+	runs, err := impl.canaryAnalysisRunRepository.FindDueRunningRuns(time.Now())
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching due canary analysis runs", "err", err)
+		return nil, err
+	}
+	var runsToRollback []*pipelineConfig.CanaryAnalysisRun
+	for _, run := range runs {
+		config, err := impl.canaryAnalysisConfigRepository.FindConfigById(run.CanaryAnalysisConfigId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching canary analysis config", "err", err, "runId", run.Id)
+			continue
+		}
+		rollback, err := impl.evaluateRun(run, config)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating canary analysis run", "err", err, "runId", run.Id)
+			continue
+		}
+		if rollback {
+			runsToRollback = append(runsToRollback, run)
+		}
+	}
+	return runsToRollback, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) evaluateRun(run *pipelineConfig.CanaryAnalysisRun, config *pipelineConfig.CanaryAnalysisConfig) (rollback bool, err error) {
+	wfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(run.CdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd workflow runner", "err", err, "wfrId", run.CdWorkflowRunnerId)
+		return false, err
+	}
+	if wfr.Status == WorkflowFailed || wfr.Status == WorkflowAborted {
+		return false, impl.finishRun(run, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_ABORTED, fmt.Sprintf("deployment is %s", wfr.Status))
+	}
+	metrics, err := impl.canaryAnalysisConfigRepository.FindActiveMetricsByConfigId(config.Id)
+	if err != nil && err != pg.ErrNoRows {
+		return false, err
+	}
+	pipeline, err := impl.pipelineRepository.FindById(run.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", run.PipelineId)
+		return false, err
+	}
+	env, err := impl.envRepository.FindById(pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "err", err, "envId", pipeline.EnvironmentId)
+		return false, err
+	}
+
+	stepPassed, details := impl.evaluateMetrics(metrics, env.Name, env.Cluster.PrometheusEndpoint)
+	stepNumber := run.SuccessfulSteps + run.FailedSteps + 1
+	timelineStatus := pipelineConfig.TIMELINE_STATUS_CANARY_STEP_SUCCEEDED
+	if stepPassed {
+		run.SuccessfulSteps += 1
+	} else {
+		run.FailedSteps += 1
+		timelineStatus = pipelineConfig.TIMELINE_STATUS_CANARY_STEP_FAILED
+	}
+	err = impl.pipelineStatusTimelineService.SaveTimeline(run.CdWorkflowRunnerId, timelineStatus, fmt.Sprintf("Step %d: %s", stepNumber, strings.Join(details, ", ")), run.CreatedBy)
+	if err != nil {
+		return false, err
+	}
+
+	switch getCanaryRunOutcome(run, config) {
+	case pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PROMOTED:
+		err = impl.patchRollouts(pipeline, env, rolloutPromoteFullPatch)
+		if err != nil {
+			// run stays in progress so that promotion is retried on next evaluation
+			impl.logger.Errorw("error in promoting rollout", "err", err, "runId", run.Id, "pipelineId", pipeline.Id)
+			err1 := impl.pipelineStatusTimelineService.SaveTimeline(run.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_CANARY_STEP_FAILED, fmt.Sprintf("Canary analysis passed, promotion of rollout failed: %s", err.Error()), run.CreatedBy)
+			if err1 != nil {
+				return false, err1
+			}
+			return false, impl.scheduleNextEvaluation(run, config)
+		}
+		err = impl.finishRun(run, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PROMOTED, "all analysis steps passed")
+		if err != nil {
+			return false, err
+		}
+		return false, impl.pipelineStatusTimelineService.SaveTimeline(run.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_CANARY_PROMOTED, "Canary analysis passed, rollout promoted.", run.CreatedBy)
+	case pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_FAILED:
+		message := fmt.Sprintf("failed steps %d exceeded failure limit %d", run.FailedSteps, config.FailureLimit)
+		// traffic is shifted back to stable version right away, rollback redeploys it to match desired state
+		err = impl.patchRollouts(pipeline, env, rolloutAbortPatch)
+		if err != nil {
+			impl.logger.Errorw("error in aborting rollout", "err", err, "runId", run.Id, "pipelineId", pipeline.Id)
+			message = fmt.Sprintf("%s, abort of rollout failed: %s", message, err.Error())
+		}
+		err = impl.finishRun(run, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_FAILED, message)
+		if err != nil {
+			return false, err
+		}
+		return config.AutoRollback, nil
+	default:
+		return false, impl.scheduleNextEvaluation(run, config)
+	}
+}
+
+func (impl *CanaryAnalysisServiceImpl) scheduleNextEvaluation(run *pipelineConfig.CanaryAnalysisRun, config *pipelineConfig.CanaryAnalysisConfig) error {
+	run.NextEvaluationOn = time.Now().Add(time.Duration(config.IntervalInSeconds) * time.Second)
+	run.UpdatedOn = time.Now()
+	run.UpdatedBy = run.CreatedBy
+	return impl.canaryAnalysisRunRepository.Update(run)
+}
+
+// patchRollouts patches status of the rollouts of release of the pipeline, rollout charts label them with release name
+func (impl *CanaryAnalysisServiceImpl) patchRollouts(pipeline *pipelineConfig.Pipeline, env *repository2.Environment, patch string) error {
+	restConfig, err := impl.k8sApplicationService.GetRestConfigByClusterId(env.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config of cluster", "err", err, "clusterId", env.ClusterId)
+		return err
+	}
+	resourceIf, _, err := impl.k8sClientService.GetResourceIf(restConfig, &application.K8sRequestBean{
+		ResourceIdentifier: application.ResourceIdentifier{GroupVersionKind: rolloutGvk},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in getting rollout resource client", "err", err, "clusterId", env.ClusterId)
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	releaseName := fmt.Sprintf("%s-%s", pipeline.App.AppName, env.Name)
+	rollouts, err := resourceIf.Namespace(env.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "release=" + releaseName})
+	if err != nil {
+		return err
+	}
+	if len(rollouts.Items) == 0 {
+		return fmt.Errorf("no rollout found for release %s in namespace %s", releaseName, env.Namespace)
+	}
+	for _, rollout := range rollouts.Items {
+		_, err = resourceIf.Namespace(env.Namespace).Patch(ctx, rollout.GetName(), types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) evaluateMetrics(metrics []*pipelineConfig.CanaryAnalysisMetric, envName string, prometheusUrl string) (bool, []string) {
+	if len(prometheusUrl) == 0 {
+		return false, []string{"prometheus endpoint not configured for cluster"}
+	}
+	prometheusAPI, err := prometheus.ContextByEnv(envName, prometheusUrl)
+	if err != nil {
+		impl.logger.Errorw("error in getting prometheus api client", "err", err, "env", envName)
+		return false, []string{"prometheus not reachable"}
+	}
+	passed := true
+	var details []string
+	for _, metric := range metrics {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		out, _, err := prometheusAPI.Query(ctx, metric.Query, time.Now())
+		cancel()
+		if err != nil {
+			impl.logger.Errorw("canary metric query failed in prometheus", "err", err, "metric", metric.Name)
+			passed = false
+			details = append(details, fmt.Sprintf("%s query failed", metric.Name))
+			continue
+		}
+		value, err := getScalarFromPrometheusResult(out)
+		if err != nil {
+			passed = false
+			details = append(details, fmt.Sprintf("%s %s", metric.Name, err.Error()))
+			continue
+		}
+		withinThreshold, err := isWithinCanaryThreshold(value, metric.Operator, metric.Threshold)
+		if err != nil {
+			passed = false
+			details = append(details, fmt.Sprintf("%s %s", metric.Name, err.Error()))
+			continue
+		}
+		result := "passed"
+		if !withinThreshold {
+			passed = false
+			result = "failed"
+		}
+		details = append(details, fmt.Sprintf("%s=%g (%s %g) %s", metric.Name, value, metric.Operator, metric.Threshold, result))
+	}
+	return passed, details
+}
+
+func (impl *CanaryAnalysisServiceImpl) MarkRolledBack(run *pipelineConfig.CanaryAnalysisRun, rollbackErr error) error {
+	if rollbackErr != nil {
+		run.Message = fmt.Sprintf("%s, rollback failed: %s", run.Message, rollbackErr.Error())
+		run.UpdatedOn = time.Now()
+		return impl.canaryAnalysisRunRepository.Update(run)
+	}
+	err := impl.finishRun(run, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_ROLLED_BACK, run.Message+", rolled back to previous healthy artifact")
+	if err != nil {
+		return err
+	}
+	return impl.pipelineStatusTimelineService.SaveTimeline(run.CdWorkflowRunnerId, pipelineConfig.TIMELINE_STATUS_CANARY_ROLLED_BACK, "Canary analysis failed, rolled back to previous healthy artifact.", run.CreatedBy)
+}
+
+func (impl *CanaryAnalysisServiceImpl) finishRun(run *pipelineConfig.CanaryAnalysisRun, status pipelineConfig.CanaryAnalysisRunStatus, message string) error {
+	run.Status = status
+	run.Message = message
+	run.FinishedOn = time.Now()
+	run.UpdatedOn = time.Now()
+	run.UpdatedBy = run.CreatedBy
+	return impl.canaryAnalysisRunRepository.Update(run)
+}
+
+// getCanaryRunOutcome returns the terminal status of a run once enough steps are evaluated, RUNNING otherwise
+func getCanaryRunOutcome(run *pipelineConfig.CanaryAnalysisRun, config *pipelineConfig.CanaryAnalysisConfig) pipelineConfig.CanaryAnalysisRunStatus {
+	if run.FailedSteps > config.FailureLimit {
+		return pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_FAILED
+	}
+	if run.SuccessfulSteps >= config.Iterations {
+		return pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PROMOTED
+	}
+	return pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING
+}
+
+func isWithinCanaryThreshold(value float64, operator pipelineConfig.CanaryMetricOperator, threshold float64) (bool, error) {
+	switch operator {
+	case pipelineConfig.CANARY_METRIC_OPERATOR_LESS_THAN:
+		return value < threshold, nil
+	case pipelineConfig.CANARY_METRIC_OPERATOR_LESS_THAN_OR_EQUAL:
+		return value <= threshold, nil
+	case pipelineConfig.CANARY_METRIC_OPERATOR_GREATER_THAN:
+		return value > threshold, nil
+	case pipelineConfig.CANARY_METRIC_OPERATOR_GREATER_THAN_OR_EQUAL:
+		return value >= threshold, nil
+	}
+	return false, fmt.Errorf("unsupported operator %s", operator)
+}
+
+// getScalarFromPrometheusResult reads a single value out of an instant query result, for vectors and matrices the
+// first series is used as queries are expected to be aggregated
+func getScalarFromPrometheusResult(value model.Value) (float64, error) {
+	switch result := value.(type) {
+	case *model.Scalar:
+		return float64(result.Value), nil
+	case model.Vector:
+		if len(result) > 0 {
+			return float64(result[0].Value), nil
+		}
+	case model.Matrix:
+		if len(result) > 0 && len(result[0].Values) > 0 {
+			return float64(result[0].Values[len(result[0].Values)-1].Value), nil
+		}
+	}
+	return 0, fmt.Errorf("no data")
+}
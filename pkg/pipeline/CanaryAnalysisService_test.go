@@ -0,0 +1,122 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devtron-labs/devtron/client/k8s/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/util/k8s"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetCanaryRunOutcome(t *testing.T) {
+	config := &pipelineConfig.CanaryAnalysisConfig{Iterations: 3, FailureLimit: 1}
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_RUNNING, getCanaryRunOutcome(&pipelineConfig.CanaryAnalysisRun{SuccessfulSteps: 2, FailedSteps: 1}, config))
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_PROMOTED, getCanaryRunOutcome(&pipelineConfig.CanaryAnalysisRun{SuccessfulSteps: 3, FailedSteps: 1}, config))
+	assert.Equal(t, pipelineConfig.CANARY_ANALYSIS_RUN_STATUS_FAILED, getCanaryRunOutcome(&pipelineConfig.CanaryAnalysisRun{SuccessfulSteps: 3, FailedSteps: 2}, config))
+}
+
+func TestIsWithinCanaryThreshold(t *testing.T) {
+	tests := []struct {
+		operator pipelineConfig.CanaryMetricOperator
+		value    float64
+		want     bool
+	}{
+		{pipelineConfig.CANARY_METRIC_OPERATOR_LESS_THAN, 0.5, true},
+		{pipelineConfig.CANARY_METRIC_OPERATOR_LESS_THAN, 1, false},
+		{pipelineConfig.CANARY_METRIC_OPERATOR_LESS_THAN_OR_EQUAL, 1, true},
+		{pipelineConfig.CANARY_METRIC_OPERATOR_GREATER_THAN, 1, false},
+		{pipelineConfig.CANARY_METRIC_OPERATOR_GREATER_THAN_OR_EQUAL, 1, true},
+	}
+	for _, tt := range tests {
+		got, err := isWithinCanaryThreshold(tt.value, tt.operator, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.want, got, "%v %s 1", tt.value, tt.operator)
+	}
+	_, err := isWithinCanaryThreshold(1, "==", 1)
+	assert.NotNil(t, err)
+}
+
+func TestGetScalarFromPrometheusResult(t *testing.T) {
+	value, err := getScalarFromPrometheusResult(&model.Scalar{Value: 0.25})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.25, value)
+
+	value, err = getScalarFromPrometheusResult(model.Vector{{Value: 2}, {Value: 3}})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(2), value)
+
+	value, err = getScalarFromPrometheusResult(model.Matrix{{Values: []model.SamplePair{{Value: 1}, {Value: 4}}}})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(4), value)
+
+	_, err = getScalarFromPrometheusResult(model.Vector{})
+	assert.NotNil(t, err)
+}
+
+type testK8sApplicationService struct {
+	k8s.K8sApplicationService
+}
+
+func (impl testK8sApplicationService) GetRestConfigByClusterId(clusterId int) (*rest.Config, error) {
+	return &rest.Config{}, nil
+}
+
+type testK8sClientService struct {
+	application.K8sClientService
+	client dynamic.Interface
+}
+
+func (impl testK8sClientService) GetResourceIf(restConfig *rest.Config, request *application.K8sRequestBean) (dynamic.NamespaceableResourceInterface, bool, error) {
+	return impl.client.Resource(rolloutGvk.GroupVersion().WithResource("rollouts")), true, nil
+}
+
+func newTestRollout(name string, release string) *unstructured.Unstructured {
+	rollout := &unstructured.Unstructured{}
+	rollout.SetGroupVersionKind(rolloutGvk)
+	rollout.SetName(name)
+	rollout.SetNamespace("devtron-demo")
+	rollout.SetLabels(map[string]string{"release": release})
+	return rollout
+}
+
+func TestPatchRollouts(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{rolloutGvk.GroupVersion().WithResource("rollouts"): "RolloutList"},
+		newTestRollout("dashboard-demo", "dashboard-demo"), newTestRollout("other-demo", "other-demo"))
+	impl := &CanaryAnalysisServiceImpl{
+		logger:                zap.NewNop().Sugar(),
+		k8sApplicationService: testK8sApplicationService{},
+		k8sClientService:      testK8sClientService{client: client},
+	}
+	pipeline := &pipelineConfig.Pipeline{App: app.App{AppName: "dashboard"}}
+	env := &repository2.Environment{Name: "demo", Namespace: "devtron-demo", ClusterId: 1}
+
+	err := impl.patchRollouts(pipeline, env, rolloutPromoteFullPatch)
+	assert.Nil(t, err)
+	rolloutIf := client.Resource(rolloutGvk.GroupVersion().WithResource("rollouts")).Namespace("devtron-demo")
+	rollout, err := rolloutIf.Get(context.Background(), "dashboard-demo", metav1.GetOptions{})
+	assert.Nil(t, err)
+	promoteFull, _, _ := unstructured.NestedBool(rollout.Object, "status", "promoteFull")
+	assert.True(t, promoteFull)
+	other, err := rolloutIf.Get(context.Background(), "other-demo", metav1.GetOptions{})
+	assert.Nil(t, err)
+	_, found, _ := unstructured.NestedBool(other.Object, "status", "promoteFull")
+	assert.False(t, found)
+
+	env.Name = "prod"
+	err = impl.patchRollouts(pipeline, env, rolloutAbortPatch)
+	assert.NotNil(t, err)
+}
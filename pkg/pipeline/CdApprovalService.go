@@ -0,0 +1,401 @@
+package pipeline
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type CdApprovalPolicyDto struct {
+	Id                int    `json:"id"`
+	PipelineId        int    `json:"pipelineId" validate:"required"`
+	RoleGroupId       int32  `json:"roleGroupId" validate:"required"`
+	RoleGroupName     string `json:"roleGroupName"`
+	RequiredApprovals int    `json:"requiredApprovals" validate:"min=1"`
+	UserId            int32  `json:"-"`
+}
+
+type CdApprovalRequestDto struct {
+	Id                int                                    `json:"id"`
+	PipelineId        int                                    `json:"pipelineId"`
+	CiArtifactId      int                                    `json:"ciArtifactId" validate:"required"`
+	Status            pipelineConfig.CdApprovalRequestStatus `json:"status"`
+	Comment           string                                 `json:"comment"`
+	RequestedBy       string                                 `json:"requestedBy"`
+	RequestedOn       time.Time                              `json:"requestedOn"`
+	RequiredApprovals int                                    `json:"requiredApprovals"`
+	Actions           []*CdApprovalActionDto                 `json:"actions"`
+	UserId            int32                                  `json:"-"`
+}
+
+type CdApprovalActionDto struct {
+	Action    pipelineConfig.CdApprovalActionType `json:"action"`
+	Comment   string                              `json:"comment"`
+	ActedBy   string                              `json:"actedBy"`
+	ActedOn   time.Time                           `json:"actedOn"`
+	RequestId int                                 `json:"-"`
+	UserId    int32                               `json:"-"`
+}
+
+type CdApprovalService interface {
+	SavePolicy(request *CdApprovalPolicyDto) (*CdApprovalPolicyDto, error)
+	GetPolicy(pipelineId int) (*CdApprovalPolicyDto, error)
+	DeletePolicy(pipelineId int, userId int32) error
+	RaiseRequest(request *CdApprovalRequestDto) (*CdApprovalRequestDto, error)
+	GetRequests(pipelineId int, offset int, size int) ([]*CdApprovalRequestDto, error)
+	GetRequestById(requestId int) (*CdApprovalRequestDto, error)
+	TakeAction(action *CdApprovalActionDto) (*CdApprovalRequestDto, error)
+	// CheckArtifactApproval returns an error if the pipeline has an approval policy and the artifact is not approved
+	CheckArtifactApproval(pipelineId int, ciArtifactId int) error
+}
+
+type CdApprovalServiceImpl struct {
+	logger               *zap.SugaredLogger
+	cdApprovalRepository pipelineConfig.CdApprovalRepository
+	roleGroupService     user.RoleGroupService
+	userService          user.UserService
+}
+
+func NewCdApprovalServiceImpl(logger *zap.SugaredLogger, cdApprovalRepository pipelineConfig.CdApprovalRepository,
+	roleGroupService user.RoleGroupService, userService user.UserService) *CdApprovalServiceImpl {
+	return &CdApprovalServiceImpl{
+		logger:               logger,
+		cdApprovalRepository: cdApprovalRepository,
+		roleGroupService:     roleGroupService,
+		userService:          userService,
+	}
+}
+
+func (impl *CdApprovalServiceImpl) SavePolicy(request *CdApprovalPolicyDto) (*CdApprovalPolicyDto, error) {
+	roleGroup, err := impl.roleGroupService.FetchRoleGroupsById(request.RoleGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching role group", "err", err, "roleGroupId", request.RoleGroupId)
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: "invalid user group"}
+	}
+	policy, err := impl.cdApprovalRepository.FindActivePolicyByPipelineId(request.PipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		policy = &pipelineConfig.CdApprovalPolicy{
+			PipelineId: request.PipelineId,
+			Active:     true,
+			AuditLog:   sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId},
+		}
+	}
+	policy.RoleGroupId = roleGroup.Id
+	policy.RequiredApprovals = request.RequiredApprovals
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = request.UserId
+	if policy.Id == 0 {
+		err = impl.cdApprovalRepository.SavePolicy(policy)
+	} else {
+		err = impl.cdApprovalRepository.UpdatePolicy(policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving approval policy", "err", err, "policy", policy)
+		return nil, err
+	}
+	request.Id = policy.Id
+	request.RoleGroupName = roleGroup.Name
+	return request, nil
+}
+
+func (impl *CdApprovalServiceImpl) GetPolicy(pipelineId int) (*CdApprovalPolicyDto, error) {
+	policy, err := impl.cdApprovalRepository.FindActivePolicyByPipelineId(pipelineId)
+	if err != nil {
+		if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		}
+		return nil, err
+	}
+	policyDto := &CdApprovalPolicyDto{
+		Id:                policy.Id,
+		PipelineId:        policy.PipelineId,
+		RoleGroupId:       policy.RoleGroupId,
+		RequiredApprovals: policy.RequiredApprovals,
+	}
+	roleGroup, err := impl.roleGroupService.FetchRoleGroupsById(policy.RoleGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching role group", "err", err, "roleGroupId", policy.RoleGroupId)
+	} else {
+		policyDto.RoleGroupName = roleGroup.Name
+	}
+	return policyDto, nil
+}
+
+func (impl *CdApprovalServiceImpl) DeletePolicy(pipelineId int, userId int32) error {
+	policy, err := impl.cdApprovalRepository.FindActivePolicyByPipelineId(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	return impl.cdApprovalRepository.UpdatePolicy(policy)
+}
+
+func (impl *CdApprovalServiceImpl) RaiseRequest(request *CdApprovalRequestDto) (*CdApprovalRequestDto, error) {
+	_, err := impl.cdApprovalRepository.FindActivePolicyByPipelineId(request.PipelineId)
+	if err == pg.ErrNoRows {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "approval policy not found", UserMessage: "approval is not configured for this pipeline"}
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	existingRequest, err := impl.cdApprovalRepository.FindLatestRequestByPipelineIdAndArtifactId(request.PipelineId, request.CiArtifactId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval request", "err", err, "pipelineId", request.PipelineId, "ciArtifactId", request.CiArtifactId)
+		return nil, err
+	}
+	if err == nil && (existingRequest.Status == pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REQUESTED || existingRequest.Status == pipelineConfig.CD_APPROVAL_REQUEST_STATUS_APPROVED) {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: "approval request already exists", UserMessage: fmt.Sprintf("artifact is already %s", existingRequest.Status)}
+	}
+	approvalRequest := &pipelineConfig.CdApprovalRequest{
+		PipelineId:   request.PipelineId,
+		CiArtifactId: request.CiArtifactId,
+		Status:       pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REQUESTED,
+		Comment:      request.Comment,
+		AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId, UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+	}
+	err = impl.cdApprovalRepository.SaveRequest(approvalRequest)
+	if err != nil {
+		impl.logger.Errorw("error in saving approval request", "err", err, "request", approvalRequest)
+		return nil, err
+	}
+	return impl.GetRequestById(approvalRequest.Id)
+}
+
+func (impl *CdApprovalServiceImpl) GetRequests(pipelineId int, offset int, size int) ([]*CdApprovalRequestDto, error) {
+	requests, err := impl.cdApprovalRepository.FindRequestsByPipelineId(pipelineId, offset, size)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval requests", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	return impl.buildRequestDtos(requests)
+}
+
+func (impl *CdApprovalServiceImpl) GetRequestById(requestId int) (*CdApprovalRequestDto, error) {
+	request, err := impl.cdApprovalRepository.FindRequestById(requestId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval request", "err", err, "requestId", requestId)
+		return nil, err
+	}
+	requestDtos, err := impl.buildRequestDtos([]*pipelineConfig.CdApprovalRequest{request})
+	if err != nil {
+		return nil, err
+	}
+	return requestDtos[0], nil
+}
+
+func (impl *CdApprovalServiceImpl) TakeAction(actionDto *CdApprovalActionDto) (*CdApprovalRequestDto, error) {
+	dbConnection := impl.cdApprovalRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	// request is locked so that concurrent actions can not both count the same approvals
+	request, err := impl.cdApprovalRepository.FindRequestByIdForUpdate(actionDto.RequestId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval request", "err", err, "requestId", actionDto.RequestId)
+		return nil, err
+	}
+	policy, err := impl.cdApprovalRepository.FindActivePolicyByPipelineId(request.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	isApprover, err := impl.isUserInRoleGroup(actionDto.UserId, policy.RoleGroupId)
+	if err != nil {
+		return nil, err
+	}
+	if !isApprover {
+		return nil, &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: "user is not an approver", UserMessage: "you are not an approver for this pipeline"}
+	}
+	actions, err := impl.cdApprovalRepository.FindActionsByRequestId(request.Id, tx)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval actions", "err", err, "requestId", request.Id)
+		return nil, err
+	}
+	status, err := getStatusAfterAction(request, actions, actionDto, policy.RequiredApprovals)
+	if err != nil {
+		return nil, err
+	}
+	action := &pipelineConfig.CdApprovalAction{
+		CdApprovalRequestId: request.Id,
+		UserId:              actionDto.UserId,
+		Action:              actionDto.Action,
+		Comment:             actionDto.Comment,
+		AuditLog:            sql.AuditLog{CreatedOn: time.Now(), CreatedBy: actionDto.UserId, UpdatedOn: time.Now(), UpdatedBy: actionDto.UserId},
+	}
+	err = impl.cdApprovalRepository.SaveAction(action, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving approval action", "err", err, "action", action)
+		return nil, err
+	}
+	if status != request.Status {
+		request.Status = status
+		request.UpdatedOn = time.Now()
+		request.UpdatedBy = actionDto.UserId
+		err = impl.cdApprovalRepository.UpdateRequest(request, tx)
+		if err != nil {
+			impl.logger.Errorw("error in updating approval request", "err", err, "request", request)
+			return nil, err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetRequestById(request.Id)
+}
+
+// getStatusAfterAction validates action of user on request having earlier actions and returns status of request once
+// the action is taken, a reject rejects the request and approvals reaching requiredApprovals approve it
+func getStatusAfterAction(request *pipelineConfig.CdApprovalRequest, actions []*pipelineConfig.CdApprovalAction,
+	actionDto *CdApprovalActionDto, requiredApprovals int) (pipelineConfig.CdApprovalRequestStatus, error) {
+	if request.Status != pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REQUESTED {
+		return request.Status, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: "approval request is not pending", UserMessage: fmt.Sprintf("approval request is already %s", request.Status)}
+	}
+	if request.CreatedBy == actionDto.UserId {
+		return request.Status, &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: "requester can not act on own request", UserMessage: "you can not approve or reject your own request"}
+	}
+	approvals := 0
+	for _, action := range actions {
+		if action.UserId == actionDto.UserId {
+			return request.Status, &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: "user already acted on request", UserMessage: "you have already acted on this request"}
+		}
+		if action.Action == pipelineConfig.CD_APPROVAL_ACTION_APPROVE {
+			approvals++
+		}
+	}
+	if actionDto.Action == pipelineConfig.CD_APPROVAL_ACTION_REJECT {
+		return pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REJECTED, nil
+	} else if approvals+1 >= requiredApprovals {
+		return pipelineConfig.CD_APPROVAL_REQUEST_STATUS_APPROVED, nil
+	}
+	return request.Status, nil
+}
+
+func (impl *CdApprovalServiceImpl) CheckArtifactApproval(pipelineId int, ciArtifactId int) error {
+	_, err := impl.cdApprovalRepository.FindActivePolicyByPipelineId(pipelineId)
+	if err == pg.ErrNoRows {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	request, err := impl.cdApprovalRepository.FindLatestRequestByPipelineIdAndArtifactId(pipelineId, ciArtifactId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval request", "err", err, "pipelineId", pipelineId, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	if err == pg.ErrNoRows || request.Status != pipelineConfig.CD_APPROVAL_REQUEST_STATUS_APPROVED {
+		return &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: "artifact not approved for deployment", UserMessage: "artifact is not approved for deployment"}
+	}
+	return nil
+}
+
+func (impl *CdApprovalServiceImpl) isUserInRoleGroup(userId int32, roleGroupId int32) (bool, error) {
+	roleGroup, err := impl.roleGroupService.FetchRoleGroupsById(roleGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching role group", "err", err, "roleGroupId", roleGroupId)
+		return false, err
+	}
+	userInfo, err := impl.userService.GetById(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user", "err", err, "userId", userId)
+		return false, err
+	}
+	for _, group := range userInfo.Groups {
+		if group == roleGroup.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (impl *CdApprovalServiceImpl) buildRequestDtos(requests []*pipelineConfig.CdApprovalRequest) ([]*CdApprovalRequestDto, error) {
+	requestDtos := make([]*CdApprovalRequestDto, 0, len(requests))
+	if len(requests) == 0 {
+		return requestDtos, nil
+	}
+	var requestIds []int
+	userIdMap := make(map[int32]bool)
+	for _, request := range requests {
+		requestIds = append(requestIds, request.Id)
+		userIdMap[request.CreatedBy] = true
+	}
+	actions, err := impl.cdApprovalRepository.FindActionsByRequestIds(requestIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching approval actions", "err", err, "requestIds", requestIds)
+		return nil, err
+	}
+	for _, action := range actions {
+		userIdMap[action.UserId] = true
+	}
+	var userIds []int32
+	for userId := range userIdMap {
+		userIds = append(userIds, userId)
+	}
+	users, err := impl.userService.GetByIds(userIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users", "err", err, "userIds", userIds)
+		return nil, err
+	}
+	emailMap := make(map[int32]string)
+	for _, userInfo := range users {
+		emailMap[userInfo.Id] = userInfo.EmailId
+	}
+	actionsMap := make(map[int][]*CdApprovalActionDto)
+	for _, action := range actions {
+		actionsMap[action.CdApprovalRequestId] = append(actionsMap[action.CdApprovalRequestId], &CdApprovalActionDto{
+			Action:  action.Action,
+			Comment: action.Comment,
+			ActedBy: emailMap[action.UserId],
+			ActedOn: action.CreatedOn,
+		})
+	}
+	requiredApprovalsMap := make(map[int]int)
+	for _, request := range requests {
+		requiredApprovals, ok := requiredApprovalsMap[request.PipelineId]
+		if !ok {
+			policy, err := impl.cdApprovalRepository.FindActivePolicyByPipelineId(request.PipelineId)
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in fetching approval policy", "err", err, "pipelineId", request.PipelineId)
+				return nil, err
+			}
+			if err == nil {
+				requiredApprovals = policy.RequiredApprovals
+			}
+			requiredApprovalsMap[request.PipelineId] = requiredApprovals
+		}
+		requestActions := actionsMap[request.Id]
+		if requestActions == nil {
+			requestActions = make([]*CdApprovalActionDto, 0)
+		}
+		requestDtos = append(requestDtos, &CdApprovalRequestDto{
+			Id:                request.Id,
+			PipelineId:        request.PipelineId,
+			CiArtifactId:      request.CiArtifactId,
+			Status:            request.Status,
+			Comment:           request.Comment,
+			RequestedBy:       emailMap[request.CreatedBy],
+			RequestedOn:       request.CreatedOn,
+			RequiredApprovals: requiredApprovals,
+			Actions:           requestActions,
+		})
+	}
+	return requestDtos, nil
+}
@@ -0,0 +1,124 @@
+package pipeline
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeApprovalRepository struct {
+	pipelineConfig.CdApprovalRepository
+	policy  *pipelineConfig.CdApprovalPolicy
+	request *pipelineConfig.CdApprovalRequest
+}
+
+func (f *fakeApprovalRepository) FindActivePolicyByPipelineId(pipelineId int) (*pipelineConfig.CdApprovalPolicy, error) {
+	if f.policy == nil {
+		return nil, pg.ErrNoRows
+	}
+	return f.policy, nil
+}
+
+func (f *fakeApprovalRepository) FindLatestRequestByPipelineIdAndArtifactId(pipelineId int, ciArtifactId int) (*pipelineConfig.CdApprovalRequest, error) {
+	if f.request == nil {
+		return nil, pg.ErrNoRows
+	}
+	return f.request, nil
+}
+
+type fakeApprovalRoleGroupService struct {
+	user.RoleGroupService
+}
+
+func (f *fakeApprovalRoleGroupService) FetchRoleGroupsById(id int32) (*bean.RoleGroup, error) {
+	return &bean.RoleGroup{Id: id, Name: "release-approvers"}, nil
+}
+
+type fakeApprovalUserService struct {
+	user.UserService
+	groups map[int32][]string
+}
+
+func (f *fakeApprovalUserService) GetById(id int32) (*bean.UserInfo, error) {
+	return &bean.UserInfo{Id: id, Groups: f.groups[id]}, nil
+}
+
+func getApprovalAction(userId int32, action pipelineConfig.CdApprovalActionType) *pipelineConfig.CdApprovalAction {
+	return &pipelineConfig.CdApprovalAction{UserId: userId, Action: action}
+}
+
+func TestGetStatusAfterAction(t *testing.T) {
+	pendingRequest := &pipelineConfig.CdApprovalRequest{Id: 1, Status: pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REQUESTED, AuditLog: sql.AuditLog{CreatedBy: 2}}
+	tests := []struct {
+		name              string
+		request           *pipelineConfig.CdApprovalRequest
+		actions           []*pipelineConfig.CdApprovalAction
+		action            *CdApprovalActionDto
+		requiredApprovals int
+		wantStatus        pipelineConfig.CdApprovalRequestStatus
+		wantHttpStatus    int
+	}{
+		{name: "self approval", request: pendingRequest, action: &CdApprovalActionDto{UserId: 2, Action: pipelineConfig.CD_APPROVAL_ACTION_APPROVE},
+			requiredApprovals: 1, wantHttpStatus: http.StatusForbidden},
+		{name: "second action of approver", request: pendingRequest, actions: []*pipelineConfig.CdApprovalAction{getApprovalAction(3, pipelineConfig.CD_APPROVAL_ACTION_APPROVE)},
+			action: &CdApprovalActionDto{UserId: 3, Action: pipelineConfig.CD_APPROVAL_ACTION_APPROVE}, requiredApprovals: 2, wantHttpStatus: http.StatusConflict},
+		{name: "request not pending", request: &pipelineConfig.CdApprovalRequest{Status: pipelineConfig.CD_APPROVAL_REQUEST_STATUS_APPROVED},
+			action: &CdApprovalActionDto{UserId: 3, Action: pipelineConfig.CD_APPROVAL_ACTION_APPROVE}, requiredApprovals: 1, wantHttpStatus: http.StatusBadRequest},
+		{name: "quorum not reached", request: pendingRequest, action: &CdApprovalActionDto{UserId: 3, Action: pipelineConfig.CD_APPROVAL_ACTION_APPROVE},
+			requiredApprovals: 2, wantStatus: pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REQUESTED},
+		{name: "quorum reached", request: pendingRequest, actions: []*pipelineConfig.CdApprovalAction{getApprovalAction(3, pipelineConfig.CD_APPROVAL_ACTION_APPROVE)},
+			action: &CdApprovalActionDto{UserId: 4, Action: pipelineConfig.CD_APPROVAL_ACTION_APPROVE}, requiredApprovals: 2, wantStatus: pipelineConfig.CD_APPROVAL_REQUEST_STATUS_APPROVED},
+		{name: "reject before quorum", request: pendingRequest, actions: []*pipelineConfig.CdApprovalAction{getApprovalAction(3, pipelineConfig.CD_APPROVAL_ACTION_APPROVE)},
+			action: &CdApprovalActionDto{UserId: 4, Action: pipelineConfig.CD_APPROVAL_ACTION_REJECT}, requiredApprovals: 3, wantStatus: pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REJECTED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := getStatusAfterAction(tt.request, tt.actions, tt.action, tt.requiredApprovals)
+			if tt.wantHttpStatus != 0 {
+				apiErr, ok := err.(*util.ApiError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.wantHttpStatus, apiErr.HttpStatusCode)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestCdApprovalService_isUserInRoleGroup(t *testing.T) {
+	userService := &fakeApprovalUserService{groups: map[int32][]string{3: {"developers", "release-approvers"}, 4: {"release-approvers-qa"}}}
+	impl := NewCdApprovalServiceImpl(zap.NewNop().Sugar(), &fakeApprovalRepository{}, &fakeApprovalRoleGroupService{}, userService)
+
+	isApprover, err := impl.isUserInRoleGroup(3, 1)
+	assert.Nil(t, err)
+	assert.True(t, isApprover)
+	isApprover, err = impl.isUserInRoleGroup(4, 1)
+	assert.Nil(t, err)
+	assert.False(t, isApprover)
+}
+
+func TestCdApprovalService_CheckArtifactApproval(t *testing.T) {
+	repository := &fakeApprovalRepository{}
+	impl := NewCdApprovalServiceImpl(zap.NewNop().Sugar(), repository, &fakeApprovalRoleGroupService{}, &fakeApprovalUserService{})
+	assert.Nil(t, impl.CheckArtifactApproval(1, 10))
+
+	repository.policy = &pipelineConfig.CdApprovalPolicy{PipelineId: 1, RequiredApprovals: 2}
+	err := impl.CheckArtifactApproval(1, 10)
+	assert.Equal(t, http.StatusForbidden, err.(*util.ApiError).HttpStatusCode)
+
+	repository.request = &pipelineConfig.CdApprovalRequest{PipelineId: 1, CiArtifactId: 10, Status: pipelineConfig.CD_APPROVAL_REQUEST_STATUS_REQUESTED}
+	err = impl.CheckArtifactApproval(1, 10)
+	assert.Equal(t, http.StatusForbidden, err.(*util.ApiError).HttpStatusCode)
+
+	repository.request.Status = pipelineConfig.CD_APPROVAL_REQUEST_STATUS_APPROVED
+	assert.Nil(t, impl.CheckArtifactApproval(1, 10))
+}
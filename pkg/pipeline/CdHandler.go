@@ -34,6 +34,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/user"
 	"github.com/go-pg/pg"
@@ -57,21 +58,22 @@ type CdHandler interface {
 }
 
 type CdHandlerImpl struct {
-	Logger                       *zap.SugaredLogger
-	cdService                    CdWorkflowService
-	cdConfig                     *CdConfig
-	ciConfig                     *CiConfig
-	userService                  user.UserService
-	ciLogService                 CiLogService
-	ciArtifactRepository         repository.CiArtifactRepository
-	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository
-	cdWorkflowRepository         pipelineConfig.CdWorkflowRepository
-	envRepository                repository2.EnvironmentRepository
-	pipelineRepository           pipelineConfig.PipelineRepository
-	ciWorkflowRepository         pipelineConfig.CiWorkflowRepository
-	helmAppService               client.HelmAppService
-	pipelineOverrideRepository   chartConfig.PipelineOverrideRepository
-	workflowDagExecutor          WorkflowDagExecutor
+	Logger                        *zap.SugaredLogger
+	cdService                     CdWorkflowService
+	cdConfig                      *CdConfig
+	ciConfig                      *CiConfig
+	userService                   user.UserService
+	ciLogService                  CiLogService
+	ciArtifactRepository          repository.CiArtifactRepository
+	ciPipelineMaterialRepository  pipelineConfig.CiPipelineMaterialRepository
+	cdWorkflowRepository          pipelineConfig.CdWorkflowRepository
+	envRepository                 repository2.EnvironmentRepository
+	pipelineRepository            pipelineConfig.PipelineRepository
+	ciWorkflowRepository          pipelineConfig.CiWorkflowRepository
+	helmAppService                client.HelmAppService
+	pipelineOverrideRepository    chartConfig.PipelineOverrideRepository
+	workflowDagExecutor           WorkflowDagExecutor
+	pipelineStatusTimelineService app.PipelineStatusTimelineService
 }
 
 func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService user.UserService,
@@ -84,23 +86,25 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, cdConfig *CdConfig, userService
 	envRepository repository2.EnvironmentRepository,
 	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
 	ciConfig *CiConfig, helmAppService client.HelmAppService,
-	pipelineOverrideRepository chartConfig.PipelineOverrideRepository, workflowDagExecutor WorkflowDagExecutor) *CdHandlerImpl {
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository, workflowDagExecutor WorkflowDagExecutor,
+	pipelineStatusTimelineService app.PipelineStatusTimelineService) *CdHandlerImpl {
 	return &CdHandlerImpl{
-		Logger:                       Logger,
-		cdConfig:                     cdConfig,
-		userService:                  userService,
-		cdService:                    cdWorkflowService,
-		ciLogService:                 ciLogService,
-		cdWorkflowRepository:         cdWorkflowRepository,
-		ciArtifactRepository:         ciArtifactRepository,
-		ciPipelineMaterialRepository: ciPipelineMaterialRepository,
-		envRepository:                envRepository,
-		pipelineRepository:           pipelineRepository,
-		ciWorkflowRepository:         ciWorkflowRepository,
-		ciConfig:                     ciConfig,
-		helmAppService:               helmAppService,
-		pipelineOverrideRepository:   pipelineOverrideRepository,
-		workflowDagExecutor:          workflowDagExecutor,
+		Logger:                        Logger,
+		cdConfig:                      cdConfig,
+		userService:                   userService,
+		cdService:                     cdWorkflowService,
+		ciLogService:                  ciLogService,
+		cdWorkflowRepository:          cdWorkflowRepository,
+		ciArtifactRepository:          ciArtifactRepository,
+		ciPipelineMaterialRepository:  ciPipelineMaterialRepository,
+		envRepository:                 envRepository,
+		pipelineRepository:            pipelineRepository,
+		ciWorkflowRepository:          ciWorkflowRepository,
+		ciConfig:                      ciConfig,
+		helmAppService:                helmAppService,
+		pipelineOverrideRepository:    pipelineOverrideRepository,
+		workflowDagExecutor:           workflowDagExecutor,
+		pipelineStatusTimelineService: pipelineStatusTimelineService,
 	}
 }
 
@@ -134,9 +138,11 @@ func (impl *CdHandlerImpl) CheckHelmAppStatusPeriodicallyAndUpdateInDb() error {
 			impl.Logger.Warnw("found error, skipping helm apps status update for this trigger", "CdWorkflowId", pipelineOverride.CdWorkflowId, "err", err)
 			continue
 		}
+		timedOut := false
 		if pipelineOverride.CreatedOn.Before(time.Now().Add(-time.Minute * DegradeTime)) {
 			// apps which are still not healthy after DegradeTime, make them "Degraded"
 			cdWf.Status = application.Degraded
+			timedOut = helmApp.ApplicationStatus != application.Degraded
 		} else {
 			cdWf.Status = helmApp.ApplicationStatus
 		}
@@ -145,6 +151,18 @@ func (impl *CdHandlerImpl) CheckHelmAppStatusPeriodicallyAndUpdateInDb() error {
 			impl.Logger.Errorw("error on update cd workflow runner", "cdWf", cdWf, "err", err)
 			return err
 		}
+		if timedOut {
+			err = impl.pipelineStatusTimelineService.SaveTimelineIfNotPresent(cdWf.Id, pipelineConfig.TIMELINE_STATUS_DEPLOYMENT_TIMED_OUT,
+				fmt.Sprintf("Resources were not ready within %d minutes, marking deployment as degraded.", DegradeTime), 1)
+		} else if cdWf.Status == application.Healthy {
+			err = impl.pipelineStatusTimelineService.SaveTimelineIfNotPresent(cdWf.Id, pipelineConfig.TIMELINE_STATUS_RESOURCES_READY, "All resources are ready.", 1)
+		} else if cdWf.Status == application.Degraded {
+			err = impl.pipelineStatusTimelineService.SaveTimelineIfNotPresent(cdWf.Id, pipelineConfig.TIMELINE_STATUS_APP_DEGRADED, "App status is degraded.", 1)
+		}
+		if err != nil {
+			// timeline is informational, status of helm app is updated regardless
+			impl.Logger.Errorw("error in saving timeline of helm app status", "cdWfrId", cdWf.Id, "status", cdWf.Status, "err", err)
+		}
 		impl.Logger.Infow("updating workflow runner status for helm app", "cdWf", cdWf)
 		if cdWf.Status == application.Healthy {
 			err = impl.workflowDagExecutor.HandleDeploymentSuccessEvent("", pipelineOverride.Id)
@@ -157,6 +175,29 @@ func (impl *CdHandlerImpl) CheckHelmAppStatusPeriodicallyAndUpdateInDb() error {
 	return nil
 }
 
+// saveStageTimeline records the completion of a pre/post stage, it is merged into the timeline of the deployment
+func (impl *CdHandlerImpl) saveStageTimeline(wfr *pipelineConfig.CdWorkflowRunner) {
+	var timelineStatus pipelineConfig.TimelineStatus
+	var stageName string
+	switch wfr.WorkflowType {
+	case bean.CD_WORKFLOW_TYPE_PRE:
+		timelineStatus, stageName = pipelineConfig.TIMELINE_STATUS_PRE_DEPLOY, "Pre-deployment"
+	case bean.CD_WORKFLOW_TYPE_POST:
+		timelineStatus, stageName = pipelineConfig.TIMELINE_STATUS_POST_DEPLOY, "Post-deployment"
+	default:
+		return
+	}
+	switch wfr.Status {
+	case string(v1alpha1.NodeSucceeded), string(v1alpha1.NodeFailed), string(v1alpha1.NodeError), WorkflowCancel:
+	default:
+		return
+	}
+	err := impl.pipelineStatusTimelineService.SaveTimelineIfNotPresent(wfr.Id, timelineStatus, fmt.Sprintf("%s stage %s.", stageName, wfr.Status), wfr.TriggeredBy)
+	if err != nil {
+		impl.Logger.Errorw("error in saving timeline of stage", "wfrId", wfr.Id, "status", wfr.Status, "err", err)
+	}
+}
+
 func (impl *CdHandlerImpl) CancelStage(workflowRunnerId int) (int, error) {
 	workflowRunner, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(workflowRunnerId)
 	if err != nil {
@@ -260,6 +301,7 @@ func (impl *CdHandlerImpl) UpdateWorkflow(workflowStatus v1alpha1.WorkflowStatus
 		if string(v1alpha1.NodeError) == savedWorkflow.Status || string(v1alpha1.NodeFailed) == savedWorkflow.Status {
 			impl.Logger.Warnw("cd stage failed for workflow: ", "wfId", savedWorkflow.Id)
 		}
+		impl.saveStageTimeline(savedWorkflow)
 	}
 	return savedWorkflow.Id, savedWorkflow.Status, nil
 }
@@ -545,6 +587,9 @@ func (impl *CdHandlerImpl) converterWFR(wfr pipelineConfig.CdWorkflowRunner) pip
 		workflow.Image = wfr.CdWorkflow.CiArtifact.Image
 		workflow.PipelineId = wfr.CdWorkflow.PipelineId
 		workflow.CiArtifactId = wfr.CdWorkflow.CiArtifactId
+		workflow.SignatureVerificationStatus = wfr.SignatureVerificationStatus
+		workflow.SignatureVerificationMessage = wfr.SignatureVerificationMessage
+		workflow.GitOpsPullRequestUrl = wfr.GitOpsPullRequestUrl
 
 	}
 	return workflow
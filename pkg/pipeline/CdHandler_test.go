@@ -0,0 +1,37 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/app"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeStageTimelineService struct {
+	app.PipelineStatusTimelineService
+	statuses []pipelineConfig.TimelineStatus
+	details  []string
+}
+
+func (f *fakeStageTimelineService) SaveTimelineIfNotPresent(wfrId int, status pipelineConfig.TimelineStatus, statusDetail string, userId int32) error {
+	f.statuses = append(f.statuses, status)
+	f.details = append(f.details, statusDetail)
+	return nil
+}
+
+func TestCdHandler_saveStageTimeline(t *testing.T) {
+	timelineService := &fakeStageTimelineService{}
+	impl := &CdHandlerImpl{Logger: zap.NewNop().Sugar(), pipelineStatusTimelineService: timelineService}
+
+	impl.saveStageTimeline(&pipelineConfig.CdWorkflowRunner{Id: 1, WorkflowType: bean.CD_WORKFLOW_TYPE_PRE, Status: string(v1alpha1.NodeRunning)})
+	impl.saveStageTimeline(&pipelineConfig.CdWorkflowRunner{Id: 1, WorkflowType: bean.CD_WORKFLOW_TYPE_PRE, Status: string(v1alpha1.NodeSucceeded)})
+	impl.saveStageTimeline(&pipelineConfig.CdWorkflowRunner{Id: 2, WorkflowType: bean.CD_WORKFLOW_TYPE_POST, Status: WorkflowCancel})
+	impl.saveStageTimeline(&pipelineConfig.CdWorkflowRunner{Id: 3, WorkflowType: bean.CD_WORKFLOW_TYPE_DEPLOY, Status: string(v1alpha1.NodeSucceeded)})
+
+	assert.Equal(t, []pipelineConfig.TimelineStatus{pipelineConfig.TIMELINE_STATUS_PRE_DEPLOY, pipelineConfig.TIMELINE_STATUS_POST_DEPLOY}, timelineService.statuses)
+	assert.Equal(t, []string{"Pre-deployment stage Succeeded.", "Post-deployment stage CANCELLED."}, timelineService.details)
+}
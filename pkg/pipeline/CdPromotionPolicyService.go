@@ -0,0 +1,373 @@
+package pipeline
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer/application"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type CdPromotionPolicyDto struct {
+	PipelineId int                        `json:"pipelineId" validate:"required"`
+	Conditions []*CdPromotionConditionDto `json:"conditions" validate:"required,min=1,dive"`
+	UserId     int32                      `json:"-"`
+}
+
+type CdPromotionConditionDto struct {
+	Id                       int    `json:"id"`
+	SourcePipelineId         int    `json:"sourcePipelineId" validate:"required"`
+	SourceEnvironmentName    string `json:"sourceEnvironmentName"`
+	MinSoakDurationInMinutes int    `json:"minSoakDurationInMinutes" validate:"min=0"`
+	RequirePostStageSuccess  bool   `json:"requirePostStageSuccess"`
+}
+
+type CdPromotionEligibilityDto struct {
+	PipelineId   int      `json:"pipelineId"`
+	CiArtifactId int      `json:"ciArtifactId"`
+	Eligible     bool     `json:"eligible"`
+	Reasons      []string `json:"reasons,omitempty"`
+}
+
+type CdPromotionPolicyService interface {
+	SavePolicy(request *CdPromotionPolicyDto) (*CdPromotionPolicyDto, error)
+	GetPolicy(pipelineId int) (*CdPromotionPolicyDto, error)
+	DeletePolicy(pipelineId int, userId int32) error
+	// GetUnmetConditions returns a reason for every promotion condition of the pipeline the artifact does not satisfy,
+	// an empty result means the artifact can be promoted to the pipeline
+	GetUnmetConditions(pipelineId int, ciArtifactId int) ([]string, error)
+	// GetUnmetConditionsForArtifacts is GetUnmetConditions for many artifacts, artifacts without unmet conditions are
+	// not part of the result
+	GetUnmetConditionsForArtifacts(pipelineId int, ciArtifactIds []int) (map[int][]string, error)
+	// CheckArtifactPromotion returns an error if the artifact does not satisfy the promotion policy of the pipeline
+	CheckArtifactPromotion(pipelineId int, ciArtifactId int) error
+	// HoldArtifactForPromotion records an artifact held back from auto promotion, it replaces the artifact held back
+	// earlier for the pipeline
+	HoldArtifactForPromotion(pipelineId int, ciArtifactId int, triggeredBy int32) error
+	GetHeldArtifacts() ([]*pipelineConfig.CdPromotionPendingArtifact, error)
+	// ReleaseHeldArtifact returns false if the artifact was already released, i.e. by another instance
+	ReleaseHeldArtifact(id int) (bool, error)
+}
+
+type CdPromotionPolicyServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	cdPromotionPolicyRepository pipelineConfig.CdPromotionPolicyRepository
+	pipelineRepository          pipelineConfig.PipelineRepository
+	cdWorkflowRepository        pipelineConfig.CdWorkflowRepository
+}
+
+func NewCdPromotionPolicyServiceImpl(logger *zap.SugaredLogger, cdPromotionPolicyRepository pipelineConfig.CdPromotionPolicyRepository,
+	pipelineRepository pipelineConfig.PipelineRepository, cdWorkflowRepository pipelineConfig.CdWorkflowRepository) *CdPromotionPolicyServiceImpl {
+	return &CdPromotionPolicyServiceImpl{
+		logger:                      logger,
+		cdPromotionPolicyRepository: cdPromotionPolicyRepository,
+		pipelineRepository:          pipelineRepository,
+		cdWorkflowRepository:        cdWorkflowRepository,
+	}
+}
+
+func (impl *CdPromotionPolicyServiceImpl) SavePolicy(request *CdPromotionPolicyDto) (*CdPromotionPolicyDto, error) {
+	pipeline, err := impl.pipelineRepository.FindById(request.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", request.PipelineId)
+		return nil, err
+	}
+	var policies []*pipelineConfig.CdPromotionPolicy
+	for _, condition := range request.Conditions {
+		sourcePipeline, err := impl.pipelineRepository.FindById(condition.SourcePipelineId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching source pipeline", "err", err, "sourcePipelineId", condition.SourcePipelineId)
+			return nil, &util.ApiError{HttpStatusCode: http.StatusBadRequest, InternalMessage: err.Error(), UserMessage: "invalid source pipeline"}
+		}
+		if sourcePipeline.Id == pipeline.Id || sourcePipeline.AppId != pipeline.AppId {
+			return nil, &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: fmt.Sprintf("source pipeline %d not allowed for pipeline %d", sourcePipeline.Id, pipeline.Id),
+				UserMessage:     "source pipeline must be another pipeline of the same app",
+			}
+		}
+		if condition.RequirePostStageSuccess && len(sourcePipeline.PostStageConfig) == 0 {
+			return nil, &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: fmt.Sprintf("post stage not configured on source pipeline %d", sourcePipeline.Id),
+				UserMessage:     fmt.Sprintf("post-deployment stage is not configured on %s", sourcePipeline.Environment.Name),
+			}
+		}
+		policies = append(policies, &pipelineConfig.CdPromotionPolicy{
+			PipelineId:               pipeline.Id,
+			SourcePipelineId:         sourcePipeline.Id,
+			MinSoakDurationInMinutes: condition.MinSoakDurationInMinutes,
+			RequirePostStageSuccess:  condition.RequirePostStageSuccess,
+			Active:                   true,
+			AuditLog:                 sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId, UpdatedOn: time.Now(), UpdatedBy: request.UserId},
+		})
+		condition.SourceEnvironmentName = sourcePipeline.Environment.Name
+	}
+
+	dbConnection := impl.cdPromotionPolicyRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		impl.logger.Errorw("error in establishing connection", "err", err)
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = impl.cdPromotionPolicyRepository.DeactivatePoliciesByPipelineId(pipeline.Id, request.UserId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deactivating promotion policy", "err", err, "pipelineId", pipeline.Id)
+		return nil, err
+	}
+	err = impl.cdPromotionPolicyRepository.SavePolicies(policies, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving promotion policy", "err", err, "pipelineId", pipeline.Id)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	for i, policy := range policies {
+		request.Conditions[i].Id = policy.Id
+	}
+	return request, nil
+}
+
+func (impl *CdPromotionPolicyServiceImpl) GetPolicy(pipelineId int) (*CdPromotionPolicyDto, error) {
+	policies, err := impl.cdPromotionPolicyRepository.FindActiveByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion policy", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, pg.ErrNoRows
+	}
+	policyDto := &CdPromotionPolicyDto{PipelineId: pipelineId}
+	for _, policy := range policies {
+		condition := &CdPromotionConditionDto{
+			Id:                       policy.Id,
+			SourcePipelineId:         policy.SourcePipelineId,
+			MinSoakDurationInMinutes: policy.MinSoakDurationInMinutes,
+			RequirePostStageSuccess:  policy.RequirePostStageSuccess,
+		}
+		sourcePipeline, err := impl.pipelineRepository.FindById(policy.SourcePipelineId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching source pipeline", "err", err, "sourcePipelineId", policy.SourcePipelineId)
+		} else {
+			condition.SourceEnvironmentName = sourcePipeline.Environment.Name
+		}
+		policyDto.Conditions = append(policyDto.Conditions, condition)
+	}
+	return policyDto, nil
+}
+
+func (impl *CdPromotionPolicyServiceImpl) DeletePolicy(pipelineId int, userId int32) error {
+	dbConnection := impl.cdPromotionPolicyRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		impl.logger.Errorw("error in establishing connection", "err", err)
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	err = impl.cdPromotionPolicyRepository.DeactivatePoliciesByPipelineId(pipelineId, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deactivating promotion policy", "err", err, "pipelineId", pipelineId)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *CdPromotionPolicyServiceImpl) GetUnmetConditions(pipelineId int, ciArtifactId int) ([]string, error) {
+	reasonsByArtifact, err := impl.GetUnmetConditionsForArtifacts(pipelineId, []int{ciArtifactId})
+	if err != nil {
+		return nil, err
+	}
+	return reasonsByArtifact[ciArtifactId], nil
+}
+
+func (impl *CdPromotionPolicyServiceImpl) GetUnmetConditionsForArtifacts(pipelineId int, ciArtifactIds []int) (map[int][]string, error) {
+	reasonsByArtifact := make(map[int][]string)
+	if len(ciArtifactIds) == 0 {
+		return reasonsByArtifact, nil
+	}
+	policies, err := impl.cdPromotionPolicyRepository.FindActiveByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion policy", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	now := time.Now()
+	for _, policy := range policies {
+		reasons, err := impl.getUnmetConditionForArtifacts(policy, ciArtifactIds, now)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating promotion condition", "err", err, "policyId", policy.Id, "ciArtifactIds", ciArtifactIds)
+			return nil, err
+		}
+		for ciArtifactId, reason := range reasons {
+			reasonsByArtifact[ciArtifactId] = append(reasonsByArtifact[ciArtifactId], reason)
+		}
+	}
+	return reasonsByArtifact, nil
+}
+
+// getUnmetConditionForArtifacts evaluates the policy for all artifacts with one lookup per runner type on the source
+// pipeline, artifacts satisfying the policy are not part of the result
+func (impl *CdPromotionPolicyServiceImpl) getUnmetConditionForArtifacts(policy *pipelineConfig.CdPromotionPolicy, ciArtifactIds []int, now time.Time) (map[int]string, error) {
+	sourcePipeline, err := impl.pipelineRepository.FindById(policy.SourcePipelineId)
+	if err != nil {
+		return nil, err
+	}
+	deployRunners, err := impl.cdWorkflowRepository.FindRunnersByPipelineIdAndArtifactIdsAndStatus(sourcePipeline.Id, ciArtifactIds, bean.CD_WORKFLOW_TYPE_DEPLOY, application.Healthy)
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	lastDeployRunners := getLastRunnerByArtifact(deployRunners)
+	postSucceeded := make(map[int]bool)
+	if policy.RequirePostStageSuccess {
+		postRunners, err := impl.cdWorkflowRepository.FindRunnersByPipelineIdAndArtifactIdsAndStatus(sourcePipeline.Id, ciArtifactIds, bean.CD_WORKFLOW_TYPE_POST, application.SUCCEEDED)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for ciArtifactId := range getLastRunnerByArtifact(postRunners) {
+			postSucceeded[ciArtifactId] = true
+		}
+	}
+	var laterDeployRunners []pipelineConfig.CdWorkflowRunner
+	if policy.MinSoakDurationInMinutes > 0 && len(lastDeployRunners) > 0 {
+		firstDeployRunnerId := 0
+		for _, deployRunner := range lastDeployRunners {
+			if firstDeployRunnerId == 0 || deployRunner.Id < firstDeployRunnerId {
+				firstDeployRunnerId = deployRunner.Id
+			}
+		}
+		laterDeployRunners, err = impl.cdWorkflowRepository.FindDeployRunnersByPipelineIdAfterRunnerId(sourcePipeline.Id, firstDeployRunnerId)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+	}
+	reasons := make(map[int]string)
+	for _, ciArtifactId := range ciArtifactIds {
+		deployRunner, deployed := lastDeployRunners[ciArtifactId]
+		var nextRunner *pipelineConfig.CdWorkflowRunner
+		if deployed {
+			nextRunner = getNextRunner(laterDeployRunners, deployRunner.Id)
+		}
+		reason := getUnmetPromotionCondition(policy, sourcePipeline.Environment.Name, deployRunner, postSucceeded[ciArtifactId], nextRunner, now)
+		if len(reason) > 0 {
+			reasons[ciArtifactId] = reason
+		}
+	}
+	return reasons, nil
+}
+
+// getLastRunnerByArtifact expects runners latest first
+func getLastRunnerByArtifact(runners []pipelineConfig.CdWorkflowRunner) map[int]*pipelineConfig.CdWorkflowRunner {
+	lastRunners := make(map[int]*pipelineConfig.CdWorkflowRunner)
+	for i := range runners {
+		ciArtifactId := runners[i].CdWorkflow.CiArtifactId
+		if _, ok := lastRunners[ciArtifactId]; !ok {
+			lastRunners[ciArtifactId] = &runners[i]
+		}
+	}
+	return lastRunners
+}
+
+// getNextRunner expects runners oldest first
+func getNextRunner(runners []pipelineConfig.CdWorkflowRunner, runnerId int) *pipelineConfig.CdWorkflowRunner {
+	for i := range runners {
+		if runners[i].Id > runnerId {
+			return &runners[i]
+		}
+	}
+	return nil
+}
+
+func getUnmetPromotionCondition(policy *pipelineConfig.CdPromotionPolicy, envName string, deployRunner *pipelineConfig.CdWorkflowRunner,
+	postSucceeded bool, nextRunner *pipelineConfig.CdWorkflowRunner, now time.Time) string {
+	if deployRunner == nil {
+		return fmt.Sprintf("not deployed successfully on %s", envName)
+	}
+	if policy.RequirePostStageSuccess && !postSucceeded {
+		return fmt.Sprintf("post-deployment stage has not succeeded on %s", envName)
+	}
+	if policy.MinSoakDurationInMinutes > 0 {
+		soakDuration := getSoakDuration(deployRunner, nextRunner, now)
+		minSoakDuration := time.Duration(policy.MinSoakDurationInMinutes) * time.Minute
+		if soakDuration < minSoakDuration {
+			return fmt.Sprintf("ran on %s for %s, at least %s required", envName, soakDuration.Truncate(time.Minute), minSoakDuration)
+		}
+	}
+	return ""
+}
+
+// getSoakDuration is the time the artifact has been running healthy on the pipeline, until now or until the next
+// deployment replaced it
+func getSoakDuration(deployRunner *pipelineConfig.CdWorkflowRunner, nextRunner *pipelineConfig.CdWorkflowRunner, now time.Time) time.Duration {
+	healthySince := deployRunner.FinishedOn
+	if healthySince.IsZero() {
+		healthySince = deployRunner.StartedOn
+	}
+	soakEnd := now
+	if nextRunner != nil && nextRunner.CdWorkflow.CiArtifactId != deployRunner.CdWorkflow.CiArtifactId && nextRunner.StartedOn.Before(soakEnd) {
+		soakEnd = nextRunner.StartedOn
+	}
+	if soakEnd.Before(healthySince) {
+		return 0
+	}
+	return soakEnd.Sub(healthySince)
+}
+
+func (impl *CdPromotionPolicyServiceImpl) CheckArtifactPromotion(pipelineId int, ciArtifactId int) error {
+	reasons, err := impl.GetUnmetConditions(pipelineId, ciArtifactId)
+	if err != nil {
+		return err
+	}
+	if len(reasons) > 0 {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusForbidden,
+			InternalMessage: "artifact not eligible for promotion",
+			UserMessage:     "artifact is not eligible for promotion: " + strings.Join(reasons, ", "),
+		}
+	}
+	return nil
+}
+
+func (impl *CdPromotionPolicyServiceImpl) HoldArtifactForPromotion(pipelineId int, ciArtifactId int, triggeredBy int32) error {
+	pendingArtifact := &pipelineConfig.CdPromotionPendingArtifact{
+		PipelineId:   pipelineId,
+		CiArtifactId: ciArtifactId,
+		TriggeredBy:  triggeredBy,
+		Active:       true,
+		AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: triggeredBy, UpdatedOn: time.Now(), UpdatedBy: triggeredBy},
+	}
+	err := impl.cdPromotionPolicyRepository.SavePendingArtifact(pendingArtifact)
+	if err != nil {
+		impl.logger.Errorw("error in saving artifact pending promotion", "err", err, "pipelineId", pipelineId, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	return nil
+}
+
+func (impl *CdPromotionPolicyServiceImpl) GetHeldArtifacts() ([]*pipelineConfig.CdPromotionPendingArtifact, error) {
+	pendingArtifacts, err := impl.cdPromotionPolicyRepository.FindActivePendingArtifacts()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching artifacts pending promotion", "err", err)
+		return nil, err
+	}
+	return pendingArtifacts, nil
+}
+
+func (impl *CdPromotionPolicyServiceImpl) ReleaseHeldArtifact(id int) (bool, error) {
+	released, err := impl.cdPromotionPolicyRepository.DeactivatePendingArtifact(id)
+	if err != nil {
+		impl.logger.Errorw("error in releasing artifact pending promotion", "err", err, "id", id)
+		return false, err
+	}
+	return released, nil
+}
@@ -0,0 +1,40 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUnmetPromotionCondition(t *testing.T) {
+	now := time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC)
+	policy := &pipelineConfig.CdPromotionPolicy{MinSoakDurationInMinutes: 60, RequirePostStageSuccess: true}
+	deployRunner := &pipelineConfig.CdWorkflowRunner{Id: 10, FinishedOn: now.Add(-90 * time.Minute), CdWorkflow: &pipelineConfig.CdWorkflow{CiArtifactId: 1}}
+
+	assert.Equal(t, "not deployed successfully on qa", getUnmetPromotionCondition(policy, "qa", nil, false, nil, now))
+	assert.Equal(t, "post-deployment stage has not succeeded on qa", getUnmetPromotionCondition(policy, "qa", deployRunner, false, nil, now))
+	assert.Equal(t, "", getUnmetPromotionCondition(policy, "qa", deployRunner, true, nil, now))
+
+	redeployRunner := &pipelineConfig.CdWorkflowRunner{Id: 11, StartedOn: now.Add(-80 * time.Minute), CdWorkflow: &pipelineConfig.CdWorkflow{CiArtifactId: 1}}
+	assert.Equal(t, "", getUnmetPromotionCondition(policy, "qa", deployRunner, true, redeployRunner, now))
+
+	nextRunner := &pipelineConfig.CdWorkflowRunner{Id: 12, StartedOn: now.Add(-60 * time.Minute), CdWorkflow: &pipelineConfig.CdWorkflow{CiArtifactId: 2}}
+	assert.Equal(t, "ran on qa for 30m0s, at least 1h0m0s required", getUnmetPromotionCondition(policy, "qa", deployRunner, true, nextRunner, now))
+}
+
+func TestGetLastAndNextRunner(t *testing.T) {
+	runners := []pipelineConfig.CdWorkflowRunner{
+		{Id: 5, CdWorkflow: &pipelineConfig.CdWorkflow{CiArtifactId: 1}},
+		{Id: 4, CdWorkflow: &pipelineConfig.CdWorkflow{CiArtifactId: 2}},
+		{Id: 3, CdWorkflow: &pipelineConfig.CdWorkflow{CiArtifactId: 1}},
+	}
+	lastRunners := getLastRunnerByArtifact(runners)
+	assert.Equal(t, 5, lastRunners[1].Id)
+	assert.Equal(t, 4, lastRunners[2].Id)
+
+	laterRunners := []pipelineConfig.CdWorkflowRunner{{Id: 4}, {Id: 5}}
+	assert.Equal(t, 5, getNextRunner(laterRunners, 4).Id)
+	assert.Nil(t, getNextRunner(laterRunners, 5))
+}
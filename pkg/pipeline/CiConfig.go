@@ -29,6 +29,19 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// kmsKeyRefPrefixes are the key references supported by cosign that keep the signing key in a kms, ci pods run user
+// scripts so the key material itself is never mounted into them
+var kmsKeyRefPrefixes = []string{"awskms://", "gcpkms://", "azurekms://", "hashivault://"}
+
+func IsKmsKeyRef(keyRef string) bool {
+	for _, prefix := range kmsKeyRefPrefixes {
+		if strings.HasPrefix(keyRef, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 const DevMode = "DEV"
 const ProdMode = "PROD"
 
@@ -57,6 +70,7 @@ type CiConfig struct {
 	ExternalCiPayload          string   `env:"EXTERNAL_CI_PAYLOAD" envDefault:"{\"ciProjectDetails\":[{\"gitRepository\":\"https://github.com/srj92/getting-started-nodejs.git\",\"checkoutPath\":\"./abc\",\"commitHash\":\"239077135f8cdeeccb7857e2851348f558cb53d3\",\"commitTime\":\"2019-10-31T20:55:21+05:30\",\"branch\":\"master\",\"message\":\"Update README.md\",\"author\":\"Suraj Gupta \"}],\"dockerImage\":\"445808685819.dkr.ecr.us-east-2.amazonaws.com/orch:23907713-2\",\"digest\":\"test1\",\"dataSource\":\"ext\",\"materialType\":\"git\"}"`
 	CiArtifactLocationFormat   string   `env:"CI_ARTIFACT_LOCATION_FORMAT" envDefault:"%d/%d.zip"`
 	ImageScannerEndpoint       string   `env:"IMAGE_SCANNER_ENDPOINT" envDefault:"http://image-scanner-new-demo-devtroncd-service.devtroncd:80"`
+	ImageSigningKeyRef         string   `env:"IMAGE_SIGNING_KEY_REF"` // kms uri of the cosign key used by ci pipelines with image signing enabled, e.g. awskms:///alias/devtron-signing
 	CloudProvider              string   `env:"BLOB_STORAGE_PROVIDER" envDefault:"S3"`
 	AzureAccountName           string   `env:"AZURE_ACCOUNT_NAME"`
 	AzureBlobContainerCiLog    string   `env:"AZURE_BLOB_CONTAINER_CI_LOG"`
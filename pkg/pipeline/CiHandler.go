@@ -85,12 +85,14 @@ type CiHandlerImpl struct {
 	eventFactory                 client.EventFactory
 	ciPipelineRepository         pipelineConfig.CiPipelineRepository
 	appListingRepository         repository.AppListingRepository
+	ciPipelineScheduleRepository pipelineConfig.CiPipelineScheduleRepository
 }
 
 func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
 	gitSensorClient gitSensor.GitSensorClient, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, workflowService WorkflowService,
 	ciLogService CiLogService, ciConfig *CiConfig, ciArtifactRepository repository.CiArtifactRepository, userService user.UserService, eventClient client.EventClient,
-	eventFactory client.EventFactory, ciPipelineRepository pipelineConfig.CiPipelineRepository, appListingRepository repository.AppListingRepository) *CiHandlerImpl {
+	eventFactory client.EventFactory, ciPipelineRepository pipelineConfig.CiPipelineRepository, appListingRepository repository.AppListingRepository,
+	ciPipelineScheduleRepository pipelineConfig.CiPipelineScheduleRepository) *CiHandlerImpl {
 	return &CiHandlerImpl{
 		Logger:                       Logger,
 		ciService:                    ciService,
@@ -106,6 +108,7 @@ func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipeline
 		eventFactory:                 eventFactory,
 		ciPipelineRepository:         ciPipelineRepository,
 		appListingRepository:         appListingRepository,
+		ciPipelineScheduleRepository: ciPipelineScheduleRepository,
 	}
 }
 
@@ -145,6 +148,7 @@ type WorkflowResponse struct {
 	TriggeredByEmail string                           `json:"triggeredByEmail"`
 	Stage            string                           `json:"stage"`
 	ArtifactId       int                              `json:"artifactId"`
+	TriggerType      string                           `json:"triggerType,omitempty"`
 }
 
 type GitTriggerInfoResponse struct {
@@ -168,6 +172,8 @@ type Trigger struct {
 
 const WorkflowCancel = "CANCELLED"
 
+const WorkflowTriggerTypeScheduled = "SCHEDULED"
+
 func (impl *CiHandlerImpl) HandleCIManual(ciTriggerRequest bean.CiTriggerRequest) (int, error) {
 	impl.Logger.Debugw("HandleCIManual for pipeline ", "PipelineId", ciTriggerRequest.PipelineId)
 	commitHashes, err := impl.buildManualTriggerCommitHashes(ciTriggerRequest)
@@ -355,6 +361,10 @@ func (impl *CiHandlerImpl) GetBuildHistory(pipelineId int, offset int, size int)
 		impl.Logger.Errorw("err", "err", err)
 		return nil, err
 	}
+	scheduledWorkflowIds, err := impl.getScheduledWorkflowIds(workFlows)
+	if err != nil {
+		return nil, err
+	}
 	var ciWorkLowResponses []WorkflowResponse
 	for _, w := range workFlows {
 		wfResponse := WorkflowResponse{
@@ -375,11 +385,31 @@ func (impl *CiHandlerImpl) GetBuildHistory(pipelineId int, offset int, size int)
 			TriggeredByEmail: w.EmailId,
 			ArtifactId:       w.CiArtifactId,
 		}
+		if scheduledWorkflowIds[w.Id] {
+			wfResponse.TriggerType = WorkflowTriggerTypeScheduled
+		}
 		ciWorkLowResponses = append(ciWorkLowResponses, wfResponse)
 	}
 	return ciWorkLowResponses, nil
 }
 
+func (impl *CiHandlerImpl) getScheduledWorkflowIds(workFlows []pipelineConfig.WorkflowWithArtifact) (map[int]bool, error) {
+	scheduledWorkflowIds := make(map[int]bool)
+	var workflowIds []int
+	for _, w := range workFlows {
+		workflowIds = append(workflowIds, w.Id)
+	}
+	scheduleRuns, err := impl.ciPipelineScheduleRepository.FindRunsByCiWorkflowIds(workflowIds)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.Logger.Errorw("error in fetching schedule runs for workflows", "err", err, "workflowIds", workflowIds)
+		return nil, err
+	}
+	for _, run := range scheduleRuns {
+		scheduledWorkflowIds[run.CiWorkflowId] = true
+	}
+	return scheduledWorkflowIds, nil
+}
+
 func (impl *CiHandlerImpl) CancelBuild(workflowId int) (int, error) {
 	workflow, err := impl.ciWorkflowRepository.FindById(workflowId)
 	if err != nil {
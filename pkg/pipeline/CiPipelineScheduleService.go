@@ -0,0 +1,375 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pipeline
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/devtron-labs/devtron/client/gitSensor"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// scheduledBuildTriggeredBy is the system admin user, scheduled builds have no human trigger
+const scheduledBuildTriggeredBy int32 = 1
+
+// ciPipelineScheduleTickExpr is how often schedules are evaluated, schedules are standard cron expressions and do not
+// fire more than once a minute
+const ciPipelineScheduleTickExpr = "* * * * *"
+
+type CiPipelineScheduleDto struct {
+	Id                int    `json:"id"`
+	CiPipelineId      int    `json:"ciPipelineId" validate:"required"`
+	CronExpression    string `json:"cronExpression" validate:"required"`
+	Timezone          string `json:"timezone"`
+	SkipIfNoNewCommit bool   `json:"skipIfNoNewCommit"`
+	UserId            int32  `json:"-"`
+}
+
+type CiPipelineScheduleRunDto struct {
+	Id                   int                                        `json:"id"`
+	CiPipelineScheduleId int                                        `json:"ciPipelineScheduleId"`
+	CiPipelineId         int                                        `json:"ciPipelineId"`
+	CiWorkflowId         int                                        `json:"ciWorkflowId,omitempty"`
+	Status               pipelineConfig.CiPipelineScheduleRunStatus `json:"status"`
+	Message              string                                     `json:"message"`
+	TriggeredOn          time.Time                                  `json:"triggeredOn"`
+}
+
+type CiPipelineScheduleService interface {
+	SaveSchedule(request *CiPipelineScheduleDto) (*CiPipelineScheduleDto, error)
+	GetSchedules(ciPipelineId int) ([]*CiPipelineScheduleDto, error)
+	// GetSchedule returns an active schedule, used by the rest layer to resolve the ci pipeline for rbac
+	GetSchedule(id int) (*CiPipelineScheduleDto, error)
+	DeleteSchedule(id int, userId int32) error
+	GetRuns(ciPipelineId int, offset int, size int) ([]*CiPipelineScheduleRunDto, error)
+	// TriggerDueSchedules is invoked every minute, it loads active schedules from db and triggers the ones whose
+	// occurrence falls in the current minute, an occurrence is claimed in db so that one instance triggers it
+	TriggerDueSchedules()
+	// TriggerScheduledBuild builds the latest head of every branch material of the pipeline unless the schedule skips
+	// builds without new commits
+	TriggerScheduledBuild(schedule *pipelineConfig.CiPipelineSchedule)
+}
+
+type CiPipelineScheduleServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	cron                         *cron.Cron
+	ciPipelineScheduleRepository pipelineConfig.CiPipelineScheduleRepository
+	ciPipelineRepository         pipelineConfig.CiPipelineRepository
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository
+	ciWorkflowRepository         pipelineConfig.CiWorkflowRepository
+	gitSensorClient              gitSensor.GitSensorClient
+	ciHandler                    CiHandler
+}
+
+func NewCiPipelineScheduleServiceImpl(logger *zap.SugaredLogger,
+	ciPipelineScheduleRepository pipelineConfig.CiPipelineScheduleRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
+	gitSensorClient gitSensor.GitSensorClient, ciHandler CiHandler) *CiPipelineScheduleServiceImpl {
+	scheduler := cron.New(
+		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	scheduler.Start()
+	impl := &CiPipelineScheduleServiceImpl{
+		logger:                       logger,
+		cron:                         scheduler,
+		ciPipelineScheduleRepository: ciPipelineScheduleRepository,
+		ciPipelineRepository:         ciPipelineRepository,
+		ciPipelineMaterialRepository: ciPipelineMaterialRepository,
+		ciWorkflowRepository:         ciWorkflowRepository,
+		gitSensorClient:              gitSensorClient,
+		ciHandler:                    ciHandler,
+	}
+	_, err := scheduler.AddFunc(ciPipelineScheduleTickExpr, impl.TriggerDueSchedules)
+	if err != nil {
+		logger.Errorw("error in starting ci pipeline schedule cron job", "err", err)
+		return nil
+	}
+	return impl
+}
+
+func getScheduleSpec(cronExpression string, timezone string) string {
+	return fmt.Sprintf("CRON_TZ=%s %s", timezone, cronExpression)
+}
+
+// getDueOccurrence returns the occurrence of schedule in the minute of now, false if schedule does not fire in it
+func getDueOccurrence(schedule cron.Schedule, now time.Time) (time.Time, bool) {
+	minute := now.Truncate(time.Minute)
+	occurrence := schedule.Next(minute.Add(-time.Second))
+	return occurrence, occurrence.Equal(minute)
+}
+
+func (impl *CiPipelineScheduleServiceImpl) SaveSchedule(request *CiPipelineScheduleDto) (*CiPipelineScheduleDto, error) {
+	if len(request.Timezone) == 0 {
+		request.Timezone = "UTC"
+	}
+	if _, err := cron.ParseStandard(getScheduleSpec(request.CronExpression, request.Timezone)); err != nil {
+		impl.logger.Errorw("invalid cron expression or timezone for ci pipeline schedule", "err", err, "request", request)
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: err.Error(),
+			UserMessage:     fmt.Sprintf("invalid schedule %q in timezone %q", request.CronExpression, request.Timezone),
+		}
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(request.CiPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", request.CiPipelineId)
+		return nil, err
+	}
+	if ciPipeline.IsExternal || ciPipeline.ParentCiPipeline > 0 {
+		return nil, &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "schedule not supported for external or linked ci pipeline",
+			UserMessage:     "schedules can only be set on ci pipelines which build from git",
+		}
+	}
+	for _, material := range ciPipeline.CiPipelineMaterials {
+		if material.Active && material.Type != pipelineConfig.SOURCE_TYPE_BRANCH_FIXED {
+			return nil, &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: "schedule not supported for non branch ci pipeline material",
+				UserMessage:     "schedules can only be set on ci pipelines whose materials build a fixed branch",
+			}
+		}
+	}
+
+	var schedule *pipelineConfig.CiPipelineSchedule
+	if request.Id > 0 {
+		schedule, err = impl.ciPipelineScheduleRepository.FindById(request.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "id", request.Id)
+			return nil, err
+		}
+		if schedule.CiPipelineId != request.CiPipelineId {
+			return nil, fmt.Errorf("schedule %d does not belong to ci pipeline %d", request.Id, request.CiPipelineId)
+		}
+	} else {
+		schedule = &pipelineConfig.CiPipelineSchedule{
+			CiPipelineId: request.CiPipelineId,
+			Active:       true,
+			AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: request.UserId},
+		}
+	}
+	schedule.CronExpression = request.CronExpression
+	schedule.Timezone = request.Timezone
+	schedule.SkipIfNoNewCommit = request.SkipIfNoNewCommit
+	schedule.UpdatedOn = time.Now()
+	schedule.UpdatedBy = request.UserId
+	if schedule.Id > 0 {
+		err = impl.ciPipelineScheduleRepository.Update(schedule)
+	} else {
+		err = impl.ciPipelineScheduleRepository.Save(schedule)
+	}
+	if err != nil {
+		return nil, err
+	}
+	request.Id = schedule.Id
+	return request, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetSchedules(ciPipelineId int) ([]*CiPipelineScheduleDto, error) {
+	schedules, err := impl.ciPipelineScheduleRepository.FindActiveByCiPipelineId(ciPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching ci pipeline schedules", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	scheduleDtos := make([]*CiPipelineScheduleDto, 0, len(schedules))
+	for _, schedule := range schedules {
+		scheduleDtos = append(scheduleDtos, adaptCiPipelineSchedule(schedule))
+	}
+	return scheduleDtos, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetSchedule(id int) (*CiPipelineScheduleDto, error) {
+	schedule, err := impl.ciPipelineScheduleRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "id", id)
+		return nil, err
+	}
+	return adaptCiPipelineSchedule(schedule), nil
+}
+
+func adaptCiPipelineSchedule(schedule *pipelineConfig.CiPipelineSchedule) *CiPipelineScheduleDto {
+	return &CiPipelineScheduleDto{
+		Id:                schedule.Id,
+		CiPipelineId:      schedule.CiPipelineId,
+		CronExpression:    schedule.CronExpression,
+		Timezone:          schedule.Timezone,
+		SkipIfNoNewCommit: schedule.SkipIfNoNewCommit,
+	}
+}
+
+func (impl *CiPipelineScheduleServiceImpl) DeleteSchedule(id int, userId int32) error {
+	schedule, err := impl.ciPipelineScheduleRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline schedule", "err", err, "id", id)
+		return err
+	}
+	schedule.Active = false
+	schedule.UpdatedOn = time.Now()
+	schedule.UpdatedBy = userId
+	return impl.ciPipelineScheduleRepository.Update(schedule)
+}
+
+func (impl *CiPipelineScheduleServiceImpl) GetRuns(ciPipelineId int, offset int, size int) ([]*CiPipelineScheduleRunDto, error) {
+	runs, err := impl.ciPipelineScheduleRepository.FindRunsByCiPipelineId(ciPipelineId, offset, size)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching ci pipeline schedule runs", "err", err, "ciPipelineId", ciPipelineId)
+		return nil, err
+	}
+	runDtos := make([]*CiPipelineScheduleRunDto, 0, len(runs))
+	for _, run := range runs {
+		runDtos = append(runDtos, &CiPipelineScheduleRunDto{
+			Id:                   run.Id,
+			CiPipelineScheduleId: run.CiPipelineScheduleId,
+			CiPipelineId:         run.CiPipelineId,
+			CiWorkflowId:         run.CiWorkflowId,
+			Status:               run.Status,
+			Message:              run.Message,
+			TriggeredOn:          run.CreatedOn,
+		})
+	}
+	return runDtos, nil
+}
+
+func (impl *CiPipelineScheduleServiceImpl) TriggerDueSchedules() {
+	now := time.Now()
+	schedules, err := impl.ciPipelineScheduleRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching active ci pipeline schedules", "err", err)
+		return
+	}
+	for _, schedule := range schedules {
+		cronSchedule, err := cron.ParseStandard(getScheduleSpec(schedule.CronExpression, schedule.Timezone))
+		if err != nil {
+			impl.logger.Errorw("error in parsing ci pipeline schedule, skipping", "err", err, "scheduleId", schedule.Id)
+			continue
+		}
+		occurrence, due := getDueOccurrence(cronSchedule, now)
+		if !due {
+			continue
+		}
+		claimed, err := impl.ciPipelineScheduleRepository.ClaimOccurrence(schedule.Id, occurrence)
+		if err != nil || !claimed {
+			continue
+		}
+		impl.TriggerScheduledBuild(schedule)
+	}
+}
+
+func (impl *CiPipelineScheduleServiceImpl) TriggerScheduledBuild(schedule *pipelineConfig.CiPipelineSchedule) {
+	run := &pipelineConfig.CiPipelineScheduleRun{
+		CiPipelineScheduleId: schedule.Id,
+		CiPipelineId:         schedule.CiPipelineId,
+		AuditLog:             sql.AuditLog{CreatedOn: time.Now(), CreatedBy: scheduledBuildTriggeredBy, UpdatedOn: time.Now(), UpdatedBy: scheduledBuildTriggeredBy},
+	}
+	workflowId, skipMessage, err := impl.triggerBuildForSchedule(schedule)
+	if err != nil {
+		impl.logger.Errorw("error in triggering scheduled ci build", "err", err, "scheduleId", schedule.Id, "ciPipelineId", schedule.CiPipelineId)
+		run.Status = pipelineConfig.CI_PIPELINE_SCHEDULE_RUN_STATUS_FAILED
+		run.Message = err.Error()
+	} else if len(skipMessage) > 0 {
+		run.Status = pipelineConfig.CI_PIPELINE_SCHEDULE_RUN_STATUS_SKIPPED
+		run.Message = skipMessage
+	} else {
+		run.Status = pipelineConfig.CI_PIPELINE_SCHEDULE_RUN_STATUS_TRIGGERED
+		run.CiWorkflowId = workflowId
+	}
+	err = impl.ciPipelineScheduleRepository.SaveRun(run)
+	if err != nil {
+		impl.logger.Errorw("error in saving ci pipeline schedule run", "err", err, "scheduleId", schedule.Id)
+	}
+}
+
+// triggerBuildForSchedule returns the triggered workflow id, or a reason if the build was skipped
+func (impl *CiPipelineScheduleServiceImpl) triggerBuildForSchedule(schedule *pipelineConfig.CiPipelineSchedule) (int, string, error) {
+	ciMaterials, err := impl.ciPipelineMaterialRepository.GetByPipelineId(schedule.CiPipelineId)
+	if err != nil {
+		return 0, "", err
+	}
+	var materialIds []int
+	for _, ciMaterial := range ciMaterials {
+		if ciMaterial.Type == pipelineConfig.SOURCE_TYPE_BRANCH_FIXED {
+			materialIds = append(materialIds, ciMaterial.Id)
+		}
+	}
+	if len(materialIds) == 0 {
+		return 0, "", fmt.Errorf("no branch material found for ci pipeline %d", schedule.CiPipelineId)
+	}
+	heads, err := impl.gitSensorClient.GetHeadForPipelineMaterials(&gitSensor.HeadRequest{MaterialIds: materialIds})
+	if err != nil {
+		return 0, "", err
+	}
+	if schedule.SkipIfNoNewCommit {
+		isBuilt, err := impl.isHeadAlreadyBuilt(schedule.CiPipelineId, heads)
+		if err != nil {
+			return 0, "", err
+		}
+		if isBuilt {
+			return 0, "no new commit since last build", nil
+		}
+	}
+	ciTriggerRequest := bean.CiTriggerRequest{
+		PipelineId:  schedule.CiPipelineId,
+		TriggeredBy: scheduledBuildTriggeredBy,
+	}
+	for _, head := range heads {
+		ciTriggerRequest.CiPipelineMaterial = append(ciTriggerRequest.CiPipelineMaterial, bean.CiPipelineMaterial{
+			Id:            head.Id,
+			GitMaterialId: head.GitMaterialId,
+			Type:          string(head.Type),
+			Value:         head.Value,
+			Active:        head.Active,
+			GitCommit:     bean.GitCommit{Commit: head.GitCommit.Commit},
+		})
+	}
+	workflowId, err := impl.ciHandler.HandleCIManual(ciTriggerRequest)
+	if err != nil {
+		return 0, "", err
+	}
+	return workflowId, "", nil
+}
+
+// isHeadAlreadyBuilt tells whether last running or succeeded build of the pipeline is of the heads, failed and aborted
+// builds are not counted so that heads are built again
+func (impl *CiPipelineScheduleServiceImpl) isHeadAlreadyBuilt(ciPipelineId int, heads []*gitSensor.CiPipelineMaterial) (bool, error) {
+	statuses := []string{WorkflowStarting, string(v1alpha1.NodePending), string(v1alpha1.NodeRunning), string(v1alpha1.NodeSucceeded)}
+	lastWorkflow, err := impl.ciWorkflowRepository.FindLastTriggeredWorkflowByStatusesIn(ciPipelineId, statuses)
+	if util.IsErrNoRows(err) {
+		return false, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching last triggered ci workflow", "err", err, "ciPipelineId", ciPipelineId)
+		return false, err
+	}
+	for _, head := range heads {
+		gitTrigger, ok := lastWorkflow.GitTriggers[head.Id]
+		if !ok || gitTrigger.Commit != head.GitCommit.Commit {
+			return false, nil
+		}
+	}
+	return true, nil
+}
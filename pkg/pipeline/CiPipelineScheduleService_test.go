@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDueOccurrence(t *testing.T) {
+	schedule, err := cron.ParseStandard(getScheduleSpec("30 2 * * *", "Asia/Kolkata"))
+	assert.Nil(t, err)
+	location, err := time.LoadLocation("Asia/Kolkata")
+	assert.Nil(t, err)
+
+	occurrence, due := getDueOccurrence(schedule, time.Date(2023, 1, 10, 2, 30, 42, 0, location))
+	assert.True(t, due)
+	assert.True(t, occurrence.Equal(time.Date(2023, 1, 10, 2, 30, 0, 0, location)))
+
+	_, due = getDueOccurrence(schedule, time.Date(2023, 1, 10, 2, 31, 0, 0, location))
+	assert.False(t, due)
+	_, due = getDueOccurrence(schedule, time.Date(2023, 1, 10, 2, 29, 59, 0, location))
+	assert.False(t, due)
+}
@@ -275,6 +275,10 @@ func (impl *CiServiceImpl) buildArtifactLocationAzure(ciWorkflowConfig *pipeline
 func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.CiPipeline, trigger Trigger,
 	ciMaterials []*pipelineConfig.CiPipelineMaterial, savedWf *pipelineConfig.CiWorkflow,
 	ciWorkflowConfig *pipelineConfig.CiWorkflowConfig, ciPipelineScripts []*pipelineConfig.CiPipelineScript) (*WorkflowRequest, error) {
+	if pipeline.ImageSigningEnabled && !IsKmsKeyRef(impl.ciConfig.ImageSigningKeyRef) {
+		impl.Logger.Errorw("image signing enabled without kms key reference", "ciPipelineId", pipeline.Id)
+		return nil, fmt.Errorf("image signing requires IMAGE_SIGNING_KEY_REF to be a kms key reference")
+	}
 	var ciProjectDetails []CiProjectDetails
 	commitHashes := trigger.CommitHashes
 	for _, ciMaterial := range ciMaterials {
@@ -415,6 +419,7 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 		CacheLimit:                 impl.ciConfig.CacheLimit,
 		InvalidateCache:            trigger.InvalidateCache,
 		ScanEnabled:                pipeline.ScanEnabled,
+		ImageSigningEnabled:        pipeline.ImageSigningEnabled,
 		CloudProvider:              impl.ciConfig.CloudProvider,
 		DefaultAddressPoolBaseCidr: impl.ciConfig.DefaultAddressPoolBaseCidr,
 		DefaultAddressPoolSize:     impl.ciConfig.DefaultAddressPoolSize,
@@ -148,16 +148,17 @@ func (impl DbPipelineOrchestratorImpl) PatchMaterialValue(createRequest *bean.Ci
 	defer tx.Rollback()
 
 	ciPipelineObject := &pipelineConfig.CiPipeline{
-		Version:          createRequest.Version,
-		Id:               createRequest.Id,
-		DockerArgs:       string(argByte),
-		Active:           createRequest.Active,
-		IsManual:         createRequest.IsManual,
-		IsExternal:       createRequest.IsExternal,
-		Deleted:          createRequest.Deleted,
-		ParentCiPipeline: createRequest.ParentCiPipeline,
-		ScanEnabled:      createRequest.ScanEnabled,
-		AuditLog:         sql.AuditLog{UpdatedBy: userId, UpdatedOn: time.Now()},
+		Version:             createRequest.Version,
+		Id:                  createRequest.Id,
+		DockerArgs:          string(argByte),
+		Active:              createRequest.Active,
+		IsManual:            createRequest.IsManual,
+		IsExternal:          createRequest.IsExternal,
+		Deleted:             createRequest.Deleted,
+		ParentCiPipeline:    createRequest.ParentCiPipeline,
+		ScanEnabled:         createRequest.ScanEnabled,
+		ImageSigningEnabled: createRequest.ImageSigningEnabled,
+		AuditLog:            sql.AuditLog{UpdatedBy: userId, UpdatedOn: time.Now()},
 	}
 	err = impl.ciPipelineRepository.Update(ciPipelineObject, tx)
 	if err != nil {
@@ -385,18 +386,19 @@ func (impl DbPipelineOrchestratorImpl) CreateCiConf(createRequest *bean.CiConfig
 		defer tx.Rollback()
 
 		ciPipelineObject := &pipelineConfig.CiPipeline{
-			AppId:            createRequest.AppId,
-			IsManual:         ciPipeline.IsManual,
-			IsExternal:       ciPipeline.IsExternal,
-			CiTemplateId:     templateId,
-			Version:          ciPipeline.Version,
-			Name:             ciPipeline.Name,
-			ParentCiPipeline: ciPipeline.ParentCiPipeline,
-			DockerArgs:       string(argByte),
-			Active:           true,
-			Deleted:          false,
-			ScanEnabled:      createRequest.ScanEnabled,
-			AuditLog:         sql.AuditLog{UpdatedBy: createRequest.UserId, CreatedBy: createRequest.UserId, UpdatedOn: time.Now(), CreatedOn: time.Now()},
+			AppId:               createRequest.AppId,
+			IsManual:            ciPipeline.IsManual,
+			IsExternal:          ciPipeline.IsExternal,
+			CiTemplateId:        templateId,
+			Version:             ciPipeline.Version,
+			Name:                ciPipeline.Name,
+			ParentCiPipeline:    ciPipeline.ParentCiPipeline,
+			DockerArgs:          string(argByte),
+			Active:              true,
+			Deleted:             false,
+			ScanEnabled:         createRequest.ScanEnabled,
+			ImageSigningEnabled: ciPipeline.ImageSigningEnabled,
+			AuditLog:            sql.AuditLog{UpdatedBy: createRequest.UserId, CreatedBy: createRequest.UserId, UpdatedOn: time.Now(), CreatedOn: time.Now()},
 		}
 		err = impl.ciPipelineRepository.Save(ciPipelineObject, tx)
 		ciPipeline.Id = ciPipelineObject.Id
@@ -147,6 +147,7 @@ type PipelineBuilderImpl struct {
 	helmAppService                   client.HelmAppService
 	deploymentGroupRepository        repository.DeploymentGroupRepository
 	ciPipelineMaterialRepository     pipelineConfig.CiPipelineMaterialRepository
+	cdPromotionPolicyService         CdPromotionPolicyService
 }
 
 func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
@@ -183,7 +184,8 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 	chartTemplateService util.ChartTemplateService, chartService chart.ChartService,
 	helmAppService client.HelmAppService,
 	deploymentGroupRepository repository.DeploymentGroupRepository,
-	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository) *PipelineBuilderImpl {
+	ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository,
+	cdPromotionPolicyService CdPromotionPolicyService) *PipelineBuilderImpl {
 	return &PipelineBuilderImpl{
 		logger:                           logger,
 		dbPipelineOrchestrator:           dbPipelineOrchestrator,
@@ -224,6 +226,7 @@ func NewPipelineBuilderImpl(logger *zap.SugaredLogger,
 		helmAppService:                   helmAppService,
 		deploymentGroupRepository:        deploymentGroupRepository,
 		ciPipelineMaterialRepository:     ciPipelineMaterialRepository,
+		cdPromotionPolicyService:         cdPromotionPolicyService,
 	}
 }
 
@@ -497,6 +500,7 @@ func (impl PipelineBuilderImpl) GetCiPipeline(appId int) (ciConfig *bean.CiConfi
 			BeforeDockerBuildScripts: beforeDockerBuildScripts,
 			AfterDockerBuildScripts:  afterDockerBuildScripts,
 			ScanEnabled:              pipeline.ScanEnabled,
+			ImageSigningEnabled:      pipeline.ImageSigningEnabled,
 		}
 
 		for _, material := range pipeline.CiPipelineMaterials {
@@ -1106,8 +1110,11 @@ func (impl PipelineBuilderImpl) CreateCdPipelines(pipelineCreateRequest *bean.Cd
 		if err != nil && pg.ErrNoRows != err {
 			return nil, err
 		}
-		gitOpsRepoName := impl.chartTemplateService.GetGitOpsRepoName(app.AppName)
-		chartGitAttr, err := impl.chartTemplateService.CreateGitRepositoryForApp(gitOpsRepoName, chart.ReferenceTemplate, chart.ChartVersion, pipelineCreateRequest.UserId)
+		gitOpsRepoName, appDir, err := impl.getGitOpsRepoNameForApp(app, chart)
+		if err != nil {
+			return nil, err
+		}
+		chartGitAttr, err := impl.chartTemplateService.CreateGitRepositoryForApp(gitOpsRepoName, appDir, chart.ReferenceTemplate, chart.ChartVersion, pipelineCreateRequest.UserId)
 		if err != nil {
 			impl.logger.Errorw("error in pushing chart to git ", "path", chartGitAttr.ChartLocation, "err", err)
 			return nil, err
@@ -1746,6 +1753,43 @@ func (impl PipelineBuilderImpl) GetArtifactsByCDPipeline(cdPipelineId int, stage
 		impl.logger.Errorw("error in getting artifacts for cd", "err", err, "stage", stage, "cdPipelineId", cdPipelineId)
 		return ciArtifactsResponse, err
 	}
+	if stage == bean2.CD_WORKFLOW_TYPE_DEPLOY {
+		ciArtifactsResponse, err = impl.filterArtifactsByPromotionPolicy(ciArtifactsResponse)
+		if err != nil {
+			impl.logger.Errorw("error in filtering artifacts by promotion policy", "err", err, "cdPipelineId", cdPipelineId)
+			return ciArtifactsResponse, err
+		}
+	}
+	return ciArtifactsResponse, nil
+}
+
+// filterArtifactsByPromotionPolicy moves the artifacts not eligible for promotion to the pipeline out of the
+// deployable list, marked with the unmet conditions. The currently deployed artifact is always kept.
+func (impl PipelineBuilderImpl) filterArtifactsByPromotionPolicy(ciArtifactsResponse bean.CiArtifactResponse) (bean.CiArtifactResponse, error) {
+	var ciArtifactIds []int
+	for _, ciArtifact := range ciArtifactsResponse.CiArtifacts {
+		if !ciArtifact.Deployed {
+			ciArtifactIds = append(ciArtifactIds, ciArtifact.Id)
+		}
+	}
+	reasonsByArtifact, err := impl.cdPromotionPolicyService.GetUnmetConditionsForArtifacts(ciArtifactsResponse.CdPipelineId, ciArtifactIds)
+	if err != nil {
+		return ciArtifactsResponse, err
+	}
+	var eligibleArtifacts []bean.CiArtifactBean
+	for _, ciArtifact := range ciArtifactsResponse.CiArtifacts {
+		if reasons := reasonsByArtifact[ciArtifact.Id]; !ciArtifact.Deployed && len(reasons) > 0 {
+			ciArtifact.PromotionBlocked = true
+			ciArtifact.PromotionBlockedReasons = reasons
+			ciArtifactsResponse.BlockedCiArtifacts = append(ciArtifactsResponse.BlockedCiArtifacts, ciArtifact)
+		} else {
+			eligibleArtifacts = append(eligibleArtifacts, ciArtifact)
+		}
+	}
+	if eligibleArtifacts == nil {
+		eligibleArtifacts = []bean.CiArtifactBean{}
+	}
+	ciArtifactsResponse.CiArtifacts = eligibleArtifacts
 	return ciArtifactsResponse, nil
 }
 
@@ -2349,6 +2393,7 @@ func (impl PipelineBuilderImpl) GetCiPipelineById(pipelineId int) (ciPipeline *b
 		BeforeDockerBuildScripts: beforeDockerBuildScripts,
 		AfterDockerBuildScripts:  afterDockerBuildScripts,
 		ScanEnabled:              pipeline.ScanEnabled,
+		ImageSigningEnabled:      pipeline.ImageSigningEnabled,
 	}
 	for _, material := range pipeline.CiPipelineMaterials {
 		ciMaterial := &bean.CiMaterial{
@@ -2408,6 +2453,39 @@ func (impl PipelineBuilderImpl) FindAllMatchesByAppName(appName string) ([]*AppB
 	return appsRes, err
 }
 
+// getGitOpsRepoNameForApp returns gitops repo and directory of app in it, app keeps the repo it already has until
+// migrated so that change of repo layout does not move it
+func (impl PipelineBuilderImpl) getGitOpsRepoNameForApp(app *app2.App, chart *chartRepoRepository.Chart) (string, string, error) {
+	if len(chart.GitRepoUrl) > 0 {
+		return impl.chartTemplateService.GetGitOpsRepoNameFromUrl(chart.GitRepoUrl), util.GetAppDirInGitOpsRepo(app.AppName, chart.ChartLocation), nil
+	}
+	teamName := ""
+	if impl.chartTemplateService.IsSharedGitOpsRepoLayout() {
+		appWithTeam, err := impl.appRepo.FindAppAndProjectByAppId(app.Id)
+		if err != nil {
+			impl.logger.Errorw("error in fetching team of app", "appId", app.Id, "err", err)
+			return "", "", err
+		}
+		teamName = appWithTeam.Team.Name
+	}
+	gitOpsRepoName, appDir, err := impl.chartTemplateService.GetGitOpsRepoNameForApp(app.AppName, teamName)
+	if err != nil {
+		return "", "", err
+	} else if len(appDir) == 0 {
+		return gitOpsRepoName, appDir, nil
+	}
+	// app created before the layout was shared keeps a repo of its own, which may be named like the shared repo
+	ownerApp, err := impl.appRepo.FindActiveByName(impl.chartTemplateService.GetAppNameOfSharedGitOpsRepo(teamName))
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching app named like shared gitops repo", "repo", gitOpsRepoName, "err", err)
+		return "", "", err
+	} else if err == nil {
+		message := fmt.Sprintf("gitops repo %s is the repo of app %s, rename the app to share the repo", gitOpsRepoName, ownerApp.AppName)
+		return "", "", &util.ApiError{HttpStatusCode: http.StatusConflict, InternalMessage: message, UserMessage: message}
+	}
+	return gitOpsRepoName, appDir, nil
+}
+
 func (impl PipelineBuilderImpl) updateGitRepoUrlInCharts(appId int, chartGitAttribute *util.ChartGitAttribute, userId int32) error {
 	charts, err := impl.chartRepository.FindActiveChartsByAppId(appId)
 	if err != nil && pg.ErrNoRows != err {
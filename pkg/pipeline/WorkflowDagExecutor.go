@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/devtron-labs/devtron/util/argo"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -46,6 +47,8 @@ import (
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app"
 	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/imageSignature"
 	"github.com/devtron-labs/devtron/pkg/user"
 	util4 "github.com/devtron-labs/devtron/util"
 	util2 "github.com/devtron-labs/devtron/util/event"
@@ -64,9 +67,12 @@ type WorkflowDagExecutor interface {
 	TriggerPostStage(cdWf *pipelineConfig.CdWorkflow, cdPipeline *pipelineConfig.Pipeline, triggeredBy int32) error
 	TriggerDeployment(cdWf *pipelineConfig.CdWorkflow, artifact *repository.CiArtifact, pipeline *pipelineConfig.Pipeline, applyAuth bool, async bool, triggeredBy int32) error
 	ManualCdTrigger(overrideRequest *bean.ValuesOverrideRequest, ctx context.Context) (int, error)
-	TriggerBulkDeploymentAsync(requests []*BulkTriggerRequest, UserId int32) (interface{}, error)
+	TriggerBulkDeploymentAsync(requests []*BulkTriggerRequest, UserId int32) ([]*BulkTriggerResponse, error)
 	StopStartApp(stopRequest *StopAppRequest, ctx context.Context) (int, error)
 	TriggerBulkHibernateAsync(request StopDeploymentGroupRequest, ctx context.Context) (interface{}, error)
+	RollbackCanaryDeployment(run *pipelineConfig.CanaryAnalysisRun) error
+	// TriggerPendingPromotions auto promotes the artifacts held back by promotion policies which are now eligible
+	TriggerPendingPromotions() error
 }
 
 type WorkflowDagExecutorImpl struct {
@@ -95,6 +101,12 @@ type WorkflowDagExecutorImpl struct {
 	appWorkflowRepository         appWorkflow.AppWorkflowRepository
 	prePostCdScriptHistoryService history2.PrePostCdScriptHistoryService
 	argoUserService               argo.ArgoUserService
+	canaryAnalysisService         CanaryAnalysisService
+	cdApprovalService             CdApprovalService
+	deploymentWindowService       deploymentWindow.DeploymentWindowService
+	cdPromotionPolicyService      CdPromotionPolicyService
+	imageSignatureService         imageSignature.ImageSignatureService
+	gitOpsPullRequestService      app.GitOpsPullRequestService
 }
 
 type CiArtifactDTO struct {
@@ -139,7 +151,13 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 	scanResultRepository security.ImageScanResultRepository,
 	appWorkflowRepository appWorkflow.AppWorkflowRepository,
 	prePostCdScriptHistoryService history2.PrePostCdScriptHistoryService,
-	argoUserService argo.ArgoUserService) *WorkflowDagExecutorImpl {
+	argoUserService argo.ArgoUserService,
+	canaryAnalysisService CanaryAnalysisService,
+	cdApprovalService CdApprovalService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	cdPromotionPolicyService CdPromotionPolicyService,
+	imageSignatureService imageSignature.ImageSignatureService,
+	gitOpsPullRequestService app.GitOpsPullRequestService) *WorkflowDagExecutorImpl {
 	wde := &WorkflowDagExecutorImpl{logger: Logger,
 		pipelineRepository:            pipelineRepository,
 		cdWorkflowRepository:          cdWorkflowRepository,
@@ -165,6 +183,12 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 		appWorkflowRepository:         appWorkflowRepository,
 		prePostCdScriptHistoryService: prePostCdScriptHistoryService,
 		argoUserService:               argoUserService,
+		canaryAnalysisService:         canaryAnalysisService,
+		cdApprovalService:             cdApprovalService,
+		deploymentWindowService:       deploymentWindowService,
+		cdPromotionPolicyService:      cdPromotionPolicyService,
+		imageSignatureService:         imageSignatureService,
+		gitOpsPullRequestService:      gitOpsPullRequestService,
 	}
 	err := util4.AddStream(wde.pubsubClient.JetStrCtxt, util4.ORCHESTRATOR_STREAM, util4.CI_RUNNER_STREAM)
 	if err != nil {
@@ -244,6 +268,16 @@ func (impl *WorkflowDagExecutorImpl) HandleCiSuccessEvent(artifact *repository.C
 
 func (impl *WorkflowDagExecutorImpl) triggerStage(cdWf *pipelineConfig.CdWorkflow, pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, applyAuth bool, async bool, triggeredBy int32) error {
 	var err error
+	isAutoTrigger := pipeline.TriggerType == pipelineConfig.TRIGGER_TYPE_AUTOMATIC
+	if len(pipeline.PreStageConfig) > 0 {
+		isAutoTrigger = pipeline.PreTriggerType == pipelineConfig.TRIGGER_TYPE_AUTOMATIC
+	}
+	if isAutoTrigger && cdWf == nil {
+		held, err := impl.holdArtifactForPromotion(pipeline, artifact, triggeredBy)
+		if err != nil || held {
+			return err
+		}
+	}
 	if len(pipeline.PreStageConfig) > 0 {
 		// pre stage exists
 		if pipeline.PreTriggerType == pipelineConfig.TRIGGER_TYPE_AUTOMATIC {
@@ -260,6 +294,66 @@ func (impl *WorkflowDagExecutorImpl) triggerStage(cdWf *pipelineConfig.CdWorkflo
 	return nil
 }
 
+// holdArtifactForPromotion holds back the auto promotion of an artifact not yet eligible for the pipeline, held artifacts
+// are re-evaluated by TriggerPendingPromotions
+func (impl *WorkflowDagExecutorImpl) holdArtifactForPromotion(pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, triggeredBy int32) (bool, error) {
+	reasons, err := impl.cdPromotionPolicyService.GetUnmetConditions(pipeline.Id, artifact.Id)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating promotion policy", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+		return false, err
+	}
+	if len(reasons) == 0 {
+		return false, nil
+	}
+	impl.logger.Infow("artifact not eligible for promotion, holding auto trigger", "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id, "reasons", reasons)
+	err = impl.cdPromotionPolicyService.HoldArtifactForPromotion(pipeline.Id, artifact.Id, triggeredBy)
+	return true, err
+}
+
+func (impl *WorkflowDagExecutorImpl) TriggerPendingPromotions() error {
+	pendingArtifacts, err := impl.cdPromotionPolicyService.GetHeldArtifacts()
+	if err != nil {
+		return err
+	}
+	for _, pendingArtifact := range pendingArtifacts {
+		reasons, err := impl.cdPromotionPolicyService.GetUnmetConditions(pendingArtifact.PipelineId, pendingArtifact.CiArtifactId)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating promotion policy", "err", err, "pipelineId", pendingArtifact.PipelineId, "ciArtifactId", pendingArtifact.CiArtifactId)
+			continue
+		}
+		//artifact deployed on the pipeline after it was held back supersedes the held artifact
+		latestCdWorkflow, err := impl.cdWorkflowRepository.FindLatestCdWorkflowByPipelineId([]int{pendingArtifact.PipelineId})
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching latest cd workflow", "err", err, "pipelineId", pendingArtifact.PipelineId)
+			continue
+		}
+		superseded := err == nil && latestCdWorkflow.CreatedOn.After(pendingArtifact.CreatedOn)
+		if len(reasons) > 0 && !superseded {
+			continue
+		}
+		released, err := impl.cdPromotionPolicyService.ReleaseHeldArtifact(pendingArtifact.Id)
+		if err != nil || !released || superseded {
+			continue
+		}
+		pipeline, err := impl.pipelineRepository.FindById(pendingArtifact.PipelineId)
+		if err != nil {
+			impl.logger.Errorw("error in getting cd pipeline by id", "err", err, "pipelineId", pendingArtifact.PipelineId)
+			continue
+		}
+		artifact, err := impl.ciArtifactRepository.Get(pendingArtifact.CiArtifactId)
+		if err != nil {
+			impl.logger.Errorw("error in getting ci artifact by id", "err", err, "ciArtifactId", pendingArtifact.CiArtifactId)
+			continue
+		}
+		applyAuth := pendingArtifact.TriggeredBy != 1
+		err = impl.triggerStage(nil, pipeline, artifact, applyAuth, false, pendingArtifact.TriggeredBy)
+		if err != nil {
+			impl.logger.Errorw("error in triggering cd pipeline for artifact pending promotion", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+		}
+	}
+	return nil
+}
+
 func (impl *WorkflowDagExecutorImpl) triggerStageForBulk(cdWf *pipelineConfig.CdWorkflow, pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact, applyAuth bool, async bool, triggeredBy int32) error {
 	var err error
 	if len(pipeline.PreStageConfig) > 0 {
@@ -364,6 +458,38 @@ func (impl *WorkflowDagExecutorImpl) TriggerPreStage(cdWf *pipelineConfig.CdWork
 		return err
 	}
 
+	//checking approval for deploying artifact, pre stage runs against the target environment as well
+	err = impl.cdApprovalService.CheckArtifactApproval(pipeline.Id, artifact.Id)
+	if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode == http.StatusForbidden {
+		runner.Status = WorkflowFailed
+		runner.Message = "Artifact not approved for deployment"
+		runner.FinishedOn = time.Now()
+		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+		if err != nil {
+			impl.logger.Errorw("error in updating status", "err", err)
+			return err
+		}
+		return apiErr
+	} else if err != nil {
+		return err
+	}
+
+	//checking promotion policy for deploying artifact
+	err = impl.cdPromotionPolicyService.CheckArtifactPromotion(pipeline.Id, artifact.Id)
+	if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode == http.StatusForbidden {
+		runner.Status = WorkflowFailed
+		runner.Message = fmt.Sprint(apiErr.UserMessage)
+		runner.FinishedOn = time.Now()
+		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+		if err != nil {
+			impl.logger.Errorw("error in updating status", "err", err)
+			return err
+		}
+		return apiErr
+	} else if err != nil {
+		return err
+	}
+
 	cdStageWorkflowRequest, err := impl.buildWFRequest(runner, cdWf, pipeline, triggeredBy)
 	if err != nil {
 		return err
@@ -766,6 +892,61 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 		return err
 	}
 
+	//checking deployment freeze windows
+	windowBlock, err := impl.deploymentWindowService.CheckDeploymentWindow(pipeline, artifact.Id)
+	if err != nil {
+		return err
+	}
+	if windowBlock != nil {
+		runner.Status = WorkflowFailed
+		runner.Message = windowBlock.Message()
+		runner.FinishedOn = time.Now()
+		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+		if err != nil {
+			impl.logger.Errorw("error in updating status", "err", err)
+			return err
+		}
+		return nil
+	}
+
+	//checking approval for deploying artifact
+	err = impl.cdApprovalService.CheckArtifactApproval(pipeline.Id, artifact.Id)
+	if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode == http.StatusForbidden {
+		runner.Status = WorkflowFailed
+		runner.Message = "Artifact not approved for deployment"
+		runner.FinishedOn = time.Now()
+		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+		if err != nil {
+			impl.logger.Errorw("error in updating status", "err", err)
+			return err
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	//checking promotion policy for deploying artifact
+	err = impl.cdPromotionPolicyService.CheckArtifactPromotion(pipeline.Id, artifact.Id)
+	if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode == http.StatusForbidden {
+		runner.Status = WorkflowFailed
+		runner.Message = fmt.Sprint(apiErr.UserMessage)
+		runner.FinishedOn = time.Now()
+		err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+		if err != nil {
+			impl.logger.Errorw("error in updating status", "err", err)
+			return err
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	//checking image signature policies for deploying image
+	blocked, err := impl.verifyImageSignature(runner, pipeline, artifact)
+	if err != nil || blocked {
+		return err
+	}
+
 	//checking vulnerability for deploying image
 	isVulnerable := false
 	if len(artifact.ImageDigest) > 0 {
@@ -814,9 +995,39 @@ func (impl *WorkflowDagExecutorImpl) TriggerDeployment(cdWf *pipelineConfig.CdWo
 		impl.logger.Errorw("error while update previous cd workflow runners", "err", err, "runner", runner, "pipelineId", pipeline.Id)
 		return err
 	}
+	err = impl.canaryAnalysisService.StartAnalysis(pipeline, savedWfr.Id, artifact.Id, "", triggeredBy)
+	if err != nil {
+		impl.logger.Errorw("error in starting canary analysis", "err", err, "pipelineId", pipeline.Id, "wfrId", savedWfr.Id)
+	}
 	return nil
 }
 
+// verifyImageSignature records the image signature verification result of the artifact on the runner, the runner is
+// marked failed when a blocking policy is not satisfied
+func (impl *WorkflowDagExecutorImpl) verifyImageSignature(runner *pipelineConfig.CdWorkflowRunner, pipeline *pipelineConfig.Pipeline, artifact *repository.CiArtifact) (bool, error) {
+	result, err := impl.imageSignatureService.VerifyArtifact(pipeline, artifact)
+	if err != nil {
+		impl.logger.Errorw("error in verifying image signature", "err", err, "pipelineId", pipeline.Id, "ciArtifactId", artifact.Id)
+		return false, err
+	}
+	if result == nil {
+		return false, nil
+	}
+	runner.SignatureVerificationStatus = string(result.Status)
+	runner.SignatureVerificationMessage = result.Message
+	if result.Blocked {
+		runner.Status = WorkflowFailed
+		runner.Message = result.Message
+		runner.FinishedOn = time.Now()
+	}
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+	if err != nil {
+		impl.logger.Errorw("error in updating status", "err", err)
+		return false, err
+	}
+	return result.Blocked, nil
+}
+
 func (impl *WorkflowDagExecutorImpl) updatePreviousDeploymentStatus(currentRunner *pipelineConfig.CdWorkflowRunner, pipelineId int, err error, triggeredAt time.Time) error {
 	if err != nil {
 		impl.logger.Errorw("error in triggering cd WF, setting wf status as fail ", "wfId", currentRunner.Id, "err", err)
@@ -841,6 +1052,7 @@ func (impl *WorkflowDagExecutorImpl) updatePreviousDeploymentStatus(currentRunne
 			impl.logger.Errorw("no previous runner found in updating cd wf runner status,", "err", err, "currentRunner", currentRunner)
 			return nil
 		}
+		var awaitingMergeRunners []*pipelineConfig.CdWorkflowRunner
 		for _, previousRunner := range previousNonTerminalRunners {
 			if previousRunner.Status == string(health.HealthStatusHealthy) ||
 				previousRunner.Status == string(health.HealthStatusDegraded) ||
@@ -851,6 +1063,9 @@ func (impl *WorkflowDagExecutorImpl) updatePreviousDeploymentStatus(currentRunne
 				return nil
 			}
 			impl.logger.Infow("updating cd wf runner status as previous runner status is", "status", previousRunner.Status)
+			if previousRunner.Status == app.WorkflowAwaitingMerge {
+				awaitingMergeRunners = append(awaitingMergeRunners, previousRunner)
+			}
 			previousRunner.FinishedOn = triggeredAt
 			previousRunner.Message = "triggered new deployment"
 			previousRunner.Status = WorkflowAborted
@@ -861,6 +1076,8 @@ func (impl *WorkflowDagExecutorImpl) updatePreviousDeploymentStatus(currentRunne
 			impl.logger.Errorw("error updating cd wf runner status", "err", err, "previousNonTerminalRunners", previousNonTerminalRunners)
 			return err
 		}
+		//pull requests of aborted deployments must not be merged anymore
+		impl.gitOpsPullRequestService.ClosePullRequests(awaitingMergeRunners, currentRunner.TriggeredBy)
 		return nil
 	}
 }
@@ -956,6 +1173,18 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 		if overrideRequest.DeploymentType == models.DEPLOYMENTTYPE_UNKNOWN {
 			overrideRequest.DeploymentType = models.DEPLOYMENTTYPE_DEPLOY
 		}
+		if overrideRequest.DeploymentType == models.DEPLOYMENTTYPE_DEPLOY && !overrideRequest.SkipDeploymentGates {
+			err = impl.cdApprovalService.CheckArtifactApproval(overrideRequest.PipelineId, overrideRequest.CiArtifactId)
+			if err != nil {
+				impl.logger.Errorw("artifact approval check failed", "err", err, "pipelineId", overrideRequest.PipelineId, "ciArtifactId", overrideRequest.CiArtifactId)
+				return 0, err
+			}
+			err = impl.cdPromotionPolicyService.CheckArtifactPromotion(overrideRequest.PipelineId, overrideRequest.CiArtifactId)
+			if err != nil {
+				impl.logger.Errorw("artifact promotion check failed", "err", err, "pipelineId", overrideRequest.PipelineId, "ciArtifactId", overrideRequest.CiArtifactId)
+				return 0, err
+			}
+		}
 		cdWf, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(overrideRequest.CdWorkflowId, bean.CD_WORKFLOW_TYPE_PRE)
 		if err != nil && !util.IsErrNoRows(err) {
 			impl.logger.Errorw("err", "err", err)
@@ -994,14 +1223,60 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 		}
 		overrideRequest.CdWorkflowId = cdWorkflowId
 
-		//checking vulnerability for deploying image
+		//checking deployment freeze windows
+		if overrideRequest.OverrideDeploymentWindow && !overrideRequest.SkipDeploymentGates {
+			err = impl.deploymentWindowService.OverrideDeploymentWindow(cdPipeline, overrideRequest.CiArtifactId, overrideRequest.UserId, overrideRequest.OverrideReason)
+			if err != nil {
+				impl.logger.Errorw("error in overriding deployment window", "err", err, "pipelineId", cdPipeline.Id)
+				err1 := impl.updatePreviousDeploymentStatus(runner, cdPipeline.Id, err, triggeredAt)
+				if err1 != nil {
+					impl.logger.Errorw("error while update previous cd workflow runners", "err", err1, "runner", runner, "pipelineId", cdPipeline.Id)
+				}
+				return 0, err
+			}
+		}
+		var windowBlock *deploymentWindow.DeploymentWindowBlock
+		if !overrideRequest.SkipDeploymentGates {
+			windowBlock, err = impl.deploymentWindowService.CheckDeploymentWindow(cdPipeline, overrideRequest.CiArtifactId)
+			if err != nil {
+				impl.logger.Errorw("error in checking deployment window", "err", err, "pipelineId", cdPipeline.Id)
+				return 0, err
+			}
+		}
+		if windowBlock != nil {
+			runner.Status = WorkflowFailed
+			runner.Message = windowBlock.Message()
+			runner.FinishedOn = triggeredAt
+			err = impl.cdWorkflowRepository.UpdateWorkFlowRunner(runner)
+			if err != nil {
+				impl.logger.Errorw("error in updating status", "err", err)
+				return 0, err
+			}
+			return 0, &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: windowBlock.Message(), UserMessage: windowBlock.Message()}
+		}
+
 		artifact, err := impl.ciArtifactRepository.Get(overrideRequest.CiArtifactId)
 		if err != nil {
 			impl.logger.Errorw("err", "err", err)
 			return 0, err
 		}
+
+		//checking image signature policies for deploying image
+		blocked := false
+		if !overrideRequest.SkipDeploymentGates {
+			blocked, err = impl.verifyImageSignature(runner, cdPipeline, artifact)
+			if err != nil {
+				impl.logger.Errorw("error in verifying image signature", "err", err, "pipelineId", cdPipeline.Id)
+				return 0, err
+			}
+		}
+		if blocked {
+			return 0, &util.ApiError{HttpStatusCode: http.StatusForbidden, InternalMessage: runner.Message, UserMessage: runner.Message}
+		}
+
+		//checking vulnerability for deploying image
 		isVulnerable := false
-		if len(artifact.ImageDigest) > 0 {
+		if len(artifact.ImageDigest) > 0 && !overrideRequest.SkipDeploymentGates {
 			var cveStores []*security.CveStore
 			imageScanResult, err := impl.scanResultRepository.FindByImageDigest(artifact.ImageDigest)
 			if err != nil && err != pg.ErrNoRows {
@@ -1050,6 +1325,12 @@ func (impl *WorkflowDagExecutorImpl) ManualCdTrigger(overrideRequest *bean.Value
 			impl.logger.Errorw("error while update previous cd workflow runners", "err", err, "runner", runner, "pipelineId", cdPipeline.Id)
 			return 0, err
 		}
+		if overrideRequest.DeploymentType == models.DEPLOYMENTTYPE_DEPLOY && !overrideRequest.SkipCanaryAnalysis {
+			err = impl.canaryAnalysisService.StartAnalysis(cdPipeline, savedWfr.Id, overrideRequest.CiArtifactId, overrideRequest.DeploymentTemplate, overrideRequest.UserId)
+			if err != nil {
+				impl.logger.Errorw("error in starting canary analysis", "err", err, "pipelineId", cdPipeline.Id, "wfrId", savedWfr.Id)
+			}
+		}
 	} else if overrideRequest.CdWorkflowType == bean.CD_WORKFLOW_TYPE_POST {
 		cdWfRunner, err := impl.cdWorkflowRepository.FindByWorkflowIdAndRunnerType(overrideRequest.CdWorkflowId, bean.CD_WORKFLOW_TYPE_DEPLOY)
 		if err != nil && !util.IsErrNoRows(err) {
@@ -1086,8 +1367,17 @@ type BulkTriggerRequest struct {
 	PipelineId   int `sql:"pipeline_id"`
 }
 
-func (impl *WorkflowDagExecutorImpl) TriggerBulkDeploymentAsync(requests []*BulkTriggerRequest, UserId int32) (interface{}, error) {
+// BulkTriggerResponse tells whether deployment of a pipeline was queued, Message has the reason when it was blocked
+type BulkTriggerResponse struct {
+	PipelineId   int    `json:"pipelineId"`
+	CiArtifactId int    `json:"ciArtifactId"`
+	Queued       bool   `json:"queued"`
+	Message      string `json:"message,omitempty"`
+}
+
+func (impl *WorkflowDagExecutorImpl) TriggerBulkDeploymentAsync(requests []*BulkTriggerRequest, UserId int32) ([]*BulkTriggerResponse, error) {
 	var cdWorkflows []*pipelineConfig.CdWorkflow
+	responses := make([]*BulkTriggerResponse, 0, len(requests))
 	for _, request := range requests {
 		cdWf := &pipelineConfig.CdWorkflow{
 			CiArtifactId:   request.CiArtifactId,
@@ -1095,21 +1385,71 @@ func (impl *WorkflowDagExecutorImpl) TriggerBulkDeploymentAsync(requests []*Bulk
 			AuditLog:       sql.AuditLog{CreatedOn: time.Now(), CreatedBy: UserId, UpdatedOn: time.Now(), UpdatedBy: UserId},
 			WorkflowStatus: pipelineConfig.REQUEST_ACCEPTED,
 		}
+		windowBlock, err := impl.blockBulkTriggerIfInDeploymentWindow(cdWf, UserId)
+		if err != nil {
+			impl.logger.Errorw("error in checking deployment window", "req", request, "err", err)
+			return nil, err
+		}
+		response := &BulkTriggerResponse{PipelineId: request.PipelineId, CiArtifactId: request.CiArtifactId}
+		responses = append(responses, response)
+		if windowBlock != nil {
+			response.Message = windowBlock.Message()
+			continue
+		}
+		response.Queued = true
 		cdWorkflows = append(cdWorkflows, cdWf)
 	}
+	if len(cdWorkflows) == 0 {
+		return responses, nil
+	}
 	err := impl.cdWorkflowRepository.SaveWorkFlows(cdWorkflows...)
 	if err != nil {
 		impl.logger.Errorw("error in saving wfs", "req", requests, "err", err)
 		return nil, err
 	}
 	impl.triggerNatsEventForBulkAction(cdWorkflows)
-	return nil, nil
+	return responses, nil
 	//return
 	//publish nats async
 	//update status
 	//consume message
 }
 
+// blockBulkTriggerIfInDeploymentWindow saves the workflow with a failed deploy runner carrying the freeze reason
+// when the pipeline is frozen, such workflows are not queued for trigger
+func (impl *WorkflowDagExecutorImpl) blockBulkTriggerIfInDeploymentWindow(cdWf *pipelineConfig.CdWorkflow, userId int32) (*deploymentWindow.DeploymentWindowBlock, error) {
+	pipeline, err := impl.pipelineRepository.FindById(cdWf.PipelineId)
+	if err != nil {
+		return nil, err
+	}
+	windowBlock, err := impl.deploymentWindowService.CheckDeploymentWindow(pipeline, cdWf.CiArtifactId)
+	if err != nil || windowBlock == nil {
+		return nil, err
+	}
+	cdWf.WorkflowStatus = pipelineConfig.TRIGGER_ERROR
+	err = impl.cdWorkflowRepository.SaveWorkFlow(cdWf)
+	if err != nil {
+		return nil, err
+	}
+	runner := &pipelineConfig.CdWorkflowRunner{
+		Name:         pipeline.Name,
+		WorkflowType: bean.CD_WORKFLOW_TYPE_DEPLOY,
+		ExecutorType: pipelineConfig.WORKFLOW_EXECUTOR_TYPE_SYSTEM,
+		Status:       WorkflowFailed,
+		TriggeredBy:  userId,
+		StartedOn:    time.Now(),
+		FinishedOn:   time.Now(),
+		Namespace:    impl.cdConfig.DefaultNamespace,
+		CdWorkflowId: cdWf.Id,
+		Message:      windowBlock.Message(),
+	}
+	_, err = impl.cdWorkflowRepository.SaveWorkFlowRunner(runner)
+	if err != nil {
+		return nil, err
+	}
+	return windowBlock, nil
+}
+
 type DeploymentGroupAppWithEnv struct {
 	EnvironmentId     int         `json:"environmentId"`
 	DeploymentGroupId int         `json:"deploymentGroupId"`
@@ -1281,3 +1621,38 @@ func (impl *WorkflowDagExecutorImpl) buildACDContext() (acdContext context.Conte
 	ctx = context.WithValue(ctx, "token", acdToken)
 	return ctx, nil
 }
+
+// RollbackCanaryDeployment redeploys the last healthy artifact of the pipeline after a failed canary analysis
+func (impl *WorkflowDagExecutorImpl) RollbackCanaryDeployment(run *pipelineConfig.CanaryAnalysisRun) error {
+	pipeline, err := impl.pipelineRepository.FindById(run.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline", "err", err, "pipelineId", run.PipelineId)
+		return err
+	}
+	lastHealthyRunner, err := impl.cdWorkflowRepository.FindLastDeployRunnerByPipelineIdAndStatusExcludingArtifact(run.PipelineId, string(health.HealthStatusHealthy), run.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching last healthy deployment", "err", err, "pipelineId", run.PipelineId)
+		return err
+	}
+	ctx, err := impl.buildACDContext()
+	if err != nil {
+		return err
+	}
+	overrideRequest := &bean.ValuesOverrideRequest{
+		PipelineId:     run.PipelineId,
+		AppId:          pipeline.AppId,
+		CiArtifactId:   lastHealthyRunner.CdWorkflow.CiArtifactId,
+		CdWorkflowType: bean.CD_WORKFLOW_TYPE_DEPLOY,
+		DeploymentType: models.DEPLOYMENTTYPE_DEPLOY,
+		// rollback is attributed to the user who triggered the canary deployment
+		UserId:              run.CreatedBy,
+		SkipCanaryAnalysis:  true,
+		SkipDeploymentGates: true,
+	}
+	_, err = impl.ManualCdTrigger(overrideRequest, ctx)
+	if err != nil {
+		impl.logger.Errorw("error in rolling back canary deployment", "err", err, "runId", run.Id, "ciArtifactId", overrideRequest.CiArtifactId)
+		return err
+	}
+	return nil
+}
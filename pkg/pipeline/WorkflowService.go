@@ -94,6 +94,7 @@ type WorkflowRequest struct {
 	CiArtifactLocation         string                   `json:"ciArtifactLocation"`
 	InvalidateCache            bool                     `json:"invalidateCache"`
 	ScanEnabled                bool                     `json:"scanEnabled"`
+	ImageSigningEnabled        bool                     `json:"imageSigningEnabled"`
 	CloudProvider              string                   `json:"cloudProvider"`
 	AzureBlobConfig            *AzureBlobConfig         `json:"azureBlobConfig"`
 	MinioEndpoint              string                   `json:"minioEndpoint"`
@@ -183,6 +184,10 @@ func (impl *WorkflowServiceImpl) SubmitWorkflow(workflowRequest *WorkflowRequest
 		miniCred := []v12.EnvVar{{Name: "AWS_ACCESS_KEY_ID", Value: impl.ciConfig.MinioAccessKey}, {Name: "AWS_SECRET_ACCESS_KEY", Value: impl.ciConfig.MinioSecretKey}}
 		containerEnvVariables = append(containerEnvVariables, miniCred...)
 	}
+	if workflowRequest.ImageSigningEnabled {
+		// only the kms reference is passed, the ci runner signs the pushed image through the kms with the workflow service account
+		containerEnvVariables = append(containerEnvVariables, v12.EnvVar{Name: "COSIGN_KEY_REF", Value: impl.ciConfig.ImageSigningKeyRef})
+	}
 
 	ciCdTriggerEvent := CiCdTriggerEvent{
 		Type:      ciEvent,
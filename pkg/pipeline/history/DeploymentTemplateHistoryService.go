@@ -22,6 +22,7 @@ type DeploymentTemplateHistoryService interface {
 	GetHistoryForDeployedTemplateById(id, pipelineId int) (*HistoryDetailDto, error)
 	CheckIfHistoryExistsForPipelineIdAndWfrId(pipelineId, wfrId int) (historyId int, exists bool, err error)
 	GetDeployedHistoryList(pipelineId, baseConfigId int) ([]*DeployedHistoryComponentMetadataDto, error)
+	GetLatestDeployedHistory(pipelineId int) (*DeploymentTemplateHistoryDto, error)
 }
 
 type DeploymentTemplateHistoryServiceImpl struct {
@@ -337,3 +338,25 @@ func (impl DeploymentTemplateHistoryServiceImpl) GetHistoryForDeployedTemplateBy
 	}
 	return historyDto, nil
 }
+
+func (impl DeploymentTemplateHistoryServiceImpl) GetLatestDeployedHistory(pipelineId int) (*DeploymentTemplateHistoryDto, error) {
+	history, err := impl.deploymentTemplateHistoryRepository.GetLatestDeployedHistory(pipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in getting latest deployed template history", "err", err, "pipelineId", pipelineId)
+		return nil, err
+	}
+	historyDto := &DeploymentTemplateHistoryDto{
+		Id:                  history.Id,
+		PipelineId:          history.PipelineId,
+		AppId:               history.AppId,
+		Template:            history.Template,
+		TemplateName:        history.TemplateName,
+		TemplateVersion:     history.TemplateVersion,
+		IsAppMetricsEnabled: history.IsAppMetricsEnabled,
+		TargetEnvironment:   history.TargetEnvironment,
+		Deployed:            history.Deployed,
+		DeployedOn:          history.DeployedOn,
+		DeployedBy:          history.DeployedBy,
+	}
+	return historyDto, nil
+}
@@ -14,6 +14,7 @@ type DeploymentTemplateHistoryRepository interface {
 	GetDeploymentDetailsForDeployedTemplateHistory(pipelineId, offset, limit int) ([]*DeploymentTemplateHistory, error)
 	GetHistoryByPipelineIdAndWfrId(pipelineId, wfrId int) (*DeploymentTemplateHistory, error)
 	GetDeployedHistoryList(pipelineId, baseConfigId int) ([]*DeploymentTemplateHistory, error)
+	GetLatestDeployedHistory(pipelineId int) (*DeploymentTemplateHistory, error)
 }
 
 type DeploymentTemplateHistoryRepositoryImpl struct {
@@ -116,3 +117,15 @@ func (impl DeploymentTemplateHistoryRepositoryImpl) GetDeployedHistoryList(pipel
 	}
 	return histories, nil
 }
+
+func (impl DeploymentTemplateHistoryRepositoryImpl) GetLatestDeployedHistory(pipelineId int) (*DeploymentTemplateHistory, error) {
+	var history DeploymentTemplateHistory
+	err := impl.dbConnection.Model(&history).Where("pipeline_id = ?", pipelineId).
+		Where("deployed = ?", true).
+		Order("id desc").Limit(1).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting latest deployed template history", "err", err, "pipelineId", pipelineId)
+		return &history, err
+	}
+	return &history, nil
+}
@@ -0,0 +1,689 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/user"
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
+	"github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+const (
+	SCIM_TYPE_UNIQUENESS     = "uniqueness"
+	SCIM_TYPE_MUTABILITY     = "mutability"
+	SCIM_TYPE_INVALID_FILTER = "invalidFilter"
+	SCIM_TYPE_INVALID_VALUE  = "invalidValue"
+	SCIM_TYPE_INVALID_SYNTAX = "invalidSyntax"
+
+	scimGroupDescription = "Provisioned by SCIM"
+)
+
+// RequestContext is the api-token making the scim request, changes are made with its permissions
+type RequestContext struct {
+	UserId      int32
+	Token       string
+	ManagerAuth func(token string, object string) bool
+}
+
+type ScimService interface {
+	ListUsers(filter string, startIndex int, count int) (*ListResponse, error)
+	GetUser(id int32) (*User, error)
+	// CreateUser creates the user without roles, a deactivated user with same userName is activated again
+	CreateUser(request *User, context *RequestContext) (*User, error)
+	ReplaceUser(id int32, request *User, context *RequestContext) (*User, error)
+	PatchUser(id int32, request *PatchRequest, context *RequestContext) (*User, error)
+	// DeleteUser deactivates the user, devtron does not delete users
+	DeleteUser(id int32, context *RequestContext) error
+
+	// ListGroups lists the groups provisioned through scim, groups created in devtron are not managed by scim and are
+	// not found through any of the group operations
+	ListGroups(filter string, startIndex int, count int) (*ListResponse, error)
+	GetGroup(id int32) (*Group, error)
+	// CreateGroup creates a role group without roles, roles of the group are managed in devtron
+	CreateGroup(request *Group, context *RequestContext) (*Group, error)
+	ReplaceGroup(id int32, request *Group, context *RequestContext) (*Group, error)
+	PatchGroup(id int32, request *PatchRequest, context *RequestContext) (*Group, error)
+	DeleteGroup(id int32, context *RequestContext) error
+}
+
+type ScimServiceImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	roleGroupService    user.RoleGroupService
+	userRepository      repository.UserRepository
+	roleGroupRepository repository.RoleGroupRepository
+}
+
+func NewScimServiceImpl(logger *zap.SugaredLogger, userService user.UserService, roleGroupService user.RoleGroupService,
+	userRepository repository.UserRepository, roleGroupRepository repository.RoleGroupRepository) *ScimServiceImpl {
+	return &ScimServiceImpl{
+		logger:              logger,
+		userService:         userService,
+		roleGroupService:    roleGroupService,
+		userRepository:      userRepository,
+		roleGroupRepository: roleGroupRepository,
+	}
+}
+
+func (impl ScimServiceImpl) ListUsers(filter string, startIndex int, count int) (*ListResponse, error) {
+	var models []repository.UserModel
+	if len(filter) > 0 {
+		attribute, value, err := parseEqFilter(filter)
+		if err != nil {
+			return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_FILTER, err.Error())
+		}
+		if !strings.EqualFold(attribute, "userName") && !strings.EqualFold(attribute, "emails.value") {
+			return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_FILTER, fmt.Sprintf("filter on '%s' is not supported", attribute))
+		}
+		model, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(value)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while fetching user by email", "email", value, "err", err)
+			return nil, err
+		}
+		if err == nil && model.UserType != bean.USER_TYPE_API_TOKEN {
+			models = append(models, *model)
+		}
+	} else {
+		var err error
+		models, err = impl.userRepository.GetAllExcludingApiTokenUser()
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while fetching users", "err", err)
+			return nil, err
+		}
+	}
+
+	start, end := getPage(len(models), startIndex, count)
+	response := newListResponse(len(models), start)
+	for i := start; i < end; i++ {
+		scimUser, err := impl.buildUser(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		response.Resources = append(response.Resources, scimUser)
+	}
+	response.ItemsPerPage = len(response.Resources)
+	return response, nil
+}
+
+func (impl ScimServiceImpl) GetUser(id int32) (*User, error) {
+	model, err := impl.getUserModel(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildUser(model)
+}
+
+func (impl ScimServiceImpl) CreateUser(request *User, context *RequestContext) (*User, error) {
+	emailId := strings.TrimSpace(request.UserName)
+	if len(emailId) == 0 || strings.Contains(emailId, ",") {
+		return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, fmt.Sprintf("userName '%s' is not valid", request.UserName))
+	}
+	existingUser, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching user by email", "email", emailId, "err", err)
+		return nil, err
+	}
+	if err == nil && (existingUser.Active || existingUser.UserType == bean.USER_TYPE_API_TOKEN) {
+		return nil, newScimError(http.StatusConflict, SCIM_TYPE_UNIQUENESS, fmt.Sprintf("user '%s' already exists", emailId))
+	}
+
+	userInfo := &bean.UserInfo{
+		EmailId:     emailId,
+		UserId:      context.UserId,
+		RoleFilters: make([]bean.RoleFilter, 0),
+		Groups:      make([]string, 0),
+	}
+	_, err = impl.userService.CreateUser(userInfo, context.Token, context.ManagerAuth)
+	if err != nil {
+		impl.logger.Errorw("error while creating user", "email", emailId, "err", err)
+		return nil, err
+	}
+	model, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+	if err != nil {
+		impl.logger.Errorw("error while fetching created user", "email", emailId, "err", err)
+		return nil, err
+	}
+	if request.Active != nil {
+		err = impl.setUserActive(model, *request.Active, context)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.buildUser(model)
+}
+
+func (impl ScimServiceImpl) ReplaceUser(id int32, request *User, context *RequestContext) (*User, error) {
+	model, err := impl.getUserModel(id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(request.UserName), model.EmailId) {
+		return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_MUTABILITY, "userName can not be changed")
+	}
+	if request.Active != nil {
+		err = impl.setUserActive(model, *request.Active, context)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.buildUser(model)
+}
+
+func (impl ScimServiceImpl) PatchUser(id int32, request *PatchRequest, context *RequestContext) (*User, error) {
+	model, err := impl.getUserModel(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != PATCH_OP_ADD && op != PATCH_OP_REPLACE && op != PATCH_OP_REMOVE {
+			return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_SYNTAX, fmt.Sprintf("patch operation '%s' is not supported", operation.Op))
+		}
+		if op == PATCH_OP_REMOVE {
+			// attributes other than active are not stored in devtron, and active can not be removed
+			continue
+		}
+		values := make(map[string]interface{})
+		if len(operation.Path) > 0 {
+			values[operation.Path] = operation.Value
+		} else if fields, ok := operation.Value.(map[string]interface{}); ok {
+			values = fields
+		}
+		for attribute, value := range values {
+			if !strings.EqualFold(attribute, "active") {
+				continue
+			}
+			active, err := parseBool(value)
+			if err != nil {
+				return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, err.Error())
+			}
+			err = impl.setUserActive(model, active, context)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return impl.buildUser(model)
+}
+
+func (impl ScimServiceImpl) DeleteUser(id int32, context *RequestContext) error {
+	model, err := impl.getUserModel(id)
+	if err != nil {
+		return err
+	}
+	return impl.setUserActive(model, false, context)
+}
+
+func (impl ScimServiceImpl) ListGroups(filter string, startIndex int, count int) (*ListResponse, error) {
+	var roleGroups []*repository.RoleGroup
+	if len(filter) > 0 {
+		attribute, value, err := parseEqFilter(filter)
+		if err != nil {
+			return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_FILTER, err.Error())
+		}
+		if !strings.EqualFold(attribute, "displayName") {
+			return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_FILTER, fmt.Sprintf("filter on '%s' is not supported", attribute))
+		}
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupByName(value)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while fetching role group by name", "name", value, "err", err)
+			return nil, err
+		}
+		if err == nil && roleGroup.ScimProvisioned {
+			roleGroups = append(roleGroups, roleGroup)
+		}
+	} else {
+		allRoleGroups, err := impl.roleGroupRepository.GetAllRoleGroup()
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while fetching role groups", "err", err)
+			return nil, err
+		}
+		for _, roleGroup := range allRoleGroups {
+			if roleGroup.ScimProvisioned {
+				roleGroups = append(roleGroups, roleGroup)
+			}
+		}
+	}
+
+	start, end := getPage(len(roleGroups), startIndex, count)
+	response := newListResponse(len(roleGroups), start)
+	for i := start; i < end; i++ {
+		group, err := impl.buildGroup(roleGroups[i])
+		if err != nil {
+			return nil, err
+		}
+		response.Resources = append(response.Resources, group)
+	}
+	response.ItemsPerPage = len(response.Resources)
+	return response, nil
+}
+
+func (impl ScimServiceImpl) GetGroup(id int32) (*Group, error) {
+	roleGroup, err := impl.getRoleGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildGroup(roleGroup)
+}
+
+func (impl ScimServiceImpl) CreateGroup(request *Group, context *RequestContext) (*Group, error) {
+	name := strings.TrimSpace(request.DisplayName)
+	if len(name) == 0 {
+		return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, "displayName is required")
+	}
+	_, err := impl.roleGroupRepository.GetRoleGroupByName(name)
+	if err == nil {
+		return nil, newScimError(http.StatusConflict, SCIM_TYPE_UNIQUENESS, fmt.Sprintf("group '%s' already exists", name))
+	} else if err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role group by name", "name", name, "err", err)
+		return nil, err
+	}
+	memberIds, err := getMemberIds(request.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	roleGroupRequest := &bean.RoleGroup{
+		Name:            name,
+		Description:     scimGroupDescription,
+		RoleFilters:     make([]bean.RoleFilter, 0),
+		UserId:          context.UserId,
+		ScimProvisioned: true,
+	}
+	roleGroupRequest, err = impl.roleGroupService.CreateRoleGroup(roleGroupRequest)
+	if err != nil {
+		impl.logger.Errorw("error while creating role group", "name", name, "err", err)
+		return nil, err
+	}
+	roleGroup, err := impl.getRoleGroup(roleGroupRequest.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setGroupMembers(roleGroup, memberIds, nil, context)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildGroup(roleGroup)
+}
+
+func (impl ScimServiceImpl) ReplaceGroup(id int32, request *Group, context *RequestContext) (*Group, error) {
+	roleGroup, err := impl.getRoleGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(request.DisplayName) != roleGroup.Name {
+		return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_MUTABILITY, "displayName can not be changed")
+	}
+	memberIds, err := getMemberIds(request.Members)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.replaceGroupMembers(roleGroup, memberIds, context)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildGroup(roleGroup)
+}
+
+func (impl ScimServiceImpl) PatchGroup(id int32, request *PatchRequest, context *RequestContext) (*Group, error) {
+	roleGroup, err := impl.getRoleGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != PATCH_OP_ADD && op != PATCH_OP_REPLACE && op != PATCH_OP_REMOVE {
+			return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_SYNTAX, fmt.Sprintf("patch operation '%s' is not supported", operation.Op))
+		}
+		if len(operation.Path) == 0 {
+			fields, ok := operation.Value.(map[string]interface{})
+			if !ok {
+				return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, "patch value without path must be an object")
+			}
+			for attribute, value := range fields {
+				err = impl.patchGroupAttribute(roleGroup, op, attribute, value, context)
+				if err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		err = impl.patchGroupAttribute(roleGroup, op, operation.Path, operation.Value, context)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.buildGroup(roleGroup)
+}
+
+func (impl ScimServiceImpl) DeleteGroup(id int32, context *RequestContext) error {
+	roleGroup, err := impl.getRoleGroup(id)
+	if err != nil {
+		return err
+	}
+	// members are removed first as deleting a role group keeps the group policies of its members
+	err = impl.replaceGroupMembers(roleGroup, nil, context)
+	if err != nil {
+		return err
+	}
+	_, err = impl.roleGroupService.DeleteRoleGroup(&bean.RoleGroup{Id: roleGroup.Id, UserId: context.UserId})
+	if err != nil {
+		impl.logger.Errorw("error while deleting role group", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl ScimServiceImpl) patchGroupAttribute(roleGroup *repository.RoleGroup, op string, path string, value interface{}, context *RequestContext) error {
+	if strings.EqualFold(path, "displayName") {
+		if name, ok := value.(string); !ok || strings.TrimSpace(name) != roleGroup.Name {
+			return newScimError(http.StatusBadRequest, SCIM_TYPE_MUTABILITY, "displayName can not be changed")
+		}
+		return nil
+	}
+	selectedMemberId, ok := parseMemberPath(path)
+	if !ok {
+		// attributes other than members are not stored in devtron
+		return nil
+	}
+	var memberIds []int32
+	var err error
+	if len(selectedMemberId) > 0 {
+		id, err := parseId(selectedMemberId)
+		if err != nil {
+			return newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, err.Error())
+		}
+		memberIds = []int32{id}
+	} else if value != nil {
+		memberIds, err = parseMemberIds(value)
+		if err != nil {
+			return newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, err.Error())
+		}
+	}
+	switch op {
+	case PATCH_OP_ADD:
+		return impl.setGroupMembers(roleGroup, memberIds, nil, context)
+	case PATCH_OP_REPLACE:
+		return impl.replaceGroupMembers(roleGroup, memberIds, context)
+	default:
+		if len(selectedMemberId) == 0 && value == nil {
+			return impl.replaceGroupMembers(roleGroup, nil, context)
+		}
+		return impl.setGroupMembers(roleGroup, nil, memberIds, context)
+	}
+}
+
+// replaceGroupMembers makes the users of memberIds the only members of role group
+func (impl ScimServiceImpl) replaceGroupMembers(roleGroup *repository.RoleGroup, memberIds []int32, context *RequestContext) error {
+	members, err := impl.getGroupMembers(roleGroup)
+	if err != nil {
+		return err
+	}
+	desired := make(map[int32]bool)
+	for _, id := range memberIds {
+		desired[id] = true
+	}
+	var removedIds []int32
+	for _, member := range members {
+		if !desired[member.Id] {
+			removedIds = append(removedIds, member.Id)
+		}
+	}
+	return impl.setGroupMembers(roleGroup, memberIds, removedIds, context)
+}
+
+// setGroupMembers adds and removes the users from role group through user update, so that user policies are
+// updated the same way as from devtron
+func (impl ScimServiceImpl) setGroupMembers(roleGroup *repository.RoleGroup, addedIds []int32, removedIds []int32, context *RequestContext) error {
+	changes := make(map[int32]bool)
+	for _, id := range addedIds {
+		changes[id] = true
+	}
+	for _, id := range removedIds {
+		changes[id] = false
+	}
+	for userId, isMember := range changes {
+		userInfo, err := impl.userService.GetById(userId)
+		if err != nil {
+			impl.logger.Errorw("error while fetching member of group", "userId", userId, "err", err)
+			if err == pg.ErrNoRows {
+				return newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, fmt.Sprintf("member '%d' is not an active user", userId))
+			}
+			return err
+		}
+		var groups []string
+		for _, group := range userInfo.Groups {
+			if group != roleGroup.Name {
+				groups = append(groups, group)
+			}
+		}
+		if isMember {
+			groups = append(groups, roleGroup.Name)
+		}
+		if len(groups) == len(userInfo.Groups) {
+			// membership is unchanged
+			continue
+		}
+		if userInfo.SuperAdmin {
+			return newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, fmt.Sprintf("group membership of super-admin '%s' can not be changed", userInfo.EmailId))
+		}
+		userInfo.Groups = groups
+		userInfo.UserId = context.UserId
+		_, err = impl.userService.UpdateUser(userInfo, context.Token, context.ManagerAuth)
+		if err != nil {
+			impl.logger.Errorw("error while updating group membership of user", "userId", userId, "group", roleGroup.Name, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// setUserActive deactivates the user through user delete, which removes all roles of the user. A deactivated user
+// has no roles, so it is activated again without roles by only marking it active
+func (impl ScimServiceImpl) setUserActive(model *repository.UserModel, active bool, context *RequestContext) error {
+	if model.Active == active {
+		return nil
+	}
+	var err error
+	if active {
+		err = impl.activateUser(model, context)
+	} else {
+		_, err = impl.userService.DeleteUser(&bean.UserInfo{Id: model.Id, UserId: context.UserId})
+	}
+	if err != nil {
+		impl.logger.Errorw("error while changing active state of user", "userId", model.Id, "active", active, "err", err)
+		return err
+	}
+	model.Active = active
+	return nil
+}
+
+func (impl ScimServiceImpl) activateUser(model *repository.UserModel, context *RequestContext) error {
+	tx, err := impl.userRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	activeModel := *model
+	activeModel.Active = true
+	activeModel.UpdatedOn = time.Now()
+	activeModel.UpdatedBy = context.UserId
+	_, err = impl.userRepository.UpdateUser(&activeModel, tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl ScimServiceImpl) getUserModel(id int32) (*repository.UserModel, error) {
+	model, err := impl.userRepository.GetByIdIncludeDeleted(id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching user", "id", id, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows || model.UserType == bean.USER_TYPE_API_TOKEN {
+		return nil, newScimError(http.StatusNotFound, "", fmt.Sprintf("user '%d' not found", id))
+	}
+	return model, nil
+}
+
+// getRoleGroup returns the role group if it is provisioned through scim
+func (impl ScimServiceImpl) getRoleGroup(id int32) (*repository.RoleGroup, error) {
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role group", "id", id, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows || !roleGroup.ScimProvisioned {
+		return nil, newScimError(http.StatusNotFound, "", fmt.Sprintf("group '%d' not found", id))
+	}
+	return roleGroup, nil
+}
+
+func (impl ScimServiceImpl) buildUser(model *repository.UserModel) (*User, error) {
+	active := model.Active
+	scimUser := &User{
+		Schemas:  []string{SCHEMA_USER},
+		Id:       strconv.Itoa(int(model.Id)),
+		UserName: model.EmailId,
+		Emails:   []*MultiValue{{Value: model.EmailId, Primary: true, Type: "work"}},
+		Active:   &active,
+		Meta:     &Meta{ResourceType: RESOURCE_TYPE_USER},
+	}
+	if !active {
+		return scimUser, nil
+	}
+	roles, err := impl.userService.CheckUserRoles(model.Id)
+	if err != nil {
+		return nil, err
+	}
+	var casbinNames []string
+	for _, role := range roles {
+		if strings.HasPrefix(role, "group:") {
+			casbinNames = append(casbinNames, role)
+		}
+	}
+	if len(casbinNames) == 0 {
+		return scimUser, nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByCasbinNames(casbinNames)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role groups of user", "userId", model.Id, "err", err)
+		return nil, err
+	}
+	for _, roleGroup := range roleGroups {
+		if roleGroup.ScimProvisioned {
+			scimUser.Groups = append(scimUser.Groups, &MultiValue{Value: strconv.Itoa(int(roleGroup.Id)), Display: roleGroup.Name})
+		}
+	}
+	return scimUser, nil
+}
+
+func (impl ScimServiceImpl) buildGroup(roleGroup *repository.RoleGroup) (*Group, error) {
+	members, err := impl.getGroupMembers(roleGroup)
+	if err != nil {
+		return nil, err
+	}
+	group := &Group{
+		Schemas:     []string{SCHEMA_GROUP},
+		Id:          strconv.Itoa(int(roleGroup.Id)),
+		DisplayName: roleGroup.Name,
+		Members:     make([]*MultiValue, 0),
+		Meta:        &Meta{ResourceType: RESOURCE_TYPE_GROUP},
+	}
+	for _, member := range members {
+		group.Members = append(group.Members, &MultiValue{Value: strconv.Itoa(int(member.Id)), Display: member.EmailId})
+	}
+	return group, nil
+}
+
+// getGroupMembers returns the active users having the role group
+func (impl ScimServiceImpl) getGroupMembers(roleGroup *repository.RoleGroup) ([]bean.UserInfo, error) {
+	emailIds, err := casbin2.GetUserByRole(roleGroup.CasbinName)
+	if err != nil {
+		impl.logger.Errorw("error while fetching members of role group", "roleGroup", roleGroup.Name, "err", err)
+		return nil, err
+	}
+	var members []bean.UserInfo
+	for _, emailId := range emailIds {
+		member, err := impl.userRepository.FetchActiveUserByEmail(emailId)
+		if err != nil {
+			impl.logger.Errorw("error while fetching member of role group", "emailId", emailId, "err", err)
+			return nil, err
+		}
+		if member.Id == 0 || member.UserType == bean.USER_TYPE_API_TOKEN {
+			continue
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func getMemberIds(members []*MultiValue) ([]int32, error) {
+	var ids []int32
+	for _, member := range members {
+		id, err := parseId(member.Value)
+		if err != nil {
+			return nil, newScimError(http.StatusBadRequest, SCIM_TYPE_INVALID_VALUE, err.Error())
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// getPage returns the bounds of the page of resources, startIndex of scim is 1 based
+func getPage(total int, startIndex int, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count <= 0 {
+		count = DEFAULT_PAGE_SIZE
+	} else if count > MAX_PAGE_SIZE {
+		count = MAX_PAGE_SIZE
+	}
+	start := startIndex - 1
+	if start > total {
+		start = total
+	}
+	end := start + count
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func newListResponse(total int, start int) *ListResponse {
+	return &ListResponse{
+		Schemas:      []string{SCHEMA_LIST_RESPONSE},
+		TotalResults: total,
+		StartIndex:   start + 1,
+		Resources:    make([]interface{}, 0),
+	}
+}
+
+// IsScimType tells whether code of an api error is a scim error type
+func IsScimType(code string) bool {
+	switch code {
+	case SCIM_TYPE_UNIQUENESS, SCIM_TYPE_MUTABILITY, SCIM_TYPE_INVALID_FILTER, SCIM_TYPE_INVALID_VALUE, SCIM_TYPE_INVALID_SYNTAX:
+		return true
+	}
+	return false
+}
+
+func newScimError(status int, scimType string, detail string) *util.ApiError {
+	return &util.ApiError{
+		HttpStatusCode:  status,
+		Code:            scimType,
+		InternalMessage: detail,
+		UserMessage:     detail,
+	}
+}
@@ -0,0 +1,246 @@
+package scim
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/user"
+	"github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users   map[int32]*repository.UserModel
+	updated []*repository.UserModel
+}
+
+func (f *fakeUserRepository) GetByIdIncludeDeleted(id int32) (*repository.UserModel, error) {
+	model, ok := f.users[id]
+	if !ok {
+		return nil, pg.ErrNoRows
+	}
+	return model, nil
+}
+
+func (f *fakeUserRepository) FetchActiveOrDeletedUserByEmail(email string) (*repository.UserModel, error) {
+	for _, model := range f.users {
+		if model.EmailId == email {
+			return model, nil
+		}
+	}
+	return nil, pg.ErrNoRows
+}
+
+func (f *fakeUserRepository) GetConnection() *pg.DB {
+	// nothing listens on the address, transactions can not be started
+	return pg.Connect(&pg.Options{Addr: "127.0.0.1:1", MaxRetries: 0})
+}
+
+func (f *fakeUserRepository) UpdateUser(userModel *repository.UserModel, tx *pg.Tx) (*repository.UserModel, error) {
+	f.updated = append(f.updated, userModel)
+	return userModel, nil
+}
+
+type fakeRoleGroupRepository struct {
+	repository.RoleGroupRepository
+	roleGroups []*repository.RoleGroup
+}
+
+func (f *fakeRoleGroupRepository) GetRoleGroupById(id int32) (*repository.RoleGroup, error) {
+	for _, roleGroup := range f.roleGroups {
+		if roleGroup.Id == id {
+			return roleGroup, nil
+		}
+	}
+	return nil, pg.ErrNoRows
+}
+
+func (f *fakeRoleGroupRepository) GetRoleGroupByName(name string) (*repository.RoleGroup, error) {
+	for _, roleGroup := range f.roleGroups {
+		if roleGroup.Name == name {
+			return roleGroup, nil
+		}
+	}
+	return nil, pg.ErrNoRows
+}
+
+func (f *fakeRoleGroupRepository) GetAllRoleGroup() ([]*repository.RoleGroup, error) {
+	return f.roleGroups, nil
+}
+
+func (f *fakeRoleGroupRepository) GetRoleGroupListByCasbinNames(names []string) ([]*repository.RoleGroup, error) {
+	var roleGroups []*repository.RoleGroup
+	for _, roleGroup := range f.roleGroups {
+		for _, name := range names {
+			if roleGroup.CasbinName == name {
+				roleGroups = append(roleGroups, roleGroup)
+			}
+		}
+	}
+	return roleGroups, nil
+}
+
+type fakeUserService struct {
+	user.UserService
+	roles        []string
+	deletedIds   []int32
+	updatedUsers []*bean.UserInfo
+}
+
+func (f *fakeUserService) CheckUserRoles(id int32) ([]string, error) {
+	return f.roles, nil
+}
+
+func (f *fakeUserService) DeleteUser(userInfo *bean.UserInfo) (bool, error) {
+	f.deletedIds = append(f.deletedIds, userInfo.Id)
+	return true, nil
+}
+
+func (f *fakeUserService) UpdateUser(userInfo *bean.UserInfo, token string, managerAuth func(token string, object string) bool) (*bean.UserInfo, error) {
+	f.updatedUsers = append(f.updatedUsers, userInfo)
+	return userInfo, nil
+}
+
+type fakeRoleGroupService struct {
+	user.RoleGroupService
+	created []*bean.RoleGroup
+	deleted []*bean.RoleGroup
+}
+
+func (f *fakeRoleGroupService) CreateRoleGroup(request *bean.RoleGroup) (*bean.RoleGroup, error) {
+	f.created = append(f.created, request)
+	return request, nil
+}
+
+func (f *fakeRoleGroupService) DeleteRoleGroup(model *bean.RoleGroup) (bool, error) {
+	f.deleted = append(f.deleted, model)
+	return true, nil
+}
+
+func newTestScimService(userRepository *fakeUserRepository, roleGroupRepository *fakeRoleGroupRepository,
+	userService *fakeUserService, roleGroupService *fakeRoleGroupService) *ScimServiceImpl {
+	return NewScimServiceImpl(zap.NewNop().Sugar(), userService, roleGroupService, userRepository, roleGroupRepository)
+}
+
+func assertScimStatus(t *testing.T, err error, status int) {
+	apiError, ok := err.(*util.ApiError)
+	if assert.True(t, ok, "err %v", err) {
+		assert.Equal(t, status, apiError.HttpStatusCode)
+	}
+}
+
+func TestScimService_GroupsNotProvisionedByScim(t *testing.T) {
+	roleGroupRepository := &fakeRoleGroupRepository{roleGroups: []*repository.RoleGroup{
+		{Id: 1, Name: "admins", CasbinName: "group:admins", Description: "created in devtron", Active: true},
+	}}
+	roleGroupService := &fakeRoleGroupService{}
+	impl := newTestScimService(&fakeUserRepository{}, roleGroupRepository, &fakeUserService{}, roleGroupService)
+	context := &RequestContext{UserId: 2}
+
+	_, err := impl.GetGroup(1)
+	assertScimStatus(t, err, http.StatusNotFound)
+	_, err = impl.ReplaceGroup(1, &Group{DisplayName: "admins"}, context)
+	assertScimStatus(t, err, http.StatusNotFound)
+	_, err = impl.PatchGroup(1, &PatchRequest{Operations: []*PatchOperation{{Op: PATCH_OP_REPLACE, Path: "displayName", Value: "owned"}}}, context)
+	assertScimStatus(t, err, http.StatusNotFound)
+	err = impl.DeleteGroup(1, context)
+	assertScimStatus(t, err, http.StatusNotFound)
+	assert.Empty(t, roleGroupService.deleted)
+	assert.Equal(t, "admins", roleGroupRepository.roleGroups[0].Name)
+
+	response, err := impl.ListGroups("", 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, response.TotalResults)
+	assert.Empty(t, response.Resources)
+	response, err = impl.ListGroups(`displayName eq "admins"`, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, response.TotalResults)
+}
+
+func TestScimService_CreateGroup(t *testing.T) {
+	roleGroupRepository := &fakeRoleGroupRepository{roleGroups: []*repository.RoleGroup{
+		{Id: 1, Name: "admins", CasbinName: "group:admins", Active: true},
+	}}
+	roleGroupService := &fakeRoleGroupService{}
+	impl := newTestScimService(&fakeUserRepository{}, roleGroupRepository, &fakeUserService{}, roleGroupService)
+	context := &RequestContext{UserId: 2}
+
+	// name of a group created in devtron can not be taken over through scim
+	_, err := impl.CreateGroup(&Group{DisplayName: "admins"}, context)
+	assertScimStatus(t, err, http.StatusConflict)
+	_, err = impl.CreateGroup(&Group{DisplayName: " "}, context)
+	assertScimStatus(t, err, http.StatusBadRequest)
+	assert.Empty(t, roleGroupService.created)
+
+	// created group is marked as provisioned through scim, it is looked up again after creation and not found in the fake
+	_, err = impl.CreateGroup(&Group{DisplayName: "developers"}, context)
+	assertScimStatus(t, err, http.StatusNotFound)
+	if assert.Len(t, roleGroupService.created, 1) {
+		assert.True(t, roleGroupService.created[0].ScimProvisioned)
+		assert.Equal(t, "developers", roleGroupService.created[0].Name)
+		assert.Empty(t, roleGroupService.created[0].RoleFilters)
+	}
+}
+
+func TestScimService_GetUser(t *testing.T) {
+	userRepository := &fakeUserRepository{users: map[int32]*repository.UserModel{
+		3: {Id: 3, EmailId: "user@example.com", Active: true},
+		4: {Id: 4, EmailId: "API-TOKEN:ci", Active: true, UserType: bean.USER_TYPE_API_TOKEN},
+	}}
+	roleGroupRepository := &fakeRoleGroupRepository{roleGroups: []*repository.RoleGroup{
+		{Id: 1, Name: "admins", CasbinName: "group:admins", Active: true},
+		{Id: 2, Name: "developers", CasbinName: "group:developers", Active: true, ScimProvisioned: true},
+	}}
+	userService := &fakeUserService{roles: []string{"group:admins", "group:developers", "role:super-admin___"}}
+	impl := newTestScimService(userRepository, roleGroupRepository, userService, &fakeRoleGroupService{})
+
+	scimUser, err := impl.GetUser(3)
+	assert.Nil(t, err)
+	assert.Equal(t, "user@example.com", scimUser.UserName)
+	assert.True(t, *scimUser.Active)
+	// only groups provisioned through scim are listed
+	assert.Equal(t, []*MultiValue{{Value: "2", Display: "developers"}}, scimUser.Groups)
+
+	_, err = impl.GetUser(4)
+	assertScimStatus(t, err, http.StatusNotFound)
+	_, err = impl.GetUser(5)
+	assertScimStatus(t, err, http.StatusNotFound)
+}
+
+func TestScimService_UserLifecycle(t *testing.T) {
+	activeUser := &repository.UserModel{Id: 3, EmailId: "user@example.com", Active: true}
+	userRepository := &fakeUserRepository{users: map[int32]*repository.UserModel{3: activeUser}}
+	userService := &fakeUserService{}
+	impl := newTestScimService(userRepository, &fakeRoleGroupRepository{}, userService, &fakeRoleGroupService{})
+	context := &RequestContext{UserId: 2}
+
+	_, err := impl.CreateUser(&User{UserName: "user@example.com"}, context)
+	assertScimStatus(t, err, http.StatusConflict)
+
+	// deactivation deletes the user in devtron which removes its roles
+	err = impl.DeleteUser(3, context)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{3}, userService.deletedIds)
+	assert.False(t, activeUser.Active)
+	err = impl.DeleteUser(3, context)
+	assert.Nil(t, err)
+	assert.Len(t, userService.deletedIds, 1)
+
+	// activation only marks the user active in a tx, roles are never replaced through the user service
+	active := true
+	_, err = impl.ReplaceUser(3, &User{UserName: "user@example.com", Active: &active}, context)
+	assert.NotNil(t, err)
+	_, err = impl.PatchUser(3, &PatchRequest{Operations: []*PatchOperation{{Op: PATCH_OP_REPLACE, Value: map[string]interface{}{"active": "True"}}}}, context)
+	assert.NotNil(t, err)
+	assert.Empty(t, userService.updatedUsers)
+	assert.Empty(t, userRepository.updated)
+	assert.False(t, activeUser.Active)
+
+	_, err = impl.ReplaceUser(3, &User{UserName: "other@example.com", Active: &active}, context)
+	assertScimStatus(t, err, http.StatusBadRequest)
+}
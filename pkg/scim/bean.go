@@ -0,0 +1,123 @@
+package scim
+
+const (
+	SCHEMA_USER          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCHEMA_GROUP         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCHEMA_LIST_RESPONSE = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCHEMA_PATCH_OP      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCHEMA_ERROR         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCHEMA_SP_CONFIG     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	RESOURCE_TYPE_USER  = "User"
+	RESOURCE_TYPE_GROUP = "Group"
+
+	PATCH_OP_ADD     = "add"
+	PATCH_OP_REMOVE  = "remove"
+	PATCH_OP_REPLACE = "replace"
+
+	// DEFAULT_PAGE_SIZE is the count of resources listed when the client does not ask for a count
+	DEFAULT_PAGE_SIZE = 100
+	MAX_PAGE_SIZE     = 500
+)
+
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// MultiValue is the complex multi-valued attribute of scim, used for emails, group members and groups of user
+type MultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// User is the scim user, userName is the email of devtron user
+type User struct {
+	Schemas     []string      `json:"schemas"`
+	Id          string        `json:"id,omitempty"`
+	ExternalId  string        `json:"externalId,omitempty"`
+	UserName    string        `json:"userName" validate:"required"`
+	Name        *Name         `json:"name,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	Emails      []*MultiValue `json:"emails,omitempty"`
+	Active      *bool         `json:"active,omitempty"`
+	// Groups are read-only, membership is managed through groups
+	Groups []*MultiValue `json:"groups,omitempty"`
+	Meta   *Meta         `json:"meta,omitempty"`
+}
+
+// Group is the scim group, it is a devtron role group whose roles are managed in devtron
+type Group struct {
+	Schemas     []string      `json:"schemas"`
+	Id          string        `json:"id,omitempty"`
+	ExternalId  string        `json:"externalId,omitempty"`
+	DisplayName string        `json:"displayName" validate:"required"`
+	Members     []*MultiValue `json:"members,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type PatchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*PatchOperation `json:"Operations" validate:"required,min=1"`
+}
+
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+type FilterSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type BulkSupported struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string                `json:"schemas"`
+	Patch                 Supported               `json:"patch"`
+	Bulk                  BulkSupported           `json:"bulk"`
+	Filter                FilterSupported         `json:"filter"`
+	ChangePassword        Supported               `json:"changePassword"`
+	Sort                  Supported               `json:"sort"`
+	Etag                  Supported               `json:"etag"`
+	AuthenticationSchemes []*AuthenticationScheme `json:"authenticationSchemes"`
+}
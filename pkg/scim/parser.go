@@ -0,0 +1,81 @@
+package scim
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// eqFilterRegex matches the equality filter used by identity providers to look up a resource, i.e. userName eq "abc"
+var eqFilterRegex = regexp.MustCompile(`(?i)^\s*([a-zA-Z.]+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// memberPathRegex matches the path selecting a member to remove, i.e. members[value eq "12"]
+var memberPathRegex = regexp.MustCompile(`(?i)^\s*members\[\s*value\s+eq\s+"([^"]*)"\s*]\s*$`)
+
+// parseEqFilter returns the attribute and value of an equality filter, only equality filters are supported
+func parseEqFilter(filter string) (string, string, error) {
+	matches := eqFilterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", "", fmt.Errorf("filter '%s' is not supported, only 'attribute eq \"value\"' is supported", filter)
+	}
+	value := strings.ReplaceAll(matches[2], `\"`, `"`)
+	return matches[1], value, nil
+}
+
+// parseMemberPath returns the member id selected in path, empty if path selects all the members
+func parseMemberPath(path string) (string, bool) {
+	if strings.EqualFold(strings.TrimSpace(path), "members") {
+		return "", true
+	}
+	matches := memberPathRegex.FindStringSubmatch(path)
+	if matches == nil {
+		return "", false
+	}
+	return matches[1], true
+}
+
+// parseBool parses the boolean values which some identity providers send as strings, i.e. "False"
+func parseBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(strings.ToLower(v))
+	}
+	return false, fmt.Errorf("value '%v' is not a boolean", value)
+}
+
+// parseMemberIds returns the ids of members in a patch value, which is a list of members or a single member
+func parseMemberIds(value interface{}) ([]int32, error) {
+	var values []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		values = v
+	case map[string]interface{}:
+		values = []interface{}{v}
+	default:
+		return nil, fmt.Errorf("value '%v' is not a list of members", value)
+	}
+	var ids []int32
+	for _, item := range values {
+		member, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("member '%v' is not valid", item)
+		}
+		id, err := parseId(fmt.Sprintf("%v", member["value"]))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func parseId(value string) (int32, error) {
+	id, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("id '%s' is not valid", value)
+	}
+	return int32(id), nil
+}
@@ -0,0 +1,53 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEqFilter(t *testing.T) {
+	attribute, value, err := parseEqFilter(`userName Eq "john@example.com"`)
+	assert.Nil(t, err)
+	assert.Equal(t, "userName", attribute)
+	assert.Equal(t, "john@example.com", value)
+
+	attribute, value, err = parseEqFilter(`displayName eq "dev \"ops\""`)
+	assert.Nil(t, err)
+	assert.Equal(t, "displayName", attribute)
+	assert.Equal(t, `dev "ops"`, value)
+
+	_, _, err = parseEqFilter(`userName sw "john"`)
+	assert.NotNil(t, err)
+}
+
+func TestParseMemberPath(t *testing.T) {
+	id, ok := parseMemberPath(`members[value eq "12"]`)
+	assert.True(t, ok)
+	assert.Equal(t, "12", id)
+
+	id, ok = parseMemberPath("members")
+	assert.True(t, ok)
+	assert.Equal(t, "", id)
+
+	_, ok = parseMemberPath("displayName")
+	assert.False(t, ok)
+}
+
+func TestParsePatchValues(t *testing.T) {
+	var request PatchRequest
+	err := json.Unmarshal([]byte(`{"Operations":[{"op":"add","path":"members","value":[{"value":"3"},{"value":"7"}]},{"op":"replace","value":{"active":"False"}}]}`), &request)
+	assert.Nil(t, err)
+
+	ids, err := parseMemberIds(request.Operations[0].Value)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{3, 7}, ids)
+
+	active, err := parseBool(request.Operations[1].Value.(map[string]interface{})["active"])
+	assert.Nil(t, err)
+	assert.False(t, active)
+
+	_, err = parseMemberIds([]interface{}{map[string]interface{}{"value": "abc"}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,226 @@
+package security
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ImageRescanService interface {
+	// RescanDeployedImages asks image scanner to scan the images deployed for apps and charts again and re-matches
+	// every deployed image against its latest scan result. Cves not present in the previous result are recorded as
+	// new cves of the deployment, the findings having cves blocked by policy in production environments are returned
+	RescanDeployedImages() ([]*bean.DeployedImageCveFinding, error)
+}
+
+type ImageRescanServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	imageScanDeployInfoRepository security.ImageScanDeployInfoRepository
+	scanHistoryRepository         security.ImageScanHistoryRepository
+	scanResultRepository          security.ImageScanResultRepository
+	imageScanNewCveRepository     security.ImageScanNewCveRepository
+	ciTemplateRepository          pipelineConfig.CiTemplateRepository
+	appRepository                 app.AppRepository
+	environmentService            cluster.EnvironmentService
+	policyService                 PolicyService
+}
+
+func NewImageRescanServiceImpl(logger *zap.SugaredLogger,
+	imageScanDeployInfoRepository security.ImageScanDeployInfoRepository,
+	scanHistoryRepository security.ImageScanHistoryRepository,
+	scanResultRepository security.ImageScanResultRepository,
+	imageScanNewCveRepository security.ImageScanNewCveRepository,
+	ciTemplateRepository pipelineConfig.CiTemplateRepository, appRepository app.AppRepository,
+	environmentService cluster.EnvironmentService, policyService PolicyService) *ImageRescanServiceImpl {
+	return &ImageRescanServiceImpl{
+		logger:                        logger,
+		imageScanDeployInfoRepository: imageScanDeployInfoRepository,
+		scanHistoryRepository:         scanHistoryRepository,
+		scanResultRepository:          scanResultRepository,
+		imageScanNewCveRepository:     imageScanNewCveRepository,
+		ciTemplateRepository:          ciTemplateRepository,
+		appRepository:                 appRepository,
+		environmentService:            environmentService,
+		policyService:                 policyService,
+	}
+}
+
+func (impl *ImageRescanServiceImpl) RescanDeployedImages() ([]*bean.DeployedImageCveFinding, error) {
+	deployInfos, err := impl.imageScanDeployInfoRepository.FindAll()
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployed images", "err", err)
+		return nil, err
+	}
+	var findings []*bean.DeployedImageCveFinding
+	for _, deployInfo := range deployInfos {
+		if deployInfo.ObjectType != security.ScanObjectType_APP && deployInfo.ObjectType != security.ScanObjectType_CHART {
+			continue
+		}
+		finding, err := impl.rescanDeployInfo(deployInfo)
+		if err != nil {
+			impl.logger.Errorw("error in rescanning deployed images", "err", err, "imageScanDeployInfoId", deployInfo.Id)
+			continue
+		}
+		if finding != nil {
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+func (impl *ImageRescanServiceImpl) rescanDeployInfo(deployInfo *security.ImageScanDeployInfo) (*bean.DeployedImageCveFinding, error) {
+	var latestHistoryIds []int
+	var newCves []*security.ImageScanNewCve
+	var newCveStores []*security.CveStore
+	var image string
+	updated := false
+	for _, historyId := range deployInfo.ImageScanExecutionHistoryId {
+		history, err := impl.scanHistoryRepository.FindOne(historyId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching scan history of deployed image", "err", err, "historyId", historyId)
+			return nil, err
+		}
+		image = history.Image
+		impl.requestRescan(deployInfo, history)
+		var latest *security.ImageScanExecutionHistory
+		if len(history.ImageHash) > 0 {
+			latest, err = impl.scanHistoryRepository.FindByImageDigest(history.ImageHash)
+		} else {
+			latest, err = impl.scanHistoryRepository.FindByImage(history.Image)
+		}
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching latest scan history of deployed image", "err", err, "image", history.Image)
+			return nil, err
+		}
+		if latest == nil || latest.Id == 0 || latest.Id == history.Id || !latest.ExecutionTime.After(history.ExecutionTime) {
+			latestHistoryIds = append(latestHistoryIds, history.Id)
+			continue
+		}
+		updated = true
+		latestHistoryIds = append(latestHistoryIds, latest.Id)
+		previousResults, err := impl.scanResultRepository.FetchByScanExecutionId(history.Id)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		latestResults, err := impl.scanResultRepository.FetchByScanExecutionId(latest.Id)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for _, cveStore := range diffCves(previousResults, latestResults) {
+			newCves = append(newCves, &security.ImageScanNewCve{
+				ImageScanDeployInfoId:               deployInfo.Id,
+				CveStoreName:                        cveStore.Name,
+				Image:                               history.Image,
+				PreviousImageScanExecutionHistoryId: history.Id,
+				ImageScanExecutionHistoryId:         latest.Id,
+				DetectedOn:                          time.Now(),
+			})
+			newCveStores = append(newCveStores, cveStore)
+		}
+	}
+	if !updated {
+		return nil, nil
+	}
+	deployInfo.ImageScanExecutionHistoryId = latestHistoryIds
+	deployInfo.UpdatedOn = time.Now()
+	deployInfo.UpdatedBy = 1
+	err := impl.imageScanDeployInfoRepository.Update(deployInfo)
+	if err != nil {
+		impl.logger.Errorw("error in updating scan history of deployed image", "err", err, "imageScanDeployInfoId", deployInfo.Id)
+		return nil, err
+	}
+	if len(newCves) == 0 {
+		return nil, nil
+	}
+	err = impl.imageScanNewCveRepository.SaveAll(newCves)
+	if err != nil {
+		impl.logger.Errorw("error in saving new cves of deployed image", "err", err, "imageScanDeployInfoId", deployInfo.Id)
+		return nil, err
+	}
+	return impl.getBlockedCveFinding(deployInfo, image, newCveStores)
+}
+
+// getBlockedCveFinding returns the finding of the new cves blocked by policy if the image runs in a production environment
+func (impl *ImageRescanServiceImpl) getBlockedCveFinding(deployInfo *security.ImageScanDeployInfo, image string, newCveStores []*security.CveStore) (*bean.DeployedImageCveFinding, error) {
+	env, err := impl.environmentService.FindById(deployInfo.EnvId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment of deployed image", "err", err, "envId", deployInfo.EnvId)
+		return nil, err
+	}
+	if !env.Default {
+		return nil, nil
+	}
+	app, err := impl.appRepository.FindById(deployInfo.ScanObjectMetaId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app of deployed image", "err", err, "appId", deployInfo.ScanObjectMetaId)
+		return nil, err
+	}
+	blockedCves, err := impl.policyService.GetBlockedCVEList(newCveStores, env.ClusterId, env.Id, app.Id, app.AppStore)
+	if err != nil {
+		impl.logger.Errorw("error in applying cve policy on new cves of deployed image", "err", err, "appId", app.Id, "envId", env.Id)
+		return nil, err
+	}
+	if len(blockedCves) == 0 {
+		return nil, nil
+	}
+	finding := &bean.DeployedImageCveFinding{
+		ImageScanDeployInfoId: deployInfo.Id,
+		AppId:                 app.Id,
+		AppName:               app.AppName,
+		EnvId:                 env.Id,
+		EnvName:               env.Environment,
+		TeamId:                app.TeamId,
+		Image:                 image,
+	}
+	for _, cve := range blockedCves {
+		finding.BlockedCves = append(finding.BlockedCves, cve.Name)
+	}
+	return finding, nil
+}
+
+// requestRescan asks image scanner to scan the image again, the result is re-matched when found newer than the
+// recorded one so failures are only logged
+func (impl *ImageRescanServiceImpl) requestRescan(deployInfo *security.ImageScanDeployInfo, history *security.ImageScanExecutionHistory) {
+	scanEvent := &ScanEvent{Image: history.Image, ImageDigest: history.ImageHash, UserId: 1}
+	if deployInfo.ObjectType == security.ScanObjectType_APP {
+		scanEvent.AppId = deployInfo.ScanObjectMetaId
+		scanEvent.EnvId = deployInfo.EnvId
+		ciTemplate, err := impl.ciTemplateRepository.FindByAppId(deployInfo.ScanObjectMetaId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching docker registry of app", "err", err, "appId", deployInfo.ScanObjectMetaId)
+			return
+		}
+		if ciTemplate != nil && ciTemplate.DockerRegistry != nil {
+			scanEvent.DockerRegistryId = ciTemplate.DockerRegistry.Id
+		}
+	}
+	err := impl.policyService.SendEventToClairUtility(scanEvent)
+	if err != nil {
+		impl.logger.Errorw("error in requesting rescan of deployed image", "err", err, "image", history.Image)
+	}
+}
+
+// diffCves returns the cves of latest scan result which are not present in previous scan result
+func diffCves(previousResults []*security.ImageScanExecutionResult, latestResults []*security.ImageScanExecutionResult) []*security.CveStore {
+	previous := make(map[string]bool)
+	for _, result := range previousResults {
+		previous[result.CveStoreName] = true
+	}
+	var newCves []*security.CveStore
+	for _, result := range latestResults {
+		if previous[result.CveStoreName] {
+			continue
+		}
+		previous[result.CveStoreName] = true
+		cveStore := result.CveStore
+		newCves = append(newCves, &cveStore)
+	}
+	return newCves
+}
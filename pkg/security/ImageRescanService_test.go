@@ -0,0 +1,26 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func scanResult(cveName string, severity security.Severity) *security.ImageScanExecutionResult {
+	return &security.ImageScanExecutionResult{CveStoreName: cveName, CveStore: security.CveStore{Name: cveName, Severity: severity}}
+}
+
+func TestDiffCves(t *testing.T) {
+	previous := []*security.ImageScanExecutionResult{scanResult("CVE-2021-1", security.Low), scanResult("CVE-2021-2", security.Critical)}
+	latest := []*security.ImageScanExecutionResult{scanResult("CVE-2021-2", security.Critical), scanResult("CVE-2022-3", security.Critical),
+		scanResult("CVE-2022-3", security.Critical)}
+
+	newCves := diffCves(previous, latest)
+	assert.Equal(t, 1, len(newCves))
+	assert.Equal(t, "CVE-2022-3", newCves[0].Name)
+	assert.Equal(t, security.Critical, newCves[0].Severity)
+
+	assert.Nil(t, diffCves(latest, previous[1:]))
+	assert.Equal(t, 2, len(diffCves(nil, previous)))
+}
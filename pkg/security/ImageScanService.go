@@ -18,6 +18,7 @@
 package security
 
 import (
+	"fmt"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
 	repository2 "github.com/devtron-labs/devtron/pkg/team"
 	"time"
@@ -54,6 +55,7 @@ type ImageScanServiceImpl struct {
 	policyService                 PolicyService
 	pipelineRepository            pipelineConfig.PipelineRepository
 	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+	imageScanNewCveRepository     security.ImageScanNewCveRepository
 }
 
 type ImageScanRequest struct {
@@ -125,7 +127,8 @@ func NewImageScanServiceImpl(Logger *zap.SugaredLogger, scanHistoryRepository se
 	userService user.UserService, teamRepository repository2.TeamRepository,
 	appRepository app.AppRepository,
 	envService cluster.EnvironmentService, ciArtifactRepository repository.CiArtifactRepository, policyService PolicyService,
-	pipelineRepository pipelineConfig.PipelineRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository) *ImageScanServiceImpl {
+	pipelineRepository pipelineConfig.PipelineRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	imageScanNewCveRepository security.ImageScanNewCveRepository) *ImageScanServiceImpl {
 	return &ImageScanServiceImpl{Logger: Logger, scanHistoryRepository: scanHistoryRepository, scanResultRepository: scanResultRepository,
 		scanObjectMetaRepository: scanObjectMetaRepository, cveStoreRepository: cveStoreRepository,
 		imageScanDeployInfoRepository: imageScanDeployInfoRepository,
@@ -137,6 +140,7 @@ func NewImageScanServiceImpl(Logger *zap.SugaredLogger, scanHistoryRepository se
 		policyService:                 policyService,
 		pipelineRepository:            pipelineRepository,
 		ciPipelineRepository:          ciPipelineRepository,
+		imageScanNewCveRepository:     imageScanNewCveRepository,
 	}
 }
 
@@ -527,6 +531,17 @@ func (impl ImageScanServiceImpl) VulnerabilityExposure(request *security.Vulnera
 	}
 
 	cveStores = append(cveStores, cveStore)
+	newCves, err := impl.imageScanNewCveRepository.FindByCveName(request.CveName)
+	if err != nil && err != pg.ErrNoRows {
+		impl.Logger.Errorw("error while fetching new cves of deployed images", "err", err)
+		return nil, err
+	}
+	newSinceLastDeploy := make(map[string]bool)
+	for _, newCve := range newCves {
+		if newCve.ImageScanDeployInfo != nil {
+			newSinceLastDeploy[fmt.Sprintf("%d-%d", newCve.ImageScanDeployInfo.ScanObjectMetaId, newCve.ImageScanDeployInfo.EnvId)] = true
+		}
+	}
 	for _, item := range vulnerabilityExposureList {
 		envId := 0
 		if item.AppStore {
@@ -545,6 +560,7 @@ func (impl ImageScanServiceImpl) VulnerabilityExposure(request *security.Vulnera
 		if len(blockCveList) > 0 {
 			item.Blocked = true
 		}
+		item.NewSinceLastDeploy = newSinceLastDeploy[fmt.Sprintf("%d-%d", item.AppId, envId)]
 	}
 	vulnerabilityExposureListingResponse.VulnerabilityExposure = vulnerabilityExposureList
 	return vulnerabilityExposureListingResponse, nil
@@ -0,0 +1,496 @@
+/*
+ * Copyright (c) 2020 Devtron Labs
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+)
+
+type SbomConfig struct {
+	ImageScannerEndpoint string `env:"IMAGE_SCANNER_ENDPOINT" envDefault:"http://image-scanner-new-demo-devtroncd-service.devtroncd:80"`
+	// SbomFormats e.g. spdx,cyclonedx, generated for every ci artifact by image scanner through POST /scanner/sbom, unset disables
+	SbomFormats []string `env:"SBOM_FORMATS"`
+}
+
+func GetSbomConfig() (*SbomConfig, error) {
+	cfg := &SbomConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type SbomRequest struct {
+	CiArtifactId int             `json:"ciArtifactId" validate:"required"`
+	Format       string          `json:"format" validate:"required"`
+	Document     json.RawMessage `json:"document" validate:"required"`
+}
+
+type SbomDto struct {
+	Id             int       `json:"id"`
+	CiArtifactId   int       `json:"ciArtifactId"`
+	Image          string    `json:"image"`
+	Format         string    `json:"format"`
+	SpecVersion    string    `json:"specVersion"`
+	ComponentCount int       `json:"componentCount"`
+	Generated      bool      `json:"generated"`
+	UpdatedOn      time.Time `json:"updatedOn"`
+}
+
+// SbomGenerateEvent asks image scanner to generate sbom documents of the image in the given formats
+type SbomGenerateEvent struct {
+	ScanEvent
+	Formats []string `json:"formats"`
+}
+
+const (
+	defaultSbomSearchSize = 20
+	// sbomSearchBatchSize is the number of exposures read at a time while filling a page of visible exposures
+	sbomSearchBatchSize = 100
+)
+
+type sbomScannerResponse struct {
+	Result []*SbomRequest `json:"result"`
+}
+
+type SbomService interface {
+	// GenerateSbom gets the sbom of the artifact generated by image scanner in configured formats and stores them, it
+	// is a no-op unless SBOM_FORMATS is configured
+	GenerateSbom(ciArtifactId int, userId int32) error
+	IsSbomGenerationEnabled() bool
+	// SaveSbom stores an uploaded sbom, sbom generated by image scanner can not be overwritten
+	SaveSbom(request *SbomRequest, userId int32) (*SbomDto, error)
+	FetchSboms(ciArtifactId int) ([]*SbomDto, error)
+	FetchSbomDocument(ciArtifactId int, format string) (*security.CiArtifactSbom, error)
+	// SearchComponents returns the page of matching exposures for which isVisible is true, exposures are read from db
+	// in batches until the page is filled
+	SearchComponents(request *security.SbomComponentSearchRequest, isVisible func(exposure *security.SbomComponentExposure) bool) ([]*security.SbomComponentExposure, error)
+	GetAppIdByCiArtifactId(ciArtifactId int) (int, error)
+}
+
+type SbomServiceImpl struct {
+	logger                   *zap.SugaredLogger
+	ciArtifactSbomRepository security.CiArtifactSbomRepository
+	ciArtifactRepository     repository.CiArtifactRepository
+	ciPipelineRepository     pipelineConfig.CiPipelineRepository
+	ciTemplateRepository     pipelineConfig.CiTemplateRepository
+	client                   *http.Client
+	sbomConfig               *SbomConfig
+}
+
+func NewSbomServiceImpl(logger *zap.SugaredLogger, ciArtifactSbomRepository security.CiArtifactSbomRepository,
+	ciArtifactRepository repository.CiArtifactRepository, ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	ciTemplateRepository pipelineConfig.CiTemplateRepository, client *http.Client, sbomConfig *SbomConfig) *SbomServiceImpl {
+	return &SbomServiceImpl{
+		logger:                   logger,
+		ciArtifactSbomRepository: ciArtifactSbomRepository,
+		ciArtifactRepository:     ciArtifactRepository,
+		ciPipelineRepository:     ciPipelineRepository,
+		ciTemplateRepository:     ciTemplateRepository,
+		client:                   client,
+		sbomConfig:               sbomConfig,
+	}
+}
+
+func (impl *SbomServiceImpl) IsSbomGenerationEnabled() bool {
+	return len(impl.sbomConfig.SbomFormats) > 0
+}
+
+func (impl *SbomServiceImpl) GenerateSbom(ciArtifactId int, userId int32) error {
+	if !impl.IsSbomGenerationEnabled() {
+		return nil
+	}
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "err", err, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(artifact.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", artifact.PipelineId)
+		return err
+	}
+	event := &SbomGenerateEvent{
+		ScanEvent: ScanEvent{
+			Image:        artifact.Image,
+			ImageDigest:  artifact.ImageDigest,
+			AppId:        ciPipeline.AppId,
+			PipelineId:   artifact.PipelineId,
+			CiArtifactId: artifact.Id,
+			UserId:       int(userId),
+		},
+		Formats: impl.sbomConfig.SbomFormats,
+	}
+	ciTemplate, err := impl.ciTemplateRepository.FindByAppId(ciPipeline.AppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching docker registry of app", "err", err, "appId", ciPipeline.AppId)
+		return err
+	}
+	if ciTemplate != nil && ciTemplate.DockerRegistry != nil {
+		event.DockerRegistryId = ciTemplate.DockerRegistry.Id
+	}
+	reqBody, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", impl.sbomConfig.ImageScannerEndpoint, "scanner/sbom"), bytes.NewBuffer(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := impl.client.Do(req)
+	if err != nil {
+		impl.logger.Errorw("error in requesting sbom from image scanner", "err", err, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		impl.logger.Errorw("sbom generation failed in image scanner", "status", resp.StatusCode, "body", string(respBody), "ciArtifactId", ciArtifactId)
+		return fmt.Errorf("sbom generation failed with status %d", resp.StatusCode)
+	}
+	scannerResponse := &sbomScannerResponse{}
+	err = json.Unmarshal(respBody, scannerResponse)
+	if err != nil {
+		impl.logger.Errorw("error in parsing sbom response of image scanner", "err", err, "ciArtifactId", ciArtifactId)
+		return err
+	}
+	for _, sbom := range scannerResponse.Result {
+		sbom.CiArtifactId = ciArtifactId
+		_, err = impl.saveSbom(sbom, userId, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *SbomServiceImpl) SaveSbom(request *SbomRequest, userId int32) (*SbomDto, error) {
+	return impl.saveSbom(request, userId, false)
+}
+
+func (impl *SbomServiceImpl) saveSbom(request *SbomRequest, userId int32, generated bool) (*SbomDto, error) {
+	format := strings.ToLower(request.Format)
+	specVersion, components, err := parseSbomDocument(format, request.Document)
+	if err != nil {
+		impl.logger.Errorw("invalid sbom document", "err", err, "ciArtifactId", request.CiArtifactId, "format", format)
+		return nil, err
+	}
+	artifact, err := impl.ciArtifactRepository.Get(request.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "err", err, "ciArtifactId", request.CiArtifactId)
+		return nil, err
+	}
+	model, err := impl.ciArtifactSbomRepository.FindByCiArtifactIdAndFormat(artifact.Id, format)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching sbom", "err", err, "ciArtifactId", artifact.Id, "format", format)
+		return nil, err
+	}
+	if model == nil || model.Id == 0 {
+		model = &security.CiArtifactSbom{
+			CiArtifactId: artifact.Id,
+			Format:       format,
+			AuditLog:     sql.AuditLog{CreatedOn: time.Now(), CreatedBy: userId},
+		}
+	}
+	err = checkSbomOverwrite(model, generated)
+	if err != nil {
+		return nil, err
+	}
+	model.Generated = generated
+	model.Image = artifact.Image
+	model.SpecVersion = specVersion
+	model.Document = string(request.Document)
+	model.ComponentCount = len(components)
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+
+	dbConnection := impl.ciArtifactSbomRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	if model.Id > 0 {
+		err = impl.ciArtifactSbomRepository.Update(model, tx)
+		if err == nil {
+			err = impl.ciArtifactSbomRepository.DeleteComponentsBySbomId(model.Id, tx)
+		}
+	} else {
+		err = impl.ciArtifactSbomRepository.Save(model, tx)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving sbom", "err", err, "ciArtifactId", artifact.Id, "format", format)
+		return nil, err
+	}
+	for _, component := range components {
+		component.CiArtifactSbomId = model.Id
+		component.CiArtifactId = artifact.Id
+		component.Image = artifact.Image
+	}
+	err = impl.ciArtifactSbomRepository.SaveComponents(components, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving sbom components", "err", err, "ciArtifactId", artifact.Id, "format", format)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return adaptSbom(model), nil
+}
+
+func (impl *SbomServiceImpl) FetchSboms(ciArtifactId int) ([]*SbomDto, error) {
+	sbomArtifactId, err := impl.getSbomArtifactId(ciArtifactId)
+	if err != nil {
+		return nil, err
+	}
+	sboms, err := impl.ciArtifactSbomRepository.FindByCiArtifactId(sbomArtifactId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching sboms", "err", err, "ciArtifactId", ciArtifactId)
+		return nil, err
+	}
+	sbomDtos := make([]*SbomDto, 0, len(sboms))
+	for _, sbom := range sboms {
+		sbomDtos = append(sbomDtos, adaptSbom(sbom))
+	}
+	return sbomDtos, nil
+}
+
+func (impl *SbomServiceImpl) FetchSbomDocument(ciArtifactId int, format string) (*security.CiArtifactSbom, error) {
+	sbomArtifactId, err := impl.getSbomArtifactId(ciArtifactId)
+	if err != nil {
+		return nil, err
+	}
+	sbom, err := impl.ciArtifactSbomRepository.FindByCiArtifactIdAndFormat(sbomArtifactId, strings.ToLower(format))
+	if err != nil {
+		impl.logger.Errorw("error in fetching sbom", "err", err, "ciArtifactId", ciArtifactId, "format", format)
+		return nil, err
+	}
+	return sbom, nil
+}
+
+func (impl *SbomServiceImpl) SearchComponents(request *security.SbomComponentSearchRequest, isVisible func(exposure *security.SbomComponentExposure) bool) ([]*security.SbomComponentExposure, error) {
+	size := request.Size
+	if size <= 0 {
+		size = defaultSbomSearchSize
+	}
+	batchSize := size
+	if batchSize < sbomSearchBatchSize {
+		batchSize = sbomSearchBatchSize
+	}
+	exposures := make([]*security.SbomComponentExposure, 0)
+	skip := request.Offset
+	for dbOffset := 0; len(exposures) < size; dbOffset += batchSize {
+		batch, err := impl.ciArtifactSbomRepository.SearchDeployedComponents(request, dbOffset, batchSize)
+		if err != nil && !util.IsErrNoRows(err) {
+			return nil, err
+		}
+		for _, exposure := range batch {
+			if len(exposures) == size {
+				break
+			}
+			if !isVisible(exposure) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			exposures = append(exposures, exposure)
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+	return exposures, nil
+}
+
+func (impl *SbomServiceImpl) GetAppIdByCiArtifactId(ciArtifactId int) (int, error) {
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "err", err, "ciArtifactId", ciArtifactId)
+		return 0, err
+	}
+	ciPipeline, err := impl.ciPipelineRepository.FindById(artifact.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "err", err, "ciPipelineId", artifact.PipelineId)
+		return 0, err
+	}
+	return ciPipeline.AppId, nil
+}
+
+// getSbomArtifactId returns the artifact whose sbom applies, artifacts of linked ci pipelines share the sbom of parent
+func (impl *SbomServiceImpl) getSbomArtifactId(ciArtifactId int) (int, error) {
+	artifact, err := impl.ciArtifactRepository.Get(ciArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci artifact", "err", err, "ciArtifactId", ciArtifactId)
+		return 0, err
+	}
+	if artifact.ParentCiArtifact > 0 {
+		return artifact.ParentCiArtifact, nil
+	}
+	return artifact.Id, nil
+}
+
+// checkSbomOverwrite returns an error if an uploaded document would replace the document generated by image scanner
+func checkSbomOverwrite(existing *security.CiArtifactSbom, generated bool) error {
+	if existing.Id > 0 && existing.Generated && !generated {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusConflict,
+			InternalMessage: fmt.Sprintf("sbom %d is generated by image scanner", existing.Id),
+			UserMessage:     fmt.Sprintf("%s sbom of the artifact is generated by image scanner and can not be overwritten", existing.Format),
+		}
+	}
+	return nil
+}
+
+func adaptSbom(sbom *security.CiArtifactSbom) *SbomDto {
+	return &SbomDto{
+		Id:             sbom.Id,
+		CiArtifactId:   sbom.CiArtifactId,
+		Image:          sbom.Image,
+		Format:         sbom.Format,
+		SpecVersion:    sbom.SpecVersion,
+		ComponentCount: sbom.ComponentCount,
+		Generated:      sbom.Generated,
+		UpdatedOn:      sbom.UpdatedOn,
+	}
+}
+
+type spdxDocument struct {
+	SpdxVersion string         `json:"spdxVersion"`
+	Packages    []*spdxPackage `json:"packages"`
+}
+
+type spdxPackage struct {
+	Name         string `json:"name"`
+	VersionInfo  string `json:"versionInfo"`
+	ExternalRefs []struct {
+		ReferenceType    string `json:"referenceType"`
+		ReferenceLocator string `json:"referenceLocator"`
+	} `json:"externalRefs"`
+}
+
+type cycloneDxDocument struct {
+	BomFormat   string                `json:"bomFormat"`
+	SpecVersion string                `json:"specVersion"`
+	Components  []*cycloneDxComponent `json:"components"`
+}
+
+type cycloneDxComponent struct {
+	Type       string                `json:"type"`
+	Name       string                `json:"name"`
+	Version    string                `json:"version"`
+	Purl       string                `json:"purl"`
+	Components []*cycloneDxComponent `json:"components"`
+}
+
+// parseSbomDocument validates a json sbom document and returns its spec version and distinct components
+func parseSbomDocument(format string, document []byte) (string, []*security.SbomComponent, error) {
+	var specVersion string
+	var components []*security.SbomComponent
+	switch format {
+	case security.SbomFormat_SPDX:
+		spdx := &spdxDocument{}
+		err := json.Unmarshal(document, spdx)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid spdx document: %s", err.Error())
+		}
+		if len(spdx.SpdxVersion) == 0 {
+			return "", nil, fmt.Errorf("invalid spdx document: spdxVersion is missing")
+		}
+		specVersion = spdx.SpdxVersion
+		for _, spdxPkg := range spdx.Packages {
+			component := &security.SbomComponent{Name: spdxPkg.Name, Version: spdxPkg.VersionInfo}
+			for _, ref := range spdxPkg.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					component.Purl = ref.ReferenceLocator
+				}
+			}
+			component.Type = getPurlType(component.Purl)
+			components = append(components, component)
+		}
+	case security.SbomFormat_CYCLONEDX:
+		cycloneDx := &cycloneDxDocument{}
+		err := json.Unmarshal(document, cycloneDx)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid cyclonedx document: %s", err.Error())
+		}
+		if cycloneDx.BomFormat != "CycloneDX" {
+			return "", nil, fmt.Errorf("invalid cyclonedx document: bomFormat is not CycloneDX")
+		}
+		specVersion = cycloneDx.SpecVersion
+		components = flattenCycloneDxComponents(cycloneDx.Components, components)
+	default:
+		return "", nil, fmt.Errorf("unsupported sbom format %s, supported formats are %s and %s", format,
+			security.SbomFormat_SPDX, security.SbomFormat_CYCLONEDX)
+	}
+	distinct := make(map[string]bool)
+	var distinctComponents []*security.SbomComponent
+	for _, component := range components {
+		key := component.Name + "@" + component.Version + "@" + component.Purl
+		if len(component.Name) == 0 || distinct[key] {
+			continue
+		}
+		distinct[key] = true
+		distinctComponents = append(distinctComponents, component)
+	}
+	return specVersion, distinctComponents, nil
+}
+
+func flattenCycloneDxComponents(cycloneDxComponents []*cycloneDxComponent, components []*security.SbomComponent) []*security.SbomComponent {
+	for _, cycloneDxComp := range cycloneDxComponents {
+		componentType := getPurlType(cycloneDxComp.Purl)
+		if len(componentType) == 0 {
+			componentType = cycloneDxComp.Type
+		}
+		components = append(components, &security.SbomComponent{
+			Name:    cycloneDxComp.Name,
+			Version: cycloneDxComp.Version,
+			Purl:    cycloneDxComp.Purl,
+			Type:    componentType,
+		})
+		components = flattenCycloneDxComponents(cycloneDxComp.Components, components)
+	}
+	return components
+}
+
+// getPurlType returns the package type of a package url like maven for pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1
+func getPurlType(purl string) string {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(purl, "pkg:"), "/", 2)[0]
+}
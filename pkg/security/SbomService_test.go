@@ -0,0 +1,134 @@
+package security
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestParseSbomDocument(t *testing.T) {
+	spdx := `{"spdxVersion":"SPDX-2.3","packages":[
+		{"name":"log4j-core","versionInfo":"2.14.1","externalRefs":[{"referenceType":"purl","referenceLocator":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]},
+		{"name":"log4j-core","versionInfo":"2.14.1","externalRefs":[{"referenceType":"purl","referenceLocator":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}]},
+		{"name":""}]}`
+	specVersion, components, err := parseSbomDocument(security.SbomFormat_SPDX, []byte(spdx))
+	assert.Nil(t, err)
+	assert.Equal(t, "SPDX-2.3", specVersion)
+	assert.Equal(t, 1, len(components))
+	assert.Equal(t, "maven", components[0].Type)
+
+	cycloneDx := `{"bomFormat":"CycloneDX","specVersion":"1.4","components":[
+		{"type":"library","name":"express","version":"4.17.1","purl":"pkg:npm/express@4.17.1","components":[{"type":"library","name":"qs","version":"6.7.0"}]}]}`
+	specVersion, components, err = parseSbomDocument(security.SbomFormat_CYCLONEDX, []byte(cycloneDx))
+	assert.Nil(t, err)
+	assert.Equal(t, "1.4", specVersion)
+	assert.Equal(t, 2, len(components))
+	assert.Equal(t, "npm", components[0].Type)
+	assert.Equal(t, "library", components[1].Type)
+
+	_, _, err = parseSbomDocument(security.SbomFormat_CYCLONEDX, []byte(`{"bomFormat":"SPDX"}`))
+	assert.NotNil(t, err)
+	_, _, err = parseSbomDocument("syft", []byte(`{}`))
+	assert.NotNil(t, err)
+}
+
+func TestCheckSbomOverwrite(t *testing.T) {
+	assert.Nil(t, checkSbomOverwrite(&security.CiArtifactSbom{}, false))
+	assert.Nil(t, checkSbomOverwrite(&security.CiArtifactSbom{Id: 1}, false))
+	assert.Nil(t, checkSbomOverwrite(&security.CiArtifactSbom{Id: 1, Generated: true}, true))
+
+	err := checkSbomOverwrite(&security.CiArtifactSbom{Id: 1, Format: security.SbomFormat_SPDX, Generated: true}, false)
+	apiErr, ok := err.(*util.ApiError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, apiErr.HttpStatusCode)
+}
+
+type fakeSbomRepository struct {
+	security.CiArtifactSbomRepository
+	sboms     []*security.CiArtifactSbom
+	exposures []*security.SbomComponentExposure
+	queries   int
+}
+
+func (f *fakeSbomRepository) FindByCiArtifactId(ciArtifactId int) ([]*security.CiArtifactSbom, error) {
+	return f.sboms, nil
+}
+
+func (f *fakeSbomRepository) SearchDeployedComponents(request *security.SbomComponentSearchRequest, offset int, limit int) ([]*security.SbomComponentExposure, error) {
+	f.queries++
+	if offset >= len(f.exposures) {
+		return nil, nil
+	}
+	exposures := f.exposures[offset:]
+	if limit < len(exposures) {
+		exposures = exposures[:limit]
+	}
+	return exposures, nil
+}
+
+type fakeSbomArtifactRepository struct {
+	repository.CiArtifactRepository
+}
+
+func (f *fakeSbomArtifactRepository) Get(id int) (*repository.CiArtifact, error) {
+	return &repository.CiArtifact{Id: id}, nil
+}
+
+func TestSbomService_FetchSboms(t *testing.T) {
+	sbomRepository := &fakeSbomRepository{sboms: []*security.CiArtifactSbom{
+		{Id: 1, CiArtifactId: 5, Format: security.SbomFormat_CYCLONEDX, Generated: true},
+		{Id: 2, CiArtifactId: 5, Format: security.SbomFormat_SPDX},
+	}}
+	impl := NewSbomServiceImpl(zap.NewNop().Sugar(), sbomRepository, &fakeSbomArtifactRepository{}, nil, nil, nil, &SbomConfig{})
+
+	sboms, err := impl.FetchSboms(5)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sboms))
+	assert.True(t, sboms[0].Generated)
+	assert.False(t, sboms[1].Generated)
+}
+
+func TestSbomService_SearchComponents(t *testing.T) {
+	var exposures []*security.SbomComponentExposure
+	for i := 1; i <= 250; i++ {
+		exposures = append(exposures, &security.SbomComponentExposure{ImageScanDeployInfoId: i, AppId: i})
+	}
+	// apps with even id are visible to the user
+	isVisible := func(exposure *security.SbomComponentExposure) bool {
+		return exposure.AppId%2 == 0
+	}
+	getAppIds := func(exposures []*security.SbomComponentExposure) []int {
+		appIds := make([]int, 0)
+		for _, exposure := range exposures {
+			appIds = append(appIds, exposure.AppId)
+		}
+		return appIds
+	}
+
+	sbomRepository := &fakeSbomRepository{exposures: exposures}
+	impl := NewSbomServiceImpl(zap.NewNop().Sugar(), sbomRepository, nil, nil, nil, nil, &SbomConfig{})
+	page, err := impl.SearchComponents(&security.SbomComponentSearchRequest{Name: "log4j", Offset: 1, Size: 3}, isVisible)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{4, 6, 8}, getAppIds(page))
+	assert.Equal(t, 1, sbomRepository.queries)
+
+	// page is filled from later batches
+	sbomRepository.queries = 0
+	page, err = impl.SearchComponents(&security.SbomComponentSearchRequest{Name: "log4j", Offset: 60, Size: 5}, isVisible)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{122, 124, 126, 128, 130}, getAppIds(page))
+	assert.Equal(t, 2, sbomRepository.queries)
+
+	page, err = impl.SearchComponents(&security.SbomComponentSearchRequest{Name: "log4j", Offset: 120}, isVisible)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{242, 244, 246, 248, 250}, getAppIds(page))
+
+	page, err = impl.SearchComponents(&security.SbomComponentSearchRequest{Name: "log4j"}, func(exposure *security.SbomComponentExposure) bool { return false })
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(page))
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/security"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
 )
@@ -48,6 +49,13 @@ type PolicyService interface {
 	GetCvePolicy(id int, userId int32) (*security.CvePolicy, error)
 	GetApplicablePolicy(clusterId, envId, appId int, isAppstore bool) (map[string]*security.CvePolicy, map[security.Severity]*security.CvePolicy, error)
 	HasBlockedCVE(cves []*security.CveStore, cvePolicy map[string]*security.CvePolicy, severityPolicy map[security.Severity]*security.CvePolicy) bool
+	// GetExpiringExceptions returns the exceptions in effect which expire before the given time
+	GetExpiringExceptions(expiresBefore time.Time) ([]*bean.CveExceptionDetail, error)
+	MarkExceptionsExpiryNotified(ids []int) error
+	GetPendingExceptions() ([]*bean.CveExceptionDetail, error)
+	// ReviewException approves or rejects a pending exception, the requester of an exception can not review it
+	ReviewException(request *bean.ReviewCveExceptionRequest, userId int32) (*bean.IdVulnerabilityPolicyResult, error)
+	SendEventToClairUtility(event *ScanEvent) error
 }
 type PolicyServiceImpl struct {
 	environmentService            cluster.EnvironmentService
@@ -66,6 +74,7 @@ type PolicyServiceImpl struct {
 	scanHistoryRepository         security.ImageScanHistoryRepository
 	cveStoreRepository            security.CveStoreRepository
 	ciTemplateRepository          pipelineConfig.CiTemplateRepository
+	userRepository                repository2.UserRepository
 }
 
 func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
@@ -80,7 +89,7 @@ func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
 	imageScanObjectMetaRepository security.ImageScanObjectMetaRepository, client *http.Client,
 	ciArtifactRepository repository.CiArtifactRepository, ciConfig *pipeline.CiConfig,
 	scanHistoryRepository security.ImageScanHistoryRepository, cveStoreRepository security.CveStoreRepository,
-	ciTemplateRepository pipelineConfig.CiTemplateRepository) *PolicyServiceImpl {
+	ciTemplateRepository pipelineConfig.CiTemplateRepository, userRepository repository2.UserRepository) *PolicyServiceImpl {
 	return &PolicyServiceImpl{
 		environmentService:            environmentService,
 		logger:                        logger,
@@ -98,6 +107,7 @@ func NewPolicyServiceImpl(environmentService cluster.EnvironmentService,
 		scanHistoryRepository:         scanHistoryRepository,
 		cveStoreRepository:            cveStoreRepository,
 		ciTemplateRepository:          ciTemplateRepository,
+		userRepository:                userRepository,
 	}
 }
 
@@ -473,6 +483,12 @@ func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicy
 		}
 		severity = cveStore.Severity
 	}
+	if request.ExpiresOn != nil {
+		err = impl.validateException(request, action)
+		if err != nil {
+			return nil, err
+		}
+	}
 	policy := &security.CvePolicy{
 		Global:        isGlobal,
 		ClusterId:     request.ClusterId,
@@ -488,6 +504,11 @@ func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicy
 			UpdatedBy: userId,
 		},
 	}
+	if request.ExpiresOn != nil {
+		policy.ExpiresOn = *request.ExpiresOn
+		policy.Justification = request.Justification
+		policy.ApprovalStatus = security.CVE_EXCEPTION_PENDING
+	}
 	policy, err = impl.cvePolicyRepository.SavePolicy(policy)
 	if err != nil {
 		impl.logger.Errorw("error in saving policy", "err", err)
@@ -496,6 +517,65 @@ func (impl *PolicyServiceImpl) SavePolicy(request bean.CreateVulnerabilityPolicy
 	return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
 }
 
+// validateException checks that an exception allows a single cve until a future date and is justified
+func (impl *PolicyServiceImpl) validateException(request bean.CreateVulnerabilityPolicyRequest, action security.PolicyAction) error {
+	if len(request.CveId) == 0 || len(request.Severity) > 0 {
+		return fmt.Errorf("exception can only be created for a cve")
+	}
+	if action != security.Allow {
+		return fmt.Errorf("exception can only allow a cve")
+	}
+	if !request.ExpiresOn.After(time.Now()) {
+		return fmt.Errorf("expiry of exception must be in future")
+	}
+	if len(strings.TrimSpace(request.Justification)) == 0 {
+		return fmt.Errorf("justification is mandatory for exception")
+	}
+	return nil
+}
+
+func (impl *PolicyServiceImpl) ReviewException(request *bean.ReviewCveExceptionRequest, userId int32) (*bean.IdVulnerabilityPolicyResult, error) {
+	policy, err := impl.cvePolicyRepository.GetById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching policy ", "id", request.Id)
+		return nil, err
+	}
+	err = validateExceptionReview(policy, userId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	status := security.CVE_EXCEPTION_REJECTED
+	if request.Approve {
+		status = security.CVE_EXCEPTION_APPROVED
+	}
+	reviewed, err := impl.cvePolicyRepository.ReviewException(policy.Id, status, userId)
+	if err != nil {
+		impl.logger.Errorw("error in reviewing exception", "err", err, "id", policy.Id)
+		return nil, err
+	}
+	if !reviewed {
+		return nil, fmt.Errorf("exception is already reviewed")
+	}
+	return &bean.IdVulnerabilityPolicyResult{Id: policy.Id}, nil
+}
+
+// validateExceptionReview checks that a pending exception in effect is reviewed by someone other than its requester
+func validateExceptionReview(policy *security.CvePolicy, userId int32, now time.Time) error {
+	if policy.Deleted || !policy.IsException() {
+		return fmt.Errorf("policy %d is not an exception", policy.Id)
+	}
+	if policy.ApprovalStatus != security.CVE_EXCEPTION_PENDING {
+		return fmt.Errorf("exception is already reviewed")
+	}
+	if !policy.ExpiresOn.After(now) {
+		return fmt.Errorf("exception has expired")
+	}
+	if policy.CreatedBy == userId {
+		return fmt.Errorf("exception can not be reviewed by the requester")
+	}
+	return nil
+}
+
 /*
   1. policy id
   2. action
@@ -513,6 +593,9 @@ func (impl *PolicyServiceImpl) UpdatePolicy(updatePolicyParams bean.UpdatePolicy
 			impl.logger.Errorw("error in fetching policy ", "id", updatePolicyParams.Id)
 			return nil, err
 		}
+		if policy.IsException() && policyAction != security.Allow {
+			return nil, fmt.Errorf("exception can only allow a cve, change it to inherit to revoke it")
+		}
 		policy.Action = policyAction
 		policy.UpdatedOn = time.Now()
 		policy.UpdatedBy = userId
@@ -671,6 +754,13 @@ func (impl *PolicyServiceImpl) vulnerabilityPolicyBuilder(policyLevel security.P
 			},
 			Name: v.CVEStoreId,
 		}
+		if v.IsException() {
+			expiresOn := v.ExpiresOn
+			cvePolicy.ExpiresOn = &expiresOn
+			cvePolicy.Justification = v.Justification
+			cvePolicy.ApprovalStatus = string(v.ApprovalStatus)
+			cvePolicy.ApprovedBy = v.ApprovedBy
+		}
 		vulnerabilityPolicy.Cves = append(vulnerabilityPolicy.Cves, cvePolicy)
 	}
 	return vulnerabilityPolicy
@@ -737,3 +827,97 @@ func (impl *PolicyServiceImpl) GetCvePolicy(id int, userId int32) (*security.Cve
 	}
 	return policy, nil
 }
+
+func (impl *PolicyServiceImpl) GetExpiringExceptions(expiresBefore time.Time) ([]*bean.CveExceptionDetail, error) {
+	policies, err := impl.cvePolicyRepository.GetExceptionsExpiringBefore(expiresBefore)
+	if err != nil {
+		impl.logger.Errorw("error in fetching expiring exceptions", "err", err)
+		return nil, err
+	}
+	return impl.getExceptionDetails(policies)
+}
+
+func (impl *PolicyServiceImpl) GetPendingExceptions() ([]*bean.CveExceptionDetail, error) {
+	policies, err := impl.cvePolicyRepository.GetPendingExceptions()
+	if err != nil {
+		impl.logger.Errorw("error in fetching pending exceptions", "err", err)
+		return nil, err
+	}
+	return impl.getExceptionDetails(policies)
+}
+
+func (impl *PolicyServiceImpl) getExceptionDetails(policies []*security.CvePolicy) ([]*bean.CveExceptionDetail, error) {
+	var userIds []int32
+	for _, policy := range policies {
+		userIds = append(userIds, policy.ApprovedBy, policy.CreatedBy)
+	}
+	emails := make(map[int32]string)
+	if len(userIds) > 0 {
+		users, err := impl.userRepository.GetByIds(userIds)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching users of exceptions", "err", err)
+			return nil, err
+		}
+		for _, user := range users {
+			emails[user.Id] = user.EmailId
+		}
+	}
+	var exceptions []*bean.CveExceptionDetail
+	for _, policy := range policies {
+		exception := &bean.CveExceptionDetail{
+			Id:             policy.Id,
+			CveName:        policy.CVEStoreId,
+			PolicyOrigin:   policy.PolicyLevel().String(),
+			ClusterId:      policy.ClusterId,
+			EnvId:          policy.EnvironmentId,
+			AppId:          policy.AppId,
+			Justification:  policy.Justification,
+			ApprovalStatus: string(policy.ApprovalStatus),
+			ApprovedBy:     emails[policy.ApprovedBy],
+			CreatedBy:      emails[policy.CreatedBy],
+			ExpiresOn:      policy.ExpiresOn,
+			ExpiryNotified: policy.ExpiryNotified,
+		}
+		if policy.Severity != nil {
+			exception.Severity = policy.Severity.String()
+		}
+		if policy.ClusterId > 0 {
+			cluster, err := impl.clusterService.FindById(policy.ClusterId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching cluster of exception", "err", err, "clusterId", policy.ClusterId)
+				return nil, err
+			}
+			exception.ClusterName = cluster.ClusterName
+		}
+		if policy.EnvironmentId > 0 {
+			env, err := impl.environmentService.FindById(policy.EnvironmentId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching environment of exception", "err", err, "envId", policy.EnvironmentId)
+				return nil, err
+			}
+			exception.EnvName = env.Environment
+		}
+		if policy.AppId > 0 {
+			app, err := impl.apRepository.FindById(policy.AppId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching app of exception", "err", err, "appId", policy.AppId)
+				return nil, err
+			}
+			exception.AppName = app.AppName
+			exception.TeamId = app.TeamId
+		}
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions, nil
+}
+
+func (impl *PolicyServiceImpl) MarkExceptionsExpiryNotified(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := impl.cvePolicyRepository.MarkExpiryNotified(ids)
+	if err != nil {
+		impl.logger.Errorw("error in marking exceptions expiry notified", "err", err, "ids", ids)
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/security"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExceptionReview(t *testing.T) {
+	now := time.Now()
+	pending := &security.CvePolicy{
+		Id:             1,
+		CVEStoreId:     "CVE-2021-44228",
+		ExpiresOn:      now.Add(24 * time.Hour),
+		ApprovalStatus: security.CVE_EXCEPTION_PENDING,
+		AuditLog:       sql.AuditLog{CreatedBy: 2},
+	}
+	assert.Nil(t, validateExceptionReview(pending, 3, now))
+	// requester can not approve own exception
+	assert.NotNil(t, validateExceptionReview(pending, 2, now))
+	// expired exceptions can not be approved
+	assert.NotNil(t, validateExceptionReview(pending, 3, now.Add(48*time.Hour)))
+
+	approved := *pending
+	approved.ApprovalStatus = security.CVE_EXCEPTION_APPROVED
+	assert.NotNil(t, validateExceptionReview(&approved, 3, now))
+
+	policy := &security.CvePolicy{Id: 2, CVEStoreId: "CVE-2021-44228", AuditLog: sql.AuditLog{CreatedBy: 2}}
+	assert.NotNil(t, validateExceptionReview(policy, 3, now))
+}
@@ -19,11 +19,15 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/pkg/auditLog/siem"
 	serverBean "github.com/devtron-labs/devtron/pkg/server/bean"
 	serverEnvConfig "github.com/devtron-labs/devtron/pkg/server/config"
 	serverDataStore "github.com/devtron-labs/devtron/pkg/server/store"
+	"github.com/devtron-labs/devtron/pkg/user/repository"
 	util2 "github.com/devtron-labs/devtron/util"
 	"go.uber.org/zap"
 	"time"
@@ -40,17 +44,47 @@ type ServerServiceImpl struct {
 	serverDataStore                *serverDataStore.ServerDataStore
 	serverEnvConfig                *serverEnvConfig.ServerEnvConfig
 	helmAppService                 client.HelmAppService
+	siemExportService              siem.SiemExportService
+	userRepository                 repository.UserRepository
 }
 
+const SIEM_RESOURCE_TYPE_SERVER = "server"
+
 func NewServerServiceImpl(logger *zap.SugaredLogger, serverActionAuditLogRepository ServerActionAuditLogRepository,
-	serverDataStore *serverDataStore.ServerDataStore, serverEnvConfig *serverEnvConfig.ServerEnvConfig, helmAppService client.HelmAppService) *ServerServiceImpl {
+	serverDataStore *serverDataStore.ServerDataStore, serverEnvConfig *serverEnvConfig.ServerEnvConfig, helmAppService client.HelmAppService,
+	siemExportService siem.SiemExportService, userRepository repository.UserRepository) *ServerServiceImpl {
 	return &ServerServiceImpl{
 		logger:                         logger,
 		serverActionAuditLogRepository: serverActionAuditLogRepository,
 		serverDataStore:                serverDataStore,
 		serverEnvConfig:                serverEnvConfig,
 		helmAppService:                 helmAppService,
+		siemExportService:              siemExportService,
+		userRepository:                 userRepository,
+	}
+}
+
+// toSiemEvent maps the server action audit log to siem event, version requested is sent as diff
+func (impl ServerServiceImpl) toSiemEvent(serverActionAuditLog *ServerActionAuditLog) *siem.SiemEvent {
+	event := &siem.SiemEvent{
+		Id:           fmt.Sprintf("%s-%d", siem.CATEGORY_SERVER_ACTION, serverActionAuditLog.Id),
+		Category:     siem.CATEGORY_SERVER_ACTION,
+		Action:       serverActionAuditLog.Action,
+		ActorId:      serverActionAuditLog.CreatedBy,
+		ResourceType: SIEM_RESOURCE_TYPE_SERVER,
+		ResourceName: impl.serverEnvConfig.DevtronHelmReleaseName,
+		CreatedOn:    serverActionAuditLog.CreatedOn,
+	}
+	user, err := impl.userRepository.GetByIdIncludeDeleted(serverActionAuditLog.CreatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user for siem event", "userId", serverActionAuditLog.CreatedBy, "err", err)
+	} else {
+		event.ActorEmail = user.EmailId
+	}
+	if diff, err := json.Marshal(map[string]string{"version": serverActionAuditLog.Version}); err == nil {
+		event.Diff = string(diff)
 	}
+	return event
 }
 
 func (impl ServerServiceImpl) GetServerInfo() (*serverBean.ServerInfoDto, error) {
@@ -123,6 +157,7 @@ func (impl ServerServiceImpl) HandleServerAction(userId int32, serverActionReque
 		impl.logger.Errorw("error in saving into audit log for server action ", "err", err)
 		return nil, err
 	}
+	impl.siemExportService.Export(impl.toSiemEvent(serverActionAuditLog))
 
 	// HELM_OPERATION Starts
 	devtronHelmAppIdentifier := impl.helmAppService.GetDevtronHelmAppIdentifier()
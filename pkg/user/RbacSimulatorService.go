@@ -0,0 +1,220 @@
+package user
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"strings"
+)
+
+type RbacSimulatorService interface {
+	// ExplainPermission tells whether the user or role group is allowed the action on the resource object and lists the
+	// policy lines deciding it along with the roles and role groups granting them
+	ExplainPermission(request *bean.RbacExplainRequest) (*bean.RbacExplainResponse, error)
+	// GetPermittedUsers returns the active users and api-tokens allowed the action on both app and env objects
+	GetPermittedUsers(action string, appObject string, envObject string) ([]*bean.RbacPermittedUser, error)
+}
+
+type RbacSimulatorServiceImpl struct {
+	logger              *zap.SugaredLogger
+	enforcer            casbin2.Enforcer
+	userRepository      repository2.UserRepository
+	userAuthRepository  repository2.UserAuthRepository
+	roleGroupRepository repository2.RoleGroupRepository
+}
+
+func NewRbacSimulatorServiceImpl(logger *zap.SugaredLogger, enforcer casbin2.Enforcer,
+	userRepository repository2.UserRepository, userAuthRepository repository2.UserAuthRepository,
+	roleGroupRepository repository2.RoleGroupRepository) *RbacSimulatorServiceImpl {
+	return &RbacSimulatorServiceImpl{
+		logger:              logger,
+		enforcer:            enforcer,
+		userRepository:      userRepository,
+		userAuthRepository:  userAuthRepository,
+		roleGroupRepository: roleGroupRepository,
+	}
+}
+
+type rbacCheck struct {
+	resource string
+	action   string
+	object   string
+}
+
+func (impl RbacSimulatorServiceImpl) ExplainPermission(request *bean.RbacExplainRequest) (*bean.RbacExplainResponse, error) {
+	subject, err := impl.getSubject(request)
+	if err != nil {
+		return nil, err
+	}
+	explanations := casbin2.ExplainPolicies(subject, request.Resource, request.Action, request.Object)
+	policies, err := impl.buildPolicyExplanations(explanations)
+	if err != nil {
+		return nil, err
+	}
+	return &bean.RbacExplainResponse{
+		Subject:  subject,
+		Allowed:  casbin2.IsAllowed(explanations),
+		Policies: policies,
+	}, nil
+}
+
+func (impl RbacSimulatorServiceImpl) GetPermittedUsers(action string, appObject string, envObject string) ([]*bean.RbacPermittedUser, error) {
+	var checks []rbacCheck
+	switch action {
+	case bean.RBAC_PERMITTED_ACTION_TRIGGER:
+		checks = []rbacCheck{
+			{resource: casbin2.ResourceApplications, action: casbin2.ActionTrigger, object: appObject},
+			{resource: casbin2.ResourceEnvironment, action: casbin2.ActionTrigger, object: envObject},
+		}
+	case bean.RBAC_PERMITTED_ACTION_EDIT:
+		checks = []rbacCheck{
+			{resource: casbin2.ResourceApplications, action: casbin2.ActionUpdate, object: appObject},
+			{resource: casbin2.ResourceEnvironment, action: casbin2.ActionUpdate, object: envObject},
+		}
+	default:
+		return nil, fmt.Errorf("action '%s' is not supported", action)
+	}
+
+	users, err := impl.userRepository.GetAllActive()
+	if err != nil {
+		impl.logger.Errorw("error while fetching active users", "err", err)
+		return nil, err
+	}
+	permittedUsers := make([]*bean.RbacPermittedUser, 0)
+	for _, user := range users {
+		emailId := strings.ToLower(user.EmailId)
+		allowed := true
+		for _, check := range checks {
+			if !impl.enforcer.EnforceByEmail(emailId, check.resource, check.action, check.object) {
+				allowed = false
+				break
+			}
+		}
+		if !allowed {
+			continue
+		}
+		permittedUser := &bean.RbacPermittedUser{
+			UserId:     user.Id,
+			EmailId:    user.EmailId,
+			IsApiToken: user.UserType == bean.USER_TYPE_API_TOKEN,
+		}
+		grantedVia := make(map[string]bool)
+		for _, check := range checks {
+			for _, explanation := range casbin2.ExplainPolicies(emailId, check.resource, check.action, check.object) {
+				for _, role := range explanation.GrantedVia {
+					if !grantedVia[role] {
+						grantedVia[role] = true
+						permittedUser.GrantedVia = append(permittedUser.GrantedVia, role)
+					}
+				}
+			}
+		}
+		permittedUser.RoleGroups, err = impl.getRoleGroupNames(permittedUser.GrantedVia)
+		if err != nil {
+			return nil, err
+		}
+		permittedUsers = append(permittedUsers, permittedUser)
+	}
+	return permittedUsers, nil
+}
+
+// getSubject returns the casbin subject of the user or role group in request
+func (impl RbacSimulatorServiceImpl) getSubject(request *bean.RbacExplainRequest) (string, error) {
+	if len(request.EmailId) > 0 && len(request.GroupName) > 0 {
+		return "", fmt.Errorf("only one of emailId and groupName is allowed")
+	}
+	if len(request.EmailId) > 0 {
+		user, err := impl.userRepository.FetchActiveUserByEmail(request.EmailId)
+		if err != nil {
+			impl.logger.Errorw("error while fetching user by email", "emailId", request.EmailId, "err", err)
+			return "", err
+		}
+		if user.Id == 0 {
+			return "", fmt.Errorf("no active user found for emailId '%s'", request.EmailId)
+		}
+		return strings.ToLower(user.EmailId), nil
+	}
+	if len(request.GroupName) > 0 {
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupByName(request.GroupName)
+		if err != nil {
+			impl.logger.Errorw("error while fetching role group by name", "groupName", request.GroupName, "err", err)
+			if err == pg.ErrNoRows {
+				return "", fmt.Errorf("no role group found for groupName '%s'", request.GroupName)
+			}
+			return "", err
+		}
+		return roleGroup.CasbinName, nil
+	}
+	return "", fmt.Errorf("either emailId or groupName is required")
+}
+
+func (impl RbacSimulatorServiceImpl) buildPolicyExplanations(explanations []*casbin2.PolicyExplanation) ([]*bean.RbacPolicyExplanation, error) {
+	var roleNames []string
+	for _, explanation := range explanations {
+		roleNames = append(roleNames, explanation.Sub)
+	}
+	rolesByName := make(map[string]*bean.RoleData)
+	if len(roleNames) > 0 {
+		roles, err := impl.userAuthRepository.GetRoleByRoles(roleNames)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error while fetching roles", "roles", roleNames, "err", err)
+			return nil, err
+		}
+		for _, role := range roles {
+			rolesByName[role.Role] = &bean.RoleData{
+				Id:          role.Id,
+				Role:        role.Role,
+				Entity:      role.Entity,
+				Team:        role.Team,
+				EntityName:  role.EntityName,
+				Environment: role.Environment,
+				Action:      role.Action,
+				AccessType:  role.AccessType,
+			}
+		}
+	}
+	policies := make([]*bean.RbacPolicyExplanation, 0)
+	for _, explanation := range explanations {
+		roleGroups, err := impl.getRoleGroupNames(explanation.GrantedVia)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, &bean.RbacPolicyExplanation{
+			Subject:    explanation.Sub,
+			Resource:   explanation.Res,
+			Action:     explanation.Act,
+			Object:     explanation.Obj,
+			Effect:     explanation.Eft,
+			GrantedVia: explanation.GrantedVia,
+			RoleGroups: roleGroups,
+			Role:       rolesByName[explanation.Sub],
+		})
+	}
+	return policies, nil
+}
+
+// getRoleGroupNames returns the names of role groups among the casbin subjects
+func (impl RbacSimulatorServiceImpl) getRoleGroupNames(subjects []string) ([]string, error) {
+	var casbinNames []string
+	for _, subject := range subjects {
+		if strings.HasPrefix(subject, "group:") {
+			casbinNames = append(casbinNames, subject)
+		}
+	}
+	if len(casbinNames) == 0 {
+		return nil, nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByCasbinNames(casbinNames)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while fetching role groups", "casbinNames", casbinNames, "err", err)
+		return nil, err
+	}
+	var names []string
+	for _, roleGroup := range roleGroups {
+		names = append(names, roleGroup.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,272 @@
+package user
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"go.uber.org/zap"
+)
+
+type RoleGrantExpiryService interface {
+	// GetExpiringRoleGrants returns the time-bound role grants of users and role groups expiring before the given time
+	// whose expiry is not notified yet
+	GetExpiringRoleGrants(expiresBefore time.Time) ([]*bean.RoleGrantExpiry, error)
+	MarkRoleGrantsExpiryNotified(grants []*bean.RoleGrantExpiry) error
+	// RevokeExpiredRoleGrants removes the role mappings and casbin policies of the expired role grants and audits the revoke
+	RevokeExpiredRoleGrants() error
+}
+
+type RoleGrantExpiryServiceImpl struct {
+	logger              *zap.SugaredLogger
+	userAuthRepository  repository2.UserAuthRepository
+	userRepository      repository2.UserRepository
+	roleGroupRepository repository2.RoleGroupRepository
+	userCommonService   UserCommonService
+	userAuditService    UserAuditService
+}
+
+func NewRoleGrantExpiryServiceImpl(logger *zap.SugaredLogger, userAuthRepository repository2.UserAuthRepository,
+	userRepository repository2.UserRepository, roleGroupRepository repository2.RoleGroupRepository,
+	userCommonService UserCommonService, userAuditService UserAuditService) *RoleGrantExpiryServiceImpl {
+	return &RoleGrantExpiryServiceImpl{
+		logger:              logger,
+		userAuthRepository:  userAuthRepository,
+		userRepository:      userRepository,
+		roleGroupRepository: roleGroupRepository,
+		userCommonService:   userCommonService,
+		userAuditService:    userAuditService,
+	}
+}
+
+func (impl RoleGrantExpiryServiceImpl) GetExpiringRoleGrants(expiresBefore time.Time) ([]*bean.RoleGrantExpiry, error) {
+	var grants []*bean.RoleGrantExpiry
+	userRoleModels, err := impl.userAuthRepository.GetUserRoleMappingsExpiringBefore(expiresBefore)
+	if err != nil {
+		impl.logger.Errorw("error in fetching expiring user roles", "err", err)
+		return nil, err
+	}
+	for _, userRoleModel := range userRoleModels {
+		if userRoleModel.ExpiryNotified {
+			continue
+		}
+		user, err := impl.userRepository.GetById(userRoleModel.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching user of expiring role", "userId", userRoleModel.UserId, "err", err)
+			continue
+		}
+		role, err := impl.getRoleData(userRoleModel.RoleId)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, &bean.RoleGrantExpiry{
+			UserRoleId: userRoleModel.Id,
+			UserId:     user.Id,
+			Role:       role,
+			ExpiresOn:  userRoleModel.ExpiresOn,
+			EmailIds:   []string{user.EmailId},
+		})
+	}
+
+	roleGroupMappingModels, err := impl.roleGroupRepository.GetRoleGroupRoleMappingsExpiringBefore(expiresBefore)
+	if err != nil {
+		impl.logger.Errorw("error in fetching expiring role group roles", "err", err)
+		return nil, err
+	}
+	for _, roleGroupMappingModel := range roleGroupMappingModels {
+		if roleGroupMappingModel.ExpiryNotified {
+			continue
+		}
+		roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(roleGroupMappingModel.RoleGroupId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching role group of expiring role", "roleGroupId", roleGroupMappingModel.RoleGroupId, "err", err)
+			continue
+		}
+		role, err := impl.getRoleData(roleGroupMappingModel.RoleId)
+		if err != nil {
+			return nil, err
+		}
+		members, err := casbin2.GetUserByRole(roleGroup.CasbinName)
+		if err != nil {
+			impl.logger.Errorw("error in fetching members of role group", "roleGroup", roleGroup.Name, "err", err)
+			return nil, err
+		}
+		grants = append(grants, &bean.RoleGrantExpiry{
+			RoleGroupRoleMappingId: roleGroupMappingModel.Id,
+			RoleGroupId:            roleGroup.Id,
+			RoleGroupName:          roleGroup.Name,
+			Role:                   role,
+			ExpiresOn:              roleGroupMappingModel.ExpiresOn,
+			EmailIds:               members,
+		})
+	}
+	return grants, nil
+}
+
+func (impl RoleGrantExpiryServiceImpl) MarkRoleGrantsExpiryNotified(grants []*bean.RoleGrantExpiry) error {
+	var userRoleIds []int
+	var roleGroupRoleMappingIds []int
+	for _, grant := range grants {
+		if grant.UserRoleId > 0 {
+			userRoleIds = append(userRoleIds, grant.UserRoleId)
+		} else if grant.RoleGroupRoleMappingId > 0 {
+			roleGroupRoleMappingIds = append(roleGroupRoleMappingIds, grant.RoleGroupRoleMappingId)
+		}
+	}
+	err := impl.userAuthRepository.MarkUserRoleMappingsExpiryNotified(userRoleIds)
+	if err != nil {
+		impl.logger.Errorw("error in marking user roles expiry notified", "ids", userRoleIds, "err", err)
+		return err
+	}
+	err = impl.roleGroupRepository.MarkRoleGroupRoleMappingsExpiryNotified(roleGroupRoleMappingIds)
+	if err != nil {
+		impl.logger.Errorw("error in marking role group roles expiry notified", "ids", roleGroupRoleMappingIds, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl RoleGrantExpiryServiceImpl) RevokeExpiredRoleGrants() error {
+	now := time.Now()
+	userRoleModels, err := impl.userAuthRepository.GetUserRoleMappingsExpiringBefore(now)
+	if err != nil {
+		impl.logger.Errorw("error in fetching expired user roles", "err", err)
+		return err
+	}
+	userRoleModelsByUserId := make(map[int32][]*repository2.UserRoleModel)
+	for _, userRoleModel := range userRoleModels {
+		userRoleModelsByUserId[userRoleModel.UserId] = append(userRoleModelsByUserId[userRoleModel.UserId], userRoleModel)
+	}
+	for userId, expiredUserRoleModels := range userRoleModelsByUserId {
+		err = impl.revokeUserRoles(userId, expiredUserRoleModels)
+		if err != nil {
+			impl.logger.Errorw("error in revoking expired user roles", "userId", userId, "err", err)
+		}
+	}
+
+	roleGroupMappingModels, err := impl.roleGroupRepository.GetRoleGroupRoleMappingsExpiringBefore(now)
+	if err != nil {
+		impl.logger.Errorw("error in fetching expired role group roles", "err", err)
+		return err
+	}
+	roleGroupMappingModelsByGroupId := make(map[int32][]*repository2.RoleGroupRoleMapping)
+	for _, roleGroupMappingModel := range roleGroupMappingModels {
+		roleGroupMappingModelsByGroupId[roleGroupMappingModel.RoleGroupId] = append(roleGroupMappingModelsByGroupId[roleGroupMappingModel.RoleGroupId], roleGroupMappingModel)
+	}
+	for roleGroupId, expiredRoleGroupMappingModels := range roleGroupMappingModelsByGroupId {
+		err = impl.revokeRoleGroupRoles(roleGroupId, expiredRoleGroupMappingModels)
+		if err != nil {
+			impl.logger.Errorw("error in revoking expired role group roles", "roleGroupId", roleGroupId, "err", err)
+		}
+	}
+	return nil
+}
+
+func (impl RoleGrantExpiryServiceImpl) revokeUserRoles(userId int32, expiredUserRoleModels []*repository2.UserRoleModel) error {
+	user, err := impl.userRepository.GetByIdIncludeDeleted(userId)
+	if err != nil {
+		return err
+	}
+	existingRoleIds := make(map[int]repository2.UserRoleModel)
+	eliminatedRoleIds := make(map[int]*repository2.UserRoleModel)
+	for _, userRoleModel := range expiredUserRoleModels {
+		existingRoleIds[userRoleModel.RoleId] = *userRoleModel
+		eliminatedRoleIds[userRoleModel.RoleId] = userRoleModel
+	}
+	tx, err := impl.userRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	// no role filters are kept, so all the expired roles are eliminated
+	userInfo := &bean.UserInfo{Id: user.Id, EmailId: user.EmailId}
+	eliminatedPolicies, err := impl.userCommonService.RemoveRolesAndReturnEliminatedPolicies(userInfo, existingRoleIds, eliminatedRoleIds, tx, "", allowAllManagerAuth)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	if len(eliminatedPolicies) > 0 {
+		casbin2.RemovePolicy(eliminatedPolicies)
+	}
+	for _, userRoleModel := range expiredUserRoleModels {
+		impl.saveRevokeAudit(&UserAudit{UserId: userId, RoleId: userRoleModel.RoleId, ExpiresOn: userRoleModel.ExpiresOn})
+	}
+	impl.logger.Infow("revoked expired roles of user", "userId", userId, "policies", eliminatedPolicies)
+	return nil
+}
+
+func (impl RoleGrantExpiryServiceImpl) revokeRoleGroupRoles(roleGroupId int32, expiredRoleGroupMappingModels []*repository2.RoleGroupRoleMapping) error {
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(roleGroupId)
+	if err != nil {
+		return err
+	}
+	existingRoles := make(map[int]*repository2.RoleGroupRoleMapping)
+	eliminatedRoles := make(map[int]*repository2.RoleGroupRoleMapping)
+	for _, roleGroupMappingModel := range expiredRoleGroupMappingModels {
+		existingRoles[roleGroupMappingModel.RoleId] = roleGroupMappingModel
+		eliminatedRoles[roleGroupMappingModel.RoleId] = roleGroupMappingModel
+	}
+	tx, err := impl.roleGroupRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	request := &bean.RoleGroup{Id: roleGroup.Id, Name: roleGroup.Name}
+	eliminatedPolicies, err := impl.userCommonService.RemoveRolesAndReturnEliminatedPoliciesForGroups(request, existingRoles, eliminatedRoles, tx, "", allowAllManagerAuth)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	if len(eliminatedPolicies) > 0 {
+		casbin2.RemovePolicy(eliminatedPolicies)
+	}
+	for _, roleGroupMappingModel := range expiredRoleGroupMappingModels {
+		impl.saveRevokeAudit(&UserAudit{RoleGroupId: roleGroupId, RoleId: roleGroupMappingModel.RoleId, ExpiresOn: roleGroupMappingModel.ExpiresOn})
+	}
+	impl.logger.Infow("revoked expired roles of role group", "roleGroupId", roleGroupId, "policies", eliminatedPolicies)
+	return nil
+}
+
+func (impl RoleGrantExpiryServiceImpl) saveRevokeAudit(userAudit *UserAudit) {
+	userAudit.Action = repository2.USER_AUDIT_ACTION_ROLE_REVOKE
+	userAudit.CreatedBy = bean.SYSTEM_USER_ID
+	userAudit.CreatedOn = time.Now()
+	err := impl.userAuditService.Save(userAudit)
+	if err != nil {
+		impl.logger.Errorw("error in saving role revoke audit", "userAudit", userAudit, "err", err)
+	}
+}
+
+func (impl RoleGrantExpiryServiceImpl) getRoleData(roleId int) (*bean.RoleData, error) {
+	role, err := impl.userAuthRepository.GetRoleById(roleId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching role", "roleId", roleId, "err", err)
+		return nil, err
+	}
+	return &bean.RoleData{
+		Id:          role.Id,
+		Role:        role.Role,
+		Entity:      role.Entity,
+		Team:        role.Team,
+		EntityName:  role.EntityName,
+		Environment: role.Environment,
+		Action:      role.Action,
+		AccessType:  role.AccessType,
+	}, nil
+}
+
+// allowAllManagerAuth is the manager auth of the expiry job. Manager auth checks that the user removing a role manages
+// the team of the role, the job has no token and acts as the system user, so it is allowed to remove every expired
+// role. It must only be passed for roles whose expiry was set by a user already authorised to grant them
+func allowAllManagerAuth(token string, object string) bool {
+	return true
+}
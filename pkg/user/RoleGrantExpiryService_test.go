@@ -0,0 +1,154 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/api/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeExpiryUserAuthRepository struct {
+	repository2.UserAuthRepository
+	userRoles   []*repository2.UserRoleModel
+	notifiedIds []int
+}
+
+func (f *fakeExpiryUserAuthRepository) GetUserRoleMappingsExpiringBefore(expiresBefore time.Time) ([]*repository2.UserRoleModel, error) {
+	var userRoles []*repository2.UserRoleModel
+	for _, userRole := range f.userRoles {
+		if userRole.ExpiresOn.Before(expiresBefore) {
+			userRoles = append(userRoles, userRole)
+		}
+	}
+	return userRoles, nil
+}
+
+func (f *fakeExpiryUserAuthRepository) MarkUserRoleMappingsExpiryNotified(ids []int) error {
+	f.notifiedIds = append(f.notifiedIds, ids...)
+	return nil
+}
+
+func (f *fakeExpiryUserAuthRepository) GetRoleById(id int) (*repository2.RoleModel, error) {
+	return &repository2.RoleModel{Id: id, Role: "role:admin_dev_app-one", Team: "dev"}, nil
+}
+
+type fakeExpiryUserRepository struct {
+	repository2.UserRepository
+	users        map[int32]*repository2.UserModel
+	dbConnection *pg.DB
+}
+
+func (f *fakeExpiryUserRepository) GetById(id int32) (*repository2.UserModel, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+	return nil, pg.ErrNoRows
+}
+
+func (f *fakeExpiryUserRepository) GetByIdIncludeDeleted(id int32) (*repository2.UserModel, error) {
+	return f.GetById(id)
+}
+
+func (f *fakeExpiryUserRepository) GetConnection() *pg.DB {
+	return f.dbConnection
+}
+
+type fakeExpiryRoleGroupRepository struct {
+	repository2.RoleGroupRepository
+	queried     bool
+	notifiedIds []int
+}
+
+func (f *fakeExpiryRoleGroupRepository) GetRoleGroupRoleMappingsExpiringBefore(expiresBefore time.Time) ([]*repository2.RoleGroupRoleMapping, error) {
+	f.queried = true
+	return nil, nil
+}
+
+func (f *fakeExpiryRoleGroupRepository) MarkRoleGroupRoleMappingsExpiryNotified(ids []int) error {
+	f.notifiedIds = append(f.notifiedIds, ids...)
+	return nil
+}
+
+type fakeExpiryUserAuditService struct {
+	UserAuditService
+	audits []*UserAudit
+}
+
+func (f *fakeExpiryUserAuditService) Save(userAudit *UserAudit) error {
+	f.audits = append(f.audits, userAudit)
+	return nil
+}
+
+func getTestRoleGrantExpiryService(userRoles []*repository2.UserRoleModel) (*RoleGrantExpiryServiceImpl, *fakeExpiryUserAuthRepository, *fakeExpiryRoleGroupRepository, *fakeExpiryUserAuditService) {
+	userAuthRepository := &fakeExpiryUserAuthRepository{userRoles: userRoles}
+	userRepository := &fakeExpiryUserRepository{users: map[int32]*repository2.UserModel{
+		5: {Id: 5, EmailId: "dev@example.com"},
+	}, dbConnection: pg.Connect(&pg.Options{Addr: "127.0.0.1:1", MaxRetries: 0})}
+	roleGroupRepository := &fakeExpiryRoleGroupRepository{}
+	userAuditService := &fakeExpiryUserAuditService{}
+	impl := NewRoleGrantExpiryServiceImpl(zap.NewNop().Sugar(), userAuthRepository, userRepository, roleGroupRepository, nil, userAuditService)
+	return impl, userAuthRepository, roleGroupRepository, userAuditService
+}
+
+func TestRoleGrantExpiryService_GetExpiringRoleGrants(t *testing.T) {
+	now := time.Now()
+	impl, _, _, _ := getTestRoleGrantExpiryService([]*repository2.UserRoleModel{
+		{Id: 1, UserId: 5, RoleId: 11, ExpiresOn: now.Add(time.Hour)},
+		// notified already
+		{Id: 2, UserId: 5, RoleId: 12, ExpiresOn: now.Add(2 * time.Hour), ExpiryNotified: true},
+		// user deleted
+		{Id: 3, UserId: 6, RoleId: 13, ExpiresOn: now.Add(2 * time.Hour)},
+		{Id: 4, UserId: 5, RoleId: 14, ExpiresOn: now.Add(48 * time.Hour)},
+	})
+
+	grants, err := impl.GetExpiringRoleGrants(now.Add(24 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(grants))
+	assert.Equal(t, 1, grants[0].UserRoleId)
+	assert.Equal(t, int32(5), grants[0].UserId)
+	assert.Equal(t, 11, grants[0].Role.Id)
+	assert.Equal(t, "dev", grants[0].Role.Team)
+	assert.Equal(t, []string{"dev@example.com"}, grants[0].EmailIds)
+}
+
+func TestRoleGrantExpiryService_MarkRoleGrantsExpiryNotified(t *testing.T) {
+	impl, userAuthRepository, roleGroupRepository, _ := getTestRoleGrantExpiryService(nil)
+
+	err := impl.MarkRoleGrantsExpiryNotified([]*bean.RoleGrantExpiry{{UserRoleId: 1}, {RoleGroupRoleMappingId: 7}, {UserRoleId: 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2}, userAuthRepository.notifiedIds)
+	assert.Equal(t, []int{7}, roleGroupRepository.notifiedIds)
+}
+
+func TestRoleGrantExpiryService_RevokeExpiredRoleGrants(t *testing.T) {
+	now := time.Now()
+	impl, _, roleGroupRepository, userAuditService := getTestRoleGrantExpiryService([]*repository2.UserRoleModel{
+		{Id: 1, UserId: 5, RoleId: 11, ExpiresOn: now.Add(-time.Minute)},
+		{Id: 2, UserId: 6, RoleId: 12, ExpiresOn: now.Add(-time.Minute)},
+		{Id: 3, UserId: 5, RoleId: 13, ExpiresOn: now.Add(time.Hour)},
+	})
+
+	// roles not removed as db is unreachable are not audited as revoked, the job continues with role groups
+	err := impl.RevokeExpiredRoleGrants()
+	assert.Nil(t, err)
+	assert.True(t, roleGroupRepository.queried)
+	assert.Empty(t, userAuditService.audits)
+}
+
+func TestRoleGrantExpiryService_saveRevokeAudit(t *testing.T) {
+	impl, _, _, userAuditService := getTestRoleGrantExpiryService(nil)
+	expiresOn := time.Now().Add(-time.Minute)
+
+	impl.saveRevokeAudit(&UserAudit{UserId: 5, RoleId: 11, ExpiresOn: expiresOn})
+	assert.Equal(t, 1, len(userAuditService.audits))
+	audit := userAuditService.audits[0]
+	assert.Equal(t, repository2.USER_AUDIT_ACTION_ROLE_REVOKE, audit.Action)
+	assert.Equal(t, bean.SYSTEM_USER_ID, audit.CreatedBy)
+	assert.Equal(t, int32(5), audit.UserId)
+	assert.Equal(t, expiresOn, audit.ExpiresOn)
+	assert.False(t, audit.CreatedOn.IsZero())
+}
@@ -49,17 +49,20 @@ type RoleGroupServiceImpl struct {
 	userRepository      repository2.UserRepository
 	roleGroupRepository repository2.RoleGroupRepository
 	userCommonService   UserCommonService
+	userAuditService    UserAuditService
 }
 
 func NewRoleGroupServiceImpl(userAuthRepository repository2.UserAuthRepository,
 	logger *zap.SugaredLogger, userRepository repository2.UserRepository,
-	roleGroupRepository repository2.RoleGroupRepository, userCommonService UserCommonService) *RoleGroupServiceImpl {
+	roleGroupRepository repository2.RoleGroupRepository, userCommonService UserCommonService,
+	userAuditService UserAuditService) *RoleGroupServiceImpl {
 	serviceImpl := &RoleGroupServiceImpl{
 		userAuthRepository:  userAuthRepository,
 		logger:              logger,
 		userRepository:      userRepository,
 		roleGroupRepository: roleGroupRepository,
 		userCommonService:   userCommonService,
+		userAuditService:    userAuditService,
 	}
 	cStore = sessions.NewCookieStore(randKey())
 	return serviceImpl
@@ -74,6 +77,11 @@ func (impl RoleGroupServiceImpl) CreateRoleGroup(request *bean.RoleGroup) (*bean
 	// Rollback tx on error.
 	defer tx.Rollback()
 
+	err = validateRoleFiltersExpiry(request.RoleFilters)
+	if err != nil {
+		return nil, err
+	}
+	var grantAudits []*UserAudit
 	if request.Id > 0 {
 		_, err := impl.roleGroupRepository.GetRoleGroupById(request.Id)
 		if err != nil {
@@ -84,8 +92,9 @@ func (impl RoleGroupServiceImpl) CreateRoleGroup(request *bean.RoleGroup) (*bean
 
 		//create new user in our db on d basis of info got from google api or hex. assign a basic role
 		model := &repository2.RoleGroup{
-			Name:        request.Name,
-			Description: request.Description,
+			Name:            request.Name,
+			Description:     request.Description,
+			ScimProvisioned: request.ScimProvisioned,
 		}
 		rgName := strings.ToLower(request.Name)
 		object := "group:" + strings.ReplaceAll(rgName, " ", "_")
@@ -179,7 +188,7 @@ func (impl RoleGroupServiceImpl) CreateRoleGroup(request *bean.RoleGroup) (*bean
 					}
 
 					if roleModel.Id > 0 {
-						roleGroupMappingModel := &repository2.RoleGroupRoleMapping{RoleGroupId: model.Id, RoleId: roleModel.Id}
+						roleGroupMappingModel := &repository2.RoleGroupRoleMapping{RoleGroupId: model.Id, RoleId: roleModel.Id, ExpiresOn: getRoleFilterExpiresOn(roleFilter)}
 						roleGroupMappingModel.CreatedBy = request.UserId
 						roleGroupMappingModel.UpdatedBy = request.UserId
 						roleGroupMappingModel.CreatedOn = time.Now()
@@ -189,6 +198,9 @@ func (impl RoleGroupServiceImpl) CreateRoleGroup(request *bean.RoleGroup) (*bean
 							return nil, err
 						}
 						policies = append(policies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(model.CasbinName), Obj: casbin2.Object(roleModel.Role)})
+						if !roleGroupMappingModel.ExpiresOn.IsZero() {
+							grantAudits = append(grantAudits, buildRoleGrantAudit(0, model.Id, roleModel.Id, roleGroupMappingModel.ExpiresOn, request.UserId))
+						}
 					}
 				}
 			}
@@ -204,6 +216,7 @@ func (impl RoleGroupServiceImpl) CreateRoleGroup(request *bean.RoleGroup) (*bean
 	if err != nil {
 		return nil, err
 	}
+	impl.saveUserAudits(grantAudits)
 	return request, nil
 }
 
@@ -216,6 +229,10 @@ func (impl RoleGroupServiceImpl) UpdateRoleGroup(request *bean.RoleGroup, token
 	// Rollback tx on error.
 	defer tx.Rollback()
 
+	err = validateRoleFiltersExpiry(request.RoleFilters)
+	if err != nil {
+		return nil, err
+	}
 	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(request.Id)
 	if err != nil {
 		impl.logger.Errorw("error while fetching user from db", "error", err)
@@ -259,6 +276,7 @@ func (impl RoleGroupServiceImpl) UpdateRoleGroup(request *bean.RoleGroup, token
 
 	//Adding New Policies
 	var policies []casbin2.Policy
+	var grantAudits []*UserAudit
 	for _, roleFilter := range request.RoleFilters {
 		if len(roleFilter.Team) > 0 {
 			// check auth only for apps permission, skip for chart group
@@ -336,13 +354,28 @@ func (impl RoleGroupServiceImpl) UpdateRoleGroup(request *bean.RoleGroup, token
 					}
 				}
 
-				if _, ok := existingRoles[roleModel.Id]; ok {
+				expiresOn := getRoleFilterExpiresOn(roleFilter)
+				if roleGroupMappingModel, ok := existingRoles[roleModel.Id]; ok {
 					//Adding policies which is removed
 					policies = append(policies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(roleGroup.CasbinName), Obj: casbin2.Object(roleModel.Role)})
+					if !roleGroupMappingModel.ExpiresOn.Equal(expiresOn) {
+						// expiry of an existing role is changed, it is notified again before the new expiry
+						roleGroupMappingModel.ExpiresOn = expiresOn
+						roleGroupMappingModel.ExpiryNotified = false
+						roleGroupMappingModel.UpdatedBy = request.UserId
+						roleGroupMappingModel.UpdatedOn = time.Now()
+						_, err = impl.roleGroupRepository.UpdateRoleGroupRoleMapping(roleGroupMappingModel, tx)
+						if err != nil {
+							return nil, err
+						}
+						if !expiresOn.IsZero() {
+							grantAudits = append(grantAudits, buildRoleGrantAudit(0, roleGroup.Id, roleModel.Id, expiresOn, request.UserId))
+						}
+					}
 				} else {
 					if roleModel.Id > 0 {
 						//new role ids in new array, add it
-						roleGroupMappingModel := &repository2.RoleGroupRoleMapping{RoleGroupId: request.Id, RoleId: roleModel.Id}
+						roleGroupMappingModel := &repository2.RoleGroupRoleMapping{RoleGroupId: request.Id, RoleId: roleModel.Id, ExpiresOn: expiresOn}
 						roleGroupMappingModel.CreatedBy = request.UserId
 						roleGroupMappingModel.UpdatedBy = request.UserId
 						roleGroupMappingModel.CreatedOn = time.Now()
@@ -352,6 +385,9 @@ func (impl RoleGroupServiceImpl) UpdateRoleGroup(request *bean.RoleGroup, token
 							return nil, err
 						}
 						policies = append(policies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(roleGroup.CasbinName), Obj: casbin2.Object(roleModel.Role)})
+						if !expiresOn.IsZero() {
+							grantAudits = append(grantAudits, buildRoleGrantAudit(0, roleGroup.Id, roleModel.Id, expiresOn, request.UserId))
+						}
 					}
 				}
 			}
@@ -367,6 +403,7 @@ func (impl RoleGroupServiceImpl) UpdateRoleGroup(request *bean.RoleGroup, token
 	if err != nil {
 		return nil, err
 	}
+	impl.saveUserAudits(grantAudits)
 
 	return request, nil
 }
@@ -384,6 +421,14 @@ func (impl RoleGroupServiceImpl) FetchRoleGroupsById(id int32) (*bean.RoleGroup,
 	if err != nil {
 		impl.logger.Errorw("No Roles Found for user", "roleGroupId", roleGroup.Id)
 	}
+	roleGroupMappingModels, err := impl.roleGroupRepository.GetRoleGroupRoleMappingByRoleGroupId(roleGroup.Id)
+	if err != nil {
+		impl.logger.Errorw("No Roles Found for user", "roleGroupId", roleGroup.Id)
+	}
+	expiresOnByRoleId := make(map[int]time.Time)
+	for _, roleGroupMappingModel := range roleGroupMappingModels {
+		expiresOnByRoleId[roleGroupMappingModel.RoleId] = roleGroupMappingModel.ExpiresOn
+	}
 	var roleFilters []bean.RoleFilter
 	roleFilterMap := make(map[string]*bean.RoleFilter)
 	for _, role := range roles {
@@ -393,6 +438,11 @@ func (impl RoleGroupServiceImpl) FetchRoleGroupsById(id int32) (*bean.RoleGroup,
 		} else if len(role.Entity) > 0 {
 			key = fmt.Sprintf("%s_%s", role.Entity, role.Action)
 		}
+		// roles expiring at different times are not merged in a role filter
+		expiresOn := expiresOnByRoleId[role.Id]
+		if !expiresOn.IsZero() {
+			key = fmt.Sprintf("%s_%d", key, expiresOn.Unix())
+		}
 		if _, ok := roleFilterMap[key]; ok {
 			envArr := strings.Split(roleFilterMap[key].Environment, ",")
 			if containsArr(envArr, AllEnvironment) {
@@ -412,6 +462,7 @@ func (impl RoleGroupServiceImpl) FetchRoleGroupsById(id int32) (*bean.RoleGroup,
 				EntityName:  role.EntityName,
 				Action:      role.Action,
 				AccessType:  role.AccessType,
+				ExpiresOn:   getExpiresOnPtr(expiresOn),
 			}
 		}
 	}
@@ -545,3 +596,13 @@ func (impl RoleGroupServiceImpl) FetchRolesForGroups(groupNames []string) ([]*be
 	}
 	return list, nil
 }
+
+// saveUserAudits saves the grant audits of time-bound roles, failure is logged as it must not fail the update
+func (impl RoleGroupServiceImpl) saveUserAudits(userAudits []*UserAudit) {
+	for _, userAudit := range userAudits {
+		err := impl.userAuditService.Save(userAudit)
+		if err != nil {
+			impl.logger.Errorw("error in saving user audit", "userAudit", userAudit, "err", err)
+		}
+	}
+}
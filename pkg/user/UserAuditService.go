@@ -18,16 +18,27 @@
 package user
 
 import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auditLog/siem"
 	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
 	"time"
 )
 
+const SIEM_RESOURCE_TYPE_USER = "user"
+
 type UserAudit struct {
 	UserId    int32
 	ClientIp  string
 	CreatedOn time.Time
+	// Action is login when empty, grant and revoke audits of time-bound roles have the role and its expiry
+	Action      string
+	RoleId      int
+	RoleGroupId int32
+	ExpiresOn   time.Time
+	CreatedBy   int32
 }
 
 type UserAuditService interface {
@@ -38,31 +49,93 @@ type UserAuditService interface {
 type UserAuditServiceImpl struct {
 	logger              *zap.SugaredLogger
 	userAuditRepository repository2.UserAuditRepository
+	userRepository      repository2.UserRepository
+	siemExportService   siem.SiemExportService
 }
 
-func NewUserAuditServiceImpl(logger *zap.SugaredLogger, userAuditRepository repository2.UserAuditRepository) *UserAuditServiceImpl {
+func NewUserAuditServiceImpl(logger *zap.SugaredLogger, userAuditRepository repository2.UserAuditRepository,
+	userRepository repository2.UserRepository, siemExportService siem.SiemExportService) *UserAuditServiceImpl {
 	return &UserAuditServiceImpl{
 		logger:              logger,
 		userAuditRepository: userAuditRepository,
+		userRepository:      userRepository,
+		siemExportService:   siemExportService,
 	}
 }
 
 func (impl UserAuditServiceImpl) Save(userAudit *UserAudit) error {
 	userId := userAudit.UserId
-	impl.logger.Infow("Saving user audit", "userId", userId)
+	action := userAudit.Action
+	if len(action) == 0 {
+		action = repository2.USER_AUDIT_ACTION_LOGIN
+	}
+	impl.logger.Infow("Saving user audit", "userId", userId, "action", action)
 	userAuditDb := &repository2.UserAudit{
-		UserId:    userId,
-		ClientIp:  userAudit.ClientIp,
-		CreatedOn: userAudit.CreatedOn,
+		UserId:      userId,
+		ClientIp:    userAudit.ClientIp,
+		Action:      action,
+		RoleId:      userAudit.RoleId,
+		RoleGroupId: userAudit.RoleGroupId,
+		ExpiresOn:   userAudit.ExpiresOn,
+		CreatedBy:   userAudit.CreatedBy,
+		CreatedOn:   userAudit.CreatedOn,
 	}
 	err := impl.userAuditRepository.Save(userAuditDb)
 	if err != nil {
 		impl.logger.Errorw("error while saving user audit log", "userId", userId, "error", err)
 		return err
 	}
+	impl.siemExportService.Export(impl.toSiemEvent(userAuditDb))
 	return nil
 }
 
+// toSiemEvent maps the user audit to siem event, actor of a login is the user itself and the role granted or
+// revoked is sent as diff
+func (impl UserAuditServiceImpl) toSiemEvent(userAudit *repository2.UserAudit) *siem.SiemEvent {
+	event := &siem.SiemEvent{
+		Id:           fmt.Sprintf("%s-%d", siem.CATEGORY_USER_AUDIT, userAudit.Id),
+		Category:     siem.CATEGORY_USER_AUDIT,
+		Action:       userAudit.Action,
+		ActorId:      userAudit.CreatedBy,
+		ResourceType: SIEM_RESOURCE_TYPE_USER,
+		ResourceId:   int(userAudit.UserId),
+		ResourceName: impl.getUserEmail(userAudit.UserId),
+		ClientIp:     userAudit.ClientIp,
+		CreatedOn:    userAudit.CreatedOn,
+	}
+	if userAudit.Action == repository2.USER_AUDIT_ACTION_LOGIN {
+		event.ActorId = userAudit.UserId
+		event.ActorEmail = event.ResourceName
+		return event
+	}
+	if event.ActorId > 0 {
+		event.ActorEmail = impl.getUserEmail(event.ActorId)
+	}
+	role := map[string]interface{}{}
+	if userAudit.RoleId > 0 {
+		role["roleId"] = userAudit.RoleId
+	}
+	if userAudit.RoleGroupId > 0 {
+		role["roleGroupId"] = userAudit.RoleGroupId
+	}
+	if !userAudit.ExpiresOn.IsZero() {
+		role["expiresOn"] = userAudit.ExpiresOn
+	}
+	if diff, err := json.Marshal(role); err == nil {
+		event.Diff = string(diff)
+	}
+	return event
+}
+
+func (impl UserAuditServiceImpl) getUserEmail(userId int32) string {
+	user, err := impl.userRepository.GetByIdIncludeDeleted(userId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching user for siem event", "userId", userId, "err", err)
+		return ""
+	}
+	return user.EmailId
+}
+
 func (impl UserAuditServiceImpl) GetLatestByUserId(userId int32) (*UserAudit, error) {
 	impl.logger.Infow("Getting latest user audit", "userId", userId)
 	userAuditDb, err := impl.userAuditRepository.GetLatestByUserId(userId)
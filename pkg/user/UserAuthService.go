@@ -381,6 +381,8 @@ func WhitelistChecker(url string) bool {
 		"/orchestrator/auth/login",
 		"/dashboard",
 		"/orchestrator/webhook/git",
+		// scim requests are authenticated by scim router with the bearer api-token
+		"/orchestrator/scim/v2/",
 	}
 	for _, a := range prefixUrls {
 		if strings.Contains(url, a) {
@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"github.com/devtron-labs/authenticator/middleware"
 	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/util"
 	casbin2 "github.com/devtron-labs/devtron/pkg/user/casbin"
 	repository2 "github.com/devtron-labs/devtron/pkg/user/repository"
 	"github.com/go-pg/pg"
 	"github.com/gorilla/sessions"
 	"go.uber.org/zap"
+	"net/http"
 	"strings"
+	"time"
 )
 
 type UserCommonService interface {
@@ -194,3 +197,43 @@ func containsArr(s []string, e string) bool {
 	}
 	return false
 }
+
+// getRoleFilterExpiresOn returns the expiry of roles in role filter, zero for the roles granted without expiry
+func getRoleFilterExpiresOn(roleFilter bean.RoleFilter) time.Time {
+	if roleFilter.ExpiresOn == nil {
+		return time.Time{}
+	}
+	return *roleFilter.ExpiresOn
+}
+
+func getExpiresOnPtr(expiresOn time.Time) *time.Time {
+	if expiresOn.IsZero() {
+		return nil
+	}
+	return &expiresOn
+}
+
+func validateRoleFiltersExpiry(roleFilters []bean.RoleFilter) error {
+	for _, roleFilter := range roleFilters {
+		if roleFilter.ExpiresOn != nil && !roleFilter.ExpiresOn.After(time.Now()) {
+			return &util.ApiError{
+				HttpStatusCode:  http.StatusBadRequest,
+				InternalMessage: fmt.Sprintf("role expiry %s is not in future", roleFilter.ExpiresOn),
+				UserMessage:     "Invalid request, role expiry must be in future",
+			}
+		}
+	}
+	return nil
+}
+
+func buildRoleGrantAudit(userId int32, roleGroupId int32, roleId int, expiresOn time.Time, grantedBy int32) *UserAudit {
+	return &UserAudit{
+		UserId:      userId,
+		Action:      repository2.USER_AUDIT_ACTION_ROLE_GRANT,
+		RoleId:      roleId,
+		RoleGroupId: roleGroupId,
+		ExpiresOn:   expiresOn,
+		CreatedBy:   grantedBy,
+		CreatedOn:   time.Now(),
+	}
+}
@@ -190,6 +190,10 @@ func (impl UserServiceImpl) saveUser(userInfo *bean.UserInfo, emailId string) (*
 		err = &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "Invalid request, please provide role filters"}
 		return nil, err
 	}
+	err = validateRoleFiltersExpiry(userInfo.RoleFilters)
+	if err != nil {
+		return nil, err
+	}
 
 	//create new user in our db on d basis of info got from google api or hex. assign a basic role
 	model := &repository2.UserModel{
@@ -316,6 +320,7 @@ func (impl UserServiceImpl) createUserIfNotExists(userInfo *bean.UserInfo, email
 
 	//Starts Role and Mapping
 	var policies []casbin2.Policy
+	var grantAudits []*UserAudit
 	if userInfo.SuperAdmin == false {
 		for _, roleFilter := range userInfo.RoleFilters {
 
@@ -387,12 +392,15 @@ func (impl UserServiceImpl) createUserIfNotExists(userInfo *bean.UserInfo, email
 					}
 					//roleModel := roleModels[0]
 					if roleModel.Id > 0 {
-						userRoleModel := &repository2.UserRoleModel{UserId: model.Id, RoleId: roleModel.Id}
+						userRoleModel := &repository2.UserRoleModel{UserId: model.Id, RoleId: roleModel.Id, ExpiresOn: getRoleFilterExpiresOn(roleFilter)}
 						userRoleModel, err = impl.userAuthRepository.CreateUserRoleMapping(userRoleModel, tx)
 						if err != nil {
 							return nil, err
 						}
 						policies = append(policies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(model.EmailId), Obj: casbin2.Object(roleModel.Role)})
+						if !userRoleModel.ExpiresOn.IsZero() {
+							grantAudits = append(grantAudits, buildRoleGrantAudit(model.Id, 0, roleModel.Id, userRoleModel.ExpiresOn, userInfo.UserId))
+						}
 					}
 				}
 			}
@@ -447,6 +455,7 @@ func (impl UserServiceImpl) createUserIfNotExists(userInfo *bean.UserInfo, email
 	if err != nil {
 		return nil, err
 	}
+	impl.saveUserAudits(grantAudits)
 	return userInfo, nil
 }
 
@@ -461,6 +470,7 @@ func (impl UserServiceImpl) mergeRoleFilter(oldR []bean.RoleFilter, newR []bean.
 			EntityName:  role.EntityName,
 			Action:      role.Action,
 			AccessType:  role.AccessType,
+			ExpiresOn:   role.ExpiresOn,
 		})
 		key := fmt.Sprintf("%s-%s-%s-%s-%s-%s", role.Entity, role.Team, role.Environment, role.EntityName, role.Action, role.AccessType)
 		keysMap[key] = true
@@ -475,6 +485,7 @@ func (impl UserServiceImpl) mergeRoleFilter(oldR []bean.RoleFilter, newR []bean.
 				EntityName:  role.EntityName,
 				Action:      role.Action,
 				AccessType:  role.AccessType,
+				ExpiresOn:   role.ExpiresOn,
 			})
 		}
 	}
@@ -532,6 +543,7 @@ func (impl UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, ma
 
 	var addedPolicies []casbin2.Policy
 	var eliminatedPolicies []casbin2.Policy
+	var grantAudits []*UserAudit
 	if userInfo.SuperAdmin == false {
 		//Starts Role and Mapping
 		userRoleModels, err := impl.userAuthRepository.GetUserRoleMappingByUserId(model.Id)
@@ -551,6 +563,10 @@ func (impl UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, ma
 			err = &util.ApiError{HttpStatusCode: http.StatusBadRequest, UserMessage: "Invalid request, please provide role filters"}
 			return nil, err
 		}
+		err = validateRoleFiltersExpiry(userInfo.RoleFilters)
+		if err != nil {
+			return nil, err
+		}
 
 		// DELETE Removed Items
 		items, err := impl.userCommonService.RemoveRolesAndReturnEliminatedPolicies(userInfo, existingRoleIds, eliminatedRoleIds, tx, token, managerAuth)
@@ -635,12 +651,28 @@ func (impl UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, ma
 							continue
 						}
 					}
-					if _, ok := existingRoleIds[roleModel.Id]; ok {
+					expiresOn := getRoleFilterExpiresOn(roleFilter)
+					if userRoleModel, ok := existingRoleIds[roleModel.Id]; ok {
 						//Adding policies which is removed
 						addedPolicies = append(addedPolicies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(model.EmailId), Obj: casbin2.Object(roleModel.Role)})
+						if !userRoleModel.ExpiresOn.Equal(expiresOn) {
+							// expiry of an existing role is changed, it is notified again before the new expiry
+							userRoleModel.ExpiresOn = expiresOn
+							userRoleModel.ExpiryNotified = false
+							userRoleModel.UpdatedBy = userInfo.UserId
+							userRoleModel.UpdatedOn = time.Now()
+							_, err = impl.userAuthRepository.UpdateUserRoleMapping(&userRoleModel, tx)
+							if err != nil {
+								return nil, err
+							}
+							existingRoleIds[roleModel.Id] = userRoleModel
+							if !expiresOn.IsZero() {
+								grantAudits = append(grantAudits, buildRoleGrantAudit(model.Id, 0, roleModel.Id, expiresOn, userInfo.UserId))
+							}
+						}
 					} else {
 						if roleModel.Id > 0 {
-							userRoleModel := &repository2.UserRoleModel{UserId: model.Id, RoleId: roleModel.Id}
+							userRoleModel := &repository2.UserRoleModel{UserId: model.Id, RoleId: roleModel.Id, ExpiresOn: expiresOn}
 							userRoleModel.CreatedBy = userInfo.UserId
 							userRoleModel.UpdatedBy = userInfo.UserId
 							userRoleModel.CreatedOn = time.Now()
@@ -650,6 +682,9 @@ func (impl UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, ma
 								return nil, err
 							}
 							addedPolicies = append(addedPolicies, casbin2.Policy{Type: "g", Sub: casbin2.Subject(model.EmailId), Obj: casbin2.Object(roleModel.Role)})
+							if !expiresOn.IsZero() {
+								grantAudits = append(grantAudits, buildRoleGrantAudit(model.Id, 0, roleModel.Id, expiresOn, userInfo.UserId))
+							}
 						}
 					}
 				}
@@ -738,6 +773,7 @@ func (impl UserServiceImpl) UpdateUser(userInfo *bean.UserInfo, token string, ma
 	if err != nil {
 		return nil, err
 	}
+	impl.saveUserAudits(grantAudits)
 
 	return userInfo, nil
 }
@@ -753,6 +789,14 @@ func (impl UserServiceImpl) GetById(id int32) (*bean.UserInfo, error) {
 	if err != nil {
 		impl.logger.Debugw("No Roles Found for user", "id", model.Id)
 	}
+	userRoleModels, err := impl.userAuthRepository.GetUserRoleMappingByUserId(model.Id)
+	if err != nil {
+		impl.logger.Debugw("No Roles Found for user", "id", model.Id)
+	}
+	expiresOnByRoleId := make(map[int]time.Time)
+	for _, userRoleModel := range userRoleModels {
+		expiresOnByRoleId[userRoleModel.RoleId] = userRoleModel.ExpiresOn
+	}
 	isSuperAdmin := false
 	var roleFilters []bean.RoleFilter
 	roleFilterMap := make(map[string]*bean.RoleFilter)
@@ -761,7 +805,12 @@ func (impl UserServiceImpl) GetById(id int32) (*bean.UserInfo, error) {
 		if len(role.Team) > 0 {
 			key = fmt.Sprintf("%s_%s_%s", role.Team, role.Action, role.AccessType)
 		} else if len(role.Entity) > 0 {
-			key = fmt.Sprintf("%s_%s_%s", role.Entity, role.Action)
+			key = fmt.Sprintf("%s_%s", role.Entity, role.Action)
+		}
+		// roles expiring at different times are not merged in a role filter
+		expiresOn := expiresOnByRoleId[role.Id]
+		if !expiresOn.IsZero() {
+			key = fmt.Sprintf("%s_%d", key, expiresOn.Unix())
 		}
 		if _, ok := roleFilterMap[key]; ok {
 			envArr := strings.Split(roleFilterMap[key].Environment, ",")
@@ -782,6 +831,7 @@ func (impl UserServiceImpl) GetById(id int32) (*bean.UserInfo, error) {
 				EntityName:  role.EntityName,
 				Action:      role.Action,
 				AccessType:  role.AccessType,
+				ExpiresOn:   getExpiresOnPtr(expiresOn),
 			}
 
 		}
@@ -1139,6 +1189,16 @@ func (impl UserServiceImpl) saveUserAudit(r *http.Request, userId int32) {
 	impl.userAuditService.Save(userAudit)
 }
 
+// saveUserAudits saves the grant and revoke audits of time-bound roles, failure is logged as it must not fail the update
+func (impl UserServiceImpl) saveUserAudits(userAudits []*UserAudit) {
+	for _, userAudit := range userAudits {
+		err := impl.userAuditService.Save(userAudit)
+		if err != nil {
+			impl.logger.Errorw("error in saving user audit", "userAudit", userAudit, "err", err)
+		}
+	}
+}
+
 func (impl UserServiceImpl) checkGroupAuth(groupName string, token string, managerAuth func(token string, object string) bool, isActionUserSuperAdmin bool) bool {
 	//check permission for group which is going to add/eliminate
 	roles, err := impl.roleGroupRepository.GetRolesByGroupCasbinName(groupName)
@@ -167,6 +167,14 @@ func GetUserByRole(role string) ([]string, error) {
 	return e.GetUsersForRole(role)
 }
 
+func GetPoliciesForSubject(sub string) [][]string {
+	return e.GetFilteredPolicy(0, strings.ToLower(sub))
+}
+
+func GetGroupingPoliciesForSubject(sub string) [][]string {
+	return e.GetFilteredGroupingPolicy(0, strings.ToLower(sub))
+}
+
 func RemovePoliciesByRoles(roles string) bool {
 	roles = strings.ToLower(roles)
 	policyResponse := e.RemovePolicy([]string{roles})
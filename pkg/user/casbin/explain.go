@@ -0,0 +1,78 @@
+package casbin
+
+import (
+	"strings"
+)
+
+const (
+	PolicyEffectAllow = "allow"
+	PolicyEffectDeny  = "deny"
+)
+
+// PolicyExplanation is a policy line matching an enforce request along with the chain of roles and groups
+// through which the policy line is reachable from the requested subject
+type PolicyExplanation struct {
+	Sub        string
+	Res        string
+	Act        string
+	Obj        string
+	Eft        string
+	GrantedVia []string
+}
+
+// ExplainPolicies returns the policy lines of the subject and of the roles and groups assigned to it which match the
+// resource, action and object in the same way as the enforcer does
+func ExplainPolicies(sub string, res string, act string, obj string) []*PolicyExplanation {
+	return explainPolicies(strings.ToLower(sub), res, act, obj, GetGroupingPoliciesForSubject, GetPoliciesForSubject)
+}
+
+// IsAllowed applies the policy effect of the model, any allow and no deny, on the explained policy lines
+func IsAllowed(explanations []*PolicyExplanation) bool {
+	allowed := false
+	for _, explanation := range explanations {
+		if explanation.Eft == PolicyEffectDeny {
+			return false
+		}
+		allowed = true
+	}
+	return allowed
+}
+
+func explainPolicies(sub string, res string, act string, obj string,
+	groupingPolicies func(sub string) [][]string, policies func(sub string) [][]string) []*PolicyExplanation {
+	var explanations []*PolicyExplanation
+	visited := map[string]bool{sub: true}
+	queue := []string{sub}
+	grantedVia := map[string][]string{sub: nil}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, policy := range policies(current) {
+			if len(policy) < 4 || !MatchKeyByPart(res, policy[1]) || !MatchKeyByPart(act, policy[2]) || !MatchKeyByPart(obj, policy[3]) {
+				continue
+			}
+			eft := PolicyEffectAllow
+			if len(policy) > 4 && policy[4] == PolicyEffectDeny {
+				eft = PolicyEffectDeny
+			}
+			explanations = append(explanations, &PolicyExplanation{
+				Sub:        policy[0],
+				Res:        policy[1],
+				Act:        policy[2],
+				Obj:        policy[3],
+				Eft:        eft,
+				GrantedVia: grantedVia[current],
+			})
+		}
+		for _, groupingPolicy := range groupingPolicies(current) {
+			if len(groupingPolicy) < 2 || visited[groupingPolicy[1]] {
+				continue
+			}
+			role := groupingPolicy[1]
+			visited[role] = true
+			grantedVia[role] = append(append([]string{}, grantedVia[current]...), role)
+			queue = append(queue, role)
+		}
+	}
+	return explanations
+}
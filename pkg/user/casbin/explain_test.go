@@ -0,0 +1,41 @@
+package casbin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainPolicies(t *testing.T) {
+	groupingPolicies := map[string][][]string{
+		"user@example.com":  {{"user@example.com", "group:devs"}, {"user@example.com", "role:view_dev"}},
+		"group:devs":        {{"group:devs", "role:trigger_dev"}},
+		"role:trigger_dev":  {{"role:trigger_dev", "group:devs"}},
+		"other@example.com": {{"other@example.com", "role:super-admin___"}},
+	}
+	policies := map[string][][]string{
+		"role:trigger_dev":    {{"role:trigger_dev", "environment", "trigger", "dev/*", "allow"}, {"role:trigger_dev", "applications", "get", "team/*", "allow"}},
+		"role:view_dev":       {{"role:view_dev", "environment", "get", "dev/*", "allow"}},
+		"role:super-admin___": {{"role:super-admin___", "*", "*", "*", "allow"}},
+	}
+	getGroupingPolicies := func(sub string) [][]string { return groupingPolicies[sub] }
+	getPolicies := func(sub string) [][]string { return policies[sub] }
+
+	explanations := explainPolicies("user@example.com", "environment", "trigger", "dev/app1", getGroupingPolicies, getPolicies)
+	assert.Equal(t, 1, len(explanations))
+	assert.Equal(t, "role:trigger_dev", explanations[0].Sub)
+	assert.Equal(t, []string{"group:devs", "role:trigger_dev"}, explanations[0].GrantedVia)
+	assert.True(t, IsAllowed(explanations))
+
+	explanations = explainPolicies("user@example.com", "environment", "trigger", "prod/app1", getGroupingPolicies, getPolicies)
+	assert.False(t, IsAllowed(explanations))
+
+	explanations = explainPolicies("other@example.com", "environment", "trigger", "prod/app1", getGroupingPolicies, getPolicies)
+	assert.Equal(t, []string{"role:super-admin___"}, explanations[0].GrantedVia)
+	assert.True(t, IsAllowed(explanations))
+
+	policies["role:view_dev"] = append(policies["role:view_dev"], []string{"role:view_dev", "environment", "trigger", "dev/app1", "deny"})
+	explanations = explainPolicies("user@example.com", "environment", "trigger", "dev/app1", getGroupingPolicies, getPolicies)
+	assert.Equal(t, 2, len(explanations))
+	assert.False(t, IsAllowed(explanations))
+}
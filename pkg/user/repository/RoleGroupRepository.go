@@ -21,6 +21,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"time"
 )
 
 type RoleGroupRepository interface {
@@ -37,6 +38,9 @@ type RoleGroupRepository interface {
 	GetRoleGroupRoleMappingByRoleGroupId(roleGroupId int32) ([]*RoleGroupRoleMapping, error)
 	DeleteRoleGroupRoleMappingByRoleId(roleId int, tx *pg.Tx) error
 	DeleteRoleGroupRoleMapping(model *RoleGroupRoleMapping, tx *pg.Tx) (bool, error)
+	UpdateRoleGroupRoleMapping(model *RoleGroupRoleMapping, tx *pg.Tx) (*RoleGroupRoleMapping, error)
+	GetRoleGroupRoleMappingsExpiringBefore(expiresBefore time.Time) ([]*RoleGroupRoleMapping, error)
+	MarkRoleGroupRoleMappingsExpiryNotified(ids []int) error
 	GetConnection() (dbConnection *pg.DB)
 	GetRoleGroupListByNames(groupNames []string) ([]*RoleGroup, error)
 	GetRoleGroupRoleMappingByRoleGroupIds(roleGroupIds []int32) ([]*RoleModel, error)
@@ -59,6 +63,8 @@ type RoleGroup struct {
 	CasbinName  string   `sql:"casbin_name,notnull"`
 	Description string   `sql:"description"`
 	Active      bool     `sql:"active,notnull"`
+	// ScimProvisioned is set for groups created through scim, the identity provider can manage only these groups
+	ScimProvisioned bool `sql:"scim_provisioned,notnull"`
 	sql.AuditLog
 }
 
@@ -67,6 +73,9 @@ type RoleGroupRoleMapping struct {
 	Id          int      `sql:"id,pk"`
 	RoleGroupId int32    `sql:"role_group_id,notnull"`
 	RoleId      int      `sql:"role_id,notnull"`
+	// ExpiresOn is zero for the roles granted without expiry
+	ExpiresOn      time.Time `sql:"expires_on,type:timestamptz"`
+	ExpiryNotified bool      `sql:"expiry_notified,notnull"`
 	sql.AuditLog
 }
 
@@ -169,6 +178,37 @@ func (impl RoleGroupRepositoryImpl) DeleteRoleGroupRoleMapping(model *RoleGroupR
 	return true, nil
 }
 
+func (impl RoleGroupRepositoryImpl) UpdateRoleGroupRoleMapping(model *RoleGroupRoleMapping, tx *pg.Tx) (*RoleGroupRoleMapping, error) {
+	err := tx.Update(model)
+	if err != nil {
+		impl.Logger.Error(err)
+		return model, err
+	}
+	return model, nil
+}
+
+// GetRoleGroupRoleMappingsExpiringBefore returns the time-bound role mappings of role groups expiring before the given time
+func (impl RoleGroupRepositoryImpl) GetRoleGroupRoleMappingsExpiringBefore(expiresBefore time.Time) ([]*RoleGroupRoleMapping, error) {
+	var models []*RoleGroupRoleMapping
+	err := impl.dbConnection.Model(&models).
+		Where("expires_on IS NOT NULL").
+		Where("expires_on < ?", expiresBefore).
+		Select()
+	return models, err
+}
+
+func (impl RoleGroupRepositoryImpl) MarkRoleGroupRoleMappingsExpiryNotified(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var model RoleGroupRoleMapping
+	_, err := impl.dbConnection.Model(&model).
+		Set("expiry_notified = ?", true).
+		Where("id in (?)", pg.In(ids)).
+		Update()
+	return err
+}
+
 func (impl RoleGroupRepositoryImpl) GetRoleGroupListByNames(groupNames []string) ([]*RoleGroup, error) {
 	var model []*RoleGroup
 	err := impl.dbConnection.Model(&model).Where("name in (?)", pg.In(groupNames)).Where("active = ?", true).Order("updated_on desc").Select()
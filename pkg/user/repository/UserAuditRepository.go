@@ -25,12 +25,23 @@ import (
 	"time"
 )
 
+const (
+	USER_AUDIT_ACTION_LOGIN       = "LOGIN"
+	USER_AUDIT_ACTION_ROLE_GRANT  = "ROLE_GRANT"
+	USER_AUDIT_ACTION_ROLE_REVOKE = "ROLE_REVOKE"
+)
+
 type UserAudit struct {
-	TableName struct{}  `sql:"user_audit"`
-	Id        int32     `sql:"id,pk"`
-	UserId    int32     `sql:"user_id, notnull"`
-	ClientIp  string    `sql:"client_ip"`
-	CreatedOn time.Time `sql:"created_on,type:timestamptz"`
+	TableName   struct{}  `sql:"user_audit"`
+	Id          int32     `sql:"id,pk"`
+	UserId      int32     `sql:"user_id"`
+	ClientIp    string    `sql:"client_ip"`
+	Action      string    `sql:"action,notnull"`
+	RoleId      int       `sql:"role_id"`
+	RoleGroupId int32     `sql:"role_group_id"`
+	ExpiresOn   time.Time `sql:"expires_on,type:timestamptz"`
+	CreatedBy   int32     `sql:"created_by"`
+	CreatedOn   time.Time `sql:"created_on,type:timestamptz"`
 }
 
 type UserAuditRepository interface {
@@ -54,6 +65,7 @@ func (impl UserAuditRepositoryImpl) GetLatestByUserId(userId int32) (*UserAudit,
 	userAudit := &UserAudit{}
 	err := impl.dbConnection.Model(userAudit).
 		Where("user_id = ?", userId).
+		Where("action = ?", USER_AUDIT_ACTION_LOGIN).
 		Order("id desc").
 		Limit(1).
 		Select()
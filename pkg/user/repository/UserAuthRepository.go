@@ -30,6 +30,7 @@ import (
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
 	"strings"
+	"time"
 )
 
 const (
@@ -52,6 +53,9 @@ type UserAuthRepository interface {
 	GetUserRoleMappingByUserId(userId int32) ([]*UserRoleModel, error)
 	DeleteUserRoleMapping(userRoleModel *UserRoleModel, tx *pg.Tx) (bool, error)
 	DeleteUserRoleByRoleId(roleId int, tx *pg.Tx) error
+	UpdateUserRoleMapping(userRoleModel *UserRoleModel, tx *pg.Tx) (*UserRoleModel, error)
+	GetUserRoleMappingsExpiringBefore(expiresBefore time.Time) ([]*UserRoleModel, error)
+	MarkUserRoleMappingsExpiryNotified(ids []int) error
 
 	CreateDefaultPolicies(team string, entityName string, env string, tx *pg.Tx) (bool, error)
 	CreateDefaultHelmPolicies(team string, entityName string, env string, tx *pg.Tx) (bool, error)
@@ -308,6 +312,37 @@ func (impl UserAuthRepositoryImpl) DeleteUserRoleMapping(userRoleModel *UserRole
 	return true, nil
 }
 
+func (impl UserAuthRepositoryImpl) UpdateUserRoleMapping(userRoleModel *UserRoleModel, tx *pg.Tx) (*UserRoleModel, error) {
+	err := tx.Update(userRoleModel)
+	if err != nil {
+		impl.Logger.Error(err)
+		return userRoleModel, err
+	}
+	return userRoleModel, nil
+}
+
+// GetUserRoleMappingsExpiringBefore returns the time-bound role mappings of users expiring before the given time
+func (impl UserAuthRepositoryImpl) GetUserRoleMappingsExpiringBefore(expiresBefore time.Time) ([]*UserRoleModel, error) {
+	var userRoleModels []*UserRoleModel
+	err := impl.dbConnection.Model(&userRoleModels).
+		Where("expires_on IS NOT NULL").
+		Where("expires_on < ?", expiresBefore).
+		Select()
+	return userRoleModels, err
+}
+
+func (impl UserAuthRepositoryImpl) MarkUserRoleMappingsExpiryNotified(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var userRoleModel UserRoleModel
+	_, err := impl.dbConnection.Model(&userRoleModel).
+		Set("expiry_notified = ?", true).
+		Where("id in (?)", pg.In(ids)).
+		Update()
+	return err
+}
+
 func (impl UserAuthRepositoryImpl) DeleteUserRoleByRoleId(roleId int, tx *pg.Tx) error {
 	var userRoleModel *UserRoleModel
 	_, err := tx.Model(userRoleModel).
@@ -25,6 +25,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"time"
 )
 
 type UserRepository interface {
@@ -33,6 +34,7 @@ type UserRepository interface {
 	GetById(id int32) (*UserModel, error)
 	GetByIdIncludeDeleted(id int32) (*UserModel, error)
 	GetAllExcludingApiTokenUser() ([]UserModel, error)
+	GetAllActive() ([]UserModel, error)
 	FetchActiveUserByEmail(email string) (bean.UserInfo, error)
 	FetchUserDetailByEmail(email string) (bean.UserInfo, error)
 	GetByIds(ids []int32) ([]UserModel, error)
@@ -65,7 +67,10 @@ type UserRoleModel struct {
 	Id        int      `sql:"id,pk"`
 	UserId    int32    `sql:"user_id,notnull"`
 	RoleId    int      `sql:"role_id,notnull"`
-	User      UserModel
+	// ExpiresOn is zero for the roles granted without expiry
+	ExpiresOn      time.Time `sql:"expires_on,type:timestamptz"`
+	ExpiryNotified bool      `sql:"expiry_notified,notnull"`
+	User           UserModel
 	sql.AuditLog
 }
 
@@ -101,6 +106,14 @@ func (impl UserRepositoryImpl) GetByIdIncludeDeleted(id int32) (*UserModel, erro
 	return &model, err
 }
 
+func (impl UserRepositoryImpl) GetAllActive() ([]UserModel, error) {
+	var userModel []UserModel
+	err := impl.dbConnection.Model(&userModel).
+		Where("active = ?", true).
+		Order("updated_on desc").Select()
+	return userModel, err
+}
+
 func (impl UserRepositoryImpl) GetAllExcludingApiTokenUser() ([]UserModel, error) {
 	var userModel []UserModel
 	err := impl.dbConnection.Model(&userModel).
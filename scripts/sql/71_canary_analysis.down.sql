@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS public.canary_analysis_run_status_IX;
+
+DROP TABLE "public"."canary_analysis_run" CASCADE;
+
+DROP TABLE "public"."canary_analysis_metric" CASCADE;
+
+DROP TABLE "public"."canary_analysis_config" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_canary_analysis_run;
+
+DROP SEQUENCE IF EXISTS public.id_seq_canary_analysis_metric;
+
+DROP SEQUENCE IF EXISTS public.id_seq_canary_analysis_config;
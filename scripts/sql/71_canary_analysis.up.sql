@@ -0,0 +1,70 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_config;
+
+-- Table Definition
+CREATE TABLE "public"."canary_analysis_config"
+(
+    "id"                       integer NOT NULL DEFAULT nextval('id_seq_canary_analysis_config'::regclass),
+    "pipeline_id"              integer NOT NULL,
+    "initial_delay_in_seconds" integer NOT NULL DEFAULT 0,
+    "interval_in_seconds"      integer NOT NULL,
+    "iterations"               integer NOT NULL,
+    "failure_limit"            integer NOT NULL DEFAULT 0,
+    "auto_rollback"            bool    NOT NULL DEFAULT TRUE,
+    "active"                   bool    NOT NULL,
+    "created_on"               timestamptz,
+    "created_by"               int4,
+    "updated_on"               timestamptz,
+    "updated_by"               int4,
+    CONSTRAINT "canary_analysis_config_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_metric;
+
+-- Table Definition
+CREATE TABLE "public"."canary_analysis_metric"
+(
+    "id"                        integer          NOT NULL DEFAULT nextval('id_seq_canary_analysis_metric'::regclass),
+    "canary_analysis_config_id" integer          NOT NULL,
+    "name"                      varchar(250)     NOT NULL,
+    "query"                     text             NOT NULL,
+    "operator"                  varchar(10)      NOT NULL,
+    "threshold"                 double precision NOT NULL,
+    "active"                    bool             NOT NULL,
+    "created_on"                timestamptz,
+    "created_by"                int4,
+    "updated_on"                timestamptz,
+    "updated_by"                int4,
+    CONSTRAINT "canary_analysis_metric_canary_analysis_config_id_fkey" FOREIGN KEY ("canary_analysis_config_id") REFERENCES "public"."canary_analysis_config" ("id"),
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_run;
+
+-- Table Definition
+CREATE TABLE "public"."canary_analysis_run"
+(
+    "id"                        integer     NOT NULL DEFAULT nextval('id_seq_canary_analysis_run'::regclass),
+    "canary_analysis_config_id" integer     NOT NULL,
+    "pipeline_id"               integer     NOT NULL,
+    "cd_workflow_runner_id"     integer     NOT NULL,
+    "ci_artifact_id"            integer     NOT NULL,
+    "status"                    varchar(50) NOT NULL,
+    "successful_steps"          integer     NOT NULL DEFAULT 0,
+    "failed_steps"              integer     NOT NULL DEFAULT 0,
+    "next_evaluation_on"        timestamptz,
+    "finished_on"               timestamptz,
+    "message"                   text,
+    "created_on"                timestamptz,
+    "created_by"                int4,
+    "updated_on"                timestamptz,
+    "updated_by"                int4,
+    CONSTRAINT "canary_analysis_run_canary_analysis_config_id_fkey" FOREIGN KEY ("canary_analysis_config_id") REFERENCES "public"."canary_analysis_config" ("id"),
+    CONSTRAINT "canary_analysis_run_cd_workflow_runner_id_fkey" FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX canary_analysis_run_status_IX ON public.canary_analysis_run (status);
@@ -0,0 +1,14 @@
+DROP TABLE "public"."cd_approval_action" CASCADE;
+
+DROP INDEX IF EXISTS public.cd_approval_request_pipeline_id_ci_artifact_id_IX;
+
+DROP TABLE "public"."cd_approval_request" CASCADE;
+
+DROP TABLE "public"."cd_approval_policy" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_cd_approval_action;
+
+DROP SEQUENCE IF EXISTS public.id_seq_cd_approval_request;
+
+DROP SEQUENCE IF EXISTS public.id_seq_cd_approval_policy;
@@ -0,0 +1,61 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_approval_policy;
+
+-- Table Definition
+CREATE TABLE "public"."cd_approval_policy"
+(
+    "id"                 integer NOT NULL DEFAULT nextval('id_seq_cd_approval_policy'::regclass),
+    "pipeline_id"        integer NOT NULL,
+    "role_group_id"      integer NOT NULL,
+    "required_approvals" integer NOT NULL,
+    "active"             bool    NOT NULL,
+    "created_on"         timestamptz,
+    "created_by"         int4,
+    "updated_on"         timestamptz,
+    "updated_by"         int4,
+    CONSTRAINT "cd_approval_policy_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_approval_policy_role_group_id_fkey" FOREIGN KEY ("role_group_id") REFERENCES "public"."role_group" ("id"),
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_approval_request;
+
+-- Table Definition
+CREATE TABLE "public"."cd_approval_request"
+(
+    "id"             integer     NOT NULL DEFAULT nextval('id_seq_cd_approval_request'::regclass),
+    "pipeline_id"    integer     NOT NULL,
+    "ci_artifact_id" integer     NOT NULL,
+    "status"         varchar(50) NOT NULL,
+    "comment"        text,
+    "created_on"     timestamptz,
+    "created_by"     int4,
+    "updated_on"     timestamptz,
+    "updated_by"     int4,
+    CONSTRAINT "cd_approval_request_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_approval_request_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS cd_approval_request_pipeline_id_ci_artifact_id_IX ON public.cd_approval_request (pipeline_id, ci_artifact_id);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_approval_action;
+
+-- Table Definition
+CREATE TABLE "public"."cd_approval_action"
+(
+    "id"                     integer     NOT NULL DEFAULT nextval('id_seq_cd_approval_action'::regclass),
+    "cd_approval_request_id" integer     NOT NULL,
+    "user_id"                integer     NOT NULL,
+    "action"                 varchar(50) NOT NULL,
+    "comment"                text,
+    "created_on"             timestamptz,
+    "created_by"             int4,
+    "updated_on"             timestamptz,
+    "updated_by"             int4,
+    CONSTRAINT "cd_approval_action_cd_approval_request_id_fkey" FOREIGN KEY ("cd_approval_request_id") REFERENCES "public"."cd_approval_request" ("id"),
+    CONSTRAINT "cd_approval_action_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id"),
+    PRIMARY KEY ("id")
+);
@@ -0,0 +1,8 @@
+DROP TABLE "public"."deployment_window_override" CASCADE;
+
+DROP TABLE "public"."deployment_window" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_deployment_window_override;
+
+DROP SEQUENCE IF EXISTS public.id_seq_deployment_window;
@@ -0,0 +1,50 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_window"
+(
+    "id"                  integer      NOT NULL DEFAULT nextval('id_seq_deployment_window'::regclass),
+    "name"                varchar(250) NOT NULL,
+    "description"         text,
+    "window_type"         varchar(50)  NOT NULL,
+    "cron_expression"     varchar(250),
+    "duration_in_minutes" integer,
+    "start_time"          timestamptz,
+    "end_time"            timestamptz,
+    "timezone"            varchar(100) NOT NULL DEFAULT 'UTC',
+    "cluster_id"          integer,
+    "environment_id"      integer,
+    "app_id"              integer,
+    "active"              bool         NOT NULL,
+    "created_on"          timestamptz,
+    "created_by"          int4,
+    "updated_on"          timestamptz,
+    "updated_by"          int4,
+    CONSTRAINT "deployment_window_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "deployment_window_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    CONSTRAINT "deployment_window_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window_override;
+
+-- Table Definition
+CREATE TABLE "public"."deployment_window_override"
+(
+    "id"                   integer NOT NULL DEFAULT nextval('id_seq_deployment_window_override'::regclass),
+    "deployment_window_id" integer NOT NULL,
+    "pipeline_id"          integer NOT NULL,
+    "ci_artifact_id"       integer NOT NULL,
+    "user_id"              integer NOT NULL,
+    "reason"               text,
+    "created_on"           timestamptz,
+    "created_by"           int4,
+    "updated_on"           timestamptz,
+    "updated_by"           int4,
+    CONSTRAINT "deployment_window_override_deployment_window_id_fkey" FOREIGN KEY ("deployment_window_id") REFERENCES "public"."deployment_window" ("id"),
+    CONSTRAINT "deployment_window_override_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "deployment_window_override_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id"),
+    PRIMARY KEY ("id")
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS public.ci_pipeline_schedule_run_ci_workflow_id_IX;
+
+DROP TABLE "public"."ci_pipeline_schedule_run" CASCADE;
+
+DROP TABLE "public"."ci_pipeline_schedule" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_ci_pipeline_schedule_run;
+
+DROP SEQUENCE IF EXISTS public.id_seq_ci_pipeline_schedule;
@@ -0,0 +1,43 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_schedule;
+
+-- Table Definition
+CREATE TABLE "public"."ci_pipeline_schedule"
+(
+    "id"                    integer      NOT NULL DEFAULT nextval('id_seq_ci_pipeline_schedule'::regclass),
+    "ci_pipeline_id"        integer      NOT NULL,
+    "cron_expression"       varchar(250) NOT NULL,
+    "timezone"              varchar(100) NOT NULL,
+    "skip_if_no_new_commit" bool         NOT NULL DEFAULT FALSE,
+    "active"                bool         NOT NULL,
+    "created_on"            timestamptz,
+    "created_by"            int4,
+    "updated_on"            timestamptz,
+    "updated_by"            int4,
+    CONSTRAINT "ci_pipeline_schedule_ci_pipeline_id_fkey" FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id"),
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_pipeline_schedule_run;
+
+-- Table Definition
+CREATE TABLE "public"."ci_pipeline_schedule_run"
+(
+    "id"                      integer     NOT NULL DEFAULT nextval('id_seq_ci_pipeline_schedule_run'::regclass),
+    "ci_pipeline_schedule_id" integer     NOT NULL,
+    "ci_pipeline_id"          integer     NOT NULL,
+    "ci_workflow_id"          integer,
+    "status"                  varchar(50) NOT NULL,
+    "message"                 text,
+    "created_on"              timestamptz,
+    "created_by"              int4,
+    "updated_on"              timestamptz,
+    "updated_by"              int4,
+    CONSTRAINT "ci_pipeline_schedule_run_ci_pipeline_schedule_id_fkey" FOREIGN KEY ("ci_pipeline_schedule_id") REFERENCES "public"."ci_pipeline_schedule" ("id"),
+    CONSTRAINT "ci_pipeline_schedule_run_ci_pipeline_id_fkey" FOREIGN KEY ("ci_pipeline_id") REFERENCES "public"."ci_pipeline" ("id"),
+    CONSTRAINT "ci_pipeline_schedule_run_ci_workflow_id_fkey" FOREIGN KEY ("ci_workflow_id") REFERENCES "public"."ci_workflow" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS ci_pipeline_schedule_run_ci_workflow_id_IX ON public.ci_pipeline_schedule_run (ci_workflow_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS public.cd_promotion_policy_pipeline_id_IX;
+
+DROP TABLE "public"."cd_promotion_policy" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_cd_promotion_policy;
@@ -0,0 +1,22 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_promotion_policy;
+
+-- Table Definition
+CREATE TABLE "public"."cd_promotion_policy"
+(
+    "id"                           integer NOT NULL DEFAULT nextval('id_seq_cd_promotion_policy'::regclass),
+    "pipeline_id"                  integer NOT NULL,
+    "source_pipeline_id"           integer NOT NULL,
+    "min_soak_duration_in_minutes" integer NOT NULL DEFAULT 0,
+    "require_post_stage_success"   bool    NOT NULL DEFAULT FALSE,
+    "active"                       bool    NOT NULL,
+    "created_on"                   timestamptz,
+    "created_by"                   int4,
+    "updated_on"                   timestamptz,
+    "updated_by"                   int4,
+    CONSTRAINT "cd_promotion_policy_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_promotion_policy_source_pipeline_id_fkey" FOREIGN KEY ("source_pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS cd_promotion_policy_pipeline_id_IX ON public.cd_promotion_policy (pipeline_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS public.pipeline_status_timeline_resources_cd_workflow_runner_id_IX;
+
+DROP TABLE "public"."pipeline_status_timeline_resources" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_pipeline_status_timeline_resources;
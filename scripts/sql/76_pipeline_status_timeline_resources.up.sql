@@ -0,0 +1,26 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_pipeline_status_timeline_resources;
+
+-- Table Definition
+CREATE TABLE "public"."pipeline_status_timeline_resources"
+(
+    "id"                    integer NOT NULL DEFAULT nextval('id_seq_pipeline_status_timeline_resources'::regclass),
+    "cd_workflow_runner_id" integer NOT NULL,
+    "resource_group"        varchar(250),
+    "resource_version"      varchar(250),
+    "resource_kind"         varchar(250),
+    "resource_name"         varchar(1000),
+    "resource_namespace"    varchar(250),
+    "sync_status"           varchar(50),
+    "sync_phase"            varchar(50),
+    "health_status"         varchar(50),
+    "status_message"        text,
+    "created_on"            timestamptz,
+    "created_by"            int4,
+    "updated_on"            timestamptz,
+    "updated_by"            int4,
+    CONSTRAINT "pipeline_status_timeline_resources_cd_workflow_runner_id_fkey" FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS pipeline_status_timeline_resources_cd_workflow_runner_id_IX ON public.pipeline_status_timeline_resources (cd_workflow_runner_id);
@@ -0,0 +1,10 @@
+DROP TABLE "public"."pager_duty_config" CASCADE;
+
+DROP TABLE "public"."webhook_config" CASCADE;
+
+DROP TABLE "public"."teams_config" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_pager_duty_config;
+DROP SEQUENCE IF EXISTS public.id_seq_webhook_config;
+DROP SEQUENCE IF EXISTS public.id_seq_teams_config;
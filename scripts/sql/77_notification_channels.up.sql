@@ -0,0 +1,60 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_teams_config;
+
+-- Table Definition
+CREATE TABLE "public"."teams_config"
+(
+    "id"           integer NOT NULL DEFAULT nextval('id_seq_teams_config'::regclass),
+    "web_hook_url" text    NOT NULL,
+    "config_name"  varchar(250) NOT NULL,
+    "description"  text,
+    "owner_id"     int4,
+    "deleted"      bool    NOT NULL DEFAULT FALSE,
+    "created_on"   timestamptz,
+    "created_by"   int4,
+    "updated_on"   timestamptz,
+    "updated_by"   int4,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_webhook_config;
+
+-- Table Definition
+CREATE TABLE "public"."webhook_config"
+(
+    "id"           integer NOT NULL DEFAULT nextval('id_seq_webhook_config'::regclass),
+    "web_hook_url" text    NOT NULL,
+    "config_name"  varchar(250) NOT NULL,
+    "description"  text,
+    "header"       text,
+    "payload"      text,
+    "secret"       text,
+    "owner_id"     int4,
+    "deleted"      bool    NOT NULL DEFAULT FALSE,
+    "created_on"   timestamptz,
+    "created_by"   int4,
+    "updated_on"   timestamptz,
+    "updated_by"   int4,
+    PRIMARY KEY ("id")
+);
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_pager_duty_config;
+
+-- Table Definition
+CREATE TABLE "public"."pager_duty_config"
+(
+    "id"          integer NOT NULL DEFAULT nextval('id_seq_pager_duty_config'::regclass),
+    "routing_key" varchar(250) NOT NULL,
+    "severity"    varchar(50),
+    "config_name" varchar(250) NOT NULL,
+    "description" text,
+    "owner_id"    int4,
+    "deleted"     bool    NOT NULL DEFAULT FALSE,
+    "created_on"  timestamptz,
+    "created_by"  int4,
+    "updated_on"  timestamptz,
+    "updated_by"  int4,
+    PRIMARY KEY ("id")
+);
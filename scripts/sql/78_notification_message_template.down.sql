@@ -0,0 +1,4 @@
+DROP TABLE "public"."notification_message_template" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_notification_message_template;
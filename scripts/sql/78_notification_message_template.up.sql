@@ -0,0 +1,23 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_notification_message_template;
+
+-- Table Definition, notification_templates is the table of default templates of notifier
+CREATE TABLE "public"."notification_message_template"
+(
+    "id"               integer     NOT NULL DEFAULT nextval('id_seq_notification_message_template'::regclass),
+    "channel_type"     varchar(50) NOT NULL,
+    "event_type_id"    int4        NOT NULL,
+    "pipeline_type"    varchar(50) NOT NULL,
+    "template_name"    varchar(250),
+    "template"         text        NOT NULL,
+    "default_template" text,
+    "active"           bool        NOT NULL DEFAULT TRUE,
+    "created_on"       timestamptz,
+    "created_by"       int4,
+    "updated_on"       timestamptz,
+    "updated_by"       int4,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "notification_message_template_channel_event_pipeline_type_active_key"
+    ON "public"."notification_message_template" ("channel_type", "event_type_id", "pipeline_type") WHERE "active" = TRUE;
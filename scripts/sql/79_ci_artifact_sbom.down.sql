@@ -0,0 +1,9 @@
+DROP TABLE "public"."sbom_component" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_sbom_component;
+
+DROP TABLE "public"."ci_artifact_sbom" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_ci_artifact_sbom;
@@ -0,0 +1,44 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_sbom;
+
+-- Table Definition
+CREATE TABLE "public"."ci_artifact_sbom"
+(
+    "id"              integer     NOT NULL DEFAULT nextval('id_seq_ci_artifact_sbom'::regclass),
+    "ci_artifact_id"  int4        NOT NULL,
+    "image"           varchar(250) NOT NULL,
+    "format"          varchar(50) NOT NULL,
+    "spec_version"    varchar(50),
+    "document"        text        NOT NULL,
+    "component_count" int4        NOT NULL DEFAULT 0,
+    "created_on"      timestamptz,
+    "created_by"      int4,
+    "updated_on"      timestamptz,
+    "updated_by"      int4,
+    CONSTRAINT "ci_artifact_sbom_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "ci_artifact_sbom_ci_artifact_id_format_key"
+    ON "public"."ci_artifact_sbom" ("ci_artifact_id", "format");
+
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_sbom_component;
+
+-- Table Definition
+CREATE TABLE "public"."sbom_component"
+(
+    "id"                  integer      NOT NULL DEFAULT nextval('id_seq_sbom_component'::regclass),
+    "ci_artifact_sbom_id" int4         NOT NULL,
+    "ci_artifact_id"      int4         NOT NULL,
+    "image"               varchar(250) NOT NULL,
+    "name"                varchar(500) NOT NULL,
+    "version"             varchar(250),
+    "purl"                text,
+    "type"                varchar(100),
+    CONSTRAINT "sbom_component_ci_artifact_sbom_id_fkey" FOREIGN KEY ("ci_artifact_sbom_id") REFERENCES "public"."ci_artifact_sbom" ("id") ON DELETE CASCADE,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "sbom_component_name_idx" ON "public"."sbom_component" (lower("name"));
+CREATE INDEX IF NOT EXISTS "sbom_component_image_idx" ON "public"."sbom_component" ("image");
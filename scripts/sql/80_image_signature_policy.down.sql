@@ -0,0 +1,9 @@
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "signature_verification_message";
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "signature_verification_status";
+
+ALTER TABLE "public"."ci_pipeline" DROP COLUMN IF EXISTS "image_signing_enabled";
+
+DROP TABLE "public"."image_signature_policy" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_image_signature_policy;
@@ -0,0 +1,34 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signature_policy;
+
+-- Table Definition
+CREATE TABLE "public"."image_signature_policy"
+(
+    "id"                    integer      NOT NULL DEFAULT nextval('id_seq_image_signature_policy'::regclass),
+    "name"                  varchar(250) NOT NULL,
+    "description"           text,
+    "cluster_id"            int4,
+    "environment_id"        int4,
+    "app_id"                int4,
+    "action"                varchar(50)  NOT NULL,
+    "verification_mode"     varchar(50),
+    "public_keys"           text,
+    "root_certificates"     text,
+    "keyless_issuer"        varchar(250),
+    "keyless_subject"       varchar(250),
+    "required_attestations" text[],
+    "active"                bool         NOT NULL DEFAULT true,
+    "created_on"            timestamptz,
+    "created_by"            int4,
+    "updated_on"            timestamptz,
+    "updated_by"            int4,
+    CONSTRAINT "image_signature_policy_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "image_signature_policy_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    CONSTRAINT "image_signature_policy_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    PRIMARY KEY ("id")
+);
+
+ALTER TABLE "public"."ci_pipeline" ADD COLUMN IF NOT EXISTS "image_signing_enabled" bool NOT NULL DEFAULT false;
+
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "signature_verification_status" varchar(50);
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "signature_verification_message" text;
@@ -0,0 +1,6 @@
+DELETE FROM "public"."event" WHERE "id" = 4;
+
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "expiry_notified";
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "approved_by";
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "justification";
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "expires_on";
@@ -0,0 +1,7 @@
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "expires_on" timestamptz;
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "justification" text;
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "approved_by" int4;
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "expiry_notified" bool NOT NULL DEFAULT false;
+
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES
+('4', 'CVE_EXCEPTION_EXPIRY', '');
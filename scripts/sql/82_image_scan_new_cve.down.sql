@@ -0,0 +1,6 @@
+DELETE FROM "public"."event" WHERE "id" = 5;
+
+DROP TABLE "public"."image_scan_new_cve" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_image_scan_new_cve;
@@ -0,0 +1,22 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_scan_new_cve;
+
+-- Table Definition
+CREATE TABLE "public"."image_scan_new_cve"
+(
+    "id"                                       integer      NOT NULL DEFAULT nextval('id_seq_image_scan_new_cve'::regclass),
+    "image_scan_deploy_info_id"                integer      NOT NULL,
+    "cve_store_name"                           varchar(255) NOT NULL,
+    "image"                                    text         NOT NULL,
+    "previous_image_scan_execution_history_id" integer,
+    "image_scan_execution_history_id"          integer      NOT NULL,
+    "detected_on"                              timestamptz  NOT NULL,
+    CONSTRAINT "image_scan_new_cve_image_scan_deploy_info_id_fkey" FOREIGN KEY ("image_scan_deploy_info_id") REFERENCES "public"."image_scan_deploy_info" ("id"),
+    CONSTRAINT "image_scan_new_cve_cve_store_name_fkey" FOREIGN KEY ("cve_store_name") REFERENCES "public"."cve_store" ("name"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "image_scan_new_cve_cve_store_name_idx" ON "public"."image_scan_new_cve" ("cve_store_name");
+
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES
+('5', 'BLOCKED_CVE_DETECTED', '');
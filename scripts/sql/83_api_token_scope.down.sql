@@ -0,0 +1,8 @@
+ALTER TABLE "public"."api_token"
+    DROP COLUMN IF EXISTS "allowed_route_groups",
+    DROP COLUMN IF EXISTS "allowed_app_ids",
+    DROP COLUMN IF EXISTS "allowed_env_ids",
+    DROP COLUMN IF EXISTS "source_cidrs",
+    DROP COLUMN IF EXISTS "read_only",
+    DROP COLUMN IF EXISTS "last_used_at",
+    DROP COLUMN IF EXISTS "last_used_by_ip";
@@ -0,0 +1,8 @@
+ALTER TABLE "public"."api_token"
+    ADD COLUMN IF NOT EXISTS "allowed_route_groups" text[],
+    ADD COLUMN IF NOT EXISTS "allowed_app_ids"      integer[],
+    ADD COLUMN IF NOT EXISTS "allowed_env_ids"      integer[],
+    ADD COLUMN IF NOT EXISTS "source_cidrs"         text[],
+    ADD COLUMN IF NOT EXISTS "read_only"            bool NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "last_used_at"         timestamptz,
+    ADD COLUMN IF NOT EXISTS "last_used_by_ip"      varchar(250);
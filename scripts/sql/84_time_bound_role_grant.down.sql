@@ -0,0 +1,18 @@
+DELETE FROM "public"."event" WHERE "id" = 6;
+
+DELETE FROM "public"."user_audit" WHERE "action" != 'LOGIN';
+ALTER TABLE "public"."user_audit" ALTER COLUMN "client_ip" SET NOT NULL;
+ALTER TABLE "public"."user_audit" ALTER COLUMN "user_id" SET NOT NULL;
+ALTER TABLE "public"."user_audit" DROP COLUMN IF EXISTS "created_by";
+ALTER TABLE "public"."user_audit" DROP COLUMN IF EXISTS "expires_on";
+ALTER TABLE "public"."user_audit" DROP COLUMN IF EXISTS "role_group_id";
+ALTER TABLE "public"."user_audit" DROP COLUMN IF EXISTS "role_id";
+ALTER TABLE "public"."user_audit" DROP COLUMN IF EXISTS "action";
+
+DROP INDEX IF EXISTS public.role_group_role_mapping_expires_on_IX;
+DROP INDEX IF EXISTS public.user_roles_expires_on_IX;
+
+ALTER TABLE "public"."role_group_role_mapping" DROP COLUMN IF EXISTS "expiry_notified";
+ALTER TABLE "public"."role_group_role_mapping" DROP COLUMN IF EXISTS "expires_on";
+ALTER TABLE "public"."user_roles" DROP COLUMN IF EXISTS "expiry_notified";
+ALTER TABLE "public"."user_roles" DROP COLUMN IF EXISTS "expires_on";
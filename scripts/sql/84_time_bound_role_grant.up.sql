@@ -0,0 +1,19 @@
+ALTER TABLE "public"."user_roles" ADD COLUMN IF NOT EXISTS "expires_on" timestamptz;
+ALTER TABLE "public"."user_roles" ADD COLUMN IF NOT EXISTS "expiry_notified" bool NOT NULL DEFAULT false;
+ALTER TABLE "public"."role_group_role_mapping" ADD COLUMN IF NOT EXISTS "expires_on" timestamptz;
+ALTER TABLE "public"."role_group_role_mapping" ADD COLUMN IF NOT EXISTS "expiry_notified" bool NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS user_roles_expires_on_IX ON public.user_roles (expires_on) WHERE expires_on IS NOT NULL;
+CREATE INDEX IF NOT EXISTS role_group_role_mapping_expires_on_IX ON public.role_group_role_mapping (expires_on) WHERE expires_on IS NOT NULL;
+
+--- user_audit records grant and revoke of time-bound roles along with logins, role group grants have no user
+ALTER TABLE "public"."user_audit" ADD COLUMN IF NOT EXISTS "action" varchar(50) NOT NULL DEFAULT 'LOGIN';
+ALTER TABLE "public"."user_audit" ADD COLUMN IF NOT EXISTS "role_id" int4;
+ALTER TABLE "public"."user_audit" ADD COLUMN IF NOT EXISTS "role_group_id" int4;
+ALTER TABLE "public"."user_audit" ADD COLUMN IF NOT EXISTS "expires_on" timestamptz;
+ALTER TABLE "public"."user_audit" ADD COLUMN IF NOT EXISTS "created_by" int4;
+ALTER TABLE "public"."user_audit" ALTER COLUMN "user_id" DROP NOT NULL;
+ALTER TABLE "public"."user_audit" ALTER COLUMN "client_ip" DROP NOT NULL;
+
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES
+('6', 'ROLE_GRANT_EXPIRY', '');
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "public"."audit_event" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_audit_event;
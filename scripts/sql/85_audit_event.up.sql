@@ -0,0 +1,30 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_audit_event;
+
+-- Table Definition, audit events are not linked to the audited resources so that they outlive them
+CREATE TABLE "public"."audit_event"
+(
+    "id"             integer      NOT NULL DEFAULT nextval('id_seq_audit_event'::regclass),
+    "actor_id"       int4,
+    "actor_email"    varchar(250),
+    "api_token_name" varchar(250),
+    "resource_type"  varchar(100) NOT NULL,
+    "resource_id"    int4,
+    "resource_name"  varchar(250),
+    "app_id"         int4,
+    "env_id"         int4,
+    "action"         varchar(50)  NOT NULL,
+    "before"         text,
+    "after"          text,
+    "diff"           text,
+    "request_id"     varchar(100),
+    "client_ip"      varchar(100),
+    "method"         varchar(20),
+    "path"           text,
+    "created_on"     timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS audit_event_created_on_IX ON public.audit_event (created_on);
+CREATE INDEX IF NOT EXISTS audit_event_resource_IX ON public.audit_event (resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS audit_event_actor_id_IX ON public.audit_event (actor_id);
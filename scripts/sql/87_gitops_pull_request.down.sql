@@ -0,0 +1,3 @@
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "gitops_pull_request_url";
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "gitops_pull_request_id";
+ALTER TABLE "public"."environment" DROP COLUMN IF EXISTS "gitops_pull_request";
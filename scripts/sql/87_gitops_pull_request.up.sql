@@ -0,0 +1,3 @@
+ALTER TABLE "public"."environment" ADD COLUMN IF NOT EXISTS "gitops_pull_request" bool NOT NULL DEFAULT false;
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "gitops_pull_request_id" int4;
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "gitops_pull_request_url" text;
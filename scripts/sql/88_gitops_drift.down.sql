@@ -0,0 +1,6 @@
+DELETE FROM "public"."event" WHERE "id" = 7;
+
+DROP TABLE IF EXISTS "public"."gitops_drift" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_gitops_drift;
@@ -0,0 +1,33 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_gitops_drift;
+
+-- Table Definition, latest drift check of cd pipelines deployed through gitops
+CREATE TABLE "public"."gitops_drift"
+(
+    "id"                             integer     NOT NULL DEFAULT nextval('id_seq_gitops_drift'::regclass),
+    "pipeline_id"                    int4        NOT NULL,
+    "app_id"                         int4        NOT NULL,
+    "env_id"                         int4        NOT NULL,
+    "deployment_template_history_id" int4,
+    "pipeline_override_id"           int4,
+    "git_drift"                      bool        NOT NULL DEFAULT false,
+    "git_drift_paths"                text,
+    "argo_sync_status"               varchar(50),
+    "live_drift"                     bool        NOT NULL DEFAULT false,
+    "drifted_resources"              text,
+    "error"                          text,
+    "drift_detected_on"              timestamptz,
+    "checked_on"                     timestamptz NOT NULL,
+    "created_on"                     timestamptz NOT NULL,
+    "created_by"                     int4        NOT NULL,
+    "updated_on"                     timestamptz NOT NULL,
+    "updated_by"                     int4        NOT NULL,
+    CONSTRAINT "gitops_drift_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS gitops_drift_pipeline_id_UX ON public.gitops_drift (pipeline_id);
+CREATE INDEX IF NOT EXISTS gitops_drift_app_id_env_id_IX ON public.gitops_drift (app_id, env_id);
+
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES
+('7', 'GITOPS_DRIFT_DETECTED', '');
@@ -0,0 +1,4 @@
+ALTER TABLE "public"."chart_repo" DROP CONSTRAINT IF EXISTS "chart_repo_docker_artifact_store_id_fkey";
+ALTER TABLE "public"."chart_repo" DROP COLUMN "oci_repositories";
+ALTER TABLE "public"."chart_repo" DROP COLUMN "docker_artifact_store_id";
+ALTER TABLE "public"."chart_repo" DROP COLUMN "is_oci_registry";
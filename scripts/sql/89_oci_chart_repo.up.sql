@@ -0,0 +1,4 @@
+ALTER TABLE "public"."chart_repo" ADD COLUMN "is_oci_registry" bool NOT NULL DEFAULT false;
+ALTER TABLE "public"."chart_repo" ADD COLUMN "docker_artifact_store_id" varchar(250);
+ALTER TABLE "public"."chart_repo" ADD COLUMN "oci_repositories" text;
+ALTER TABLE "public"."chart_repo" ADD CONSTRAINT "chart_repo_docker_artifact_store_id_fkey" FOREIGN KEY ("docker_artifact_store_id") REFERENCES "public"."docker_artifact_store" ("id");
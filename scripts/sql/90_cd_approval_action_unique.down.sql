@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "cd_approval_action_request_id_user_id_unique";
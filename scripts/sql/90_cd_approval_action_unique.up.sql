@@ -0,0 +1,2 @@
+-- an approver can act only once on a request, concurrent actions of same user are rejected
+CREATE UNIQUE INDEX IF NOT EXISTS "cd_approval_action_request_id_user_id_unique" ON "public"."cd_approval_action" ("cd_approval_request_id", "user_id");
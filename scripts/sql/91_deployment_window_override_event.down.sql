@@ -0,0 +1 @@
+DELETE FROM "public"."event" WHERE "id" = 8;
@@ -0,0 +1,2 @@
+INSERT INTO "public"."event" ("id", "event_type", "description") VALUES
+('8', 'DEPLOYMENT_WINDOW_OVERRIDDEN', '');
@@ -0,0 +1 @@
+ALTER TABLE "public"."ci_pipeline_schedule" DROP COLUMN IF EXISTS "last_triggered_on";
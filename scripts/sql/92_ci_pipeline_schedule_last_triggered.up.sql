@@ -0,0 +1,2 @@
+-- occurrence of schedule last claimed for trigger, instances claim an occurrence before triggering it
+ALTER TABLE "public"."ci_pipeline_schedule" ADD COLUMN IF NOT EXISTS "last_triggered_on" timestamptz;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "public"."cd_promotion_pending_artifact" CASCADE;
+
+---- DROP sequence
+DROP SEQUENCE IF EXISTS public.id_seq_cd_promotion_pending_artifact;
@@ -0,0 +1,21 @@
+-- Sequence and defined type
+CREATE SEQUENCE IF NOT EXISTS id_seq_cd_promotion_pending_artifact;
+
+-- Table Definition, artifacts held back from auto promotion which are re-evaluated till conditions are met
+CREATE TABLE "public"."cd_promotion_pending_artifact"
+(
+    "id"             integer NOT NULL DEFAULT nextval('id_seq_cd_promotion_pending_artifact'::regclass),
+    "pipeline_id"    integer NOT NULL,
+    "ci_artifact_id" integer NOT NULL,
+    "triggered_by"   int4    NOT NULL,
+    "active"         bool    NOT NULL,
+    "created_on"     timestamptz,
+    "created_by"     int4,
+    "updated_on"     timestamptz,
+    "updated_by"     int4,
+    CONSTRAINT "cd_promotion_pending_artifact_pipeline_id_fkey" FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT "cd_promotion_pending_artifact_ci_artifact_id_fkey" FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS cd_promotion_pending_artifact_active_IX ON public.cd_promotion_pending_artifact (active);
@@ -0,0 +1 @@
+ALTER TABLE "public"."notification_message_template" ADD COLUMN IF NOT EXISTS "default_template" text;
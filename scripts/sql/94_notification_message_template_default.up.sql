@@ -0,0 +1,14 @@
+-- custom templates of slack, ses and smtp are rendered by orchestrator and sent with the event, default templates of
+-- notifier replaced by them are restored
+UPDATE "public"."notification_templates" nt
+SET template_payload = nmt.default_template
+FROM "public"."notification_message_template" nmt
+WHERE nmt.active = TRUE
+  AND nmt.default_template IS NOT NULL
+  AND nt.id = (SELECT max(id)
+               FROM "public"."notification_templates"
+               WHERE channel_type = nmt.channel_type
+                 AND node_type = nmt.pipeline_type
+                 AND event_type_id = nmt.event_type_id);
+
+ALTER TABLE "public"."notification_message_template" DROP COLUMN IF EXISTS "default_template";
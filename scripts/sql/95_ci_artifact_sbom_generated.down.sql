@@ -0,0 +1 @@
+ALTER TABLE "public"."ci_artifact_sbom" DROP COLUMN IF EXISTS "generated";
@@ -0,0 +1,2 @@
+-- documents generated by image scanner, they can not be overwritten by uploads
+ALTER TABLE "public"."ci_artifact_sbom" ADD COLUMN IF NOT EXISTS "generated" bool NOT NULL DEFAULT FALSE;
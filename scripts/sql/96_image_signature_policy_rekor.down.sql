@@ -0,0 +1 @@
+ALTER TABLE "public"."image_signature_policy" DROP COLUMN IF EXISTS "rekor_public_keys";
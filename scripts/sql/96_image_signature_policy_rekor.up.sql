@@ -0,0 +1,2 @@
+-- public keys of private rekor instances, entries of keyless signatures are verified against them or public rekor
+ALTER TABLE "public"."image_signature_policy" ADD COLUMN IF NOT EXISTS "rekor_public_keys" text;
@@ -0,0 +1,2 @@
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "approved_on";
+ALTER TABLE "public"."cve_policy_control" DROP COLUMN IF EXISTS "approval_status";
@@ -0,0 +1,5 @@
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "approval_status" varchar(50);
+ALTER TABLE "public"."cve_policy_control" ADD COLUMN IF NOT EXISTS "approved_on" timestamptz;
+
+-- exceptions created before review was required are active only once reviewed by another super admin
+UPDATE "public"."cve_policy_control" SET "approval_status" = 'PENDING' WHERE "expires_on" IS NOT NULL AND "approval_status" IS NULL AND "deleted" = false;
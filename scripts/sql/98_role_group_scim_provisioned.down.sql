@@ -0,0 +1 @@
+ALTER TABLE "public"."role_group" DROP COLUMN IF EXISTS "scim_provisioned";
@@ -0,0 +1,4 @@
+-- groups created by the identity provider through scim, only these groups are managed through scim
+ALTER TABLE "public"."role_group" ADD COLUMN IF NOT EXISTS "scim_provisioned" bool NOT NULL DEFAULT FALSE;
+
+UPDATE "public"."role_group" SET "scim_provisioned" = TRUE WHERE "description" = 'Provisioned by SCIM';
@@ -4,6 +4,11 @@ import "github.com/caarlos0/env"
 
 type GlobalEnvVariables struct {
 	GitOpsRepoPrefix string `env:"GITOPS_REPO_PREFIX" envDefault:""`
+	// GitOpsRepoLayout is the layout of gitops repos of devtron apps, APP for a repo per app, TEAM for a repo per team
+	// named team-<team name> and GLOBAL for one repo of all apps. Apps sharing a repo get their charts in a directory
+	// of app name
+	GitOpsRepoLayout     string `env:"GITOPS_REPO_LAYOUT" envDefault:"APP"`
+	GitOpsSharedRepoName string `env:"GITOPS_SHARED_REPO_NAME" envDefault:"devtron-gitops"`
 }
 
 func GetGlobalEnvVariables() (*GlobalEnvVariables, error) {
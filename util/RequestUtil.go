@@ -18,11 +18,59 @@
 package util
 
 import (
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/caarlos0/env"
 )
 
 const xForwardedForHeaderName = "X-Forwarded-For"
 
+type TrustedProxyConfig struct {
+	// TrustedProxyCidrs are the load balancers and ingress controllers in front of devtron, GetTrustedClientIP honours
+	// X-Forwarded-For only for requests coming through them as any client can set the header
+	TrustedProxyCidrs []string `env:"TRUSTED_PROXY_CIDRS" envDefault:""`
+}
+
+var (
+	trustedProxies     []*net.IPNet
+	trustedProxiesOnce sync.Once
+)
+
+func getTrustedProxies() []*net.IPNet {
+	trustedProxiesOnce.Do(func() {
+		cfg := &TrustedProxyConfig{}
+		err := env.Parse(cfg)
+		if err != nil {
+			return
+		}
+		trustedProxies = ParseCidrs(cfg.TrustedProxyCidrs)
+	})
+	return trustedProxies
+}
+
+// ParseCidrs parses cidrs and plain ips, invalid entries are ignored
+func ParseCidrs(cidrs []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr = cidr + "/32"
+			} else {
+				cidr = cidr + "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
 // GetClientIP gets a requests IP address by reading off the forwarded-for
 // header (for proxies) and falls back to use the remote address.
 func GetClientIP(r *http.Request) string {
@@ -32,3 +80,50 @@ func GetClientIP(r *http.Request) string {
 	}
 	return r.RemoteAddr
 }
+
+// GetTrustedClientIP gets the IP address of the client of a request for access checks, unlike GetClientIP it can not
+// be spoofed. The remote address is used unless it is a trusted proxy of TRUSTED_PROXY_CIDRS, then the right-most
+// address of the forwarded-for header not added by a trusted proxy is the client.
+func GetTrustedClientIP(r *http.Request) string {
+	return getClientIP(r, getTrustedProxies())
+}
+
+func getClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	remoteIp := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remoteIp = host
+	}
+	if !isTrustedProxy(remoteIp, trustedProxies) {
+		return remoteIp
+	}
+	var hops []string
+	for _, header := range r.Header.Values(xForwardedForHeaderName) {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	clientIp := remoteIp
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			// a malformed hop is not from a trusted proxy, the last valid hop is the client
+			break
+		}
+		clientIp = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return clientIp
+}
+
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsedIp := net.ParseIP(ip)
+	if parsedIp == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsedIp) {
+			return true
+		}
+	}
+	return false
+}
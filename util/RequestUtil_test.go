@@ -0,0 +1,47 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClientIP(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.1.2.3:443", Header: http.Header{}}
+	assert.Equal(t, "10.1.2.3:443", GetClientIP(r))
+	// forwarded-for header is honoured from any client for audits
+	r.Header.Set(xForwardedForHeaderName, "198.51.100.2")
+	assert.Equal(t, "198.51.100.2", GetClientIP(r))
+}
+
+func TestGetTrustedClientIP(t *testing.T) {
+	trustedProxies := ParseCidrs([]string{"10.0.0.0/8", "192.168.1.1"})
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		xForwardedFor []string
+		want          string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.7:52314", want: "203.0.113.7"},
+		{name: "spoofed header from untrusted client", remoteAddr: "203.0.113.7:52314", xForwardedFor: []string{"1.2.3.4"}, want: "203.0.113.7"},
+		{name: "client behind trusted proxy", remoteAddr: "10.1.2.3:443", xForwardedFor: []string{"198.51.100.2"}, want: "198.51.100.2"},
+		{name: "spoofed hop before client", remoteAddr: "10.1.2.3:443", xForwardedFor: []string{"1.2.3.4, 198.51.100.2"}, want: "198.51.100.2"},
+		{name: "chain of trusted proxies", remoteAddr: "10.1.2.3:443", xForwardedFor: []string{"198.51.100.2, 192.168.1.1", "10.4.5.6"}, want: "198.51.100.2"},
+		{name: "malformed hop", remoteAddr: "10.1.2.3:443", xForwardedFor: []string{"unknown, 10.4.5.6"}, want: "10.4.5.6"},
+		{name: "trusted proxy without header", remoteAddr: "10.1.2.3:443", want: "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{RemoteAddr: tt.remoteAddr, Header: http.Header{}}
+			for _, header := range tt.xForwardedFor {
+				r.Header.Add(xForwardedForHeaderName, header)
+			}
+			assert.Equal(t, tt.want, getClientIP(r, trustedProxies))
+		})
+	}
+
+	// no proxy is trusted unless configured
+	r := &http.Request{RemoteAddr: "10.1.2.3:443", Header: http.Header{xForwardedForHeaderName: []string{"198.51.100.2"}}}
+	assert.Equal(t, "10.1.2.3", getClientIP(r, nil))
+}
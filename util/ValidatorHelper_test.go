@@ -74,7 +74,7 @@ func TestCompareLimitsRequests(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := CompareLimitsRequests(tt.args.dat)
+			got, err := CompareLimitsRequests(tt.args.dat, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CompareLimitsRequests() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -22,6 +22,11 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const CveExceptionExpiry EventType = 4
+const BlockedCveDetected EventType = 5
+const RoleGrantExpiry EventType = 6
+const GitOpsDriftDetected EventType = 7
+const DeploymentWindowOverridden EventType = 8
 
 type PipelineType string
 
@@ -33,9 +38,12 @@ type Level string
 type Channel string
 
 const (
-	Slack Channel = "slack"
-	SES   Channel = "ses"
-	SMTP  Channel = "smtp"
+	Slack     Channel = "slack"
+	SES       Channel = "ses"
+	SMTP      Channel = "smtp"
+	Teams     Channel = "teams"
+	Webhook   Channel = "webhook"
+	PagerDuty Channel = "pagerduty"
 )
 
 type UpdateType string